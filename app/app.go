@@ -9,6 +9,9 @@ import (
 	"runtime"
 	"runtime/pprof"
 	"strings"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
 
 	"github.com/rudderlabs/rudder-server/config"
 	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
@@ -139,6 +142,25 @@ func New(options *Options) Interface {
 	}
 }
 
+var (
+	unhealthyComponentsMu sync.RWMutex
+	unhealthyComponents   = make(map[string]string) // component -> reason
+)
+
+// SetUnhealthy reports the component as unhealthy on the health endpoint, until SetHealthy is called for it
+func SetUnhealthy(component, reason string) {
+	unhealthyComponentsMu.Lock()
+	defer unhealthyComponentsMu.Unlock()
+	unhealthyComponents[component] = reason
+}
+
+// SetHealthy stops reporting the component as unhealthy
+func SetHealthy(component string) {
+	unhealthyComponentsMu.Lock()
+	defer unhealthyComponentsMu.Unlock()
+	delete(unhealthyComponents, component)
+}
+
 // LivenessHandler is the http handler for the Kubernetes liveness probe
 func LivenessHandler(jobsDB jobsdb.JobsDB) http.HandlerFunc {
 	return func(w http.ResponseWriter, _ *http.Request) {
@@ -160,11 +182,21 @@ func getHealthVal(jobsDB jobsdb.JobsDB) string {
 		backendConfigMode = "JSON"
 	}
 
+	// the server stays up while components are unhealthy, so that the liveness probe doesn't restart it
+	server, unhealthy := "UP", ""
+	unhealthyComponentsMu.RLock()
+	if len(unhealthyComponents) > 0 {
+		server = "DEGRADED"
+		components, _ := jsoniter.Marshal(unhealthyComponents)
+		unhealthy = fmt.Sprintf(`,"unhealthy":%s`, components)
+	}
+	unhealthyComponentsMu.RUnlock()
+
 	appTypeStr := strings.ToUpper(config.GetEnv("APP_TYPE", EMBEDDED))
 	return fmt.Sprintf(
-		`{"appType":"%s","server":"UP","db":"%s","acceptingEvents":"TRUE","routingEvents":"%s","mode":"%s",`+
-			`"backendConfigMode":"%s","lastSync":"%s","lastRegulationSync":"%s"}`,
-		appTypeStr, dbService, enabledRouter, strings.ToUpper(db.CurrentMode),
-		backendConfigMode, backendconfig.LastSync, backendconfig.LastRegulationSync,
+		`{"appType":"%s","server":"%s","db":"%s","acceptingEvents":"TRUE","routingEvents":"%s","mode":"%s",`+
+			`"backendConfigMode":"%s","lastSync":"%s","lastRegulationSync":"%s"%s}`,
+		appTypeStr, server, dbService, enabledRouter, strings.ToUpper(db.CurrentMode),
+		backendConfigMode, backendconfig.LastSync, backendconfig.LastRegulationSync, unhealthy,
 	)
 }
@@ -0,0 +1,47 @@
+package apphandlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/bugsnag/bugsnag-go/v2"
+
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/jobsdb/encryption"
+	"github.com/rudderlabs/rudder-server/jobsdb/prebackup"
+	"github.com/rudderlabs/rudder-server/services/dlq"
+	dlq_http "github.com/rudderlabs/rudder-server/services/dlq/http"
+	"github.com/rudderlabs/rudder-server/utils/httputil"
+	"github.com/rudderlabs/rudder-server/utils/types"
+)
+
+// newDLQDB returns the jobsdb of the dead-letter queue, keeping the jobs aborted by the routers
+func newDLQDB(clearDB bool, migrationMode string, payloadKeys encryption.KeyProvider, prebackupHandlers []prebackup.Handler) *jobsdb.HandleT {
+	return jobsdb.NewForReadWrite(
+		"dlq",
+		jobsdb.WithClearDB(clearDB),
+		jobsdb.WithMigrationMode(migrationMode),
+		jobsdb.WithQueryFilterKeys(jobsdb.QueryFiltersT{}),
+		jobsdb.WithPayloadEncryption(payloadKeys),
+		jobsdb.WithPreBackupHandlers(prebackupHandlers),
+	)
+}
+
+// startDLQWebHandler serves the api for browsing, editing & re-driving the events of the dead-letter queue
+func startDLQWebHandler(ctx context.Context, service dlq.Service, reporting types.ReportingI) error {
+	// re-driven events are reported
+	reporting.WaitForSetup(ctx, types.CORE_REPORTING_CLIENT)
+
+	pkgLogger.Infof("Starting dead-letter queue api in %d", dlqWebPort)
+	srv := &http.Server{
+		Addr:              ":" + strconv.Itoa(dlqWebPort),
+		Handler:           bugsnag.Handler(dlq_http.NewHandler(service, pkgLogger.Child("dlq"))),
+		ReadTimeout:       ReadTimeout,
+		ReadHeaderTimeout: ReadHeaderTimeout,
+		WriteTimeout:      WriteTimeout,
+		IdleTimeout:       IdleTimeout,
+		MaxHeaderBytes:    MaxHeaderBytes,
+	}
+	return httputil.ListenAndServe(ctx, srv)
+}
@@ -25,6 +25,7 @@ import (
 	destinationdebugger "github.com/rudderlabs/rudder-server/services/debugger/destination"
 	sourcedebugger "github.com/rudderlabs/rudder-server/services/debugger/source"
 	transformationdebugger "github.com/rudderlabs/rudder-server/services/debugger/transformation"
+	"github.com/rudderlabs/rudder-server/services/dlq"
 	"github.com/rudderlabs/rudder-server/services/multitenant"
 	"github.com/rudderlabs/rudder-server/services/transientsource"
 	"github.com/rudderlabs/rudder-server/utils/misc"
@@ -77,11 +78,19 @@ func (embedded *EmbeddedApp) StartRudderCore(ctx context.Context, options *app.O
 	prebackupHandlers := []prebackup.Handler{
 		prebackup.DropSourceIds(transientSources.SourceIdsSupplier()),
 	}
+	if useEmbeddedJobsDB() {
+		return embedded.startWithEmbeddedJobsDB(ctx, g, options, deploymentType, reportingI, transientSources)
+	}
 	rsourcesService, err := NewRsourcesService(deploymentType)
 	if err != nil {
 		return err
 	}
 
+	payloadKeys, err := payloadEncryptionKeys()
+	if err != nil {
+		return err
+	}
+
 	// IMP NOTE: All the jobsdb setups must happen before migrator setup.
 	// This gwDBForProcessor should only be used by processor as this is supposed to be stopped and started with the
 	// Processor.
@@ -91,7 +100,9 @@ func (embedded *EmbeddedApp) StartRudderCore(ctx context.Context, options *app.O
 		jobsdb.WithMigrationMode(migrationMode),
 		jobsdb.WithStatusHandler(),
 		jobsdb.WithQueryFilterKeys(jobsdb.QueryFiltersT{}),
+		jobsdb.WithPayloadEncryption(payloadKeys),
 		jobsdb.WithPreBackupHandlers(prebackupHandlers),
+		jobsdb.WithExpiredJobsHandler(expiredJobsReporter(reportingI, "", types.GATEWAY)),
 	)
 	defer gwDBForProcessor.Close()
 	routerDB := jobsdb.NewForReadWrite(
@@ -100,7 +111,9 @@ func (embedded *EmbeddedApp) StartRudderCore(ctx context.Context, options *app.O
 		jobsdb.WithMigrationMode(migrationMode),
 		jobsdb.WithStatusHandler(),
 		jobsdb.WithQueryFilterKeys(router.QueryFilters),
+		jobsdb.WithPayloadEncryption(payloadKeys),
 		jobsdb.WithPreBackupHandlers(prebackupHandlers),
+		jobsdb.WithExpiredJobsHandler(expiredJobsReporter(reportingI, types.DEST_TRANSFORMER, types.ROUTER)),
 	)
 	defer routerDB.Close()
 	batchRouterDB := jobsdb.NewForReadWrite(
@@ -109,7 +122,9 @@ func (embedded *EmbeddedApp) StartRudderCore(ctx context.Context, options *app.O
 		jobsdb.WithMigrationMode(migrationMode),
 		jobsdb.WithStatusHandler(),
 		jobsdb.WithQueryFilterKeys(batchrouter.QueryFilters),
+		jobsdb.WithPayloadEncryption(payloadKeys),
 		jobsdb.WithPreBackupHandlers(prebackupHandlers),
+		jobsdb.WithExpiredJobsHandler(expiredJobsReporter(reportingI, types.DEST_TRANSFORMER, types.BATCH_ROUTER)),
 	)
 	defer batchRouterDB.Close()
 	errDB := jobsdb.NewForReadWrite(
@@ -118,9 +133,23 @@ func (embedded *EmbeddedApp) StartRudderCore(ctx context.Context, options *app.O
 		jobsdb.WithMigrationMode(migrationMode),
 		jobsdb.WithStatusHandler(),
 		jobsdb.WithQueryFilterKeys(jobsdb.QueryFiltersT{}),
+		jobsdb.WithPayloadEncryption(payloadKeys),
 		jobsdb.WithPreBackupHandlers(prebackupHandlers),
 	)
 
+	var dlqDB jobsdb.JobsDB // stays nil if the dead-letter queue is disabled
+	if dlq.IsEnabled() {
+		dlqHandle := newDLQDB(options.ClearDB, migrationMode, payloadKeys, prebackupHandlers)
+		defer dlqHandle.Close()
+		if err := dlqHandle.Start(); err != nil {
+			return fmt.Errorf("could not start dlqDB: %w", err)
+		}
+		defer dlqHandle.Stop()
+		dlqDB = dlqHandle
+		g.Go(func() error {
+			return startDLQWebHandler(ctx, dlq.NewService(dlqHandle, routerDB, batchRouterDB, reportingI), reportingI)
+		})
+	}
 	var tenantRouterDB jobsdb.MultiTenantJobsDB
 	var multitenantStats multitenant.MultiTenantI
 	if misc.UseFairPickup() {
@@ -173,22 +202,9 @@ func (embedded *EmbeddedApp) StartRudderCore(ctx context.Context, options *app.O
 		}
 	}
 
-	var modeProvider cluster.ChangeEventProvider
-
-	switch deploymentType {
-	case deployment.MultiTenantType:
-		pkgLogger.Info("using ETCD Based Dynamic Cluster Manager")
-		modeProvider = state.NewETCDDynamicProvider()
-	case deployment.DedicatedType:
-		// FIXME: hacky way to determine server mode
-		pkgLogger.Info("using Static Cluster Manager")
-		if enableProcessor && enableRouter {
-			modeProvider = state.NewStaticProvider(servermode.NormalMode)
-		} else {
-			modeProvider = state.NewStaticProvider(servermode.DegradedMode)
-		}
-	default:
-		return fmt.Errorf("unsupported deployment type: %q", deploymentType)
+	modeProvider, err := newModeProvider(deploymentType)
+	if err != nil {
+		return err
 	}
 
 	proc := processor.New(ctx, &options.ClearDB, gwDBForProcessor, routerDB, batchRouterDB, errDB, multitenantStats, reportingI, transientSources, rsourcesService)
@@ -198,6 +214,7 @@ func (embedded *EmbeddedApp) StartRudderCore(ctx context.Context, options *app.O
 		BackendConfig:    backendconfig.DefaultBackendConfig,
 		RouterDB:         tenantRouterDB,
 		ProcErrorDB:      errDB,
+		DLQDB:            dlqDB,
 		TransientSources: transientSources,
 		RsourcesService:  rsourcesService,
 	}
@@ -207,6 +224,7 @@ func (embedded *EmbeddedApp) StartRudderCore(ctx context.Context, options *app.O
 		BackendConfig:    backendconfig.DefaultBackendConfig,
 		RouterDB:         batchRouterDB,
 		ProcErrorDB:      errDB,
+		DLQDB:            dlqDB,
 		TransientSources: transientSources,
 		RsourcesService:  rsourcesService,
 	}
@@ -236,6 +254,7 @@ func (embedded *EmbeddedApp) StartRudderCore(ctx context.Context, options *app.O
 			jobsdb.WithMigrationMode(migrationMode),
 			jobsdb.WithStatusHandler(),
 			jobsdb.WithQueryFilterKeys(jobsdb.QueryFiltersT{}),
+			jobsdb.WithPayloadEncryption(payloadKeys),
 		)
 		defer gwDBForProcessor.Close()
 		if err = gatewayDB.Start(); err != nil {
@@ -244,6 +263,20 @@ func (embedded *EmbeddedApp) StartRudderCore(ctx context.Context, options *app.O
 		defer gatewayDB.Stop()
 
 		gw.SetReadonlyDBs(&readonlyGatewayDB, &readonlyRouterDB, &readonlyBatchRouterDB)
+		if gateway.IsSchemaEnforcementEnabled() {
+			quarantineDB := jobsdb.NewForWrite(
+				"gw_quarantine",
+				jobsdb.WithClearDB(options.ClearDB),
+				jobsdb.WithQueryFilterKeys(jobsdb.QueryFiltersT{}),
+				jobsdb.WithPayloadEncryption(payloadKeys),
+			)
+			defer quarantineDB.Close()
+			if err := quarantineDB.Start(); err != nil {
+				return fmt.Errorf("could not start quarantineDB: %w", err)
+			}
+			defer quarantineDB.Stop()
+			gw.SetQuarantineDB(quarantineDB)
+		}
 		err = gw.Setup(
 			embedded.App, backendconfig.DefaultBackendConfig, gatewayDB,
 			&rateLimiter, embedded.VersionHandler, rsourcesService,
@@ -263,6 +296,12 @@ func (embedded *EmbeddedApp) StartRudderCore(ctx context.Context, options *app.O
 		g.Go(func() error {
 			return gw.StartWebHandler(ctx)
 		})
+		g.Go(func() error {
+			return gw.StartGRPCHandler(ctx)
+		})
+		g.Go(func() error {
+			return gw.StartKafkaConsumers(ctx)
+		})
 	}
 	if enableReplay {
 		var replayDB jobsdb.HandleT
@@ -291,6 +330,24 @@ func (embedded *EmbeddedApp) StartRudderCore(ctx context.Context, options *app.O
 	return g.Wait()
 }
 
+// newModeProvider returns the provider of the server mode of a deployment type
+func newModeProvider(deploymentType deployment.Type) (cluster.ChangeEventProvider, error) {
+	switch deploymentType {
+	case deployment.MultiTenantType:
+		pkgLogger.Info("using ETCD Based Dynamic Cluster Manager")
+		return state.NewETCDDynamicProvider(), nil
+	case deployment.DedicatedType:
+		// FIXME: hacky way to determine server mode
+		pkgLogger.Info("using Static Cluster Manager")
+		if enableProcessor && enableRouter {
+			return state.NewStaticProvider(servermode.NormalMode), nil
+		}
+		return state.NewStaticProvider(servermode.DegradedMode), nil
+	default:
+		return nil, fmt.Errorf("unsupported deployment type: %q", deploymentType)
+	}
+}
+
 func (*EmbeddedApp) HandleRecovery(options *app.Options) {
 	db.HandleEmbeddedRecovery(options.NormalMode, options.DegradedMode, options.MigrationMode, misc.AppStartTime, app.EMBEDDED)
 }
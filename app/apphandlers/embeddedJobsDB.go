@@ -0,0 +1,200 @@
+package apphandlers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/rudderlabs/rudder-server/app"
+	"github.com/rudderlabs/rudder-server/app/cluster"
+	"github.com/rudderlabs/rudder-server/config"
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/gateway"
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/processor"
+	ratelimiter "github.com/rudderlabs/rudder-server/rate-limiter"
+	"github.com/rudderlabs/rudder-server/router"
+	"github.com/rudderlabs/rudder-server/router/batchrouter"
+	routerManager "github.com/rudderlabs/rudder-server/router/manager"
+	"github.com/rudderlabs/rudder-server/services/dlq"
+	"github.com/rudderlabs/rudder-server/services/multitenant"
+	"github.com/rudderlabs/rudder-server/services/rsources"
+	"github.com/rudderlabs/rudder-server/services/transientsource"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/utils/types"
+	"github.com/rudderlabs/rudder-server/utils/types/deployment"
+)
+
+// embeddedJobsDBBackend is the value of JobsDB.backend storing the jobs in the embedded jobsdb instead of postgres
+const embeddedJobsDBBackend = "embedded"
+
+func useEmbeddedJobsDB() bool {
+	return config.GetString("JobsDB.backend", "postgres") == embeddedJobsDBBackend
+}
+
+/*
+validateEmbeddedJobsDB returns an error if the embedded jobsdb is used by an app type other than the embedded one, or along
+with a feature it doesn't support. Reporting and failed keys are written with the SQL transactions of the jobsdb, which the
+embedded jobsdb doesn't have, whereas migrations, replay, the dead-letter queue and payload encryption are only implemented
+for the postgres jobsdb.
+*/
+func validateEmbeddedJobsDB(appType, migrationMode string) error {
+	if appType != app.EMBEDDED {
+		return fmt.Errorf("the %s jobsdb backend can only be used by the %s app type", embeddedJobsDBBackend, app.EMBEDDED)
+	}
+	unsupported := []struct {
+		setting string
+		enabled bool
+	}{
+		{"Reporting.enabled", config.GetBool("Reporting.enabled", types.DEFAULT_REPORTING_ENABLED)},
+		{"Router.failedKeysEnabled", config.GetBool("Router.failedKeysEnabled", false)},
+		{"Replay.enabled", enableReplay},
+		{"DLQ.enabled", dlq.IsEnabled()},
+		{"JobsDB.payloadEncryption.keyFile", config.GetString("JobsDB.payloadEncryption.keyFile", "") != ""},
+		{"migration mode", migrationMode != ""},
+	}
+	for _, u := range unsupported {
+		if u.enabled {
+			return fmt.Errorf("the %s jobsdb backend doesn't support %s, disable it or use the postgres backend", embeddedJobsDBBackend, u.setting)
+		}
+	}
+	return nil
+}
+
+// newEmbeddedJobsDB opens the embedded jobsdb of a table prefix, under a directory of its own in JobsDB.embedded.path
+func newEmbeddedJobsDB(tablePrefix string, clearDB bool) (*jobsdb.EmbeddedHandleT, error) {
+	jd, err := jobsdb.NewEmbedded(tablePrefix, filepath.Join(jobsdb.DefaultEmbeddedPath(), tablePrefix), jobsdb.WithEmbeddedClearDB(clearDB))
+	if err != nil {
+		return nil, fmt.Errorf("could not open embedded %s jobsdb: %w", tablePrefix, err)
+	}
+	return jd, nil
+}
+
+/*
+startWithEmbeddedJobsDB starts the processor, the routers and the gateway of the embedded app, storing their jobs in embedded
+jobsdb instances. The gateway and the processor share the same gateway jobsdb, as the store can only be opened once.
+Rudder sources job runs are not tracked, since their stats are written with the SQL transactions of the jobsdb.
+*/
+func (embedded *EmbeddedApp) startWithEmbeddedJobsDB(
+	ctx context.Context, g *errgroup.Group, options *app.Options, deploymentType deployment.Type,
+	reportingI types.ReportingI, transientSources transientsource.Service,
+) error {
+	if err := validateEmbeddedJobsDB(app.EMBEDDED, embedded.App.Options().MigrationMode); err != nil {
+		return err
+	}
+	pkgLogger.Infof("Storing jobs in the embedded jobsdb at %q", jobsdb.DefaultEmbeddedPath())
+	rsourcesService := rsources.NewNoOpService()
+
+	jobsDBs := make(map[string]*jobsdb.EmbeddedHandleT)
+	defer func() {
+		for _, jd := range jobsDBs {
+			jd.TearDown()
+		}
+	}()
+	for _, tablePrefix := range []string{"gw", "rt", "batch_rt", "proc_error"} {
+		jd, err := newEmbeddedJobsDB(tablePrefix, options.ClearDB)
+		if err != nil {
+			return err
+		}
+		jobsDBs[tablePrefix] = jd
+	}
+	gwDB, routerDB, batchRouterDB, errDB := jobsDBs["gw"], jobsDBs["rt"], jobsDBs["batch_rt"], jobsDBs["proc_error"]
+
+	stats := multitenant.NewStats(map[string]jobsdb.MultiTenantJobsDB{
+		"rt":       routerDB,
+		"batch_rt": batchRouterDB,
+	})
+	var multitenantStats multitenant.MultiTenantI = stats
+	if !misc.UseFairPickup() {
+		multitenantStats = multitenant.WithLegacyPickupJobs(stats)
+	}
+
+	modeProvider, err := newModeProvider(deploymentType)
+	if err != nil {
+		return err
+	}
+
+	proc := processor.New(ctx, &options.ClearDB, gwDB, routerDB, batchRouterDB, errDB, multitenantStats, reportingI, transientSources, rsourcesService)
+	rtFactory := &router.Factory{
+		Reporting:        reportingI,
+		Multitenant:      multitenantStats,
+		BackendConfig:    backendconfig.DefaultBackendConfig,
+		RouterDB:         routerDB,
+		ProcErrorDB:      errDB,
+		TransientSources: transientSources,
+		RsourcesService:  rsourcesService,
+	}
+	brtFactory := &batchrouter.Factory{
+		Reporting:        reportingI,
+		Multitenant:      multitenantStats,
+		BackendConfig:    backendconfig.DefaultBackendConfig,
+		RouterDB:         batchRouterDB,
+		ProcErrorDB:      errDB,
+		TransientSources: transientSources,
+		RsourcesService:  rsourcesService,
+	}
+	rt := routerManager.New(rtFactory, brtFactory, backendconfig.DefaultBackendConfig)
+
+	dm := cluster.Dynamic{
+		Provider:        modeProvider,
+		GatewayDB:       gwDB,
+		RouterDB:        routerDB,
+		BatchRouterDB:   batchRouterDB,
+		ErrorDB:         errDB,
+		Processor:       proc,
+		Router:          rt,
+		MultiTenantStat: multitenantStats,
+	}
+
+	// the gateway keeps storing jobs in degraded mode, when the processor isn't reading them
+	if err := gwDB.Start(); err != nil {
+		return fmt.Errorf("could not start gateway: %w", err)
+	}
+	rateLimiter := ratelimiter.HandleT{}
+	rateLimiter.SetUp()
+	gw := gateway.HandleT{}
+	gw.SetReadonlyDBs(gwDB, routerDB, batchRouterDB)
+	if gateway.IsSchemaEnforcementEnabled() {
+		quarantineDB, err := newEmbeddedJobsDB("gw_quarantine", options.ClearDB)
+		if err != nil {
+			return err
+		}
+		jobsDBs["gw_quarantine"] = quarantineDB
+		if err := quarantineDB.Start(); err != nil {
+			return fmt.Errorf("could not start quarantineDB: %w", err)
+		}
+		gw.SetQuarantineDB(quarantineDB)
+	}
+	err = gw.Setup(
+		embedded.App, backendconfig.DefaultBackendConfig, gwDB,
+		&rateLimiter, embedded.VersionHandler, rsourcesService,
+	)
+	if err != nil {
+		return fmt.Errorf("could not setup gateway: %w", err)
+	}
+	defer func() {
+		if err := gw.Shutdown(); err != nil {
+			pkgLogger.Warnf("Gateway shutdown error: %v", err)
+		}
+	}()
+
+	g.Go(func() error {
+		return gw.StartAdminHandler(ctx)
+	})
+	g.Go(func() error {
+		return gw.StartWebHandler(ctx)
+	})
+	g.Go(func() error {
+		return gw.StartGRPCHandler(ctx)
+	})
+	g.Go(func() error {
+		return gw.StartKafkaConsumers(ctx)
+	})
+	g.Go(func() error {
+		return dm.Run(ctx)
+	})
+
+	return g.Wait()
+}
@@ -0,0 +1,64 @@
+package apphandlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/router"
+	"github.com/rudderlabs/rudder-server/utils/types"
+)
+
+// expiredJobsReporter returns a jobsdb.ExpiredJobsHandler reporting the jobs which got aborted due to their expiry
+// as aborted events of the given processing unit, in the same transaction which aborted them
+func expiredJobsReporter(reporting types.ReportingI, inPU, pu string) jobsdb.ExpiredJobsHandler {
+	return func(tx jobsdb.UpdateSafeTx, jobs []*jobsdb.JobT, statusList []*jobsdb.JobStatusT) error {
+		reporting.Report(expiredJobsMetrics(inPU, pu, jobs, statusList), tx.Tx())
+		return nil
+	}
+}
+
+func expiredJobsMetrics(inPU, pu string, jobs []*jobsdb.JobT, statusList []*jobsdb.JobStatusT) []*types.PUReportedMetric {
+	statusByJobID := make(map[int64]*jobsdb.JobStatusT, len(statusList))
+	for _, status := range statusList {
+		statusByJobID[status.JobID] = status
+	}
+	metricsByKey := make(map[string]*types.PUReportedMetric)
+	var metrics []*types.PUReportedMetric
+	for _, job := range jobs {
+		status, ok := statusByJobID[job.JobID]
+		if !ok {
+			continue
+		}
+		var parameters router.JobParametersT
+		if err := json.Unmarshal(job.Parameters, &parameters); err != nil {
+			pkgLogger.Errorf("Unmarshal of parameters of expired job %d failed: %v", job.JobID, err)
+		}
+		eventName := gjson.GetBytes(job.Parameters, "event_name").String()
+		eventType := gjson.GetBytes(job.Parameters, "event_type").String()
+		key := fmt.Sprintf("%s:%s:%s:%s:%s", parameters.SourceID, parameters.DestinationID, parameters.SourceBatchID, eventName, eventType)
+		m, ok := metricsByKey[key]
+		if !ok {
+			errorCode, err := strconv.Atoi(status.ErrorCode)
+			if err != nil {
+				errorCode = jobsdb.ExpiredJobErrorCode
+			}
+			m = &types.PUReportedMetric{
+				ConnectionDetails: *types.CreateConnectionDetail(parameters.SourceID, parameters.DestinationID, parameters.SourceBatchID, parameters.SourceTaskID, parameters.SourceTaskRunID, parameters.SourceJobID, parameters.SourceJobRunID, parameters.SourceDefinitionID, parameters.DestinationDefinitionID, parameters.SourceCategory),
+				PUDetails:         *types.CreatePUDetails(inPU, pu, true, inPU == ""),
+				StatusDetail:      types.CreateStatusDetail(jobsdb.Aborted.State, 0, errorCode, string(status.ErrorResponse), []byte(`{}`), eventName, eventType),
+			}
+			metricsByKey[key] = m
+			metrics = append(metrics, m)
+		}
+		if job.EventCount > 0 {
+			m.StatusDetail.Count += int64(job.EventCount)
+		} else {
+			m.StatusDetail.Count++
+		}
+	}
+	return metrics
+}
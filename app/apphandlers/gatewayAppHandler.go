@@ -34,6 +34,10 @@ func (*GatewayApp) GetAppType() string {
 func (gatewayApp *GatewayApp) StartRudderCore(ctx context.Context, options *app.Options) error {
 	pkgLogger.Info("Gateway starting")
 
+	if useEmbeddedJobsDB() {
+		return validateEmbeddedJobsDB(app.GATEWAY, gatewayApp.App.Options().MigrationMode)
+	}
+
 	rudderCoreDBValidator()
 	rudderCoreWorkSpaceTableSetup()
 	rudderCoreBaseSetup()
@@ -50,12 +54,18 @@ func (gatewayApp *GatewayApp) StartRudderCore(ctx context.Context, options *app.
 
 	migrationMode := gatewayApp.App.Options().MigrationMode
 
+	payloadKeys, err := payloadEncryptionKeys()
+	if err != nil {
+		return err
+	}
+
 	gatewayDB := jobsdb.NewForWrite(
 		"gw",
 		jobsdb.WithClearDB(options.ClearDB),
 		jobsdb.WithMigrationMode(migrationMode),
 		jobsdb.WithStatusHandler(),
 		jobsdb.WithQueryFilterKeys(jobsdb.QueryFiltersT{}),
+		jobsdb.WithPayloadEncryption(payloadKeys),
 	)
 	defer gatewayDB.Close()
 	if err := gatewayDB.Start(); err != nil {
@@ -105,6 +115,20 @@ func (gatewayApp *GatewayApp) StartRudderCore(ctx context.Context, options *app.
 
 		rateLimiter.SetUp()
 		gw.SetReadonlyDBs(&readonlyGatewayDB, &readonlyRouterDB, &readonlyBatchRouterDB)
+		if gateway.IsSchemaEnforcementEnabled() {
+			quarantineDB := jobsdb.NewForWrite(
+				"gw_quarantine",
+				jobsdb.WithClearDB(options.ClearDB),
+				jobsdb.WithQueryFilterKeys(jobsdb.QueryFiltersT{}),
+				jobsdb.WithPayloadEncryption(payloadKeys),
+			)
+			defer quarantineDB.Close()
+			if err := quarantineDB.Start(); err != nil {
+				return fmt.Errorf("could not start quarantineDB: %w", err)
+			}
+			defer quarantineDB.Stop()
+			gw.SetQuarantineDB(quarantineDB)
+		}
 		rsourcesService, err := NewRsourcesService(deploymentType)
 		if err != nil {
 			return err
@@ -128,6 +152,12 @@ func (gatewayApp *GatewayApp) StartRudderCore(ctx context.Context, options *app.
 		g.Go(func() error {
 			return gw.StartWebHandler(ctx)
 		})
+		g.Go(func() error {
+			return gw.StartGRPCHandler(ctx)
+		})
+		g.Go(func() error {
+			return gw.StartKafkaConsumers(ctx)
+		})
 	}
 	// go readIOforResume(router) //keeping it as input from IO, to be replaced by UI
 	return g.Wait()
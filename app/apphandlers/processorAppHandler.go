@@ -30,6 +30,7 @@ import (
 	"github.com/rudderlabs/rudder-server/services/db"
 	destinationdebugger "github.com/rudderlabs/rudder-server/services/debugger/destination"
 	transformationdebugger "github.com/rudderlabs/rudder-server/services/debugger/transformation"
+	"github.com/rudderlabs/rudder-server/services/dlq"
 	"github.com/rudderlabs/rudder-server/services/multitenant"
 	"github.com/rudderlabs/rudder-server/services/transientsource"
 	"github.com/rudderlabs/rudder-server/utils/misc"
@@ -72,6 +73,10 @@ func loadConfigHandler() {
 func (processor *ProcessorApp) StartRudderCore(ctx context.Context, options *app.Options) error {
 	pkgLogger.Info("Processor starting")
 
+	if useEmbeddedJobsDB() {
+		return validateEmbeddedJobsDB(app.PROCESSOR, processor.App.Options().MigrationMode)
+	}
+
 	rudderCoreDBValidator()
 	rudderCoreWorkSpaceTableSetup()
 	rudderCoreNodeSetup()
@@ -107,6 +112,11 @@ func (processor *ProcessorApp) StartRudderCore(ctx context.Context, options *app
 		return err
 	}
 
+	payloadKeys, err := payloadEncryptionKeys()
+	if err != nil {
+		return err
+	}
+
 	// IMP NOTE: All the jobsdb setups must happen before migrator setup.
 	gwDBForProcessor := jobsdb.NewForRead(
 		"gw",
@@ -114,7 +124,9 @@ func (processor *ProcessorApp) StartRudderCore(ctx context.Context, options *app
 		jobsdb.WithMigrationMode(migrationMode),
 		jobsdb.WithStatusHandler(),
 		jobsdb.WithQueryFilterKeys(jobsdb.QueryFiltersT{}),
+		jobsdb.WithPayloadEncryption(payloadKeys),
 		jobsdb.WithPreBackupHandlers(prebackupHandlers),
+		jobsdb.WithExpiredJobsHandler(expiredJobsReporter(reportingI, "", types.GATEWAY)),
 	)
 	defer gwDBForProcessor.Close()
 	gatewayDB = gwDBForProcessor
@@ -124,7 +136,9 @@ func (processor *ProcessorApp) StartRudderCore(ctx context.Context, options *app
 		jobsdb.WithMigrationMode(migrationMode),
 		jobsdb.WithStatusHandler(),
 		jobsdb.WithQueryFilterKeys(router.QueryFilters),
+		jobsdb.WithPayloadEncryption(payloadKeys),
 		jobsdb.WithPreBackupHandlers(prebackupHandlers),
+		jobsdb.WithExpiredJobsHandler(expiredJobsReporter(reportingI, types.DEST_TRANSFORMER, types.ROUTER)),
 	)
 	defer routerDB.Close()
 	batchRouterDB := jobsdb.NewForReadWrite(
@@ -133,7 +147,9 @@ func (processor *ProcessorApp) StartRudderCore(ctx context.Context, options *app
 		jobsdb.WithMigrationMode(migrationMode),
 		jobsdb.WithStatusHandler(),
 		jobsdb.WithQueryFilterKeys(batchrouter.QueryFilters),
+		jobsdb.WithPayloadEncryption(payloadKeys),
 		jobsdb.WithPreBackupHandlers(prebackupHandlers),
+		jobsdb.WithExpiredJobsHandler(expiredJobsReporter(reportingI, types.DEST_TRANSFORMER, types.BATCH_ROUTER)),
 	)
 	defer batchRouterDB.Close()
 	errDB := jobsdb.NewForReadWrite(
@@ -142,8 +158,22 @@ func (processor *ProcessorApp) StartRudderCore(ctx context.Context, options *app
 		jobsdb.WithMigrationMode(migrationMode),
 		jobsdb.WithStatusHandler(),
 		jobsdb.WithQueryFilterKeys(jobsdb.QueryFiltersT{}),
+		jobsdb.WithPayloadEncryption(payloadKeys),
 		jobsdb.WithPreBackupHandlers(prebackupHandlers),
 	)
+	var dlqDB jobsdb.JobsDB // stays nil if the dead-letter queue is disabled
+	if dlq.IsEnabled() {
+		dlqHandle := newDLQDB(options.ClearDB, migrationMode, payloadKeys, prebackupHandlers)
+		defer dlqHandle.Close()
+		if err := dlqHandle.Start(); err != nil {
+			return fmt.Errorf("could not start dlqDB: %w", err)
+		}
+		defer dlqHandle.Stop()
+		dlqDB = dlqHandle
+		g.Go(func() error {
+			return startDLQWebHandler(ctx, dlq.NewService(dlqHandle, routerDB, batchRouterDB, reportingI), reportingI)
+		})
+	}
 	var tenantRouterDB jobsdb.MultiTenantJobsDB
 	var multitenantStats multitenant.MultiTenantI
 	if misc.UseFairPickup() {
@@ -218,6 +248,7 @@ func (processor *ProcessorApp) StartRudderCore(ctx context.Context, options *app
 		BackendConfig:    backendconfig.DefaultBackendConfig,
 		RouterDB:         tenantRouterDB,
 		ProcErrorDB:      errDB,
+		DLQDB:            dlqDB,
 		TransientSources: transientSources,
 		RsourcesService:  rsourcesService,
 	}
@@ -227,6 +258,7 @@ func (processor *ProcessorApp) StartRudderCore(ctx context.Context, options *app
 		BackendConfig:    backendconfig.DefaultBackendConfig,
 		RouterDB:         batchRouterDB,
 		ProcErrorDB:      errDB,
+		DLQDB:            dlqDB,
 		TransientSources: transientSources,
 		RsourcesService:  rsourcesService,
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/rudderlabs/rudder-server/config"
 	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
 	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/jobsdb/encryption"
 	"github.com/rudderlabs/rudder-server/processor"
 	"github.com/rudderlabs/rudder-server/router"
 	"github.com/rudderlabs/rudder-server/router/batchrouter"
@@ -38,6 +39,7 @@ var (
 	Diagnostics                                                diagnostics.DiagnosticsI
 	readonlyGatewayDB, readonlyRouterDB, readonlyBatchRouterDB jobsdb.ReadonlyHandleT
 	readonlyProcErrorDB                                        jobsdb.ReadonlyHandleT
+	dlqWebPort                                                 int
 )
 
 // AppHandler to be implemented by different app type objects.
@@ -73,10 +75,25 @@ func loadConfig() {
 	config.RegisterBoolConfigVariable(true, &enableProcessor, false, "enableProcessor")
 	config.RegisterBoolConfigVariable(types.DEFAULT_REPLAY_ENABLED, &enableReplay, false, "Replay.enabled")
 	config.RegisterBoolConfigVariable(true, &enableRouter, false, "enableRouter")
+	config.RegisterIntConfigVariable(8087, &dlqWebPort, false, 1, "DLQ.webPort")
 	objectStorageDestinations = []string{"S3", "GCS", "AZURE_BLOB", "MINIO", "DIGITAL_OCEAN_SPACES"}
 	asyncDestinations = []string{"MARKETO_BULK_UPLOAD"}
 }
 
+// payloadEncryptionKeys returns the key provider to be used for encrypting event payloads stored in jobsdb,
+// or nil if payload encryption is not enabled, i.e. no key file is configured
+func payloadEncryptionKeys() (encryption.KeyProvider, error) {
+	keyFile := config.GetString("JobsDB.payloadEncryption.keyFile", "")
+	if keyFile == "" {
+		return nil, nil
+	}
+	keys, err := encryption.NewKeyFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading payload encryption keys: %w", err)
+	}
+	return keys, nil
+}
+
 func rudderCoreDBValidator() {
 	validators.ValidateEnv()
 }
@@ -230,7 +230,10 @@ func (handle *dumpsLoaderHandleT) Setup(ctx context.Context, db *jobsdb.HandleT,
 	handle.dbHandle = db
 	handle.handleRecovery()
 
-	lastJob := handle.dbHandle.GetLastJob()
+	lastJob, err := handle.dbHandle.GetLastJob()
+	if err != nil {
+		panic(fmt.Errorf("getting last job: %w", err))
+	}
 	handle.startAfterKey = gjson.GetBytes(lastJob.EventPayload, "location").String()
 	handle.bucket = bucket
 	handle.uploader = uploader
@@ -21,6 +21,7 @@ import (
 	"github.com/rudderlabs/rudder-server/processor/transformer"
 	"github.com/rudderlabs/rudder-server/services/filemanager"
 	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
 )
 
 type SourceWorkerT struct {
@@ -116,6 +117,11 @@ func (worker *SourceWorkerT) replayJobsInFile(ctx context.Context, filePath stri
 		lineBytes := sc.Bytes()
 		copyLineBytes := make([]byte, len(lineBytes))
 		copy(copyLineBytes, lineBytes)
+		copyLineBytes, err = worker.decryptEventPayload(copyLineBytes)
+		if err != nil {
+			pkgLogger.Errorf("failed to decrypt event payload: %s", err)
+			continue
+		}
 
 		if transformationVersionID == "" {
 			createdAt, err := time.Parse(misc.POSTGRESTIMEFORMATPARSE, gjson.GetBytes(copyLineBytes, worker.getFieldIdentifier(createdAt)).String())
@@ -163,7 +169,11 @@ func (worker *SourceWorkerT) replayJobsInFile(ctx context.Context, filePath stri
 	}
 
 	if transformationVersionID != "" {
-		response := worker.transformer.Transform(context.TODO(), transEvents, integrations.GetUserTransformURL(), userTransformBatchSize)
+		response, err := worker.transformer.Transform(ctx, transEvents, integrations.GetUserTransformURL(), userTransformBatchSize)
+		if err != nil {
+			pkgLogger.Errorf("Stopped replaying file %s while transforming its events: %v", filePath, err)
+			return
+		}
 
 		for _, ev := range response.Events {
 			destEventJSON, err := json.Marshal(ev.Output[worker.getFieldIdentifier(eventPayload)])
@@ -217,6 +227,24 @@ func (worker *SourceWorkerT) replayJobsInFile(ctx context.Context, filePath stri
 	}
 }
 
+// decryptEventPayload replaces the event payload of a backed up job with its decrypted form, if the payload was backed up encrypted
+func (worker *SourceWorkerT) decryptEventPayload(line []byte) ([]byte, error) {
+	keyID := gjson.GetBytes(line, "encryption_key_id")
+	if keyID.Type != gjson.String {
+		return line, nil
+	}
+	payloadField := worker.getFieldIdentifier(eventPayload)
+	payload, err := worker.replayHandler.toDB.DecryptPayload([]byte(gjson.GetBytes(line, payloadField).Raw), keyID.String())
+	if err != nil {
+		return nil, err
+	}
+	line, err = sjson.SetRawBytes(line, payloadField, payload)
+	if err != nil {
+		return nil, err
+	}
+	return sjson.DeleteBytes(line, "encryption_key_id")
+}
+
 const (
 	userID       = "userID"
 	parameters   = "parameters"
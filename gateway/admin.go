@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/tidwall/gjson"
+
 	"github.com/rudderlabs/rudder-server/jobsdb"
 	"github.com/rudderlabs/rudder-server/utils/misc"
 )
@@ -40,6 +42,13 @@ type GatewayRPCHandler struct {
 type SqlRunner struct {
 	dbHandle     *sql.DB
 	jobTableName string
+	// decryptPayload decrypts event payloads which were stored encrypted, nil if the jobsdb cannot decrypt payloads
+	decryptPayload func(payload json.RawMessage, keyID string) (json.RawMessage, error)
+}
+
+// payloadDecrypter is implemented by jobsdbs which can store event payloads encrypted
+type payloadDecrypter interface {
+	DecryptPayload(payload json.RawMessage, keyID string) (json.RawMessage, error)
 }
 
 type SourceEvents struct {
@@ -83,15 +92,49 @@ func (r *SqlRunner) getNumUniqueUsers() (int, error) {
 }
 
 func (r *SqlRunner) getAvgBatchSize() (float64, error) {
-	var batchSize sql.NullFloat64
-	var avgBatchSize float64
+	var batchSizes sql.NullInt64
+	var numBatches int64
 	var err error
-	avgBatchSizeStmt := fmt.Sprintf(`select avg(jsonb_array_length(batch)) from (select event_payload->'batch' as batch from %s) t`, r.jobTableName)
-	err = runSQL(r, avgBatchSizeStmt, &batchSize)
-	if batchSize.Valid {
-		avgBatchSize = batchSize.Float64
+	// encrypted payloads can only be inspected after being decrypted, see getEncryptedBatchSizes
+	batchSizesStmt := fmt.Sprintf(`select sum(jsonb_array_length(batch)), count(*) from (select event_payload->'batch' as batch from %s where encryption_key_id is null) t`, r.jobTableName)
+	err = r.dbHandle.QueryRow(batchSizesStmt).Scan(&batchSizes, &numBatches)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+	encryptedBatchSizes, numEncryptedBatches, err := r.getEncryptedBatchSizes()
+	if err != nil {
+		return 0, err
 	}
-	return avgBatchSize, err
+	if numBatches+numEncryptedBatches == 0 {
+		return 0, nil
+	}
+	return float64(batchSizes.Int64+encryptedBatchSizes) / float64(numBatches+numEncryptedBatches), nil
+}
+
+// getEncryptedBatchSizes returns the total number of events and batches of the jobs whose payloads were stored encrypted
+func (r *SqlRunner) getEncryptedBatchSizes() (batchSizes, numBatches int64, err error) {
+	encryptedStmt := fmt.Sprintf(`select event_payload, encryption_key_id from %s where encryption_key_id is not null`, r.jobTableName)
+	rows, err := r.dbHandle.Query(encryptedStmt)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var payload json.RawMessage
+		var keyID string
+		if err = rows.Scan(&payload, &keyID); err != nil {
+			return 0, 0, err
+		}
+		if r.decryptPayload == nil {
+			return 0, 0, fmt.Errorf("payload is encrypted with key %q but payload encryption is not enabled", keyID)
+		}
+		if payload, err = r.decryptPayload(payload, keyID); err != nil {
+			return 0, 0, err
+		}
+		batchSizes += gjson.GetBytes(payload, "batch.#").Int()
+		numBatches++
+	}
+	return batchSizes, numBatches, rows.Err()
 }
 
 func (r *SqlRunner) getTableSize() (int64, error) {
@@ -156,6 +199,9 @@ func (g *GatewayRPCHandler) GetDSStats(dsName string, result *string) (err error
 	}
 	defer func() { _ = dbHandle.Close() }() // since this also returns an error, we can explicitly close but not doing
 	runner := &SqlRunner{dbHandle: dbHandle, jobTableName: jobTableName}
+	if decrypter, ok := g.jobsDB.(payloadDecrypter); ok {
+		runner.decryptPayload = decrypter.DecryptPayload
+	}
 	sources, err := runner.getUniqueSources()
 	if err != nil {
 		misc.AppendError("getUniqueSources", &completeErr, &err)
@@ -45,6 +45,56 @@ func loadConfig() {
 	config.RegisterIntConfigVariable(524288, &maxHeaderBytes, false, 1, "MaxHeaderBytes")
 	// default values is '0', which means disabled. So, if `maxActiveClients` is not set. We consider it to be disabled.
 	config.RegisterIntConfigVariable(0, &maxConcurrentRequests, false, 1, "Gateway.maxConcurrentRequests")
+	// Time after which unprocessed gateway jobs expire and get aborted. default value is '0', which means jobs never expire.
+	config.RegisterDurationConfigVariable(0, &jobTTL, true, time.Second, []string{"Gateway.jobTTL", "Gateway.jobTTLInS"}...)
+	// Enables the gRPC ingestion endpoint. false by default
+	config.RegisterBoolConfigVariable(false, &enableGRPC, false, "Gateway.grpc.enabled")
+	// Port where the gRPC ingestion endpoint is running
+	config.RegisterIntConfigVariable(8090, &grpcPort, false, 1, "Gateway.grpc.port")
+	// Maximum number of requests of a single gRPC stream that are being processed concurrently
+	config.RegisterIntConfigVariable(128, &maxGRPCInFlightRequests, false, 1, "Gateway.grpc.maxInFlightRequests")
+	// Enables consuming the kafka topics of Gateway.kafka.topics. false by default
+	config.RegisterBoolConfigVariable(false, &enableKafkaSource, false, "Gateway.kafka.enabled")
+	config.RegisterStringSliceConfigVariable(nil, &kafkaBrokers, false, "Gateway.kafka.brokers")
+	// Kafka topics consumed, along with the write key of the source their records belong to, as topic:writeKey
+	config.RegisterStringSliceConfigVariable(nil, &kafkaTopics, false, "Gateway.kafka.topics")
+	config.RegisterStringConfigVariable("rudder-gateway", &kafkaConsumerGroup, false, "Gateway.kafka.consumerGroup")
+	// Request type of kafka records without a type header
+	config.RegisterStringConfigVariable("batch", &kafkaRequestType, false, "Gateway.kafka.requestType")
+	// Maximum number of records of a single topic that are being processed concurrently
+	config.RegisterIntConfigVariable(128, &maxKafkaInFlightRecords, false, 1, "Gateway.kafka.maxInFlightRecords")
+	config.RegisterDurationConfigVariable(1, &kafkaCommitInterval, false, time.Second, "Gateway.kafka.commitInterval")
+	config.RegisterDurationConfigVariable(10, &kafkaDialTimeout, false, time.Second, "Gateway.kafka.dialTimeout")
+	// Enables handling the Idempotency-Key header of batch requests. false by default
+	config.RegisterBoolConfigVariable(false, &enableIdempotencyKeys, false, "Gateway.idempotency.enabled")
+	// Time during which replays of a request with an idempotency key get the response of the original request
+	config.RegisterDurationConfigVariable(24, &idempotencyWindow, false, time.Hour, "Gateway.idempotency.window")
+	// Enables enforcing the json schemas of sources. false by default
+	config.RegisterBoolConfigVariable(false, &enableSchemaEnforcement, false, "Gateway.schemaEnforcement.enabled")
+	// Directory containing the json schemas files of sources, named <sourceID>.json
+	config.RegisterStringConfigVariable("/etc/rudderstack/schemas", &schemasDir, false, "Gateway.schemaEnforcement.schemasDir")
+	// How requests violating the json schemas of their source are handled: disabled, reject or quarantine. Sources need to opt in by default.
+	config.RegisterStringConfigVariable(schemaEnforcementDisabled, &schemaEnforcementMode, true, "Gateway.schemaEnforcement.mode")
+}
+
+// getJobTTLForSource returns the time to live of jobs received for the source, zero if they never expire.
+// It can be overridden per source using Gateway.<sourceID>.jobTTL, which is resolved whenever the backend config changes.
+func getJobTTLForSource(sourceID string) time.Duration {
+	configSubscriberLock.RLock()
+	defer configSubscriberLock.RUnlock()
+
+	if ttl, ok := sourceIDJobTTLMap[sourceID]; ok {
+		return ttl
+	}
+	return jobTTL
+}
+
+// sourceJobTTL returns the time to live of jobs received for the source if it is overridden by Gateway.<sourceID>.jobTTL
+func sourceJobTTL(sourceID string) (time.Duration, bool) {
+	if !config.IsSet("Gateway." + sourceID + ".jobTTL") {
+		return 0, false
+	}
+	return config.GetDuration("Gateway."+sourceID+".jobTTL", 0, time.Second), true
 }
 
 // MaxReqSize is the maximum request body size, in bytes, accepted by gateway web handlers
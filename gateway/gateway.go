@@ -85,6 +85,7 @@ var (
 	enabledWriteKeyWebhookMap                                                         map[string]string
 	enabledWriteKeyWorkspaceMap                                                       map[string]string
 	sourceIDToNameMap                                                                 map[string]string
+	sourceIDJobTTLMap                                                                 map[string]time.Duration
 	configSubscriberLock                                                              sync.RWMutex
 	maxReqSize                                                                        int
 	enableRateLimit                                                                   bool
@@ -97,6 +98,18 @@ var (
 	IdleTimeout                                                                       time.Duration
 	allowReqsWithoutUserIDAndAnonymousID                                              bool
 	gwAllowPartialWriteWithErrors                                                     bool
+	jobTTL                                                                            time.Duration
+	enableGRPC                                                                        bool
+	grpcPort, maxGRPCInFlightRequests                                                 int
+	enableSchemaEnforcement                                                           bool
+	enableIdempotencyKeys                                                             bool
+	idempotencyWindow                                                                 time.Duration
+	enableKafkaSource                                                                 bool
+	kafkaBrokers, kafkaTopics                                                         []string
+	kafkaConsumerGroup, kafkaRequestType                                              string
+	maxKafkaInFlightRecords                                                           int
+	kafkaCommitInterval, kafkaDialTimeout                                             time.Duration
+	schemasDir, schemaEnforcementMode                                                 string
 	pkgLogger                                                                         logger.LoggerI
 	Diagnostics                                                                       diagnostics.DiagnosticsI
 )
@@ -187,6 +200,10 @@ type HandleT struct {
 	backgroundCancel                                           context.CancelFunc
 	backgroundWait                                             func() error
 	rsourcesService                                            rsources.JobService
+	schemaStore                                                *schemaStoreT
+	idempotencyStore                                           idempotencyStore
+	closeIdempotencyStore                                      func()
+	quarantineDB                                               jobsdb.JobsDB
 }
 
 func (gateway *HandleT) updateSourceStats(sourceStats map[string]int, bucket string, sourceTagMap map[string]string) {
@@ -427,7 +444,9 @@ func (gateway *HandleT) userWebRequestWorkerProcess(userWebRequestWorker *userWe
 		sourceFailStats := make(map[string]int)
 		sourceFailEventStats := make(map[string]int)
 		workspaceDropRequestStats := make(map[string]int)
+		sourceQuarantineStats := make(map[string]int)
 		sourceTagMap := make(map[string]string)
+		var quarantined []quarantinedRequestT
 		var preDbStoreCount int
 		// Saving the event data read from req.request.Body to the splice.
 		// Using this to send event schema to the config backend.
@@ -593,6 +612,42 @@ func (gateway *HandleT) userWebRequestWorkerProcess(userWebRequestWorker *userWe
 			body, _ = sjson.SetBytes(body, "requestIP", ipAddr)
 			body, _ = sjson.SetBytes(body, "writeKey", writeKey)
 			body, _ = sjson.SetBytes(body, "receivedAt", time.Now().Format(misc.RFC3339Milli))
+
+			if mode, violations := gateway.enforceSchemas(sourceID, body); len(violations) > 0 {
+				errorMessage := response.MakeSchemaViolationResponse(violations)
+				preDbStoreCount++
+				misc.IncrementMapByKey(sourceFailStats, sourceTag, 1)
+				misc.IncrementMapByKey(sourceFailEventStats, sourceTag, totalEventsInReq)
+				if mode != schemaEnforcementQuarantine || gateway.quarantineDB == nil {
+					req.done <- errorMessage
+					continue
+				}
+				marshalledParams, err := json.Marshal(map[string]interface{}{
+					"source_id":         sourceID,
+					"batch_id":          counter,
+					"schema_violations": violations,
+				})
+				if err != nil {
+					gateway.logger.Errorf("[Gateway] Failed to marshal schema violations: %+v", violations)
+					marshalledParams = []byte(`{"error": "rudder-server gateway failed to marshal params"}`)
+				}
+				quarantined = append(quarantined, quarantinedRequestT{
+					req: req,
+					job: &jobsdb.JobT{
+						UUID:         uuid.Must(uuid.NewV4()),
+						UserID:       builtUserID,
+						Parameters:   marshalledParams,
+						CustomVal:    CustomVal,
+						EventPayload: body,
+						EventCount:   totalEventsInReq,
+						WorkspaceId:  workspaceId,
+					},
+					errorMessage: errorMessage,
+				})
+				misc.IncrementMapByKey(sourceQuarantineStats, sourceTag, 1)
+				continue
+			}
+
 			eventBatchesToRecord = append(eventBatchesToRecord, string(body))
 			sourcesJobRunID := gjson.GetBytes(body, "batch.0.context.sources.job_run_id").Str   // pick the job_run_id from the first event of batch. We are assuming job_run_id will be same for all events in a batch and the batch is coming from rudder-sources
 			sourcesTaskRunID := gjson.GetBytes(body, "batch.0.context.sources.task_run_id").Str // pick the task_run_id from the first event of batch. We are assuming task_run_id will be same for all events in a batch and the batch is coming from rudder-sources
@@ -619,6 +674,9 @@ func (gateway *HandleT) userWebRequestWorkerProcess(userWebRequestWorker *userWe
 				EventCount:   totalEventsInReq,
 				WorkspaceId:  workspaceId,
 			}
+			if ttl := getJobTTLForSource(sourceID); ttl > 0 {
+				newJob.ExpiryTime = time.Now().Add(ttl)
+			}
 			jobList = append(jobList, &newJob)
 
 			jobIDReqMap[newJob.UUID] = req
@@ -626,6 +684,8 @@ func (gateway *HandleT) userWebRequestWorkerProcess(userWebRequestWorker *userWe
 			jobEventCountMap[newJob.UUID] = totalEventsInReq
 		}
 
+		gateway.storeQuarantinedRequests(quarantined)
+
 		errorMessagesMap := make(map[uuid.UUID]string)
 		if len(jobList) > 0 {
 			gateway.userWorkerBatchRequestQ <- &userWorkerBatchRequestT{
@@ -663,6 +723,7 @@ func (gateway *HandleT) userWebRequestWorkerProcess(userWebRequestWorker *userWe
 		gateway.updateSourceStats(sourceStats, "gateway.write_key_requests", sourceTagMap)
 		gateway.updateSourceStats(sourceSuccessStats, "gateway.write_key_successful_requests", sourceTagMap)
 		gateway.updateSourceStats(sourceFailStats, "gateway.write_key_failed_requests", sourceTagMap)
+		gateway.updateSourceStats(sourceQuarantineStats, "gateway.write_key_quarantined_requests", sourceTagMap)
 		if enableRateLimit {
 			gateway.updateSourceStats(workspaceDropRequestStats, "gateway.work_space_dropped_requests", sourceTagMap)
 		}
@@ -1117,7 +1178,7 @@ func (gateway *HandleT) webRequestHandler(rh RequestHandler, w http.ResponseWrit
 		errorMessage = err.Error()
 		return
 	}
-	errorMessage = rh.ProcessRequest(gateway, &w, r, reqType, payload, writeKey)
+	errorMessage = gateway.processIdempotentRequest(rh, w, r, reqType, payload, writeKey)
 	atomic.AddUint64(&gateway.ackCount, 1)
 	gateway.trackRequestMetrics(errorMessage)
 	if errorMessage != "" {
@@ -1463,8 +1524,12 @@ func (gateway *HandleT) backendConfigSubscriber() {
 		enabledWriteKeyWorkspaceMap = map[string]string{}
 		sources := config.Data.(backendconfig.ConfigT)
 		sourceIDToNameMap = map[string]string{}
+		sourceIDJobTTLMap = map[string]time.Duration{}
 		for _, source := range sources.Sources {
 			sourceIDToNameMap[source.ID] = source.Name
+			if ttl, ok := sourceJobTTL(source.ID); ok {
+				sourceIDJobTTLMap[source.ID] = ttl
+			}
 			writeKeysSourceMap[source.WriteKey] = source
 
 			if source.Enabled {
@@ -1489,13 +1554,16 @@ Finds the worker for a particular userID and queues the webrequest with the work
 They are further batched together in userWebRequestBatcher
 */
 func (gateway *HandleT) addToWebRequestQ(_ *http.ResponseWriter, req *http.Request, done chan string, reqType string, requestPayload []byte, writeKey string) {
-	userIDHeader := req.Header.Get("AnonymousId")
+	gateway.enqueueWebRequest(done, reqType, requestPayload, writeKey, misc.GetIPFromReq(req), req.Header.Get("AnonymousId"))
+}
+
+// enqueueWebRequest hands a request over to a user web request worker, which will send the request's response to done
+func (gateway *HandleT) enqueueWebRequest(done chan<- string, reqType string, requestPayload []byte, writeKey, ipAddr, userIDHeader string) {
 	if userIDHeader == "" {
 		// If the request comes through proxy, proxy would already send this. So this shouldn't be happening in that case
 		gateway.emptyAnonIdHeaderStat.Increment()
 	}
 	userWebRequestWorker := gateway.findUserWebRequestWorker(uuid.Must(uuid.NewV4()).String())
-	ipAddr := misc.GetIPFromReq(req)
 	webReq := webRequestT{done: done, reqType: reqType, requestPayload: requestPayload, writeKey: writeKey, ipAddr: ipAddr, userIDHeader: userIDHeader}
 	userWebRequestWorker.webRequestQ <- &webReq
 }
@@ -1601,6 +1669,18 @@ func (gateway *HandleT) Setup(
 		gateway.eventSchemaHandler = event_schema.GetInstance()
 	}
 
+	if enableSchemaEnforcement {
+		gateway.schemaStore = newSchemaStore(schemasDir)
+	}
+
+	if enableIdempotencyKeys {
+		store, err := newIdempotencyStore()
+		if err != nil {
+			return fmt.Errorf("could not setup idempotency store: %w", err)
+		}
+		gateway.idempotencyStore, gateway.closeIdempotencyStore = store, store.Close
+	}
+
 	rruntime.Go(func() {
 		gateway.backendConfigSubscriber()
 	})
@@ -1646,5 +1726,9 @@ func (gateway *HandleT) Shutdown() error {
 		close(worker.webRequestQ)
 	}
 
-	return gateway.backgroundWait()
+	err := gateway.backgroundWait()
+	if gateway.closeIdempotencyStore != nil {
+		gateway.closeIdempotencyStore()
+	}
+	return err
 }
@@ -9,7 +9,10 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/gofrs/uuid"
@@ -30,6 +33,7 @@ import (
 	mocksJobsDB "github.com/rudderlabs/rudder-server/mocks/jobsdb"
 	mocksRateLimiter "github.com/rudderlabs/rudder-server/mocks/rate-limiter"
 	mocksTypes "github.com/rudderlabs/rudder-server/mocks/utils/types"
+	"github.com/rudderlabs/rudder-server/services/dedup"
 	"github.com/rudderlabs/rudder-server/services/rsources"
 	"github.com/rudderlabs/rudder-server/services/stats"
 	"github.com/rudderlabs/rudder-server/utils/logger"
@@ -529,6 +533,166 @@ var _ = Describe("Gateway", func() {
 		}
 	})
 
+	Context("Schema enforcement", func() {
+		var (
+			gateway      *HandleT
+			quarantineDB *mocksJobsDB.MockJobsDB
+			modeKey      = "Gateway." + SourceIDEnabled + ".schemaEnforcement.mode"
+		)
+
+		BeforeEach(func() {
+			dir := GinkgoT().TempDir()
+			schemas := `[{
+				"schema": {"$schema": "http://json-schema.org/draft-07/schema#", "type": "object", "additionalProperties": false, "required": ["price"],
+					"properties": {"price": {"type": ["number"]}, "currency": {"type": ["string"]}}},
+				"schemaType": "track",
+				"schemaIdentifier": "Order Completed"
+			}]`
+			Expect(os.WriteFile(filepath.Join(dir, SourceIDEnabled+".json"), []byte(schemas), 0o600)).To(Succeed())
+			enableSchemaEnforcement, schemasDir = true, dir
+			config.SetString(modeKey, schemaEnforcementReject)
+
+			gateway = &HandleT{}
+			quarantineDB = mocksJobsDB.NewMockJobsDB(c.mockCtrl)
+			gateway.SetQuarantineDB(quarantineDB)
+			err := gateway.Setup(c.mockApp, c.mockBackendConfig, c.mockJobsDB, nil, c.mockVersionHandler, rsources.NewNoOpService())
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			enableSchemaEnforcement = false
+			config.SetString(modeKey, schemaEnforcementDisabled)
+		})
+
+		trackRequest := func(event, properties string) *http.Request {
+			return authorizedRequest(WriteKeyEnabled, bytes.NewBufferString(fmt.Sprintf(`{"userId":"dummyId","event":%q,"properties":%s}`, event, properties)))
+		}
+
+		expectViolations := func(req *http.Request, violations ...response.Violation) {
+			testutils.RunTestWithTimeout(func() {
+				rr := httptest.NewRecorder()
+				gateway.webTrackHandler(rr, req)
+				Expect(rr.Result().StatusCode).To(Equal(http.StatusBadRequest))
+				body, _ := io.ReadAll(rr.Body)
+				Expect(gjson.GetBytes(body, "msg").String()).To(Equal(response.SchemaViolation))
+				var actual []response.Violation
+				Expect(json.Unmarshal([]byte(gjson.GetBytes(body, "violations").Raw), &actual)).To(Succeed())
+				Expect(actual).To(ConsistOf(violations))
+			}, testTimeout)
+		}
+
+		It("should accept events conforming to their schema, or without a schema", func() {
+			c.mockJobsDB.EXPECT().WithStoreSafeTx(gomock.Any()).Times(2).Do(func(f func(tx jobsdb.StoreSafeTx) error) {
+				_ = f(jobsdb.EmptyStoreSafeTx())
+			}).Return(nil)
+			c.mockJobsDB.EXPECT().StoreWithRetryEachInTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(jobsToEmptyErrors).Times(2)
+
+			expectHandlerResponse(gateway.webTrackHandler, trackRequest("Order Completed", `{"price":10,"currency":"EUR"}`), 200, "OK")
+			expectHandlerResponse(gateway.webTrackHandler, trackRequest("Product Viewed", `{"anything":true}`), 200, "OK")
+		})
+
+		It("should reject events violating their schema with the violated paths", func() {
+			expectViolations(trackRequest("Order Completed", `{"price":"10","discount":1}`),
+				response.Violation{Path: "batch.0.properties.price", Error: "Invalid type. Expected: number, given: string"},
+				response.Violation{Path: "batch.0.properties.discount", Error: "Additional property discount is not allowed"},
+			)
+			expectViolations(trackRequest("Order Completed", `{}`),
+				response.Violation{Path: "batch.0.properties.price", Error: "price is required"},
+			)
+		})
+
+		It("should quarantine events violating their schema in quarantine mode", func() {
+			config.SetString(modeKey, schemaEnforcementQuarantine)
+			quarantineDB.EXPECT().Store(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, jobs []*jobsdb.JobT) error {
+				Expect(jobs).To(HaveLen(1))
+				Expect(gjson.GetBytes(jobs[0].EventPayload, "batch.0.properties.price").String()).To(Equal("free"))
+				Expect(gjson.GetBytes(jobs[0].EventPayload, "writeKey").String()).To(Equal(WriteKeyEnabled))
+				Expect(gjson.GetBytes(jobs[0].Parameters, "source_id").String()).To(Equal(SourceIDEnabled))
+				Expect(gjson.GetBytes(jobs[0].Parameters, "schema_violations.0.path").String()).To(Equal("batch.0.properties.price"))
+				return nil
+			})
+
+			expectViolations(trackRequest("Order Completed", `{"price":"free"}`),
+				response.Violation{Path: "batch.0.properties.price", Error: "Invalid type. Expected: number, given: string"},
+			)
+		})
+
+		It("should not enforce schemas of sources that didn't opt in", func() {
+			config.SetString(modeKey, schemaEnforcementDisabled)
+			c.mockJobsDB.EXPECT().WithStoreSafeTx(gomock.Any()).Times(1).Do(func(f func(tx jobsdb.StoreSafeTx) error) {
+				_ = f(jobsdb.EmptyStoreSafeTx())
+			}).Return(nil)
+			c.mockJobsDB.EXPECT().StoreWithRetryEachInTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(jobsToEmptyErrors).Times(1)
+
+			expectHandlerResponse(gateway.webTrackHandler, trackRequest("Order Completed", `{"price":"free"}`), 200, "OK")
+		})
+	})
+
+	Context("Idempotency keys", func() {
+		var (
+			gateway *HandleT
+			store   *fakeIdempotencyStore
+		)
+
+		BeforeEach(func() {
+			SetEnableRateLimit(true)
+			gateway = &HandleT{}
+			err := gateway.Setup(c.mockApp, c.mockBackendConfig, c.mockJobsDB, c.mockRateLimiter, c.mockVersionHandler, rsources.NewNoOpService())
+			Expect(err).To(BeNil())
+			store = &fakeIdempotencyStore{responses: map[string]*string{}}
+			gateway.idempotencyStore = store
+			c.mockBackendConfig.EXPECT().GetWorkspaceIDForWriteKey(WriteKeyEnabled).Return("workspace").AnyTimes()
+		})
+
+		AfterEach(func() {
+			SetEnableRateLimit(false)
+		})
+
+		batchRequest := func(idempotencyKey string) *http.Request {
+			req := authorizedRequest(WriteKeyEnabled, bytes.NewBufferString(`{"batch":[{"type":"track","userId":"dummyId"}]}`))
+			req.Header.Set(idempotencyKeyHeader, idempotencyKey)
+			return req
+		}
+
+		It("should respond to replays of a request with the response of the original request, without storing them", func() {
+			c.mockRateLimiter.EXPECT().LimitReached("workspace").Return(false).Times(1)
+			c.mockJobsDB.EXPECT().WithStoreSafeTx(gomock.Any()).Times(1).Do(func(f func(tx jobsdb.StoreSafeTx) error) {
+				_ = f(jobsdb.EmptyStoreSafeTx())
+			}).Return(nil)
+			c.mockJobsDB.EXPECT().StoreWithRetryEachInTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(jobsToEmptyErrors).Times(1)
+
+			expectHandlerResponse(gateway.webBatchHandler, batchRequest("key-1"), 200, "OK")
+			testutils.RunTestWithTimeout(func() {
+				rr := httptest.NewRecorder()
+				gateway.webBatchHandler(rr, batchRequest("key-1"))
+				Expect(rr.Result().StatusCode).To(Equal(http.StatusOK))
+				Expect(rr.Result().Header.Get(idempotentReplayedHeader)).To(Equal("true"))
+			}, testTimeout)
+			Expect(store.responses).To(HaveKey(WriteKeyEnabled + DELIMITER + "key-1"))
+		})
+
+		It("should reject requests whose idempotency key is in use", func() {
+			store.responses[WriteKeyEnabled+DELIMITER+"key-1"] = nil
+
+			expectHandlerResponse(gateway.webBatchHandler, batchRequest("key-1"), http.StatusConflict, response.IdempotencyKeyInUse+"\n")
+		})
+
+		It("should release the idempotency key of requests failing for a retryable reason", func() {
+			gomock.InOrder(
+				c.mockRateLimiter.EXPECT().LimitReached("workspace").Return(true).Times(1),
+				c.mockRateLimiter.EXPECT().LimitReached("workspace").Return(false).Times(1),
+			)
+			c.mockJobsDB.EXPECT().WithStoreSafeTx(gomock.Any()).Times(1).Do(func(f func(tx jobsdb.StoreSafeTx) error) {
+				_ = f(jobsdb.EmptyStoreSafeTx())
+			}).Return(nil)
+			c.mockJobsDB.EXPECT().StoreWithRetryEachInTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(jobsToEmptyErrors).Times(1)
+
+			expectHandlerResponse(gateway.webBatchHandler, batchRequest("key-1"), 429, response.TooManyRequests+"\n")
+			Expect(store.responses).To(BeEmpty())
+			expectHandlerResponse(gateway.webBatchHandler, batchRequest("key-1"), 200, "OK")
+		})
+	})
+
 	Context("Robots", func() {
 		gateway := &HandleT{}
 
@@ -552,6 +716,40 @@ func unauthorizedRequest(body io.Reader) *http.Request {
 	return req
 }
 
+// fakeIdempotencyStore keeps responses in memory, a nil response meaning the key is in use
+type fakeIdempotencyStore struct {
+	mu        sync.Mutex
+	responses map[string]*string
+}
+
+func (s *fakeIdempotencyStore) Begin(_ context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.responses[key]
+	if !ok {
+		s.responses[key] = nil
+		return "", false, nil
+	}
+	if stored == nil {
+		return "", false, dedup.ErrIdempotencyKeyInUse
+	}
+	return *stored, true, nil
+}
+
+func (s *fakeIdempotencyStore) Complete(_ context.Context, key, response string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[key] = &response
+	return nil
+}
+
+func (s *fakeIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.responses, key)
+	return nil
+}
+
 func authorizedRequest(username string, body io.Reader) *http.Request {
 	req := unauthorizedRequest(body)
 
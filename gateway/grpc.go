@@ -0,0 +1,183 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/rudderlabs/rudder-server/gateway/response"
+	proto "github.com/rudderlabs/rudder-server/proto/gateway"
+	"github.com/rudderlabs/rudder-server/services/stats"
+)
+
+const (
+	// grpcWriteKeyMetadata is the metadata key of gRPC calls containing the source's write key
+	grpcWriteKeyMetadata = "writekey"
+	grpcShutdownTimeout  = 10 * time.Second
+)
+
+// ingestRequestTypes are the request types accepted by the gRPC ingestion endpoint & the kafka source, i.e. the ones of the /v1/* http endpoints
+var ingestRequestTypes = map[string]struct{}{
+	"batch": {}, "track": {}, "identify": {}, "page": {}, "screen": {}, "alias": {}, "merge": {}, "group": {},
+}
+
+/*
+StartGRPCHandler starts the gRPC ingestion endpoint (see proto/gateway), listening on the gateway gRPC port, if it is enabled.
+
+Requests received through gRPC are handed over to the same user web request workers as the ones received by the http
+handlers, so they go through the same validation, deduplication, user suppression and rate limiting.
+*/
+func (gateway *HandleT) StartGRPCHandler(ctx context.Context) error {
+	if !enableGRPC {
+		return nil
+	}
+	gateway.logger.Infof("GRPCHandler waiting for BackendConfig before starting on %d", grpcPort)
+	gateway.backendConfig.WaitForConfig(ctx)
+	gateway.logger.Infof("GRPCHandler Starting on %d", grpcPort)
+
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(grpcPort))
+	if err != nil {
+		return err
+	}
+	server := grpc.NewServer(grpc.MaxRecvMsgSize(maxReqSize + 1024))
+	proto.RegisterGatewayServer(server, &grpcIngestServer{gateway: gateway})
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(grpcShutdownTimeout):
+			// streams are long-lived, don't wait for clients to close them
+			server.Stop()
+		}
+		return nil
+	}
+}
+
+type grpcIngestServer struct {
+	proto.UnimplementedGatewayServer
+	gateway *HandleT
+}
+
+// Ingest processes the requests of the stream concurrently, up to maxGRPCInFlightRequests at a time, acknowledging each one of them
+// as soon as its response is available
+func (s *grpcIngestServer) Ingest(stream proto.Gateway_IngestServer) error {
+	gateway := s.gateway
+	ctx := stream.Context()
+	writeKey := grpcWriteKey(ctx)
+	if writeKey == "" {
+		gateway.updateSourceStats(map[string]int{"noWriteKey": 1}, "gateway.write_key_failed_requests", map[string]string{"noWriteKey": "noWriteKey", "reqType": "grpc"})
+		gateway.updateSourceStats(map[string]int{"noWriteKey": 1}, "gateway.write_key_requests", map[string]string{"noWriteKey": "noWriteKey", "reqType": "grpc"})
+		return status.Error(codes.Unauthenticated, response.NoWriteKeyInBasicAuth)
+	}
+	ipAddr := grpcClientIP(ctx)
+
+	var (
+		sendMu   sync.Mutex
+		wg       sync.WaitGroup
+		inFlight = make(chan struct{}, maxGRPCInFlightRequests)
+	)
+	ack := func(requestID, errorMessage string) {
+		atomic.AddUint64(&gateway.ackCount, 1)
+		gateway.trackRequestMetrics(errorMessage)
+		res := &proto.IngestResponse{RequestID: requestID, StatusCode: http.StatusOK}
+		if errorMessage != "" {
+			res.StatusCode = int32(response.GetErrorStatusCode(errorMessage))
+			res.Error = errorMessage
+		}
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		if err := stream.Send(res); err != nil {
+			// the stream is broken, receiving from it will fail too
+			gateway.logger.Debugf("IP: %s -- gRPC -- Failed to acknowledge request %q: %v", ipAddr, requestID, err)
+		}
+	}
+	defer wg.Wait()
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		atomic.AddUint64(&gateway.recvCount, 1)
+		reqType := req.Type
+		if reqType == "" {
+			reqType = "batch"
+		}
+		if _, ok := ingestRequestTypes[reqType]; !ok {
+			ack(req.RequestID, response.InvalidRequestType)
+			continue
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		wg.Add(1)
+		go func(req *proto.IngestRequest, reqType string) {
+			defer wg.Done()
+			defer func() { <-inFlight }()
+			start := time.Now()
+			done := make(chan string, 1)
+			gateway.enqueueWebRequest(done, reqType, req.Payload, writeKey, ipAddr, req.AnonymousID)
+			errorMessage := <-done
+			gateway.stats.NewTaggedStat("gateway.grpc_req_handler_time", stats.TimerType, stats.Tags{"reqType": reqType}).Since(start)
+			ack(req.RequestID, errorMessage)
+		}(req, reqType)
+	}
+}
+
+// grpcWriteKey returns the write key found in the metadata of a gRPC call
+func grpcWriteKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(grpcWriteKeyMetadata); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// grpcClientIP returns the ip of the client of a gRPC call, the same way misc.GetIPFromReq does for http requests
+func grpcClientIP(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 && forwarded[0] != "" {
+			return strings.ReplaceAll(strings.Split(forwarded[0], ",")[0], " ", "")
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+package gateway
+
+import (
+	"context"
+	"net"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/tidwall/gjson"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/rudderlabs/rudder-server/gateway/response"
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	proto "github.com/rudderlabs/rudder-server/proto/gateway"
+	"github.com/rudderlabs/rudder-server/services/rsources"
+	"github.com/rudderlabs/rudder-server/services/stats"
+)
+
+var _ = Describe("Gateway gRPC", func() {
+	initGW()
+
+	var (
+		c      *testContext
+		client proto.GatewayClient
+		server *grpc.Server
+		conn   *grpc.ClientConn
+	)
+
+	BeforeEach(func() {
+		c = &testContext{}
+		c.Setup()
+		c.initializeAppFeatures()
+		stats.Setup()
+		SetEnableRateLimit(false)
+		SetEnableEventSchemasFeature(false)
+
+		gateway := &HandleT{}
+		err := gateway.Setup(c.mockApp, c.mockBackendConfig, c.mockJobsDB, nil, c.mockVersionHandler, rsources.NewNoOpService())
+		Expect(err).To(BeNil())
+
+		listener := bufconn.Listen(1024 * 1024)
+		server = grpc.NewServer()
+		proto.RegisterGatewayServer(server, &grpcIngestServer{gateway: gateway})
+		go func() { _ = server.Serve(listener) }()
+		conn, err = grpc.Dial("bufnet",
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+			grpc.WithInsecure(),
+		)
+		Expect(err).To(BeNil())
+		client = proto.NewGatewayClient(conn)
+	})
+
+	AfterEach(func() {
+		_ = conn.Close()
+		server.Stop()
+		c.Finish()
+	})
+
+	ingest := func(writeKey string, requests ...*proto.IngestRequest) (map[string]*proto.IngestResponse, error) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "writekey", writeKey, "x-forwarded-for", TestRemoteAddress)
+		stream, err := client.Ingest(ctx)
+		Expect(err).To(BeNil())
+		for _, req := range requests {
+			Expect(stream.Send(req)).To(Succeed())
+		}
+		Expect(stream.CloseSend()).To(Succeed())
+		responses := map[string]*proto.IngestResponse{}
+		for range requests {
+			res, err := stream.Recv()
+			if err != nil {
+				return responses, err
+			}
+			responses[res.RequestID] = res
+		}
+		return responses, nil
+	}
+
+	It("should acknowledge and store valid requests", func() {
+		c.mockJobsDB.EXPECT().WithStoreSafeTx(gomock.Any()).Times(1).Do(func(f func(tx jobsdb.StoreSafeTx) error) {
+			_ = f(jobsdb.EmptyStoreSafeTx())
+		}).Return(nil)
+		c.mockJobsDB.EXPECT().StoreWithRetryEachInTx(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx jobsdb.StoreSafeTx, jobs []*jobsdb.JobT) map[uuid.UUID]string {
+				Expect(jobs).To(HaveLen(1))
+				payload := jobs[0].EventPayload
+				Expect(gjson.GetBytes(payload, "writeKey").String()).To(Equal(WriteKeyEnabled))
+				Expect(gjson.GetBytes(payload, "requestIP").String()).To(Equal(TestRemoteAddress))
+				Expect(gjson.GetBytes(payload, "batch.0.type").String()).To(Equal("track"))
+				Expect(gjson.GetBytes(payload, "batch.0.userId").String()).To(Equal("dummyId"))
+				c.asyncHelper.ExpectAndNotifyCallbackWithName("jobsdb_store")()
+				return jobsToEmptyErrors(ctx, tx, jobs)
+			}).Times(1)
+
+		responses, err := ingest(WriteKeyEnabled, &proto.IngestRequest{RequestID: "1", Type: "track", Payload: []byte(`{"userId":"dummyId"}`)})
+		Expect(err).To(BeNil())
+		Expect(responses["1"].StatusCode).To(Equal(int32(200)))
+		Expect(responses["1"].Error).To(BeEmpty())
+	})
+
+	It("should acknowledge invalid requests with the same errors as the http handlers", func() {
+		responses, err := ingest(WriteKeyEnabled,
+			&proto.IngestRequest{RequestID: "invalid-json", Type: "track", Payload: []byte(`not-a-valid-json`)},
+			&proto.IngestRequest{RequestID: "invalid-type", Type: "unknown", Payload: []byte(`{"userId":"dummyId"}`)},
+			&proto.IngestRequest{RequestID: "non-identifiable", Type: "track", Payload: []byte(`{"data":"valid-json"}`)},
+		)
+		Expect(err).To(BeNil())
+		Expect(responses["invalid-json"].StatusCode).To(Equal(int32(400)))
+		Expect(responses["invalid-json"].Error).To(Equal(response.InvalidJSON))
+		Expect(responses["invalid-type"].StatusCode).To(Equal(int32(400)))
+		Expect(responses["invalid-type"].Error).To(Equal(response.InvalidRequestType))
+		Expect(responses["non-identifiable"].Error).To(Equal(response.NonIdentifiableRequest))
+
+		responses, err = ingest(WriteKeyInvalid, &proto.IngestRequest{RequestID: "1", Payload: []byte(`{"batch":[{"userId":"dummyId"}]}`)})
+		Expect(err).To(BeNil())
+		Expect(responses["1"].StatusCode).To(Equal(int32(401)))
+		Expect(responses["1"].Error).To(Equal(response.InvalidWriteKey))
+	})
+
+	It("should reject streams without a write key", func() {
+		_, err := ingest("", &proto.IngestRequest{RequestID: "1", Payload: []byte(`{}`)})
+		Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+	})
+})
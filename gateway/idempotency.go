@@ -0,0 +1,76 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/rudderlabs/rudder-server/gateway/response"
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/services/dedup"
+)
+
+const (
+	// idempotencyKeyHeader is the header of batch requests containing the client-provided idempotency key of the request
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader is set on responses of requests which are replays of an earlier request
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// idempotencyStore keeps the responses of requests by their idempotency key, see dedup.IdempotencyStore
+type idempotencyStore interface {
+	Begin(ctx context.Context, key string) (response string, found bool, err error)
+	Complete(ctx context.Context, key, response string) error
+	Release(ctx context.Context, key string) error
+}
+
+// newIdempotencyStore returns the idempotency store shared by all gateway nodes using the same jobsdb
+func newIdempotencyStore() (*dedup.IdempotencyStore, error) {
+	db, err := sql.Open("postgres", jobsdb.GetConnectionString())
+	if err != nil {
+		return nil, err
+	}
+	return dedup.NewIdempotencyStore(db, dedup.WithWindow(idempotencyWindow))
+}
+
+/*
+processIdempotentRequest processes a batch request having an Idempotency-Key header at most once within the idempotency window.
+
+Replays of the request get the response of the original request instead of being processed again. Responses of
+requests failing for a reason worth retrying (rate limits, internal errors) are not kept, so that they can be retried.
+*/
+func (gateway *HandleT) processIdempotentRequest(rh RequestHandler, w http.ResponseWriter, r *http.Request, reqType string, payload []byte, writeKey string) string {
+	idempotencyKey := r.Header.Get(idempotencyKeyHeader)
+	if gateway.idempotencyStore == nil || reqType != "batch" || idempotencyKey == "" {
+		return rh.ProcessRequest(gateway, &w, r, reqType, payload, writeKey)
+	}
+	// keys are provided by clients, so they are scoped by source
+	key := writeKey + DELIMITER + idempotencyKey
+	ctx := r.Context()
+	stored, found, err := gateway.idempotencyStore.Begin(ctx, key)
+	if errors.Is(err, dedup.ErrIdempotencyKeyInUse) {
+		return response.IdempotencyKeyInUse
+	}
+	if err != nil {
+		gateway.logger.Errorf("Checking idempotency key %q: %v", idempotencyKey, err)
+		return response.IdempotencyKeyCheckFailed
+	}
+	if found {
+		w.Header().Set(idempotentReplayedHeader, "true")
+		return stored
+	}
+
+	errorMessage := rh.ProcessRequest(gateway, &w, r, reqType, payload, writeKey)
+	// the request was processed, so its response needs to be kept even if the client is gone
+	ctx = context.Background()
+	if statusCode := response.GetErrorStatusCode(errorMessage); errorMessage != "" && (statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError) {
+		err = gateway.idempotencyStore.Release(ctx, key)
+	} else {
+		err = gateway.idempotencyStore.Complete(ctx, key, errorMessage)
+	}
+	if err != nil {
+		gateway.logger.Errorf("Storing response for idempotency key %q: %v", idempotencyKey, err)
+	}
+	return errorMessage
+}
@@ -0,0 +1,318 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/gateway/response"
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/services/streammanager/kafka/client"
+)
+
+const (
+	// kafkaRequestTypeHeader is the header of kafka records containing their request type, overriding Gateway.kafka.requestType
+	kafkaRequestTypeHeader = "type"
+	// kafkaAnonymousIDHeader is the header of kafka records playing the role of the AnonymousId header of http requests
+	kafkaAnonymousIDHeader = "anonymousId"
+)
+
+// kafkaConsumer is the part of client.Consumer used for consuming a topic
+type kafkaConsumer interface {
+	Fetch(ctx context.Context) (client.Message, error)
+	Commit(ctx context.Context, msgs ...client.Message) error
+}
+
+/*
+StartKafkaConsumers consumes the kafka topics configured in Gateway.kafka.topics, if the kafka source is enabled.
+
+Each topic is mapped to the write key of a source, as topic:writeKey. Records go through the same user web request workers
+as the requests received by the http handlers, so they are validated & stored into the gateway jobsdb the same way.
+The offset of a record is committed only after the record has been stored, or rejected for a reason retrying won't fix.
+*/
+func (gateway *HandleT) StartKafkaConsumers(ctx context.Context) error {
+	if !enableKafkaSource {
+		return nil
+	}
+	topics, err := kafkaTopicWriteKeys()
+	if err != nil {
+		return err
+	}
+	kafkaClient, err := newKafkaSourceClient()
+	if err != nil {
+		return fmt.Errorf("could not create kafka source client: %w", err)
+	}
+	gateway.logger.Info("KafkaConsumers waiting for BackendConfig before starting")
+	gateway.backendConfig.WaitForConfig(ctx)
+
+	g, ctx := errgroup.WithContext(ctx)
+	for topic, writeKey := range topics {
+		topic, writeKey := topic, writeKey
+		gateway.logger.Infof("KafkaConsumer Starting for topic %s", topic)
+		consumer := kafkaClient.NewConsumer(topic, client.ConsumerConfig{
+			GroupID:     kafkaConsumerGroup,
+			StartOffset: client.FirstOffset,
+			ErrorLogger: kafkaLogger{gateway: gateway},
+		})
+		g.Go(func() error {
+			defer func() {
+				closeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				if err := consumer.Close(closeCtx); err != nil {
+					gateway.logger.Warnf("Closing kafka consumer for topic %s: %v", topic, err)
+				}
+			}()
+			return gateway.consumeKafkaTopic(ctx, consumer, topic, writeKey)
+		})
+	}
+	return g.Wait()
+}
+
+// kafkaRecordT is a record of a topic being ingested, processed gets closed once its offset can be committed
+type kafkaRecordT struct {
+	msg       client.Message
+	processed chan struct{}
+}
+
+/*
+consumeKafkaTopic ingests the records of the topic, up to maxKafkaInFlightRecords at a time, committing their offsets in
+the order they were fetched every kafkaCommitInterval. The partitions of the topic are ingested concurrently, but the records
+of a partition are ingested one after the other, so that the events of a partition are stored in order, even when retried.
+*/
+func (gateway *HandleT) consumeKafkaTopic(ctx context.Context, consumer kafkaConsumer, topic, writeKey string) error {
+	records := make(chan *kafkaRecordT, maxKafkaInFlightRecords)
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		defer close(records)
+		partitions := make(map[int32]chan *kafkaRecordT) // partition -> records waiting to be ingested
+		defer func() {
+			for _, partition := range partitions {
+				close(partition)
+			}
+		}()
+		for {
+			msg, err := consumer.Fetch(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("fetching from kafka topic %s: %w", topic, err)
+			}
+			record := &kafkaRecordT{msg: msg, processed: make(chan struct{})}
+			select {
+			case records <- record:
+			case <-ctx.Done():
+				return nil
+			}
+			partition, ok := partitions[msg.Partition]
+			if !ok {
+				partition = make(chan *kafkaRecordT, maxKafkaInFlightRecords)
+				partitions[msg.Partition] = partition
+				g.Go(func() error {
+					for record := range partition {
+						if !gateway.ingestKafkaRecord(ctx, writeKey, record.msg) {
+							return nil
+						}
+						close(record.processed)
+					}
+					return nil
+				})
+			}
+			select {
+			case partition <- record:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	})
+	g.Go(func() error {
+		var processed []client.Message
+		commit := func(ctx context.Context) error {
+			if len(processed) == 0 {
+				return nil
+			}
+			if err := consumer.Commit(ctx, processed...); err != nil {
+				return fmt.Errorf("committing offsets of kafka topic %s: %w", topic, err)
+			}
+			processed = nil
+			return nil
+		}
+		// records processed before shutting down are committed, even though the context is canceled
+		defer func() {
+			commitCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := commit(commitCtx); err != nil {
+				gateway.logger.Warn(err)
+			}
+		}()
+		ticker := time.NewTicker(kafkaCommitInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case record, ok := <-records:
+				if !ok {
+					return nil
+				}
+				select {
+				case <-record.processed:
+					processed = append(processed, record.msg)
+				case <-ctx.Done():
+					return nil
+				}
+			case <-ticker.C:
+				if err := commit(ctx); err != nil {
+					return err
+				}
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	})
+	return g.Wait()
+}
+
+/*
+ingestKafkaRecord hands the record over to the user web request workers, retrying for as long as the gateway responds
+with a retryable error (rate limits, internal errors). It returns false if the context got canceled before the record
+could be processed.
+*/
+func (gateway *HandleT) ingestKafkaRecord(ctx context.Context, writeKey string, msg client.Message) bool {
+	reqType, anonymousID := kafkaRequestType, ""
+	for _, header := range msg.Headers {
+		switch header.Key {
+		case kafkaRequestTypeHeader:
+			reqType = string(header.Value)
+		case kafkaAnonymousIDHeader:
+			anonymousID = string(header.Value)
+		}
+	}
+	sourceTag := gateway.getSourceTagFromWriteKey(writeKey)
+	tags := stats.Tags{"topic": msg.Topic, "source": sourceTag}
+	if _, ok := ingestRequestTypes[reqType]; !ok {
+		gateway.logger.Errorf("Dropping record %d of kafka topic %s partition %d: %s %q", msg.Offset, msg.Topic, msg.Partition, response.InvalidRequestType, reqType)
+		gateway.stats.NewTaggedStat("gateway.kafka_dropped_records", stats.CountType, tags).Increment()
+		return true
+	}
+
+	expBackoff := backoff.NewExponentialBackOff()
+	// the record's offset cannot be committed until it's processed, so there is no giving up
+	expBackoff.MaxElapsedTime = 0
+	retry := backoff.WithContext(expBackoff, ctx)
+	retry.Reset()
+	for {
+		atomic.AddUint64(&gateway.recvCount, 1)
+		start := time.Now()
+		done := make(chan string, 1)
+		gateway.enqueueWebRequest(done, reqType, msg.Value, writeKey, "", anonymousID)
+		var errorMessage string
+		select {
+		case errorMessage = <-done:
+		case <-ctx.Done():
+			return false
+		}
+		atomic.AddUint64(&gateway.ackCount, 1)
+		gateway.trackRequestMetrics(errorMessage)
+		gateway.stats.NewTaggedStat("gateway.kafka_record_handler_time", stats.TimerType, tags).Since(start)
+		if errorMessage == "" {
+			return true
+		}
+		if statusCode := response.GetErrorStatusCode(errorMessage); statusCode != http.StatusTooManyRequests && statusCode < http.StatusInternalServerError {
+			gateway.logger.Errorf("Dropping record %d of kafka topic %s partition %d: %s", msg.Offset, msg.Topic, msg.Partition, errorMessage)
+			gateway.stats.NewTaggedStat("gateway.kafka_dropped_records", stats.CountType, tags).Increment()
+			return true
+		}
+		next := retry.NextBackOff()
+		if next == backoff.Stop {
+			return false
+		}
+		gateway.logger.Warnf("Retrying record %d of kafka topic %s partition %d in %s: %s", msg.Offset, msg.Topic, msg.Partition, next, errorMessage)
+		select {
+		case <-time.After(next):
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// kafkaTopicWriteKeys returns the write keys of the topics configured in Gateway.kafka.topics, as topic:writeKey
+func kafkaTopicWriteKeys() (map[string]string, error) {
+	topics := make(map[string]string, len(kafkaTopics))
+	for _, mapping := range kafkaTopics {
+		topic, writeKey, ok := strings.Cut(mapping, ":")
+		if !ok || topic == "" || writeKey == "" {
+			return nil, fmt.Errorf("invalid kafka topic %q, expected topic:writeKey", mapping)
+		}
+		topics[topic] = writeKey
+	}
+	if len(topics) == 0 {
+		return nil, errors.New("kafka source is enabled but no topics are configured")
+	}
+	return topics, nil
+}
+
+// newKafkaSourceClient creates the client of the kafka source, using the TLS & SASL settings of Gateway.kafka
+func newKafkaSourceClient() (*client.Client, error) {
+	if len(kafkaBrokers) == 0 {
+		return nil, errors.New("no kafka brokers are configured")
+	}
+	conf := client.Config{
+		ClientID:    "rudder-gateway",
+		DialTimeout: kafkaDialTimeout,
+	}
+	if config.GetBool("Gateway.kafka.tls.enabled", false) {
+		conf.TLS = &client.TLS{
+			InsecureSkipVerify: config.GetBool("Gateway.kafka.tls.insecureSkipVerify", false),
+		}
+		var err error
+		if conf.TLS.CACertificate, err = readKafkaTLSFile("Gateway.kafka.tls.caCertificateFile"); err != nil {
+			return nil, err
+		}
+		if conf.TLS.Cert, err = readKafkaTLSFile("Gateway.kafka.tls.certificateFile"); err != nil {
+			return nil, err
+		}
+		if conf.TLS.Key, err = readKafkaTLSFile("Gateway.kafka.tls.keyFile"); err != nil {
+			return nil, err
+		}
+		if len(conf.TLS.CACertificate) == 0 {
+			conf.TLS.WithSystemCertPool = true
+		}
+	}
+	if config.GetBool("Gateway.kafka.sasl.enabled", false) {
+		conf.SASL = &client.SASL{
+			Username: config.GetString("Gateway.kafka.sasl.username", ""),
+			Password: config.GetString("Gateway.kafka.sasl.password", ""),
+		}
+		var err error
+		conf.SASL.ScramHashGen, err = client.ScramHashGeneratorFromString(config.GetString("Gateway.kafka.sasl.mechanism", "plain"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SASL mechanism: %w", err)
+		}
+	}
+	return client.New("tcp", kafkaBrokers, conf)
+}
+
+func readKafkaTLSFile(key string) ([]byte, error) {
+	file := config.GetString(key, "")
+	if file == "" {
+		return nil, nil
+	}
+	return os.ReadFile(file)
+}
+
+// kafkaLogger reports the errors of kafka consumers to the gateway logger
+type kafkaLogger struct {
+	gateway *HandleT
+}
+
+func (l kafkaLogger) Printf(format string, args ...interface{}) {
+	l.gateway.logger.Errorf("[Kafka] "+format, args...)
+}
@@ -0,0 +1,166 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/tidwall/gjson"
+
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/services/rsources"
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/services/streammanager/kafka/client"
+)
+
+type fakeKafkaConsumer struct {
+	records chan client.Message
+
+	mu        sync.Mutex
+	committed []int64
+}
+
+func (c *fakeKafkaConsumer) Fetch(ctx context.Context) (client.Message, error) {
+	select {
+	case msg := <-c.records:
+		return msg, nil
+	case <-ctx.Done():
+		return client.Message{}, ctx.Err()
+	}
+}
+
+func (c *fakeKafkaConsumer) Commit(_ context.Context, msgs ...client.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, msg := range msgs {
+		c.committed = append(c.committed, msg.Offset)
+	}
+	return nil
+}
+
+func (c *fakeKafkaConsumer) committedOffsets() []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]int64{}, c.committed...)
+}
+
+var _ = Describe("Gateway Kafka source", func() {
+	initGW()
+
+	var (
+		c        *testContext
+		gateway  *HandleT
+		consumer *fakeKafkaConsumer
+		cancel   context.CancelFunc
+		stopped  chan error
+	)
+
+	BeforeEach(func() {
+		c = &testContext{}
+		c.Setup()
+		c.initializeAppFeatures()
+		stats.Setup()
+		SetEnableRateLimit(false)
+		SetEnableEventSchemasFeature(false)
+		kafkaCommitInterval = 10 * time.Millisecond
+
+		gateway = &HandleT{}
+		err := gateway.Setup(c.mockApp, c.mockBackendConfig, c.mockJobsDB, c.mockRateLimiter, c.mockVersionHandler, rsources.NewNoOpService())
+		Expect(err).To(BeNil())
+
+		consumer = &fakeKafkaConsumer{records: make(chan client.Message)}
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		stopped = make(chan error, 1)
+		go func() {
+			stopped <- gateway.consumeKafkaTopic(ctx, consumer, "events", WriteKeyEnabled)
+		}()
+	})
+
+	AfterEach(func() {
+		cancel()
+		Eventually(stopped).Should(Receive(BeNil()))
+		SetEnableRateLimit(false)
+		c.Finish()
+	})
+
+	produce := func(offset int64, value string, headers ...client.MessageHeader) {
+		consumer.records <- client.Message{Topic: "events", Offset: offset, Value: []byte(value), Headers: headers}
+	}
+
+	It("should store records and commit their offsets, dropping the invalid ones", func() {
+		c.mockJobsDB.EXPECT().WithStoreSafeTx(gomock.Any()).AnyTimes().Do(func(f func(tx jobsdb.StoreSafeTx) error) {
+			_ = f(jobsdb.EmptyStoreSafeTx())
+		}).Return(nil)
+		c.mockJobsDB.EXPECT().StoreWithRetryEachInTx(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx jobsdb.StoreSafeTx, jobs []*jobsdb.JobT) map[uuid.UUID]string {
+				for _, job := range jobs {
+					Expect(gjson.GetBytes(job.EventPayload, "writeKey").String()).To(Equal(WriteKeyEnabled))
+					Expect(gjson.GetBytes(job.EventPayload, "batch.0.type").String()).To(Equal("track"))
+				}
+				return jobsToEmptyErrors(ctx, tx, jobs)
+			}).MinTimes(1)
+
+		produce(1, `{"batch":[{"type":"track","userId":"dummyId"}]}`)
+		produce(2, `{"userId":"dummyId"}`, client.MessageHeader{Key: kafkaRequestTypeHeader, Value: []byte("track")})
+		produce(3, `not-a-valid-json`)
+		produce(4, `{"userId":"dummyId"}`, client.MessageHeader{Key: kafkaRequestTypeHeader, Value: []byte("unknown")})
+
+		Eventually(consumer.committedOffsets, testTimeout).Should(Equal([]int64{1, 2, 3, 4}))
+	})
+
+	It("should retry records rejected for a retryable reason before committing their offsets", func() {
+		SetEnableRateLimit(true)
+		c.mockBackendConfig.EXPECT().GetWorkspaceIDForWriteKey(WriteKeyEnabled).Return("workspace").AnyTimes()
+		gomock.InOrder(
+			c.mockRateLimiter.EXPECT().LimitReached("workspace").Return(true).Times(1),
+			c.mockRateLimiter.EXPECT().LimitReached("workspace").Return(false).Times(1),
+		)
+		c.mockJobsDB.EXPECT().WithStoreSafeTx(gomock.Any()).Times(1).Do(func(f func(tx jobsdb.StoreSafeTx) error) {
+			_ = f(jobsdb.EmptyStoreSafeTx())
+		}).Return(nil)
+		c.mockJobsDB.EXPECT().StoreWithRetryEachInTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(jobsToEmptyErrors).Times(1)
+
+		produce(1, `{"batch":[{"type":"track","userId":"dummyId"}]}`)
+
+		Consistently(consumer.committedOffsets, 200*time.Millisecond).Should(BeEmpty())
+		Eventually(consumer.committedOffsets, testTimeout).Should(Equal([]int64{1}))
+	})
+
+	It("should ingest the records of a partition in order, even when retried", func() {
+		SetEnableRateLimit(true)
+		c.mockBackendConfig.EXPECT().GetWorkspaceIDForWriteKey(WriteKeyEnabled).Return("workspace").AnyTimes()
+		gomock.InOrder(
+			c.mockRateLimiter.EXPECT().LimitReached("workspace").Return(true).Times(1),
+			c.mockRateLimiter.EXPECT().LimitReached("workspace").Return(false).AnyTimes(),
+		)
+		c.mockJobsDB.EXPECT().WithStoreSafeTx(gomock.Any()).AnyTimes().Do(func(f func(tx jobsdb.StoreSafeTx) error) {
+			_ = f(jobsdb.EmptyStoreSafeTx())
+		}).Return(nil)
+		var (
+			mu      sync.Mutex
+			userIDs []string
+		)
+		c.mockJobsDB.EXPECT().StoreWithRetryEachInTx(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx jobsdb.StoreSafeTx, jobs []*jobsdb.JobT) map[uuid.UUID]string {
+				mu.Lock()
+				defer mu.Unlock()
+				for _, job := range jobs {
+					userIDs = append(userIDs, gjson.GetBytes(job.EventPayload, "batch.0.userId").String())
+				}
+				return jobsToEmptyErrors(ctx, tx, jobs)
+			}).MinTimes(1)
+
+		produce(1, `{"batch":[{"type":"track","userId":"first"}]}`)
+		produce(2, `{"batch":[{"type":"track","userId":"second"}]}`)
+
+		Eventually(consumer.committedOffsets, testTimeout).Should(Equal([]int64{1, 2}))
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(userIDs).To(Equal([]string{"first", "second"}))
+	})
+})
@@ -1,8 +1,10 @@
 package response
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 const (
@@ -52,6 +54,14 @@ const (
 	ErrorInParseMultiform = "Error during parsing multiform"
 	// NotRudderEvent = Event is not a Valid Rudder Event
 	NotRudderEvent = "Event is not a valid rudder event"
+	// InvalidRequestType - Request type is not one of the supported ones
+	InvalidRequestType = "Invalid request type"
+	// SchemaViolation - Request contains events which don't conform to the json schemas of the source
+	SchemaViolation = "Request contains events violating the source's schemas"
+	// IdempotencyKeyInUse - A request with the same idempotency key is being processed
+	IdempotencyKeyInUse = "A request with the same idempotency key is being processed"
+	// IdempotencyKeyCheckFailed - Failed to check whether the idempotency key was already used
+	IdempotencyKeyCheckFailed = "Failed to check idempotency key"
 
 	transPixelResponse = "\x47\x49\x46\x38\x39\x61\x01\x00\x01\x00\x80\x00\x00\x00\x00\x00\x00\x00\x00\x21\xF9\x04" +
 		"\x01\x00\x00\x00\x00\x2C\x00\x00\x00\x00\x01\x00\x01\x00\x00\x02\x02\x44\x01\x00\x3B"
@@ -82,6 +92,10 @@ var statusMap = map[string]status{
 	ErrorInParseForm:                               {message: ErrorInParseForm, code: http.StatusBadRequest},
 	ErrorInParseMultiform:                          {message: ErrorInParseMultiform, code: http.StatusBadRequest},
 	NotRudderEvent:                                 {message: NotRudderEvent, code: http.StatusBadRequest},
+	InvalidRequestType:                             {message: InvalidRequestType, code: http.StatusBadRequest},
+	SchemaViolation:                                {message: SchemaViolation, code: http.StatusBadRequest},
+	IdempotencyKeyInUse:                            {message: IdempotencyKeyInUse, code: http.StatusConflict},
+	IdempotencyKeyCheckFailed:                      {message: IdempotencyKeyCheckFailed, code: http.StatusInternalServerError},
 }
 
 // status holds the gateway response status message and code
@@ -105,9 +119,38 @@ func GetErrorStatusCode(key string) int {
 	if status, ok := statusMap[key]; ok {
 		return status.code
 	}
+	// structured responses carry the status message in their msg field
+	if strings.HasPrefix(key, "{") {
+		var structured struct {
+			Msg string `json:"msg"`
+		}
+		if err := json.Unmarshal([]byte(key), &structured); err == nil {
+			if status, ok := statusMap[structured.Msg]; ok {
+				return status.code
+			}
+		}
+	}
 	return http.StatusInternalServerError
 }
 
+// Violation is a path of an event which doesn't conform to its json schema, along with the reason
+type Violation struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// MakeSchemaViolationResponse returns the structured SchemaViolation response, listing the violated paths of the request
+func MakeSchemaViolationResponse(violations []Violation) string {
+	res, err := json.Marshal(struct {
+		Msg        string      `json:"msg"`
+		Violations []Violation `json:"violations"`
+	}{Msg: SchemaViolation, Violations: violations})
+	if err != nil {
+		return SchemaViolation
+	}
+	return string(res)
+}
+
 func MakeResponse(msg string) string {
 	return fmt.Sprintf(`{"msg": %q}`, msg)
 }
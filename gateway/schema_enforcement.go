@@ -0,0 +1,232 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/rudderlabs/rudder-server/config"
+	event_schema "github.com/rudderlabs/rudder-server/event-schema"
+	"github.com/rudderlabs/rudder-server/gateway/response"
+	"github.com/rudderlabs/rudder-server/jobsdb"
+)
+
+const (
+	schemaEnforcementDisabled   = "disabled"
+	schemaEnforcementReject     = "reject"
+	schemaEnforcementQuarantine = "quarantine"
+
+	// schemaFileCheckInterval is the minimum interval between two checks of a source's schemas file for changes
+	schemaFileCheckInterval = 10 * time.Second
+)
+
+// schemaEnforcedFields are the fields of the events that are validated against the json schema of their event model, per
+// event type. They are the same fields event_schema generates json schemas from.
+var schemaEnforcedFields = map[string]string{
+	"track":    "properties",
+	"screen":   "properties",
+	"page":     "properties",
+	"identify": "traits",
+	"group":    "traits",
+}
+
+// IsSchemaEnforcementEnabled is true if the gateway can enforce the json schemas of sources, in which case a quarantine
+// jobsdb should be provided to the gateway using SetQuarantineDB
+func IsSchemaEnforcementEnabled() bool {
+	return enableSchemaEnforcement
+}
+
+// getSchemaEnforcementModeForSource returns how events of the source violating its json schemas are handled (disabled, reject or quarantine).
+// It can be overridden per source using Gateway.<sourceID>.schemaEnforcement.mode
+func getSchemaEnforcementModeForSource(sourceID string) string {
+	if config.IsSet("Gateway." + sourceID + ".schemaEnforcement.mode") {
+		return config.GetString("Gateway."+sourceID+".schemaEnforcement.mode", schemaEnforcementDisabled)
+	}
+	return schemaEnforcementMode
+}
+
+// SetQuarantineDB sets the jobsdb where requests violating the json schemas of sources in quarantine mode are stored
+func (gateway *HandleT) SetQuarantineDB(quarantineDB jobsdb.JobsDB) {
+	gateway.quarantineDB = quarantineDB
+}
+
+// quarantinedRequestT is a request violating the json schemas of its source, waiting to be stored in the quarantine jobsdb
+type quarantinedRequestT struct {
+	req          *webRequestT
+	job          *jobsdb.JobT
+	errorMessage string
+}
+
+/*
+enforceSchemas validates the events of a request's batch against the json schemas of the source, returning the violations found.
+
+Events are matched to json schemas by their event type & identifier (the event name for track events), the same way
+event_schema builds event models. Events without a json schema are not validated.
+*/
+func (gateway *HandleT) enforceSchemas(sourceID string, body []byte) (mode string, violations []response.Violation) {
+	if gateway.schemaStore == nil {
+		return schemaEnforcementDisabled, nil
+	}
+	mode = getSchemaEnforcementModeForSource(sourceID)
+	if mode != schemaEnforcementReject && mode != schemaEnforcementQuarantine {
+		return schemaEnforcementDisabled, nil
+	}
+	schemas := gateway.schemaStore.get(sourceID)
+	if len(schemas) == 0 {
+		return mode, nil
+	}
+	gjson.GetBytes(body, "batch").ForEach(func(index, event gjson.Result) bool {
+		eventType := event.Get("type").String()
+		field, ok := schemaEnforcedFields[eventType]
+		if !ok {
+			return true
+		}
+		var eventIdentifier string
+		if eventType == "track" {
+			eventIdentifier = event.Get("event").String()
+		}
+		schema, ok := schemas[schemaKey(eventType, eventIdentifier)]
+		if !ok {
+			return true
+		}
+		path := fmt.Sprintf("batch.%d.%s", index.Int(), field)
+		document := "{}"
+		if value := event.Get(field); value.Exists() {
+			document = value.Raw
+		}
+		result, err := schema.Validate(gojsonschema.NewStringLoader(document))
+		if err != nil {
+			violations = append(violations, response.Violation{Path: path, Error: err.Error()})
+			return true
+		}
+		for _, resultErr := range result.Errors() {
+			violations = append(violations, response.Violation{Path: violationPath(path, resultErr), Error: resultErr.Description()})
+		}
+		return true
+	})
+	return mode, violations
+}
+
+// storeQuarantinedRequests stores the quarantined requests in the quarantine jobsdb and responds to them
+func (gateway *HandleT) storeQuarantinedRequests(quarantined []quarantinedRequestT) {
+	if len(quarantined) == 0 {
+		return
+	}
+	jobs := make([]*jobsdb.JobT, len(quarantined))
+	for i := range quarantined {
+		jobs[i] = quarantined[i].job
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), WriteTimeout)
+	defer cancel()
+	if err := gateway.quarantineDB.Store(ctx, jobs); err != nil {
+		// requests are rejected anyway, so there is nothing more to do than letting it be known
+		gateway.logger.Errorf("Storing %d requests into quarantine db failed with error: %v", len(jobs), err)
+	}
+	for _, q := range quarantined {
+		q.req.done <- q.errorMessage
+	}
+}
+
+// violationPath returns the path of the event where a json schema validation error occurred
+func violationPath(path string, resultErr gojsonschema.ResultError) string {
+	if field := resultErr.Field(); field != gojsonschema.STRING_ROOT_SCHEMA_PROPERTY {
+		path += "." + field
+	}
+	// errors of missing & additional properties are reported on their parent
+	if property, ok := resultErr.Details()["property"].(string); ok {
+		path += "." + property
+	}
+	return path
+}
+
+func schemaKey(eventType, eventIdentifier string) string {
+	return eventType + DELIMITER + eventIdentifier
+}
+
+// sourceSchemasT are the compiled json schemas of a source, keyed by schemaKey
+type sourceSchemasT struct {
+	schemas   map[string]*gojsonschema.Schema
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+/*
+schemaStoreT provides the json schemas of sources, loaded from <dir>/<sourceID>.json files.
+
+The files contain the json schemas of the event models of a source, in the format returned by the
+/schemas/event-models/json-schemas endpoint, and are reloaded whenever they change.
+*/
+type schemaStoreT struct {
+	dir     string
+	mu      sync.Mutex
+	sources map[string]*sourceSchemasT
+}
+
+func newSchemaStore(dir string) *schemaStoreT {
+	return &schemaStoreT{dir: dir, sources: make(map[string]*sourceSchemasT)}
+}
+
+// get returns the json schemas of the source, if any. In case its schemas file cannot be loaded, the last loaded schemas are returned.
+func (s *schemaStoreT) get(sourceID string) map[string]*gojsonschema.Schema {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cached, ok := s.sources[sourceID]
+	if ok && time.Since(cached.checkedAt) < schemaFileCheckInterval {
+		return cached.schemas
+	}
+	if !ok {
+		cached = &sourceSchemasT{}
+		s.sources[sourceID] = cached
+	}
+	cached.checkedAt = time.Now()
+
+	path := filepath.Join(s.dir, sourceID+".json")
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		cached.schemas, cached.modTime = nil, time.Time{}
+		return nil
+	}
+	if err != nil {
+		pkgLogger.Errorf("Failed to stat schemas file %q of source %s: %v", path, sourceID, err)
+		return cached.schemas
+	}
+	if info.ModTime().Equal(cached.modTime) {
+		return cached.schemas
+	}
+	schemas, err := loadSchemas(path)
+	if err != nil {
+		pkgLogger.Errorf("Failed to load schemas file %q of source %s: %v", path, sourceID, err)
+		return cached.schemas
+	}
+	cached.schemas, cached.modTime = schemas, info.ModTime()
+	return schemas
+}
+
+func loadSchemas(path string) (map[string]*gojsonschema.Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var jsonSchemas []event_schema.JsonSchemaT
+	if err := json.Unmarshal(data, &jsonSchemas); err != nil {
+		return nil, err
+	}
+	schemas := make(map[string]*gojsonschema.Schema, len(jsonSchemas))
+	for _, jsonSchema := range jsonSchemas {
+		schema, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(jsonSchema.Schema))
+		if err != nil {
+			return nil, fmt.Errorf("invalid json schema for %s event %q: %w", jsonSchema.SchemaType, jsonSchema.SchemaTIdentifier, err)
+		}
+		schemas[schemaKey(jsonSchema.SchemaType, jsonSchema.SchemaTIdentifier)] = schema
+	}
+	return schemas, nil
+}
@@ -0,0 +1,1498 @@
+package jobsdb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/dgraph-io/badger/v2/options"
+	"github.com/gofrs/uuid"
+	"github.com/tidwall/gjson"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+/*
+EmbeddedHandleT is an implementation of JobsDB (and MultiTenantJobsDB) backed by an embedded
+Badger key-value store instead of Postgres. It is meant for deployments where shipping
+a Postgres server is not an option, e.g. edge gateways.
+
+Data is laid out in the same way as in HandleT: jobs are appended to the latest dataset and
+a new dataset is added once the latest one grows beyond maxDSSize. Statuses of a job live in
+the same dataset as the job itself. Datasets which have been (mostly) processed are migrated by
+copying their pending jobs into the next dataset and dropping them afterwards. Dataset operations
+are recorded in a journal, so that they can be completed after a crash.
+
+Keys of a single jobsdb instance are prefixed with its table prefix:
+
+	<prefix>/ds/<idx>                        -> dataset marker
+	<prefix>/job/<idx>/<jobID>               -> JobT
+	<prefix>/status/<idx>/<jobID>/<statusID> -> JobStatusT (full status history)
+	<prefix>/latest/<idx>/<jobID>            -> JobStatusT (latest status of the job)
+	<prefix>/journal/<opID>                  -> journal entry
+
+There is no SQL transaction backing an EmbeddedHandleT, so WithTx invokes its function with a nil *sql.Tx.
+Store-safe and update-safe transactions are backed by a Badger transaction instead, thus callers must not
+use StoreSafeTx.Tx() or UpdateSafeTx.Tx() for issuing their own SQL statements against an embedded jobsdb.
+For this reason the server refuses to use the embedded backend along with reporting and failed keys, which are
+written in the jobsdb transactions, and tracks no rudder sources job runs (see apphandlers).
+Transactions of other jobsdb instances are rejected, instead of writing outside of them.
+*/
+type EmbeddedHandleT struct {
+	db          *badger.DB
+	path        string
+	tablePrefix string
+	ownerType   OwnerType
+	clearAll    bool
+	logger      logger.LoggerI
+	stats       stats.Stats
+
+	expiredJobsHandler ExpiredJobsHandler
+
+	// dsListLock protects datasets; write operations on jobs and statuses are holding a read lock,
+	// whereas dataset operations (add, migrate, drop) are holding the write lock.
+	dsListLock sync.RWMutex
+	datasets   []*embeddedDataSet
+	// countersLock protects the counters of datasets, which are updated while holding the dataset list read lock
+	countersLock sync.Mutex
+
+	jobSeq     *badger.Sequence
+	statusSeq  *badger.Sequence
+	journalSeq *badger.Sequence
+
+	maxDSSize                  int
+	jobDoneMigrateThres        float64
+	addNewDSLoopSleepDuration  time.Duration
+	migrateDSLoopSleepDuration time.Duration
+
+	// TriggerAddNewDS, TriggerMigrateDS are useful for triggering dataset operations from tests.
+	TriggerAddNewDS  func() <-chan time.Time
+	TriggerMigrateDS func() <-chan time.Time
+
+	statDSCount stats.RudderStats
+
+	lifecycle struct {
+		mu               sync.Mutex
+		started          bool
+		backgroundCancel context.CancelFunc
+		backgroundGroup  *errgroup.Group
+	}
+}
+
+var (
+	_ JobsDB            = &EmbeddedHandleT{}
+	_ MultiTenantJobsDB = &EmbeddedHandleT{}
+	_ ReadonlyJobsDB    = &EmbeddedHandleT{}
+)
+
+// embeddedDataSet keeps the in-memory state of a dataset.
+type embeddedDataSet struct {
+	index    int
+	minJobID int64
+	maxJobID int64
+	jobCount int
+}
+
+func (ds *embeddedDataSet) String() string {
+	return strconv.Itoa(ds.index)
+}
+
+// embeddedJournalEntry is the value stored for every journal key
+type embeddedJournalEntry struct {
+	JournalEntryT
+	Owner OwnerType `json:"owner"`
+}
+
+// errEmbeddedAdminQuery is returned by the admin queries which the embedded jobsdb doesn't support
+var errEmbeddedAdminQuery = errors.New("admin query not supported by the embedded jobsdb")
+
+// errEmbeddedForeignTx is returned when an embedded jobsdb is given a transaction which it didn't start itself
+var errEmbeddedForeignTx = errors.New("transaction doesn't belong to this embedded jobsdb")
+
+// embeddedTx is the store-safe and update-safe transaction of an embedded jobsdb
+type embeddedTx struct {
+	txn      *badger.Txn
+	identity string
+}
+
+func (*embeddedTx) Tx() *sql.Tx {
+	return nil
+}
+
+func (r *embeddedTx) storeSafeTxIdentifier() string {
+	return r.identity
+}
+
+func (r *embeddedTx) updateSafeTxSealIdentifier() string {
+	return r.identity
+}
+
+type EmbeddedOptsFunc func(jd *EmbeddedHandleT)
+
+// WithEmbeddedOwnerType sets the owner type of the embedded jobsdb, defaults to ReadWrite
+func WithEmbeddedOwnerType(ownerType OwnerType) EmbeddedOptsFunc {
+	return func(jd *EmbeddedHandleT) {
+		jd.ownerType = ownerType
+	}
+}
+
+// WithEmbeddedClearDB, if set to true it will remove all existing data of the jobsdb
+func WithEmbeddedClearDB(clearDB bool) EmbeddedOptsFunc {
+	return func(jd *EmbeddedHandleT) {
+		jd.clearAll = clearDB
+	}
+}
+
+// WithEmbeddedMaxDSSize overrides the maximum number of jobs a dataset can hold before a new dataset is added
+func WithEmbeddedMaxDSSize(maxDSSize int) EmbeddedOptsFunc {
+	return func(jd *EmbeddedHandleT) {
+		jd.maxDSSize = maxDSSize
+	}
+}
+
+// WithEmbeddedStats overrides the stats instance used by the embedded jobsdb
+func WithEmbeddedStats(s stats.Stats) EmbeddedOptsFunc {
+	return func(jd *EmbeddedHandleT) {
+		jd.stats = s
+	}
+}
+
+// WithEmbeddedExpiredJobsHandler sets a handler for jobs aborted due to their expiry
+func WithEmbeddedExpiredJobsHandler(handler ExpiredJobsHandler) EmbeddedOptsFunc {
+	return func(jd *EmbeddedHandleT) {
+		jd.expiredJobsHandler = handler
+	}
+}
+
+// DefaultEmbeddedPath returns the default directory of the embedded jobsdb store
+func DefaultEmbeddedPath() string {
+	tmpDirPath, err := misc.CreateTMPDIR()
+	if err != nil {
+		panic(err)
+	}
+	return config.GetString("JobsDB.embedded.path", tmpDirPath+"/jobsdb")
+}
+
+// NewEmbedded opens (or creates) an embedded jobsdb under path. Every jobsdb instance needs a path of its own,
+// as the store can only be opened once.
+func NewEmbedded(tablePrefix, path string, opts ...EmbeddedOptsFunc) (*EmbeddedHandleT, error) {
+	if tablePrefix == "" {
+		return nil, errors.New("tablePrefix received is empty")
+	}
+	jd := &EmbeddedHandleT{
+		path:        path,
+		tablePrefix: tablePrefix,
+		ownerType:   ReadWrite,
+		logger:      logger.NewLogger().Child("jobsdb").Child(tablePrefix),
+		stats:       stats.DefaultStats,
+
+		maxDSSize:                  config.GetInt("JobsDB.maxDSSize", 100000),
+		jobDoneMigrateThres:        config.GetFloat64("JobsDB.jobDoneMigrateThres", 0.8),
+		addNewDSLoopSleepDuration:  config.GetDuration("JobsDB.addNewDSLoopSleepDuration", 5, time.Second),
+		migrateDSLoopSleepDuration: config.GetDuration("JobsDB.migrateDSLoopSleepDuration", 30, time.Second),
+	}
+	for _, fn := range opts {
+		fn(jd)
+	}
+	if jd.TriggerAddNewDS == nil {
+		jd.TriggerAddNewDS = func() <-chan time.Time {
+			return time.After(jd.addNewDSLoopSleepDuration)
+		}
+	}
+	if jd.TriggerMigrateDS == nil {
+		jd.TriggerMigrateDS = func() <-chan time.Time {
+			return time.After(jd.migrateDSLoopSleepDuration)
+		}
+	}
+	if jd.stats != nil {
+		jd.statDSCount = jd.stats.NewTaggedStat("jobsdb.tables_count", stats.GaugeType, stats.Tags{"customVal": jd.tablePrefix})
+	}
+
+	if err := jd.open(); err != nil {
+		return nil, err
+	}
+	return jd, nil
+}
+
+func (jd *EmbeddedHandleT) open() error {
+	var err error
+	opts := badger.
+		DefaultOptions(jd.path).
+		WithTruncate(true).
+		WithLogger(embeddedLogger{jd.logger}).
+		WithCompression(options.Snappy)
+	if jd.db, err = badger.Open(opts); err != nil {
+		return fmt.Errorf("opening embedded jobsdb at %q: %w", jd.path, err)
+	}
+	if jd.clearAll {
+		if err = jd.db.DropPrefix([]byte(jd.key() + "/")); err != nil {
+			return fmt.Errorf("clearing embedded jobsdb: %w", err)
+		}
+	}
+	if jd.jobSeq, err = jd.db.GetSequence([]byte(jd.key("seq", "job")), 1000); err != nil {
+		return err
+	}
+	if jd.statusSeq, err = jd.db.GetSequence([]byte(jd.key("seq", "status")), 1000); err != nil {
+		return err
+	}
+	if jd.journalSeq, err = jd.db.GetSequence([]byte(jd.key("seq", "journal")), 10); err != nil {
+		return err
+	}
+
+	jd.dsListLock.Lock()
+	defer jd.dsListLock.Unlock()
+	if err = jd.recoverFromJournal(); err != nil {
+		return err
+	}
+	if err = jd.loadDSList(); err != nil {
+		return err
+	}
+	if len(jd.datasets) == 0 {
+		return jd.addNewDS()
+	}
+	return nil
+}
+
+// Start starts the housekeeping goroutines (adding new datasets and migrating old ones).
+func (jd *EmbeddedHandleT) Start() error {
+	jd.lifecycle.mu.Lock()
+	defer jd.lifecycle.mu.Unlock()
+	if jd.lifecycle.started {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	g, ctx := errgroup.WithContext(ctx)
+	jd.lifecycle.backgroundCancel = cancel
+	jd.lifecycle.backgroundGroup = g
+
+	if jd.ownerType == Write || jd.ownerType == ReadWrite {
+		g.Go(misc.WithBugsnag(func() error {
+			jd.addNewDSLoop(ctx)
+			return nil
+		}))
+	}
+	if jd.ownerType == Read || jd.ownerType == ReadWrite {
+		g.Go(misc.WithBugsnag(func() error {
+			jd.migrateDSLoop(ctx)
+			return nil
+		}))
+	}
+	jd.lifecycle.started = true
+	return nil
+}
+
+// Stop stops the background goroutines and waits until they finish.
+func (jd *EmbeddedHandleT) Stop() {
+	jd.lifecycle.mu.Lock()
+	defer jd.lifecycle.mu.Unlock()
+	if jd.lifecycle.started {
+		jd.lifecycle.backgroundCancel()
+		_ = jd.lifecycle.backgroundGroup.Wait()
+		jd.lifecycle.started = false
+	}
+}
+
+// TearDown stops the background goroutines and closes the store.
+func (jd *EmbeddedHandleT) TearDown() {
+	jd.Stop()
+	jd.Close()
+}
+
+// Close releases the sequences and closes the store.
+func (jd *EmbeddedHandleT) Close() {
+	for _, seq := range []*badger.Sequence{jd.jobSeq, jd.statusSeq, jd.journalSeq} {
+		if seq != nil {
+			_ = seq.Release()
+		}
+	}
+	_ = jd.db.Close()
+}
+
+func (jd *EmbeddedHandleT) Identifier() string {
+	return jd.tablePrefix
+}
+
+func (jd *EmbeddedHandleT) GetTablePrefix() string {
+	return jd.tablePrefix
+}
+
+func (jd *EmbeddedHandleT) Ping() error {
+	if jd.db.IsClosed() {
+		return errors.New("embedded jobsdb is closed")
+	}
+	return nil
+}
+
+func (jd *EmbeddedHandleT) Status() interface{} {
+	jd.dsListLock.RLock()
+	defer jd.dsListLock.RUnlock()
+	jd.countersLock.Lock()
+	defer jd.countersLock.Unlock()
+	dsList := make([]map[string]interface{}, 0, len(jd.datasets))
+	for _, ds := range jd.datasets {
+		dsList = append(dsList, map[string]interface{}{
+			"index":      ds.index,
+			"min-job-id": ds.minJobID,
+			"max-job-id": ds.maxJobID,
+			"jobs":       ds.jobCount,
+		})
+	}
+	return map[string]interface{}{
+		"backend":      "embedded",
+		"path":         jd.path,
+		"dataset-list": dsList,
+	}
+}
+
+/* Commands */
+
+// WithTx invokes f with a nil *sql.Tx, since there is no SQL database backing an embedded jobsdb.
+func (*EmbeddedHandleT) WithTx(f func(tx *sql.Tx) error) error {
+	return f(nil)
+}
+
+func (jd *EmbeddedHandleT) WithStoreSafeTx(f func(tx StoreSafeTx) error) error {
+	return jd.withEmbeddedTx(func(tx *embeddedTx) error {
+		return f(tx)
+	})
+}
+
+func (jd *EmbeddedHandleT) WithUpdateSafeTx(f func(tx UpdateSafeTx) error) error {
+	return jd.withEmbeddedTx(func(tx *embeddedTx) error {
+		return f(tx)
+	})
+}
+
+// withEmbeddedTx starts a new badger transaction, holding the dataset list read lock for its whole duration.
+func (jd *EmbeddedHandleT) withEmbeddedTx(f func(tx *embeddedTx) error) error {
+	jd.dsListLock.RLock()
+	defer jd.dsListLock.RUnlock()
+	txn := jd.db.NewTransaction(true)
+	defer txn.Discard()
+	if err := f(&embeddedTx{txn: txn, identity: jd.Identifier()}); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+// txnOf returns the badger transaction of tx, if it is an embedded transaction of this jobsdb.
+func (jd *EmbeddedHandleT) txnOf(tx interface{}) (*badger.Txn, bool) {
+	etx, ok := tx.(*embeddedTx)
+	if !ok || etx.identity != jd.Identifier() || etx.txn == nil {
+		return nil, false
+	}
+	return etx.txn, true
+}
+
+func (jd *EmbeddedHandleT) Store(ctx context.Context, jobList []*JobT) error {
+	return jd.WithStoreSafeTx(func(tx StoreSafeTx) error {
+		return jd.StoreInTx(ctx, tx, jobList)
+	})
+}
+
+func (jd *EmbeddedHandleT) StoreInTx(ctx context.Context, tx StoreSafeTx, jobList []*JobT) error {
+	txn, ok := jd.txnOf(tx)
+	if !ok {
+		return errEmbeddedForeignTx
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ds := jd.datasets[len(jd.datasets)-1]
+	ids := make([]int64, 0, len(jobList))
+	for _, job := range jobList {
+		id, err := jd.storeJob(txn, ds, job)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	jd.trackStoredJobs(ds, ids)
+	return nil
+}
+
+func (jd *EmbeddedHandleT) StoreWithRetryEach(ctx context.Context, jobList []*JobT) map[uuid.UUID]string {
+	var res map[uuid.UUID]string
+	_ = jd.WithStoreSafeTx(func(tx StoreSafeTx) error {
+		res = jd.StoreWithRetryEachInTx(ctx, tx, jobList)
+		return nil
+	})
+	return res
+}
+
+// StoreWithRetryEachInTx stores every job on its own, so that a single invalid job doesn't fail the whole batch.
+// Jobs with a payload which is not a valid json are rejected, same as they would be rejected by postgres.
+func (jd *EmbeddedHandleT) StoreWithRetryEachInTx(ctx context.Context, tx StoreSafeTx, jobList []*JobT) map[uuid.UUID]string {
+	errorMessagesMap := make(map[uuid.UUID]string)
+	txn, ok := jd.txnOf(tx)
+	if !ok {
+		for _, job := range jobList {
+			errorMessagesMap[job.UUID] = errEmbeddedForeignTx.Error()
+		}
+		return errorMessagesMap
+	}
+	ds := jd.datasets[len(jd.datasets)-1]
+	ids := make([]int64, 0, len(jobList))
+	for _, job := range jobList {
+		id, err := jd.storeJob(txn, ds, job)
+		if err != nil {
+			errorMessagesMap[job.UUID] = err.Error()
+			continue
+		}
+		ids = append(ids, id)
+	}
+	jd.trackStoredJobs(ds, ids)
+	return errorMessagesMap
+}
+
+func (jd *EmbeddedHandleT) storeJob(txn *badger.Txn, ds *embeddedDataSet, job *JobT) (int64, error) {
+	if !json.Valid(job.EventPayload) {
+		return 0, fmt.Errorf("invalid json payload for job with uuid %s", job.UUID)
+	}
+	id, err := jd.jobSeq.Next()
+	if err != nil {
+		return 0, err
+	}
+	// sequences start from zero, whereas job ids are expected to be positive
+	job.JobID = int64(id) + 1
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = time.Now()
+	}
+	if job.ExpireAt.IsZero() {
+		job.ExpireAt = job.CreatedAt
+	}
+	job.sanitizeJson()
+	stored := *job
+	stored.LastJobStatus = JobStatusT{}
+	stored.PayloadSize = int64(len(job.EventPayload))
+	value, err := json.Marshal(&stored)
+	if err != nil {
+		return 0, err
+	}
+	return job.JobID, txn.Set([]byte(jd.jobKey(ds.index, job.JobID)), value)
+}
+
+// trackStoredJobs updates the in-memory counters of ds after storing jobs in it.
+// Counters are updated optimistically, a rolled back transaction only makes the next dataset rotation happen a bit earlier.
+func (jd *EmbeddedHandleT) trackStoredJobs(ds *embeddedDataSet, ids []int64) {
+	jd.countersLock.Lock()
+	defer jd.countersLock.Unlock()
+	for _, id := range ids {
+		if ds.minJobID == 0 || id < ds.minJobID {
+			ds.minJobID = id
+		}
+		if id > ds.maxJobID {
+			ds.maxJobID = id
+		}
+	}
+	ds.jobCount += len(ids)
+}
+
+func (jd *EmbeddedHandleT) UpdateJobStatus(ctx context.Context, statusList []*JobStatusT, customValFilters []string, parameterFilters []ParameterFilterT) error {
+	return jd.WithUpdateSafeTx(func(tx UpdateSafeTx) error {
+		return jd.UpdateJobStatusInTx(ctx, tx, statusList, customValFilters, parameterFilters)
+	})
+}
+
+func (jd *EmbeddedHandleT) UpdateJobStatusInTx(ctx context.Context, tx UpdateSafeTx, statusList []*JobStatusT, customValFilters []string, parameterFilters []ParameterFilterT) error {
+	txn, ok := jd.txnOf(tx)
+	if !ok {
+		return errEmbeddedForeignTx
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, status := range statusList {
+		ds := jd.dsOf(status.JobID)
+		if len(customValFilters) > 0 || len(parameterFilters) > 0 {
+			if err := jd.checkStatusFilters(txn, ds, status.JobID, customValFilters, parameterFilters); err != nil {
+				return err
+			}
+		}
+		if err := jd.updateJobStatus(txn, ds, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkStatusFilters returns an error if the job of a status update doesn't match the custom value and parameter
+// filters of the update, the same filters the jobs were queried with.
+func (jd *EmbeddedHandleT) checkStatusFilters(txn *badger.Txn, ds *embeddedDataSet, jobID int64, customValFilters []string, parameterFilters []ParameterFilterT) error {
+	item, err := txn.Get([]byte(jd.jobKey(ds.index, jobID)))
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return fmt.Errorf("updating status of job %d: job not found", jobID)
+	}
+	if err != nil {
+		return err
+	}
+	var job JobT
+	if err := item.Value(func(val []byte) error {
+		return json.Unmarshal(val, &job)
+	}); err != nil {
+		return err
+	}
+	if !matchesQueryConditions(&job, GetQueryParamsT{CustomValFilters: customValFilters, ParameterFilters: parameterFilters}) {
+		return fmt.Errorf("updating status of job %d: job doesn't match the custom value filters %v and parameter filters %v", jobID, customValFilters, parameterFilters)
+	}
+	return nil
+}
+
+func (jd *EmbeddedHandleT) updateJobStatus(txn *badger.Txn, ds *embeddedDataSet, status *JobStatusT) error {
+	if !isValidEmbeddedJobState(status.JobState) {
+		return fmt.Errorf("invalid job state %q", status.JobState)
+	}
+	statusID, err := jd.statusSeq.Next()
+	if err != nil {
+		return err
+	}
+	status.sanitizeJson()
+	value, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	if err := txn.Set([]byte(jd.statusKey(ds.index, status.JobID, int64(statusID))), value); err != nil {
+		return err
+	}
+	return txn.Set([]byte(jd.latestKey(ds.index, status.JobID)), value)
+}
+
+// dsOf returns the dataset which contains the job with the given id.
+// Caller must hold the dataset list lock.
+func (jd *EmbeddedHandleT) dsOf(jobID int64) *embeddedDataSet {
+	jd.countersLock.Lock()
+	defer jd.countersLock.Unlock()
+	for _, ds := range jd.datasets {
+		if jobID <= ds.maxJobID {
+			return ds
+		}
+	}
+	return jd.datasets[len(jd.datasets)-1]
+}
+
+/* Queries */
+
+func (jd *EmbeddedHandleT) GetUnprocessed(ctx context.Context, params GetQueryParamsT) (JobsResult, error) { // skipcq: CRT-P0003
+	return jd.getJobs(ctx, params, func(_ *JobT, status *JobStatusT) bool {
+		return status == nil
+	})
+}
+
+func (jd *EmbeddedHandleT) GetProcessed(ctx context.Context, params GetQueryParamsT) (JobsResult, error) { // skipcq: CRT-P0003
+	now := getTimeNowFunc()
+	return jd.getJobs(ctx, params, func(_ *JobT, status *JobStatusT) bool {
+		if status == nil || !status.RetryTime.Before(now) {
+			return false
+		}
+		return len(params.StateFilters) == 0 || misc.ContainsString(params.StateFilters, status.JobState)
+	})
+}
+
+func (jd *EmbeddedHandleT) GetToRetry(ctx context.Context, params GetQueryParamsT) (JobsResult, error) { // skipcq: CRT-P0003
+	params.StateFilters = []string{Failed.State}
+	return jd.GetProcessed(ctx, params)
+}
+
+func (jd *EmbeddedHandleT) GetWaiting(ctx context.Context, params GetQueryParamsT) (JobsResult, error) { // skipcq: CRT-P0003
+	params.StateFilters = []string{Waiting.State}
+	return jd.GetProcessed(ctx, params)
+}
+
+func (jd *EmbeddedHandleT) GetExecuting(ctx context.Context, params GetQueryParamsT) (JobsResult, error) { // skipcq: CRT-P0003
+	params.StateFilters = []string{Executing.State}
+	return jd.GetProcessed(ctx, params)
+}
+
+func (jd *EmbeddedHandleT) GetImporting(ctx context.Context, params GetQueryParamsT) (JobsResult, error) { // skipcq: CRT-P0003
+	params.StateFilters = []string{Importing.State}
+	return jd.GetProcessed(ctx, params)
+}
+
+// GetAllJobs returns failed, waiting and unprocessed jobs, in this order, the same way MultiTenantLegacy does.
+func (jd *EmbeddedHandleT) GetAllJobs(ctx context.Context, workspaceCount map[string]int, params GetQueryParamsT, _ int) ([]*JobT, error) { // skipcq: CRT-P0003
+	var list []*JobT
+	params.JobsLimit = 0
+	for workspace := range workspaceCount {
+		params.JobsLimit += workspaceCount[workspace]
+	}
+	for _, get := range []func(context.Context, GetQueryParamsT) (JobsResult, error){jd.GetToRetry, jd.GetWaiting, jd.GetUnprocessed} {
+		res, err := get(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, res.Jobs...)
+		if res.LimitsReached {
+			break
+		}
+		updateParams(&params, res)
+	}
+	return list, nil
+}
+
+// getJobs iterates over all jobs in dataset order, returning the ones for which include returns true
+// and which are matching the query's filters, until one of the query's limits is reached.
+func (jd *EmbeddedHandleT) getJobs(ctx context.Context, params GetQueryParamsT, include func(job *JobT, status *JobStatusT) bool) (JobsResult, error) { // skipcq: CRT-P0003
+	if params.JobsLimit <= 0 || params.PayloadSizeLimit < 0 {
+		return JobsResult{}, nil
+	}
+	limiter := jobsLimiter{params: params}
+	var expired []*JobT
+	now := getTimeNowFunc()
+	var lastJobID int64
+	jd.dsListLock.RLock()
+	err := jd.db.View(func(txn *badger.Txn) error {
+		for _, ds := range jd.datasets {
+			done, err := jd.iterateJobs(txn, ds, params.AfterJobID, func(job *JobT, status *JobStatusT) (bool, error) {
+				if err := ctx.Err(); err != nil {
+					return false, err
+				}
+				if job.JobID <= lastJobID { // a copy of a job of a dataset being migrated
+					return true, nil
+				}
+				lastJobID = job.JobID
+				if !include(job, status) || !matchesQueryConditions(job, params) {
+					return true, nil
+				}
+				if status != nil {
+					job.LastJobStatus = *status
+				}
+				if job.hasExpired(now) && !isTerminalState(job.LastJobStatus.JobState) {
+					expired = append(expired, job)
+					return true, nil
+				}
+				return limiter.add(job), nil
+			})
+			if err != nil || done {
+				return err
+			}
+		}
+		return nil
+	})
+	jd.dsListLock.RUnlock()
+	if err != nil {
+		return JobsResult{}, err
+	}
+	if len(expired) > 0 {
+		if err := jd.abortExpiredJobs(expired); err != nil {
+			return JobsResult{}, err
+		}
+	}
+	return limiter.result, nil
+}
+
+// abortExpiredJobs marks the provided jobs as aborted, notifying the expired jobs handler, if any, in the same transaction
+func (jd *EmbeddedHandleT) abortExpiredJobs(jobs []*JobT) error {
+	statusList := expiredJobStatuses(jobs)
+	err := jd.withEmbeddedTx(func(tx *embeddedTx) error {
+		for _, status := range statusList {
+			if err := jd.updateJobStatus(tx.txn, jd.dsOf(status.JobID), status); err != nil {
+				return err
+			}
+		}
+		if jd.expiredJobsHandler != nil {
+			return jd.expiredJobsHandler(tx, jobs, statusList)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("aborting %d expired jobs: %w", len(jobs), err)
+	}
+	return nil
+}
+
+// iterateJobs calls f for every job of ds with a job id greater than afterJobID along with its latest status
+// (nil if there is none), until f returns false. It returns true if the iteration was interrupted by f.
+func (jd *EmbeddedHandleT) iterateJobs(txn *badger.Txn, ds *embeddedDataSet, afterJobID int64, f func(job *JobT, status *JobStatusT) (bool, error)) (bool, error) {
+	prefix := []byte(jd.key("job", strconv.Itoa(ds.index)) + "/")
+	it := txn.NewIterator(badger.IteratorOptions{PrefetchValues: true, PrefetchSize: 100, Prefix: prefix})
+	defer it.Close()
+	if afterJobID < 0 {
+		afterJobID = 0
+	}
+	for it.Seek([]byte(jd.jobKey(ds.index, afterJobID+1))); it.Valid(); it.Next() {
+		var job JobT
+		if err := it.Item().Value(func(val []byte) error {
+			return json.Unmarshal(val, &job)
+		}); err != nil {
+			return false, err
+		}
+		status, err := jd.latestStatus(txn, ds, job.JobID)
+		if err != nil {
+			return false, err
+		}
+		cont, err := f(&job, status)
+		if err != nil || !cont {
+			return !cont, err
+		}
+	}
+	return false, nil
+}
+
+func (jd *EmbeddedHandleT) latestStatus(txn *badger.Txn, ds *embeddedDataSet, jobID int64) (*JobStatusT, error) {
+	item, err := txn.Get([]byte(jd.latestKey(ds.index, jobID)))
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var status JobStatusT
+	if err := item.Value(func(val []byte) error {
+		return json.Unmarshal(val, &status)
+	}); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (jd *EmbeddedHandleT) GetPileUpCounts(ctx context.Context) (map[string]map[string]int, error) {
+	jd.dsListLock.RLock()
+	defer jd.dsListLock.RUnlock()
+	statMap := make(map[string]map[string]int)
+	var lastJobID int64
+	err := jd.db.View(func(txn *badger.Txn) error {
+		for _, ds := range jd.datasets {
+			_, err := jd.iterateJobs(txn, ds, 0, func(job *JobT, status *JobStatusT) (bool, error) {
+				if err := ctx.Err(); err != nil {
+					return false, err
+				}
+				if job.JobID <= lastJobID { // a copy of a job of a dataset being migrated
+					return true, nil
+				}
+				lastJobID = job.JobID
+				if status != nil && isTerminalState(status.JobState) {
+					return true, nil
+				}
+				if _, ok := statMap[job.WorkspaceId]; !ok {
+					statMap[job.WorkspaceId] = make(map[string]int)
+				}
+				statMap[job.WorkspaceId][job.CustomVal]++
+				return true, nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return statMap, err
+}
+
+/* Readonly */
+
+// HavePendingJobs returns whether there are jobs matching the filters which are not in a terminal state
+func (jd *EmbeddedHandleT) HavePendingJobs(ctx context.Context, customValFilters []string, _ int, parameterFilters []ParameterFilterT) (bool, error) {
+	res, err := jd.getJobs(ctx, GetQueryParamsT{CustomValFilters: customValFilters, ParameterFilters: parameterFilters, JobsLimit: 1}, func(_ *JobT, status *JobStatusT) bool {
+		return status == nil || !isTerminalState(status.JobState)
+	})
+	return len(res.Jobs) > 0, err
+}
+
+func (jd *EmbeddedHandleT) GetDSListString() (string, error) {
+	jd.dsListLock.RLock()
+	defer jd.dsListLock.RUnlock()
+	var response string
+	for _, ds := range jd.datasets {
+		response = response + jd.key("ds", ds.String()) + "\n"
+	}
+	return response, nil
+}
+
+func (*EmbeddedHandleT) GetJobSummaryCount(_, _ string) (string, error) {
+	return "", errEmbeddedAdminQuery
+}
+
+func (*EmbeddedHandleT) GetLatestFailedJobs(_, _ string) (string, error) {
+	return "", errEmbeddedAdminQuery
+}
+
+func (*EmbeddedHandleT) GetJobIDsForUser(_ []string) (string, error) {
+	return "", errEmbeddedAdminQuery
+}
+
+func (*EmbeddedHandleT) GetFailedStatusErrorCodeCountsByDestination(_ []string) (string, error) {
+	return "", errEmbeddedAdminQuery
+}
+
+func (*EmbeddedHandleT) GetJobIDStatus(_, _ string) (string, error) {
+	return "", errEmbeddedAdminQuery
+}
+
+func (*EmbeddedHandleT) GetJobByID(_, _ string) (string, error) {
+	return "", errEmbeddedAdminQuery
+}
+
+/* Admin */
+
+// DeleteExecuting deletes the latest status of jobs whose latest job state is executing,
+// so that they are going to be picked up again.
+func (jd *EmbeddedHandleT) DeleteExecuting() {
+	jd.dsListLock.Lock()
+	defer jd.dsListLock.Unlock()
+	for _, ds := range jd.datasets {
+		err := jd.db.Update(func(txn *badger.Txn) error {
+			_, err := jd.iterateJobs(txn, ds, 0, func(job *JobT, status *JobStatusT) (bool, error) {
+				if status == nil || status.JobState != Executing.State {
+					return true, nil
+				}
+				return true, jd.deleteLatestStatus(txn, ds, job.JobID)
+			})
+			return err
+		})
+		if err != nil {
+			panic(fmt.Errorf("[[ %s ]]: deleting executing job statuses: %w", jd.tablePrefix, err))
+		}
+	}
+}
+
+// deleteLatestStatus removes the last status of a job from its history, making the previous one (if any) the latest
+func (jd *EmbeddedHandleT) deleteLatestStatus(txn *badger.Txn, ds *embeddedDataSet, jobID int64) error {
+	prefix := []byte(jd.statusKey(ds.index, jobID, -1))
+	it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix, Reverse: true})
+	var keys [][]byte
+	var previous []byte
+	for it.Seek(append(prefix, 0xFF)); it.Valid() && len(keys) < 2; it.Next() {
+		keys = append(keys, it.Item().KeyCopy(nil))
+		if len(keys) == 2 {
+			var err error
+			if previous, err = it.Item().ValueCopy(nil); err != nil {
+				it.Close()
+				return err
+			}
+		}
+	}
+	it.Close()
+	if len(keys) > 0 {
+		if err := txn.Delete(keys[0]); err != nil {
+			return err
+		}
+	}
+	if previous == nil {
+		return txn.Delete([]byte(jd.latestKey(ds.index, jobID)))
+	}
+	return txn.Set([]byte(jd.latestKey(ds.index, jobID)), previous)
+}
+
+/* Journal */
+
+func (jd *EmbeddedHandleT) JournalMarkStart(opType string, opPayload json.RawMessage) int64 {
+	id, err := jd.journalSeq.Next()
+	if err != nil {
+		panic(err)
+	}
+	entry := embeddedJournalEntry{
+		JournalEntryT: JournalEntryT{OpID: int64(id) + 1, OpType: opType, OpPayload: opPayload},
+		Owner:         jd.ownerType,
+	}
+	if err := jd.putJournalEntry(&entry); err != nil {
+		panic(err)
+	}
+	return entry.OpID
+}
+
+// JournalMarkDone marks the end of a journal action
+func (jd *EmbeddedHandleT) JournalMarkDone(opID int64) {
+	err := jd.db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(jd.journalKey(opID)))
+		if err != nil {
+			return err
+		}
+		var entry embeddedJournalEntry
+		if err := item.Value(func(val []byte) error { return json.Unmarshal(val, &entry) }); err != nil {
+			return err
+		}
+		entry.OpDone = true
+		value, err := json.Marshal(&entry)
+		if err != nil {
+			return err
+		}
+		return txn.Set(item.KeyCopy(nil), value)
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (jd *EmbeddedHandleT) JournalDeleteEntry(opID int64) {
+	err := jd.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(jd.journalKey(opID)))
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (jd *EmbeddedHandleT) GetJournalEntries(opType string) (entries []JournalEntryT) {
+	err := jd.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{PrefetchValues: true, Prefix: []byte(jd.key("journal") + "/")})
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			var entry embeddedJournalEntry
+			if err := it.Item().Value(func(val []byte) error { return json.Unmarshal(val, &entry) }); err != nil {
+				return err
+			}
+			if !entry.OpDone && entry.OpType == opType && entry.Owner == jd.ownerType {
+				entries = append(entries, entry.JournalEntryT)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return entries
+}
+
+func (jd *EmbeddedHandleT) putJournalEntry(entry *embeddedJournalEntry) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return jd.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(jd.journalKey(entry.OpID)), value)
+	})
+}
+
+// recoverFromJournal completes dataset operations which were interrupted by a crash.
+// Both copying pending jobs and dropping a dataset are idempotent, so they are simply executed again.
+// Caller must hold the dataset list write lock.
+func (jd *EmbeddedHandleT) recoverFromJournal() error {
+	for _, opType := range []string{migrateCopyOperation, dropDSOperation} {
+		for _, entry := range jd.GetJournalEntries(opType) {
+			var payload embeddedJournalPayload
+			if err := json.Unmarshal(entry.OpPayload, &payload); err != nil {
+				return err
+			}
+			jd.logger.Infof("Recovering from interrupted %s operation: %s", opType, string(entry.OpPayload))
+			if opType == migrateCopyOperation {
+				// the copy is complete once the dataset has been removed from the list
+				pending, err := jd.hasDSMarker(payload.From)
+				if err != nil {
+					return err
+				}
+				if pending {
+					if _, _, err := jd.copyPendingJobs(payload.From, payload.To); err != nil {
+						return err
+					}
+				}
+			}
+			if err := jd.dropDSData(payload.From); err != nil {
+				return err
+			}
+			jd.JournalMarkDone(entry.OpID)
+		}
+	}
+	return nil
+}
+
+/* Datasets */
+
+type embeddedJournalPayload struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// loadDSList loads the list of datasets along with their job id ranges from the store.
+// Caller must hold the dataset list write lock.
+func (jd *EmbeddedHandleT) loadDSList() error {
+	jd.datasets = nil
+	err := jd.db.View(func(txn *badger.Txn) error {
+		prefix := []byte(jd.key("ds") + "/")
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix})
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			idx, err := strconv.Atoi(strings.TrimPrefix(string(it.Item().Key()), string(prefix)))
+			if err != nil {
+				return err
+			}
+			jd.datasets = append(jd.datasets, &embeddedDataSet{index: idx})
+		}
+		sort.Slice(jd.datasets, func(i, j int) bool { return jd.datasets[i].index < jd.datasets[j].index })
+
+		for _, ds := range jd.datasets {
+			jobsIt := txn.NewIterator(badger.IteratorOptions{Prefix: []byte(jd.key("job", strconv.Itoa(ds.index)) + "/")})
+			for jobsIt.Rewind(); jobsIt.Valid(); jobsIt.Next() {
+				id := jd.jobIDOf(jobsIt.Item().Key())
+				if ds.minJobID == 0 {
+					ds.minJobID = id
+				}
+				ds.maxJobID = id
+				ds.jobCount++
+			}
+			jobsIt.Close()
+		}
+		return nil
+	})
+	jd.gaugeDSCount()
+	return err
+}
+
+// addNewDS appends a new dataset to the list of datasets.
+// Caller must hold the dataset list write lock.
+func (jd *EmbeddedHandleT) addNewDS() error {
+	idx := 1
+	if len(jd.datasets) > 0 {
+		idx = jd.datasets[len(jd.datasets)-1].index + 1
+	}
+	err := jd.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(jd.key("ds", strconv.Itoa(idx))), nil)
+	})
+	if err != nil {
+		return err
+	}
+	jd.datasets = append(jd.datasets, &embeddedDataSet{index: idx})
+	jd.gaugeDSCount()
+	jd.logger.Infof("Added new dataset %d", idx)
+	return nil
+}
+
+func (jd *EmbeddedHandleT) addNewDSLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-jd.TriggerAddNewDS():
+		}
+		jd.dsListLock.Lock()
+		var err error
+		if last := jd.datasets[len(jd.datasets)-1]; last.jobCount >= jd.maxDSSize {
+			err = jd.addNewDS()
+		}
+		jd.dsListLock.Unlock()
+		if err != nil {
+			panic(fmt.Errorf("[[ %s ]]: adding new dataset: %w", jd.tablePrefix, err))
+		}
+	}
+}
+
+func (jd *EmbeddedHandleT) migrateDSLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-jd.TriggerMigrateDS():
+		}
+		if err := jd.migrateDS(); err != nil {
+			panic(fmt.Errorf("[[ %s ]]: migrating datasets: %w", jd.tablePrefix, err))
+		}
+	}
+}
+
+// migrateDS looks for datasets (except the latest one) whose jobs are completed. If all of a dataset's jobs
+// are in a terminal state, the dataset is dropped. If the fraction of completed jobs is above jobDoneMigrateThres,
+// pending jobs are copied into the next dataset and then the dataset is dropped.
+//
+// Pending jobs are copied while holding the dataset list read lock, so that jobs can be stored and updated in the
+// meantime. Readers skip the copies, as they have already seen the same job ids in the dataset being migrated.
+// The write lock is only held for copying again the jobs updated during the copy and removing the dataset from the list.
+func (jd *EmbeddedHandleT) migrateDS() error {
+	for {
+		migrated, err := jd.migrateNextDS()
+		if err != nil || !migrated {
+			return err
+		}
+	}
+}
+
+// migrateNextDS drops or migrates the first dataset which can be dropped or migrated, returning false if there is none
+func (jd *EmbeddedHandleT) migrateNextDS() (bool, error) {
+	jd.dsListLock.RLock()
+	var ds, next *embeddedDataSet
+	var copyJobs bool
+	for i := 0; i < len(jd.datasets)-1 && ds == nil; i++ {
+		pending, err := jd.countPendingJobs(jd.datasets[i])
+		if err != nil {
+			jd.dsListLock.RUnlock()
+			return false, err
+		}
+		jd.countersLock.Lock()
+		jobCount := jd.datasets[i].jobCount
+		jd.countersLock.Unlock()
+		switch {
+		case pending == 0:
+			ds, next = jd.datasets[i], jd.datasets[i+1]
+		case jobCount > 0 && float64(jobCount-pending)/float64(jobCount) >= jd.jobDoneMigrateThres:
+			ds, next, copyJobs = jd.datasets[i], jd.datasets[i+1], true
+		}
+	}
+	if ds == nil {
+		jd.dsListLock.RUnlock()
+		return false, nil
+	}
+
+	payload, _ := json.Marshal(embeddedJournalPayload{From: ds.index, To: next.index})
+	var (
+		opID     int64
+		copied   int
+		copiedAt uint64
+	)
+	if copyJobs {
+		opID = jd.JournalMarkStart(migrateCopyOperation, payload)
+		var err error
+		if copied, copiedAt, err = jd.copyPendingJobs(ds.index, next.index); err != nil {
+			jd.dsListLock.RUnlock()
+			return false, err
+		}
+	} else {
+		opID = jd.JournalMarkStart(dropDSOperation, payload)
+	}
+	jd.dsListLock.RUnlock()
+
+	jd.dsListLock.Lock()
+	if copyJobs {
+		recopied, err := jd.copyJobsUpdatedSince(ds.index, next.index, copiedAt)
+		if err != nil {
+			jd.dsListLock.Unlock()
+			return false, err
+		}
+		jd.countersLock.Lock()
+		if next.minJobID == 0 || ds.minJobID < next.minJobID {
+			next.minJobID = ds.minJobID
+		}
+		next.jobCount += copied + recopied
+		jd.countersLock.Unlock()
+	}
+	// removing the dataset marker before releasing the lock, so that the copy isn't repeated after a crash,
+	// overwriting the statuses of the jobs updated after the migration
+	if err := jd.deleteDSMarker(ds.index); err != nil {
+		jd.dsListLock.Unlock()
+		return false, err
+	}
+	for i := range jd.datasets {
+		if jd.datasets[i] == ds {
+			jd.datasets = append(jd.datasets[:i], jd.datasets[i+1:]...)
+			break
+		}
+	}
+	jd.gaugeDSCount()
+	jd.dsListLock.Unlock()
+
+	if err := jd.dropDSData(ds.index); err != nil {
+		return false, err
+	}
+	jd.JournalMarkDone(opID)
+	if copyJobs {
+		jd.logger.Infof("Migrated %d pending jobs from dataset %d to %d", copied, ds.index, next.index)
+	}
+	return true, nil
+}
+
+// countPendingJobs returns the number of jobs of ds which are not in a terminal state
+func (jd *EmbeddedHandleT) countPendingJobs(ds *embeddedDataSet) (int, error) {
+	var pending int
+	err := jd.db.View(func(txn *badger.Txn) error {
+		_, err := jd.iterateJobs(txn, ds, 0, func(_ *JobT, status *JobStatusT) (bool, error) {
+			if status == nil || !isTerminalState(status.JobState) {
+				pending++
+			}
+			return true, nil
+		})
+		return err
+	})
+	return pending, err
+}
+
+// copyPendingJobs copies jobs of dataset from which are not in a terminal state, along with their latest status, to
+// dataset to, removing previous copies of jobs which are now in a terminal state. It returns the number of copied jobs
+// and the version of the store which they were copied at.
+func (jd *EmbeddedHandleT) copyPendingJobs(from, to int) (int, uint64, error) {
+	var (
+		copied   int
+		copiedAt uint64
+	)
+	src := &embeddedDataSet{index: from}
+	wb := jd.db.NewWriteBatch()
+	defer wb.Cancel()
+	err := jd.db.View(func(txn *badger.Txn) error {
+		copiedAt = txn.ReadTs()
+		_, err := jd.iterateJobs(txn, src, 0, func(job *JobT, status *JobStatusT) (bool, error) {
+			pending, err := jd.copyJob(wb, to, job, status)
+			if pending {
+				copied++
+			}
+			return err == nil, err
+		})
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return copied, copiedAt, wb.Flush()
+}
+
+// copyJobsUpdatedSince copies again the jobs of dataset from whose latest status changed after version copiedAt of the store,
+// returning the number of jobs which became pending since then (negative if more jobs completed).
+// Caller must hold the dataset list write lock.
+func (jd *EmbeddedHandleT) copyJobsUpdatedSince(from, to int, copiedAt uint64) (int, error) {
+	var updated []int64
+	err := jd.db.View(func(txn *badger.Txn) error {
+		prefix := []byte(jd.key("latest", strconv.Itoa(from)) + "/")
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix, AllVersions: true})
+		defer it.Close()
+		var last []byte
+		for it.Rewind(); it.Valid(); it.Next() {
+			// versions of a key are iterated from the newest to the oldest one, only the newest one is relevant
+			if key := it.Item().Key(); !bytes.Equal(key, last) {
+				last = it.Item().KeyCopy(nil)
+				if it.Item().Version() > copiedAt {
+					updated = append(updated, jd.jobIDOf(last))
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil || len(updated) == 0 {
+		return 0, err
+	}
+
+	var delta int
+	src := &embeddedDataSet{index: from}
+	wb := jd.db.NewWriteBatch()
+	defer wb.Cancel()
+	err = jd.db.View(func(txn *badger.Txn) error {
+		for _, jobID := range updated {
+			item, err := txn.Get([]byte(jd.jobKey(from, jobID)))
+			if err != nil {
+				return err
+			}
+			var job JobT
+			if err := item.Value(func(val []byte) error { return json.Unmarshal(val, &job) }); err != nil {
+				return err
+			}
+			status, err := jd.latestStatus(txn, src, jobID)
+			if err != nil {
+				return err
+			}
+			copiedBefore, err := jd.isCopied(txn, to, jobID)
+			if err != nil {
+				return err
+			}
+			pending, err := jd.copyJob(wb, to, &job, status)
+			if err != nil {
+				return err
+			}
+			switch {
+			case pending && !copiedBefore:
+				delta++
+			case !pending && copiedBefore:
+				delta--
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return delta, wb.Flush()
+}
+
+// isCopied returns whether a job has been copied to dataset to
+func (jd *EmbeddedHandleT) isCopied(txn *badger.Txn, to int, jobID int64) (bool, error) {
+	_, err := txn.Get([]byte(jd.jobKey(to, jobID)))
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// copyJob writes job along with its latest status to dataset to, or removes its copy if the job is in a terminal state.
+// It returns whether the job is pending.
+func (jd *EmbeddedHandleT) copyJob(wb *badger.WriteBatch, to int, job *JobT, status *JobStatusT) (bool, error) {
+	if status != nil && isTerminalState(status.JobState) {
+		for _, key := range []string{jd.jobKey(to, job.JobID), jd.statusKey(to, job.JobID, 0), jd.latestKey(to, job.JobID)} {
+			if err := wb.Delete([]byte(key)); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+	job.LastJobStatus = JobStatusT{}
+	value, err := json.Marshal(job)
+	if err != nil {
+		return false, err
+	}
+	if err := wb.Set([]byte(jd.jobKey(to, job.JobID)), value); err != nil {
+		return false, err
+	}
+	if status == nil {
+		for _, key := range []string{jd.statusKey(to, job.JobID, 0), jd.latestKey(to, job.JobID)} {
+			if err := wb.Delete([]byte(key)); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	statusValue, err := json.Marshal(status)
+	if err != nil {
+		return false, err
+	}
+	// using a status id of zero, so that the copied status is always the oldest one in the history
+	if err := wb.Set([]byte(jd.statusKey(to, job.JobID, 0)), statusValue); err != nil {
+		return false, err
+	}
+	return true, wb.Set([]byte(jd.latestKey(to, job.JobID)), statusValue)
+}
+
+// hasDSMarker returns whether the marker of a dataset exists, i.e. whether the dataset hasn't been dropped yet
+func (jd *EmbeddedHandleT) hasDSMarker(idx int) (bool, error) {
+	err := jd.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte(jd.key("ds", strconv.Itoa(idx))))
+		return err
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (jd *EmbeddedHandleT) deleteDSMarker(idx int) error {
+	return jd.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(jd.key("ds", strconv.Itoa(idx))))
+	})
+}
+
+// dropDSData removes all data of a dataset
+func (jd *EmbeddedHandleT) dropDSData(idx int) error {
+	dsIdx := strconv.Itoa(idx)
+	if err := jd.deleteDSMarker(idx); err != nil {
+		return err
+	}
+	return jd.db.DropPrefix(
+		[]byte(jd.key("job", dsIdx)+"/"),
+		[]byte(jd.key("status", dsIdx)+"/"),
+		[]byte(jd.key("latest", dsIdx)+"/"),
+	)
+}
+
+func (jd *EmbeddedHandleT) gaugeDSCount() {
+	if jd.statDSCount != nil {
+		jd.statDSCount.Gauge(len(jd.datasets))
+	}
+}
+
+/* Keys */
+
+func (jd *EmbeddedHandleT) key(parts ...string) string {
+	return strings.Join(append([]string{jd.tablePrefix}, parts...), "/")
+}
+
+func (jd *EmbeddedHandleT) jobKey(dsIdx int, jobID int64) string {
+	return jd.key("job", strconv.Itoa(dsIdx), fmt.Sprintf("%020d", jobID))
+}
+
+// statusKey returns the key of a job's status; a negative statusID returns the common prefix of all the job's statuses
+func (jd *EmbeddedHandleT) statusKey(dsIdx int, jobID, statusID int64) string {
+	if statusID < 0 {
+		return jd.key("status", strconv.Itoa(dsIdx), fmt.Sprintf("%020d", jobID)) + "/"
+	}
+	return jd.key("status", strconv.Itoa(dsIdx), fmt.Sprintf("%020d", jobID), fmt.Sprintf("%020d", statusID))
+}
+
+func (jd *EmbeddedHandleT) latestKey(dsIdx int, jobID int64) string {
+	return jd.key("latest", strconv.Itoa(dsIdx), fmt.Sprintf("%020d", jobID))
+}
+
+func (jd *EmbeddedHandleT) journalKey(opID int64) string {
+	return jd.key("journal", fmt.Sprintf("%020d", opID))
+}
+
+func (*EmbeddedHandleT) jobIDOf(key []byte) int64 {
+	k := string(key)
+	id, _ := strconv.ParseInt(k[strings.LastIndex(k, "/")+1:], 10, 64)
+	return id
+}
+
+/* Helpers */
+
+// jobsLimiter accumulates jobs into a JobsResult, applying the same limit semantics as the postgres queries:
+// the first job is always accepted, even if it exceeds the events or payload size limits.
+type jobsLimiter struct {
+	params GetQueryParamsT
+	result JobsResult
+}
+
+// add adds job to the result, if it fits, and returns whether more jobs can be added
+func (l *jobsLimiter) add(job *JobT) bool {
+	if len(l.result.Jobs) > 0 {
+		if l.params.EventsLimit > 0 && l.result.EventsCount+job.EventCount > l.params.EventsLimit ||
+			l.params.PayloadSizeLimit > 0 && l.result.PayloadSize+job.PayloadSize > l.params.PayloadSizeLimit {
+			l.result.LimitsReached = true
+			return false
+		}
+	}
+	l.result.Jobs = append(l.result.Jobs, job)
+	l.result.EventsCount += job.EventCount
+	l.result.PayloadSize += job.PayloadSize
+
+	if len(l.result.Jobs) >= l.params.JobsLimit ||
+		l.params.EventsLimit > 0 && l.result.EventsCount >= l.params.EventsLimit ||
+		l.params.PayloadSizeLimit > 0 && l.result.PayloadSize >= l.params.PayloadSizeLimit {
+		l.result.LimitsReached = true
+		return false
+	}
+	return true
+}
+
+// matchesQueryConditions evaluates the custom value, priority and parameter filters of params against job
+func matchesQueryConditions(job *JobT, params GetQueryParamsT) bool {
+	if len(params.CustomValFilters) > 0 && !params.IgnoreCustomValFiltersInQuery && !misc.ContainsString(params.CustomValFilters, job.CustomVal) {
+		return false
+	}
+	if len(params.PriorityFilters) > 0 && !misc.ContainsInt(params.PriorityFilters, job.Priority) {
+		return false
+	}
+	for _, filter := range params.ExcludedParameterFilters {
+		if value := gjson.GetBytes(job.Parameters, filter.Name); value.Type == gjson.String && value.Str == filter.Value {
+			return false
+		}
+	}
+	return matchesParameterFilters(job.Parameters, params.ParameterFilters)
+}
+
+// matchesParameterFilters mirrors constructParameterJSONQuery: either all filters match, or all mandatory
+// filters match and all optional parameters are missing.
+func matchesParameterFilters(parameters json.RawMessage, filters []ParameterFilterT) bool {
+	allMatch, optionalMissing := true, true
+	for _, filter := range filters {
+		value := gjson.GetBytes(parameters, filter.Name)
+		matches := value.Type == gjson.String && value.Str == filter.Value
+		if !filter.Optional && !matches {
+			return false
+		}
+		if filter.Optional && value.Exists() {
+			optionalMissing = false
+		}
+		allMatch = allMatch && matches
+	}
+	return allMatch || optionalMissing
+}
+
+func isValidEmbeddedJobState(state string) bool {
+	for _, js := range jobStates {
+		if js.isValid && js.State == state {
+			return true
+		}
+	}
+	return false
+}
+
+type embeddedLogger struct {
+	logger.LoggerI
+}
+
+func (l embeddedLogger) Warningf(fmt string, args ...interface{}) {
+	l.Warnf(fmt, args...)
+}
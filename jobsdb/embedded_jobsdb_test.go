@@ -0,0 +1,373 @@
+package jobsdb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+)
+
+func initEmbeddedJobsDB() {
+	config.Load()
+	logger.Init()
+}
+
+func newTestEmbeddedJobsDB(t *testing.T, path string, opts ...EmbeddedOptsFunc) *EmbeddedHandleT {
+	jd, err := NewEmbedded("gw", path, append([]EmbeddedOptsFunc{WithEmbeddedStats(nil)}, opts...)...)
+	require.NoError(t, err)
+	return jd
+}
+
+func genEmbeddedJobs(workspaceID, customVal string, count, eventsPerJob int) []*JobT {
+	jobs := make([]*JobT, count)
+	for i := range jobs {
+		jobs[i] = &JobT{
+			UUID:         uuid.Must(uuid.NewV4()),
+			UserID:       fmt.Sprintf("user-%d", i%3),
+			CustomVal:    customVal,
+			EventCount:   eventsPerJob,
+			EventPayload: []byte(`{"batch":[{"type":"track"}]}`),
+			Parameters:   []byte(`{"source_id":"sourceID","destination_id":"destinationID"}`),
+			WorkspaceId:  workspaceID,
+		}
+	}
+	return jobs
+}
+
+func embeddedStatuses(jobs []*JobT, state string) []*JobStatusT {
+	statuses := make([]*JobStatusT, 0, len(jobs))
+	for _, job := range jobs {
+		statuses = append(statuses, &JobStatusT{
+			JobID:         job.JobID,
+			JobState:      state,
+			AttemptNum:    1,
+			ExecTime:      time.Now(),
+			RetryTime:     time.Now(),
+			ErrorCode:     "200",
+			ErrorResponse: []byte(`{}`),
+			Parameters:    []byte(`{}`),
+			WorkspaceId:   job.WorkspaceId,
+		})
+	}
+	return statuses
+}
+
+func TestEmbeddedJobsDB(t *testing.T) {
+	initEmbeddedJobsDB()
+	ctx := context.Background()
+
+	t.Run("store, get unprocessed and update statuses", func(t *testing.T) {
+		jd := newTestEmbeddedJobsDB(t, t.TempDir())
+		defer jd.TearDown()
+
+		jobs := genEmbeddedJobs("ws", "GW", 10, 1)
+		require.NoError(t, jd.Store(ctx, jobs))
+		for i := 1; i < len(jobs); i++ {
+			require.Greater(t, jobs[i].JobID, jobs[i-1].JobID, "job ids should be increasing")
+		}
+
+		unprocessed, err := jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Len(t, unprocessed.Jobs, 10)
+		require.False(t, unprocessed.LimitsReached)
+		require.Equal(t, jobs[0].UUID, unprocessed.Jobs[0].UUID)
+
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(jobs[:4], Failed.State), nil, nil))
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(jobs[4:6], Succeeded.State), nil, nil))
+
+		unprocessed, err = jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Len(t, unprocessed.Jobs, 4)
+
+		toRetry, err := jd.GetToRetry(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Len(t, toRetry.Jobs, 4)
+		require.Equal(t, Failed.State, toRetry.Jobs[0].LastJobStatus.JobState)
+
+		pileUp, err := jd.GetPileUpCounts(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 8, pileUp["ws"]["GW"])
+	})
+
+	t.Run("query limits and filters", func(t *testing.T) {
+		jd := newTestEmbeddedJobsDB(t, t.TempDir())
+		defer jd.TearDown()
+
+		require.NoError(t, jd.Store(ctx, genEmbeddedJobs("ws", "WEBHOOK", 5, 2)))
+		require.NoError(t, jd.Store(ctx, genEmbeddedJobs("ws", "GW", 5, 2)))
+
+		res, err := jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 3})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 3)
+		require.True(t, res.LimitsReached)
+
+		res, err = jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100, EventsLimit: 5})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 2)
+		require.Equal(t, 4, res.EventsCount)
+		require.True(t, res.LimitsReached)
+
+		res, err = jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100, EventsLimit: 1})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 1, "the first job should always be returned, even if it exceeds the events limit")
+
+		res, err = jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100, CustomValFilters: []string{"GW"}})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 5)
+
+		res, err = jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100, ParameterFilters: []ParameterFilterT{{Name: "source_id", Value: "other"}}})
+		require.NoError(t, err)
+		require.Empty(t, res.Jobs)
+
+		res, err = jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100, ParameterFilters: []ParameterFilterT{
+			{Name: "source_id", Value: "sourceID"},
+			{Name: "destination_id", Value: "destinationID", Optional: true},
+		}})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 10)
+
+		res, err = jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100, ExcludedParameterFilters: []ParameterFilterT{{Name: "source_id", Value: "sourceID"}}})
+		require.NoError(t, err)
+		require.Empty(t, res.Jobs)
+
+		res, err = jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100, ExcludedParameterFilters: []ParameterFilterT{{Name: "source_id", Value: "other"}}})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 10)
+	})
+
+	t.Run("store-safe transaction is rolled back on error", func(t *testing.T) {
+		jd := newTestEmbeddedJobsDB(t, t.TempDir())
+		defer jd.TearDown()
+
+		err := jd.WithStoreSafeTx(func(tx StoreSafeTx) error {
+			require.NoError(t, jd.StoreInTx(ctx, tx, genEmbeddedJobs("ws", "GW", 2, 1)))
+			return fmt.Errorf("rollback")
+		})
+		require.Error(t, err)
+
+		res, err := jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Empty(t, res.Jobs)
+	})
+
+	t.Run("store with retry each rejects invalid payloads", func(t *testing.T) {
+		jd := newTestEmbeddedJobsDB(t, t.TempDir())
+		defer jd.TearDown()
+
+		jobs := genEmbeddedJobs("ws", "GW", 2, 1)
+		jobs[1].EventPayload = []byte(`{"invalid"`)
+		errorMessages := jd.StoreWithRetryEach(ctx, jobs)
+		require.Len(t, errorMessages, 1)
+		require.Contains(t, errorMessages, jobs[1].UUID)
+	})
+
+	t.Run("delete executing", func(t *testing.T) {
+		jd := newTestEmbeddedJobsDB(t, t.TempDir())
+		defer jd.TearDown()
+
+		jobs := genEmbeddedJobs("ws", "GW", 2, 1)
+		require.NoError(t, jd.Store(ctx, jobs))
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(jobs[:1], Failed.State), nil, nil))
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(jobs, Executing.State), nil, nil))
+
+		jd.DeleteExecuting()
+
+		res, err := jd.GetExecuting(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Empty(t, res.Jobs)
+		res, err = jd.GetToRetry(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 1, "previous status should become the latest one")
+		res, err = jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 1)
+	})
+
+	t.Run("dataset rotation and migration", func(t *testing.T) {
+		triggerAddNewDS := make(chan time.Time)
+		jd := newTestEmbeddedJobsDB(t, t.TempDir(), WithEmbeddedMaxDSSize(10), func(jd *EmbeddedHandleT) {
+			jd.TriggerAddNewDS = func() <-chan time.Time { return triggerAddNewDS }
+			jd.TriggerMigrateDS = func() <-chan time.Time { return make(chan time.Time) }
+		})
+		defer jd.TearDown()
+		require.NoError(t, jd.Start())
+
+		first := genEmbeddedJobs("ws", "GW", 10, 1)
+		require.NoError(t, jd.Store(ctx, first))
+		triggerAddNewDS <- time.Now()
+		triggerAddNewDS <- time.Now() // waiting for the previous iteration to complete
+		second := genEmbeddedJobs("ws", "GW", 10, 1)
+		require.NoError(t, jd.Store(ctx, second))
+		triggerAddNewDS <- time.Now()
+		triggerAddNewDS <- time.Now()
+		third := genEmbeddedJobs("ws", "GW", 5, 1)
+		require.NoError(t, jd.Store(ctx, third))
+		require.Len(t, jd.datasets, 3)
+
+		// first dataset is fully processed, second one is mostly processed
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(first, Succeeded.State), nil, nil))
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(second[:9], Aborted.State), nil, nil))
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(second[9:], Failed.State), nil, nil))
+
+		require.NoError(t, jd.migrateDS())
+		require.Len(t, jd.datasets, 1)
+		require.Equal(t, 6, jd.datasets[0].jobCount)
+		require.Empty(t, jd.GetJournalEntries(migrateCopyOperation))
+
+		toRetry, err := jd.GetToRetry(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Len(t, toRetry.Jobs, 1)
+		require.Equal(t, second[9].JobID, toRetry.Jobs[0].JobID)
+
+		// statuses of migrated jobs can still be updated
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(second[9:], Succeeded.State), nil, nil))
+		res, err := jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 5)
+		require.Equal(t, third[0].JobID, res.Jobs[0].JobID)
+	})
+
+	t.Run("jobs updated while being migrated", func(t *testing.T) {
+		jd := newTestEmbeddedJobsDB(t, t.TempDir())
+		defer jd.TearDown()
+
+		first := genEmbeddedJobs("ws", "GW", 10, 1)
+		require.NoError(t, jd.Store(ctx, first))
+		jd.dsListLock.Lock()
+		require.NoError(t, jd.addNewDS())
+		jd.dsListLock.Unlock()
+		second := genEmbeddedJobs("ws", "GW", 2, 1)
+		require.NoError(t, jd.Store(ctx, second))
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(first[:7], Succeeded.State), nil, nil))
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(first[7:8], Failed.State), nil, nil))
+
+		// copying the pending jobs, as the first step of the migration
+		from, to := jd.datasets[0].index, jd.datasets[1].index
+		copied, copiedAt, err := jd.copyPendingJobs(from, to)
+		require.NoError(t, err)
+		require.Equal(t, 3, copied)
+
+		res, err := jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 4, "copies of jobs should not be returned twice")
+		pileUp, err := jd.GetPileUpCounts(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 5, pileUp["ws"]["GW"])
+
+		// jobs being updated while copying
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(first[7:9], Succeeded.State), nil, nil))
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(first[9:], Waiting.State), nil, nil))
+
+		recopied, err := jd.copyJobsUpdatedSince(from, to, copiedAt)
+		require.NoError(t, err)
+		require.Equal(t, -2, recopied)
+
+		require.NoError(t, jd.dropDSData(from))
+		jd.datasets = jd.datasets[1:]
+		res, err = jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 2)
+		res, err = jd.GetWaiting(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 1)
+		require.Equal(t, first[9].JobID, res.Jobs[0].JobID)
+		res, err = jd.GetToRetry(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Empty(t, res.Jobs, "jobs completed while copying should not be migrated")
+	})
+
+	t.Run("transactions of other jobsdb instances and status filters", func(t *testing.T) {
+		jd := newTestEmbeddedJobsDB(t, t.TempDir())
+		defer jd.TearDown()
+		other, err := NewEmbedded("rt", t.TempDir(), WithEmbeddedStats(nil))
+		require.NoError(t, err)
+		defer other.TearDown()
+
+		jobs := genEmbeddedJobs("ws", "GW", 2, 1)
+		err = other.WithStoreSafeTx(func(tx StoreSafeTx) error {
+			return jd.StoreInTx(ctx, tx, jobs)
+		})
+		require.ErrorIs(t, err, errEmbeddedForeignTx)
+		_ = other.WithStoreSafeTx(func(tx StoreSafeTx) error {
+			require.Len(t, jd.StoreWithRetryEachInTx(ctx, tx, jobs), 2)
+			return nil
+		})
+
+		require.NoError(t, jd.Store(ctx, jobs))
+		err = other.WithUpdateSafeTx(func(tx UpdateSafeTx) error {
+			return jd.UpdateJobStatusInTx(ctx, tx, embeddedStatuses(jobs, Succeeded.State), nil, nil)
+		})
+		require.ErrorIs(t, err, errEmbeddedForeignTx)
+
+		require.Error(t, jd.UpdateJobStatus(ctx, embeddedStatuses(jobs, Succeeded.State), []string{"WEBHOOK"}, nil))
+		require.Error(t, jd.UpdateJobStatus(ctx, embeddedStatuses(jobs, Succeeded.State), nil, []ParameterFilterT{{Name: "destination_id", Value: "otherDestinationID"}}))
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(jobs, Succeeded.State), []string{"GW"}, []ParameterFilterT{{Name: "destination_id", Value: "destinationID"}}))
+	})
+
+	t.Run("expired jobs are aborted", func(t *testing.T) {
+		var expiredJobs []*JobT
+		var expiredStatuses []*JobStatusT
+		jd := newTestEmbeddedJobsDB(t, t.TempDir(), WithEmbeddedExpiredJobsHandler(func(_ UpdateSafeTx, jobs []*JobT, statusList []*JobStatusT) error {
+			expiredJobs = append(expiredJobs, jobs...)
+			expiredStatuses = append(expiredStatuses, statusList...)
+			return nil
+		}))
+		defer jd.TearDown()
+
+		jobs := genEmbeddedJobs("ws", "GW", 4, 1)
+		jobs[0].ExpiryTime = time.Now().Add(-time.Minute)
+		jobs[1].ExpiryTime = time.Now().Add(time.Hour)
+		jobs[2].ExpiryTime = time.Now().Add(-time.Minute)
+		require.NoError(t, jd.Store(ctx, jobs))
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(jobs[2:3], Failed.State), nil, nil))
+
+		unprocessed, err := jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Len(t, unprocessed.Jobs, 2)
+		require.Equal(t, jobs[1].JobID, unprocessed.Jobs[0].JobID)
+		require.Equal(t, jobs[3].JobID, unprocessed.Jobs[1].JobID)
+		toRetry, err := jd.GetToRetry(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Empty(t, toRetry.Jobs)
+
+		require.Len(t, expiredJobs, 2)
+		require.Len(t, expiredStatuses, 2)
+		for _, status := range expiredStatuses {
+			require.Equal(t, Aborted.State, status.JobState)
+			require.Equal(t, "1114", status.ErrorCode)
+		}
+
+		unprocessed, err = jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Len(t, unprocessed.Jobs, 2)
+		require.Len(t, expiredJobs, 2, "expired jobs should be aborted only once")
+	})
+
+	t.Run("data and interrupted operations survive a restart", func(t *testing.T) {
+		path := t.TempDir()
+		jd := newTestEmbeddedJobsDB(t, path)
+		jobs := genEmbeddedJobs("ws", "GW", 3, 1)
+		require.NoError(t, jd.Store(ctx, jobs))
+		require.NoError(t, jd.addNewDS())
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(jobs, Succeeded.State), nil, nil))
+		// simulate a crash in the middle of dropping the first dataset
+		jd.JournalMarkStart(dropDSOperation, []byte(`{"from":1,"to":2}`))
+		jd.Close()
+
+		jd = newTestEmbeddedJobsDB(t, path)
+		defer jd.TearDown()
+		require.Len(t, jd.datasets, 1)
+		require.Equal(t, 2, jd.datasets[0].index)
+		require.Empty(t, jd.GetJournalEntries(dropDSOperation))
+
+		more := genEmbeddedJobs("ws", "GW", 1, 1)
+		require.NoError(t, jd.Store(ctx, more))
+		require.Greater(t, more[0].JobID, jobs[2].JobID, "job ids should keep increasing after a restart")
+	})
+}
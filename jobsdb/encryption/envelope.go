@@ -0,0 +1,165 @@
+// Package encryption provides envelope encryption of job payloads.
+//
+// Every payload is encrypted with a data key, using AES-256-GCM. Data keys are wrapped (encrypted) by a master key,
+// provided by a KeyProvider, and the wrapped data key is stored alongside the ciphertext. The id of the master key
+// needs to be stored by the caller next to the encrypted payload, so that payloads remain readable after the
+// master key is rotated.
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/rudderlabs/rudder-server/utils/logger"
+)
+
+var pkgLogger logger.LoggerI = logger.NewLogger().Child("jobsdb").Child("encryption")
+
+const (
+	envelopeVersion byte = 1
+	dataKeySize          = 32
+	// dataKeyMaxUsages is the number of payloads a data key is used for before a new one is generated
+	dataKeyMaxUsages = 1 << 20
+)
+
+// ErrInvalidEnvelope is returned when trying to decrypt data which were not encrypted by an Envelope
+var ErrInvalidEnvelope = errors.New("invalid encryption envelope")
+
+// Envelope encrypts and decrypts data using data keys wrapped by the master keys of a KeyProvider
+type Envelope struct {
+	keys KeyProvider
+
+	mu      sync.Mutex
+	dataKey *dataKey            // the data key currently used for encrypting
+	cache   map[string]*dataKey // unwrapped data keys used for decrypting, by wrapped key
+}
+
+type dataKey struct {
+	masterKeyID string
+	wrapped     []byte
+	key         []byte
+	usages      int
+}
+
+// NewEnvelope returns an Envelope using master keys of the provided KeyProvider
+func NewEnvelope(keys KeyProvider) *Envelope {
+	return &Envelope{keys: keys, cache: map[string]*dataKey{}}
+}
+
+/*
+Encrypt encrypts plaintext returning the ciphertext along with the id of the master key used for wrapping the data key.
+The ciphertext has the following format:
+
+	version (1 byte) | wrapped data key length (2 bytes) | wrapped data key | nonce | sealed plaintext
+*/
+func (e *Envelope) Encrypt(plaintext []byte) (ciphertext []byte, keyID string, err error) {
+	dk, err := e.currentDataKey()
+	if err != nil {
+		return nil, "", err
+	}
+	aead, err := newAEAD(dk.key)
+	if err != nil {
+		return nil, "", err
+	}
+	header := make([]byte, 3, 3+len(dk.wrapped))
+	header[0] = envelopeVersion
+	binary.BigEndian.PutUint16(header[1:], uint16(len(dk.wrapped)))
+	header = append(header, dk.wrapped...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, "", err
+	}
+	ciphertext = make([]byte, 0, len(header)+len(nonce)+len(plaintext)+aead.Overhead())
+	ciphertext = append(append(ciphertext, header...), nonce...)
+	// the header is authenticated too, so that the wrapped key cannot be swapped
+	ciphertext = aead.Seal(ciphertext, nonce, plaintext, header)
+	return ciphertext, dk.masterKeyID, nil
+}
+
+// Decrypt decrypts a ciphertext produced by Encrypt, using the master key with the provided id for unwrapping its data key
+func (e *Envelope) Decrypt(ciphertext []byte, keyID string) ([]byte, error) {
+	if len(ciphertext) < 3 || ciphertext[0] != envelopeVersion {
+		return nil, ErrInvalidEnvelope
+	}
+	wrappedLen := int(binary.BigEndian.Uint16(ciphertext[1:3]))
+	if len(ciphertext) < 3+wrappedLen {
+		return nil, ErrInvalidEnvelope
+	}
+	header, rest := ciphertext[:3+wrappedLen], ciphertext[3+wrappedLen:]
+	key, err := e.unwrap(keyID, header[3:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, ErrInvalidEnvelope
+	}
+	plaintext, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], header)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting with key %q: %w", keyID, err)
+	}
+	return plaintext, nil
+}
+
+// currentDataKey returns the data key to be used for encrypting, generating a new one if the current master key
+// changed or the data key has been used too many times
+func (e *Envelope) currentDataKey() (*dataKey, error) {
+	keyID, err := e.keys.CurrentKeyID()
+	if err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if dk := e.dataKey; dk != nil && dk.masterKeyID == keyID && dk.usages < dataKeyMaxUsages {
+		dk.usages++
+		return dk, nil
+	}
+
+	key := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	wrapped, err := e.keys.WrapKey(keyID, key)
+	if err != nil {
+		return nil, fmt.Errorf("wrapping data key with key %q: %w", keyID, err)
+	}
+	if len(wrapped) > 0xffff {
+		return nil, fmt.Errorf("wrapped data key too long: %d bytes", len(wrapped))
+	}
+	e.dataKey = &dataKey{masterKeyID: keyID, wrapped: wrapped, key: key, usages: 1}
+	e.cache[keyID+string(wrapped)] = e.dataKey
+	return e.dataKey, nil
+}
+
+func (e *Envelope) unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	cacheKey := keyID + string(wrapped)
+	e.mu.Lock()
+	dk, ok := e.cache[cacheKey]
+	e.mu.Unlock()
+	if ok {
+		return dk.key, nil
+	}
+	key, err := e.keys.UnwrapKey(keyID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapping data key with key %q: %w", keyID, err)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.cache) >= maxCachedDataKeys {
+		// data keys are cheap to unwrap again, no need for anything smarter than starting over
+		e.cache = map[string]*dataKey{}
+	}
+	e.cache[cacheKey] = &dataKey{masterKeyID: keyID, wrapped: wrapped, key: key}
+	return key, nil
+}
+
+// maxCachedDataKeys is the maximum number of unwrapped data keys kept in memory
+const maxCachedDataKeys = 1024
@@ -0,0 +1,106 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelope(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "keys.json")
+	writeKeyFile(t, keyFile, "key-1", "key-1")
+	keys, err := NewKeyFile(keyFile)
+	require.NoError(t, err)
+
+	envelope := NewEnvelope(keys)
+	plaintext := []byte(`{"userId":"user-1","traits":{"email":"user@example.com"}}`)
+
+	t.Run("encrypt and decrypt", func(t *testing.T) {
+		ciphertext, keyID, err := envelope.Encrypt(plaintext)
+		require.NoError(t, err)
+		require.Equal(t, "key-1", keyID)
+		require.NotContains(t, string(ciphertext), "user@example.com")
+
+		decrypted, err := envelope.Decrypt(ciphertext, keyID)
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+
+		decrypted, err = NewEnvelope(keys).Decrypt(ciphertext, keyID)
+		require.NoError(t, err, "a new envelope should be able to unwrap the data key")
+		require.Equal(t, plaintext, decrypted)
+	})
+
+	t.Run("tampered ciphertext", func(t *testing.T) {
+		ciphertext, keyID, err := envelope.Encrypt(plaintext)
+		require.NoError(t, err)
+		ciphertext[len(ciphertext)-1] ^= 0xff
+		_, err = envelope.Decrypt(ciphertext, keyID)
+		require.Error(t, err)
+
+		_, err = envelope.Decrypt(plaintext, keyID)
+		require.ErrorIs(t, err, ErrInvalidEnvelope)
+	})
+
+	t.Run("key rotation", func(t *testing.T) {
+		oldCiphertext, oldKeyID, err := envelope.Encrypt(plaintext)
+		require.NoError(t, err)
+
+		writeKeyFile(t, keyFile, "key-2", "key-1", "key-2")
+		require.NoError(t, keys.Reload())
+
+		ciphertext, keyID, err := envelope.Encrypt(plaintext)
+		require.NoError(t, err)
+		require.Equal(t, "key-2", keyID)
+
+		for ct, id := range map[string]string{string(oldCiphertext): oldKeyID, string(ciphertext): keyID} {
+			decrypted, err := NewEnvelope(keys).Decrypt([]byte(ct), id)
+			require.NoError(t, err)
+			require.Equal(t, plaintext, decrypted)
+		}
+
+		_, err = NewEnvelope(keys).Decrypt(ciphertext, "unknown")
+		require.ErrorIs(t, err, ErrKeyNotFound)
+	})
+
+	t.Run("invalid key file", func(t *testing.T) {
+		invalid := filepath.Join(t.TempDir(), "keys.json")
+		writeKeyFile(t, invalid, "key-3", "key-1")
+		_, err := NewKeyFile(invalid)
+		require.ErrorIs(t, err, ErrKeyNotFound, "current key should be present in the key file")
+
+		_, err = NewKeyFile(filepath.Join(t.TempDir(), "missing.json"))
+		require.Error(t, err)
+	})
+}
+
+// writeKeyFile writes a key file containing random keys with the provided ids
+func writeKeyFile(t *testing.T, path, current string, ids ...string) {
+	t.Helper()
+	contents := keyFileContents{Current: current, Keys: map[string]string{}}
+	existing, err := os.ReadFile(path)
+	if err == nil {
+		require.NoError(t, json.Unmarshal(existing, &contents))
+		contents.Current = current
+	}
+	for _, id := range ids {
+		if _, ok := contents.Keys[id]; ok {
+			continue
+		}
+		key := make([]byte, dataKeySize)
+		_, err := rand.Read(key)
+		require.NoError(t, err)
+		contents.Keys[id] = base64.StdEncoding.EncodeToString(key)
+	}
+	data, err := json.Marshal(contents)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	// make sure the modification time changes
+	modTime := time.Now().Add(time.Duration(len(contents.Keys)) * time.Second)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
@@ -0,0 +1,197 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrKeyNotFound is returned by a KeyProvider for unknown key ids
+var ErrKeyNotFound = errors.New("encryption key not found")
+
+// KeyProvider is a KMS-like provider of master keys, which are used for wrapping & unwrapping data keys.
+// Master keys never leave the provider, they are only referenced by their ids.
+type KeyProvider interface {
+	// CurrentKeyID returns the id of the master key which new data keys should be wrapped with
+	CurrentKeyID() (string, error)
+	// WrapKey encrypts a data key using the master key with the provided id
+	WrapKey(keyID string, dataKey []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key which was wrapped using the master key with the provided id
+	UnwrapKey(keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+/*
+KeyFile is a KeyProvider backed by a local json file containing base64 encoded 256-bit master keys, e.g.
+
+	{
+		"current": "key-2",
+		"keys": {
+			"key-1": "q2Vw...",
+			"key-2": "cG9p..."
+		}
+	}
+
+Keys are rotated by adding a new key to the file and pointing current to it. Old keys need to be kept in the file
+for as long as there is data encrypted with them. Changes to the file are picked up automatically.
+*/
+type KeyFile struct {
+	path string
+
+	mu        sync.RWMutex
+	modTime   time.Time
+	current   string
+	keys      map[string]cipher.AEAD
+	lastCheck time.Time
+}
+
+// keyFileCheckInterval is the minimum interval between two checks for changes of the key file
+const keyFileCheckInterval = 10 * time.Second
+
+type keyFileContents struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// NewKeyFile loads the master keys of the key file found in path
+func NewKeyFile(path string) (*KeyFile, error) {
+	kf := &KeyFile{path: path}
+	if err := kf.Reload(); err != nil {
+		return nil, err
+	}
+	return kf, nil
+}
+
+// Reload loads the key file again, if it was modified since the last time it was loaded
+func (kf *KeyFile) Reload() error {
+	info, err := os.Stat(kf.path)
+	if err != nil {
+		return fmt.Errorf("stat key file: %w", err)
+	}
+	kf.mu.Lock()
+	defer kf.mu.Unlock()
+	kf.lastCheck = time.Now()
+	if info.ModTime().Equal(kf.modTime) && kf.keys != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(kf.path)
+	if err != nil {
+		return fmt.Errorf("read key file: %w", err)
+	}
+	var contents keyFileContents
+	if err := json.Unmarshal(data, &contents); err != nil {
+		return fmt.Errorf("parse key file: %w", err)
+	}
+	keys := make(map[string]cipher.AEAD, len(contents.Keys))
+	for id, encoded := range contents.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("decode key %q: %w", id, err)
+		}
+		if len(key) != dataKeySize {
+			return fmt.Errorf("key %q should be %d bytes long, got %d", id, dataKeySize, len(key))
+		}
+		if keys[id], err = newAEAD(key); err != nil {
+			return fmt.Errorf("key %q: %w", id, err)
+		}
+	}
+	if _, ok := keys[contents.Current]; !ok {
+		return fmt.Errorf("current key %q: %w", contents.Current, ErrKeyNotFound)
+	}
+	kf.modTime = info.ModTime()
+	kf.current = contents.Current
+	kf.keys = keys
+	return nil
+}
+
+// CurrentKeyID returns the id of the current key of the key file
+func (kf *KeyFile) CurrentKeyID() (string, error) {
+	kf.reloadIfStale()
+	kf.mu.RLock()
+	defer kf.mu.RUnlock()
+	return kf.current, nil
+}
+
+// WrapKey encrypts dataKey with the key having the provided id
+func (kf *KeyFile) WrapKey(keyID string, dataKey []byte) ([]byte, error) {
+	aead, err := kf.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return seal(aead, dataKey)
+}
+
+// UnwrapKey decrypts wrappedKey with the key having the provided id
+func (kf *KeyFile) UnwrapKey(keyID string, wrappedKey []byte) ([]byte, error) {
+	aead, err := kf.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, wrappedKey)
+}
+
+func (kf *KeyFile) key(keyID string) (cipher.AEAD, error) {
+	kf.mu.RLock()
+	aead, ok := kf.keys[keyID]
+	kf.mu.RUnlock()
+	if ok {
+		return aead, nil
+	}
+	// the key might have been added to the file since the last time it was loaded
+	if err := kf.Reload(); err != nil {
+		return nil, err
+	}
+	kf.mu.RLock()
+	defer kf.mu.RUnlock()
+	if aead, ok = kf.keys[keyID]; !ok {
+		return nil, fmt.Errorf("key %q: %w", keyID, ErrKeyNotFound)
+	}
+	return aead, nil
+}
+
+// reloadIfStale reloads the key file if enough time has passed since the last check, keeping the
+// previously loaded keys in case of an error
+func (kf *KeyFile) reloadIfStale() {
+	kf.mu.RLock()
+	stale := time.Since(kf.lastCheck) > keyFileCheckInterval
+	kf.mu.RUnlock()
+	if stale {
+		if err := kf.Reload(); err != nil {
+			pkgLogger.Errorf("Reloading key file %q: %v", kf.path, err)
+		}
+	}
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext using a random nonce, which is prepended to the returned ciphertext
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts a ciphertext produced by seal
+func open(aead cipher.AEAD, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, nil)
+}
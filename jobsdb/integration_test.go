@@ -2,12 +2,16 @@ package jobsdb_test
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"sync"
 	"syscall"
@@ -22,6 +26,7 @@ import (
 	"github.com/rudderlabs/rudder-server/admin"
 	"github.com/rudderlabs/rudder-server/config"
 	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/jobsdb/encryption"
 	"github.com/rudderlabs/rudder-server/jobsdb/prebackup"
 	"github.com/rudderlabs/rudder-server/services/archiver"
 	"github.com/rudderlabs/rudder-server/services/stats"
@@ -159,404 +164,697 @@ func genJobStatuses(jobs []*jobsdb.JobT, state string) []*jobsdb.JobStatusT {
 	return statuses
 }
 
+// testBackend is a jobsdb backend the integration tests are run against
+type testBackend struct {
+	name string
+	// setup returns a started jobsdb of the backend, which is torn down along with the test
+	setup func(t *testing.T, opts testJobsDBOpts) jobsdb.JobsDB
+	// multiTenant returns the multi-tenant jobsdb reading the jobs of jd
+	multiTenant func(jd jobsdb.JobsDB) jobsdb.MultiTenantJobsDB
+	// multiTenantLegacy returns the legacy multi-tenant jobsdb reading the jobs of jd
+	multiTenantLegacy func(jd jobsdb.JobsDB) jobsdb.MultiTenantJobsDB
+}
+
+// testJobsDBOpts are the options of the jobsdb set up by a test backend
+type testJobsDBOpts struct {
+	tablePrefix        string
+	clearDB            bool
+	maxDSSize          int // the default maxDSSize is used if zero
+	triggerAddNewDS    chan time.Time
+	expiredJobsHandler jobsdb.ExpiredJobsHandler
+}
+
+var testBackends = []testBackend{
+	{
+		name: "postgres",
+		setup: func(t *testing.T, opts testJobsDBOpts) jobsdb.JobsDB {
+			jd := &jobsdb.HandleT{}
+			if opts.maxDSSize > 0 {
+				maxDSSize := opts.maxDSSize
+				jd.MaxDSSize = &maxDSSize
+			}
+			if opts.triggerAddNewDS != nil {
+				jd.TriggerAddNewDS = func() <-chan time.Time {
+					return opts.triggerAddNewDS
+				}
+			}
+			if opts.expiredJobsHandler != nil {
+				jobsdb.WithExpiredJobsHandler(opts.expiredJobsHandler)(jd)
+			}
+			queryFilters := jobsdb.QueryFiltersT{
+				CustomVal: true,
+			}
+			require.NoError(t, jd.Setup(jobsdb.ReadWrite, opts.clearDB, opts.tablePrefix, "", true, queryFilters, []prebackup.Handler{}))
+			t.Cleanup(jd.TearDown)
+			return jd
+		},
+		multiTenant: func(jd jobsdb.JobsDB) jobsdb.MultiTenantJobsDB {
+			return &jobsdb.MultiTenantHandleT{HandleT: jd.(*jobsdb.HandleT)}
+		},
+		multiTenantLegacy: func(jd jobsdb.JobsDB) jobsdb.MultiTenantJobsDB {
+			return &jobsdb.MultiTenantLegacy{HandleT: jd.(*jobsdb.HandleT)}
+		},
+	},
+	{
+		name: "embedded",
+		setup: func(t *testing.T, opts testJobsDBOpts) jobsdb.JobsDB {
+			embeddedOpts := []jobsdb.EmbeddedOptsFunc{jobsdb.WithEmbeddedClearDB(opts.clearDB), jobsdb.WithEmbeddedStats(nil)}
+			if opts.maxDSSize > 0 {
+				embeddedOpts = append(embeddedOpts, jobsdb.WithEmbeddedMaxDSSize(opts.maxDSSize))
+			}
+			if opts.expiredJobsHandler != nil {
+				embeddedOpts = append(embeddedOpts, jobsdb.WithEmbeddedExpiredJobsHandler(opts.expiredJobsHandler))
+			}
+			jd, err := jobsdb.NewEmbedded(opts.tablePrefix, t.TempDir(), embeddedOpts...)
+			require.NoError(t, err)
+			if opts.triggerAddNewDS != nil {
+				jd.TriggerAddNewDS = func() <-chan time.Time {
+					return opts.triggerAddNewDS
+				}
+			}
+			require.NoError(t, jd.Start())
+			t.Cleanup(jd.TearDown)
+			return jd
+		},
+		// the embedded jobsdb reads the jobs of all workspaces together, like the legacy multi-tenant jobsdb
+		multiTenant: func(jd jobsdb.JobsDB) jobsdb.MultiTenantJobsDB {
+			return jd.(*jobsdb.EmbeddedHandleT)
+		},
+		multiTenantLegacy: func(jd jobsdb.JobsDB) jobsdb.MultiTenantJobsDB {
+			return jd.(*jobsdb.EmbeddedHandleT)
+		},
+	},
+}
+
 func TestJobsDB(t *testing.T) {
 	initJobsDB()
 	stats.Setup()
 
 	migrationMode := ""
-
-	triggerAddNewDS := make(chan time.Time)
-	maxDSSize := 10
-	jobDB := jobsdb.HandleT{
-		MaxDSSize: &maxDSSize,
-		TriggerAddNewDS: func() <-chan time.Time {
-			return triggerAddNewDS
-		},
-	}
 	queryFilters := jobsdb.QueryFiltersT{
 		CustomVal: true,
 	}
 
-	err := jobDB.Setup(jobsdb.ReadWrite, false, "batch_rt", migrationMode, true, queryFilters, []prebackup.Handler{})
-	require.NoError(t, err)
-	defer jobDB.TearDown()
+	for _, backend := range testBackends {
+		backend := backend
+		t.Run(backend.name, func(t *testing.T) {
+			triggerAddNewDS := make(chan time.Time)
+			jobDB := backend.setup(t, testJobsDBOpts{tablePrefix: "batch_rt", maxDSSize: 10, triggerAddNewDS: triggerAddNewDS})
+
+			customVal := "MOCKDS"
+			sampleTestJob := jobsdb.JobT{
+				Parameters:   []byte(`{"batch_id":1,"source_id":"sourceID","source_job_run_id":""}`),
+				EventPayload: []byte(`{"receivedAt":"2021-06-06T20:26:39.598+05:30","writeKey":"writeKey","requestIP":"[::1]",  "batch": [{"anonymousId":"anon_id","channel":"android-sdk","context":{"app":{"build":"1","name":"RudderAndroidClient","namespace":"com.rudderlabs.android.sdk","version":"1.0"},"device":{"id":"49e4bdd1c280bc00","manufacturer":"Google","model":"Android SDK built for x86","name":"generic_x86"},"library":{"name":"com.rudderstack.android.sdk.core"},"locale":"en-US","network":{"carrier":"Android"},"screen":{"density":420,"height":1794,"width":1080},"traits":{"anonymousId":"49e4bdd1c280bc00"},"user_agent":"Dalvik/2.1.0 (Linux; U; Android 9; Android SDK built for x86 Build/PSR1.180720.075)"},"event":"Demo Track","integrations":{"All":true},"messageId":"b96f3d8a-7c26-4329-9671-4e3202f42f15","originalTimestamp":"2019-08-12T05:08:30.909Z","properties":{"category":"Demo Category","floatVal":4.501,"label":"Demo Label","testArray":[{"id":"elem1","value":"e1"},{"id":"elem2","value":"e2"}],"testMap":{"t1":"a","t2":4},"value":5},"rudderId":"a-292e-4e79-9880-f8009e0ae4a3","sentAt":"2019-08-12T05:08:30.909Z","type":"track"}]}`),
+				UserID:       "a-292e-4e79-9880-f8009e0ae4a3",
+				UUID:         uuid.Must(uuid.NewV4()),
+				CustomVal:    customVal,
+			}
 
-	customVal := "MOCKDS"
-	sampleTestJob := jobsdb.JobT{
-		Parameters:   []byte(`{"batch_id":1,"source_id":"sourceID","source_job_run_id":""}`),
-		EventPayload: []byte(`{"receivedAt":"2021-06-06T20:26:39.598+05:30","writeKey":"writeKey","requestIP":"[::1]",  "batch": [{"anonymousId":"anon_id","channel":"android-sdk","context":{"app":{"build":"1","name":"RudderAndroidClient","namespace":"com.rudderlabs.android.sdk","version":"1.0"},"device":{"id":"49e4bdd1c280bc00","manufacturer":"Google","model":"Android SDK built for x86","name":"generic_x86"},"library":{"name":"com.rudderstack.android.sdk.core"},"locale":"en-US","network":{"carrier":"Android"},"screen":{"density":420,"height":1794,"width":1080},"traits":{"anonymousId":"49e4bdd1c280bc00"},"user_agent":"Dalvik/2.1.0 (Linux; U; Android 9; Android SDK built for x86 Build/PSR1.180720.075)"},"event":"Demo Track","integrations":{"All":true},"messageId":"b96f3d8a-7c26-4329-9671-4e3202f42f15","originalTimestamp":"2019-08-12T05:08:30.909Z","properties":{"category":"Demo Category","floatVal":4.501,"label":"Demo Label","testArray":[{"id":"elem1","value":"e1"},{"id":"elem2","value":"e2"}],"testMap":{"t1":"a","t2":4},"value":5},"rudderId":"a-292e-4e79-9880-f8009e0ae4a3","sentAt":"2019-08-12T05:08:30.909Z","type":"track"}]}`),
-		UserID:       "a-292e-4e79-9880-f8009e0ae4a3",
-		UUID:         uuid.Must(uuid.NewV4()),
-		CustomVal:    customVal,
-	}
+			unprocessedJobEmpty, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
+				CustomValFilters: []string{customVal},
+				JobsLimit:        1,
+				ParameterFilters: []jobsdb.ParameterFilterT{},
+			})
+			require.NoError(t, err, "GetUnprocessed failed")
+			unprocessedListEmpty := unprocessedJobEmpty.Jobs
+			require.Equal(t, 0, len(unprocessedListEmpty))
+			err = jobDB.Store(context.Background(), []*jobsdb.JobT{&sampleTestJob})
+			require.NoError(t, err)
 
-	unprocessedJobEmpty, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
-		CustomValFilters: []string{customVal},
-		JobsLimit:        1,
-		ParameterFilters: []jobsdb.ParameterFilterT{},
-	})
-	require.NoError(t, err, "GetUnprocessed failed")
-	unprocessedListEmpty := unprocessedJobEmpty.Jobs
-	require.Equal(t, 0, len(unprocessedListEmpty))
-	err = jobDB.Store(context.Background(), []*jobsdb.JobT{&sampleTestJob})
-	require.NoError(t, err)
+			unprocessedJob, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
+				CustomValFilters: []string{customVal},
+				JobsLimit:        1,
+				ParameterFilters: []jobsdb.ParameterFilterT{},
+			})
+			require.NoError(t, err, "GetUnprocessed failed")
+			unprocessedList := unprocessedJob.Jobs
+			require.Equal(t, 1, len(unprocessedList))
 
-	unprocessedJob, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
-		CustomValFilters: []string{customVal},
-		JobsLimit:        1,
-		ParameterFilters: []jobsdb.ParameterFilterT{},
-	})
-	require.NoError(t, err, "GetUnprocessed failed")
-	unprocessedList := unprocessedJob.Jobs
-	require.Equal(t, 1, len(unprocessedList))
+			status := jobsdb.JobStatusT{
+				JobID:         unprocessedList[0].JobID,
+				JobState:      "succeeded",
+				AttemptNum:    1,
+				ExecTime:      time.Now(),
+				RetryTime:     time.Now(),
+				ErrorCode:     "202",
+				ErrorResponse: []byte(`{"success":"OK"}`),
+				Parameters:    []byte(`{}`),
+				WorkspaceId:   defaultWorkspaceID,
+			}
 
-	status := jobsdb.JobStatusT{
-		JobID:         unprocessedList[0].JobID,
-		JobState:      "succeeded",
-		AttemptNum:    1,
-		ExecTime:      time.Now(),
-		RetryTime:     time.Now(),
-		ErrorCode:     "202",
-		ErrorResponse: []byte(`{"success":"OK"}`),
-		Parameters:    []byte(`{}`),
-		WorkspaceId:   defaultWorkspaceID,
-	}
+			err = jobDB.UpdateJobStatus(context.Background(), []*jobsdb.JobStatusT{&status}, []string{customVal}, []jobsdb.ParameterFilterT{})
+			require.NoError(t, err)
 
-	err = jobDB.UpdateJobStatus(context.Background(), []*jobsdb.JobStatusT{&status}, []string{customVal}, []jobsdb.ParameterFilterT{})
-	require.NoError(t, err)
+			uj, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
+				CustomValFilters: []string{customVal},
+				JobsLimit:        1,
+				ParameterFilters: []jobsdb.ParameterFilterT{},
+			})
+			require.NoError(t, err, "GetUnprocessed failed")
+			unprocessedList = uj.Jobs
+			require.Equal(t, 0, len(unprocessedList))
 
-	uj, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
-		CustomValFilters: []string{customVal},
-		JobsLimit:        1,
-		ParameterFilters: []jobsdb.ParameterFilterT{},
-	})
-	require.NoError(t, err, "GetUnprocessed failed")
-	unprocessedList = uj.Jobs
-	require.Equal(t, 0, len(unprocessedList))
+			t.Run("multi events per job", func(t *testing.T) {
+				jobCountPerDS := 12
+				eventsPerJob := 60
 
-	t.Run("multi events per job", func(t *testing.T) {
-		jobCountPerDS := 12
-		eventsPerJob := 60
+				dsCount := 3
+				jobCount := dsCount * jobCountPerDS
 
-		dsCount := 3
-		jobCount := dsCount * jobCountPerDS
+				t.Logf("spread %d jobs into %d data sets", jobCount, dsCount)
+				for i := 0; i < dsCount; i++ {
+					require.NoError(t, jobDB.Store(context.Background(), genJobs(defaultWorkspaceID, customVal, jobCountPerDS, eventsPerJob)))
+					triggerAddNewDS <- time.Now()
+					triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
+				}
 
-		t.Logf("spread %d jobs into %d data sets", jobCount, dsCount)
-		for i := 0; i < dsCount; i++ {
-			require.NoError(t, jobDB.Store(context.Background(), genJobs(defaultWorkspaceID, customVal, jobCountPerDS, eventsPerJob)))
-			triggerAddNewDS <- time.Now()
-			triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
-		}
+				t.Log("GetUnprocessed with job count limit")
+				JobLimitJob, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
+					CustomValFilters: []string{customVal},
+					JobsLimit:        100,
+					ParameterFilters: []jobsdb.ParameterFilterT{},
+				})
+				require.NoError(t, err, "GetUnprocessed failed")
+				JobLimitList := JobLimitJob.Jobs
+				require.Equal(t, jobCount, len(JobLimitList))
+
+				t.Log("GetUnprocessed with event count limit")
+				eventLimitJob, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
+					CustomValFilters: []string{customVal},
+					JobsLimit:        100,
+					EventsLimit:      eventsPerJob * 20,
+					ParameterFilters: []jobsdb.ParameterFilterT{},
+				})
+				require.NoError(t, err, "GetUnprocessed failed")
+				eventLimitList := eventLimitJob.Jobs
+				require.Equal(t, 20, len(eventLimitList))
+				t.Log("GetUnprocessed jobs should have the expected event count")
+				for _, j := range eventLimitList {
+					require.Equal(t, eventsPerJob, j.EventCount)
+				}
 
-		t.Log("GetUnprocessed with job count limit")
-		JobLimitJob, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
-			CustomValFilters: []string{customVal},
-			JobsLimit:        100,
-			ParameterFilters: []jobsdb.ParameterFilterT{},
-		})
-		require.NoError(t, err, "GetUnprocessed failed")
-		JobLimitList := JobLimitJob.Jobs
-		require.Equal(t, jobCount, len(JobLimitList))
+				t.Log("Repeat read")
+				eventLimitListRepeat, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
+					CustomValFilters: []string{customVal},
+					JobsLimit:        100,
+					EventsLimit:      eventsPerJob * 20,
+					ParameterFilters: []jobsdb.ParameterFilterT{},
+				})
+				require.NoError(t, err, "GetUnprocessed failed")
+				require.Equal(t, 20, len(eventLimitListRepeat.Jobs))
+				require.Equal(t, eventLimitList, eventLimitListRepeat.Jobs)
+
+				statuses := make([]*jobsdb.JobStatusT, len(JobLimitList))
+
+				n := time.Now().Add(time.Hour * -1)
+				for i := range statuses {
+					statuses[i] = &jobsdb.JobStatusT{
+						JobID:         JobLimitList[i].JobID,
+						JobState:      jobsdb.Failed.State,
+						AttemptNum:    1,
+						ExecTime:      n,
+						RetryTime:     n,
+						ErrorResponse: []byte(`{"success":"OK"}`),
+						Parameters:    []byte(`{}`),
+						WorkspaceId:   defaultWorkspaceID,
+					}
+				}
+				t.Log("Mark some jobs as failed")
+				err = jobDB.UpdateJobStatus(context.Background(), statuses, []string{customVal}, []jobsdb.ParameterFilterT{})
+				require.NoError(t, err)
 
-		t.Log("GetUnprocessed with event count limit")
-		eventLimitJob, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
-			CustomValFilters: []string{customVal},
-			JobsLimit:        100,
-			EventsLimit:      eventsPerJob * 20,
-			ParameterFilters: []jobsdb.ParameterFilterT{},
-		})
-		require.NoError(t, err, "GetUnprocessed failed")
-		eventLimitList := eventLimitJob.Jobs
-		require.Equal(t, 20, len(eventLimitList))
-		t.Log("GetUnprocessed jobs should have the expected event count")
-		for _, j := range eventLimitList {
-			require.Equal(t, eventsPerJob, j.EventCount)
-		}
+				t.Log("GetUnprocessed with job count limit")
+				retryJobLimitList, err := jobDB.GetToRetry(context.Background(), jobsdb.GetQueryParamsT{
+					CustomValFilters: []string{customVal},
+					JobsLimit:        100,
+				})
+				require.NoError(t, err, "GetToRetry failed")
+				require.Equal(t, jobCount, len(retryJobLimitList.Jobs))
+
+				t.Log("GetToRetry with event count limit")
+				retryEventLimitList, err := jobDB.GetToRetry(context.Background(), jobsdb.GetQueryParamsT{
+					CustomValFilters: []string{customVal},
+					JobsLimit:        100,
+					EventsLimit:      eventsPerJob * 20,
+				})
+				require.NoError(t, err, "GetToRetry failed")
+				require.Equal(t, 20, len(retryEventLimitList.Jobs))
+				t.Log("GetToRetry jobs should have the expected event count")
+				for _, j := range eventLimitList {
+					require.Equal(t, eventsPerJob, j.EventCount)
+				}
+			})
 
-		t.Log("Repeat read")
-		eventLimitListRepeat, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
-			CustomValFilters: []string{customVal},
-			JobsLimit:        100,
-			EventsLimit:      eventsPerJob * 20,
-			ParameterFilters: []jobsdb.ParameterFilterT{},
-		})
-		require.NoError(t, err, "GetUnprocessed failed")
-		require.Equal(t, 20, len(eventLimitListRepeat.Jobs))
-		require.Equal(t, eventLimitList, eventLimitListRepeat.Jobs)
+			t.Run("DSoverflow", func(t *testing.T) {
+				customVal := "MOCKDS"
 
-		statuses := make([]*jobsdb.JobStatusT, len(JobLimitList))
+				triggerAddNewDS := make(chan time.Time)
 
-		n := time.Now().Add(time.Hour * -1)
-		for i := range statuses {
-			statuses[i] = &jobsdb.JobStatusT{
-				JobID:         JobLimitList[i].JobID,
-				JobState:      jobsdb.Failed.State,
-				AttemptNum:    1,
-				ExecTime:      n,
-				RetryTime:     n,
-				ErrorResponse: []byte(`{"success":"OK"}`),
-				Parameters:    []byte(`{}`),
-				WorkspaceId:   defaultWorkspaceID,
-			}
-		}
-		t.Log("Mark some jobs as failed")
-		err = jobDB.UpdateJobStatus(context.Background(), statuses, []string{customVal}, []jobsdb.ParameterFilterT{})
-		require.NoError(t, err)
+				jobDB := backend.setup(t, testJobsDBOpts{tablePrefix: "gw", clearDB: true, maxDSSize: 9, triggerAddNewDS: triggerAddNewDS})
 
-		t.Log("GetUnprocessed with job count limit")
-		retryJobLimitList, err := jobDB.GetToRetry(context.Background(), jobsdb.GetQueryParamsT{
-			CustomValFilters: []string{customVal},
-			JobsLimit:        100,
-		})
-		require.NoError(t, err, "GetToRetry failed")
-		require.Equal(t, jobCount, len(retryJobLimitList.Jobs))
+				jobCountPerDS := 10
+				eventsPerJob_ds1 := 60
+				eventsPerJob_ds2 := 20
 
-		t.Log("GetToRetry with event count limit")
-		retryEventLimitList, err := jobDB.GetToRetry(context.Background(), jobsdb.GetQueryParamsT{
-			CustomValFilters: []string{customVal},
-			JobsLimit:        100,
-			EventsLimit:      eventsPerJob * 20,
-		})
-		require.NoError(t, err, "GetToRetry failed")
-		require.Equal(t, 20, len(retryEventLimitList.Jobs))
-		t.Log("GetToRetry jobs should have the expected event count")
-		for _, j := range eventLimitList {
-			require.Equal(t, eventsPerJob, j.EventCount)
-		}
-	})
+				t.Log("First jobs table with jobs of 60 events, second with jobs of 20 events")
+				require.NoError(t, jobDB.Store(context.Background(), genJobs(defaultWorkspaceID, customVal, jobCountPerDS, eventsPerJob_ds1)))
+				triggerAddNewDS <- time.Now()
+				triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
 
-	t.Run("DSoverflow", func(t *testing.T) {
-		customVal := "MOCKDS"
+				require.NoError(t, jobDB.Store(context.Background(), genJobs(defaultWorkspaceID, customVal, jobCountPerDS, eventsPerJob_ds2)))
+				triggerAddNewDS <- time.Now()
+				triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
 
-		triggerAddNewDS := make(chan time.Time)
+				t.Log("GetUnprocessed with event count limit")
+				t.Log("Using event count that will cause spill-over, not exact for ds1, but remainder suitable for ds2")
+				trickyEventCount := (eventsPerJob_ds1 * (jobCountPerDS - 1)) + eventsPerJob_ds2
 
-		maxDSSize := 9
-		jobDB := jobsdb.HandleT{
-			MaxDSSize: &maxDSSize,
-			TriggerAddNewDS: func() <-chan time.Time {
-				return triggerAddNewDS
-			},
-		}
+				eventLimitList, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
+					CustomValFilters: []string{customVal},
+					JobsLimit:        100,
+					EventsLimit:      trickyEventCount,
+					ParameterFilters: []jobsdb.ParameterFilterT{},
+				})
+				require.NoError(t, err, "GetUnprocessed failed")
+				requireSequential(t, eventLimitList.Jobs)
+				require.Equal(t, jobCountPerDS-1, len(eventLimitList.Jobs))
 
-		err := jobDB.Setup(jobsdb.ReadWrite, true, "gw", migrationMode, true, queryFilters, []prebackup.Handler{})
-		require.NoError(t, err)
-		defer jobDB.TearDown()
+				t.Log("Prepare GetToRetry")
+				{
+					allJobs, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
+						CustomValFilters: []string{customVal},
+						JobsLimit:        1000,
+						ParameterFilters: []jobsdb.ParameterFilterT{},
+					})
+					require.NoError(t, err, "GetUnprocessed failed")
+
+					statuses := make([]*jobsdb.JobStatusT, len(allJobs.Jobs))
+					n := time.Now().Add(time.Hour * -1)
+					for i := range statuses {
+						statuses[i] = &jobsdb.JobStatusT{
+							JobID:         allJobs.Jobs[i].JobID,
+							JobState:      jobsdb.Failed.State,
+							AttemptNum:    1,
+							ExecTime:      n,
+							RetryTime:     n,
+							ErrorResponse: []byte(`{"success":"OK"}`),
+							Parameters:    []byte(`{}`),
+							WorkspaceId:   defaultWorkspaceID,
+						}
+					}
+					t.Log("Mark all jobs as failed")
+					err = jobDB.UpdateJobStatus(context.Background(), statuses, []string{customVal}, []jobsdb.ParameterFilterT{})
+					require.NoError(t, err)
+				}
 
-		jobCountPerDS := 10
-		eventsPerJob_ds1 := 60
-		eventsPerJob_ds2 := 20
+				t.Log("Test spill over with GetToRetry")
+				{
+					eventLimitList, err := jobDB.GetToRetry(context.Background(), jobsdb.GetQueryParamsT{
+						CustomValFilters: []string{customVal},
+						JobsLimit:        100,
+						EventsLimit:      trickyEventCount,
+						ParameterFilters: []jobsdb.ParameterFilterT{},
+					})
+					require.NoError(t, err, "GetToRetry failed")
+					requireSequential(t, eventLimitList.Jobs)
+					require.Equal(t, jobCountPerDS-1, len(eventLimitList.Jobs))
+				}
+			})
 
-		t.Log("First jobs table with jobs of 60 events, second with jobs of 20 events")
-		require.NoError(t, jobDB.Store(context.Background(), genJobs(defaultWorkspaceID, customVal, jobCountPerDS, eventsPerJob_ds1)))
-		triggerAddNewDS <- time.Now()
-		triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
+			t.Run("limit by total payload size", func(t *testing.T) {
+				customVal := "MOCKDS"
 
-		require.NoError(t, jobDB.Store(context.Background(), genJobs(defaultWorkspaceID, customVal, jobCountPerDS, eventsPerJob_ds2)))
-		triggerAddNewDS <- time.Now()
-		triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
+				triggerAddNewDS := make(chan time.Time)
 
-		t.Log("GetUnprocessed with event count limit")
-		t.Log("Using event count that will cause spill-over, not exact for ds1, but remainder suitable for ds2")
-		trickyEventCount := (eventsPerJob_ds1 * (jobCountPerDS - 1)) + eventsPerJob_ds2
+				jobDB := backend.setup(t, testJobsDBOpts{tablePrefix: "gw", clearDB: true, maxDSSize: 2, triggerAddNewDS: triggerAddNewDS})
 
-		eventLimitList, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
-			CustomValFilters: []string{customVal},
-			JobsLimit:        100,
-			EventsLimit:      trickyEventCount,
-			ParameterFilters: []jobsdb.ParameterFilterT{},
-		})
-		require.NoError(t, err, "GetUnprocessed failed")
-		requireSequential(t, eventLimitList.Jobs)
-		require.Equal(t, jobCountPerDS-1, len(eventLimitList.Jobs))
+				jobs := genJobs(defaultWorkspaceID, customVal, 2, 1)
+				require.NoError(t, jobDB.Store(context.Background(), jobs))
+				payloadSize, err := getPayloadSize(t, jobDB, jobs[0])
+				require.NoError(t, err)
+				triggerAddNewDS <- time.Now()
+				triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
+
+				require.NoError(t, jobDB.Store(context.Background(), genJobs(defaultWorkspaceID, customVal, 2, 1)))
+				triggerAddNewDS <- time.Now()
+				triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
+
+				payloadLimit := 3 * payloadSize
+				payloadLimitList, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
+					CustomValFilters: []string{customVal},
+					JobsLimit:        100,
+					PayloadSizeLimit: payloadLimit,
+					ParameterFilters: []jobsdb.ParameterFilterT{},
+				})
+				require.NoError(t, err, "GetUnprocessed failed")
+
+				requireSequential(t, payloadLimitList.Jobs)
+				require.Equal(t, 3, len(payloadLimitList.Jobs))
+			})
 
-		t.Log("Prepare GetToRetry")
-		{
-			allJobs, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
-				CustomValFilters: []string{customVal},
-				JobsLimit:        1000,
-				ParameterFilters: []jobsdb.ParameterFilterT{},
+			t.Run("querying with an payload size limit should return at least one job even if limit is exceeded", func(t *testing.T) {
+				customVal := "MOCKDS"
+				triggerAddNewDS := make(chan time.Time)
+
+				jobDB := backend.setup(t, testJobsDBOpts{tablePrefix: "gw", maxDSSize: 1, triggerAddNewDS: triggerAddNewDS})
+
+				jobs := genJobs(defaultWorkspaceID, customVal, 2, 1)
+				require.NoError(t, jobDB.Store(context.Background(), jobs))
+				payloadSize, err := getPayloadSize(t, jobDB, jobs[0])
+				require.NoError(t, err)
+
+				payloadLimit := payloadSize / 2
+				payloadLimitList, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
+					CustomValFilters: []string{customVal},
+					JobsLimit:        100,
+					PayloadSizeLimit: payloadLimit,
+					ParameterFilters: []jobsdb.ParameterFilterT{},
+				})
+				require.NoError(t, err, "GetUnprocessed failed")
+
+				requireSequential(t, payloadLimitList.Jobs)
+				require.Equal(t, 1, len(payloadLimitList.Jobs))
 			})
-			require.NoError(t, err, "GetUnprocessed failed")
 
-			statuses := make([]*jobsdb.JobStatusT, len(allJobs.Jobs))
-			n := time.Now().Add(time.Hour * -1)
-			for i := range statuses {
-				statuses[i] = &jobsdb.JobStatusT{
-					JobID:         allJobs.Jobs[i].JobID,
-					JobState:      jobsdb.Failed.State,
-					AttemptNum:    1,
-					ExecTime:      n,
-					RetryTime:     n,
-					ErrorResponse: []byte(`{"success":"OK"}`),
-					Parameters:    []byte(`{}`),
-					WorkspaceId:   defaultWorkspaceID,
-				}
-			}
-			t.Log("Mark all jobs as failed")
-			err = jobDB.UpdateJobStatus(context.Background(), statuses, []string{customVal}, []jobsdb.ParameterFilterT{})
-			require.NoError(t, err)
-		}
+			t.Run("querying with an event count limit should return at least one job even if limit is exceeded", func(t *testing.T) {
+				customVal := "MOCKDS"
+				triggerAddNewDS := make(chan time.Time)
 
-		t.Log("Test spill over with GetToRetry")
-		{
-			eventLimitList, err := jobDB.GetToRetry(context.Background(), jobsdb.GetQueryParamsT{
-				CustomValFilters: []string{customVal},
-				JobsLimit:        100,
-				EventsLimit:      trickyEventCount,
-				ParameterFilters: []jobsdb.ParameterFilterT{},
+				jobDB := backend.setup(t, testJobsDBOpts{tablePrefix: "gw", clearDB: true, maxDSSize: 1, triggerAddNewDS: triggerAddNewDS})
+
+				jobs := genJobs(defaultWorkspaceID, customVal, 2, 4)
+				require.NoError(t, jobDB.Store(context.Background(), jobs))
+
+				eventCountLimit := 1
+				eventLimitList, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
+					CustomValFilters: []string{customVal},
+					JobsLimit:        100,
+					EventsLimit:      eventCountLimit,
+					ParameterFilters: []jobsdb.ParameterFilterT{},
+				})
+				require.NoError(t, err, "GetUnprocessed failed")
+
+				requireSequential(t, eventLimitList.Jobs)
+				require.Equal(t, 1, len(eventLimitList.Jobs))
 			})
-			require.NoError(t, err, "GetToRetry failed")
-			requireSequential(t, eventLimitList.Jobs)
-			require.Equal(t, jobCountPerDS-1, len(eventLimitList.Jobs))
-		}
-	})
 
-	t.Run("limit by total payload size", func(t *testing.T) {
-		customVal := "MOCKDS"
+			t.Run("should stay within event count limits", func(t *testing.T) {
+				customVal := "MOCKDS"
+				triggerAddNewDS := make(chan time.Time)
+
+				jobDB := backend.setup(t, testJobsDBOpts{tablePrefix: "gw", clearDB: true, maxDSSize: 4, triggerAddNewDS: triggerAddNewDS})
+
+				jobs := []*jobsdb.JobT{}
+				jobs = append(jobs, genJobs(defaultWorkspaceID, customVal, 1, 1)...)
+				jobs = append(jobs, genJobs(defaultWorkspaceID, customVal, 1, 2)...)
+				jobs = append(jobs, genJobs(defaultWorkspaceID, customVal, 1, 3)...)
+				jobs = append(jobs, genJobs(defaultWorkspaceID, customVal, 1, 10)...)
+				require.NoError(t, jobDB.Store(context.Background(), jobs))
+				triggerAddNewDS <- time.Now()
+				triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
+
+				require.NoError(t, jobDB.Store(context.Background(), jobs))
+				triggerAddNewDS <- time.Now()
+				triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
+
+				eventCountLimit := 10
+				eventLimitList, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
+					CustomValFilters: []string{customVal},
+					JobsLimit:        100,
+					EventsLimit:      eventCountLimit,
+					ParameterFilters: []jobsdb.ParameterFilterT{},
+				})
+				require.NoError(t, err, "GetUnprocessed failed")
+
+				requireSequential(t, eventLimitList.Jobs)
+				require.Equal(t, 3, len(eventLimitList.Jobs))
+			})
+			t.Run("iterating over jobs spanning multiple datasets", func(t *testing.T) {
+				customVal := "MOCKDS"
+				triggerAddNewDS := make(chan time.Time)
+
+				jobDB := backend.setup(t, testJobsDBOpts{tablePrefix: "gw", clearDB: true, maxDSSize: 3, triggerAddNewDS: triggerAddNewDS})
+
+				jobs := genJobs(defaultWorkspaceID, customVal, 3, 1)
+				require.NoError(t, jobDB.Store(context.Background(), jobs))
+				triggerAddNewDS <- time.Now()
+				triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
+				require.NoError(t, jobDB.Store(context.Background(), genJobs(defaultWorkspaceID, customVal, 3, 1)))
+
+				params := jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal}, JobsLimit: 100}
+				allList, err := jobDB.GetUnprocessed(context.Background(), params) // read to get Ids
+				require.NoError(t, err, "GetUnprocessed failed")
+				jobs = allList.Jobs
+				require.Equal(t, 6, len(jobs))
+
+				params.AfterJobID = jobs[1].JobID
+				afterList, err := jobDB.GetUnprocessed(context.Background(), params)
+				require.NoError(t, err, "GetUnprocessed failed")
+				requireSequential(t, afterList.Jobs)
+				require.Equal(t, 4, len(afterList.Jobs))
+				require.Equal(t, jobs[2].JobID, afterList.Jobs[0].JobID)
+
+				params.AfterJobID = 0
+				it := jobsdb.NewJobsIterator(jobDB.GetUnprocessed, params, jobsdb.WithPageSize(2))
+				var iterated []*jobsdb.JobT
+				for it.Next(context.Background()) {
+					iterated = append(iterated, it.Job())
+				}
+				require.NoError(t, it.Err())
+				requireSequential(t, iterated)
+				require.Equal(t, 6, len(iterated))
+			})
 
-		triggerAddNewDS := make(chan time.Time)
+			t.Run("expired jobs are aborted", func(t *testing.T) {
+				customVal := "MOCKDS"
+				var expiredJobs []*jobsdb.JobT
+				expiredJobsHandler := func(tx jobsdb.UpdateSafeTx, jobs []*jobsdb.JobT, statusList []*jobsdb.JobStatusT) error {
+					if backend.name == "postgres" {
+						require.NotNil(t, tx.Tx())
+					}
+					require.Equal(t, len(jobs), len(statusList))
+					expiredJobs = append(expiredJobs, jobs...)
+					return nil
+				}
+				jobDB := backend.setup(t, testJobsDBOpts{tablePrefix: "gw", clearDB: true, expiredJobsHandler: expiredJobsHandler})
 
-		maxDSSize := 2
-		jobDB := jobsdb.HandleT{
-			MaxDSSize: &maxDSSize,
-			TriggerAddNewDS: func() <-chan time.Time {
-				return triggerAddNewDS
-			},
-		}
+				jobs := genJobs(defaultWorkspaceID, customVal, 3, 1)
+				jobs[0].ExpiryTime = time.Now().Add(-time.Minute)
+				jobs[1].ExpiryTime = time.Now().Add(time.Hour)
+				require.NoError(t, jobDB.Store(context.Background(), jobs))
 
-		err := jobDB.Setup(jobsdb.ReadWrite, true, "gw", migrationMode, true, queryFilters, []prebackup.Handler{})
-		require.NoError(t, err)
-		defer jobDB.TearDown()
+				params := jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal}, JobsLimit: 100}
+				unprocessed, err := jobDB.GetUnprocessed(context.Background(), params)
+				require.NoError(t, err)
+				require.Equal(t, 2, len(unprocessed.Jobs))
+				require.Equal(t, 1, len(expiredJobs))
+				require.Equal(t, jobs[0].UUID, expiredJobs[0].UUID)
 
-		jobs := genJobs(defaultWorkspaceID, customVal, 2, 1)
-		require.NoError(t, jobDB.Store(context.Background(), jobs))
-		payloadSize, err := getPayloadSize(t, &jobDB, jobs[0])
-		require.NoError(t, err)
-		triggerAddNewDS <- time.Now()
-		triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
+				unprocessed, err = jobDB.GetUnprocessed(context.Background(), params)
+				require.NoError(t, err)
+				require.Equal(t, 2, len(unprocessed.Jobs))
+				require.Equal(t, 1, len(expiredJobs), "expired jobs should be aborted only once")
+			})
 
-		require.NoError(t, jobDB.Store(context.Background(), genJobs(defaultWorkspaceID, customVal, 2, 1)))
-		triggerAddNewDS <- time.Now()
-		triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
+			t.Run("fetching jobs by priority", func(t *testing.T) {
+				customVal := "MOCKDS"
+				jobDB := backend.setup(t, testJobsDBOpts{tablePrefix: "rt", clearDB: true})
 
-		payloadLimit := 3 * payloadSize
-		payloadLimitList, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
-			CustomValFilters: []string{customVal},
-			JobsLimit:        100,
-			PayloadSizeLimit: payloadLimit,
-			ParameterFilters: []jobsdb.ParameterFilterT{},
-		})
-		require.NoError(t, err, "GetUnprocessed failed")
+				jobs := genJobs(defaultWorkspaceID, customVal, 3, 1)
+				jobs[0].Priority = jobsdb.LowPriority
+				jobs[2].Priority = jobsdb.HighPriority
+				require.NoError(t, jobDB.Store(context.Background(), jobs))
 
-		requireSequential(t, payloadLimitList.Jobs)
-		require.Equal(t, 3, len(payloadLimitList.Jobs))
-	})
+				params := jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal}, JobsLimit: 100, PriorityFilters: []int{jobsdb.HighPriority}}
+				high, err := jobDB.GetUnprocessed(context.Background(), params)
+				require.NoError(t, err)
+				require.Equal(t, 1, len(high.Jobs))
+				require.Equal(t, jobsdb.HighPriority, high.Jobs[0].Priority)
 
-	t.Run("querying with an payload size limit should return at least one job even if limit is exceeded", func(t *testing.T) {
-		customVal := "MOCKDS"
-		triggerAddNewDS := make(chan time.Time)
+				params.PriorityFilters = nil
+				byPriority, err := jobsdb.QueryByPriority(jobDB.GetUnprocessed)(context.Background(), params)
+				require.NoError(t, err)
+				require.Equal(t, 3, len(byPriority.Jobs))
+				require.Equal(t, jobs[2].UUID, byPriority.Jobs[0].UUID)
+				require.Equal(t, jobs[1].UUID, byPriority.Jobs[1].UUID)
+				require.Equal(t, jobs[0].UUID, byPriority.Jobs[2].UUID)
 
-		maxDSSize := 1
-		jobDB := jobsdb.HandleT{
-			MaxDSSize: &maxDSSize,
-			TriggerAddNewDS: func() <-chan time.Time {
-				return triggerAddNewDS
-			},
-		}
-		err := jobDB.Setup(jobsdb.ReadWrite, false, "gw", migrationMode, true, queryFilters, []prebackup.Handler{})
-		require.NoError(t, err)
-		defer jobDB.TearDown()
+				allJobs, err := jobsdb.GetAllJobsByPriority(context.Background(), backend.multiTenant(jobDB), map[string]int{defaultWorkspaceID: 2}, jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal}, JobsLimit: 2}, 10)
+				require.NoError(t, err)
+				require.Equal(t, 2, len(allJobs))
+				require.Equal(t, jobs[2].UUID, allJobs[0].UUID)
+				require.Equal(t, jobs[1].UUID, allJobs[1].UUID)
+			})
 
-		jobs := genJobs(defaultWorkspaceID, customVal, 2, 1)
-		require.NoError(t, jobDB.Store(context.Background(), jobs))
-		payloadSize, err := getPayloadSize(t, &jobDB, jobs[0])
-		require.NoError(t, err)
+			t.Run("statuses of jobs", func(t *testing.T) {
+				jobDB := backend.setup(t, testJobsDBOpts{tablePrefix: "gw", clearDB: true})
+				require.NoError(t, jobDB.Store(context.Background(), genJobs(defaultWorkspaceID, customVal, 10, 1)))
+				unprocessed, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal}, JobsLimit: 100})
+				require.NoError(t, err)
+				jobs := unprocessed.Jobs
+				require.Equal(t, 10, len(jobs))
+				requireSequential(t, jobs)
+
+				require.NoError(t, jobDB.UpdateJobStatus(context.Background(), genJobStatuses(jobs[:3], jobsdb.Failed.State), []string{customVal}, []jobsdb.ParameterFilterT{}))
+				require.NoError(t, jobDB.UpdateJobStatus(context.Background(), genJobStatuses(jobs[3:5], jobsdb.Waiting.State), []string{customVal}, []jobsdb.ParameterFilterT{}))
+				require.NoError(t, jobDB.UpdateJobStatus(context.Background(), genJobStatuses(jobs[5:7], jobsdb.Succeeded.State), []string{customVal}, []jobsdb.ParameterFilterT{}))
+
+				// statuses are retried after their retry time, which is now
+				time.Sleep(10 * time.Millisecond)
+				params := jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal}, JobsLimit: 100}
+				unprocessed, err = jobDB.GetUnprocessed(context.Background(), params)
+				require.NoError(t, err)
+				require.Equal(t, 3, len(unprocessed.Jobs))
+				failed, err := jobDB.GetToRetry(context.Background(), params)
+				require.NoError(t, err)
+				require.Equal(t, 3, len(failed.Jobs))
+				require.Equal(t, jobsdb.Failed.State, failed.Jobs[0].LastJobStatus.JobState)
+				waiting, err := jobDB.GetWaiting(context.Background(), params)
+				require.NoError(t, err)
+				require.Equal(t, 2, len(waiting.Jobs))
 
-		payloadLimit := payloadSize / 2
-		payloadLimitList, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
-			CustomValFilters: []string{customVal},
-			JobsLimit:        100,
-			PayloadSizeLimit: payloadLimit,
-			ParameterFilters: []jobsdb.ParameterFilterT{},
-		})
-		require.NoError(t, err, "GetUnprocessed failed")
+				pileUp, err := jobDB.GetPileUpCounts(context.Background())
+				require.NoError(t, err)
+				require.Equal(t, 8, pileUp[defaultWorkspaceID][customVal])
 
-		requireSequential(t, payloadLimitList.Jobs)
-		require.Equal(t, 1, len(payloadLimitList.Jobs))
-	})
+				require.NoError(t, jobDB.UpdateJobStatus(context.Background(), genJobStatuses(unprocessed.Jobs, jobsdb.Executing.State), []string{customVal}, []jobsdb.ParameterFilterT{}))
+				executing, err := jobDB.GetExecuting(context.Background(), params)
+				require.NoError(t, err)
+				require.Equal(t, 3, len(executing.Jobs))
+				jobDB.DeleteExecuting()
+				executing, err = jobDB.GetExecuting(context.Background(), params)
+				require.NoError(t, err)
+				require.Equal(t, 0, len(executing.Jobs))
+				unprocessed, err = jobDB.GetUnprocessed(context.Background(), params)
+				require.NoError(t, err)
+				require.Equal(t, 3, len(unprocessed.Jobs), "jobs whose executing status got deleted should be unprocessed again")
+			})
 
-	t.Run("querying with an event count limit should return at least one job even if limit is exceeded", func(t *testing.T) {
-		customVal := "MOCKDS"
-		triggerAddNewDS := make(chan time.Time)
+			t.Run("empty results of partial queries", func(t *testing.T) {
+				jobDB := backend.setup(t, testJobsDBOpts{tablePrefix: "gw", clearDB: true})
+				jobs := genJobs(defaultWorkspaceID, customVal, 4, 1)
+				for i, job := range jobs {
+					job.Parameters = []byte(fmt.Sprintf(`{"source_id":"source-%[1]d","destination_id":"destination-%[1]d"}`, i%2))
+				}
 
-		maxDSSize := 1
-		jobDB := jobsdb.HandleT{
-			MaxDSSize: &maxDSSize,
-			TriggerAddNewDS: func() <-chan time.Time {
-				return triggerAddNewDS
-			},
-		}
-		err := jobDB.Setup(jobsdb.ReadWrite, true, "gw", migrationMode, true, queryFilters, []prebackup.Handler{})
-		require.NoError(t, err)
-		defer jobDB.TearDown()
+				destination1 := []jobsdb.ParameterFilterT{{Name: "destination_id", Value: "destination-1"}}
+				unprocessed, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal}, ParameterFilters: destination1, JobsLimit: 100})
+				require.NoError(t, err)
+				require.Equal(t, 0, len(unprocessed.Jobs))
 
-		jobs := genJobs(defaultWorkspaceID, customVal, 2, 4)
-		require.NoError(t, jobDB.Store(context.Background(), jobs))
+				require.NoError(t, jobDB.Store(context.Background(), append(jobs, genJobs(defaultWorkspaceID, "OTHER", 3, 1)...)))
+				unprocessed, err = jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal}, ParameterFilters: destination1, JobsLimit: 100})
+				require.NoError(t, err)
+				require.Equal(t, 2, len(unprocessed.Jobs), "jobs stored after an empty result should be returned")
+				lastJobID := unprocessed.Jobs[1].JobID
+				require.NoError(t, jobDB.UpdateJobStatus(context.Background(), genJobStatuses(unprocessed.Jobs, jobsdb.Aborted.State), []string{customVal}, destination1))
 
-		eventCountLimit := 1
-		eventLimitList, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
-			CustomValFilters: []string{customVal},
-			JobsLimit:        100,
-			EventsLimit:      eventCountLimit,
-			ParameterFilters: []jobsdb.ParameterFilterT{},
-		})
-		require.NoError(t, err, "GetUnprocessed failed")
+				unprocessed, err = jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal}, ParameterFilters: destination1, JobsLimit: 100})
+				require.NoError(t, err)
+				require.Equal(t, 0, len(unprocessed.Jobs))
+				unprocessed, err = jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal}, JobsLimit: 100, AfterJobID: lastJobID})
+				require.NoError(t, err)
+				require.Equal(t, 0, len(unprocessed.Jobs))
+				unprocessed, err = jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal}, JobsLimit: 100})
+				require.NoError(t, err)
+				require.Equal(t, 2, len(unprocessed.Jobs), "empty results of partial queries shouldn't hide the other jobs")
+				unprocessed, err = jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{CustomValFilters: []string{"OTHER"}, JobsLimit: 100})
+				require.NoError(t, err)
+				require.Equal(t, 3, len(unprocessed.Jobs))
+			})
 
-		requireSequential(t, eventLimitList.Jobs)
-		require.Equal(t, 1, len(eventLimitList.Jobs))
-	})
+			t.Run("storing with retry each", func(t *testing.T) {
+				jobDB := backend.setup(t, testJobsDBOpts{tablePrefix: "gw", clearDB: true})
+				jobs := genJobs(defaultWorkspaceID, customVal, 3, 1)
+				jobs[1].EventPayload = []byte(`{"invalid": json}`)
+				errorMessages := jobDB.StoreWithRetryEach(context.Background(), jobs)
+				require.Equal(t, 1, len(errorMessages))
+				require.Contains(t, errorMessages, jobs[1].UUID)
 
-	t.Run("should stay within event count limits", func(t *testing.T) {
-		customVal := "MOCKDS"
-		triggerAddNewDS := make(chan time.Time)
+				unprocessed, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal}, JobsLimit: 100})
+				require.NoError(t, err)
+				require.Equal(t, 2, len(unprocessed.Jobs))
+			})
 
-		maxDSSize := 4
-		jobDB := jobsdb.HandleT{
-			MaxDSSize: &maxDSSize,
-			TriggerAddNewDS: func() <-chan time.Time {
-				return triggerAddNewDS
-			},
+			t.Run("jobs stored in a rolled back transaction", func(t *testing.T) {
+				jobDB := backend.setup(t, testJobsDBOpts{tablePrefix: "gw", clearDB: true})
+				err := jobDB.WithStoreSafeTx(func(tx jobsdb.StoreSafeTx) error {
+					if err := jobDB.StoreInTx(context.Background(), tx, genJobs(defaultWorkspaceID, customVal, 2, 1)); err != nil {
+						return err
+					}
+					return context.Canceled
+				})
+				require.ErrorIs(t, err, context.Canceled)
+
+				unprocessed, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal}, JobsLimit: 100})
+				require.NoError(t, err)
+				require.Equal(t, 0, len(unprocessed.Jobs))
+			})
+		})
+	}
+
+	t.Run("event payloads are encrypted at rest", func(t *testing.T) {
+		customVal := "MOCKDS"
+		keyFile := filepath.Join(t.TempDir(), "keys.json")
+		writeKeyFile := func(current string, keys map[string]string) {
+			data, err := json.Marshal(map[string]interface{}{"current": current, "keys": keys})
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(keyFile, data, 0o600))
+			modTime := time.Now().Add(time.Duration(len(keys)) * time.Second)
+			require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
 		}
+		newKey := func() string {
+			key := make([]byte, 32)
+			_, err := rand.Read(key)
+			require.NoError(t, err)
+			return base64.StdEncoding.EncodeToString(key)
+		}
+		masterKeys := map[string]string{"key-1": newKey()}
+		writeKeyFile("key-1", masterKeys)
+		keys, err := encryption.NewKeyFile(keyFile)
+		require.NoError(t, err)
 
-		err := jobDB.Setup(jobsdb.ReadWrite, true, "gw", migrationMode, true, queryFilters, []prebackup.Handler{})
+		jobDB := jobsdb.HandleT{}
+		jobsdb.WithPayloadEncryption(keys)(&jobDB)
+		err = jobDB.Setup(jobsdb.ReadWrite, true, "gw", migrationMode, true, queryFilters, []prebackup.Handler{})
 		require.NoError(t, err)
 		defer jobDB.TearDown()
 
-		jobs := []*jobsdb.JobT{}
-		jobs = append(jobs, genJobs(defaultWorkspaceID, customVal, 1, 1)...)
-		jobs = append(jobs, genJobs(defaultWorkspaceID, customVal, 1, 2)...)
-		jobs = append(jobs, genJobs(defaultWorkspaceID, customVal, 1, 3)...)
-		jobs = append(jobs, genJobs(defaultWorkspaceID, customVal, 1, 10)...)
-		require.NoError(t, jobDB.Store(context.Background(), jobs))
-		triggerAddNewDS <- time.Now()
-		triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
+		jobs := genJobs(defaultWorkspaceID, customVal, 2, 1)
+		require.NoError(t, jobDB.Store(context.Background(), jobs[:1]))
 
-		require.NoError(t, jobDB.Store(context.Background(), jobs))
-		triggerAddNewDS <- time.Now()
-		triggerAddNewDS <- time.Now() // Second time, waits for the first loop to finish
+		// rotating the master key
+		masterKeys["key-2"] = newKey()
+		writeKeyFile("key-2", masterKeys)
+		require.NoError(t, keys.Reload())
+		require.NoError(t, jobDB.Store(context.Background(), jobs[1:]))
 
-		eventCountLimit := 10
-		eventLimitList, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{
-			CustomValFilters: []string{customVal},
-			JobsLimit:        100,
-			EventsLimit:      eventCountLimit,
-			ParameterFilters: []jobsdb.ParameterFilterT{},
-		})
-		require.NoError(t, err, "GetUnprocessed failed")
+		rows, err := db.Query(`SELECT event_payload::text, encryption_key_id FROM gw_jobs_1 ORDER BY job_id`)
+		require.NoError(t, err)
+		defer func() { _ = rows.Close() }()
+		var keyIDs []string
+		for rows.Next() {
+			var payload, keyID string
+			require.NoError(t, rows.Scan(&payload, &keyID))
+			require.NotContains(t, payload, "Demo Track", "payload shouldn't be stored in plaintext")
+			keyIDs = append(keyIDs, keyID)
+		}
+		require.NoError(t, rows.Err())
+		require.Equal(t, []string{"key-1", "key-2"}, keyIDs)
+
+		unprocessed, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal}, JobsLimit: 100})
+		require.NoError(t, err)
+		require.Equal(t, 2, len(unprocessed.Jobs))
+		for i := range unprocessed.Jobs {
+			require.JSONEq(t, string(jobs[i].EventPayload), string(unprocessed.Jobs[i].EventPayload), "jobs encrypted with rotated keys should remain readable")
+		}
 
-		requireSequential(t, eventLimitList.Jobs)
-		require.Equal(t, 3, len(eventLimitList.Jobs))
+		invalid := genJobs(defaultWorkspaceID, customVal, 1, 1)
+		invalid[0].EventPayload = []byte(`{"invalid":`)
+		errorMessages := jobDB.StoreWithRetryEach(context.Background(), invalid)
+		require.Equal(t, "Invalid JSON", errorMessages[invalid[0].UUID])
 	})
+
 	t.Run("should create a new dataset after maxDSRetentionPeriod", func(t *testing.T) {
 		customVal := "MOCKDS"
 		triggerAddNewDS := make(chan time.Time)
@@ -652,92 +950,83 @@ func TestJobsDB(t *testing.T) {
 func TestMultiTenantLegacyGetAllJobs(t *testing.T) {
 	initJobsDB()
 	stats.Setup()
-	migrationMode := ""
-
-	triggerAddNewDS := make(chan time.Time)
-	maxDSSize := 10
-	jobDB := jobsdb.HandleT{
-		MaxDSSize: &maxDSSize,
-		TriggerAddNewDS: func() <-chan time.Time {
-			return triggerAddNewDS
-		},
-	}
 
-	customVal := "MTL"
-	queryFilters := jobsdb.QueryFiltersT{
-		CustomVal: true,
-	}
-	err := jobDB.Setup(jobsdb.ReadWrite, false, strings.ToLower(customVal), migrationMode, true, queryFilters, []prebackup.Handler{})
-	require.NoError(t, err)
-	defer jobDB.TearDown()
-
-	mtl := jobsdb.MultiTenantLegacy{HandleT: &jobDB}
-
-	eventsPerJob := 10
-	// Create 30 jobs
-	jobs := genJobs(defaultWorkspaceID, customVal, 30, eventsPerJob)
-	require.NoError(t, jobDB.Store(context.Background(), jobs))
-	payloadSize, err := getPayloadSize(t, &jobDB, jobs[0])
-	require.NoError(t, err)
-	j, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{JobsLimit: 100}) // read to get Ids
-	require.NoError(t, err, "failed to get unprocessed jobs")
-	jobs = j.Jobs
-	require.Equal(t, 30, len(jobs), "should get all 30 jobs")
-
-	// Mark 1-10 as failed
-	require.NoError(t, jobDB.UpdateJobStatus(context.Background(), genJobStatuses(jobs[0:10], jobsdb.Failed.State), []string{customVal}, []jobsdb.ParameterFilterT{}))
-
-	// Mark 11-20 as waiting
-	require.NoError(t, jobDB.UpdateJobStatus(context.Background(), genJobStatuses(jobs[10:20], jobsdb.Waiting.State), []string{customVal}, []jobsdb.ParameterFilterT{}))
-
-	t.Run("GetAllJobs with large limits", func(t *testing.T) {
-		params := jobsdb.GetQueryParamsT{JobsLimit: 30}
-		allJobs, err := mtl.GetAllJobs(context.Background(), map[string]int{defaultWorkspaceID: 30}, params, 0)
-		require.NoError(t, err, "failed to get all jobs")
-		require.Equal(t, 30, len(allJobs), "should get all 30 jobs")
-	})
+	for _, backend := range testBackends {
+		backend := backend
+		t.Run(backend.name, func(t *testing.T) {
+			customVal := "MTL"
+			jobDB := backend.setup(t, testJobsDBOpts{tablePrefix: strings.ToLower(customVal), maxDSSize: 10, triggerAddNewDS: make(chan time.Time)})
+
+			mtl := backend.multiTenantLegacy(jobDB)
+			eventsPerJob := 10
+			// Create 30 jobs
+			jobs := genJobs(defaultWorkspaceID, customVal, 30, eventsPerJob)
+			require.NoError(t, jobDB.Store(context.Background(), jobs))
+			payloadSize, err := getPayloadSize(t, jobDB, jobs[0])
+			require.NoError(t, err)
+			j, err := jobDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{JobsLimit: 100}) // read to get Ids
+			require.NoError(t, err, "failed to get unprocessed jobs")
+			jobs = j.Jobs
+			require.Equal(t, 30, len(jobs), "should get all 30 jobs")
+
+			// Mark 1-10 as failed
+			require.NoError(t, jobDB.UpdateJobStatus(context.Background(), genJobStatuses(jobs[0:10], jobsdb.Failed.State), []string{customVal}, []jobsdb.ParameterFilterT{}))
+
+			// Mark 11-20 as waiting
+			require.NoError(t, jobDB.UpdateJobStatus(context.Background(), genJobStatuses(jobs[10:20], jobsdb.Waiting.State), []string{customVal}, []jobsdb.ParameterFilterT{}))
+
+			t.Run("GetAllJobs with large limits", func(t *testing.T) {
+				params := jobsdb.GetQueryParamsT{JobsLimit: 30}
+				allJobs, err := mtl.GetAllJobs(context.Background(), map[string]int{defaultWorkspaceID: 30}, params, 0)
+				require.NoError(t, err, "failed to get all jobs")
+				require.Equal(t, 30, len(allJobs), "should get all 30 jobs")
+			})
 
-	t.Run("GetAllJobs with only jobs limit", func(t *testing.T) {
-		jobsLimit := 10
-		params := jobsdb.GetQueryParamsT{JobsLimit: 10}
-		allJobs, err := mtl.GetAllJobs(context.Background(), map[string]int{defaultWorkspaceID: jobsLimit}, params, 0)
-		require.NoError(t, err, "failed to get all jobs")
-		require.Truef(t, len(allJobs)-jobsLimit == 0, "should get %d jobs", jobsLimit)
-	})
+			t.Run("GetAllJobs with only jobs limit", func(t *testing.T) {
+				jobsLimit := 10
+				params := jobsdb.GetQueryParamsT{JobsLimit: 10}
+				allJobs, err := mtl.GetAllJobs(context.Background(), map[string]int{defaultWorkspaceID: jobsLimit}, params, 0)
+				require.NoError(t, err, "failed to get all jobs")
+				require.Truef(t, len(allJobs)-jobsLimit == 0, "should get %d jobs", jobsLimit)
+			})
 
-	t.Run("GetAllJobs with events limit", func(t *testing.T) {
-		jobsLimit := 10
-		params := jobsdb.GetQueryParamsT{JobsLimit: 10, EventsLimit: 3 * eventsPerJob}
-		allJobs, err := mtl.GetAllJobs(context.Background(), map[string]int{defaultWorkspaceID: jobsLimit}, params, 0)
-		require.NoError(t, err, "failed to get all jobs")
-		require.Equal(t, 3, len(allJobs), "should get 3 jobs")
-	})
+			t.Run("GetAllJobs with events limit", func(t *testing.T) {
+				jobsLimit := 10
+				params := jobsdb.GetQueryParamsT{JobsLimit: 10, EventsLimit: 3 * eventsPerJob}
+				allJobs, err := mtl.GetAllJobs(context.Background(), map[string]int{defaultWorkspaceID: jobsLimit}, params, 0)
+				require.NoError(t, err, "failed to get all jobs")
+				require.Equal(t, 3, len(allJobs), "should get 3 jobs")
+			})
 
-	t.Run("GetAllJobs with events limit less than the events of the first job get one job", func(t *testing.T) {
-		jobsLimit := 10
-		params := jobsdb.GetQueryParamsT{JobsLimit: jobsLimit, EventsLimit: eventsPerJob - 1}
-		allJobs, err := mtl.GetAllJobs(context.Background(), map[string]int{defaultWorkspaceID: jobsLimit}, params, 0)
-		require.NoError(t, err, "failed to get all jobs")
-		require.Equal(t, 1, len(allJobs), "should get 1 overflown job")
-	})
+			t.Run("GetAllJobs with events limit less than the events of the first job get one job", func(t *testing.T) {
+				jobsLimit := 10
+				params := jobsdb.GetQueryParamsT{JobsLimit: jobsLimit, EventsLimit: eventsPerJob - 1}
+				allJobs, err := mtl.GetAllJobs(context.Background(), map[string]int{defaultWorkspaceID: jobsLimit}, params, 0)
+				require.NoError(t, err, "failed to get all jobs")
+				require.Equal(t, 1, len(allJobs), "should get 1 overflown job")
+			})
 
-	t.Run("GetAllJobs with payload limit", func(t *testing.T) {
-		jobsLimit := 10
-		params := jobsdb.GetQueryParamsT{JobsLimit: jobsLimit, PayloadSizeLimit: 3 * payloadSize}
-		allJobs, err := mtl.GetAllJobs(context.Background(), map[string]int{defaultWorkspaceID: jobsLimit}, params, 0)
-		require.NoError(t, err, "failed to get all jobs")
-		require.Equal(t, 3, len(allJobs), "should get 3 jobs")
-	})
+			t.Run("GetAllJobs with payload limit", func(t *testing.T) {
+				jobsLimit := 10
+				params := jobsdb.GetQueryParamsT{JobsLimit: jobsLimit, PayloadSizeLimit: 3 * payloadSize}
+				allJobs, err := mtl.GetAllJobs(context.Background(), map[string]int{defaultWorkspaceID: jobsLimit}, params, 0)
+				require.NoError(t, err, "failed to get all jobs")
+				require.Equal(t, 3, len(allJobs), "should get 3 jobs")
+			})
 
-	t.Run("GetAllJobs with payload limit less than the payload size should get one job", func(t *testing.T) {
-		jobsLimit := 10
-		params := jobsdb.GetQueryParamsT{JobsLimit: jobsLimit, PayloadSizeLimit: payloadSize - 1}
-		allJobs, err := mtl.GetAllJobs(context.Background(), map[string]int{defaultWorkspaceID: jobsLimit}, params, 0)
-		require.NoError(t, err, "failed to get all jobs")
-		require.Equal(t, 1, len(allJobs), "should get 1 overflown job")
-	})
+			t.Run("GetAllJobs with payload limit less than the payload size should get one job", func(t *testing.T) {
+				jobsLimit := 10
+				params := jobsdb.GetQueryParamsT{JobsLimit: jobsLimit, PayloadSizeLimit: payloadSize - 1}
+				allJobs, err := mtl.GetAllJobs(context.Background(), map[string]int{defaultWorkspaceID: jobsLimit}, params, 0)
+				require.NoError(t, err, "failed to get all jobs")
+				require.Equal(t, 1, len(allJobs), "should get 1 overflown job")
+			})
+		})
+	}
 }
 
+// TestMultiTenantGetAllJobs only runs against postgres, as the embedded jobsdb applies the sum of the workspace limits
+// to the jobs of all workspaces, like the legacy multi-tenant jobsdb (see TestMultiTenantLegacyGetAllJobs)
 func TestMultiTenantGetAllJobs(t *testing.T) {
 	initJobsDB()
 	stats.Setup()
@@ -845,6 +1134,31 @@ func TestMultiTenantGetAllJobs(t *testing.T) {
 		require.NoError(t, err, "failed to get all jobs")
 		require.Equal(t, 3, len(allJobs), "should get limit+1 jobs")
 	})
+
+	t.Run("GetAllJobs after a job id", func(t *testing.T) {
+		workspaceLimits := map[string]int{
+			workspaceA: 30,
+			workspaceB: 30,
+			workspaceC: 30,
+		}
+		afterJobID := workspaceCJobs[0].JobID
+		params := jobsdb.GetQueryParamsT{JobsLimit: 90, AfterJobID: afterJobID}
+		allJobs, err := mtl.GetAllJobs(context.Background(), workspaceLimits, params, 100)
+		require.NoError(t, err, "failed to get all jobs")
+		require.Equal(t, 29, len(allJobs), "should get the jobs of workspace C after its first job")
+		for _, job := range allJobs {
+			require.Greater(t, job.JobID, afterJobID)
+		}
+
+		params.AfterJobID = allJobs[len(allJobs)-1].JobID
+		allJobs, err = mtl.GetAllJobs(context.Background(), workspaceLimits, params, 100)
+		require.NoError(t, err, "failed to get all jobs")
+		require.Empty(t, allJobs, "should get no jobs after the last one")
+
+		allJobs, err = mtl.GetAllJobs(context.Background(), workspaceLimits, jobsdb.GetQueryParamsT{JobsLimit: 90}, 100)
+		require.NoError(t, err, "failed to get all jobs")
+		require.Equal(t, 90, len(allJobs), "an empty result after a job id is not cached")
+	})
 }
 
 func TestStoreAndUpdateStatusExceedingAnalyzeThreshold(t *testing.T) {
@@ -1294,31 +1608,17 @@ func consume(t testing.TB, db *jobsdb.HandleT, count int) {
 	require.NoError(t, err)
 }
 
+// getPayloadSize returns the payload size of the stored job, as used by the jobsdb for the payload size limits of its queries
 func getPayloadSize(t *testing.T, jobsDB jobsdb.JobsDB, job *jobsdb.JobT) (int64, error) {
-	var size int64
-	var tables []string
-	err := jobsDB.WithTx(func(tx *sql.Tx) error {
-		rows, err := tx.Query(fmt.Sprintf("SELECT tablename FROM pg_catalog.pg_tables where tablename like '%s_jobs_%%'", jobsDB.Identifier()))
-		require.NoError(t, err)
-		for rows.Next() {
-			var table string
-			err = rows.Scan(&table)
-			require.NoError(t, err)
-			tables = append(tables, table)
-		}
-		_ = rows.Close()
-
-		for _, table := range tables {
-			stmt, err := tx.Prepare(fmt.Sprintf("select pg_column_size(event_payload) from %s where uuid=$1", table))
-			require.NoError(t, err)
-			err = stmt.QueryRow(job.UUID).Scan(&size)
-			_ = stmt.Close()
-			if err == nil {
-				break
-			}
+	t.Helper()
+	unprocessed, err := jobsDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParamsT{JobsLimit: 1000})
+	if err != nil {
+		return 0, err
+	}
+	for _, stored := range unprocessed.Jobs {
+		if stored.UUID == job.UUID {
+			return stored.PayloadSize, nil
 		}
-		return err
-	})
-
-	return size, err
+	}
+	return 0, fmt.Errorf("job %s not found", job.UUID)
 }
@@ -0,0 +1,203 @@
+package jobsdb
+
+import (
+	"context"
+)
+
+const defaultIteratorPageSize = 1000
+
+// QueryFunc is the signature of jobsdb's query methods, e.g. JobsDB.GetUnprocessed or JobsDB.GetToRetry
+type QueryFunc func(ctx context.Context, params GetQueryParamsT) (JobsResult, error)
+
+// JobsIteratorOptsFunc is used for configuring a JobsIterator
+type JobsIteratorOptsFunc func(it *JobsIterator)
+
+// WithPageSize sets the maximum number of jobs the iterator fetches from the database in a single query
+func WithPageSize(pageSize int) JobsIteratorOptsFunc {
+	return func(it *JobsIterator) {
+		if pageSize > 0 {
+			it.pageSize = pageSize
+		}
+	}
+}
+
+// WithPriorityLanes makes the iterator return the jobs of every priority lane, in descending priority order, the same
+// way QueryByPriority does. Every lane is paged with a job id cursor of its own. It has no effect if the query parameters
+// already have priority filters.
+func WithPriorityLanes() JobsIteratorOptsFunc {
+	return func(it *JobsIterator) {
+		if len(it.params.PriorityFilters) == 0 {
+			it.lanes = Priorities
+		}
+	}
+}
+
+/*
+JobsIterator streams the results of a jobsdb query, fetching them lazily page by page using a job id cursor
+(see GetQueryParamsT.AfterJobID), so that only a single page of jobs is held in memory at any time.
+A new page is only fetched when the consumer asks for the next job after having consumed the current page,
+thus a slow consumer naturally applies back-pressure to the reading side.
+
+The limits of the query parameters (JobsLimit, EventsLimit & PayloadSizeLimit) apply to the whole iteration,
+with the same semantics as the respective query: the first job is always returned, even if it exceeds the events
+or payload size limits. A JobsLimit less than or equal to zero means that the iteration is not bounded by a number of jobs.
+
+Since each page is a separate query, the iteration is not a consistent snapshot: jobs changing state while iterating
+may or may not be returned, but a job is never returned twice.
+
+Usage:
+
+	it := jobsdb.NewJobsIterator(db.GetToRetry, params, jobsdb.WithPageSize(100))
+	for it.Next(ctx) {
+		job := it.Job()
+		...
+	}
+	if err := it.Err(); err != nil {
+		...
+	}
+*/
+type JobsIterator struct {
+	query    QueryFunc
+	params   GetQueryParamsT
+	pageSize int
+	lanes    []int // priorities of the lanes to iterate, in order (see WithPriorityLanes)
+	laneIdx  int
+
+	page          []*JobT
+	pageIdx       int
+	job           *JobT
+	lastJobID     int64
+	exhausted     bool
+	limitsReached bool
+	err           error
+
+	jobsCount   int
+	eventsCount int
+	payloadSize int64
+}
+
+// NewJobsIterator returns a new iterator over the results of query
+func NewJobsIterator(query QueryFunc, params GetQueryParamsT, opts ...JobsIteratorOptsFunc) *JobsIterator {
+	it := &JobsIterator{
+		query:     query,
+		params:    params,
+		pageSize:  defaultIteratorPageSize,
+		lastJobID: params.AfterJobID,
+	}
+	for _, opt := range opts {
+		opt(it)
+	}
+	return it
+}
+
+// Next advances the iterator to the next job, fetching a new page of jobs if needed.
+// It returns false when the iteration is over, either because there are no more jobs, a limit has been reached
+// or an error occurred. Err should be consulted to distinguish between the last two cases.
+func (it *JobsIterator) Next(ctx context.Context) bool {
+	it.job = nil
+	if it.err != nil || it.limitsReached {
+		return false
+	}
+	for it.pageIdx >= len(it.page) {
+		if it.exhausted {
+			if it.laneIdx >= len(it.lanes)-1 {
+				return false
+			}
+			// moving on to the next priority lane, from the start
+			it.laneIdx++
+			it.lastJobID = it.params.AfterJobID
+			it.exhausted = false
+		}
+		if err := it.fetchPage(ctx); err != nil {
+			it.err = err
+			return false
+		}
+		if len(it.page) == 0 && (len(it.lanes) == 0 || !it.exhausted) {
+			return false
+		}
+	}
+	job := it.page[it.pageIdx]
+	if it.jobsCount > 0 && it.exceedsLimits(job) {
+		it.limitsReached = true
+		it.page = nil
+		return false
+	}
+	it.pageIdx++
+	it.job = job
+	it.lastJobID = job.JobID
+	it.jobsCount++
+	it.eventsCount += job.EventCount
+	it.payloadSize += job.PayloadSize
+	if it.params.JobsLimit > 0 && it.jobsCount >= it.params.JobsLimit ||
+		it.params.EventsLimit > 0 && it.eventsCount >= it.params.EventsLimit ||
+		it.params.PayloadSizeLimit > 0 && it.payloadSize >= it.params.PayloadSizeLimit {
+		it.limitsReached = true
+	}
+	return true
+}
+
+// Job returns the current job, nil if Next hasn't been called or returned false
+func (it *JobsIterator) Job() *JobT {
+	return it.job
+}
+
+// Err returns the error which stopped the iteration, if any
+func (it *JobsIterator) Err() error {
+	return it.err
+}
+
+// LimitsReached returns true if the iteration stopped because one of the query's limits was reached
+func (it *JobsIterator) LimitsReached() bool {
+	return it.limitsReached
+}
+
+// fetchPage queries the next page of jobs after the last job returned, adjusting the page's limits
+// to whatever is left from the iteration's limits
+func (it *JobsIterator) fetchPage(ctx context.Context) error {
+	params := it.params
+	params.AfterJobID = it.lastJobID
+	params.JobsLimit = it.pageSize
+	if len(it.lanes) > 0 {
+		params.PriorityFilters = []int{it.lanes[it.laneIdx]}
+	}
+	if it.params.JobsLimit > 0 && it.params.JobsLimit-it.jobsCount < params.JobsLimit {
+		params.JobsLimit = it.params.JobsLimit - it.jobsCount
+	}
+	if it.params.EventsLimit > 0 {
+		params.EventsLimit = it.params.EventsLimit - it.eventsCount
+	}
+	if it.params.PayloadSizeLimit > 0 {
+		params.PayloadSizeLimit = it.params.PayloadSizeLimit - it.payloadSize
+	}
+
+	res, err := it.query(ctx, params)
+	if err != nil {
+		return err
+	}
+	it.page = res.Jobs
+	it.pageIdx = 0
+	// a partial page without any limits reached means that there are no more jobs to fetch
+	if len(res.Jobs) < params.JobsLimit && !res.LimitsReached {
+		it.exhausted = true
+	}
+	return nil
+}
+
+// Collect consumes the remaining jobs of the iterator, returning them as a single result
+func (it *JobsIterator) Collect(ctx context.Context) (JobsResult, error) {
+	var res JobsResult
+	for it.Next(ctx) {
+		job := it.Job()
+		res.Jobs = append(res.Jobs, job)
+		res.EventsCount += job.EventCount
+		res.PayloadSize += job.PayloadSize
+	}
+	res.LimitsReached = it.LimitsReached()
+	return res, it.Err()
+}
+
+// exceedsLimits returns true if job doesn't fit in the remaining events or payload size limits
+func (it *JobsIterator) exceedsLimits(job *JobT) bool {
+	return it.params.EventsLimit > 0 && it.eventsCount+job.EventCount > it.params.EventsLimit ||
+		it.params.PayloadSizeLimit > 0 && it.payloadSize+job.PayloadSize > it.params.PayloadSizeLimit
+}
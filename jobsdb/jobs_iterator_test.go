@@ -0,0 +1,163 @@
+package jobsdb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobsIterator(t *testing.T) {
+	initEmbeddedJobsDB()
+	ctx := context.Background()
+
+	iterate := func(t *testing.T, it *JobsIterator) []*JobT {
+		t.Helper()
+		var jobs []*JobT
+		for it.Next(ctx) {
+			jobs = append(jobs, it.Job())
+		}
+		require.NoError(t, it.Err())
+		require.Nil(t, it.Job())
+		return jobs
+	}
+
+	t.Run("streams all jobs across datasets in pages", func(t *testing.T) {
+		jd := newTestEmbeddedJobsDB(t, t.TempDir())
+		defer jd.TearDown()
+		jobs := genEmbeddedJobs("ws", "GW", 5, 1)
+		require.NoError(t, jd.Store(ctx, jobs))
+		require.NoError(t, jd.addNewDS())
+		jobs = append(jobs, genEmbeddedJobs("ws", "GW", 6, 1)...)
+		require.NoError(t, jd.Store(ctx, jobs[5:]))
+
+		var queries int
+		query := func(ctx context.Context, params GetQueryParamsT) (JobsResult, error) {
+			queries++
+			require.LessOrEqual(t, params.JobsLimit, 3)
+			return jd.GetUnprocessed(ctx, params)
+		}
+		it := NewJobsIterator(query, GetQueryParamsT{}, WithPageSize(3))
+		iterated := iterate(t, it)
+		require.Len(t, iterated, 11)
+		for i := range jobs {
+			require.Equal(t, jobs[i].JobID, iterated[i].JobID)
+		}
+		require.Equal(t, 4, queries, "the last partial page should end the iteration")
+		require.False(t, it.LimitsReached())
+	})
+
+	t.Run("fetches pages lazily", func(t *testing.T) {
+		jd := newTestEmbeddedJobsDB(t, t.TempDir())
+		defer jd.TearDown()
+		require.NoError(t, jd.Store(ctx, genEmbeddedJobs("ws", "GW", 10, 1)))
+
+		var queries int
+		query := func(ctx context.Context, params GetQueryParamsT) (JobsResult, error) {
+			queries++
+			return jd.GetUnprocessed(ctx, params)
+		}
+		it := NewJobsIterator(query, GetQueryParamsT{}, WithPageSize(4))
+		require.Equal(t, 0, queries)
+		for i := 0; i < 4; i++ {
+			require.True(t, it.Next(ctx))
+		}
+		require.Equal(t, 1, queries)
+		require.True(t, it.Next(ctx))
+		require.Equal(t, 2, queries)
+	})
+
+	t.Run("respects the overall limits", func(t *testing.T) {
+		jd := newTestEmbeddedJobsDB(t, t.TempDir())
+		defer jd.TearDown()
+		require.NoError(t, jd.Store(ctx, genEmbeddedJobs("ws", "GW", 10, 2)))
+
+		it := NewJobsIterator(jd.GetUnprocessed, GetQueryParamsT{JobsLimit: 5}, WithPageSize(2))
+		require.Len(t, iterate(t, it), 5)
+		require.True(t, it.LimitsReached())
+
+		it = NewJobsIterator(jd.GetUnprocessed, GetQueryParamsT{EventsLimit: 7}, WithPageSize(2))
+		require.Len(t, iterate(t, it), 3, "a job exceeding the remaining events limit shouldn't be returned")
+		require.True(t, it.LimitsReached())
+
+		it = NewJobsIterator(jd.GetUnprocessed, GetQueryParamsT{EventsLimit: 1}, WithPageSize(2))
+		require.Len(t, iterate(t, it), 1, "the first job should always be returned")
+	})
+
+	t.Run("starts after the provided job id", func(t *testing.T) {
+		jd := newTestEmbeddedJobsDB(t, t.TempDir())
+		defer jd.TearDown()
+		jobs := genEmbeddedJobs("ws", "GW", 5, 1)
+		require.NoError(t, jd.Store(ctx, jobs))
+		require.NoError(t, jd.UpdateJobStatus(ctx, embeddedStatuses(jobs, Failed.State), nil, nil))
+
+		iterated := iterate(t, NewJobsIterator(jd.GetToRetry, GetQueryParamsT{AfterJobID: jobs[2].JobID}))
+		require.Len(t, iterated, 2)
+		require.Equal(t, jobs[3].JobID, iterated[0].JobID)
+	})
+
+	t.Run("pages every priority lane across datasets", func(t *testing.T) {
+		jd := newTestEmbeddedJobsDB(t, t.TempDir())
+		defer jd.TearDown()
+		// every dataset has jobs of all priorities, interleaved
+		byPriority := map[int][]*JobT{}
+		for ds := 0; ds < 3; ds++ {
+			if ds > 0 {
+				require.NoError(t, jd.addNewDS())
+			}
+			jobs := genEmbeddedJobs("ws", "GW", 6, 1)
+			for i, job := range jobs {
+				job.Priority = []int{LowPriority, HighPriority, NormalPriority}[i%3]
+			}
+			require.NoError(t, jd.Store(ctx, jobs))
+			for _, job := range jobs {
+				byPriority[job.Priority] = append(byPriority[job.Priority], job)
+			}
+		}
+		var expected []int64
+		for _, priority := range Priorities {
+			for _, job := range byPriority[priority] {
+				expected = append(expected, job.JobID)
+			}
+		}
+
+		var lanes []int
+		query := func(ctx context.Context, params GetQueryParamsT) (JobsResult, error) {
+			require.Len(t, params.PriorityFilters, 1)
+			require.LessOrEqual(t, params.JobsLimit, 4)
+			lanes = append(lanes, params.PriorityFilters[0])
+			return jd.GetUnprocessed(ctx, params)
+		}
+		iterated := iterate(t, NewJobsIterator(query, GetQueryParamsT{}, WithPageSize(4), WithPriorityLanes()))
+		require.Len(t, iterated, len(expected))
+		for i := range expected {
+			require.Equal(t, expected[i], iterated[i].JobID, "jobs should be returned by priority, then in job id order")
+		}
+		require.Equal(t, []int{HighPriority, HighPriority, NormalPriority, NormalPriority, LowPriority, LowPriority}, lanes)
+
+		it := NewJobsIterator(jd.GetUnprocessed, GetQueryParamsT{JobsLimit: 8}, WithPageSize(4), WithPriorityLanes())
+		res, err := it.Collect(ctx)
+		require.NoError(t, err)
+		require.True(t, res.LimitsReached)
+		require.Len(t, res.Jobs, 8)
+		require.Equal(t, 8, res.EventsCount)
+		for i := range res.Jobs {
+			require.Equal(t, expected[i], res.Jobs[i].JobID, "the limits should span across lanes")
+		}
+		require.Equal(t, NormalPriority, res.Jobs[7].Priority)
+
+		res, err = NewJobsIterator(jd.GetUnprocessed, GetQueryParamsT{PriorityFilters: []int{LowPriority}}, WithPriorityLanes()).Collect(ctx)
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 6, "provided priority filters should be respected")
+	})
+
+	t.Run("stops on query errors", func(t *testing.T) {
+		it := NewJobsIterator(func(context.Context, GetQueryParamsT) (JobsResult, error) {
+			return JobsResult{}, fmt.Errorf("query failed")
+		}, GetQueryParamsT{})
+		require.False(t, it.Next(ctx))
+		require.Error(t, it.Err())
+		require.False(t, it.Next(ctx))
+	})
+}
@@ -40,6 +40,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/rudderlabs/rudder-server/admin"
+	"github.com/rudderlabs/rudder-server/jobsdb/encryption"
 	"github.com/rudderlabs/rudder-server/jobsdb/internal/lock"
 	"github.com/rudderlabs/rudder-server/jobsdb/prebackup"
 	"github.com/rudderlabs/rudder-server/utils/bytesize"
@@ -82,6 +83,9 @@ type QueryConditions struct {
 	CustomValFilters              []string
 	ParameterFilters              []ParameterFilterT
 	StateFilters                  []string
+	PriorityFilters               []int
+	ExcludedParameterFilters      []ParameterFilterT
+	AfterJobID                    int64
 }
 
 // GetQueryParamsT is a struct to hold jobsdb query params.
@@ -93,6 +97,16 @@ type GetQueryParamsT struct {
 	CustomValFilters              []string
 	ParameterFilters              []ParameterFilterT
 	StateFilters                  []string
+	// Only jobs with a job_id greater than AfterJobID are returned, which allows
+	// paginating through results (see JobsIterator).
+	// A value less than or equal to zero disables this condition.
+	AfterJobID int64
+	// Only jobs having one of the provided priorities are returned (see QueryByPriority).
+	// An empty list disables this condition.
+	PriorityFilters []int
+	// Jobs matching any of the provided parameter filters are not returned, e.g. the jobs of destinations
+	// which can't be delivered to for a while. An empty list disables this condition.
+	ExcludedParameterFilters []ParameterFilterT
 
 	// query limits
 
@@ -109,6 +123,12 @@ type GetQueryParamsT struct {
 	PayloadSizeLimit int64
 }
 
+// isPartial returns true if the query conditions only match a subset of the jobs described by the
+// state, custom value & parameter filters, in which case an empty result cannot be cached
+func (params *GetQueryParamsT) isPartial() bool {
+	return params.AfterJobID > 0 || len(params.PriorityFilters) > 0 || len(params.ExcludedParameterFilters) > 0
+}
+
 // statTags is a struct to hold tags for stats
 type statTags struct {
 	CustomValFilters []string
@@ -351,6 +371,16 @@ type JobT struct {
 	LastJobStatus JobStatusT      `json:"LastJobStatus"`
 	Parameters    json.RawMessage `json:"Parameters"`
 	WorkspaceId   string          `json:"WorkspaceId"`
+	// ExpiryTime is optional, if set the job gets aborted once this time has passed without it reaching a terminal state.
+	// Not to be confused with ExpireAt, which is not used for expiring jobs.
+	ExpiryTime time.Time `json:"ExpiryTime"`
+	// Priority of the job, one of HighPriority, NormalPriority or LowPriority (see QueryByPriority)
+	Priority int `json:"Priority"`
+}
+
+// hasExpired returns true if the job has an expiry time which is before now
+func (job *JobT) hasExpired(now time.Time) bool {
+	return !job.ExpiryTime.IsZero() && job.ExpiryTime.Before(now)
 }
 
 func (job *JobT) String() string {
@@ -436,6 +466,8 @@ type HandleT struct {
 	backgroundGroup               *errgroup.Group
 	maxBackupRetryTime            time.Duration
 	preBackupHandlers             []prebackup.Handler
+	expiredJobsHandler            ExpiredJobsHandler
+	payloadEncryption             *encryption.Envelope
 
 	// skipSetupDBSetup is useful for testing as we mock the database client
 	// TODO: Remove this flag once we have test setup that uses real database
@@ -583,6 +615,13 @@ var (
 	WontMigrate = jobStateT{isValid: true, isTerminal: true, State: "wont_migrate"}
 )
 
+// ExpiredJobErrorCode is the error code of the aborted statuses created by jobsdb for expired jobs
+const ExpiredJobErrorCode = 1114
+
+// ExpiredJobsHandler is called whenever jobsdb aborts jobs which have expired (see JobT.ExpiryTime),
+// within the same transaction that the aborted statuses are being stored, e.g. for reporting them
+type ExpiredJobsHandler func(tx UpdateSafeTx, jobs []*JobT, statusList []*JobStatusT) error
+
 // Adding a new state to this list, will require an enum change in postgres db.
 var jobStates = []jobStateT{
 	NotProcessed,
@@ -738,6 +777,23 @@ func WithPreBackupHandlers(preBackupHandlers []prebackup.Handler) OptsFunc {
 	}
 }
 
+// WithExpiredJobsHandler, sets a handler for jobs aborted due to their expiry
+func WithExpiredJobsHandler(handler ExpiredJobsHandler) OptsFunc {
+	return func(jd *HandleT) {
+		jd.expiredJobsHandler = handler
+	}
+}
+
+// WithPayloadEncryption, enables envelope encryption of event payloads using master keys of the provided key provider.
+// Payload encryption stays disabled if keys is nil.
+func WithPayloadEncryption(keys encryption.KeyProvider) OptsFunc {
+	return func(jd *HandleT) {
+		if keys != nil {
+			jd.payloadEncryption = encryption.NewEnvelope(keys)
+		}
+	}
+}
+
 func NewForRead(tablePrefix string, opts ...OptsFunc) *HandleT {
 	return newOwnerType(Read, tablePrefix, opts...)
 }
@@ -1575,7 +1631,11 @@ func (jd *HandleT) createDSInTx(tx *sql.Tx, newDS dataSetT) error {
                                       event_payload JSONB NOT NULL,
 									  event_count INTEGER NOT NULL DEFAULT 1,
                                       created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-                                      expire_at TIMESTAMP NOT NULL DEFAULT NOW());`, newDS.JobTable)
+                                      expire_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                                      expiry_time TIMESTAMP,
+                                      priority SMALLINT NOT NULL DEFAULT 0,
+                                      encryption_key_id TEXT,
+                                      plaintext_size INTEGER);`, newDS.JobTable)
 
 	_, err = tx.ExecContext(context.TODO(), sqlStatement)
 	if err != nil {
@@ -1939,8 +1999,21 @@ func (jd *HandleT) migrateJobs(ctx context.Context, srcDS, destDS dataSetT) (noJ
 	}
 	jobsToMigrate := append(unprocessedList.Jobs, retryList.Jobs...)
 	noJobsMigrated = len(jobsToMigrate)
+	// Expired jobs are aborted instead of being migrated
+	expiredJobs := append(unprocessedList.expired, retryList.expired...)
 
 	err = jd.WithTx(func(tx *sql.Tx) error {
+		if len(expiredJobs) > 0 {
+			expiredStatusList := expiredJobStatuses(expiredJobs)
+			if err := jd.copyJobStatusDS(ctx, tx, srcDS, expiredStatusList, []string{}); err != nil {
+				return err
+			}
+			if jd.expiredJobsHandler != nil {
+				if err := jd.expiredJobsHandler(&updateSafeTx{tx: tx, identity: jd.tablePrefix}, expiredJobs, expiredStatusList); err != nil {
+					return err
+				}
+			}
+		}
 		if err := jd.copyJobsDS(tx, destDS, jobsToMigrate); err != nil {
 			return err
 		}
@@ -2277,12 +2350,12 @@ func (jd *HandleT) GetPileUpCounts(ctx context.Context) (map[string]map[string]i
 	return statMap, nil
 }
 
-func (*HandleT) copyJobsDSInTx(txHandler transactionHandler, ds dataSetT, jobList []*JobT) error {
+func (jd *HandleT) copyJobsDSInTx(txHandler transactionHandler, ds dataSetT, jobList []*JobT) error {
 	var stmt *sql.Stmt
 	var err error
 
 	stmt, err = txHandler.Prepare(pq.CopyIn(ds.JobTable, "job_id", "uuid", "user_id", "custom_val", "parameters",
-		"event_payload", "event_count", "created_at", "expire_at", "workspace_id"))
+		"event_payload", "event_count", "created_at", "expire_at", "workspace_id", "expiry_time", "priority", "encryption_key_id", "plaintext_size"))
 
 	if err != nil {
 		return err
@@ -2296,8 +2369,13 @@ func (*HandleT) copyJobsDSInTx(txHandler transactionHandler, ds dataSetT, jobLis
 			eventCount = job.EventCount
 		}
 
+		// payloads are (re-)encrypted using the current key while copied, e.g. during migrations
+		payload, keyID, plaintextSize, err := jd.encryptPayload(job)
+		if err != nil {
+			return err
+		}
 		_, err = stmt.Exec(job.JobID, job.UUID, job.UserID, job.CustomVal, string(job.Parameters),
-			string(job.EventPayload), eventCount, job.CreatedAt, job.ExpireAt, job.WorkspaceId)
+			payload, eventCount, job.CreatedAt, job.ExpireAt, job.WorkspaceId, nullTime(job.ExpiryTime), job.Priority, keyID, plaintextSize)
 
 		if err != nil {
 			return err
@@ -2320,7 +2398,7 @@ func (jd *HandleT) doStoreJobsInTx(ctx context.Context, tx *sql.Tx, ds dataSetT,
 		var stmt *sql.Stmt
 		var err error
 
-		stmt, err = tx.PrepareContext(ctx, pq.CopyIn(ds.JobTable, "uuid", "user_id", "custom_val", "parameters", "event_payload", "event_count", "workspace_id"))
+		stmt, err = tx.PrepareContext(ctx, pq.CopyIn(ds.JobTable, "uuid", "user_id", "custom_val", "parameters", "event_payload", "event_count", "workspace_id", "expiry_time", "priority", "encryption_key_id", "plaintext_size"))
 		if err != nil {
 			return err
 		}
@@ -2332,7 +2410,11 @@ func (jd *HandleT) doStoreJobsInTx(ctx context.Context, tx *sql.Tx, ds dataSetT,
 				eventCount = job.EventCount
 			}
 
-			if _, err = stmt.ExecContext(ctx, job.UUID, job.UserID, job.CustomVal, string(job.Parameters), string(job.EventPayload), eventCount, job.WorkspaceId); err != nil {
+			payload, keyID, plaintextSize, err := jd.encryptPayload(job)
+			if err != nil {
+				return err
+			}
+			if _, err = stmt.ExecContext(ctx, job.UUID, job.UserID, job.CustomVal, string(job.Parameters), payload, eventCount, job.WorkspaceId, nullTime(job.ExpiryTime), job.Priority, keyID, plaintextSize); err != nil {
 				return err
 			}
 		}
@@ -2369,13 +2451,17 @@ func (jd *HandleT) doStoreJobsInTx(ctx context.Context, tx *sql.Tx, ds dataSetT,
 }
 
 func (jd *HandleT) storeJob(ctx context.Context, tx *sql.Tx, ds dataSetT, job *JobT) (err error) {
-	sqlStatement := fmt.Sprintf(`INSERT INTO %q (uuid, user_id, custom_val, parameters, event_payload, workspace_id)
-	                                   VALUES ($1, $2, $3, $4, $5, $6) RETURNING job_id`, ds.JobTable)
+	sqlStatement := fmt.Sprintf(`INSERT INTO %q (uuid, user_id, custom_val, parameters, event_payload, workspace_id, expiry_time, priority, encryption_key_id, plaintext_size)
+	                                   VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING job_id`, ds.JobTable)
 	stmt, err := tx.PrepareContext(ctx, sqlStatement)
 	jd.assertError(err)
 	defer stmt.Close()
 	job.sanitizeJson()
-	_, err = stmt.ExecContext(ctx, job.UUID, job.UserID, job.CustomVal, string(job.Parameters), string(job.EventPayload), job.WorkspaceId)
+	payload, keyID, plaintextSize, err := jd.encryptPayload(job)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.ExecContext(ctx, job.UUID, job.UserID, job.CustomVal, string(job.Parameters), payload, job.WorkspaceId, nullTime(job.ExpiryTime), job.Priority, keyID, plaintextSize)
 	if err == nil {
 		// Empty customValFilters means we want to clear for all
 		jd.markClearEmptyResult(ds, allWorkspaces, []string{}, []string{}, nil, hasJobs, nil)
@@ -2387,7 +2473,7 @@ func (jd *HandleT) storeJob(ctx context.Context, tx *sql.Tx, ds dataSetT, job *J
 	if ok {
 		errCode := string(pqErr.Code)
 		if _, ok := dbInvalidJsonErrors[errCode]; ok {
-			return errors.New("Invalid JSON")
+			return errInvalidJSON
 		}
 	}
 	return
@@ -2555,6 +2641,9 @@ type JobsResult struct {
 	LimitsReached bool
 	EventsCount   int
 	PayloadSize   int64
+
+	// expired jobs are left out of Jobs, so that they can be aborted
+	expired []*JobT
 }
 
 /*
@@ -2582,7 +2671,7 @@ func (jd *HandleT) getProcessedJobsDS(ctx context.Context, ds dataSetT, getAll b
 	// We don't reset this in case of error for now, as any error in this function causes panic
 	jd.markClearEmptyResult(ds, allWorkspaces, stateFilters, customValFilters, parameterFilters, willTryToSet, nil)
 
-	var stateQuery, customValQuery, limitQuery, sourceQuery string
+	var stateQuery, customValQuery, limitQuery, sourceQuery, afterJobIDQuery, priorityQuery string
 
 	if len(stateFilters) > 0 {
 		stateQuery = " AND " + constructQueryOR("job_state", stateFilters)
@@ -2604,6 +2693,21 @@ func (jd *HandleT) getProcessedJobsDS(ctx context.Context, ds dataSetT, getAll b
 		sourceQuery = ""
 	}
 
+	if params.AfterJobID > 0 {
+		jd.assert(!getAll, "getAll is true")
+		afterJobIDQuery = fmt.Sprintf(" AND jobs.job_id > %d", params.AfterJobID)
+	}
+
+	if len(params.PriorityFilters) > 0 {
+		jd.assert(!getAll, "getAll is true")
+		priorityQuery = " AND " + constructPriorityQuery("jobs", params.PriorityFilters)
+	}
+
+	if len(params.ExcludedParameterFilters) > 0 {
+		jd.assert(!getAll, "getAll is true")
+		sourceQuery += " AND " + constructExcludedParameterJSONQuery("jobs", params.ExcludedParameterFilters)
+	}
+
 	if params.JobsLimit > 0 {
 		jd.assert(!getAll, "getAll is true")
 		limitQuery = fmt.Sprintf(" LIMIT %d ", params.JobsLimit)
@@ -2615,10 +2719,10 @@ func (jd *HandleT) getProcessedJobsDS(ctx context.Context, ds dataSetT, getAll b
 	if getAll {
 		sqlStatement := fmt.Sprintf(`SELECT
                                 	jobs.job_id, jobs.uuid, jobs.user_id, jobs.parameters,  jobs.custom_val, jobs.event_payload, jobs.event_count,
-                                	jobs.created_at, jobs.expire_at, jobs.workspace_id,
-									pg_column_size(jobs.event_payload) as payload_size,
+                                	jobs.created_at, jobs.expire_at, jobs.workspace_id, jobs.expiry_time, jobs.priority, jobs.encryption_key_id,
+									`+payloadSizeColumn+` as payload_size,
 									sum(jobs.event_count) over (order by jobs.job_id asc) as running_event_counts,
-									sum(pg_column_size(jobs.event_payload)) over (order by jobs.job_id) as running_payload_size,
+									sum(`+payloadSizeColumn+`) over (order by jobs.job_id) as running_payload_size,
                                 	job_latest_state.job_state, job_latest_state.attempt,
                                 	job_latest_state.exec_time, job_latest_state.retry_time,
                                 	job_latest_state.error_code, job_latest_state.error_response, job_latest_state.parameters
@@ -2640,10 +2744,10 @@ func (jd *HandleT) getProcessedJobsDS(ctx context.Context, ds dataSetT, getAll b
 	} else {
 		sqlStatement := fmt.Sprintf(`SELECT
 									jobs.job_id, jobs.uuid, jobs.user_id, jobs.parameters, jobs.custom_val, jobs.event_payload, jobs.event_count,
-									jobs.created_at, jobs.expire_at, jobs.workspace_id,
-									pg_column_size(jobs.event_payload) as payload_size,
+									jobs.created_at, jobs.expire_at, jobs.workspace_id, jobs.expiry_time, jobs.priority, jobs.encryption_key_id,
+									`+payloadSizeColumn+` as payload_size,
 									sum(jobs.event_count) over (order by jobs.job_id asc) as running_event_counts,
-									sum(pg_column_size(jobs.event_payload)) over (order by jobs.job_id) as running_payload_size,
+									sum(`+payloadSizeColumn+`) over (order by jobs.job_id) as running_payload_size,
 									job_latest_state.job_state, job_latest_state.attempt,
 									job_latest_state.exec_time, job_latest_state.retry_time,
 									job_latest_state.error_code, job_latest_state.error_response, job_latest_state.parameters
@@ -2654,9 +2758,9 @@ func (jd *HandleT) getProcessedJobsDS(ctx context.Context, ds dataSetT, getAll b
 										(SELECT MAX(id) from %[2]q GROUP BY job_id) %[3]s)
 									AS job_latest_state
 								WHERE jobs.job_id=job_latest_state.job_id
-									%[4]s %[5]s
+									%[4]s %[5]s %[7]s %[8]s
 									AND job_latest_state.retry_time < $1 ORDER BY jobs.job_id %[6]s`,
-			ds.JobTable, ds.JobStatusTable, stateQuery, customValQuery, sourceQuery, limitQuery)
+			ds.JobTable, ds.JobStatusTable, stateQuery, customValQuery, sourceQuery, limitQuery, afterJobIDQuery, priorityQuery)
 
 		args := []interface{}{getTimeNowFunc()}
 
@@ -2697,22 +2801,33 @@ func (jd *HandleT) getProcessedJobsDS(ctx context.Context, ds dataSetT, getAll b
 	var runningEventCount int
 	var runningPayloadSize int64
 
-	var jobList []*JobT
+	var jobList, expiredList []*JobT
 	var limitsReached bool
 	var eventCount int
 	var payloadSize int64
+	now := getTimeNowFunc()
 
 	for rows.Next() {
 		var job JobT
+		var expiryTime sql.NullTime
+		var encryptionKeyID sql.NullString
 
 		err := rows.Scan(&job.JobID, &job.UUID, &job.UserID, &job.Parameters, &job.CustomVal,
-			&job.EventPayload, &job.EventCount, &job.CreatedAt, &job.ExpireAt, &job.WorkspaceId, &job.PayloadSize, &runningEventCount, &runningPayloadSize,
+			&job.EventPayload, &job.EventCount, &job.CreatedAt, &job.ExpireAt, &job.WorkspaceId, &expiryTime, &job.Priority, &encryptionKeyID, &job.PayloadSize, &runningEventCount, &runningPayloadSize,
 			&job.LastJobStatus.JobState, &job.LastJobStatus.AttemptNum,
 			&job.LastJobStatus.ExecTime, &job.LastJobStatus.RetryTime,
 			&job.LastJobStatus.ErrorCode, &job.LastJobStatus.ErrorResponse, &job.LastJobStatus.Parameters)
 		if err != nil {
 			return JobsResult{}, err
 		}
+		if err := jd.decryptPayload(&job, encryptionKeyID); err != nil {
+			return JobsResult{}, err
+		}
+		job.ExpiryTime = expiryTime.Time
+		if job.hasExpired(now) && !isTerminalState(job.LastJobStatus.JobState) {
+			expiredList = append(expiredList, &job)
+			continue
+		}
 
 		if !getAll { // if getAll is true, limits do not apply
 			if params.EventsLimit > 0 && runningEventCount > params.EventsLimit && len(jobList) > 0 {
@@ -2740,7 +2855,8 @@ func (jd *HandleT) getProcessedJobsDS(ctx context.Context, ds dataSetT, getAll b
 	}
 
 	result := hasJobs
-	if len(jobList) == 0 {
+	// an empty result for a subset of the dataset's jobs doesn't mean that the dataset has no jobs
+	if len(jobList) == 0 && len(expiredList) == 0 && !params.isPartial() {
 		jd.logger.Debugf("[getProcessedJobsDS] Setting empty cache for ds: %v, stateFilters: %v, customValFilters: %v, parameterFilters: %v", ds, stateFilters, customValFilters, parameterFilters)
 		result = noJobs
 	}
@@ -2752,6 +2868,7 @@ func (jd *HandleT) getProcessedJobsDS(ctx context.Context, ds dataSetT, getAll b
 		LimitsReached: limitsReached,
 		PayloadSize:   payloadSize,
 		EventsCount:   eventCount,
+		expired:       expiredList,
 	}, nil
 }
 
@@ -2787,20 +2904,20 @@ func (jd *HandleT) getUnprocessedJobsDS(ctx context.Context, ds dataSetT, order
 	if useJoinForUnprocessed {
 		// event_count default 1, number of items in payload
 		sqlStatement = fmt.Sprintf(
-			`SELECT jobs.job_id, jobs.uuid, jobs.user_id, jobs.parameters, jobs.custom_val, jobs.event_payload, jobs.event_count, jobs.created_at, jobs.expire_at, jobs.workspace_id,`+
-				`	pg_column_size(jobs.event_payload) as payload_size, `+
+			`SELECT jobs.job_id, jobs.uuid, jobs.user_id, jobs.parameters, jobs.custom_val, jobs.event_payload, jobs.event_count, jobs.created_at, jobs.expire_at, jobs.workspace_id, jobs.expiry_time, jobs.priority, jobs.encryption_key_id,`+
+				`	`+payloadSizeColumn+` as payload_size, `+
 				`	sum(jobs.event_count) over (order by jobs.job_id asc) as running_event_counts, `+
-				`	sum(pg_column_size(jobs.event_payload)) over (order by jobs.job_id) as running_payload_size `+
+				`	sum(`+payloadSizeColumn+`) over (order by jobs.job_id) as running_payload_size `+
 				`FROM %[1]q AS jobs `+
 				`LEFT JOIN %[2]q AS job_status ON jobs.job_id=job_status.job_id `+
 				`WHERE job_status.job_id is NULL `,
 			ds.JobTable, ds.JobStatusTable)
 	} else {
 		sqlStatement = fmt.Sprintf(
-			`SELECT jobs.job_id, jobs.uuid, jobs.user_id, jobs.parameters, jobs.custom_val, jobs.event_payload, jobs.event_count, jobs.created_at, jobs.expire_at, jobs.workspace_id,`+
-				`	pg_column_size(jobs.event_payload) as payload_size, `+
+			`SELECT jobs.job_id, jobs.uuid, jobs.user_id, jobs.parameters, jobs.custom_val, jobs.event_payload, jobs.event_count, jobs.created_at, jobs.expire_at, jobs.workspace_id, jobs.expiry_time, jobs.priority, jobs.encryption_key_id,`+
+				`	`+payloadSizeColumn+` as payload_size, `+
 				`	sum(jobs.event_count) over (order by jobs.job_id asc) as running_event_counts, `+
-				`	sum(pg_column_size(jobs.event_payload)) over (order by jobs.job_id) as running_payload_size `+
+				`	sum(`+payloadSizeColumn+`) over (order by jobs.job_id) as running_payload_size `+
 				` FROM %[1]q AS jobs `+
 				`WHERE jobs.job_id NOT IN (SELECT DISTINCT(job_status.job_id) FROM %[2]q AS job_status)`,
 			ds.JobTable, ds.JobStatusTable)
@@ -2814,6 +2931,19 @@ func (jd *HandleT) getUnprocessedJobsDS(ctx context.Context, ds dataSetT, order
 		sqlStatement += " AND " + constructParameterJSONQuery("jobs", parameterFilters)
 	}
 
+	if params.AfterJobID > 0 {
+		sqlStatement += fmt.Sprintf(" AND jobs.job_id > $%d", len(args)+1)
+		args = append(args, params.AfterJobID)
+	}
+
+	if len(params.PriorityFilters) > 0 {
+		sqlStatement += " AND " + constructPriorityQuery("jobs", params.PriorityFilters)
+	}
+
+	if len(params.ExcludedParameterFilters) > 0 {
+		sqlStatement += " AND " + constructExcludedParameterJSONQuery("jobs", params.ExcludedParameterFilters)
+	}
+
 	if order {
 		sqlStatement += " ORDER BY jobs.job_id"
 	}
@@ -2853,18 +2983,29 @@ func (jd *HandleT) getUnprocessedJobsDS(ctx context.Context, ds dataSetT, order
 	var runningEventCount int
 	var runningPayloadSize int64
 
-	var jobList []*JobT
+	var jobList, expiredList []*JobT
 	var limitsReached bool
 	var eventCount int
 	var payloadSize int64
+	now := getTimeNowFunc()
 
 	for rows.Next() {
 		var job JobT
+		var expiryTime sql.NullTime
+		var encryptionKeyID sql.NullString
 		err := rows.Scan(&job.JobID, &job.UUID, &job.UserID, &job.Parameters, &job.CustomVal,
-			&job.EventPayload, &job.EventCount, &job.CreatedAt, &job.ExpireAt, &job.WorkspaceId, &job.PayloadSize, &runningEventCount, &runningPayloadSize)
+			&job.EventPayload, &job.EventCount, &job.CreatedAt, &job.ExpireAt, &job.WorkspaceId, &expiryTime, &job.Priority, &encryptionKeyID, &job.PayloadSize, &runningEventCount, &runningPayloadSize)
 		if err != nil {
 			return JobsResult{}, err
 		}
+		if err := jd.decryptPayload(&job, encryptionKeyID); err != nil {
+			return JobsResult{}, err
+		}
+		job.ExpiryTime = expiryTime.Time
+		if job.hasExpired(now) {
+			expiredList = append(expiredList, &job)
+			continue
+		}
 		if params.EventsLimit > 0 && runningEventCount > params.EventsLimit && len(jobList) > 0 {
 			// events limit overflow is triggered as long as we have read at least one job
 			limitsReached = true
@@ -2892,7 +3033,8 @@ func (jd *HandleT) getUnprocessedJobsDS(ctx context.Context, ds dataSetT, order
 	result := hasJobs
 	dsList := jd.getDSList()
 	// if jobsdb owner is a reader and if ds is the right most one, ignoring setting result as noJobs
-	if len(jobList) == 0 && (jd.ownerType != Read || ds.Index != dsList[len(dsList)-1].Index) {
+	// an empty result for a subset of the dataset's jobs doesn't mean that the dataset has no jobs
+	if len(jobList) == 0 && len(expiredList) == 0 && !params.isPartial() && (jd.ownerType != Read || ds.Index != dsList[len(dsList)-1].Index) {
 		jd.logger.Debugf("[getUnprocessedJobsDS] Setting empty cache for ds: %v, stateFilters: NP, customValFilters: %v, parameterFilters: %v", ds, customValFilters, parameterFilters)
 		result = noJobs
 	}
@@ -2904,6 +3046,7 @@ func (jd *HandleT) getUnprocessedJobsDS(ctx context.Context, ds dataSetT, order
 		LimitsReached: limitsReached,
 		PayloadSize:   payloadSize,
 		EventsCount:   eventCount,
+		expired:       expiredList,
 	}, nil
 }
 
@@ -3348,6 +3491,7 @@ func (jd *HandleT) getBackUpQuery(backupDSRange *dataSetRangeT, isJobStatusTable
 					'parameters',failed_jobs.parameters,
 					'custom_val',failed_jobs.custom_val,
 					'event_payload',failed_jobs.event_payload,
+					'encryption_key_id',failed_jobs.encryption_key_id,
 					'event_count',failed_jobs.event_count,
 					'created_at',failed_jobs.created_at,
 					'expire_at',failed_jobs.expire_at,
@@ -3393,6 +3537,7 @@ func (jd *HandleT) getBackUpQuery(backupDSRange *dataSetRangeT, isJobStatusTable
 							job.parameters,
 							job.custom_val,
 							job.event_payload,
+							job.encryption_key_id,
 							job.event_count,
 							job.created_at,
 							job.expire_at,
@@ -3468,6 +3613,9 @@ func (jd *HandleT) getBackUpQuery(backupDSRange *dataSetRangeT, isJobStatusTable
 					'event_count', dump_table.event_count,
 					'created_at', dump_table.created_at,
 					'expire_at', dump_table.expire_at
+				) || (
+					CASE WHEN dump_table.encryption_key_id IS NULL THEN '{}'::jsonb
+					ELSE jsonb_build_object('encryption_key_id', dump_table.encryption_key_id) END
 				)
 		  	FROM
 				(
@@ -4192,7 +4340,7 @@ func (jd *HandleT) GetUnprocessed(ctx context.Context, params GetQueryParamsT) (
 		return queryResultWrapper(jd.getUnprocessed(ctx, params))
 	}
 	res, _ := jd.executeDbRequest(newReadDbRequest("unprocessed", &tags, command)).(queryResult)
-	return res.JobsResult, res.err
+	return jd.abortExpired(ctx, res)
 }
 
 /*
@@ -4236,6 +4384,7 @@ func (jd *HandleT) getUnprocessed(ctx context.Context, params GetQueryParamsT) (
 			return JobsResult{}, err
 		}
 		completeUnprocessedJobs.Jobs = append(completeUnprocessedJobs.Jobs, unprocessedJobs.Jobs...)
+		completeUnprocessedJobs.expired = append(completeUnprocessedJobs.expired, unprocessedJobs.expired...)
 		completeUnprocessedJobs.EventsCount += unprocessedJobs.EventsCount
 		completeUnprocessedJobs.PayloadSize += unprocessedJobs.PayloadSize
 
@@ -4268,7 +4417,7 @@ func (jd *HandleT) GetImporting(ctx context.Context, params GetQueryParamsT) (Jo
 		return queryResultWrapper(jd.getImportingList(ctx, params))
 	}
 	res, _ := jd.executeDbRequest(newReadDbRequest("importing", &tags, command)).(queryResult)
-	return res.JobsResult, res.err
+	return jd.abortExpired(ctx, res)
 }
 
 /*
@@ -4276,7 +4425,7 @@ getImportingList returns events which need are Importing.
 This is a wrapper over GetProcessed call above
 */
 func (jd *HandleT) getImportingList(ctx context.Context, params GetQueryParamsT) (JobsResult, error) { // skipcq: CRT-P0003
-	return jd.GetProcessed(ctx, params)
+	return jd.getProcessed(ctx, params)
 }
 
 /*
@@ -4406,6 +4555,11 @@ can return the same set of events. It is the responsibility of the caller to cal
 one thread, update the state (to "waiting") in the same thread and pass on the the processors
 */
 func (jd *HandleT) GetProcessed(ctx context.Context, params GetQueryParamsT) (JobsResult, error) { // skipcq: CRT-P0003
+	res, err := jd.getProcessed(ctx, params)
+	return jd.abortExpired(ctx, queryResultWrapper(res, err))
+}
+
+func (jd *HandleT) getProcessed(ctx context.Context, params GetQueryParamsT) (JobsResult, error) { // skipcq: CRT-P0003
 	if params.JobsLimit <= 0 {
 		return JobsResult{}, nil
 	}
@@ -4444,6 +4598,7 @@ func (jd *HandleT) GetProcessed(ctx context.Context, params GetQueryParamsT) (Jo
 			return JobsResult{}, err
 		}
 		completeProcessedJobs.Jobs = append(completeProcessedJobs.Jobs, processedJobs.Jobs...)
+		completeProcessedJobs.expired = append(completeProcessedJobs.expired, processedJobs.expired...)
 		completeProcessedJobs.EventsCount += processedJobs.EventsCount
 		completeProcessedJobs.PayloadSize += processedJobs.PayloadSize
 
@@ -4478,6 +4633,57 @@ func queryResultWrapper(res JobsResult, err error) queryResult {
 	}
 }
 
+// abortExpired aborts the expired jobs which were left out of a query's result, before returning the result
+func (jd *HandleT) abortExpired(ctx context.Context, res queryResult) (JobsResult, error) {
+	if res.err != nil || len(res.expired) == 0 {
+		return res.JobsResult, res.err
+	}
+	if err := jd.abortExpiredJobs(ctx, res.expired); err != nil {
+		return JobsResult{}, err
+	}
+	res.expired = nil
+	return res.JobsResult, nil
+}
+
+// abortExpiredJobs marks the provided jobs as aborted, notifying the expired jobs handler, if any, in the same transaction
+func (jd *HandleT) abortExpiredJobs(ctx context.Context, jobs []*JobT) error {
+	statusList := expiredJobStatuses(jobs)
+	err := jd.WithUpdateSafeTx(func(tx UpdateSafeTx) error {
+		if err := jd.UpdateJobStatusInTx(ctx, tx, statusList, nil, nil); err != nil {
+			return err
+		}
+		if jd.expiredJobsHandler != nil {
+			return jd.expiredJobsHandler(tx, jobs, statusList)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("aborting %d expired jobs: %w", len(jobs), err)
+	}
+	jd.logger.Debugf("[[ %s ]]: Aborted %d expired jobs", jd.tablePrefix, len(jobs))
+	return nil
+}
+
+// expiredJobStatuses returns aborted statuses for expired jobs
+func expiredJobStatuses(jobs []*JobT) []*JobStatusT {
+	now := getTimeNowFunc()
+	statusList := make([]*JobStatusT, 0, len(jobs))
+	for _, job := range jobs {
+		statusList = append(statusList, &JobStatusT{
+			JobID:         job.JobID,
+			JobState:      Aborted.State,
+			AttemptNum:    job.LastJobStatus.AttemptNum,
+			ExecTime:      now,
+			RetryTime:     now,
+			ErrorCode:     strconv.Itoa(ExpiredJobErrorCode),
+			ErrorResponse: []byte(fmt.Sprintf(`{"reason":"job expired at %s"}`, job.ExpiryTime.Format(time.RFC3339))),
+			Parameters:    []byte(`{}`),
+			WorkspaceId:   job.WorkspaceId,
+		})
+	}
+	return statusList
+}
+
 /*
 GetToRetry returns events which need to be retried.
 If enableReaderQueue is true, this goes through worker pool, else calls getUnprocessed directly.
@@ -4492,7 +4698,7 @@ func (jd *HandleT) GetToRetry(ctx context.Context, params GetQueryParamsT) (Jobs
 		return queryResultWrapper(jd.getToRetry(ctx, params))
 	}
 	res, _ := jd.executeDbRequest(newReadDbRequest("processed", &tags, command)).(queryResult)
-	return res.JobsResult, res.err
+	return jd.abortExpired(ctx, res)
 }
 
 /*
@@ -4500,7 +4706,7 @@ getToRetry returns events which need to be retried.
 This is a wrapper over GetProcessed call above
 */
 func (jd *HandleT) getToRetry(ctx context.Context, params GetQueryParamsT) (JobsResult, error) { // skipcq: CRT-P0003
-	return jd.GetProcessed(ctx, params)
+	return jd.getProcessed(ctx, params)
 }
 
 /*
@@ -4517,7 +4723,7 @@ func (jd *HandleT) GetWaiting(ctx context.Context, params GetQueryParamsT) (Jobs
 		return queryResultWrapper(jd.getWaiting(ctx, params))
 	}
 	res, _ := jd.executeDbRequest(newReadDbRequest("processed", &tags, command)).(queryResult)
-	return res.JobsResult, res.err
+	return jd.abortExpired(ctx, res)
 }
 
 /*
@@ -4525,7 +4731,7 @@ GetWaiting returns events which are under processing
 This is a wrapper over GetProcessed call above
 */
 func (jd *HandleT) getWaiting(ctx context.Context, params GetQueryParamsT) (JobsResult, error) { // skipcq: CRT-P0003
-	return jd.GetProcessed(ctx, params)
+	return jd.getProcessed(ctx, params)
 }
 
 func (jd *HandleT) GetExecuting(ctx context.Context, params GetQueryParamsT) (JobsResult, error) { // skipcq: CRT-P0003
@@ -4538,14 +4744,14 @@ func (jd *HandleT) GetExecuting(ctx context.Context, params GetQueryParamsT) (Jo
 		return queryResultWrapper(jd.getExecuting(ctx, params))
 	}
 	res, _ := jd.executeDbRequest(newReadDbRequest("processed", &tags, command)).(queryResult)
-	return res.JobsResult, res.err
+	return jd.abortExpired(ctx, res)
 }
 
 /*
 getExecuting returns events which  in executing state
 */
 func (jd *HandleT) getExecuting(ctx context.Context, params GetQueryParamsT) (JobsResult, error) { // skipcq: CRT-P0003
-	return jd.GetProcessed(ctx, params)
+	return jd.getProcessed(ctx, params)
 }
 
 /*
@@ -4585,19 +4791,24 @@ func (jd *HandleT) GetLastJobID() int64 {
 	return jd.GetMaxIDForDs(dsList[len(dsList)-1])
 }
 
-func (jd *HandleT) GetLastJob() *JobT {
+// GetLastJob returns the job with the largest job id
+func (jd *HandleT) GetLastJob() (*JobT, error) {
 	jd.dsListLock.RLock()
 	defer jd.dsListLock.RUnlock()
 	dsList := jd.getDSList()
 	maxID := jd.GetMaxIDForDs(dsList[len(dsList)-1])
 
 	var job JobT
-	sqlStatement := fmt.Sprintf(`SELECT %[1]s.job_id, %[1]s.uuid, %[1]s.user_id, %[1]s.parameters, %[1]s.custom_val, %[1]s.event_payload, %[1]s.created_at, %[1]s.expire_at FROM %[1]s WHERE %[1]s.job_id = %[2]d`, dsList[len(dsList)-1].JobTable, maxID)
-	err := jd.dbHandle.QueryRow(sqlStatement).Scan(&job.JobID, &job.UUID, &job.UserID, &job.Parameters, &job.CustomVal, &job.EventPayload, &job.CreatedAt, &job.ExpireAt)
+	var encryptionKeyID sql.NullString
+	sqlStatement := fmt.Sprintf(`SELECT %[1]s.job_id, %[1]s.uuid, %[1]s.user_id, %[1]s.parameters, %[1]s.custom_val, %[1]s.event_payload, %[1]s.created_at, %[1]s.expire_at, %[1]s.encryption_key_id FROM %[1]s WHERE %[1]s.job_id = %[2]d`, dsList[len(dsList)-1].JobTable, maxID)
+	err := jd.dbHandle.QueryRow(sqlStatement).Scan(&job.JobID, &job.UUID, &job.UserID, &job.Parameters, &job.CustomVal, &job.EventPayload, &job.CreatedAt, &job.ExpireAt, &encryptionKeyID)
 	if err != nil && err != sql.ErrNoRows {
-		jd.assertError(err)
+		return nil, err
+	}
+	if err := jd.decryptPayload(&job, encryptionKeyID); err != nil {
+		return nil, err
 	}
-	return &job
+	return &job, nil
 }
 
 func sanitizeJson(input json.RawMessage) json.RawMessage {
@@ -167,7 +167,7 @@ func (jd *HandleT) getNonMigratedJobsFromDS(ds dataSetT, count int) ([]*JobT, er
 		SELECT * FROM (
 			SELECT DISTINCT ON (%[1]s.job_id)
 				%[1]s.job_id, %[1]s.uuid, %[1]s.user_id, %[1]s.parameters, %[1]s.custom_val,
-				%[1]s.event_payload, %[1]s.created_at, %[1]s.expire_at,
+				%[1]s.event_payload, %[1]s.created_at, %[1]s.expire_at, %[1]s.encryption_key_id,
 				%[2]s.job_state, %[2]s.attempt, %[2]s.exec_time,
 				%[2]s.retry_time, %[2]s.error_code, %[2]s.error_response
 			FROM %[1]s LEFT JOIN %[2]s
@@ -188,9 +188,10 @@ func (jd *HandleT) getNonMigratedJobsFromDS(ds dataSetT, count int) ([]*JobT, er
 	sqlJobStatusT := SQLJobStatusT{}
 	for rows.Next() {
 		var job JobT
+		var encryptionKeyID sql.NullString
 		err := rows.Scan(&job.JobID, &job.UUID, &job.UserID,
 			&job.Parameters, &job.CustomVal,
-			&job.EventPayload, &job.CreatedAt, &job.ExpireAt,
+			&job.EventPayload, &job.CreatedAt, &job.ExpireAt, &encryptionKeyID,
 			&sqlJobStatusT.JobState, &sqlJobStatusT.AttemptNum,
 			&sqlJobStatusT.ExecTime, &sqlJobStatusT.RetryTime,
 			&sqlJobStatusT.ErrorCode, &sqlJobStatusT.ErrorResponse)
@@ -201,7 +202,7 @@ func (jd *HandleT) getNonMigratedJobsFromDS(ds dataSetT, count int) ([]*JobT, er
 		if sqlJobStatusT.JobState.Valid {
 			err = rows.Scan(&job.JobID, &job.UUID, &job.UserID,
 				&job.Parameters, &job.CustomVal,
-				&job.EventPayload, &job.CreatedAt, &job.ExpireAt,
+				&job.EventPayload, &job.CreatedAt, &job.ExpireAt, &encryptionKeyID,
 				&job.LastJobStatus.JobState, &job.LastJobStatus.AttemptNum,
 				&job.LastJobStatus.ExecTime, &job.LastJobStatus.RetryTime,
 				&job.LastJobStatus.ErrorCode, &job.LastJobStatus.ErrorResponse)
@@ -210,6 +211,10 @@ func (jd *HandleT) getNonMigratedJobsFromDS(ds dataSetT, count int) ([]*JobT, er
 				return nil, fmt.Errorf("scan rows: %w", err)
 			}
 		}
+		// jobs are exported decrypted, they will be encrypted again when stored by the importing node
+		if err := jd.decryptPayload(&job, encryptionKeyID); err != nil {
+			return nil, err
+		}
 		jobList = append(jobList, &job)
 	}
 
@@ -594,6 +594,13 @@ func TestRefreshDSList(t *testing.T) {
 	})
 }
 
+func TestJobHasExpired(t *testing.T) {
+	now := time.Now()
+	require.False(t, (&JobT{CreatedAt: now.Add(-time.Hour), ExpireAt: now.Add(-time.Hour)}).hasExpired(now), "jobs without an expiry time never expire")
+	require.True(t, (&JobT{ExpiryTime: now.Add(-time.Minute)}).hasExpired(now))
+	require.False(t, (&JobT{ExpiryTime: now.Add(time.Minute)}).hasExpired(now))
+}
+
 func TestJobsDBTimeout(t *testing.T) {
 	defaultWorkspaceID := "workspaceId"
 
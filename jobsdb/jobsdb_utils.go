@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rudderlabs/rudder-server/admin"
 	"github.com/rudderlabs/rudder-server/services/stats"
@@ -168,6 +169,21 @@ func checkValidJobState(jd assertInterface, stateFilters []string) {
 	}
 }
 
+// isTerminalState returns true if state is one of the terminal job states
+func isTerminalState(state string) bool {
+	for _, js := range jobStates {
+		if js.isTerminal && js.State == state {
+			return true
+		}
+	}
+	return false
+}
+
+// nullTime returns a NULL value for zero times, so that optional timestamps can be stored as such
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
 // constructQueryOR construct a query were paramKey is any of the values in paramValues
 func constructQueryOR(paramKey string, paramList []string) string {
 	var queryList []string
@@ -177,6 +193,15 @@ func constructQueryOR(paramKey string, paramList []string) string {
 	return "(" + strings.Join(queryList, " OR ") + ")"
 }
 
+// constructPriorityQuery constructs a query condition matching any of the provided priorities
+func constructPriorityQuery(alias string, priorities []int) string {
+	values := make([]string, len(priorities))
+	for i, priority := range priorities {
+		values[i] = strconv.Itoa(priority)
+	}
+	return alias + ".priority IN (" + strings.Join(values, ",") + ")"
+}
+
 // constructStateQuery construct query from provided state filters
 func constructStateQuery(alias, paramKey string, paramList []string, queryType string) string {
 	var queryList []string
@@ -218,6 +243,16 @@ func constructParameterJSONQuery(table string, parameterFilters []ParameterFilte
 	return fmt.Sprintf(`(%s.parameters @> '{%s}' %s)`, table, strings.Join(allKeyValues, ","), opQuery)
 }
 
+// constructExcludedParameterJSONQuery constructs a query matching the jobs which match none of the parameter filters
+func constructExcludedParameterJSONQuery(table string, parameterFilters []ParameterFilterT) string {
+	// eg. NOT (rt_jobs_1.parameters @> '{"destination_id":"<destination_id>"}' OR rt_jobs_1.parameters @> '{"destination_id":"<other_destination_id>"}')
+	conditions := make([]string, len(parameterFilters))
+	for i, parameter := range parameterFilters {
+		conditions[i] = fmt.Sprintf(`%s.parameters @> '{%q:%q}'`, table, parameter.Name, parameter.Value)
+	}
+	return "NOT (" + strings.Join(conditions, " OR ") + ")"
+}
+
 // Admin Handlers
 type JobsdbUtilsHandler struct{}
 
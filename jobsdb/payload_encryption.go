@@ -0,0 +1,101 @@
+package jobsdb
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/jobsdb/encryption"
+)
+
+var errInvalidJSON = errors.New("Invalid JSON")
+
+/*
+encryptPayload returns the event payload of the job as it needs to be stored in the jobs table, along with
+the id of the key used for encrypting it and the size of the plaintext payload.
+
+If payload encryption is enabled, the payload is stored as a json string containing the base64 encoded
+ciphertext (see encryption.Envelope), so that the event_payload column remains valid jsonb. Since postgres
+can no longer validate encrypted payloads, they are sanitized & validated before being encrypted.
+The plaintext size is stored next to encrypted payloads, so that payload size limits apply to the plaintext
+instead of the ciphertext (see payloadSizeColumn). The job itself is never modified.
+*/
+func (jd *HandleT) encryptPayload(job *JobT) (payload string, keyID sql.NullString, plaintextSize sql.NullInt64, err error) {
+	if jd.payloadEncryption == nil {
+		return string(job.EventPayload), sql.NullString{}, sql.NullInt64{}, nil
+	}
+	plaintext := sanitizeJson(job.EventPayload)
+	if !json.Valid(plaintext) {
+		return "", sql.NullString{}, sql.NullInt64{}, errInvalidJSON
+	}
+	ciphertext, id, err := jd.payloadEncryption.Encrypt(plaintext)
+	if err != nil {
+		return "", sql.NullString{}, sql.NullInt64{}, fmt.Errorf("encrypting payload of job %s: %w", job.UUID, err)
+	}
+	encoded, err := json.Marshal(base64.StdEncoding.EncodeToString(ciphertext))
+	if err != nil {
+		return "", sql.NullString{}, sql.NullInt64{}, err
+	}
+	return string(encoded), sql.NullString{String: id, Valid: true}, sql.NullInt64{Int64: int64(len(plaintext)), Valid: true}, nil
+}
+
+// decryptPayload replaces the event payload of a job read from the jobs table with its decrypted form, if the job's
+// payload was stored encrypted, i.e. it has an encryption key id.
+func (jd *HandleT) decryptPayload(job *JobT, keyID sql.NullString) error {
+	return decryptJobPayload(jd.payloadEncryption, job, keyID)
+}
+
+// DecryptPayload decrypts an event payload which was stored encrypted using the key with the provided id,
+// e.g. an event payload found in a backup file along with its encryption_key_id.
+func (jd *HandleT) DecryptPayload(payload json.RawMessage, keyID string) (json.RawMessage, error) {
+	return decryptEventPayload(jd.payloadEncryption, payload, keyID)
+}
+
+// decryptJobPayload is decryptPayload for jobsdb handles other than HandleT, e.g. ReadonlyHandleT
+func decryptJobPayload(envelope *encryption.Envelope, job *JobT, keyID sql.NullString) error {
+	if !keyID.Valid {
+		return nil
+	}
+	plaintext, err := decryptEventPayload(envelope, job.EventPayload, keyID.String)
+	if err != nil {
+		return fmt.Errorf("decrypting payload of job %d: %w", job.JobID, err)
+	}
+	job.EventPayload = plaintext
+	return nil
+}
+
+func decryptEventPayload(envelope *encryption.Envelope, payload json.RawMessage, keyID string) (json.RawMessage, error) {
+	if envelope == nil {
+		return nil, fmt.Errorf("payload is encrypted with key %q but payload encryption is not enabled", keyID)
+	}
+	var encoded string
+	if err := json.Unmarshal(payload, &encoded); err != nil {
+		return nil, fmt.Errorf("encrypted payload should be a json string: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return envelope.Decrypt(ciphertext, keyID)
+}
+
+// payloadEncryptionFromConfig returns the envelope for decrypting payloads using the keys of JobsDB.payloadEncryption.keyFile,
+// or nil if no key file is configured
+func payloadEncryptionFromConfig() (*encryption.Envelope, error) {
+	keyFile := config.GetString("JobsDB.payloadEncryption.keyFile", "")
+	if keyFile == "" {
+		return nil, nil
+	}
+	keys, err := encryption.NewKeyFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading payload encryption keys: %w", err)
+	}
+	return encryption.NewEnvelope(keys), nil
+}
+
+// payloadSizeColumn is the size of a job's event payload as used for payload size limits: the plaintext size of
+// encrypted payloads, the stored size otherwise
+const payloadSizeColumn = "COALESCE(jobs.plaintext_size, pg_column_size(jobs.event_payload))"
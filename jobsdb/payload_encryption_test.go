@@ -0,0 +1,65 @@
+package jobsdb
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/jobsdb/encryption"
+)
+
+func TestPayloadEncryption(t *testing.T) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "keys.json")
+	data, err := json.Marshal(map[string]interface{}{"current": "key-1", "keys": map[string]string{"key-1": base64.StdEncoding.EncodeToString(key)}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyFile, data, 0o600))
+	keys, err := encryption.NewKeyFile(keyFile)
+	require.NoError(t, err)
+
+	job := &JobT{JobID: 1, UUID: uuid.Must(uuid.NewV4()), EventPayload: []byte(`{"traits":{"email":"user@example.com","name":"a\u0000b"}}`)}
+
+	t.Run("disabled", func(t *testing.T) {
+		jd := &HandleT{}
+		payload, keyID, plaintextSize, err := jd.encryptPayload(job)
+		require.NoError(t, err)
+		require.Equal(t, string(job.EventPayload), payload)
+		require.False(t, keyID.Valid)
+		require.False(t, plaintextSize.Valid, "plaintext payloads are sized by postgres")
+
+		stored := &JobT{JobID: 1, EventPayload: []byte(`"ZW5jcnlwdGVk"`)}
+		require.Error(t, jd.decryptPayload(stored, sql.NullString{String: "key-1", Valid: true}), "encrypted payloads cannot be read without keys")
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		jd := &HandleT{}
+		WithPayloadEncryption(keys)(jd)
+		payload, keyID, plaintextSize, err := jd.encryptPayload(job)
+		require.NoError(t, err)
+		require.Equal(t, sql.NullString{String: "key-1", Valid: true}, keyID)
+		require.Equal(t, sql.NullInt64{Int64: int64(len(`{"traits":{"email":"user@example.com","name":"ab"}}`)), Valid: true}, plaintextSize, "payload size limits should apply on the plaintext")
+		require.NotContains(t, payload, "user@example.com")
+		require.True(t, json.Valid([]byte(payload)), "encrypted payload should be valid json")
+		require.Contains(t, string(job.EventPayload), "user@example.com", "job shouldn't be modified")
+
+		stored := &JobT{JobID: 1, EventPayload: []byte(payload)}
+		require.NoError(t, jd.decryptPayload(stored, keyID))
+		require.JSONEq(t, `{"traits":{"email":"user@example.com","name":"ab"}}`, string(stored.EventPayload), "payload should be sanitized before encrypted")
+
+		plain := &JobT{JobID: 2, EventPayload: []byte(`{"plain":true}`)}
+		require.NoError(t, jd.decryptPayload(plain, sql.NullString{}), "payloads stored before enabling encryption should remain readable")
+		require.JSONEq(t, `{"plain":true}`, string(plain.EventPayload))
+
+		_, _, _, err = jd.encryptPayload(&JobT{EventPayload: []byte(`{"invalid":`)})
+		require.ErrorIs(t, err, errInvalidJSON)
+	})
+}
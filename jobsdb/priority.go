@@ -0,0 +1,104 @@
+package jobsdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Job priorities, see JobT.Priority
+const (
+	LowPriority    = -1
+	NormalPriority = 0
+	HighPriority   = 1
+)
+
+// Priorities are all the job priorities, in descending order
+var Priorities = []int{HighPriority, NormalPriority, LowPriority}
+
+// ParsePriority parses a priority name, i.e. high, normal or low
+func ParsePriority(name string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "high":
+		return HighPriority, nil
+	case "normal", "":
+		return NormalPriority, nil
+	case "low":
+		return LowPriority, nil
+	default:
+		return NormalPriority, fmt.Errorf("invalid priority: %q", name)
+	}
+}
+
+/*
+QueryByPriority returns a query which is fetching jobs in priority lanes: jobs of a higher priority are
+returned before any job of a lower priority, regardless of the dataset they belong to, while jobs of the
+same priority are returned in job id order.
+
+Each priority lane is a separate query (see GetQueryParamsT.PriorityFilters), with the limits of every query
+reduced by the jobs fetched from the previous lanes. Lanes are not queried at all if the caller already provides
+priority filters.
+*/
+func QueryByPriority(query QueryFunc) QueryFunc {
+	return func(ctx context.Context, params GetQueryParamsT) (JobsResult, error) {
+		if len(params.PriorityFilters) > 0 {
+			return query(ctx, params)
+		}
+		var result JobsResult
+		for _, priority := range Priorities {
+			params.PriorityFilters = []int{priority}
+			res, err := query(ctx, params)
+			if err != nil {
+				return JobsResult{}, err
+			}
+			result.Jobs = append(result.Jobs, res.Jobs...)
+			result.EventsCount += res.EventsCount
+			result.PayloadSize += res.PayloadSize
+			if res.LimitsReached {
+				result.LimitsReached = true
+				break
+			}
+			updateParams(&params, res)
+		}
+		return result, nil
+	}
+}
+
+// GetAllJobsByPriority is the MultiTenantJobsDB.GetAllJobs equivalent of QueryByPriority: jobs of each priority lane are
+// fetched separately, in descending priority order, with each workspace's count reduced by the jobs fetched from
+// the previous lanes.
+func GetAllJobsByPriority(ctx context.Context, db MultiTenantJobsDB, workspaceCount map[string]int, params GetQueryParamsT, maxDSQuerySize int) ([]*JobT, error) { // skipcq: CRT-P0003
+	remaining := make(map[string]int, len(workspaceCount))
+	for workspace, count := range workspaceCount {
+		if count > 0 {
+			remaining[workspace] = count
+		}
+	}
+	var list []*JobT
+	payloadLimited := params.PayloadSizeLimit > 0
+	for _, priority := range Priorities {
+		laneCount := make(map[string]int, len(remaining))
+		for workspace, count := range remaining {
+			laneCount[workspace] = count
+		}
+		params.PriorityFilters = []int{priority}
+		jobs, err := db.GetAllJobs(ctx, laneCount, params, maxDSQuerySize)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, jobs...)
+		params.JobsLimit -= len(jobs)
+		for _, job := range jobs {
+			if payloadLimited {
+				params.PayloadSizeLimit -= job.PayloadSize
+			}
+			if remaining[job.WorkspaceId]--; remaining[job.WorkspaceId] <= 0 {
+				delete(remaining, job.WorkspaceId)
+			}
+		}
+		if len(remaining) == 0 || params.JobsLimit <= 0 || payloadLimited && params.PayloadSizeLimit <= 0 {
+			break
+		}
+	}
+	return list, nil
+}
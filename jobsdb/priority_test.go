@@ -0,0 +1,127 @@
+package jobsdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePriority(t *testing.T) {
+	for name, expected := range map[string]int{"high": HighPriority, " High": HighPriority, "normal": NormalPriority, "": NormalPriority, "LOW": LowPriority} {
+		priority, err := ParsePriority(name)
+		require.NoError(t, err)
+		require.Equal(t, expected, priority, name)
+	}
+	priority, err := ParsePriority("urgent")
+	require.Error(t, err)
+	require.Equal(t, NormalPriority, priority)
+}
+
+func TestPriorityLanes(t *testing.T) {
+	initEmbeddedJobsDB()
+	ctx := context.Background()
+
+	// stores 2 low, 2 normal & 2 high priority jobs per workspace, in this order
+	setup := func(t *testing.T, workspaces ...string) (*EmbeddedHandleT, map[int][]*JobT) {
+		jd := newTestEmbeddedJobsDB(t, t.TempDir())
+		byPriority := map[int][]*JobT{}
+		for _, priority := range []int{LowPriority, NormalPriority, HighPriority} {
+			for _, workspace := range workspaces {
+				jobs := genEmbeddedJobs(workspace, "GW", 2, 1)
+				for _, job := range jobs {
+					job.Priority = priority
+				}
+				require.NoError(t, jd.Store(ctx, jobs))
+				byPriority[priority] = append(byPriority[priority], jobs...)
+			}
+		}
+		return jd, byPriority
+	}
+
+	t.Run("priority filters", func(t *testing.T) {
+		jd, byPriority := setup(t, "ws")
+		defer jd.TearDown()
+
+		res, err := jd.GetUnprocessed(ctx, GetQueryParamsT{JobsLimit: 100, PriorityFilters: []int{HighPriority, LowPriority}})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 4)
+		require.Equal(t, byPriority[LowPriority][0].JobID, res.Jobs[0].JobID, "jobs should be returned in job id order")
+		require.Equal(t, HighPriority, res.Jobs[3].Priority)
+	})
+
+	t.Run("query by priority", func(t *testing.T) {
+		jd, byPriority := setup(t, "ws")
+		defer jd.TearDown()
+
+		res, err := QueryByPriority(jd.GetUnprocessed)(ctx, GetQueryParamsT{JobsLimit: 100})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 6)
+		require.False(t, res.LimitsReached)
+		require.Equal(t, 6, res.EventsCount)
+		var expected []int64
+		for _, priority := range Priorities {
+			for _, job := range byPriority[priority] {
+				expected = append(expected, job.JobID)
+			}
+		}
+		for i := range res.Jobs {
+			require.Equal(t, expected[i], res.Jobs[i].JobID)
+		}
+
+		res, err = QueryByPriority(jd.GetUnprocessed)(ctx, GetQueryParamsT{JobsLimit: 3})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 3)
+		require.True(t, res.LimitsReached)
+		require.Equal(t, HighPriority, res.Jobs[1].Priority)
+		require.Equal(t, NormalPriority, res.Jobs[2].Priority)
+
+		res, err = QueryByPriority(jd.GetUnprocessed)(ctx, GetQueryParamsT{JobsLimit: 100, PriorityFilters: []int{LowPriority}})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 2, "provided priority filters should be respected")
+	})
+
+	t.Run("get all jobs by priority", func(t *testing.T) {
+		jd, _ := setup(t, "ws")
+		defer jd.TearDown()
+
+		workspaceCount := map[string]int{"ws": 3}
+		jobs, err := GetAllJobsByPriority(ctx, jd, workspaceCount, GetQueryParamsT{JobsLimit: 3}, 10)
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{"ws": 3}, workspaceCount, "workspace counts shouldn't be modified")
+		require.Len(t, jobs, 3)
+		require.Equal(t, HighPriority, jobs[0].Priority)
+		require.Equal(t, HighPriority, jobs[1].Priority)
+		require.Equal(t, NormalPriority, jobs[2].Priority)
+	})
+
+	t.Run("get all jobs by priority reduces workspace counts per lane", func(t *testing.T) {
+		db := &laneRecordingJobsDB{jobs: map[int][]*JobT{
+			HighPriority:   {{JobID: 3, WorkspaceId: "ws-1", PayloadSize: 10}},
+			NormalPriority: {{JobID: 1, WorkspaceId: "ws-1", PayloadSize: 10}, {JobID: 2, WorkspaceId: "ws-2", PayloadSize: 10}},
+			LowPriority:    {{JobID: 4, WorkspaceId: "ws-2", PayloadSize: 10}},
+		}}
+		jobs, err := GetAllJobsByPriority(ctx, db, map[string]int{"ws-1": 2, "ws-2": 1, "ws-3": 0}, GetQueryParamsT{JobsLimit: 3, PayloadSizeLimit: 100}, 10)
+		require.NoError(t, err)
+		require.Len(t, jobs, 3)
+		require.Equal(t, []map[string]int{{"ws-1": 2, "ws-2": 1}, {"ws-1": 1, "ws-2": 1}}, db.workspaceCounts)
+		require.Equal(t, []int{3, 2}, db.jobsLimits)
+		require.Equal(t, []int64{100, 90}, db.payloadLimits)
+	})
+}
+
+// laneRecordingJobsDB returns the configured jobs for each priority lane, recording the queries' limits
+type laneRecordingJobsDB struct {
+	MultiTenantJobsDB
+	jobs            map[int][]*JobT
+	workspaceCounts []map[string]int
+	jobsLimits      []int
+	payloadLimits   []int64
+}
+
+func (db *laneRecordingJobsDB) GetAllJobs(_ context.Context, workspaceCount map[string]int, params GetQueryParamsT, _ int) ([]*JobT, error) {
+	db.workspaceCounts = append(db.workspaceCounts, workspaceCount)
+	db.jobsLimits = append(db.jobsLimits, params.JobsLimit)
+	db.payloadLimits = append(db.payloadLimits, params.PayloadSizeLimit)
+	return db.jobs[params.PriorityFilters[0]], nil
+}
@@ -9,7 +9,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/tidwall/gjson"
+
 	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/jobsdb/encryption"
 	"github.com/rudderlabs/rudder-server/utils/logger"
 
 	"github.com/rudderlabs/rudder-server/services/stats"
@@ -34,9 +37,10 @@ type ReadonlyJobsDB interface {
 }
 
 type ReadonlyHandleT struct {
-	DbHandle    *sql.DB
-	tablePrefix string
-	logger      logger.LoggerI
+	DbHandle          *sql.DB
+	tablePrefix       string
+	logger            logger.LoggerI
+	payloadEncryption *encryption.Envelope
 }
 
 type DSPair struct {
@@ -102,6 +106,10 @@ func (jd *ReadonlyHandleT) Setup(tablePrefix string) {
 	err = jd.DbHandle.PingContext(ctx)
 	jd.assertError(err)
 
+	// payloads which were stored encrypted need to be decrypted for reading them
+	jd.payloadEncryption, err = payloadEncryptionFromConfig()
+	jd.assertError(err)
+
 	jd.logger.Infof("Readonly user connected to %s DB", tablePrefix)
 }
 
@@ -235,23 +243,24 @@ func (jd *ReadonlyHandleT) getUnprocessedJobsDSCount(ctx context.Context, ds dat
 
 	var selectColumn string
 	if jd.tablePrefix == "gw" {
-		selectColumn = "event_payload->'batch' as batch"
+		selectColumn = fmt.Sprintf("%[1]s.event_payload->'batch' as batch", ds.JobTable)
 	} else {
 		selectColumn = "COUNT(*)"
 	}
-	sqlStatement = fmt.Sprintf(`SELECT %[3]s FROM %[1]s LEFT JOIN %[2]s ON %[1]s.job_id=%[2]s.job_id
-											 WHERE %[2]s.job_id is NULL`, ds.JobTable, ds.JobStatusTable, selectColumn)
+	fromQuery := fmt.Sprintf(` FROM %[1]s LEFT JOIN %[2]s ON %[1]s.job_id=%[2]s.job_id
+											 WHERE %[2]s.job_id is NULL`, ds.JobTable, ds.JobStatusTable)
 
 	if len(customValFilters) > 0 {
-		sqlStatement += " AND " + constructQueryOR(fmt.Sprintf("%s.custom_val", ds.JobTable), customValFilters)
+		fromQuery += " AND " + constructQueryOR(fmt.Sprintf("%s.custom_val", ds.JobTable), customValFilters)
 	}
 
 	if len(parameterFilters) > 0 {
-		sqlStatement += " AND " + constructParameterJSONQuery(ds.JobTable, parameterFilters)
+		fromQuery += " AND " + constructParameterJSONQuery(ds.JobTable, parameterFilters)
 	}
+	sqlStatement = "SELECT " + selectColumn + fromQuery
 
 	if jd.tablePrefix == "gw" {
-		sqlStatement = fmt.Sprintf("select sum(jsonb_array_length(batch)) from (%s) t", sqlStatement)
+		sqlStatement = fmt.Sprintf("select sum(jsonb_array_length(batch)) from (%s AND %s.encryption_key_id IS NULL) t", sqlStatement, ds.JobTable)
 	}
 
 	jd.logger.Debug(sqlStatement)
@@ -271,6 +280,16 @@ func (jd *ReadonlyHandleT) getUnprocessedJobsDSCount(ctx context.Context, ds dat
 		return 0, err
 	}
 
+	if jd.tablePrefix == "gw" {
+		encryptedCount, err := jd.countEncryptedEvents(txn, ds, fromQuery)
+		if err != nil {
+			jd.logger.Errorf("Returning 0 because failed to count encrypted unprocessed events of dataset: %v. Err: %s", ds, err.Error())
+			return 0, err
+		}
+		count.Int64 += encryptedCount
+		count.Valid = count.Valid || encryptedCount > 0
+	}
+
 	if count.Valid {
 		return count.Int64, nil
 	}
@@ -391,7 +410,7 @@ func (jd *ReadonlyHandleT) getProcessedJobsDSCount(ctx context.Context, ds dataS
 	} else {
 		selectColumn = fmt.Sprintf("COUNT(%[1]s.job_id)", ds.JobTable)
 	}
-	sqlStatement = fmt.Sprintf(`SELECT %[6]s FROM
+	fromQuery := fmt.Sprintf(` FROM
                                                %[1]s,
                                                (SELECT job_id, retry_time FROM %[2]s WHERE id IN
                                                    (SELECT MAX(id) from %[2]s GROUP BY job_id) %[3]s)
@@ -399,15 +418,17 @@ func (jd *ReadonlyHandleT) getProcessedJobsDSCount(ctx context.Context, ds dataS
                                             WHERE %[1]s.job_id=job_latest_state.job_id
                                              %[4]s %[5]s
                                              AND job_latest_state.retry_time < $1`,
-		ds.JobTable, ds.JobStatusTable, stateQuery, customValQuery, sourceQuery, selectColumn)
+		ds.JobTable, ds.JobStatusTable, stateQuery, customValQuery, sourceQuery)
+	sqlStatement = "SELECT " + selectColumn + fromQuery
 
 	if jd.tablePrefix == "gw" {
-		sqlStatement = fmt.Sprintf("select sum(jsonb_array_length(batch)) from (%s) t", sqlStatement)
+		sqlStatement = fmt.Sprintf("select sum(jsonb_array_length(batch)) from (%s AND %s.encryption_key_id IS NULL) t", sqlStatement, ds.JobTable)
 	}
 
 	jd.logger.Debug(sqlStatement)
 
-	row := txn.QueryRow(sqlStatement, time.Now())
+	now := time.Now()
+	row := txn.QueryRow(sqlStatement, now)
 	defer func() {
 		if err := txn.Rollback(); err != nil {
 			jd.logger.Errorf("Transaction rollback (lock release) on ds(%v) commit err. Err: %v", ds, err)
@@ -421,6 +442,16 @@ func (jd *ReadonlyHandleT) getProcessedJobsDSCount(ctx context.Context, ds dataS
 		return 0, err
 	}
 
+	if jd.tablePrefix == "gw" {
+		encryptedCount, err := jd.countEncryptedEvents(txn, ds, fromQuery, now)
+		if err != nil {
+			jd.logger.Errorf("Returning 0 because failed to count encrypted processed events of dataset: %v. Err: %s", ds, err.Error())
+			return 0, err
+		}
+		count.Int64 += encryptedCount
+		count.Valid = count.Valid || encryptedCount > 0
+	}
+
 	if count.Valid {
 		return count.Int64, nil
 	}
@@ -429,6 +460,31 @@ func (jd *ReadonlyHandleT) getProcessedJobsDSCount(ctx context.Context, ds dataS
 	return 0, nil
 }
 
+// countEncryptedEvents returns the number of events in the batches of the gateway jobs matched by fromQuery whose payloads
+// were stored encrypted, since these can only be counted after being decrypted
+func (jd *ReadonlyHandleT) countEncryptedEvents(txn *sql.Tx, ds dataSetT, fromQuery string, args ...interface{}) (int64, error) {
+	sqlStatement := fmt.Sprintf("SELECT %[1]s.job_id, %[1]s.event_payload, %[1]s.encryption_key_id", ds.JobTable) + fromQuery +
+		fmt.Sprintf(" AND %s.encryption_key_id IS NOT NULL", ds.JobTable)
+	rows, err := txn.Query(sqlStatement, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+	var count int64
+	for rows.Next() {
+		var job JobT
+		var keyID sql.NullString
+		if err := rows.Scan(&job.JobID, &job.EventPayload, &keyID); err != nil {
+			return 0, err
+		}
+		if err := decryptJobPayload(jd.payloadEncryption, &job, keyID); err != nil {
+			return 0, err
+		}
+		count += gjson.GetBytes(job.EventPayload, "batch.#").Int()
+	}
+	return count, rows.Err()
+}
+
 func getStatusPrefix(jobPrefix string) string {
 	var response string
 	switch jobPrefix {
@@ -619,7 +675,7 @@ func (jd *ReadonlyHandleT) GetJobByID(job_id, prefix string) (string, error) {
 		}
 		sqlStatement = fmt.Sprintf(`SELECT
 						%[1]s.job_id, %[1]s.uuid, %[1]s.user_id, %[1]s.parameters, %[1]s.custom_val, %[1]s.event_payload,
-						%[1]s.created_at, %[1]s.expire_at,
+						%[1]s.created_at, %[1]s.expire_at, %[1]s.encryption_key_id,
 						job_latest_state.job_state, job_latest_state.attempt,
 						job_latest_state.exec_time, job_latest_state.retry_time,
 						job_latest_state.error_code, job_latest_state.error_response
@@ -634,25 +690,29 @@ func (jd *ReadonlyHandleT) GetJobByID(job_id, prefix string) (string, error) {
 					WHERE %[1]s.job_id = %[3]s;`, dsPair.JobTable, dsPair.JobStatusTable, job_id)
 
 		event := JobT{}
+		var encryptionKeyID sql.NullString
 		row = jd.DbHandle.QueryRow(sqlStatement)
 		err = row.Scan(&event.JobID, &event.UUID, &event.UserID, &event.Parameters, &event.CustomVal, &event.EventPayload,
-			&event.CreatedAt, &event.ExpireAt, &event.LastJobStatus.JobState, &event.LastJobStatus.AttemptNum,
+			&event.CreatedAt, &event.ExpireAt, &encryptionKeyID, &event.LastJobStatus.JobState, &event.LastJobStatus.AttemptNum,
 			&event.LastJobStatus.ExecTime, &event.LastJobStatus.RetryTime, &event.LastJobStatus.ErrorCode,
 			&event.LastJobStatus.ErrorResponse)
 		if err != nil {
 			sqlStatement = fmt.Sprintf(`SELECT
 						%[1]s.job_id, %[1]s.uuid, %[1]s.user_id, %[1]s.parameters, %[1]s.custom_val, %[1]s.event_payload,
-						%[1]s.created_at, %[1]s.expire_at
+						%[1]s.created_at, %[1]s.expire_at, %[1]s.encryption_key_id
 					FROM
 						%[1]s
 					WHERE %[1]s.job_id = %[2]s;`, dsPair.JobTable, job_id)
 			row = jd.DbHandle.QueryRow(sqlStatement)
 			err1 := row.Scan(&event.JobID, &event.UUID, &event.UserID, &event.Parameters, &event.CustomVal, &event.EventPayload,
-				&event.CreatedAt, &event.ExpireAt)
+				&event.CreatedAt, &event.ExpireAt, &encryptionKeyID)
 			if err1 != nil {
 				return "", err1
 			}
 		}
+		if err := decryptJobPayload(jd.payloadEncryption, &event, encryptionKeyID); err != nil {
+			return "", err
+		}
 		response, err = json.MarshalIndent(event, "", " ")
 		if err != nil {
 			return "", err
@@ -41,7 +41,7 @@ func (*MultiTenantHandleT) getSingleWorkspaceQueryString(workspace string, jobsL
 	sqlStatement = fmt.Sprintf(
 		`SELECT
 			jobs.job_id, jobs.uuid, jobs.user_id, jobs.parameters, jobs.custom_val, jobs.event_payload, jobs.event_count,
-			jobs.created_at, jobs.expire_at, jobs.workspace_id,
+			jobs.created_at, jobs.expire_at, jobs.workspace_id, jobs.expiry_time, jobs.priority, jobs.encryption_key_id,
 			jobs.payload_size,
 			sum(jobs.payload_size) over (order by jobs.job_id) as running_payload_size,
 			jobs.job_state, jobs.attempt,
@@ -62,6 +62,19 @@ func (*MultiTenantHandleT) getSingleWorkspaceQueryString(workspace string, jobsL
 // All Jobs
 
 func (mj *MultiTenantHandleT) GetAllJobs(ctx context.Context, workspaceCount map[string]int, params GetQueryParamsT, maxDSQuerySize int) ([]*JobT, error) { // skipcq: CRT-P0003
+	jobs, expiredJobs, err := mj.getAllJobs(ctx, workspaceCount, params, maxDSQuerySize)
+	if err != nil {
+		return nil, err
+	}
+	if len(expiredJobs) > 0 {
+		if err := mj.abortExpiredJobs(ctx, expiredJobs); err != nil {
+			return nil, err
+		}
+	}
+	return jobs, nil
+}
+
+func (mj *MultiTenantHandleT) getAllJobs(ctx context.Context, workspaceCount map[string]int, params GetQueryParamsT, maxDSQuerySize int) ([]*JobT, []*JobT, error) { // skipcq: CRT-P0003
 	// The order of lock is very important. The migrateDSLoop
 	// takes lock in this order so reversing this will cause
 	// deadlocks
@@ -72,6 +85,7 @@ func (mj *MultiTenantHandleT) GetAllJobs(ctx context.Context, workspaceCount map
 
 	dsList := mj.getDSList()
 	outJobs := make([]*JobT, 0)
+	var expiredJobs []*JobT
 
 	workspacePayloadLimitMap := make(map[string]int64)
 	for workspace, count := range workspaceCount {
@@ -87,14 +101,18 @@ func (mj *MultiTenantHandleT) GetAllJobs(ctx context.Context, workspaceCount map
 		CustomValFilters:              params.CustomValFilters,
 		ParameterFilters:              params.ParameterFilters,
 		StateFilters:                  params.StateFilters,
+		PriorityFilters:               params.PriorityFilters,
+		ExcludedParameterFilters:      params.ExcludedParameterFilters,
+		AfterJobID:                    params.AfterJobID,
 	}
 	start := time.Now()
 	for _, ds := range dsList {
-		jobs, err := mj.getUnionDS(ctx, ds, workspaceCount, workspacePayloadLimitMap, conditions)
+		jobs, expired, err := mj.getUnionDS(ctx, ds, workspaceCount, workspacePayloadLimitMap, conditions)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		outJobs = append(outJobs, jobs...)
+		expiredJobs = append(expiredJobs, expired...)
 		if len(jobs) > 0 {
 			tablesQueried++
 		}
@@ -111,15 +129,16 @@ func (mj *MultiTenantHandleT) GetAllJobs(ctx context.Context, workspaceCount map
 
 	mj.tablesQueriedStat.Gauge(tablesQueried)
 
-	return outJobs, nil
+	return outJobs, expiredJobs, nil
 }
 
-func (mj *MultiTenantHandleT) getUnionDS(ctx context.Context, ds dataSetT, workspaceCount map[string]int, workspacePayloadLimitMap map[string]int64, conditions QueryConditions) ([]*JobT, error) { // skipcq: CRT-P0003
-	var jobList []*JobT
+// getUnionDS returns the jobs of the dataset for the requested workspaces, along with any expired jobs which were left out
+func (mj *MultiTenantHandleT) getUnionDS(ctx context.Context, ds dataSetT, workspaceCount map[string]int, workspacePayloadLimitMap map[string]int64, conditions QueryConditions) ([]*JobT, []*JobT, error) { // skipcq: CRT-P0003
+	var jobList, expiredList []*JobT
 	queryString, workspacesToQuery := mj.getUnionQuerystring(workspaceCount, workspacePayloadLimitMap, ds, conditions)
 
 	if len(workspacesToQuery) == 0 {
-		return jobList, nil
+		return jobList, expiredList, nil
 	}
 	for _, workspace := range workspacesToQuery {
 		mj.markClearEmptyResult(ds, workspace, conditions.StateFilters, conditions.CustomValFilters, conditions.ParameterFilters,
@@ -127,8 +146,11 @@ func (mj *MultiTenantHandleT) getUnionDS(ctx context.Context, ds dataSetT, works
 	}
 
 	cacheUpdateByWorkspace := make(map[string]string)
-	for _, workspace := range workspacesToQuery {
-		cacheUpdateByWorkspace[workspace] = string(noJobs)
+	// an empty result for a subset of the jobs' ids, priorities or parameters doesn't mean that there are no jobs for the workspace
+	if conditions.AfterJobID <= 0 && len(conditions.PriorityFilters) == 0 && len(conditions.ExcludedParameterFilters) == 0 {
+		for _, workspace := range workspacesToQuery {
+			cacheUpdateByWorkspace[workspace] = string(noJobs)
+		}
 	}
 
 	var rows *sql.Rows
@@ -137,7 +159,7 @@ func (mj *MultiTenantHandleT) getUnionDS(ctx context.Context, ds dataSetT, works
 	stmt, err := mj.dbHandle.PrepareContext(ctx, queryString)
 	mj.logger.Debug(queryString)
 	if err != nil {
-		return jobList, err
+		return jobList, expiredList, err
 	}
 	defer func(stmt *sql.Stmt) {
 		err := stmt.Close()
@@ -146,9 +168,10 @@ func (mj *MultiTenantHandleT) getUnionDS(ctx context.Context, ds dataSetT, works
 		}
 	}(stmt)
 
-	rows, err = stmt.QueryContext(ctx, getTimeNowFunc())
+	now := getTimeNowFunc()
+	rows, err = stmt.QueryContext(ctx, now)
 	if err != nil {
-		return jobList, err
+		return jobList, expiredList, err
 	}
 	defer func(rows *sql.Rows) {
 		err := rows.Close()
@@ -171,12 +194,18 @@ func (mj *MultiTenantHandleT) getUnionDS(ctx context.Context, ds dataSetT, works
 		// So look for the possible scenarios
 		var _nullER sql.NullString
 		var _nullSP sql.NullString
+		var expiryTime sql.NullTime
+		var encryptionKeyID sql.NullString
 		err = rows.Scan(&job.JobID, &job.UUID, &job.UserID, &job.Parameters, &job.CustomVal,
-			&job.EventPayload, &job.EventCount, &job.CreatedAt, &job.ExpireAt, &job.WorkspaceId, &job.PayloadSize, &_null,
+			&job.EventPayload, &job.EventCount, &job.CreatedAt, &job.ExpireAt, &job.WorkspaceId, &expiryTime, &job.Priority, &encryptionKeyID, &job.PayloadSize, &_null,
 			&_nullJS, &_nullA, &_nullET, &_nullRT, &_nullEC, &_nullER, &_nullSP)
 		if err != nil {
-			return jobList, err
+			return jobList, expiredList, err
+		}
+		if err = mj.decryptPayload(&job, encryptionKeyID); err != nil {
+			return jobList, expiredList, err
 		}
+		job.ExpiryTime = expiryTime.Time
 
 		job.LastJobStatus = JobStatusT{}
 		if _nullJS.Valid {
@@ -201,6 +230,11 @@ func (mj *MultiTenantHandleT) getUnionDS(ctx context.Context, ds dataSetT, works
 			job.LastJobStatus.Parameters = json.RawMessage(_nullSP.String)
 		}
 
+		cacheUpdateByWorkspace[job.WorkspaceId] = string(hasJobs)
+		if job.hasExpired(now) {
+			expiredList = append(expiredList, &job)
+			continue
+		}
 		jobList = append(jobList, &job)
 
 		workspaceCount[job.WorkspaceId] -= 1
@@ -217,11 +251,9 @@ func (mj *MultiTenantHandleT) getUnionDS(ctx context.Context, ds dataSetT, works
 				delete(workspacePayloadLimitMap, job.WorkspaceId)
 			}
 		}
-
-		cacheUpdateByWorkspace[job.WorkspaceId] = string(hasJobs)
 	}
 	if err = rows.Err(); err != nil {
-		return jobList, err
+		return jobList, expiredList, err
 	}
 
 	// do cache stuff here
@@ -231,7 +263,7 @@ func (mj *MultiTenantHandleT) getUnionDS(ctx context.Context, ds dataSetT, works
 			cacheValue(cacheUpdate), &_willTryToSet)
 	}
 
-	return jobList, err
+	return jobList, expiredList, err
 }
 
 func (mj *MultiTenantHandleT) getUnionQuerystring(workspaceCount map[string]int, workspacePayloadLimitMap map[string]int64, ds dataSetT, conditions QueryConditions) (string, []string) {
@@ -266,7 +298,7 @@ func (*MultiTenantHandleT) getInitialSingleWorkspaceQueryString(ds dataSetT, con
 	}
 	workspaceString := "(" + strings.Join(workspaceArray, ", ") + ")"
 
-	var stateQuery, customValQuery, limitQuery, sourceQuery string
+	var stateQuery, customValQuery, limitQuery, sourceQuery, priorityQuery string
 
 	if len(stateFilters) > 0 {
 		stateQuery = "AND (" + constructStateQuery("job_latest_state", "job_state", stateFilters, "OR") + ")"
@@ -289,13 +321,25 @@ func (*MultiTenantHandleT) getInitialSingleWorkspaceQueryString(ds dataSetT, con
 		sourceQuery = ""
 	}
 
+	if len(conditions.PriorityFilters) > 0 {
+		priorityQuery = " AND " + constructPriorityQuery("jobs", conditions.PriorityFilters)
+	}
+
+	if len(conditions.ExcludedParameterFilters) > 0 {
+		sourceQuery += " AND " + constructExcludedParameterJSONQuery("jobs", conditions.ExcludedParameterFilters)
+	}
+
+	if conditions.AfterJobID > 0 {
+		sourceQuery += fmt.Sprintf(" AND jobs.job_id > %d", conditions.AfterJobID)
+	}
+
 	sqlStatement = fmt.Sprintf(
 		`with rt_jobs_view AS (
 			SELECT
 				jobs.job_id, jobs.uuid, jobs.user_id, jobs.parameters, jobs.custom_val,
 				jobs.event_payload, jobs.event_count, jobs.created_at,
-				jobs.expire_at, jobs.workspace_id,
-				pg_column_size(jobs.event_payload) as payload_size,
+				jobs.expire_at, jobs.workspace_id, jobs.expiry_time, jobs.priority, jobs.encryption_key_id,
+				`+payloadSizeColumn+` as payload_size,
 				job_latest_state.job_state, job_latest_state.attempt,
 				job_latest_state.exec_time, job_latest_state.retry_time,
 				job_latest_state.error_code, job_latest_state.error_response,
@@ -315,7 +359,7 @@ func (*MultiTenantHandleT) getInitialSingleWorkspaceQueryString(ds dataSetT, con
 						)
 				) AS job_latest_state ON jobs.job_id = job_latest_state.job_id
 			WHERE
-				jobs.workspace_id IN %[7]s %[3]s %[4]s %[5]s %[6]s`,
-		ds.JobTable, ds.JobStatusTable, stateQuery, customValQuery, sourceQuery, limitQuery, workspaceString)
+				jobs.workspace_id IN %[7]s %[3]s %[4]s %[5]s %[8]s %[6]s`,
+		ds.JobTable, ds.JobStatusTable, stateQuery, customValQuery, sourceQuery, limitQuery, workspaceString, priorityQuery)
 	return sqlStatement + ")"
 }
@@ -0,0 +1,145 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/app"
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/services/alert"
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+)
+
+// degradedModeComponent is the component reported as unhealthy while the processor is in degraded mode
+const degradedModeComponent = "transformer"
+
+/*
+degradedModeT keeps the processor running while the transformer is unreachable, instead of restarting the server in a loop.
+
+Requests to the transformer which run out of retries enter degraded mode and block until the transformer's health
+endpoint responds again, so that their batch of gateway jobs gets transformed once, as if nothing happened.
+While degraded, the processor doesn't pick up gateway jobs.
+*/
+type degradedModeT struct {
+	ctx                 context.Context
+	healthURL           string
+	healthCheckInterval time.Duration
+	client              *http.Client
+	alert               func(message string)
+	logger              logger.LoggerI
+
+	mu        sync.Mutex
+	degraded  bool
+	since     time.Time
+	reason    string
+	recovered chan struct{} // closed when leaving degraded mode
+}
+
+func newDegradedMode(ctx context.Context, transformerURL string, log logger.LoggerI) *degradedModeT {
+	d := &degradedModeT{
+		ctx:       ctx,
+		healthURL: transformerURL + "/health",
+		client:    &http.Client{Timeout: 10 * time.Second},
+		logger:    log,
+		alert: func(message string) {
+			alertManager, err := alert.New()
+			if err != nil {
+				log.Errorf("Unable to initialize the alertManager: %v", err)
+				return
+			}
+			alertManager.Alert(message)
+		},
+	}
+	config.RegisterDurationConfigVariable(5, &d.healthCheckInterval, true, time.Second, "Processor.transformerHealthCheckInterval")
+	return d
+}
+
+// Unavailable enters degraded mode and blocks until the transformer is healthy again, or the request's context is done
+func (d *degradedModeT) Unavailable(ctx context.Context, err error) error {
+	d.mu.Lock()
+	if !d.degraded {
+		d.degraded = true
+		d.since = time.Now()
+		d.reason = err.Error()
+		d.recovered = make(chan struct{})
+		d.logger.Errorf("Transformer is unavailable, processor entered degraded mode: %v", err)
+		app.SetUnhealthy(degradedModeComponent, d.reason)
+		stats.DefaultStats.NewStat("processor.degraded_mode", stats.GaugeType).Gauge(1)
+		d.alert(fmt.Sprintf("Dataplane server %s processor entered degraded mode, transformer is unavailable: %v", config.GetEnv("INSTANCE_ID", ""), err))
+		go d.waitForHealthyTransformer()
+	}
+	recovered := d.recovered
+	d.mu.Unlock()
+
+	select {
+	case <-recovered:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// waitForHealthyTransformer polls the health endpoint of the transformer and leaves degraded mode once it responds
+func (d *degradedModeT) waitForHealthyTransformer() {
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-time.After(d.healthCheckInterval):
+		}
+		if d.isTransformerHealthy() {
+			break
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.logger.Infof("Transformer is available again after %s, processor left degraded mode", time.Since(d.since))
+	d.degraded = false
+	close(d.recovered)
+	app.SetHealthy(degradedModeComponent)
+	stats.DefaultStats.NewStat("processor.degraded_mode", stats.GaugeType).Gauge(0)
+}
+
+func (d *degradedModeT) isTransformerHealthy() bool {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodGet, d.healthURL, http.NoBody)
+	if err != nil {
+		return false
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return resp.StatusCode == http.StatusOK
+}
+
+// isDegraded returns whether the processor is in degraded mode. A nil degraded mode is never degraded.
+func (d *degradedModeT) isDegraded() bool {
+	if d == nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.degraded
+}
+
+// status returns the state of the degraded mode for the admin status
+func (d *degradedModeT) status() map[string]interface{} {
+	status := map[string]interface{}{"degraded": false}
+	if d == nil {
+		return status
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.degraded {
+		status["degraded"] = true
+		status["since"] = d.since
+		status["reason"] = d.reason
+	}
+	return status
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/rudderlabs/rudder-server/processor/transformer"
 	"github.com/rudderlabs/rudder-server/router"
 	"github.com/rudderlabs/rudder-server/router/batchrouter"
+	router_utils "github.com/rudderlabs/rudder-server/router/utils"
 	"github.com/rudderlabs/rudder-server/rruntime"
 	destinationdebugger "github.com/rudderlabs/rudder-server/services/debugger/destination"
 	transformationdebugger "github.com/rudderlabs/rudder-server/services/debugger/transformation"
@@ -81,6 +82,7 @@ type HandleT struct {
 	jobdDBMaxRetries          int
 	transientSources          transientsource.Service
 	rsourcesService           rsources.JobService
+	degradedMode              *degradedModeT
 }
 
 type processorStats struct {
@@ -167,6 +169,9 @@ type ParametersT struct {
 	SourceCategory          string      `json:"source_category"`
 	RecordID                interface{} `json:"record_id"`
 	WorkspaceId             string      `json:"workspaceId"`
+	// the ordering key of the destination and its value for the event before being transformed, if the key is a json path
+	OrderingKey   string `json:"ordering_key,omitempty"`
+	OrderingValue string `json:"ordering_value,omitempty"`
 }
 
 type MetricMetadata struct {
@@ -286,10 +291,18 @@ func Init() {
 // NewProcessor creates a new Processor instance
 func NewProcessor() *HandleT {
 	return &HandleT{
-		transformer: transformer.NewTransformer(),
+		transformer: newTransformer(),
 	}
 }
 
+// newTransformer returns the built-in transformer if enabled, otherwise the client of the external transformer
+func newTransformer() transformer.Transformer {
+	if transformer.UseBuiltin() {
+		return transformer.NewBuiltinTransformer()
+	}
+	return transformer.NewTransformer()
+}
+
 func (proc *HandleT) Status() interface{} {
 	proc.stats.transformEventsByTimeMutex.RLock()
 	defer proc.stats.transformEventsByTimeMutex.RUnlock()
@@ -300,6 +313,7 @@ func (proc *HandleT) Status() interface{} {
 	for _, pqUserEvent := range proc.stats.userTransformEventsByTimeTaken {
 		statusRes["user-transformer"] = append(statusRes["user-transformer"], *pqUserEvent)
 	}
+	statusRes["degraded-mode"] = []interface{}{proc.degradedMode.status()}
 
 	if enableDedup {
 		proc.dedupHandler.PrintHistogram()
@@ -419,10 +433,22 @@ func (proc *HandleT) Setup(
 		proc.backendConfigSubscriber()
 	})
 
-	g.Go(misc.WithBugsnag(func() error {
-		proc.syncTransformerFeatureJson(ctx)
-		return nil
-	}))
+	if _, ok := proc.transformer.(*transformer.BuiltinT); ok {
+		// there is no external transformer to get the features from
+		proc.transformerFeatures = json.RawMessage(transformer.BuiltinFeatures)
+		isUnLocked = true
+	} else {
+		g.Go(misc.WithBugsnag(func() error {
+			proc.syncTransformerFeatureJson(ctx)
+			return nil
+		}))
+	}
+
+	if t, ok := proc.transformer.(*transformer.HandleT); ok {
+		// wait for the transformer to come back instead of panicking when it is unreachable
+		proc.degradedMode = newDegradedMode(ctx, transformerURL, proc.logger.Child("degraded_mode"))
+		t.SetUnavailabilityHandler(proc.degradedMode)
+	}
 
 	g.Go(misc.WithBugsnag(func() error {
 		router.CleanFailedRecordsTableProcess(ctx)
@@ -473,6 +499,7 @@ var (
 	loopSleep                 time.Duration // DEPRECATED: used only on the old mainLoop
 	fixedLoopSleep            time.Duration // DEPRECATED: used only on the old mainLoop
 	maxEventsToProcess        int
+	readPageSize              int
 	transformBatchSize        int
 	userTransformBatchSize    int
 	writeKeyDestinationMap    map[string][]backendconfig.DestinationT
@@ -515,6 +542,7 @@ func loadConfig() {
 	config.RegisterBoolConfigVariable(false, &enableEventSchemasFeature, false, "EventSchemas.enableEventSchemasFeature")
 	config.RegisterBoolConfigVariable(false, &enableEventSchemasAPIOnly, true, "EventSchemas.enableEventSchemasAPIOnly")
 	config.RegisterIntConfigVariable(10000, &maxEventsToProcess, true, 1, "Processor.maxLoopProcessEvents")
+	config.RegisterIntConfigVariable(10000, &readPageSize, true, 1, "Processor.readPageSize")
 
 	batchDestinations, customDestinations = misc.LoadDestinations()
 	config.RegisterIntConfigVariable(5, &transformTimesPQLength, false, 1, "Processor.transformTimesPQLength")
@@ -1147,7 +1175,7 @@ func getDiffMetrics(inPU, pu string, inCountMetadataMap map[string]MetricMetadat
 	return diffMetrics
 }
 
-func (proc *HandleT) processJobsForDest(subJobs subJob, parsedEventList [][]types.SingularEventT) *transformationMessage {
+func (proc *HandleT) processJobsForDest(ctx context.Context, subJobs subJob, parsedEventList [][]types.SingularEventT) (*transformationMessage, error) {
 	jobList := subJobs.subJobs
 	start := time.Now()
 
@@ -1157,6 +1185,7 @@ func (proc *HandleT) processJobsForDest(subJobs subJob, parsedEventList [][]type
 	groupedEvents := make(map[string][]transformer.TransformerEventT)
 	groupedEventsByWriteKey := make(map[WriteKeyT][]transformer.TransformerEventT)
 	eventsByMessageID := make(map[string]types.SingularEventWithReceivedAt)
+	jobExpiryTimes := make(map[int64]time.Time) // gateway job id -> expiry time, for jobs that expire
 	var procErrorJobs []*jobsdb.JobT
 
 	if !(parsedEventList == nil || len(jobList) == len(parsedEventList)) {
@@ -1201,6 +1230,9 @@ func (proc *HandleT) processJobsForDest(subJobs subJob, parsedEventList [][]type
 		writeKey := gjson.Get(string(batchEvent.EventPayload), "writeKey").Str
 		requestIP := gjson.Get(string(batchEvent.EventPayload), "requestIP").Str
 		receivedAt := gjson.Get(string(batchEvent.EventPayload), "receivedAt").Time()
+		if !batchEvent.ExpiryTime.IsZero() {
+			jobExpiryTimes[batchEvent.JobID] = batchEvent.ExpiryTime
+		}
 
 		if ok {
 			var duplicateIndexes []int
@@ -1349,7 +1381,10 @@ func (proc *HandleT) processJobsForDest(subJobs subJob, parsedEventList [][]type
 	// Placing the trackingPlan validation filters here.
 	// Else further down events are duplicated by destId, so multiple validation takes places for same event
 	validateEventsStart := time.Now()
-	validatedEventsByWriteKey, validatedReportMetrics, validatedErrorJobs, trackingPlanEnabledMap := proc.validateEvents(groupedEventsByWriteKey, eventsByMessageID)
+	validatedEventsByWriteKey, validatedReportMetrics, validatedErrorJobs, trackingPlanEnabledMap, err := proc.validateEvents(ctx, groupedEventsByWriteKey, eventsByMessageID)
+	if err != nil {
+		return nil, err
+	}
 	validateEventsTime := time.Since(validateEventsStart)
 	defer proc.stats.validateEventsTime.SendTiming(validateEventsTime)
 
@@ -1429,6 +1464,7 @@ func (proc *HandleT) processJobsForDest(subJobs subJob, parsedEventList [][]type
 		trackingPlanEnabledMap,
 		eventsByMessageID,
 		uniqueMessageIdsBySrcDestKey,
+		jobExpiryTimes,
 		reportMetrics,
 		statusList,
 		procErrorJobs,
@@ -1440,7 +1476,7 @@ func (proc *HandleT) processJobsForDest(subJobs subJob, parsedEventList [][]type
 
 		subJobs.hasMore,
 		subJobs.rsourcesStats,
-	}
+	}, nil
 }
 
 type transformationMessage struct {
@@ -1449,6 +1485,7 @@ type transformationMessage struct {
 	trackingPlanEnabledMap       map[SourceIDT]bool
 	eventsByMessageID            map[string]types.SingularEventWithReceivedAt
 	uniqueMessageIdsBySrcDestKey map[string]map[string]struct{}
+	jobExpiryTimes               map[int64]time.Time
 	reportMetrics                []*types.PUReportedMetric
 	statusList                   []*jobsdb.JobStatusT
 	procErrorJobs                []*jobsdb.JobT
@@ -1462,11 +1499,13 @@ type transformationMessage struct {
 	rsourcesStats rsources.StatsCollector
 }
 
-func (proc *HandleT) transformations(in *transformationMessage) *storeMessage {
+// transformations only returns an error if the transformations got cancelled through the context,
+// in which case the jobs must not be stored
+func (proc *HandleT) transformations(ctx context.Context, in *transformationMessage) (*storeMessage, error) {
 	// Now do the actual transformation. We call it in batches, once
 	// for each destination ID
 
-	ctx, task := trace.NewTask(context.Background(), "transformations")
+	ctx, task := trace.NewTask(ctx, "transformations")
 	defer task.End()
 
 	procErrorJobsByDestID := make(map[string][]*jobsdb.JobT)
@@ -1491,6 +1530,7 @@ func (proc *HandleT) transformations(in *transformationMessage) *storeMessage {
 				in.trackingPlanEnabledMap,
 				in.eventsByMessageID,
 				in.uniqueMessageIdsBySrcDestKey,
+				in.jobExpiryTimes,
 			)
 		}()
 	}
@@ -1499,7 +1539,12 @@ func (proc *HandleT) transformations(in *transformationMessage) *storeMessage {
 		close(chOut)
 	}()
 
+	var err error
 	for o := range chOut {
+		if o.err != nil {
+			err = o.err
+			continue
+		}
 		destJobs = append(destJobs, o.destJobs...)
 		batchDestJobs = append(batchDestJobs, o.batchDestJobs...)
 
@@ -1508,6 +1553,9 @@ func (proc *HandleT) transformations(in *transformationMessage) *storeMessage {
 			procErrorJobsByDestID[k] = append(procErrorJobsByDestID[k], v...)
 		}
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	destProcTime := time.Since(destProcStart)
 	defer proc.stats.destProcessing.SendTiming(destProcTime)
@@ -1530,7 +1578,7 @@ func (proc *HandleT) transformations(in *transformationMessage) *storeMessage {
 		in.start,
 		in.hasMore,
 		in.rsourcesStats,
-	}
+	}, nil
 }
 
 type storeMessage struct {
@@ -1714,6 +1762,7 @@ type transformSrcDestOutput struct {
 	destJobs        []*jobsdb.JobT
 	batchDestJobs   []*jobsdb.JobT
 	errorsPerDestID map[string][]*jobsdb.JobT
+	err             error // set if the transformations got cancelled
 }
 
 func (proc *HandleT) transformSrcDest(
@@ -1725,6 +1774,7 @@ func (proc *HandleT) transformSrcDest(
 	trackingPlanEnabledMap map[SourceIDT]bool,
 	eventsByMessageID map[string]types.SingularEventWithReceivedAt,
 	uniqueMessageIdsBySrcDestKey map[string]map[string]struct{},
+	jobExpiryTimes map[int64]time.Time,
 ) transformSrcDestOutput {
 	defer proc.stats.pipeProcessing.Since(time.Now())
 
@@ -1785,6 +1835,7 @@ func (proc *HandleT) transformSrcDest(
 	url := integrations.GetDestinationURL(destType)
 	var response transformer.ResponseT
 	var eventsToTransform []transformer.TransformerEventT
+	var err error
 	// Send to custom transformer only if the destination has a transformer enabled
 	if transformationEnabled {
 		userTransformationStat := proc.newUserTransformationStat(sourceID, workspaceID, destination)
@@ -1793,7 +1844,10 @@ func (proc *HandleT) transformSrcDest(
 
 		trace.WithRegion(ctx, "UserTransform", func() {
 			startedAt := time.Now()
-			response = proc.transformer.Transform(ctx, eventList, integrations.GetUserTransformURL(), userTransformBatchSize)
+			response, err = proc.transformer.Transform(ctx, eventList, integrations.GetUserTransformURL(), userTransformBatchSize)
+			if err != nil {
+				return
+			}
 			d := time.Since(startedAt)
 			userTransformationStat.transformTime.SendTiming(d)
 			proc.addToTransformEventByTimePQ(&TransformRequestT{
@@ -1840,6 +1894,9 @@ func (proc *HandleT) transformSrcDest(
 			}
 			// REPORTING - END
 		})
+		if err != nil {
+			return transformSrcDestOutput{err: err}
+		}
 	} else {
 		proc.logger.Debug("No custom transformation")
 		eventsToTransform = eventList
@@ -1927,7 +1984,10 @@ func (proc *HandleT) transformSrcDest(
 			trace.Logf(ctx, "Dest Transform", "input size %d", len(eventsToTransform))
 			proc.logger.Debug("Dest Transform input size", len(eventsToTransform))
 			s := time.Now()
-			response = proc.transformer.Transform(ctx, eventsToTransform, url, transformBatchSize)
+			response, err = proc.transformer.Transform(ctx, eventsToTransform, url, transformBatchSize)
+			if err != nil {
+				return
+			}
 
 			destTransformationStat := proc.newDestinationTransformationStat(sourceID, workspaceID, transformAt, destination)
 			destTransformationStat.transformTime.Since(s)
@@ -1990,8 +2050,12 @@ func (proc *HandleT) transformSrcDest(
 			}
 			// REPORTING - PROCESSOR metrics - END
 		})
+		if err != nil {
+			return transformSrcDestOutput{err: err}
+		}
 	}
 
+	orderingKey := router_utils.OrderingKey(destination)
 	trace.WithRegion(ctx, "MarshalForDB", func() {
 		// Save the JSON in DB. This is what the router uses
 		for i := range response.Events {
@@ -2052,6 +2116,11 @@ func (proc *HandleT) transformSrcDest(
 				RecordID:                recordId,
 				WorkspaceId:             workspaceId,
 			}
+			if orderingKey != router_utils.UserIDOrderingKey && orderingKey != router_utils.NoneOrderingKey {
+				// the router orders jobs by the value of the event sent by the source, not the one of the transformed payload
+				params.OrderingKey = orderingKey
+				params.OrderingValue = router_utils.OrderingKeyValue(orderingKey, eventsByMessageID[messageId].SingularEvent)
+			}
 			marshalledParams, err := jsonfast.Marshal(params)
 			if err != nil {
 				proc.logger.Errorf("[Processor] Failed to marshal parameters object. Parameters: %v", params)
@@ -2068,6 +2137,8 @@ func (proc *HandleT) transformSrcDest(
 				EventPayload: destEventJSON,
 				WorkspaceId:  workspaceId,
 			}
+			newJob.ExpiryTime = router_utils.JobExpiryTime(destID, newJob.CreatedAt, jobExpiryTimes[jobId])
+			newJob.Priority = router_utils.JobPriority(sourceID, eventType)
 			if misc.ContainsString(batchDestinations, newJob.CustomVal) {
 				batchDestJobs = append(batchDestJobs, &newJob)
 			} else {
@@ -2185,7 +2256,12 @@ func (proc *HandleT) addToTransformEventByTimePQ(event *TransformRequestT, pq *t
 	}
 }
 
-func (proc *HandleT) getJobs() jobsdb.JobsResult {
+/*
+readJobs reads the unprocessed gateway jobs of a loop in pages of at most readPageSize jobs, handing every page over to handle
+before reading the next one, so that the jobs of a loop are never all in memory at once and a busy consumer holds off the reads.
+The reads stop early, leaving the jobs not handed over unprocessed, if ctx is done. It returns the number of jobs & events read.
+*/
+func (proc *HandleT) readJobs(ctx context.Context, handle func(page []*jobsdb.JobT)) (jobsCount, eventsCount int) {
 	s := time.Now()
 
 	proc.logger.Debugf("Processor DB Read size: %d", maxEventsToProcess)
@@ -2194,21 +2270,47 @@ func (proc *HandleT) getJobs() jobsdb.JobsResult {
 	if !enableEventCount {
 		eventCount = 0
 	}
-	unprocessedList, err := jobsdb.QueryJobsResultWithRetries(context.Background(), proc.jobdDBQueryRequestTimeout, proc.jobdDBMaxRetries, func(ctx context.Context) (jobsdb.JobsResult, error) {
-		return proc.gatewayDB.GetUnprocessed(ctx, jobsdb.GetQueryParamsT{
-			CustomValFilters: []string{GWCustomVal},
-			JobsLimit:        maxEventsToProcess,
-			EventsLimit:      eventCount,
-			PayloadSizeLimit: proc.payloadLimit,
+	query := func(ctx context.Context, params jobsdb.GetQueryParamsT) (jobsdb.JobsResult, error) {
+		return jobsdb.QueryJobsResultWithRetries(ctx, proc.jobdDBQueryRequestTimeout, proc.jobdDBMaxRetries, func(ctx context.Context) (jobsdb.JobsResult, error) {
+			return proc.gatewayDB.GetUnprocessed(ctx, params)
 		})
-	})
-	if err != nil {
-		proc.logger.Errorf("Failed to get unprocessed jobs from DB. Error: %v", err)
-		panic(err)
 	}
+	it := jobsdb.NewJobsIterator(query, jobsdb.GetQueryParamsT{
+		CustomValFilters: []string{GWCustomVal},
+		JobsLimit:        maxEventsToProcess,
+		EventsLimit:      eventCount,
+		PayloadSizeLimit: proc.payloadLimit,
+	}, jobsdb.WithPageSize(readPageSize))
+
+	var (
+		page              []*jobsdb.JobT
+		pageEvents        int
+		totalPayloadBytes int
+		eventSchemasTime  time.Duration
+		handleTime        time.Duration
+	)
+	flush := func() {
+		if len(page) == 0 {
+			return
+		}
+		eventSchemasStart := time.Now()
+		if enableEventSchemasFeature && !enableEventSchemasAPIOnly {
+			for _, unprocessedJob := range page {
+				writeKey := gjson.GetBytes(unprocessedJob.EventPayload, "writeKey").Str
+				proc.eventSchemaHandler.RecordEventSchema(writeKey, string(unprocessedJob.EventPayload))
+			}
+		}
+		eventSchemasTime += time.Since(eventSchemasStart)
 
-	totalPayloadBytes := 0
-	for _, job := range unprocessedList.Jobs {
+		handleStart := time.Now()
+		handle(page)
+		handleTime += time.Since(handleStart)
+		jobsCount += len(page)
+		eventsCount += pageEvents
+		page, pageEvents = nil, 0
+	}
+	for it.Next(ctx) {
+		job := it.Job()
 		totalPayloadBytes += len(job.EventPayload)
 
 		if job.JobID <= proc.lastJobID {
@@ -2219,35 +2321,51 @@ func (proc *HandleT) getJobs() jobsdb.JobsResult {
 			proc.stats.statDBReadOutOfSequence.Count(1)
 		}
 		proc.lastJobID = job.JobID
+
+		page = append(page, job)
+		pageEvents += job.EventCount
+		if len(page) >= readPageSize {
+			flush()
+		}
+	}
+	if err := it.Err(); err != nil {
+		if ctx.Err() != nil {
+			proc.logger.Infof("Reading of gateway jobs got cancelled: %v", err)
+			return jobsCount, eventsCount
+		}
+		proc.logger.Errorf("Failed to get unprocessed jobs from DB. Error: %v", err)
+		panic(err)
 	}
-	dbReadTime := time.Since(s)
+	flush()
+
+	dbReadTime := time.Since(s) - handleTime - eventSchemasTime
 	defer proc.stats.statDBR.SendTiming(dbReadTime)
 
 	// check if there is work to be done
-	if len(unprocessedList.Jobs) == 0 {
+	if jobsCount == 0 {
 		proc.logger.Debugf("Processor DB Read Complete. No GW Jobs to process.")
-		proc.stats.pStatsDBR.Rate(0, time.Since(s))
-		return unprocessedList
-	}
-
-	eventSchemasStart := time.Now()
-	if enableEventSchemasFeature && !enableEventSchemasAPIOnly {
-		for _, unprocessedJob := range unprocessedList.Jobs {
-			writeKey := gjson.GetBytes(unprocessedJob.EventPayload, "writeKey").Str
-			proc.eventSchemaHandler.RecordEventSchema(writeKey, string(unprocessedJob.EventPayload))
-		}
+		proc.stats.pStatsDBR.Rate(0, dbReadTime)
+		return jobsCount, eventsCount
 	}
-	eventSchemasTime := time.Since(eventSchemasStart)
 	defer proc.stats.eventSchemasTime.SendTiming(eventSchemasTime)
 
-	proc.logger.Debugf("Processor DB Read Complete. unprocessedList: %v total_events: %d", len(unprocessedList.Jobs), unprocessedList.EventsCount)
-	proc.stats.pStatsDBR.Rate(len(unprocessedList.Jobs), time.Since(s))
-	proc.stats.statGatewayDBR.Count(len(unprocessedList.Jobs))
+	proc.logger.Debugf("Processor DB Read Complete. unprocessedList: %v total_events: %d", jobsCount, eventsCount)
+	proc.stats.pStatsDBR.Rate(jobsCount, dbReadTime)
+	proc.stats.statGatewayDBR.Count(jobsCount)
 
-	proc.stats.statDBReadRequests.Observe(float64(len(unprocessedList.Jobs)))
-	proc.stats.statDBReadEvents.Observe(float64(unprocessedList.EventsCount))
+	proc.stats.statDBReadRequests.Observe(float64(jobsCount))
+	proc.stats.statDBReadEvents.Observe(float64(eventsCount))
 	proc.stats.statDBReadPayloadBytes.Observe(float64(totalPayloadBytes))
 
+	return jobsCount, eventsCount
+}
+
+// getJobs reads the unprocessed gateway jobs of a loop, all pages of them at once
+func (proc *HandleT) getJobs(ctx context.Context) jobsdb.JobsResult {
+	var unprocessedList jobsdb.JobsResult
+	_, unprocessedList.EventsCount = proc.readJobs(ctx, func(page []*jobsdb.JobT) {
+		unprocessedList.Jobs = append(unprocessedList.Jobs, page...)
+	})
 	return unprocessedList
 }
 
@@ -2282,10 +2400,10 @@ func (proc *HandleT) markExecuting(jobs []*jobsdb.JobT) error {
 
 // handlePendingGatewayJobs is checking for any pending gateway jobs (failed and unprocessed), and routes them appropriately
 // Returns true if any job is handled, otherwise returns false.
-func (proc *HandleT) handlePendingGatewayJobs() bool {
+func (proc *HandleT) handlePendingGatewayJobs(ctx context.Context) bool {
 	s := time.Now()
 
-	unprocessedList := proc.getJobs()
+	unprocessedList := proc.getJobs(ctx)
 
 	if len(unprocessedList.Jobs) == 0 {
 		return false
@@ -2294,15 +2412,22 @@ func (proc *HandleT) handlePendingGatewayJobs() bool {
 	rsourcesStats := rsources.NewStatsCollector(proc.rsourcesService)
 	rsourcesStats.BeginProcessing(unprocessedList.Jobs)
 
-	proc.Store(
-		proc.transformations(
-			proc.processJobsForDest(subJob{
-				subJobs:       unprocessedList.Jobs,
-				hasMore:       false,
-				rsourcesStats: rsourcesStats,
-			}, nil),
-		),
-	)
+	transformationMessage, err := proc.processJobsForDest(ctx, subJob{
+		subJobs:       unprocessedList.Jobs,
+		hasMore:       false,
+		rsourcesStats: rsourcesStats,
+	}, nil)
+	if err != nil {
+		// the jobs are left unprocessed, to be picked up again
+		proc.logger.Infof("Processing of pending gateway jobs got cancelled: %v", err)
+		return false
+	}
+	storeMessage, err := proc.transformations(ctx, transformationMessage)
+	if err != nil {
+		proc.logger.Infof("Processing of pending gateway jobs got cancelled: %v", err)
+		return false
+	}
+	proc.Store(storeMessage)
 	proc.stats.statLoopTime.Since(s)
 
 	return true
@@ -2322,8 +2447,8 @@ func (proc *HandleT) mainLoop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-time.After(mainLoopTimeout):
-			if isUnLocked {
-				found := proc.handlePendingGatewayJobs()
+			if isUnLocked && !proc.degradedMode.isDegraded() {
+				found := proc.handlePendingGatewayJobs(ctx)
 				if found {
 					currLoopSleep = 0
 				} else {
@@ -2419,15 +2544,28 @@ func (proc *HandleT) mainPipeline(ctx context.Context) {
 			case <-ctx.Done():
 				return
 			case <-time.After(nextSleepTime):
-				if !isUnLocked {
+				if !isUnLocked || proc.degradedMode.isDegraded() {
 					nextSleepTime = proc.maxLoopSleep
 					continue
 				}
 				dbReadStart := time.Now()
-				jobs := proc.getJobs()
-				rsourcesStats := rsources.NewStatsCollector(proc.rsourcesService)
-				rsourcesStats.BeginProcessing(jobs.Jobs)
-				if len(jobs.Jobs) == 0 {
+				var pipelineWait time.Duration
+				// every page is marked as executing and processed as a batch of its own as soon as it's read, while the
+				// next page is being read, until the pipeline is full
+				jobs, events := proc.readJobs(ctx, func(page []*jobsdb.JobT) {
+					if err := proc.markExecuting(page); err != nil {
+						pkgLogger.Error(err)
+						panic(err)
+					}
+					rsourcesStats := rsources.NewStatsCollector(proc.rsourcesService)
+					rsourcesStats.BeginProcessing(page)
+					waitStart := time.Now()
+					for _, subJob := range jobSplitter(page, rsourcesStats) {
+						chProc <- subJob
+					}
+					pipelineWait += time.Since(waitStart)
+				})
+				if jobs == 0 {
 					// no jobs found, double sleep time until maxLoopSleep
 					nextSleepTime = 2 * nextSleepTime
 					if nextSleepTime > proc.maxLoopSleep {
@@ -2438,13 +2576,7 @@ func (proc *HandleT) mainPipeline(ctx context.Context) {
 					continue
 				}
 
-				err := proc.markExecuting(jobs.Jobs)
-				if err != nil {
-					pkgLogger.Error(err)
-					panic(err)
-				}
-				dbReadTime := time.Since(dbReadStart)
-				events := jobs.EventsCount
+				dbReadTime := time.Since(dbReadStart) - pipelineWait
 				dbReadThroughput := throughputPerSecond(events, dbReadTime)
 				// DB read throughput per second.
 				proc.stats.DBReadThroughput.Count(dbReadThroughput)
@@ -2452,11 +2584,6 @@ func (proc *HandleT) mainPipeline(ctx context.Context) {
 				// nextSleepTime is dependent on the number of events read in this loop
 				emptyRatio := 1.0 - math.Min(1, float64(events)/float64(maxEventsToProcess))
 				nextSleepTime = time.Duration(emptyRatio * float64(proc.readLoopSleep))
-
-				subJobs := jobSplitter(jobs.Jobs, rsourcesStats)
-				for _, subJob := range subJobs {
-					chProc <- subJob
-				}
 			}
 		}
 	}()
@@ -2467,7 +2594,13 @@ func (proc *HandleT) mainPipeline(ctx context.Context) {
 		defer wg.Done()
 		defer close(chTrans)
 		for jobs := range chProc {
-			chTrans <- proc.processJobsForDest(jobs, nil)
+			msg, err := proc.processJobsForDest(ctx, jobs, nil)
+			if err != nil {
+				// the executing jobs are left to be picked up again after restarting
+				proc.logger.Infof("Processing of gateway jobs got cancelled: %v", err)
+				continue
+			}
+			chTrans <- msg
 		}
 	}()
 
@@ -2478,7 +2611,12 @@ func (proc *HandleT) mainPipeline(ctx context.Context) {
 		defer wg.Done()
 		defer close(chStore)
 		for msg := range chTrans {
-			chStore <- proc.transformations(msg)
+			storeMsg, err := proc.transformations(ctx, msg)
+			if err != nil {
+				proc.logger.Infof("Transformation of gateway jobs got cancelled: %v", err)
+				continue
+			}
+			chStore <- storeMsg
 		}
 	}()
 
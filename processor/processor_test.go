@@ -386,10 +386,50 @@ var _ = Describe("Processor", func() {
 			payloadLimit := processor.payloadLimit
 			c.mockGatewayJobsDB.EXPECT().GetUnprocessed(gomock.Any(), jobsdb.GetQueryParamsT{CustomValFilters: gatewayCustomVal, JobsLimit: c.dbReadBatchSize, EventsLimit: c.processEventSize, PayloadSizeLimit: payloadLimit}).Return(jobsdb.JobsResult{Jobs: emptyJobsList}, nil).Times(1)
 
-			didWork := processor.handlePendingGatewayJobs()
+			didWork := processor.handlePendingGatewayJobs(context.Background())
 			Expect(didWork).To(Equal(false))
 		})
 
+		It("should hand over the jobs read page by page, before reading the next page", func() {
+			mockTransformer := mocksTransformer.NewMockTransformer(c.mockCtrl)
+			mockTransformer.EXPECT().Setup().Times(1)
+
+			processor := &HandleT{
+				transformer: mockTransformer,
+			}
+
+			processor.Setup(c.mockBackendConfig, c.mockGatewayJobsDB, c.mockRouterJobsDB, c.mockBatchRouterJobsDB, c.mockProcErrorsDB, &clearDB, c.MockReportingI, c.MockMultitenantHandle, transientsource.NewEmptyService(), c.MockRsourcesService)
+			prevReadPageSize := readPageSize
+			readPageSize = 2
+			defer func() { readPageSize = prevReadPageSize }()
+
+			jobs := []*jobsdb.JobT{{JobID: 1, EventCount: 1}, {JobID: 2, EventCount: 1}, {JobID: 3, EventCount: 1}}
+			var handed [][]int64
+			gomock.InOrder(
+				c.mockGatewayJobsDB.EXPECT().GetUnprocessed(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, params jobsdb.GetQueryParamsT) (jobsdb.JobsResult, error) {
+					Expect(params.AfterJobID).To(BeZero())
+					Expect(params.JobsLimit).To(Equal(2))
+					return jobsdb.JobsResult{Jobs: jobs[:2], EventsCount: 2}, nil
+				}),
+				c.mockGatewayJobsDB.EXPECT().GetUnprocessed(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, params jobsdb.GetQueryParamsT) (jobsdb.JobsResult, error) {
+					Expect(handed).To(HaveLen(1), "the first page is handed over before reading the second one")
+					Expect(params.AfterJobID).To(Equal(int64(2)))
+					return jobsdb.JobsResult{Jobs: jobs[2:], EventsCount: 1}, nil
+				}),
+			)
+
+			jobsCount, eventsCount := processor.readJobs(context.Background(), func(page []*jobsdb.JobT) {
+				var ids []int64
+				for _, job := range page {
+					ids = append(ids, job.JobID)
+				}
+				handed = append(handed, ids)
+			})
+			Expect(jobsCount).To(Equal(3))
+			Expect(eventsCount).To(Equal(3))
+			Expect(handed).To(Equal([][]int64{{1, 2}, {3}}))
+		})
+
 		It("should process unprocessed jobs to destination without user transformation", func() {
 			messages := map[string]mockEventData{
 				// this message should be delivered only to destination A
@@ -703,7 +743,7 @@ var _ = Describe("Processor", func() {
 
 			// We expect one call to user transform for destination B
 			callUserTransform := mockTransformer.EXPECT().Transform(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).After(callUnprocessed).
-				DoAndReturn(func(ctx context.Context, clientEvents []transformer.TransformerEventT, url string, batchSize int) transformer.ResponseT {
+				DoAndReturn(func(ctx context.Context, clientEvents []transformer.TransformerEventT, url string, batchSize int) (transformer.ResponseT, error) {
 					defer GinkgoRecover()
 
 					Expect(url).To(Equal("http://localhost:9090/customTransform"))
@@ -719,7 +759,7 @@ var _ = Describe("Processor", func() {
 
 					return transformer.ResponseT{
 						Events: outputEvents,
-					}
+					}, nil
 				})
 
 			// We expect one transform call to destination B, after user transform for destination B.
@@ -965,7 +1005,7 @@ var _ = Describe("Processor", func() {
 				Return(transformer.ResponseT{
 					Events:       []transformer.TransformerResponseT{},
 					FailedEvents: transformerResponses,
-				})
+				}, nil)
 
 			c.MockMultitenantHandle.EXPECT().ReportProcLoopAddStats(gomock.Any(), gomock.Any()).Times(0)
 
@@ -1100,7 +1140,7 @@ var _ = Describe("Processor", func() {
 				Return(transformer.ResponseT{
 					Events:       []transformer.TransformerResponseT{},
 					FailedEvents: transformerResponses,
-				})
+				}, nil)
 
 			c.MockMultitenantHandle.EXPECT().ReportProcLoopAddStats(gomock.Any(), gomock.Any()).Times(0)
 
@@ -1913,8 +1953,8 @@ func assertReportMetric(expectedMetric, actualMetric []*types.PUReportedMetric)
 	}
 }
 
-func assertDestinationTransform(messages map[string]mockEventData, sourceId, destinationID string, expectations transformExpectation) func(ctx context.Context, clientEvents []transformer.TransformerEventT, url string, batchSize int) transformer.ResponseT {
-	return func(ctx context.Context, clientEvents []transformer.TransformerEventT, url string, batchSize int) transformer.ResponseT {
+func assertDestinationTransform(messages map[string]mockEventData, sourceId, destinationID string, expectations transformExpectation) func(ctx context.Context, clientEvents []transformer.TransformerEventT, url string, batchSize int) (transformer.ResponseT, error) {
+	return func(ctx context.Context, clientEvents []transformer.TransformerEventT, url string, batchSize int) (transformer.ResponseT, error) {
 		defer GinkgoRecover()
 		destinationDefinitionName := expectations.destinationDefinitionName
 
@@ -2024,7 +2064,7 @@ func assertDestinationTransform(messages map[string]mockEventData, sourceId, des
 					},
 				},
 			},
-		}
+		}, nil
 	}
 }
 
@@ -2049,7 +2089,7 @@ func Setup(processor *HandleT, c *testContext, enableDedup, enableReporting bool
 }
 
 func handlePendingGatewayJobs(processor *HandleT) {
-	didWork := processor.handlePendingGatewayJobs()
+	didWork := processor.handlePendingGatewayJobs(context.Background())
 	Expect(didWork).To(Equal(true))
 }
 
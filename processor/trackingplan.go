@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/rudderlabs/rudder-server/config"
@@ -54,7 +55,8 @@ func enhanceWithViolation(response transformer.ResponseT, trackingPlanId string,
 // The ResponseT will contain both the Events and FailedEvents
 // 1. eventsToTransform gets added to validatedEventsByWriteKey
 // 2. failedJobs gets added to validatedErrorJobs
-func (proc *HandleT) validateEvents(groupedEventsByWriteKey map[WriteKeyT][]transformer.TransformerEventT, eventsByMessageID map[string]types.SingularEventWithReceivedAt) (map[WriteKeyT][]transformer.TransformerEventT, []*types.PUReportedMetric, []*jobsdb.JobT, map[SourceIDT]bool) {
+// An error is only returned if the validation got cancelled through the context.
+func (proc *HandleT) validateEvents(ctx context.Context, groupedEventsByWriteKey map[WriteKeyT][]transformer.TransformerEventT, eventsByMessageID map[string]types.SingularEventWithReceivedAt) (map[WriteKeyT][]transformer.TransformerEventT, []*types.PUReportedMetric, []*jobsdb.JobT, map[SourceIDT]bool, error) {
 	validatedEventsByWriteKey := make(map[WriteKeyT][]transformer.TransformerEventT)
 	validatedReportMetrics := make([]*types.PUReportedMetric, 0)
 	validatedErrorJobs := make([]*jobsdb.JobT, 0)
@@ -76,8 +78,11 @@ func (proc *HandleT) validateEvents(groupedEventsByWriteKey map[WriteKeyT][]tran
 		}
 
 		validationStat.tpValidationTime.Start()
-		response := proc.transformer.Validate(eventList, integrations.GetTrackingPlanValidationURL(), userTransformBatchSize)
+		response, err := proc.transformer.Validate(ctx, eventList, integrations.GetTrackingPlanValidationURL(), userTransformBatchSize)
 		validationStat.tpValidationTime.End()
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
 
 		// If transformerInput does not match with transformerOutput then we do not consider transformerOutput
 		// This is a safety check we are adding so that if something unexpected comes from transformer
@@ -123,7 +128,7 @@ func (proc *HandleT) validateEvents(groupedEventsByWriteKey map[WriteKeyT][]tran
 		validatedEventsByWriteKey[writeKey] = make([]transformer.TransformerEventT, 0)
 		validatedEventsByWriteKey[writeKey] = append(validatedEventsByWriteKey[writeKey], eventsToTransform...)
 	}
-	return validatedEventsByWriteKey, validatedReportMetrics, validatedErrorJobs, trackingPlanEnabledMap
+	return validatedEventsByWriteKey, validatedReportMetrics, validatedErrorJobs, trackingPlanEnabledMap, nil
 }
 
 // makeCommonMetadataFromTransformerEvent Creates a new MetadataT instance
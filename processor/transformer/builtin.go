@@ -0,0 +1,219 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+)
+
+// BuiltinFeatures are the features of the built-in transformer: it has no router transformations,
+// so all destinations get transformed at the processor
+const BuiltinFeatures = `{"routerTransform":{}}`
+
+// destinationTransformationFunc maps an event to the payload of a destination
+type destinationTransformationFunc func(event *TransformerEventT) (map[string]interface{}, error)
+
+var (
+	// destinationTransformations are the destination mappings of the built-in transformer, by destination type
+	destinationTransformations = map[string]destinationTransformationFunc{
+		"WEBHOOK":              webhookTransformation,
+		"S3":                   passThroughTransformation,
+		"GCS":                  passThroughTransformation,
+		"AZURE_BLOB":           passThroughTransformation,
+		"MINIO":                passThroughTransformation,
+		"DIGITAL_OCEAN_SPACES": passThroughTransformation,
+	}
+)
+
+// UseBuiltin returns whether the processor transforms events in-process instead of calling the external transformer
+func UseBuiltin() bool {
+	return config.GetBool("Processor.builtinTransformer.enabled", false)
+}
+
+/*
+BuiltinT transforms events in-process, without the external transformer, so that the processor can run as a single binary.
+
+It supports a core set of destination mappings and javascript user transformations, whose code it loads from the
+transformation's config in backend config (see userTransformation). Events of other destinations fail with a 404 status
+code, just like the external transformer responds for unknown destinations. Tracking plans are not validated: events
+pass through unchanged.
+*/
+type BuiltinT struct {
+	logger       logger.LoggerI
+	receivedStat stats.RudderStats
+}
+
+// NewBuiltinTransformer creates a new built-in transformer
+func NewBuiltinTransformer() *BuiltinT {
+	return &BuiltinT{}
+}
+
+// Setup initializes this class
+func (trans *BuiltinT) Setup() {
+	trans.logger = pkgLogger
+	trans.receivedStat = stats.DefaultStats.NewStat("processor.transformer_received", stats.CountType)
+}
+
+// Transform transforms the events with the transformation of the transformer url, the batch size is irrelevant in-process
+func (trans *BuiltinT) Transform(ctx context.Context, clientEvents []TransformerEventT, transformerURL string, _ int) (ResponseT, error) {
+	if len(clientEvents) == 0 {
+		return ResponseT{}, nil
+	}
+	u, err := url.Parse(transformerURL)
+	if err != nil {
+		return failAll(clientEvents, http.StatusBadRequest, fmt.Sprintf("invalid transformer url %q: %v", transformerURL, err)), nil
+	}
+
+	var response ResponseT
+	switch {
+	case u.Path == "/customTransform":
+		if response, err = trans.userTransform(ctx, clientEvents); err != nil {
+			return ResponseT{}, err
+		}
+	case u.Path == "/v0/validate":
+		response = passThrough(clientEvents)
+	case strings.HasPrefix(u.Path, "/v0/"):
+		response = trans.destinationTransform(clientEvents)
+	default:
+		return failAll(clientEvents, http.StatusNotFound, fmt.Sprintf("transformation %q is not supported by the built-in transformer", u.Path)), nil
+	}
+	trans.receivedStat.Count(len(response.Events))
+	return response, nil
+}
+
+func (trans *BuiltinT) Validate(ctx context.Context, clientEvents []TransformerEventT, url string, batchSize int) (ResponseT, error) {
+	return trans.Transform(ctx, clientEvents, url, batchSize)
+}
+
+func (trans *BuiltinT) userTransform(ctx context.Context, clientEvents []TransformerEventT) (ResponseT, error) {
+	var response ResponseT
+	// runtimes are created per request, since they can't be shared between concurrent requests
+	transformations := make(map[string]*userTransformation)
+	for i := range clientEvents {
+		event := &clientEvents[i]
+		if len(event.Destination.Transformations) == 0 {
+			response.Events = append(response.Events, TransformerResponseT{Output: event.Message, Metadata: event.Metadata, StatusCode: http.StatusOK})
+			continue
+		}
+		transformation := event.Destination.Transformations[0]
+		t, ok := transformations[transformation.VersionID]
+		if !ok {
+			var err error
+			if t, err = newUserTransformation(transformation.VersionID, transformation.Config); err != nil {
+				trans.logger.Errorf("Loading user transformation %s: %v", transformation.VersionID, err)
+			}
+			transformations[transformation.VersionID] = t
+		}
+		if t == nil {
+			response.FailedEvents = append(response.FailedEvents, failed(event, http.StatusNotFound, fmt.Sprintf("transformation version %s could not be loaded by the built-in transformer", transformation.VersionID)))
+			continue
+		}
+		outputs, err := t.transform(ctx, event.Message, event.Metadata, userTransformationTimeout)
+		if ctx.Err() != nil {
+			return ResponseT{}, ctx.Err()
+		}
+		if err != nil {
+			response.FailedEvents = append(response.FailedEvents, failed(event, http.StatusBadRequest, err.Error()))
+			continue
+		}
+		for _, output := range outputs {
+			response.Events = append(response.Events, TransformerResponseT{Output: output, Metadata: event.Metadata, StatusCode: http.StatusOK})
+		}
+	}
+	return response, nil
+}
+
+func (trans *BuiltinT) destinationTransform(clientEvents []TransformerEventT) ResponseT {
+	var response ResponseT
+	for i := range clientEvents {
+		event := &clientEvents[i]
+		destType := event.Destination.DestinationDefinition.Name
+		f, ok := destinationTransformations[destType]
+		if !ok {
+			response.FailedEvents = append(response.FailedEvents, failed(event, http.StatusNotFound, fmt.Sprintf("destination %s is not supported by the built-in transformer", destType)))
+			continue
+		}
+		output, err := f(event)
+		if err != nil {
+			response.FailedEvents = append(response.FailedEvents, failed(event, http.StatusBadRequest, err.Error()))
+			continue
+		}
+		response.Events = append(response.Events, TransformerResponseT{Output: output, Metadata: event.Metadata, StatusCode: http.StatusOK})
+	}
+	return response
+}
+
+func passThrough(clientEvents []TransformerEventT) ResponseT {
+	response := ResponseT{Events: make([]TransformerResponseT, 0, len(clientEvents))}
+	for i := range clientEvents {
+		response.Events = append(response.Events, TransformerResponseT{Output: clientEvents[i].Message, Metadata: clientEvents[i].Metadata, StatusCode: http.StatusOK})
+	}
+	return response
+}
+
+func failAll(clientEvents []TransformerEventT, statusCode int, err string) ResponseT {
+	response := ResponseT{FailedEvents: make([]TransformerResponseT, 0, len(clientEvents))}
+	for i := range clientEvents {
+		response.FailedEvents = append(response.FailedEvents, failed(&clientEvents[i], statusCode, err))
+	}
+	return response
+}
+
+func failed(event *TransformerEventT, statusCode int, err string) TransformerResponseT {
+	return TransformerResponseT{Metadata: event.Metadata, StatusCode: statusCode, Error: err}
+}
+
+// passThroughTransformation is the mapping of object storage destinations, which store the events as they are
+func passThroughTransformation(event *TransformerEventT) (map[string]interface{}, error) {
+	return event.Message, nil
+}
+
+// webhookTransformation sends the event as json to the webhook url, with the headers configured in the destination.
+// The output is the integrations.PostParametersT the router sends.
+func webhookTransformation(event *TransformerEventT) (map[string]interface{}, error) {
+	endpoint, _ := event.Destination.Config["webhookUrl"].(string)
+	if endpoint == "" {
+		return nil, fmt.Errorf("webhookUrl is not configured")
+	}
+	method, _ := event.Destination.Config["webhookMethod"].(string)
+	if method == "" {
+		method = http.MethodPost
+	}
+	headers := map[string]interface{}{"content-type": "application/json"}
+	if configHeaders, ok := event.Destination.Config["headers"].([]interface{}); ok {
+		for _, h := range configHeaders {
+			header, _ := h.(map[string]interface{})
+			from, _ := header["from"].(string)
+			if from != "" {
+				headers[from] = header["to"]
+			}
+		}
+	}
+	body := map[string]interface{}{
+		"JSON":       map[string]interface{}{},
+		"JSON_ARRAY": map[string]interface{}{},
+		"XML":        map[string]interface{}{},
+		"FORM":       map[string]interface{}{},
+	}
+	if method != http.MethodGet {
+		body["JSON"] = event.Message
+	}
+	anonymousID, _ := event.Message["anonymousId"].(string)
+	return map[string]interface{}{
+		"version":  "1",
+		"type":     "REST",
+		"method":   strings.ToUpper(method),
+		"endpoint": endpoint,
+		"userId":   anonymousID,
+		"headers":  headers,
+		"params":   map[string]interface{}{},
+		"body":     body,
+		"files":    map[string]interface{}{},
+	}, nil
+}
@@ -0,0 +1,168 @@
+package transformer_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/config"
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/processor/integrations"
+	"github.com/rudderlabs/rudder-server/processor/transformer"
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+	"github.com/rudderlabs/rudder-server/utils/types"
+)
+
+func Test_BuiltinTransformer(t *testing.T) {
+	t.Setenv("RSERVER_PROCESSOR_BUILTIN_TRANSFORMER_USER_TRANSFORMATION_TIMEOUT", "100ms")
+	t.Setenv("RSERVER_PROCESSOR_BUILTIN_TRANSFORMER_MAX_COMPILED_TRANSFORMATIONS", "2")
+	config.Load()
+	logger.Init()
+	stats.Setup()
+	transformer.Init()
+	integrations.Init()
+
+	tr := transformer.NewBuiltinTransformer()
+	tr.Setup()
+	ctx := context.Background()
+
+	webhook := backendconfig.DestinationT{
+		ID:                    "d1",
+		DestinationDefinition: backendconfig.DestinationDefinitionT{Name: "WEBHOOK"},
+		Config: map[string]interface{}{
+			"webhookUrl": "https://example.com/hook",
+			"headers":    []interface{}{map[string]interface{}{"from": "X-Key", "to": "secret"}},
+		},
+		Transformations: []backendconfig.TransformationT{{VersionID: "v1", Config: map[string]interface{}{"code": `
+export function transformEvent(event, metadata) {
+	switch (event.messageId) {
+	case "drop":
+		return null;
+	case "fail":
+		throw new Error("bad event");
+	case "split":
+		return [event, Object.assign({}, event, { messageId: "split-2" })];
+	case "loop":
+		while (true) {}
+	}
+	event.transformed = true;
+	event.sourceId = metadata.sourceId;
+	return event;
+}`}}},
+	}
+	newEvent := func(messageID string, destination backendconfig.DestinationT) transformer.TransformerEventT {
+		return transformer.TransformerEventT{
+			Message:     types.SingularEventT{"messageId": messageID, "anonymousId": "a1", "event": "e"},
+			Metadata:    transformer.MetadataT{MessageID: messageID, SourceID: "s1"},
+			Destination: destination,
+		}
+	}
+
+	t.Run("destination transformation", func(t *testing.T) {
+		unsupported := backendconfig.DestinationT{DestinationDefinition: backendconfig.DestinationDefinitionT{Name: "AM"}}
+		misconfigured := backendconfig.DestinationT{DestinationDefinition: backendconfig.DestinationDefinitionT{Name: "WEBHOOK"}}
+		events := []transformer.TransformerEventT{newEvent("m1", webhook), newEvent("m2", unsupported), newEvent("m3", misconfigured)}
+
+		response, err := tr.Transform(ctx, events, integrations.GetDestinationURL("WEBHOOK"), 10)
+		require.NoError(t, err)
+		require.Len(t, response.Events, 1)
+		require.Equal(t, http.StatusOK, response.Events[0].StatusCode)
+		require.Equal(t, "m1", response.Events[0].Metadata.MessageID)
+		output := response.Events[0].Output
+		require.Equal(t, "https://example.com/hook", output["endpoint"])
+		require.Equal(t, "POST", output["method"])
+		require.Equal(t, "a1", output["userId"])
+		require.Equal(t, map[string]interface{}{"content-type": "application/json", "X-Key": "secret"}, output["headers"])
+		require.Equal(t, events[0].Message, output["body"].(map[string]interface{})["JSON"])
+
+		require.Len(t, response.FailedEvents, 2)
+		require.Equal(t, http.StatusNotFound, response.FailedEvents[0].StatusCode)
+		require.Equal(t, "m2", response.FailedEvents[0].Metadata.MessageID)
+		require.Equal(t, http.StatusBadRequest, response.FailedEvents[1].StatusCode)
+		require.Equal(t, "m3", response.FailedEvents[1].Metadata.MessageID)
+	})
+
+	t.Run("object storage destinations get the events as they are", func(t *testing.T) {
+		s3 := backendconfig.DestinationT{DestinationDefinition: backendconfig.DestinationDefinitionT{Name: "S3"}}
+		events := []transformer.TransformerEventT{newEvent("m1", s3)}
+		response, err := tr.Transform(ctx, events, integrations.GetDestinationURL("S3"), 10)
+		require.NoError(t, err)
+		require.Empty(t, response.FailedEvents)
+		require.Equal(t, []transformer.TransformerResponseT{{Output: events[0].Message, Metadata: events[0].Metadata, StatusCode: http.StatusOK}}, response.Events)
+	})
+
+	t.Run("user transformation", func(t *testing.T) {
+		withoutCode := webhook
+		withoutCode.Transformations = []backendconfig.TransformationT{{VersionID: "v2"}}
+		invalidCode := webhook
+		invalidCode.Transformations = []backendconfig.TransformationT{{VersionID: "v3", Config: map[string]interface{}{"code": "function transformEvent(event {"}}}
+		events := []transformer.TransformerEventT{
+			newEvent("m1", webhook), newEvent("drop", webhook), newEvent("split", webhook), newEvent("fail", webhook), newEvent("loop", webhook),
+			newEvent("m2", withoutCode), newEvent("m3", invalidCode),
+		}
+
+		response, err := tr.Transform(ctx, events, integrations.GetUserTransformURL(), 10)
+		require.NoError(t, err)
+		require.Len(t, response.Events, 3)
+		require.Equal(t, "m1", response.Events[0].Metadata.MessageID)
+		require.Equal(t, true, response.Events[0].Output["transformed"])
+		require.Equal(t, "s1", response.Events[0].Output["sourceId"], "transformation should get the event's metadata")
+		require.Equal(t, "split", response.Events[1].Output["messageId"])
+		require.Equal(t, "split-2", response.Events[2].Output["messageId"])
+		require.Equal(t, "split", response.Events[2].Metadata.MessageID)
+
+		require.Len(t, response.FailedEvents, 4)
+		require.Equal(t, http.StatusBadRequest, response.FailedEvents[0].StatusCode)
+		require.Contains(t, response.FailedEvents[0].Error, "bad event")
+		require.Equal(t, http.StatusBadRequest, response.FailedEvents[1].StatusCode)
+		require.Equal(t, "user transformation timed out", response.FailedEvents[1].Error)
+		require.Equal(t, http.StatusNotFound, response.FailedEvents[2].StatusCode)
+		require.Equal(t, "m2", response.FailedEvents[2].Metadata.MessageID)
+		require.Equal(t, http.StatusNotFound, response.FailedEvents[3].StatusCode)
+		require.Equal(t, "m3", response.FailedEvents[3].Metadata.MessageID)
+	})
+
+	t.Run("least recently used transformations are evicted", func(t *testing.T) {
+		withVersion := func(versionID string, withCode bool) backendconfig.DestinationT {
+			destination := webhook
+			destination.Transformations = []backendconfig.TransformationT{{VersionID: versionID}}
+			if withCode {
+				destination.Transformations[0].Config = map[string]interface{}{"code": "function transformEvent(event) { return event; }"}
+			}
+			return destination
+		}
+		transform := func(destination backendconfig.DestinationT) transformer.ResponseT {
+			response, err := tr.Transform(ctx, []transformer.TransformerEventT{newEvent("m1", destination)}, integrations.GetUserTransformURL(), 10)
+			require.NoError(t, err)
+			return response
+		}
+
+		for _, versionID := range []string{"v10", "v11", "v12"} {
+			require.Len(t, transform(withVersion(versionID, true)).Events, 1)
+		}
+		// without its code, a version can only run while it is compiled
+		require.Len(t, transform(withVersion("v12", false)).Events, 1)
+		require.Len(t, transform(withVersion("v11", false)).Events, 1)
+		response := transform(withVersion("v10", false))
+		require.Empty(t, response.Events)
+		require.Equal(t, http.StatusNotFound, response.FailedEvents[0].StatusCode)
+	})
+
+	t.Run("cancelled user transformation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := tr.Transform(ctx, []transformer.TransformerEventT{newEvent("loop", webhook)}, integrations.GetUserTransformURL(), 10)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("tracking plan validation passes events through", func(t *testing.T) {
+		events := []transformer.TransformerEventT{newEvent("m1", webhook)}
+		response, err := tr.Validate(ctx, events, integrations.GetTrackingPlanValidationURL(), 10)
+		require.NoError(t, err)
+		require.Empty(t, response.FailedEvents)
+		require.Equal(t, map[string]interface{}(events[0].Message), response.Events[0].Output)
+	})
+}
@@ -0,0 +1,167 @@
+package transformer
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+
+	"github.com/rudderlabs/rudder-server/utils/types"
+)
+
+// userTransformationRunner calls the transformEvent function of the user transformation with the event & metadata
+// as json, so that only plain json values cross the boundary between go and javascript
+const userTransformationRunner = `(function (event, metadata) {
+	var output = transformEvent(JSON.parse(event), JSON.parse(metadata));
+	return output === undefined || output === null ? null : JSON.stringify(output);
+})`
+
+var (
+	// exportKeyword matches the export keywords of transformation code written as an es module, which goja can't load
+	exportKeyword = regexp.MustCompile(`(?m)^(\s*)export\s+(default\s+)?`)
+
+	// the compiled code of the most recently used transformation versions, up to maxCompiledUserTransformations
+	userTransformationProgramsMu  sync.Mutex
+	userTransformationPrograms    = make(map[string]*list.Element) // transformation version id -> element of userTransformationProgramsLRU
+	userTransformationProgramsLRU = list.New()                     // *compiledUserTransformation, most recently used first
+	userTransformationRunnerProg  = goja.MustCompile("runner.js", userTransformationRunner, true)
+
+	errUserTransformationTimeout = errors.New("user transformation timed out")
+)
+
+/*
+userTransformation runs the javascript code of a transformation version, as found in the config of the destination's
+transformation in backend config (Config["code"]). Like the external transformer, the code has to define a
+function transformEvent(event, metadata) which returns the transformed event, an array of events or null to drop it.
+
+A userTransformation is not safe for concurrent use, since goja runtimes aren't.
+*/
+type userTransformation struct {
+	vm  *goja.Runtime
+	run goja.Callable
+}
+
+// newUserTransformation creates a runtime for the code of a transformation version, compiling the code only once per version
+func newUserTransformation(versionID string, transformationConfig map[string]interface{}) (*userTransformation, error) {
+	program, err := compileUserTransformation(versionID, transformationConfig)
+	if err != nil {
+		return nil, err
+	}
+	vm := goja.New()
+	if _, err := vm.RunProgram(program); err != nil {
+		return nil, fmt.Errorf("loading transformation version %s: %w", versionID, err)
+	}
+	if _, ok := goja.AssertFunction(vm.Get("transformEvent")); !ok {
+		return nil, fmt.Errorf("transformation version %s doesn't define a transformEvent function", versionID)
+	}
+	runner, err := vm.RunProgram(userTransformationRunnerProg)
+	if err != nil {
+		return nil, err
+	}
+	run, _ := goja.AssertFunction(runner)
+	return &userTransformation{vm: vm, run: run}, nil
+}
+
+// compiledUserTransformation is the compiled code of a transformation version
+type compiledUserTransformation struct {
+	versionID string
+	program   *goja.Program
+}
+
+// compileUserTransformation compiles the code of a transformation version, or returns it from the cache of the most
+// recently used versions. Versions no longer used by backend config eventually get evicted from the cache.
+func compileUserTransformation(versionID string, transformationConfig map[string]interface{}) (*goja.Program, error) {
+	userTransformationProgramsMu.Lock()
+	defer userTransformationProgramsMu.Unlock()
+	if element, ok := userTransformationPrograms[versionID]; ok {
+		userTransformationProgramsLRU.MoveToFront(element)
+		return element.Value.(*compiledUserTransformation).program, nil
+	}
+	code, _ := transformationConfig["code"].(string)
+	if code == "" {
+		return nil, fmt.Errorf("code of transformation version %s is not available in backend config", versionID)
+	}
+	program, err := goja.Compile(versionID+".js", exportKeyword.ReplaceAllString(code, "$1"), false)
+	if err != nil {
+		return nil, fmt.Errorf("compiling transformation version %s: %w", versionID, err)
+	}
+	userTransformationPrograms[versionID] = userTransformationProgramsLRU.PushFront(&compiledUserTransformation{versionID: versionID, program: program})
+	for userTransformationProgramsLRU.Len() > maxCompiledUserTransformations {
+		oldest := userTransformationProgramsLRU.Remove(userTransformationProgramsLRU.Back())
+		delete(userTransformationPrograms, oldest.(*compiledUserTransformation).versionID)
+	}
+	return program, nil
+}
+
+// transform runs the transformation for an event, interrupting it if it doesn't complete within the timeout or
+// the context gets cancelled
+func (t *userTransformation) transform(ctx context.Context, event types.SingularEventT, metadata MetadataT, timeout time.Duration) ([]types.SingularEventT, error) {
+	rawEvent, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	rawMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	interrupter := make(chan struct{})
+	go func() {
+		defer close(interrupter)
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			t.vm.Interrupt(ctx.Err())
+		case <-timer.C:
+			t.vm.Interrupt(errUserTransformationTimeout)
+		case <-done:
+		}
+	}()
+	output, err := t.run(goja.Undefined(), t.vm.ToValue(string(rawEvent)), t.vm.ToValue(string(rawMetadata)))
+	close(done)
+	<-interrupter
+	t.vm.ClearInterrupt()
+	if err != nil {
+		var interrupted *goja.InterruptedError
+		if errors.As(err, &interrupted) {
+			if cause, ok := interrupted.Value().(error); ok {
+				return nil, cause
+			}
+		}
+		return nil, err
+	}
+	if goja.IsNull(output) {
+		return nil, nil
+	}
+
+	var transformed interface{}
+	if err := json.Unmarshal([]byte(output.String()), &transformed); err != nil {
+		return nil, err
+	}
+	switch transformed := transformed.(type) {
+	case map[string]interface{}:
+		return []types.SingularEventT{transformed}, nil
+	case []interface{}:
+		events := make([]types.SingularEventT, 0, len(transformed))
+		for _, e := range transformed {
+			switch e := e.(type) {
+			case nil:
+			case map[string]interface{}:
+				events = append(events, e)
+			default:
+				return nil, fmt.Errorf("transformEvent returned an array containing a %T instead of events", e)
+			}
+		}
+		return events, nil
+	default:
+		return nil, fmt.Errorf("transformEvent returned a %T instead of an event", transformed)
+	}
+}
@@ -84,13 +84,23 @@ type HandleT struct {
 	Client *http.Client
 
 	guardConcurrency chan struct{}
+
+	unavailabilityHandler UnavailabilityHandler
+}
+
+// UnavailabilityHandler handles the transformer being unreachable past the retries of a request
+type UnavailabilityHandler interface {
+	// Unavailable blocks until the transformer is available again, so that the request can be retried,
+	// or returns an error if the request should not be retried anymore, e.g. ctx.Err() once the request's context is done
+	Unavailable(ctx context.Context, err error) error
 }
 
-// Transformer provides methods to transform events
+// Transformer provides methods to transform events.
+// Transform & Validate only return an error if they were cancelled through their context, in which case the response is incomplete.
 type Transformer interface {
 	Setup()
-	Transform(ctx context.Context, clientEvents []TransformerEventT, url string, batchSize int) ResponseT
-	Validate(clientEvents []TransformerEventT, url string, batchSize int) ResponseT
+	Transform(ctx context.Context, clientEvents []TransformerEventT, url string, batchSize int) (ResponseT, error)
+	Validate(ctx context.Context, clientEvents []TransformerEventT, url string, batchSize int) (ResponseT, error)
 }
 
 // NewTransformer creates a new transformer
@@ -102,6 +112,8 @@ var (
 	maxConcurrency, maxHTTPConnections, maxHTTPIdleConnections, maxRetry int
 	retrySleep                                                           time.Duration
 	timeoutDuration                                                      time.Duration
+	userTransformationTimeout                                            time.Duration
+	maxCompiledUserTransformations                                       int
 	pkgLogger                                                            logger.LoggerI
 )
 
@@ -118,6 +130,8 @@ func loadConfig() {
 	config.RegisterIntConfigVariable(30, &maxRetry, true, 1, "Processor.maxRetry")
 	config.RegisterDurationConfigVariable(100, &retrySleep, true, time.Millisecond, []string{"Processor.retrySleep", "Processor.retrySleepInMS"}...)
 	config.RegisterDurationConfigVariable(30, &timeoutDuration, false, time.Second, "HttpClient.procTransformer.timeout")
+	config.RegisterDurationConfigVariable(5, &userTransformationTimeout, true, time.Second, "Processor.builtinTransformer.userTransformationTimeout")
+	config.RegisterIntConfigVariable(100, &maxCompiledUserTransformations, true, 1, "Processor.builtinTransformer.maxCompiledTransformations")
 }
 
 type TransformerResponseT struct {
@@ -135,6 +149,12 @@ type ValidationErrorT struct {
 	Meta    map[string]string `json:"meta"`
 }
 
+// SetUnavailabilityHandler sets the handler of the transformer being unreachable.
+// Without a handler, requests panic once they run out of retries.
+func (trans *HandleT) SetUnavailabilityHandler(handler UnavailabilityHandler) {
+	trans.unavailabilityHandler = handler
+}
+
 // Setup initializes this class
 func (trans *HandleT) Setup() {
 	trans.logger = pkgLogger
@@ -193,9 +213,9 @@ func GetVersion() (transformerBuildVersion string) {
 // Transform function is used to invoke transformer API
 func (trans *HandleT) Transform(ctx context.Context, clientEvents []TransformerEventT,
 	url string, batchSize int,
-) ResponseT {
+) (ResponseT, error) {
 	if len(clientEvents) == 0 {
-		return ResponseT{}
+		return ResponseT{}, nil
 	}
 
 	sTags := statsTags(clientEvents[0])
@@ -215,6 +235,7 @@ func (trans *HandleT) Transform(ctx context.Context, clientEvents []TransformerE
 	trace.Logf(ctx, "request", "batch_count: %d", batchCount)
 
 	transformResponse := make([][]TransformerResponseT, batchCount)
+	transformErrors := make([]error, batchCount)
 
 	wg := sync.WaitGroup{}
 	wg.Add(len(transformResponse))
@@ -228,13 +249,18 @@ func (trans *HandleT) Transform(ctx context.Context, clientEvents []TransformerE
 		trans.guardConcurrency <- struct{}{}
 		go func() {
 			trace.WithRegion(ctx, "request", func() {
-				transformResponse[i] = trans.request(ctx, url, clientEvents[from:to])
+				transformResponse[i], transformErrors[i] = trans.request(ctx, url, clientEvents[from:to])
 			})
 			<-trans.guardConcurrency
 			wg.Done()
 		}()
 	}
 	wg.Wait()
+	for _, err := range transformErrors {
+		if err != nil {
+			return ResponseT{}, err
+		}
+	}
 
 	var outClientEvents []TransformerResponseT
 	var failedEvents []TransformerResponseT
@@ -261,13 +287,13 @@ func (trans *HandleT) Transform(ctx context.Context, clientEvents []TransformerE
 	return ResponseT{
 		Events:       outClientEvents,
 		FailedEvents: failedEvents,
-	}
+	}, nil
 }
 
-func (trans *HandleT) Validate(clientEvents []TransformerEventT,
+func (trans *HandleT) Validate(ctx context.Context, clientEvents []TransformerEventT,
 	url string, batchSize int,
-) ResponseT {
-	return trans.Transform(context.TODO(), clientEvents, url, batchSize)
+) (ResponseT, error) {
+	return trans.Transform(ctx, clientEvents, url, batchSize)
 }
 
 func (*HandleT) requestTime(s stats.Tags, d time.Duration) {
@@ -282,7 +308,7 @@ func statsTags(event TransformerEventT) stats.Tags {
 	}
 }
 
-func (trans *HandleT) request(ctx context.Context, url string, data []TransformerEventT) []TransformerResponseT {
+func (trans *HandleT) request(ctx context.Context, url string, data []TransformerEventT) ([]TransformerResponseT, error) {
 	// Call remote transformation
 	var (
 		rawJSON []byte
@@ -303,7 +329,7 @@ func (trans *HandleT) request(ctx context.Context, url string, data []Transforme
 	reqFailed := false
 
 	if len(data) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// assume that the first event is representative
@@ -311,7 +337,13 @@ func (trans *HandleT) request(ctx context.Context, url string, data []Transforme
 	for {
 		s := time.Now()
 		trace.WithRegion(ctx, "request/post", func() {
-			resp, err = trans.Client.Post(url, "application/json; charset=utf-8", bytes.NewBuffer(rawJSON))
+			var req *http.Request
+			req, err = http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(rawJSON))
+			if err != nil {
+				return
+			}
+			req.Header.Set("Content-Type", "application/json; charset=utf-8")
+			resp, err = trans.Client.Do(req)
 		})
 		if err == nil {
 			// If no err returned by client.Post, reading body.
@@ -321,14 +353,30 @@ func (trans *HandleT) request(ctx context.Context, url string, data []Transforme
 		}
 
 		if err != nil {
+			// the request was cancelled with its context, e.g. on shutdown
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			trans.requestTime(statsTags(data[0]), time.Since(s))
 			reqFailed = true
 			trans.logger.Errorf("JS HTTP connection error: URL: %v Error: %+v", url, err)
 			if retryCount > maxRetry {
-				panic(fmt.Errorf("JS HTTP connection error: URL: %v Error: %+v", url, err))
+				err = fmt.Errorf("JS HTTP connection error: URL: %v Error: %+v", url, err)
+				if trans.unavailabilityHandler == nil {
+					panic(err)
+				}
+				if err := trans.unavailabilityHandler.Unavailable(ctx, err); err != nil {
+					return nil, err
+				}
+				retryCount = 0
+				continue
 			}
 			retryCount++
-			time.Sleep(retrySleep)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(retrySleep):
+			}
 			// Refresh the connection
 			continue
 		}
@@ -386,5 +434,5 @@ func (trans *HandleT) request(ctx context.Context, url string, data []Transforme
 			transformerResponses = append(transformerResponses, resp)
 		}
 	}
-	return transformerResponses
+	return transformerResponses, nil
 }
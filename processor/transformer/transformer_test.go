@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/rudderlabs/rudder-server/config"
 	"github.com/rudderlabs/rudder-server/processor/transformer"
@@ -124,7 +126,111 @@ func Test_Transformer(t *testing.T) {
 
 		}
 
-		rsp := tr.Transform(context.TODO(), events, srv.URL, batchSize)
+		rsp, err := tr.Transform(context.TODO(), events, srv.URL, batchSize)
+		require.NoError(t, err)
 		require.Equal(t, expectedResponse, rsp)
 	}
 }
+
+type unavailableTransport struct {
+	unavailable bool
+	transport   http.RoundTripper
+}
+
+func (t *unavailableTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if t.unavailable {
+		return nil, fmt.Errorf("connection refused")
+	}
+	return t.transport.RoundTrip(r)
+}
+
+type unavailabilityHandler struct {
+	calls     int
+	transport *unavailableTransport
+}
+
+func (h *unavailabilityHandler) Unavailable(ctx context.Context, _ error) error {
+	h.calls++
+	if h.transport == nil {
+		// the transformer doesn't come back
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	h.transport.unavailable = false
+	return nil
+}
+
+func Test_TransformerUnavailable(t *testing.T) {
+	t.Setenv("RSERVER_PROCESSOR_MAX_RETRY", "1")
+	t.Setenv("RSERVER_PROCESSOR_RETRY_SLEEP", "1ms")
+	config.Load()
+	logger.Init()
+	stats.Setup()
+	transformer.Init()
+
+	ft := &fakeTransformer{}
+	srv := httptest.NewServer(ft)
+	defer srv.Close()
+
+	events := []transformer.TransformerEventT{{
+		Metadata: transformer.MetadataT{MessageID: "messageID-1"},
+		Message:  map[string]interface{}{"src-key-1": "messageID-1", "forceStatusCode": 200},
+	}}
+
+	transport := &unavailableTransport{unavailable: true, transport: srv.Client().Transport}
+	handler := &unavailabilityHandler{transport: transport}
+
+	tr := transformer.NewTransformer()
+	tr.Client = &http.Client{Transport: transport}
+	tr.Setup()
+	tr.SetUnavailabilityHandler(handler)
+
+	// the request is retried once the handler returns, instead of panicking
+	rsp, err := tr.Transform(context.TODO(), events, srv.URL, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, handler.calls)
+	require.Len(t, rsp.Events, 1)
+	require.Equal(t, "messageID-1", rsp.Events[0].Output["echo-key-1"])
+	require.Len(t, ft.requests, 1)
+
+	t.Run("cancelled while unavailable", func(t *testing.T) {
+		handler := &unavailabilityHandler{}
+		tr := transformer.NewTransformer()
+		tr.Client = &http.Client{Transport: &unavailableTransport{unavailable: true}}
+		tr.Setup()
+		tr.SetUnavailabilityHandler(handler)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		// the context error is returned instead of panicking
+		rsp, err := tr.Transform(ctx, events, srv.URL, 10)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Empty(t, rsp.Events)
+		require.Empty(t, rsp.FailedEvents)
+	})
+
+	t.Run("cancelled during a request", func(t *testing.T) {
+		released := make(chan struct{})
+		unresponsive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.Copy(io.Discard, r.Body)
+			select {
+			case <-r.Context().Done():
+			case <-released:
+			}
+		}))
+		defer unresponsive.Close()
+		defer close(released)
+
+		tr := transformer.NewTransformer()
+		tr.Client = unresponsive.Client()
+		tr.Setup()
+		tr.SetUnavailabilityHandler(&unavailabilityHandler{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		// the in-flight request is cancelled instead of waiting for the transformer
+		rsp, err := tr.Transform(ctx, events, unresponsive.URL, 10)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Empty(t, rsp.Events)
+	})
+}
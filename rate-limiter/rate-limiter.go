@@ -7,7 +7,9 @@ import (
 
 	"github.com/EagleChen/restrictor"
 	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/services/kvstoremanager"
 	"github.com/rudderlabs/rudder-server/utils/logger"
+	limiter "github.com/rudderlabs/rudder-server/utils/ratelimiter"
 )
 
 var (
@@ -24,7 +26,8 @@ type RateLimiter interface {
 
 // HandleT is a Handle for event limiter
 type HandleT struct {
-	restrictor restrictor.Restrictor
+	restrictor    restrictor.Restrictor
+	sharedLimiter *limiter.RateLimiter
 }
 
 func Init() {
@@ -43,6 +46,11 @@ func loadConfig() {
 
 // SetUp eventLimiter
 func (rateLimiter *HandleT) SetUp() {
+	if config.GetString("RateLimit.store", "memory") == "redis" {
+		rateLimiter.SetUpWithStore(kvstoremanager.New("REDIS", kvstoremanager.RedisConfig("RateLimit.redis")))
+		return
+	}
+
 	store, err := restrictor.NewMemoryStore()
 	if err != nil {
 		pkgLogger.Error("memory store failed")
@@ -51,7 +59,26 @@ func (rateLimiter *HandleT) SetUp() {
 	rateLimiter.restrictor = restrictor.NewRestrictor(rateLimitWindowInMins, uint32(eventLimit), uint32(noOfBucketsInWindow), store)
 }
 
+// SetUpWithStore sets up the eventLimiter keeping its counters in store, so that the limit applies to all the nodes of the cluster together
+func (rateLimiter *HandleT) SetUpWithStore(store limiter.CounterStore) {
+	dataStore := limiter.NewSharedLimitStore(store, 2*rateLimitWindowInMins)
+	rateLimiter.sharedLimiter = limiter.New(dataStore, int64(eventLimit), rateLimitWindowInMins)
+}
+
 // LimitReached returns true if number of events in the rolling window is less than the max events allowed, else false
 func (rateLimiter *HandleT) LimitReached(key string) bool {
+	if rateLimiter.sharedLimiter != nil {
+		return rateLimiter.sharedLimitReached(key)
+	}
 	return rateLimiter.restrictor.LimitReached(key)
 }
+
+// sharedLimitReached checks the limit and counts the event atomically, so that concurrent nodes don't exceed it together
+func (rateLimiter *HandleT) sharedLimitReached(key string) bool {
+	limitStatus, err := rateLimiter.sharedLimiter.CheckAndInc(key, time.Now())
+	if err != nil {
+		pkgLogger.Errorf("Error checking shared rate limit of %s: %v", key, err)
+		return false
+	}
+	return limitStatus.IsLimited
+}
@@ -0,0 +1,55 @@
+package ratelimiter_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rudderlabs/rudder-server/config"
+	ratelimiter "github.com/rudderlabs/rudder-server/rate-limiter"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+	limiter "github.com/rudderlabs/rudder-server/utils/ratelimiter"
+)
+
+var _ = Describe("RateLimiter", func() {
+	BeforeEach(func() {
+		Expect(os.Setenv("RSERVER_RATE_LIMIT_EVENT_LIMIT", "10")).To(Succeed())
+		DeferCleanup(os.Unsetenv, "RSERVER_RATE_LIMIT_EVENT_LIMIT")
+		config.Load()
+		logger.Init()
+		ratelimiter.Init()
+	})
+
+	It("should limit the events of a key on a single node", func() {
+		var rateLimiter ratelimiter.HandleT
+		rateLimiter.SetUp()
+
+		limited := 0
+		for i := 0; i < 20; i++ {
+			if rateLimiter.LimitReached("w1") {
+				limited++
+			}
+		}
+		Expect(limited).To(BeNumerically(">=", 10))
+		Expect(rateLimiter.LimitReached("w2")).To(BeFalse())
+	})
+
+	It("should share the limit of a key between the nodes using the same store", func() {
+		store := limiter.NewMemoryCounterStore()
+		var node1, node2 ratelimiter.HandleT
+		node1.SetUpWithStore(store)
+		node2.SetUpWithStore(store)
+
+		allowed := 0
+		for i := 0; i < 10; i++ {
+			for _, node := range []*ratelimiter.HandleT{&node1, &node2} {
+				if !node.LimitReached("w1") {
+					allowed++
+				}
+			}
+		}
+		Expect(allowed).To(Equal(10))
+		Expect(node1.LimitReached("w2")).To(BeFalse())
+	})
+})
@@ -0,0 +1,237 @@
+package router
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/router/types"
+	"github.com/rudderlabs/rudder-server/services/stats"
+)
+
+/*
+adaptiveLimiterT limits the concurrency and the send rate of the jobs of each destination of the router, adapting the limits
+to the responses of the destination in an AIMD (additive increase, multiplicative decrease) fashion.
+
+The concurrency limit is the number of jobs of the destination picked up and not yet done. It starts at maxConcurrency,
+shrinks by decreaseFactor when a request gets throttled (429, Retry-After) or takes longer than latencyThreshold,
+and grows back by one every concurrency limit successful requests.
+
+The rate limit is engaged the first time a request gets throttled, at decreaseFactor times the rate of the last second.
+It shrinks by decreaseFactor on every throttled request and grows by rateIncrease for every second without one,
+until it reaches maxRate, or gets disengaged if maxRate is 0.
+*/
+type adaptiveLimiterT struct {
+	destType         string
+	minConcurrency   int
+	maxConcurrency   int
+	latencyThreshold time.Duration
+	decreaseFactor   float64
+	minRate          float64
+	maxRate          float64
+	rateIncrease     float64
+	now              func() time.Time
+
+	mu     sync.Mutex
+	limits map[string]*adaptiveLimitT // destinationID -> limits
+}
+
+type adaptiveLimitT struct {
+	concurrency  float64
+	inFlight     int
+	rate         float64 // jobs per second, 0 if the rate limit is not engaged
+	tokens       float64
+	refilledAt   time.Time
+	sentInSecond int
+	secondStart  time.Time
+	lastRate     int // jobs sent in the last full second
+	decreasedAt  time.Time
+	increasedAt  time.Time
+}
+
+// adaptiveLimitStatus is the status of the limits of a destination, as shown in the router admin status
+type adaptiveLimitStatus struct {
+	Concurrency int     `json:"concurrency"`
+	InFlight    int     `json:"inFlight"`
+	Rate        float64 `json:"rate,omitempty"`
+}
+
+// newAdaptiveLimiter returns the adaptive limiter of the router of destType, or nil if adaptive throttling is disabled for it
+func newAdaptiveLimiter(destType string) *adaptiveLimiterT {
+	if !getRouterConfigBool("adaptiveThrottling.enabled", destType, false) {
+		return nil
+	}
+	al := &adaptiveLimiterT{
+		destType: destType,
+		now:      time.Now,
+		limits:   make(map[string]*adaptiveLimitT),
+	}
+	keys := func(key string) []string {
+		return []string{"Router." + destType + ".adaptiveThrottling." + key, "Router.adaptiveThrottling." + key}
+	}
+	config.RegisterIntConfigVariable(1, &al.minConcurrency, true, 1, keys("minConcurrency")...)
+	config.RegisterIntConfigVariable(100, &al.maxConcurrency, true, 1, keys("maxConcurrency")...)
+	config.RegisterDurationConfigVariable(5, &al.latencyThreshold, true, time.Second, keys("latencyThreshold")...)
+	config.RegisterFloat64ConfigVariable(0.5, &al.decreaseFactor, true, keys("decreaseFactor")...)
+	config.RegisterFloat64ConfigVariable(1, &al.minRate, true, keys("minRate")...)
+	config.RegisterFloat64ConfigVariable(0, &al.maxRate, true, keys("maxRate")...)
+	config.RegisterFloat64ConfigVariable(1, &al.rateIncrease, true, keys("rateIncrease")...)
+	return al
+}
+
+// limit needs to be called with al.mu held
+func (al *adaptiveLimiterT) limit(destID string) *adaptiveLimitT {
+	limit, ok := al.limits[destID]
+	if !ok {
+		now := al.now()
+		limit = &adaptiveLimitT{
+			concurrency: float64(al.maxConcurrency),
+			refilledAt:  now,
+			secondStart: now,
+		}
+		al.limits[destID] = limit
+	}
+	return limit
+}
+
+// allow returns whether a job of the destination can be picked up. A nil adaptive limiter allows all jobs.
+func (al *adaptiveLimiterT) allow(destID string) bool {
+	if al == nil {
+		return true
+	}
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	limit := al.limit(destID)
+	now := al.now()
+	if limit.inFlight >= int(limit.concurrency) {
+		return false
+	}
+	if limit.rate > 0 {
+		limit.tokens = math.Min(limit.tokens+limit.rate*now.Sub(limit.refilledAt).Seconds(), math.Max(limit.rate, 1))
+		limit.refilledAt = now
+		if limit.tokens < 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// picked records that a job allowed by allow got assigned to a worker
+func (al *adaptiveLimiterT) picked(destID string) {
+	if al == nil {
+		return
+	}
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	limit := al.limit(destID)
+	limit.inFlight++
+	if limit.rate > 0 {
+		limit.tokens--
+	}
+	now := al.now()
+	if now.Sub(limit.secondStart) >= time.Second {
+		limit.lastRate = limit.sentInSecond
+		limit.sentInSecond = 0
+		limit.secondStart = now
+	}
+	limit.sentInSecond++
+}
+
+// released records that a picked job of the destination is done
+func (al *adaptiveLimiterT) released(destID string) {
+	if al == nil {
+		return
+	}
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	if limit, ok := al.limits[destID]; ok && limit.inFlight > 0 {
+		limit.inFlight--
+	}
+}
+
+// observe adapts the limits of the destination to the response of a request sent to it
+func (al *adaptiveLimiterT) observe(destID string, statusCode int, latency, retryAfter time.Duration) {
+	if al == nil || statusCode == types.RouterTimedOutStatusCode || statusCode == types.RouterUnMarshalErrorCode {
+		return
+	}
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	limit := al.limit(destID)
+	now := al.now()
+	throttled := statusCode == http.StatusTooManyRequests || retryAfter > 0
+
+	switch {
+	case throttled:
+		if limit.rate == 0 {
+			// engaging the rate limit at the rate of the last second, decreased below
+			limit.rate = math.Max(float64(limit.lastRate), al.minRate)
+			limit.tokens = 0
+			limit.refilledAt = now
+		}
+		al.decrease(destID, limit, now, true)
+	case latency > al.latencyThreshold:
+		al.decrease(destID, limit, now, false)
+	case statusCode < http.StatusInternalServerError:
+		al.increase(destID, limit, now)
+	}
+}
+
+// decrease needs to be called with al.mu held. Decreases are applied at most once per latency threshold,
+// so that the responses of the requests already in flight don't collapse the limits.
+func (al *adaptiveLimiterT) decrease(destID string, limit *adaptiveLimitT, now time.Time, rate bool) {
+	if now.Sub(limit.decreasedAt) < al.latencyThreshold {
+		return
+	}
+	limit.decreasedAt = now
+	limit.concurrency = math.Max(limit.concurrency*al.decreaseFactor, float64(al.minConcurrency))
+	if rate {
+		limit.rate = math.Max(limit.rate*al.decreaseFactor, al.minRate)
+	}
+	al.report(destID, limit)
+}
+
+// increase needs to be called with al.mu held
+func (al *adaptiveLimiterT) increase(destID string, limit *adaptiveLimitT, now time.Time) {
+	concurrency, rate := int(limit.concurrency), limit.rate
+	limit.concurrency = math.Min(limit.concurrency+1/limit.concurrency, float64(al.maxConcurrency))
+	if limit.rate > 0 && now.Sub(limit.increasedAt) >= time.Second && now.Sub(limit.decreasedAt) >= time.Second {
+		limit.increasedAt = now
+		limit.rate += al.rateIncrease
+		if al.maxRate > 0 {
+			limit.rate = math.Min(limit.rate, al.maxRate)
+		} else if limit.rate > float64(2*limit.lastRate) && limit.rate > al.minRate {
+			// the rate limit isn't limiting anymore
+			limit.rate = 0
+		}
+	}
+	if int(limit.concurrency) != concurrency || limit.rate != rate {
+		al.report(destID, limit)
+	}
+}
+
+// report needs to be called with al.mu held
+func (al *adaptiveLimiterT) report(destID string, limit *adaptiveLimitT) {
+	tags := stats.Tags{"destType": al.destType, "destinationId": destID}
+	stats.NewTaggedStat("router_adaptive_concurrency_limit", stats.GaugeType, tags).Gauge(int(limit.concurrency))
+	stats.NewTaggedStat("router_adaptive_rate_limit", stats.GaugeType, tags).Gauge(limit.rate)
+}
+
+// status returns the current limits by destination id
+func (al *adaptiveLimiterT) status() map[string]adaptiveLimitStatus {
+	status := make(map[string]adaptiveLimitStatus)
+	if al == nil {
+		return status
+	}
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	for destID, limit := range al.limits {
+		status[destID] = adaptiveLimitStatus{
+			Concurrency: int(limit.concurrency),
+			InFlight:    limit.inFlight,
+			Rate:        limit.rate,
+		}
+	}
+	return status
+}
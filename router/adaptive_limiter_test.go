@@ -0,0 +1,104 @@
+package router
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rudderlabs/rudder-server/services/stats"
+)
+
+var _ = Describe("Adaptive limiter", func() {
+	var (
+		al  *adaptiveLimiterT
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		stats.Setup()
+		now = time.Now()
+		al = &adaptiveLimiterT{
+			destType:         "WEBHOOK",
+			minConcurrency:   1,
+			maxConcurrency:   4,
+			latencyThreshold: time.Second,
+			decreaseFactor:   0.5,
+			minRate:          1,
+			rateIncrease:     1,
+			now:              func() time.Time { return now },
+			limits:           make(map[string]*adaptiveLimitT),
+		}
+	})
+
+	pick := func(destID string) int {
+		picked := 0
+		for al.allow(destID) {
+			al.picked(destID)
+			picked++
+		}
+		return picked
+	}
+
+	It("should allow every job when disabled", func() {
+		var disabled *adaptiveLimiterT
+		disabled.picked("d1")
+		disabled.observe("d1", http.StatusTooManyRequests, time.Second, time.Minute)
+		Expect(disabled.allow("d1")).To(BeTrue())
+		Expect(disabled.status()).To(BeEmpty())
+	})
+
+	It("should limit the jobs in flight per destination", func() {
+		Expect(pick("d1")).To(Equal(4))
+		Expect(pick("d2")).To(Equal(4))
+
+		al.released("d1")
+		Expect(pick("d1")).To(Equal(1))
+		Expect(al.status()["d1"]).To(Equal(adaptiveLimitStatus{Concurrency: 4, InFlight: 4}))
+	})
+
+	It("should decrease the concurrency multiplicatively on slow responses and increase it additively", func() {
+		al.observe("d1", http.StatusOK, 2*time.Second, 0)
+		Expect(al.status()["d1"].Concurrency).To(Equal(2))
+
+		By("ignoring slow responses of requests already in flight")
+		al.observe("d1", http.StatusOK, 2*time.Second, 0)
+		Expect(al.status()["d1"].Concurrency).To(Equal(2))
+
+		now = now.Add(time.Second)
+		al.observe("d1", http.StatusOK, 2*time.Second, 0)
+		al.observe("d1", http.StatusOK, 2*time.Second, 0)
+		Expect(al.status()["d1"].Concurrency).To(Equal(1))
+
+		al.observe("d1", http.StatusOK, time.Millisecond, 0)
+		Expect(al.status()["d1"].Concurrency).To(Equal(2))
+		for i := 0; i < 3; i++ {
+			al.observe("d1", http.StatusOK, time.Millisecond, 0)
+		}
+		Expect(al.status()["d1"].Concurrency).To(Equal(3))
+	})
+
+	It("should engage a rate limit when throttled by the destination", func() {
+		Expect(pick("d1")).To(Equal(4))
+		for i := 0; i < 4; i++ {
+			al.released("d1")
+		}
+		now = now.Add(time.Second)
+		al.picked("d1")
+		al.released("d1")
+
+		al.observe("d1", http.StatusTooManyRequests, time.Millisecond, 0)
+		Expect(al.status()["d1"]).To(Equal(adaptiveLimitStatus{Concurrency: 2, Rate: 2}))
+
+		Expect(al.allow("d1")).To(BeFalse())
+		now = now.Add(500 * time.Millisecond)
+		Expect(pick("d1")).To(Equal(1))
+
+		By("increasing the rate every second without throttling")
+		al.released("d1")
+		now = now.Add(time.Second)
+		al.observe("d1", http.StatusOK, time.Millisecond, 0)
+		Expect(al.status()["d1"].Rate).To(Equal(float64(3)))
+	})
+})
@@ -64,6 +64,15 @@ func (ra *Admin) Status() interface{} {
 		if len(abortedUsersMap) > 0 {
 			routerStatus["aborted-usersmap"] = abortedUsersMap
 		}
+		if circuitBreakers := router.circuitBreaker.status(); len(circuitBreakers) > 0 {
+			routerStatus["circuit-breakers"] = circuitBreakers
+		}
+		if adaptiveLimits := router.adaptiveLimiter.status(); len(adaptiveLimits) > 0 {
+			routerStatus["adaptive-limits"] = adaptiveLimits
+		}
+		if pausedDestinations := router.pausedDestinations.status(); len(pausedDestinations) > 0 {
+			routerStatus["paused-destinations"] = pausedDestinations
+		}
 
 		statusList = append(statusList, routerStatus)
 	}
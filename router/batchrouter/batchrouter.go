@@ -38,6 +38,7 @@ import (
 	"github.com/rudderlabs/rudder-server/rruntime"
 	destinationdebugger "github.com/rudderlabs/rudder-server/services/debugger/destination"
 	"github.com/rudderlabs/rudder-server/services/diagnostics"
+	"github.com/rudderlabs/rudder-server/services/dlq"
 	"github.com/rudderlabs/rudder-server/services/filemanager"
 	"github.com/rudderlabs/rudder-server/services/stats"
 	"github.com/rudderlabs/rudder-server/utils/bytesize"
@@ -82,6 +83,7 @@ type HandleT struct {
 	processQ                    chan *BatchDestinationDataT
 	jobsDB                      jobsdb.JobsDB
 	errorDB                     jobsdb.JobsDB
+	dlqDB                       jobsdb.JobsDB // dead-letter queue of aborted jobs, nil if disabled
 	isEnabled                   bool
 	batchRequestsMetricLock     sync.RWMutex
 	multitenantI                multitenant.MultiTenantI
@@ -125,6 +127,7 @@ type HandleT struct {
 	backgroundWait   func() error
 
 	payloadLimit              int64
+	enablePriorityLanes       bool
 	transientSources          transientsource.Service
 	rsourcesService           rsources.JobService
 	jobsDBCommandTimeout      time.Duration
@@ -183,7 +186,11 @@ func (brt *HandleT) backendConfigSubscriber() {
 				for _, destination := range source.Destinations {
 					if destination.DestinationDefinition.Name == brt.destType {
 						if _, ok := brt.destinationsMap[destination.ID]; !ok {
-							brt.destinationsMap[destination.ID] = &router_utils.BatchDestinationT{Destination: destination, Sources: []backendconfig.SourceT{}}
+							brt.destinationsMap[destination.ID] = &router_utils.BatchDestinationT{
+								Destination:     destination,
+								Sources:         []backendconfig.SourceT{},
+								DeliveryWindows: router_utils.GetDeliveryWindows(destination),
+							}
 							brt.uploadIntervalMap[destination.ID] = brt.parseUploadIntervalFromConfig(destination.Config)
 						}
 						brt.destinationsMap[destination.ID].Sources = append(brt.destinationsMap[destination.ID].Sources, source)
@@ -1321,6 +1328,20 @@ func (brt *HandleT) setJobStatus(batchJobs *BatchJobsT, isWarehouse bool, errOcc
 		}
 	}
 
+	// Store the aborted jobs to the dead-letter queue
+	if abortedEvents != nil && brt.dlqDB != nil {
+		dlqJobs := make([]*jobsdb.JobT, 0, len(abortedEvents))
+		for _, job := range abortedEvents {
+			dlqJobs = append(dlqJobs, dlq.NewJob(job, types.BATCH_ROUTER, strconv.Itoa(getBRTErrorCode(jobsdb.Aborted.State)), errorResp))
+		}
+		err := misc.RetryWith(context.Background(), brt.jobsDBCommandTimeout, brt.jobdDBMaxRetries, func(ctx context.Context) error {
+			return brt.dlqDB.Store(ctx, dlqJobs)
+		})
+		if err != nil {
+			panic(fmt.Errorf("storing jobs into dead-letter queue: %w", err))
+		}
+	}
+
 	// REPORTING - START
 	if brt.reporting != nil && brt.reportingEnabled {
 		types.AssertSameKeys(connectionDetailsMap, statusDetailsMap)
@@ -1620,7 +1641,7 @@ func (worker *workerT) workerProcess() {
 				}
 
 				toRetry, err := jobsdb.QueryJobsResultWithRetries(context.Background(), brt.jobdDBQueryRequestTimeout, brt.jobdDBMaxRetries, func(ctx context.Context) (jobsdb.JobsResult, error) {
-					return brt.jobsDB.GetToRetry(ctx, queryParams)
+					return brt.prioritised(brt.jobsDB.GetToRetry)(ctx, queryParams)
 				})
 				if err != nil {
 					brt.logger.Errorf("BRT: %s: Error while reading from DB: %v", brt.destType, err)
@@ -1633,7 +1654,7 @@ func (worker *workerT) workerProcess() {
 						queryParams.PayloadSizeLimit -= toRetry.PayloadSize
 					}
 					unprocessed, err := jobsdb.QueryJobsResultWithRetries(context.Background(), brt.jobdDBQueryRequestTimeout, brt.jobdDBMaxRetries, func(ctx context.Context) (jobsdb.JobsResult, error) {
-						return brt.jobsDB.GetUnprocessed(ctx, queryParams)
+						return brt.prioritised(brt.jobsDB.GetUnprocessed)(ctx, queryParams)
 					})
 					if err != nil {
 						brt.logger.Errorf("BRT: %s: Error while reading from DB: %v", brt.destType, err)
@@ -1955,6 +1976,10 @@ func (brt *HandleT) readAndProcess() {
 				brt.logger.Debugf("BRT: Skipping batch router upload loop since destination %s:%s is in progress", batchDest.Destination.DestinationDefinition.Name, destID)
 				continue
 			}
+			if !batchDest.DeliveryWindows.IsOpen(time.Now()) {
+				brt.logger.Debugf("BRT: Skipping batch router upload loop since destination %s:%s is outside of its delivery windows", batchDest.Destination.DestinationDefinition.Name, destID)
+				continue
+			}
 			if brt.uploadFrequencyExceeded(destID) {
 				brt.logger.Debugf("BRT: Skipping batch router upload loop since %s:%s upload freq not exceeded", batchDest.Destination.DestinationDefinition.Name, destID)
 				continue
@@ -1974,13 +1999,15 @@ func (brt *HandleT) readAndProcess() {
 		brtQueryStat.Start()
 
 		if !brt.holdFetchingJobs([]jobsdb.ParameterFilterT{}) {
+			now := time.Now()
 			queryParams := jobsdb.GetQueryParamsT{
-				CustomValFilters: []string{brt.destType},
-				JobsLimit:        brt.jobQueryBatchSize,
-				PayloadSizeLimit: brt.payloadLimit,
+				CustomValFilters:         []string{brt.destType},
+				ExcludedParameterFilters: heldDestinationFilters(destinationsMap, now),
+				JobsLimit:                brt.jobQueryBatchSize,
+				PayloadSizeLimit:         brt.payloadLimit,
 			}
 			toRetry, err := jobsdb.QueryJobsResultWithRetries(context.Background(), brt.jobdDBQueryRequestTimeout, brt.jobdDBMaxRetries, func(ctx context.Context) (jobsdb.JobsResult, error) {
-				return brt.jobsDB.GetToRetry(ctx, queryParams)
+				return brt.prioritised(brt.jobsDB.GetToRetry)(ctx, queryParams)
 			})
 			if err != nil {
 				brt.logger.Errorf("BRT: %s: Error getting jobs to retry: %s", brt.destType, err)
@@ -1995,7 +2022,7 @@ func (brt *HandleT) readAndProcess() {
 					queryParams.PayloadSizeLimit -= toRetry.PayloadSize
 				}
 				unprocessed, err := jobsdb.QueryJobsResultWithRetries(context.Background(), brt.jobdDBQueryRequestTimeout, brt.jobdDBMaxRetries, func(ctx context.Context) (jobsdb.JobsResult, error) {
-					return brt.jobsDB.GetUnprocessed(ctx, queryParams)
+					return brt.prioritised(brt.jobsDB.GetUnprocessed)(ctx, queryParams)
 				})
 				if err != nil {
 					brt.logger.Errorf("BRT: %s: Error getting jobs to retry: %s", brt.destType, err)
@@ -2010,6 +2037,11 @@ func (brt *HandleT) readAndProcess() {
 
 			var wg sync.WaitGroup
 			for destID, batchDest := range destinationsMap {
+				if !batchDest.DeliveryWindows.IsOpen(now) {
+					// jobs of the destination are left as they are, until its delivery window opens
+					brt.logger.Debugf("BRT: Skipping batch router upload loop since destination %s:%s is outside of its delivery windows", batchDest.Destination.DestinationDefinition.Name, destID)
+					continue
+				}
 				brt.setDestInProgress(destID, true)
 
 				wg.Add(1)
@@ -2021,6 +2053,18 @@ func (brt *HandleT) readAndProcess() {
 	}
 }
 
+// heldDestinationFilters returns the filters excluding the jobs of the destinations outside of their delivery windows at t
+// from the query of the jobs of all destinations, so that their held jobs don't take the place of the jobs of the other destinations
+func heldDestinationFilters(destinationsMap map[string]*router_utils.BatchDestinationT, t time.Time) []jobsdb.ParameterFilterT {
+	var filters []jobsdb.ParameterFilterT
+	for destID, batchDest := range destinationsMap {
+		if !batchDest.DeliveryWindows.IsOpen(t) {
+			filters = append(filters, jobsdb.ParameterFilterT{Name: "destination_id", Value: destID})
+		}
+	}
+	return filters
+}
+
 func (brt *HandleT) mainLoop(ctx context.Context) {
 loop:
 	for {
@@ -2126,6 +2170,14 @@ func (brt *HandleT) dedupRawDataDestJobsOnCrash() {
 	}
 }
 
+// prioritised returns a query fetching jobs in priority lanes if priority lanes are enabled, otherwise the query itself
+func (brt *HandleT) prioritised(query jobsdb.QueryFunc) jobsdb.QueryFunc {
+	if brt.enablePriorityLanes {
+		return jobsdb.QueryByPriority(query)
+	}
+	return query
+}
+
 func (brt *HandleT) holdFetchingJobs(parameterFilters []jobsdb.ParameterFilterT) bool {
 	// var importingList jobsdb.JobsResult
 	// var err error
@@ -2314,6 +2366,7 @@ func (brt *HandleT) Setup(backendConfig backendconfig.BackendConfig, jobsDB, err
 	config.RegisterDurationConfigVariable(180, &brt.retryTimeWindow, true, time.Minute, []string{"BatchRouter." + brt.destType + "." + "retryTimeWindow", "BatchRouter." + brt.destType + "." + "retryTimeWindowInMins", "BatchRouter." + "retryTimeWindow", "BatchRouter." + "retryTimeWindowInMins"}...)
 	config.RegisterDurationConfigVariable(30, &brt.asyncUploadTimeout, true, time.Minute, []string{"BatchRouter." + brt.destType + "." + "asyncUploadTimeout", "BatchRouter." + "asyncUploadTimeout"}...)
 	config.RegisterInt64ConfigVariable(1*bytesize.GB, &brt.payloadLimit, true, 1, []string{"BatchRouter." + brt.destType + "." + "PayloadLimit", "BatchRouter.PayloadLimit"}...)
+	config.RegisterBoolConfigVariable(false, &brt.enablePriorityLanes, true, []string{"BatchRouter." + brt.destType + "." + "enablePriorityLanes", "BatchRouter.enablePriorityLanes"}...)
 	config.RegisterIntConfigVariable(3, &brt.jobdDBMaxRetries, true, 1, []string{"JobsDB.BatchRouter.MaxRetries", "JobsDB.MaxRetries"}...)
 	config.RegisterDurationConfigVariable(60, &brt.jobdDBQueryRequestTimeout, true, time.Second, []string{"JobsDB.BatchRouter.QueryRequestTimeout", "JobsDB.QueryRequestTimeout"}...)
 	config.RegisterDurationConfigVariable(90, &brt.jobsDBCommandTimeout, true, time.Second, []string{"JobsDB.BatchRouter.CommandRequestTimeout", "JobsDB.CommandRequestTimeout"}...)
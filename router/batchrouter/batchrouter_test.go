@@ -261,6 +261,36 @@ var _ = Describe("BatchRouter", func() {
 			batchrouter.readAndProcess()
 		})
 
+		It("should hold jobs of a destination outside of its delivery windows", func() {
+			batchrouter := &HandleT{}
+
+			batchrouter.Setup(c.mockBackendConfig, c.mockBatchRouterJobsDB, c.mockProcErrorsDB, s3DestinationDefinition.Name, nil, c.mockMultitenantI, transientsource.NewEmptyService(), rsources.NewNoOpService())
+			readPerDestination = false
+			setQueryFilters()
+			batchrouter.fileManagerFactory = c.mockFileManagerFactory
+
+			// the jobs of the destination are left out of the query, so that they don't take the place of the jobs of other destinations
+			excludesHeldDestination := func(_ context.Context, params jobsdb.GetQueryParamsT) (jobsdb.JobsResult, error) {
+				Expect(params.ExcludedParameterFilters).To(Equal([]jobsdb.ParameterFilterT{{Name: "destination_id", Value: S3DestinationID}}))
+				return jobsdb.JobsResult{}, nil
+			}
+			callRetry := c.mockBatchRouterJobsDB.EXPECT().GetToRetry(gomock.Any(), gomock.Any()).DoAndReturn(excludesHeldDestination).Times(1)
+			c.mockBatchRouterJobsDB.EXPECT().GetUnprocessed(gomock.Any(), gomock.Any()).DoAndReturn(excludesHeldDestination).Times(1).After(callRetry)
+
+			<-batchrouter.backendConfigInitialized
+			// a blackout of the whole day, but the last minute of it
+			batchrouter.configSubscriberLock.Lock()
+			now := time.Now().UTC()
+			minute := now.Hour()*60 + now.Minute()
+			batchrouter.destinationsMap[S3DestinationID].DeliveryWindows = router_utils.DeliveryWindowsT{
+				Blackouts: []router_utils.WindowT{{Start: minute, End: (minute + 1439) % 1440}},
+			}
+			batchrouter.configSubscriberLock.Unlock()
+
+			batchrouter.readAndProcess()
+			Expect(batchrouter.isDestInProgress(S3DestinationID)).To(BeFalse())
+		})
+
 		// It("should split batchJobs based on timeWindow for s3 datalake destination", func() {
 
 		// 	batchJobs := BatchJobsT{
@@ -15,12 +15,13 @@ type Factory struct {
 	BackendConfig    backendconfig.BackendConfig
 	RouterDB         jobsdb.JobsDB
 	ProcErrorDB      jobsdb.JobsDB
+	DLQDB            jobsdb.JobsDB // optional, aborted jobs are stored into it if set
 	TransientSources transientsource.Service
 	RsourcesService  rsources.JobService
 }
 
 func (f *Factory) New(destType string) *HandleT {
-	r := &HandleT{}
+	r := &HandleT{dlqDB: f.DLQDB}
 
 	r.Setup(f.BackendConfig, f.RouterDB, f.ProcErrorDB, destType, f.Reporting, f.Multitenant, f.TransientSources, f.RsourcesService)
 	return r
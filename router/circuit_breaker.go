@@ -0,0 +1,223 @@
+package router
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/router/types"
+	"github.com/rudderlabs/rudder-server/services/stats"
+)
+
+type circuitBreakerState int
+
+const (
+	// circuitClosed lets jobs through to the destination
+	circuitClosed circuitBreakerState = iota
+	// circuitHalfOpen lets a few probe jobs through to the destination, to find out whether it recovered
+	circuitHalfOpen
+	// circuitOpen parks the jobs of the destination in waiting state, without sending them
+	circuitOpen
+)
+
+// circuitOpenReason is the reason of the jobs parked while the circuit breaker of their destination is open
+const circuitOpenReason = "circuit breaker of destination is open"
+
+func (s circuitBreakerState) String() string {
+	switch s {
+	case circuitHalfOpen:
+		return "half-open"
+	case circuitOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+/*
+circuitBreakerT keeps a circuit breaker per destination of the router, driven by the status codes of the requests sent to the destinations.
+
+A circuit opens after failureThreshold consecutive requests to the destination failed with a 5xx status code. While open, the jobs
+of the destination are parked in waiting state instead of being sent. After openTimeout the circuit becomes half-open and lets
+halfOpenProbes jobs through: the circuit closes if they succeed, or opens again if they fail.
+*/
+type circuitBreakerT struct {
+	destType         string
+	failureThreshold int
+	halfOpenProbes   int
+	openTimeout      time.Duration
+	now              func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuitT // destinationID -> circuit
+}
+
+type circuitT struct {
+	state               circuitBreakerState
+	consecutiveFailures int
+	openedAt            time.Time
+	probes              int
+	lastProbeAt         time.Time
+}
+
+// newCircuitBreaker returns the circuit breaker of the router of destType, or nil if circuit breaking is disabled for it
+func newCircuitBreaker(destType string) *circuitBreakerT {
+	if !getRouterConfigBool("circuitBreaker.enabled", destType, false) {
+		return nil
+	}
+	cb := &circuitBreakerT{
+		destType: destType,
+		now:      time.Now,
+		circuits: make(map[string]*circuitT),
+	}
+	config.RegisterIntConfigVariable(50, &cb.failureThreshold, true, 1, "Router."+destType+".circuitBreaker.failureThreshold", "Router.circuitBreaker.failureThreshold")
+	config.RegisterIntConfigVariable(1, &cb.halfOpenProbes, true, 1, "Router."+destType+".circuitBreaker.halfOpenProbes", "Router.circuitBreaker.halfOpenProbes")
+	config.RegisterDurationConfigVariable(30, &cb.openTimeout, true, time.Second, "Router."+destType+".circuitBreaker.openTimeout", "Router.circuitBreaker.openTimeout")
+	return cb
+}
+
+// allow returns whether a job of the destination can be sent. A nil circuit breaker allows all jobs.
+func (cb *circuitBreakerT) allow(destID string) bool {
+	if cb == nil {
+		return true
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	circuit, ok := cb.circuits[destID]
+	if !ok {
+		return true
+	}
+	now := cb.now()
+	switch circuit.state {
+	case circuitOpen:
+		if now.Sub(circuit.openedAt) < cb.openTimeout {
+			return false
+		}
+		cb.transition(destID, circuit, circuitHalfOpen)
+		return true
+	case circuitHalfOpen:
+		// probes may never report back, e.g. if they are parked behind a failed job of the same user
+		if circuit.probes > 0 && now.Sub(circuit.lastProbeAt) >= cb.openTimeout {
+			circuit.probes = 0
+		}
+		return circuit.probes < cb.halfOpenProbes
+	default:
+		return true
+	}
+}
+
+// openDestinationFilters returns the filters excluding the jobs of the destinations whose circuit doesn't let jobs through
+// from the pickup query, so that they don't take the place of the jobs of the other destinations. The jobs of half-open
+// circuits with probes left, and of open circuits due to become half-open, are picked up, so that they probe the destination.
+func (cb *circuitBreakerT) openDestinationFilters() []jobsdb.ParameterFilterT {
+	if cb == nil {
+		return nil
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	now := cb.now()
+	var filters []jobsdb.ParameterFilterT
+	for destID, circuit := range cb.circuits {
+		var blocked bool
+		switch circuit.state {
+		case circuitOpen:
+			blocked = now.Sub(circuit.openedAt) < cb.openTimeout
+		case circuitHalfOpen:
+			blocked = circuit.probes >= cb.halfOpenProbes && now.Sub(circuit.lastProbeAt) < cb.openTimeout
+		}
+		if blocked {
+			filters = append(filters, jobsdb.ParameterFilterT{Name: "destination_id", Value: destID})
+		}
+	}
+	return filters
+}
+
+// picked records that a job allowed by allow got assigned to a worker, using up a probe of a half-open circuit
+func (cb *circuitBreakerT) picked(destID string) {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if circuit, ok := cb.circuits[destID]; ok && circuit.state == circuitHalfOpen {
+		circuit.probes++
+		circuit.lastProbeAt = cb.now()
+	}
+}
+
+// record updates the circuit of the destination with the status code of a request sent to it
+func (cb *circuitBreakerT) record(destID string, statusCode int) {
+	if cb == nil || statusCode == types.RouterTimedOutStatusCode || statusCode == types.RouterUnMarshalErrorCode {
+		return
+	}
+	failed := statusCode >= http.StatusInternalServerError
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	circuit, ok := cb.circuits[destID]
+	if !ok {
+		if !failed {
+			return
+		}
+		circuit = &circuitT{}
+		cb.circuits[destID] = circuit
+	}
+	switch circuit.state {
+	case circuitClosed:
+		if !failed {
+			circuit.consecutiveFailures = 0
+			return
+		}
+		circuit.consecutiveFailures++
+		if circuit.consecutiveFailures >= cb.failureThreshold {
+			cb.transition(destID, circuit, circuitOpen)
+		}
+	case circuitHalfOpen:
+		if failed {
+			cb.transition(destID, circuit, circuitOpen)
+		} else {
+			cb.transition(destID, circuit, circuitClosed)
+		}
+	}
+	// results of requests sent before the circuit opened are ignored
+}
+
+// transition needs to be called with cb.mu held
+func (cb *circuitBreakerT) transition(destID string, circuit *circuitT, state circuitBreakerState) {
+	pkgLogger.Infof("[%s Router] :: circuit breaker of destination %s is %s, was %s", cb.destType, destID, state, circuit.state)
+	circuit.state = state
+	circuit.consecutiveFailures = 0
+	circuit.probes = 0
+	if state == circuitOpen {
+		circuit.openedAt = cb.now()
+	}
+	tags := stats.Tags{"destType": cb.destType, "destinationId": destID}
+	stats.NewTaggedStat("router_circuit_breaker_state", stats.GaugeType, tags).Gauge(int(state))
+	tags = stats.Tags{"destType": cb.destType, "destinationId": destID, "state": state.String()}
+	stats.NewTaggedStat("router_circuit_breaker_transitions", stats.CountType, tags).Increment()
+}
+
+// status returns the state of the circuits which are not closed, by destination id
+func (cb *circuitBreakerT) status() map[string]string {
+	status := make(map[string]string)
+	if cb == nil {
+		return status
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	for destID, circuit := range cb.circuits {
+		if circuit.state != circuitClosed {
+			status[destID] = circuit.state.String()
+		}
+	}
+	return status
+}
+
+// isParkedByCircuitBreaker returns whether the job is already parked because of an open circuit
+func isParkedByCircuitBreaker(job *jobsdb.JobT) bool {
+	return job.LastJobStatus.JobState == jobsdb.Waiting.State &&
+		gjson.GetBytes(job.LastJobStatus.ErrorResponse, "reason").String() == circuitOpenReason
+}
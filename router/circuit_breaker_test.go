@@ -0,0 +1,131 @@
+package router
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/router/types"
+	"github.com/rudderlabs/rudder-server/services/stats"
+)
+
+var _ = Describe("Circuit breaker", func() {
+	var (
+		cb  *circuitBreakerT
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		stats.Setup()
+		now = time.Now()
+		cb = &circuitBreakerT{
+			destType:         "GA",
+			failureThreshold: 3,
+			halfOpenProbes:   1,
+			openTimeout:      time.Minute,
+			now:              func() time.Time { return now },
+			circuits:         make(map[string]*circuitT),
+		}
+	})
+
+	fail := func(destID string, times int) {
+		for i := 0; i < times; i++ {
+			cb.record(destID, 503)
+		}
+	}
+
+	It("should allow every job when disabled", func() {
+		var disabled *circuitBreakerT
+		disabled.record("d1", 500)
+		Expect(disabled.allow("d1")).To(BeTrue())
+		Expect(disabled.status()).To(BeEmpty())
+	})
+
+	It("should open after consecutive failures of a destination only", func() {
+		fail("d1", 2)
+		cb.record("d1", 400)
+		fail("d1", 2)
+		Expect(cb.allow("d1")).To(BeTrue())
+
+		fail("d1", 1)
+		Expect(cb.allow("d1")).To(BeFalse())
+		Expect(cb.allow("d2")).To(BeTrue())
+		Expect(cb.status()).To(Equal(map[string]string{"d1": "open"}))
+	})
+
+	It("should ignore status codes of the router itself", func() {
+		for i := 0; i < 5; i++ {
+			cb.record("d1", types.RouterTimedOutStatusCode)
+			cb.record("d1", types.RouterUnMarshalErrorCode)
+		}
+		Expect(cb.allow("d1")).To(BeTrue())
+	})
+
+	It("should close after a successful probe", func() {
+		fail("d1", 3)
+		now = now.Add(time.Minute)
+		Expect(cb.allow("d1")).To(BeTrue())
+		Expect(cb.status()).To(Equal(map[string]string{"d1": "half-open"}))
+		cb.picked("d1")
+		Expect(cb.allow("d1")).To(BeFalse())
+
+		cb.record("d1", 200)
+		Expect(cb.allow("d1")).To(BeTrue())
+		Expect(cb.status()).To(BeEmpty())
+	})
+
+	It("should open again after a failed probe", func() {
+		fail("d1", 3)
+		now = now.Add(time.Minute)
+		Expect(cb.allow("d1")).To(BeTrue())
+		cb.picked("d1")
+
+		fail("d1", 1)
+		Expect(cb.allow("d1")).To(BeFalse())
+		now = now.Add(time.Minute)
+		Expect(cb.allow("d1")).To(BeTrue())
+	})
+
+	It("should let new probes through if probes don't report back", func() {
+		fail("d1", 3)
+		now = now.Add(time.Minute)
+		Expect(cb.allow("d1")).To(BeTrue())
+		cb.picked("d1")
+		Expect(cb.allow("d1")).To(BeFalse())
+
+		now = now.Add(time.Minute)
+		Expect(cb.allow("d1")).To(BeTrue())
+	})
+
+	It("should exclude the destinations of open circuits from the pickup, letting probes through", func() {
+		var disabled *circuitBreakerT
+		Expect(disabled.openDestinationFilters()).To(BeEmpty())
+
+		fail("d1", 3)
+		fail("d2", 2)
+		Expect(cb.openDestinationFilters()).To(Equal([]jobsdb.ParameterFilterT{{Name: "destination_id", Value: "d1"}}))
+
+		now = now.Add(time.Minute)
+		Expect(cb.openDestinationFilters()).To(BeEmpty(), "open circuits due to become half-open are probed")
+		Expect(cb.allow("d1")).To(BeTrue())
+		Expect(cb.openDestinationFilters()).To(BeEmpty(), "half-open circuits with probes left are probed")
+		cb.picked("d1")
+		Expect(cb.openDestinationFilters()).To(Equal([]jobsdb.ParameterFilterT{{Name: "destination_id", Value: "d1"}}))
+
+		now = now.Add(time.Minute)
+		Expect(cb.openDestinationFilters()).To(BeEmpty(), "probes which don't report back are replaced")
+	})
+
+	It("should recognise jobs parked by the circuit breaker", func() {
+		Expect(isParkedByCircuitBreaker(&jobsdb.JobT{LastJobStatus: jobsdb.JobStatusT{
+			JobState:      jobsdb.Waiting.State,
+			ErrorResponse: []byte(`{"reason":"` + circuitOpenReason + `"}`),
+		}})).To(BeTrue())
+		Expect(isParkedByCircuitBreaker(&jobsdb.JobT{LastJobStatus: jobsdb.JobStatusT{
+			JobState:      jobsdb.Waiting.State,
+			ErrorResponse: []byte(`{"blocking_id":1}`),
+		}})).To(BeFalse())
+	})
+})
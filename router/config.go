@@ -21,3 +21,21 @@ func getRouterConfigInt(key, destType string, defaultValue int) int {
 		return config.GetInt("Router."+key, defaultValue)
 	}
 }
+
+func getRouterConfigString(key, destType, defaultValue string) string {
+	destOverrideFound := config.IsSet("Router." + destType + "." + key)
+	if destOverrideFound {
+		return config.GetString("Router."+destType+"."+key, defaultValue)
+	} else {
+		return config.GetString("Router."+key, defaultValue)
+	}
+}
+
+func getRouterConfigStringSlice(key, destType string, defaultValue []string) []string {
+	destOverrideFound := config.IsSet("Router." + destType + "." + key)
+	if destOverrideFound {
+		return config.GetStringSlice("Router."+destType+"."+key, defaultValue)
+	} else {
+		return config.GetStringSlice("Router."+key, defaultValue)
+	}
+}
@@ -0,0 +1,43 @@
+package router
+
+import (
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/rudderlabs/rudder-server/jobsdb"
+)
+
+// outsideDeliveryWindowReason is the reason of the jobs held while their destination is outside of its delivery windows
+const outsideDeliveryWindowReason = "destination is outside of its delivery windows"
+
+// isInDeliveryWindow returns whether jobs of the destination can be delivered at t, as per its delivery & blackout windows
+func (rt *HandleT) isInDeliveryWindow(destID string, t time.Time) bool {
+	rt.configSubscriberLock.RLock()
+	defer rt.configSubscriberLock.RUnlock()
+	batchDestination, ok := rt.destinationsMap[destID]
+	if !ok {
+		return true
+	}
+	return batchDestination.DeliveryWindows.IsOpen(t)
+}
+
+// heldDestinationFilters returns the filters excluding the jobs of the destinations outside of their delivery windows at t
+// from the pickup query, so that their held jobs don't take the place of the jobs of the other destinations
+func (rt *HandleT) heldDestinationFilters(t time.Time) []jobsdb.ParameterFilterT {
+	rt.configSubscriberLock.RLock()
+	defer rt.configSubscriberLock.RUnlock()
+	var filters []jobsdb.ParameterFilterT
+	for destID, batchDestination := range rt.destinationsMap {
+		if !batchDestination.DeliveryWindows.IsOpen(t) {
+			filters = append(filters, jobsdb.ParameterFilterT{Name: "destination_id", Value: destID})
+		}
+	}
+	return filters
+}
+
+// isHeldOutsideDeliveryWindow returns whether the job is already held because its destination is outside of its delivery windows
+func isHeldOutsideDeliveryWindow(job *jobsdb.JobT) bool {
+	return job.LastJobStatus.JobState == jobsdb.Waiting.State &&
+		gjson.GetBytes(job.LastJobStatus.ErrorResponse, "reason").String() == outsideDeliveryWindowReason
+}
@@ -13,6 +13,7 @@ type Factory struct {
 	BackendConfig    backendconfig.BackendConfig
 	RouterDB         jobsdb.MultiTenantJobsDB
 	ProcErrorDB      jobsdb.JobsDB
+	DLQDB            jobsdb.JobsDB // optional, aborted jobs are stored into it if set
 	TransientSources transientsource.Service
 	RsourcesService  rsources.JobService
 }
@@ -21,6 +22,7 @@ func (f *Factory) New(destinationDefinition backendconfig.DestinationDefinitionT
 	r := &HandleT{
 		Reporting:    f.Reporting,
 		MultitenantI: f.Multitenant,
+		dlqDB:        f.DLQDB,
 	}
 	r.Setup(f.BackendConfig, f.RouterDB, f.ProcErrorDB, destinationDefinition, f.TransientSources, f.RsourcesService)
 	return r
@@ -0,0 +1,288 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/rudderlabs/rudder-server/config"
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/processor/integrations"
+	"github.com/rudderlabs/rudder-server/router/types"
+	"github.com/rudderlabs/rudder-server/services/stats"
+)
+
+const (
+	jsonArrayBatchFormat = "json"
+	ndjsonBatchFormat    = "ndjson"
+)
+
+/*
+httpBatcherT aggregates the jobs of a destination, transformed by the processor into JSON requests, into a single request
+with a JSON array (or NDJSON) body of the bodies of the jobs. Jobs are batched by destination & request (endpoint, method,
+headers & query params), and a batch is sent once it has maxEvents jobs, reaches maxBytes or waits for timeout.
+
+All the jobs of a batch get the status of the batch request, unless the destination returns per item results:
+a JSON array at itemsPath of the response, with a result per job of the batch (in the order of the batch).
+A result is either a status code or an object with the status code at itemStatusKey. Results without a status code
+get missingItemStatus, or fail the whole batch if it isn't set, as the destination didn't say what became of them.
+*/
+type httpBatcherT struct {
+	destType          string
+	format            string
+	maxEvents         int
+	maxBytes          int
+	timeout           time.Duration
+	itemsPath         string
+	itemStatusKey     string
+	missingItemStatus int
+}
+
+// httpBatchT is a batch of jobs waiting to be sent in a single request
+type httpBatchT struct {
+	request     integrations.PostParametersT // the request of the first job of the batch
+	bodies      []json.RawMessage
+	size        int
+	jobMetadata []types.JobMetadataT
+	destination backendconfig.DestinationT
+}
+
+// batchItemResultT is the result of a job of a batch, as returned by the destination
+type batchItemResultT struct {
+	statusCode int
+	body       string
+}
+
+// newHTTPBatcher returns the http batcher of the router of destType, or nil if http batching is disabled for it
+func newHTTPBatcher(destType string) *httpBatcherT {
+	if !getRouterConfigBool("httpBatching.enabled", destType, false) {
+		return nil
+	}
+	b := &httpBatcherT{destType: destType}
+	keys := func(key string) []string {
+		return []string{"Router." + destType + ".httpBatching." + key, "Router.httpBatching." + key}
+	}
+	config.RegisterStringConfigVariable(jsonArrayBatchFormat, &b.format, false, keys("format")...)
+	config.RegisterIntConfigVariable(100, &b.maxEvents, true, 1, keys("maxEvents")...)
+	config.RegisterIntConfigVariable(1024*1024, &b.maxBytes, true, 1, keys("maxBytes")...)
+	config.RegisterDurationConfigVariable(100, &b.timeout, false, time.Millisecond, keys("timeout")...)
+	config.RegisterStringConfigVariable("", &b.itemsPath, true, keys("itemsPath")...)
+	config.RegisterStringConfigVariable("status", &b.itemStatusKey, true, keys("itemStatusKey")...)
+	config.RegisterIntConfigVariable(0, &b.missingItemStatus, true, 1, keys("missingItemStatus")...)
+	return b
+}
+
+// request returns the request of a job if it can be batched: a REST request with a JSON body
+func (b *httpBatcherT) request(payload json.RawMessage) (request integrations.PostParametersT, body json.RawMessage, ok bool) {
+	if b == nil {
+		return request, nil, false
+	}
+	if err := json.Unmarshal(payload, &request); err != nil || request.Type != "REST" || len(request.Files) > 0 {
+		return request, nil, false
+	}
+	for format, value := range request.Body {
+		if value, ok := value.(map[string]interface{}); ok && len(value) > 0 {
+			if format != "JSON" {
+				return request, nil, false
+			}
+			body, err := json.Marshal(value)
+			if err != nil {
+				return request, nil, false
+			}
+			return request, body, true
+		}
+	}
+	return request, nil, false
+}
+
+// key returns the key of the batch of a request of a destination: requests are batched together only if they
+// differ just by their body
+func (*httpBatcherT) key(destinationID string, request integrations.PostParametersT) string {
+	headers, _ := json.Marshal(request.Headers)
+	params, _ := json.Marshal(request.QueryParams)
+	return strings.Join([]string{destinationID, request.RequestMethod, request.URL, string(headers), string(params)}, "::")
+}
+
+// full returns whether body can't be added to the batch without exceeding its limits
+func (b *httpBatcherT) full(batch *httpBatchT, body json.RawMessage) bool {
+	return len(batch.bodies) >= b.maxEvents || (len(batch.bodies) > 0 && batch.size+len(body)+1 > b.maxBytes)
+}
+
+// destinationJob returns the destination job sending the batch in a single request
+func (b *httpBatcherT) destinationJob(batch *httpBatchT) types.DestinationJobT {
+	request := batch.request
+	bodyFormat := "JSON_ARRAY"
+	var body string
+	if b.format == ndjsonBatchFormat {
+		bodyFormat = "NDJSON"
+		lines := make([]string, len(batch.bodies))
+		for i := range batch.bodies {
+			lines[i] = string(batch.bodies[i])
+		}
+		body = strings.Join(lines, "\n") + "\n"
+		headers := make(map[string]interface{}, len(request.Headers)+1)
+		for key, value := range request.Headers {
+			if !strings.EqualFold(key, "Content-Type") {
+				headers[key] = value
+			}
+		}
+		headers["Content-Type"] = "application/x-ndjson"
+		request.Headers = headers
+	} else {
+		array, err := json.Marshal(batch.bodies)
+		if err != nil {
+			return b.failedDestinationJob(batch, fmt.Errorf("marshalling batch bodies: %w", err))
+		}
+		body = string(array)
+	}
+	request.Body = map[string]interface{}{
+		bodyFormat: map[string]interface{}{"batch": body},
+	}
+	message, err := json.Marshal(request)
+	if err != nil {
+		return b.failedDestinationJob(batch, fmt.Errorf("marshalling batch request: %w", err))
+	}
+
+	stats.NewTaggedStat("router_http_batch_num_jobs", stats.CountType, stats.Tags{
+		"destType":      b.destType,
+		"destinationId": batch.destination.ID,
+	}).Count(len(batch.bodies))
+
+	return types.DestinationJobT{
+		Message:          message,
+		JobMetadataArray: batch.jobMetadata,
+		Destination:      batch.destination,
+		Batched:          true,
+	}
+}
+
+// failedDestinationJob returns the destination job failing the jobs of a batch which couldn't be turned into a request
+func (*httpBatcherT) failedDestinationJob(batch *httpBatchT, err error) types.DestinationJobT {
+	pkgLogger.Errorf("[%s Router] :: Failed to build the http batch request of destination %s: %v", batch.destination.DestinationDefinition.Name, batch.destination.ID, err)
+	return types.DestinationJobT{
+		JobMetadataArray: batch.jobMetadata,
+		Destination:      batch.destination,
+		Batched:          true,
+		StatusCode:       http.StatusInternalServerError,
+		Error:            err.Error(),
+	}
+}
+
+// itemResults returns the results of the jobs of a batch of n jobs from the response of the destination,
+// or nil if the response has no per item results
+func (b *httpBatcherT) itemResults(respBody string, n int) []batchItemResultT {
+	if b == nil || b.itemsPath == "" {
+		return nil
+	}
+	items := gjson.Get(respBody, b.itemsPath)
+	if !items.IsArray() {
+		return nil
+	}
+	array := items.Array()
+	if len(array) != n {
+		return nil
+	}
+	results := make([]batchItemResultT, n)
+	for i, item := range array {
+		statusCode := item
+		if item.IsObject() {
+			statusCode = item.Get(b.itemStatusKey)
+		}
+		results[i] = batchItemResultT{statusCode: int(statusCode.Int()), body: item.Raw}
+		if results[i].statusCode != 0 {
+			continue
+		}
+		if b.missingItemStatus == 0 {
+			return b.failedItemResults(n, fmt.Sprintf("result %d of the batch response has no status: %s", i, item.Raw))
+		}
+		results[i].statusCode = b.missingItemStatus
+	}
+	return results
+}
+
+// failedItemResults returns the results failing all the jobs of a batch of n jobs
+func (*httpBatcherT) failedItemResults(n int, reason string) []batchItemResultT {
+	results := make([]batchItemResultT, n)
+	for i := range results {
+		results[i] = batchItemResultT{statusCode: http.StatusInternalServerError, body: reason}
+	}
+	return results
+}
+
+// addToHTTPBatch adds a job to its batch, sending the batch first if the job doesn't fit in it
+func (worker *workerT) addToHTTPBatch(request integrations.PostParametersT, body json.RawMessage, jobMetadata types.JobMetadataT, destination backendconfig.DestinationT) {
+	key := worker.rt.httpBatcher.key(destination.ID, request)
+	batch, ok := worker.httpBatches[key]
+	if ok && worker.rt.httpBatcher.full(batch, body) {
+		worker.flushHTTPBatches(key)
+		ok = false
+	}
+	if !ok {
+		batch = &httpBatchT{request: request, destination: destination}
+		worker.httpBatches[key] = batch
+	}
+	batch.bodies = append(batch.bodies, body)
+	batch.size += len(body) + 1
+	batch.jobMetadata = append(batch.jobMetadata, jobMetadata)
+	if len(batch.bodies) >= worker.rt.httpBatcher.maxEvents || batch.size >= worker.rt.httpBatcher.maxBytes {
+		worker.flushHTTPBatches(key)
+	}
+}
+
+// flushHTTPBatches sends the batches with the given keys, or all the batches if no key is given
+func (worker *workerT) flushHTTPBatches(keys ...string) {
+	if len(worker.httpBatches) == 0 {
+		return
+	}
+	if len(keys) == 0 {
+		for key := range worker.httpBatches {
+			keys = append(keys, key)
+		}
+	}
+	// processing the destination jobs clears the router jobs waiting for router transform along with their throttler counts,
+	// so they are set aside and sent on their own. The jobs of a destination are either batched or router transformed, so
+	// this doesn't change the order of the jobs of any destination.
+	routerJobs, jobCountsByDestAndUser, encounteredRouterTransform := worker.routerJobs, worker.jobCountsByDestAndUser, worker.encounteredRouterTransform
+	worker.routerJobs = make([]types.RouterJobT, 0)
+	worker.jobCountsByDestAndUser = make(map[string]*destJobCountsT)
+	worker.encounteredRouterTransform = false
+
+	for _, key := range keys {
+		batch, ok := worker.httpBatches[key]
+		if !ok {
+			continue
+		}
+		delete(worker.httpBatches, key)
+		for i := range batch.jobMetadata {
+			worker.recordCountsByDestAndUser(batch.destination.ID, batch.jobMetadata[i].UserID)
+		}
+		worker.destinationJobs = append(worker.destinationJobs, worker.rt.httpBatcher.destinationJob(batch))
+	}
+	worker.processDestinationJobs()
+
+	worker.routerJobs, worker.jobCountsByDestAndUser, worker.encounteredRouterTransform = routerJobs, jobCountsByDestAndUser, encounteredRouterTransform
+}
+
+// flushHTTPBatchesOf sends the batches of a destination, so that the jobs of the destination which can't be batched are
+// sent after the ones batched before them
+func (worker *workerT) flushHTTPBatchesOf(destinationID string) {
+	var keys []string
+	for key, batch := range worker.httpBatches {
+		if batch.destination.ID == destinationID {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) > 0 {
+		worker.flushHTTPBatches(keys...)
+	}
+}
+
+// isHTTPBatch returns whether the destination job is a batch of the http batcher
+func (worker *workerT) isHTTPBatch(destinationJob *types.DestinationJobT) bool {
+	return worker.rt.httpBatcher != nil && destinationJob.Batched && !worker.rt.enableBatching &&
+		destinationJob.JobMetadataArray[0].TransformAt != "router"
+}
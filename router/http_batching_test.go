@@ -0,0 +1,130 @@
+package router
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/processor/integrations"
+	"github.com/rudderlabs/rudder-server/router/types"
+	"github.com/rudderlabs/rudder-server/services/stats"
+)
+
+var _ = Describe("HTTP batcher", func() {
+	var b *httpBatcherT
+
+	BeforeEach(func() {
+		stats.Setup()
+		b = &httpBatcherT{
+			destType:      "WEBHOOK",
+			format:        jsonArrayBatchFormat,
+			maxEvents:     3,
+			maxBytes:      64,
+			itemsPath:     "results",
+			itemStatusKey: "status",
+		}
+	})
+
+	It("batches only REST requests with a JSON body", func() {
+		request, body, ok := b.request(json.RawMessage(`{"type": "REST", "endpoint": "https://webhook.example.com", "body": {"JSON": {"event": "Demo Track"}, "FORM": {}}}`))
+		Expect(ok).To(BeTrue())
+		Expect(request.URL).To(Equal("https://webhook.example.com"))
+		Expect(body).To(MatchJSON(`{"event": "Demo Track"}`))
+
+		_, _, ok = b.request(json.RawMessage(`{"type": "REST", "body": {"JSON": {}, "FORM": {"event": "Demo Track"}}}`))
+		Expect(ok).To(BeFalse())
+		_, _, ok = b.request(json.RawMessage(`{"type": "REST", "body": {"JSON": {"event": "Demo Track"}}, "files": {"file": "content"}}`))
+		Expect(ok).To(BeFalse())
+		_, _, ok = (*httpBatcherT)(nil).request(json.RawMessage(`{"type": "REST", "body": {"JSON": {"event": "Demo Track"}}}`))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("batches together requests differing only by their body", func() {
+		request := integrations.PostParametersT{RequestMethod: "POST", URL: "https://webhook.example.com", Headers: map[string]interface{}{"a": "1", "b": "2"}}
+		sameRequest := integrations.PostParametersT{RequestMethod: "POST", URL: "https://webhook.example.com", Headers: map[string]interface{}{"b": "2", "a": "1"}}
+		otherRequest := integrations.PostParametersT{RequestMethod: "POST", URL: "https://webhook.example.com", Headers: map[string]interface{}{"a": "2"}}
+		Expect(b.key("d1", request)).To(Equal(b.key("d1", sameRequest)))
+		Expect(b.key("d1", request)).NotTo(Equal(b.key("d1", otherRequest)))
+		Expect(b.key("d1", request)).NotTo(Equal(b.key("d2", request)))
+	})
+
+	It("limits batches by events and bytes", func() {
+		batch := &httpBatchT{}
+		Expect(b.full(batch, make(json.RawMessage, 100))).To(BeFalse(), "a job always fits in an empty batch")
+		batch.bodies, batch.size = []json.RawMessage{make(json.RawMessage, 30)}, 31
+		Expect(b.full(batch, make(json.RawMessage, 30))).To(BeFalse())
+		Expect(b.full(batch, make(json.RawMessage, 40))).To(BeTrue())
+		batch.bodies, batch.size = make([]json.RawMessage, 3), 3
+		Expect(b.full(batch, json.RawMessage(`{}`))).To(BeTrue())
+	})
+
+	DescribeTable("sends the batch in a single request",
+		func(format, bodyFormat, expectedBody, expectedContentType string) {
+			b.format = format
+			batch := &httpBatchT{
+				request: integrations.PostParametersT{
+					Type:          "REST",
+					URL:           "https://webhook.example.com",
+					RequestMethod: "POST",
+					Headers:       map[string]interface{}{"Content-Type": "application/json"},
+				},
+				bodies:      []json.RawMessage{json.RawMessage(`{"id":1}`), json.RawMessage(`{"id":2}`)},
+				jobMetadata: []types.JobMetadataT{{JobID: 1}, {JobID: 2}},
+				destination: backendconfig.DestinationT{ID: "d1"},
+			}
+			destinationJob := b.destinationJob(batch)
+			Expect(destinationJob.Batched).To(BeTrue())
+			Expect(destinationJob.Destination.ID).To(Equal("d1"))
+			Expect(destinationJob.JobMetadataArray).To(HaveLen(2))
+
+			var request integrations.PostParametersT
+			Expect(json.Unmarshal(destinationJob.Message, &request)).To(Succeed())
+			Expect(request.URL).To(Equal("https://webhook.example.com"))
+			Expect(request.Headers).To(HaveKeyWithValue("Content-Type", expectedContentType))
+			Expect(request.Body).To(HaveKeyWithValue(bodyFormat, map[string]interface{}{"batch": expectedBody}))
+		},
+		Entry("as a JSON array", jsonArrayBatchFormat, "JSON_ARRAY", `[{"id":1},{"id":2}]`, "application/json"),
+		Entry("as NDJSON", ndjsonBatchFormat, "NDJSON", "{\"id\":1}\n{\"id\":2}\n", "application/x-ndjson"),
+	)
+
+	DescribeTable("maps the per item results of the response to the jobs",
+		func(itemsPath, respBody string, expected []batchItemResultT) {
+			b.itemsPath = itemsPath
+			Expect(b.itemResults(respBody, 2)).To(Equal(expected))
+		},
+		Entry("without items path", "", `{"results": [200, 500]}`, nil),
+		Entry("without items", "results", `{"ok": true}`, nil),
+		Entry("with a result per job", "results", `{"results": [200, 500]}`,
+			[]batchItemResultT{{statusCode: 200, body: "200"}, {statusCode: 500, body: "500"}}),
+		Entry("with result objects", "results", `{"results": [{"status": 400, "error": "invalid"}, {"status": 200}]}`,
+			[]batchItemResultT{{statusCode: 400, body: `{"status": 400, "error": "invalid"}`}, {statusCode: 200, body: `{"status": 200}`}}),
+		Entry("with a result without status", "results", `{"results": [{"status": 200}, {}]}`,
+			[]batchItemResultT{
+				{statusCode: 500, body: "result 1 of the batch response has no status: {}"},
+				{statusCode: 500, body: "result 1 of the batch response has no status: {}"},
+			}),
+		Entry("with a result count not matching the jobs", "results", `{"results": [200]}`, nil),
+	)
+
+	It("gives results without status the configured status", func() {
+		b.missingItemStatus = 200
+		Expect(b.itemResults(`{"results": [{"status": 400}, {}]}`, 2)).To(Equal(
+			[]batchItemResultT{{statusCode: 400, body: `{"status": 400}`}, {statusCode: 200, body: "{}"}},
+		))
+	})
+
+	It("fails the batch if its request can't be built", func() {
+		batch := &httpBatchT{
+			request:     integrations.PostParametersT{Type: "REST", URL: "https://webhook.example.com", RequestMethod: "POST"},
+			bodies:      []json.RawMessage{json.RawMessage(`{"id":1}`), json.RawMessage(`{"id":`)},
+			jobMetadata: []types.JobMetadataT{{JobID: 1}, {JobID: 2}},
+			destination: backendconfig.DestinationT{ID: "d1"},
+		}
+		destinationJob := b.destinationJob(batch)
+		Expect(destinationJob.StatusCode).To(Equal(500))
+		Expect(destinationJob.Error).To(ContainSubstring("marshalling batch bodies"))
+		Expect(destinationJob.JobMetadataArray).To(HaveLen(2))
+	})
+})
@@ -3,6 +3,7 @@
 package router
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/json"
@@ -13,6 +14,7 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rudderlabs/rudder-server/processor/integrations"
@@ -30,8 +32,14 @@ func init() {
 
 // NetHandleT is the wrapper holding private variables
 type NetHandleT struct {
-	httpClient sysUtils.HTTPClientI
-	logger     logger.LoggerI
+	httpClient       sysUtils.HTTPClientI
+	logger           logger.LoggerI
+	transport        *http.Transport
+	netClientTimeout time.Duration
+	signing          requestSignerT
+
+	destinationClientsMu sync.Mutex
+	destinationClients   map[string]destinationClientT // destinationID -> client with the destination's client certificate
 }
 
 // Network interface
@@ -66,7 +74,6 @@ func (network *NetHandleT) SendPost(ctx context.Context, structData integrations
 			ResponseBody: []byte("200: outgoing disabled"),
 		}
 	}
-	client := network.httpClient
 	postInfo := structData
 	isRest := postInfo.Type == "REST"
 
@@ -111,6 +118,16 @@ func (network *NetHandleT) SendPost(ctx context.Context, structData integrations
 					}
 				}
 				payload = strings.NewReader(jsonListStr)
+			case "NDJSON":
+				// support for newline delimited JSON, used by the batches of the router
+				ndjsonStr, ok := bodyValue["batch"].(string)
+				if !ok {
+					return &utils.SendPostResponse{
+						StatusCode:   400,
+						ResponseBody: []byte("400 Unable to parse ndjson batch. Unexpected batch payload"),
+					}
+				}
+				payload = strings.NewReader(ndjsonStr)
 			case "XML":
 				strValue, ok := bodyValue["payload"].(string)
 				if !ok {
@@ -131,6 +148,14 @@ func (network *NetHandleT) SendPost(ctx context.Context, structData integrations
 			}
 		}
 
+		// the body is read upfront for signing it
+		signer := network.signer(ctx)
+		var body []byte
+		if signer.enabled() && payload != nil {
+			body, _ = io.ReadAll(payload)
+			payload = bytes.NewReader(body)
+		}
+
 		req, err := http.NewRequestWithContext(ctx, requestMethod, postInfo.URL, payload)
 		if err != nil {
 			network.logger.Error(fmt.Sprintf(`400 Unable to construct "%s" request for URL : "%s"`, requestMethod, postInfo.URL))
@@ -159,7 +184,18 @@ func (network *NetHandleT) SendPost(ctx context.Context, structData integrations
 		}
 
 		req.Header.Add("User-Agent", "RudderLabs")
+		if signer.enabled() {
+			signer.sign(req.Header, body, time.Now())
+		}
 
+		client, err := network.client(ctx)
+		if err != nil {
+			network.logger.Error(err)
+			return &utils.SendPostResponse{
+				StatusCode:   400,
+				ResponseBody: []byte(fmt.Sprintf(`400 Unable to load client certificate for URL : "%s"`, postInfo.URL)),
+			}
+		}
 		resp, err := client.Do(req)
 		if err != nil {
 			return &utils.SendPostResponse{
@@ -209,6 +245,7 @@ func (network *NetHandleT) SendPost(ctx context.Context, structData integrations
 			StatusCode:          resp.StatusCode,
 			ResponseBody:        respBody,
 			ResponseContentType: contentTypeHeader,
+			RetryAfter:          retryAfter(resp.Header, time.Now()),
 		}
 	}
 
@@ -247,10 +284,22 @@ func (network *NetHandleT) Setup(destID string, netClientTimeout time.Duration)
 		defaultTransportCopy.TLSClientConfig = &tlsClientConfig
 		network.logger.Info(destID, defaultTransportCopy.TLSClientConfig.NextProtos)
 	}
+	tlsClientConfig, err := clientTLSConfig(defaultTransportCopy.TLSClientConfig, destID)
+	if err != nil {
+		panic(fmt.Errorf("setting up tls for %s: %w", destID, err))
+	}
+	defaultTransportCopy.TLSClientConfig = tlsClientConfig
+	network.signing = requestSignerT{
+		secret:          getRouterConfigString("signing.secret", destID, ""),
+		signatureHeader: getRouterConfigString("signing.signatureHeader", destID, defaultSignatureHeader),
+		timestampHeader: getRouterConfigString("signing.timestampHeader", destID, defaultTimestampHeader),
+	}
 	defaultTransportCopy.MaxIdleConns = getRouterConfigInt("httpMaxIdleConns", destID, 100)
 	defaultTransportCopy.MaxIdleConnsPerHost = getRouterConfigInt("httpMaxIdleConnsPerHost", destID, 100)
 	network.logger.Info(destID, ":   defaultTransportCopy.MaxIdleConns: ", defaultTransportCopy.MaxIdleConns)
 	network.logger.Info("defaultTransportCopy.MaxIdleConnsPerHost: ", defaultTransportCopy.MaxIdleConnsPerHost)
 	network.logger.Info("netClientTimeout: ", netClientTimeout)
+	network.transport = &defaultTransportCopy
+	network.netClientTimeout = netClientTimeout
 	network.httpClient = &http.Client{Transport: &defaultTransportCopy, Timeout: netClientTimeout}
 }
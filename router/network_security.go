@@ -0,0 +1,165 @@
+package router
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/utils/sysUtils"
+)
+
+const (
+	defaultSignatureHeader = "X-Rudder-Signature"
+	defaultTimestampHeader = "X-Rudder-Timestamp"
+)
+
+type destinationCtxKey struct{}
+
+// withDestination returns a context carrying the destination a request is sent to, so that the network handler
+// can sign the request and authenticate with the client certificate of the destination
+func withDestination(ctx context.Context, destination backendconfig.DestinationT) context.Context {
+	return context.WithValue(ctx, destinationCtxKey{}, destination)
+}
+
+func destinationFromContext(ctx context.Context) (backendconfig.DestinationT, bool) {
+	destination, ok := ctx.Value(destinationCtxKey{}).(backendconfig.DestinationT)
+	return destination, ok
+}
+
+/*
+requestSignerT signs the requests sent to a destination with an HMAC-SHA256 signature, so that the destination can verify
+that they were sent by us and haven't been tampered with or replayed.
+
+The timestamp header holds the unix time of the request, in seconds, and the signature header holds
+"sha256=" followed by the hex encoded HMAC-SHA256 of "<timestamp>.<body>" with the signing secret as key.
+*/
+type requestSignerT struct {
+	secret          string
+	signatureHeader string
+	timestampHeader string
+}
+
+// signer returns the signer of the requests of the destination: the Router[.<DEST>].signing.* settings,
+// overridden by the signingSecret, signatureHeader & timestampHeader settings of the destination
+func (network *NetHandleT) signer(ctx context.Context) requestSignerT {
+	signer := network.signing
+	destination, ok := destinationFromContext(ctx)
+	if !ok {
+		return signer
+	}
+	if secret, ok := destination.Config["signingSecret"].(string); ok && secret != "" {
+		signer.secret = secret
+	}
+	if header, ok := destination.Config["signatureHeader"].(string); ok && header != "" {
+		signer.signatureHeader = header
+	}
+	if header, ok := destination.Config["timestampHeader"].(string); ok && header != "" {
+		signer.timestampHeader = header
+	}
+	return signer
+}
+
+func (s requestSignerT) enabled() bool {
+	return s.secret != ""
+}
+
+// sign sets the timestamp & signature headers of a request with body, sent at now
+func (s requestSignerT) sign(header http.Header, body []byte, now time.Time) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(s.secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	header.Set(s.timestampHeader, timestamp)
+	header.Set(s.signatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+}
+
+// destinationClientT is the http client of a destination authenticating with its own client certificate
+type destinationClientT struct {
+	certificate, key string
+	client           sysUtils.HTTPClientI
+}
+
+// client returns the http client for the requests of the destination: a client authenticating with the clientCertificate
+// & clientKey (PEM encoded) of the destination if it has them, or the client of the network handler otherwise
+func (network *NetHandleT) client(ctx context.Context) (sysUtils.HTTPClientI, error) {
+	destination, ok := destinationFromContext(ctx)
+	if !ok {
+		return network.httpClient, nil
+	}
+	certificate, _ := destination.Config["clientCertificate"].(string)
+	key, _ := destination.Config["clientKey"].(string)
+	if certificate == "" || key == "" {
+		return network.httpClient, nil
+	}
+
+	network.destinationClientsMu.Lock()
+	defer network.destinationClientsMu.Unlock()
+	if client, ok := network.destinationClients[destination.ID]; ok && client.certificate == certificate && client.key == key {
+		return client.client, nil
+	}
+	keyPair, err := tls.X509KeyPair([]byte(certificate), []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("loading client certificate of destination %s: %w", destination.ID, err)
+	}
+	transport := network.transport
+	if transport == nil {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.Certificates = []tls.Certificate{keyPair}
+	client := &http.Client{Transport: transport, Timeout: network.netClientTimeout}
+
+	if network.destinationClients == nil {
+		network.destinationClients = make(map[string]destinationClientT)
+	}
+	network.destinationClients[destination.ID] = destinationClientT{certificate: certificate, key: key, client: client}
+	return client, nil
+}
+
+// clientTLSConfig returns a copy of tlsConfig with the client certificate of the Router[.<DEST>].tls.clientCertificateFile
+// & clientKeyFile settings and the certificate authorities of the tls.caCertificateFile setting, or tlsConfig itself if none are set
+func clientTLSConfig(tlsConfig *tls.Config, destType string) (*tls.Config, error) {
+	certificateFile := getRouterConfigString("tls.clientCertificateFile", destType, "")
+	keyFile := getRouterConfigString("tls.clientKeyFile", destType, "")
+	caCertificateFile := getRouterConfigString("tls.caCertificateFile", destType, "")
+	if certificateFile == "" && keyFile == "" && caCertificateFile == "" {
+		return tlsConfig, nil
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	} else {
+		tlsConfig = tlsConfig.Clone()
+	}
+	if certificateFile != "" || keyFile != "" {
+		keyPair, err := tls.LoadX509KeyPair(certificateFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{keyPair}
+	}
+	if caCertificateFile != "" {
+		caCertificate, err := os.ReadFile(caCertificateFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading ca certificate: %w", err)
+		}
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(caCertificate) {
+			return nil, fmt.Errorf("no certificate found in ca certificate file %s", caCertificateFile)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,174 @@
+package router
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/processor/integrations"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+)
+
+var _ = Describe("Network security", func() {
+	var (
+		network *NetHandleT
+		params  integrations.PostParametersT
+	)
+
+	BeforeEach(func() {
+		network = &NetHandleT{}
+		network.logger = logger.NewLogger().Child("network")
+		params = integrations.PostParametersT{
+			Type:          "REST",
+			RequestMethod: "POST",
+			Body: map[string]interface{}{
+				"JSON": map[string]interface{}{"event": "Demo Track"},
+			},
+		}
+	})
+
+	Context("Request signing", func() {
+		type request struct {
+			header http.Header
+			body   string
+		}
+		var requests chan request
+
+		BeforeEach(func() {
+			requests = make(chan request, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests <- request{header: r.Header, body: string(body)}
+			}))
+			DeferCleanup(server.Close)
+			network.httpClient = server.Client()
+			params.URL = server.URL
+		})
+
+		expectSigned := func(secret, signatureHeader, timestampHeader string) {
+			var r request
+			Eventually(requests).Should(Receive(&r))
+			Expect(r.body).To(Equal(`{"event":"Demo Track"}`))
+			timestamp := r.header.Get(timestampHeader)
+			Expect(timestamp).NotTo(BeEmpty())
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write([]byte(timestamp + "." + r.body))
+			Expect(r.header.Get(signatureHeader)).To(Equal("sha256=" + hex.EncodeToString(mac.Sum(nil))))
+		}
+
+		It("doesn't sign requests without a signing secret", func() {
+			network.signing = requestSignerT{signatureHeader: defaultSignatureHeader, timestampHeader: defaultTimestampHeader}
+			Expect(network.SendPost(context.Background(), params).StatusCode).To(Equal(http.StatusOK))
+			var r request
+			Eventually(requests).Should(Receive(&r))
+			Expect(r.header.Get(defaultSignatureHeader)).To(BeEmpty())
+			Expect(r.header.Get(defaultTimestampHeader)).To(BeEmpty())
+		})
+
+		It("signs requests with the signing secret of the router", func() {
+			network.signing = requestSignerT{secret: "router-secret", signatureHeader: defaultSignatureHeader, timestampHeader: defaultTimestampHeader}
+			Expect(network.SendPost(context.Background(), params).StatusCode).To(Equal(http.StatusOK))
+			expectSigned("router-secret", defaultSignatureHeader, defaultTimestampHeader)
+		})
+
+		It("signs requests with the signing settings of the destination", func() {
+			network.signing = requestSignerT{secret: "router-secret", signatureHeader: defaultSignatureHeader, timestampHeader: defaultTimestampHeader}
+			ctx := withDestination(context.Background(), backendconfig.DestinationT{
+				ID: "d1",
+				Config: map[string]interface{}{
+					"signingSecret":   "destination-secret",
+					"signatureHeader": "X-Hub-Signature",
+					"timestampHeader": "X-Hub-Timestamp",
+				},
+			})
+			Expect(network.SendPost(ctx, params).StatusCode).To(Equal(http.StatusOK))
+			expectSigned("destination-secret", "X-Hub-Signature", "X-Hub-Timestamp")
+		})
+	})
+
+	Context("Client certificates", func() {
+		var (
+			server                 *httptest.Server
+			certificate, clientKey string
+		)
+
+		BeforeEach(func() {
+			certificate, clientKey = selfSignedCertificate()
+			clientCAs := x509.NewCertPool()
+			Expect(clientCAs.AppendCertsFromPEM([]byte(certificate))).To(BeTrue())
+
+			server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+			}))
+			server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+			server.StartTLS()
+			DeferCleanup(server.Close)
+
+			network.transport = server.Client().Transport.(*http.Transport)
+			network.httpClient = server.Client()
+			params.URL = server.URL
+		})
+
+		It("fails the handshake without a client certificate", func() {
+			Expect(network.SendPost(context.Background(), params).StatusCode).To(Equal(http.StatusGatewayTimeout))
+		})
+
+		It("authenticates with the client certificate of the destination", func() {
+			ctx := withDestination(context.Background(), backendconfig.DestinationT{
+				ID:     "d1",
+				Config: map[string]interface{}{"clientCertificate": certificate, "clientKey": clientKey},
+			})
+			resp := network.SendPost(ctx, params)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(string(resp.ResponseBody)).To(Equal("rudder-router"))
+			Expect(network.destinationClients).To(HaveKey("d1"))
+		})
+
+		It("aborts requests of destinations with an invalid client certificate", func() {
+			ctx := withDestination(context.Background(), backendconfig.DestinationT{
+				ID:     "d1",
+				Config: map[string]interface{}{"clientCertificate": "invalid", "clientKey": "invalid"},
+			})
+			Expect(network.SendPost(ctx, params).StatusCode).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
+
+// selfSignedCertificate returns a PEM encoded self-signed client certificate and its key
+func selfSignedCertificate() (certificate, key string) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "rudder-router"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	Expect(err).NotTo(HaveOccurred())
+	keyDer, err := x509.MarshalECPrivateKey(privateKey)
+	Expect(err).NotTo(HaveOccurred())
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
@@ -181,4 +181,5 @@ var _ = Describe("Network", func() {
 			Entry("'invalidcontenttype' should result in altered body", "invalidcontenttype", true),
 		)
 	})
+
 })
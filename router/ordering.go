@@ -0,0 +1,127 @@
+package router
+
+import (
+	"strconv"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	routerutils "github.com/rudderlabs/rudder-server/router/utils"
+	"github.com/rudderlabs/rudder-server/services/stats"
+)
+
+const (
+	userIDOrderingKey = routerutils.UserIDOrderingKey
+	noneOrderingKey   = routerutils.NoneOrderingKey
+)
+
+/*
+orderingT is the ordering of the jobs of a destination (see #JobOrder).
+
+The ordering key is either userId, none, or a json path over the event (e.g. "context.traits.accountId"), jobs with the same
+value of the key being delivered in order. The path refers to the event as sent by the source, not to the payload transformed
+for the destination: the processor evaluates it and stores its value in the ordering_value parameter of the router job
+(see routerutils.OrderingKeyValue). Jobs without a value for the path, e.g. jobs created before the ordering key got
+configured, are ordered per userId.
+Jobs of the unordered event types are never blocked by, nor block, other jobs.
+
+The ordering of a destination type is configured with Router.<DEST_TYPE>.orderingKey & Router.<DEST_TYPE>.unorderedEventTypes,
+and can be overridden per destination with the orderingKey & unorderedEventTypes keys of its config.
+*/
+type orderingT struct {
+	key                 string
+	unorderedEventTypes map[string]struct{}
+}
+
+// setupOrdering reads the default ordering of the jobs of the destination type
+func (rt *HandleT) setupOrdering() {
+	rt.defaultOrdering = orderingT{
+		key:                 getRouterConfigString("orderingKey", rt.destName, userIDOrderingKey),
+		unorderedEventTypes: toSet(getRouterConfigStringSlice("unorderedEventTypes", rt.destName, nil)),
+	}
+}
+
+// ordering returns the ordering of the jobs of a destination
+func (rt *HandleT) ordering(destinationID string) orderingT {
+	rt.configSubscriberLock.RLock()
+	defer rt.configSubscriberLock.RUnlock()
+	ordering := rt.defaultOrdering
+	batchDestination, ok := rt.destinationsMap[destinationID]
+	if !ok {
+		return ordering
+	}
+	if key, ok := batchDestination.Destination.Config["orderingKey"].(string); ok && key != "" {
+		ordering.key = key
+	}
+	if eventTypes, ok := batchDestination.Destination.Config["unorderedEventTypes"].([]interface{}); ok {
+		ordering.unorderedEventTypes = map[string]struct{}{}
+		for _, eventType := range eventTypes {
+			if eventType, ok := eventType.(string); ok {
+				ordering.unorderedEventTypes[eventType] = struct{}{}
+			}
+		}
+	}
+	return ordering
+}
+
+// jobOrderKey returns the key used for guaranteeing the order of a job (see #JobOrder) and the key its worker is picked by.
+// An empty order key means that the job is not ordered.
+// If priority lanes are enabled, jobs of different priorities are picked up in separate lanes, thus their order is only
+// guaranteed within the same priority.
+func (rt *HandleT) jobOrderKey(job *jobsdb.JobT) (orderKey, partitionKey string) {
+	ordering := rt.ordering(destinationID(job))
+	if ordering.key == noneOrderingKey {
+		return "", ""
+	}
+	if _, ok := ordering.unorderedEventTypes[gjson.GetBytes(job.Parameters, "event_type").String()]; ok {
+		return "", ""
+	}
+
+	partitionKey = job.UserID
+	if ordering.key != userIDOrderingKey {
+		params := gjson.GetManyBytes(job.Parameters, "ordering_key", "ordering_value")
+		if params[0].String() == ordering.key && params[1].String() != "" {
+			partitionKey = ordering.key + "::" + params[1].String()
+		} else {
+			rt.orderingKeyFallback(job, ordering.key)
+		}
+	}
+	if !rt.enablePriorityLanes || job.Priority == jobsdb.NormalPriority {
+		return partitionKey, partitionKey
+	}
+	return partitionKey + "::" + strconv.Itoa(job.Priority), partitionKey
+}
+
+// orderingKeyFallback reports a job ordered per userId, since it has no value for the ordering key of its destination
+func (rt *HandleT) orderingKeyFallback(job *jobsdb.JobT, orderingKey string) {
+	destID := destinationID(job)
+	// warn once per destination & key, instead of for every job picked up
+	if _, logged := rt.orderingKeyFallbacks.LoadOrStore(destID+"::"+orderingKey, struct{}{}); !logged {
+		rt.logger.Warnf("Ordering jobs of destination %s per userId, since some of them have no value for the ordering key %q (first: job %d)", destID, orderingKey, job.JobID)
+	} else {
+		rt.logger.Debugf("Ordering job %d of destination %s per userId, since it has no value for the ordering key %q", job.JobID, destID, orderingKey)
+	}
+	stats.NewTaggedStat("router_ordering_key_fallback_jobs", stats.CountType, stats.Tags{
+		"destType":      rt.destName,
+		"destinationId": destID,
+		"orderingKey":   orderingKey,
+	}).Increment()
+}
+
+// orderingBlocked reports a job waiting for an earlier job with the same order key
+func (rt *HandleT) orderingBlocked(job *jobsdb.JobT) {
+	destID := destinationID(job)
+	stats.NewTaggedStat("router_ordering_blocked_jobs", stats.CountType, stats.Tags{
+		"destType":      rt.destName,
+		"destinationId": destID,
+		"orderingKey":   rt.ordering(destID).key,
+	}).Increment()
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,175 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	routerutils "github.com/rudderlabs/rudder-server/router/utils"
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+)
+
+type noopThrottler struct{}
+
+func (*noopThrottler) CheckLimitReachedAndInc(string, string, time.Time) (bool, string) {
+	return false, ""
+}
+func (*noopThrottler) Dec(string, string, int64, time.Time, string) {}
+func (*noopThrottler) IsEnabled() bool                              { return false }
+func (*noopThrottler) IsUserLevelEnabled() bool                     { return false }
+func (*noopThrottler) IsDestLevelEnabled() bool                     { return false }
+
+var _ = Describe("Ordering", func() {
+	var rt *HandleT
+
+	BeforeEach(func() {
+		stats.Setup()
+		rt = &HandleT{
+			destName:        "WEBHOOK",
+			defaultOrdering: orderingT{key: userIDOrderingKey},
+			destinationsMap: map[string]*routerutils.BatchDestinationT{},
+			logger:          logger.NOP{},
+		}
+	})
+
+	job := func(userID, destinationID, eventType, payload string) *jobsdb.JobT {
+		return &jobsdb.JobT{
+			JobID:        1,
+			UserID:       userID,
+			Priority:     jobsdb.NormalPriority,
+			EventPayload: json.RawMessage(payload),
+			Parameters:   json.RawMessage(`{"destination_id":"` + destinationID + `","event_type":"` + eventType + `"}`),
+		}
+	}
+
+	// orderedJob is a job with the value of an ordering key, as stored by the processor
+	orderedJob := func(userID, destinationID, eventType, orderingKey, orderingValue string) *jobsdb.JobT {
+		j := job(userID, destinationID, eventType, `{}`)
+		j.Parameters = json.RawMessage(`{"destination_id":"` + destinationID + `","event_type":"` + eventType + `","ordering_key":"` + orderingKey + `","ordering_value":"` + orderingValue + `"}`)
+		return j
+	}
+
+	destination := func(id string, config map[string]interface{}) {
+		rt.destinationsMap[id] = &routerutils.BatchDestinationT{Destination: backendconfig.DestinationT{ID: id, Config: config}}
+	}
+
+	It("should order jobs per userId by default", func() {
+		orderKey, partitionKey := rt.jobOrderKey(job("u1", "d1", "track", `{"groupId":"g1"}`))
+		Expect(orderKey).To(Equal("u1"))
+		Expect(partitionKey).To(Equal("u1"))
+
+		priorityJob := job("u1", "d1", "track", `{}`)
+		priorityJob.Priority = 10
+		orderKey, _ = rt.jobOrderKey(priorityJob)
+		Expect(orderKey).To(Equal("u1"), "jobs of all priorities are ordered together without priority lanes")
+
+		rt.enablePriorityLanes = true
+		orderKey, partitionKey = rt.jobOrderKey(priorityJob)
+		Expect(orderKey).To(Equal("u1::10"))
+		Expect(partitionKey).To(Equal("u1"))
+	})
+
+	It("should sort jobs by priority only if priority lanes are enabled", func() {
+		jobs := func() []*jobsdb.JobT {
+			var jobs []*jobsdb.JobT
+			for i, priority := range []int{jobsdb.LowPriority, jobsdb.HighPriority, jobsdb.NormalPriority, jobsdb.HighPriority} {
+				j := job("u1", "d1", "track", `{}`)
+				j.JobID = int64(4 - i)
+				j.Priority = priority
+				jobs = append(jobs, j)
+			}
+			return jobs
+		}
+		jobIDs := func(jobs []*jobsdb.JobT) []int64 {
+			var ids []int64
+			for _, j := range jobs {
+				ids = append(ids, j.JobID)
+			}
+			return ids
+		}
+
+		sorted := jobs()
+		rt.sortJobs(sorted)
+		Expect(jobIDs(sorted)).To(Equal([]int64{1, 2, 3, 4}))
+
+		rt.enablePriorityLanes = true
+		sorted = jobs()
+		rt.sortJobs(sorted)
+		Expect(jobIDs(sorted)).To(Equal([]int64{1, 3, 2, 4}))
+	})
+
+	It("should order jobs per the value of the ordering key of the destination type", func() {
+		rt.defaultOrdering.key = "context.traits.accountId"
+		orderKey, partitionKey := rt.jobOrderKey(orderedJob("u1", "d1", "track", "context.traits.accountId", "a1"))
+		Expect(orderKey).To(Equal("context.traits.accountId::a1"))
+		Expect(partitionKey).To(Equal(orderKey))
+
+		otherUserKey, _ := rt.jobOrderKey(orderedJob("u2", "d1", "track", "context.traits.accountId", "a1"))
+		Expect(otherUserKey).To(Equal(orderKey))
+
+		By("ignoring the transformed payload")
+		orderKey, _ = rt.jobOrderKey(job("u1", "d1", "track", `{"context":{"traits":{"accountId":"a1"}}}`))
+		Expect(orderKey).To(Equal("u1"))
+
+		By("falling back to userId when the job has no value for the key")
+		orderKey, _ = rt.jobOrderKey(orderedJob("u1", "d1", "track", "context.traits.accountId", ""))
+		Expect(orderKey).To(Equal("u1"))
+
+		By("falling back to userId when the job has the value of another key")
+		orderKey, _ = rt.jobOrderKey(orderedJob("u1", "d1", "track", "groupId", "g1"))
+		Expect(orderKey).To(Equal("u1"))
+	})
+
+	It("should let destinations override the ordering of the destination type", func() {
+		destination("d1", map[string]interface{}{"orderingKey": "groupId", "unorderedEventTypes": []interface{}{"page"}})
+		destination("d2", map[string]interface{}{"orderingKey": noneOrderingKey})
+
+		orderKey, _ := rt.jobOrderKey(orderedJob("u1", "d1", "group", "groupId", "g1"))
+		Expect(orderKey).To(Equal("groupId::g1"))
+		orderKey, _ = rt.jobOrderKey(job("u1", "d1", "page", `{"groupId":"g1"}`))
+		Expect(orderKey).To(BeEmpty())
+		orderKey, _ = rt.jobOrderKey(job("u1", "d2", "track", `{"groupId":"g1"}`))
+		Expect(orderKey).To(BeEmpty())
+		orderKey, _ = rt.jobOrderKey(job("u1", "d3", "track", `{"groupId":"g1"}`))
+		Expect(orderKey).To(Equal("u1"))
+	})
+
+	It("should not order jobs of unordered event types", func() {
+		rt.defaultOrdering.unorderedEventTypes = toSet([]string{"identify"})
+		orderKey, partitionKey := rt.jobOrderKey(job("u1", "d1", "identify", `{}`))
+		Expect(orderKey).To(BeEmpty())
+		Expect(partitionKey).To(BeEmpty())
+	})
+
+	It("should not block unordered jobs behind a failed job of the same user", func() {
+		rt.guaranteeUserEventOrder = true
+		rt.noOfWorkers = 4
+		rt.throttler = &noopThrottler{}
+		rt.throttledUserMap = map[string]struct{}{}
+		rt.throttledDestinationMap = map[string]struct{}{}
+		rt.backgroundCtx = context.Background()
+		for i := 0; i < rt.noOfWorkers; i++ {
+			rt.workers = append(rt.workers, &workerT{
+				rt:               rt,
+				failedJobIDMap:   map[string]int64{"u1": 1},
+				abortedUserIDMap: map[string]map[int64]struct{}{},
+				retryForJobMap:   map[int64]time.Time{},
+			})
+		}
+		destination("d1", map[string]interface{}{"unorderedEventTypes": []interface{}{"page"}})
+
+		blocked := job("u1", "d1", "track", `{}`)
+		blocked.JobID = 2
+		Expect(rt.findWorker(blocked, time.Now())).To(BeNil())
+
+		unordered := job("u1", "d1", "page", `{}`)
+		unordered.JobID = 3
+		Expect(rt.findWorker(unordered, time.Now())).NotTo(BeNil())
+	})
+})
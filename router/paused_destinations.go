@@ -0,0 +1,135 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/services/stats"
+)
+
+/*
+pausedDestinationsT keeps the destinations which asked the router to hold off sending them requests,
+through the Retry-After or rate limit headers of their responses. Jobs of paused destinations are not picked up until the
+pause is over, which is capped at Router.maxRetryAfter.
+*/
+type pausedDestinationsT struct {
+	destType string
+	now      func() time.Time
+
+	mu     sync.Mutex
+	paused map[string]time.Time // destinationID -> paused until
+}
+
+func newPausedDestinations(destType string) *pausedDestinationsT {
+	return &pausedDestinationsT{
+		destType: destType,
+		now:      time.Now,
+		paused:   make(map[string]time.Time),
+	}
+}
+
+// pause holds off the jobs of the destination for retryAfter, at most maxRetryAfter
+func (p *pausedDestinationsT) pause(destID string, retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		return
+	}
+	if retryAfter > maxRetryAfter {
+		retryAfter = maxRetryAfter
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	until := p.now().Add(retryAfter)
+	if until.After(p.paused[destID]) {
+		p.paused[destID] = until
+		stats.NewTaggedStat("router_destination_paused", stats.CountType, stats.Tags{
+			"destType":      p.destType,
+			"destinationId": destID,
+		}).Increment()
+	}
+}
+
+// isPaused returns whether the jobs of the destination are held off
+func (p *pausedDestinationsT) isPaused(destID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	until, ok := p.paused[destID]
+	if !ok {
+		return false
+	}
+	if !p.now().Before(until) {
+		delete(p.paused, destID)
+		return false
+	}
+	return true
+}
+
+// status returns until when the paused destinations are held off, by destination id
+func (p *pausedDestinationsT) status() map[string]time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status := make(map[string]time.Time)
+	for destID, until := range p.paused {
+		if p.now().Before(until) {
+			status[destID] = until
+		}
+	}
+	return status
+}
+
+// nextAttemptAfter returns how long to wait before retrying a job which failed in its attempt: the backoff of the attempt,
+// or how long the destination asked to wait if longer, capped at Router.maxRetryAfter
+func nextAttemptAfter(attempt int, retryAfter time.Duration) time.Duration {
+	backoff := durationBeforeNextAttempt(attempt)
+	if retryAfter > maxRetryAfter {
+		retryAfter = maxRetryAfter
+	}
+	if retryAfter > backoff {
+		return retryAfter
+	}
+	return backoff
+}
+
+// rateLimitHeaders are the pairs of remaining requests & reset headers destinations use to announce that their rate limit is exhausted
+var rateLimitHeaders = [][2]string{
+	{"X-RateLimit-Remaining", "X-RateLimit-Reset"},
+	{"RateLimit-Remaining", "RateLimit-Reset"},
+	{"X-Rate-Limit-Remaining", "X-Rate-Limit-Reset"},
+}
+
+// retryAfter returns how long the destination asked to wait before sending more requests, from the Retry-After header
+// (in seconds or as an http date) or from the reset header of an exhausted rate limit (in seconds or as a unix timestamp)
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	if value := strings.TrimSpace(header.Get("Retry-After")); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			if seconds < 0 {
+				return 0
+			}
+			return time.Duration(seconds) * time.Second
+		}
+		if date, err := http.ParseTime(value); err == nil && date.After(now) {
+			return date.Sub(now)
+		}
+		return 0
+	}
+	for _, headers := range rateLimitHeaders {
+		if strings.TrimSpace(header.Get(headers[0])) != "0" {
+			continue
+		}
+		reset, err := strconv.ParseInt(strings.TrimSpace(header.Get(headers[1])), 10, 64)
+		if err != nil || reset <= 0 {
+			continue
+		}
+		// resets past a day in seconds can only be unix timestamps
+		if reset > int64((24 * time.Hour).Seconds()) {
+			if resetAt := time.Unix(reset, 0); resetAt.After(now) {
+				return resetAt.Sub(now)
+			}
+			return 0
+		}
+		return time.Duration(reset) * time.Second
+	}
+	return 0
+}
@@ -0,0 +1,102 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rudderlabs/rudder-server/services/stats"
+)
+
+var _ = Describe("Paused destinations", func() {
+	var (
+		pd                                *pausedDestinationsT
+		now                               time.Time
+		prevMaxRetryAfter, prevMinBackoff time.Duration
+		prevMaxBackoff                    time.Duration
+	)
+
+	BeforeEach(func() {
+		stats.Setup()
+		now = time.Now()
+		prevMaxRetryAfter, prevMinBackoff, prevMaxBackoff = maxRetryAfter, minRetryBackoff, maxRetryBackoff
+		maxRetryAfter, minRetryBackoff, maxRetryBackoff = time.Minute, 10*time.Second, 300*time.Second
+		pd = newPausedDestinations("WEBHOOK")
+		pd.now = func() time.Time { return now }
+	})
+
+	AfterEach(func() {
+		maxRetryAfter, minRetryBackoff, maxRetryBackoff = prevMaxRetryAfter, prevMinBackoff, prevMaxBackoff
+	})
+
+	It("pauses a destination until its retry after is over", func() {
+		pd.pause("d1", 5*time.Second)
+		Expect(pd.isPaused("d1")).To(BeTrue())
+		Expect(pd.isPaused("d2")).To(BeFalse())
+		Expect(pd.status()).To(HaveKeyWithValue("d1", now.Add(5*time.Second)))
+
+		now = now.Add(5 * time.Second)
+		Expect(pd.isPaused("d1")).To(BeFalse())
+		Expect(pd.status()).To(BeEmpty())
+	})
+
+	It("caps the pause at maxRetryAfter and never shortens it", func() {
+		pd.pause("d1", time.Hour)
+		Expect(pd.status()).To(HaveKeyWithValue("d1", now.Add(time.Minute)))
+
+		pd.pause("d1", time.Second)
+		Expect(pd.status()).To(HaveKeyWithValue("d1", now.Add(time.Minute)))
+	})
+
+	It("ignores missing retry afters", func() {
+		pd.pause("d1", 0)
+		Expect(pd.isPaused("d1")).To(BeFalse())
+	})
+
+	DescribeTable("next attempt of a failed job",
+		func(attempt int, retryAfter, expected time.Duration) {
+			Expect(nextAttemptAfter(attempt, retryAfter)).To(Equal(expected))
+		},
+		Entry("backoff without retry after", 1, time.Duration(0), 10*time.Second),
+		Entry("backoff longer than retry after", 2, 15*time.Second, 20*time.Second),
+		Entry("retry after longer than backoff", 1, 30*time.Second, 30*time.Second),
+		Entry("retry after capped at maxRetryAfter", 1, time.Hour, time.Minute),
+	)
+
+	Context("Retry-After", func() {
+		now := time.Date(2022, 9, 1, 10, 0, 0, 0, time.UTC)
+
+		DescribeTable("should parse the header",
+			func(value string, expected time.Duration) {
+				header := http.Header{}
+				if value != "" {
+					header.Set("Retry-After", value)
+				}
+				Expect(retryAfter(header, now)).To(Equal(expected))
+			},
+			Entry("no header", "", time.Duration(0)),
+			Entry("seconds", "120", 2*time.Minute),
+			Entry("negative seconds", "-1", time.Duration(0)),
+			Entry("http date", "Thu, 01 Sep 2022 10:00:30 GMT", 30*time.Second),
+			Entry("http date in the past", "Thu, 01 Sep 2022 09:00:00 GMT", time.Duration(0)),
+			Entry("invalid", "soon", time.Duration(0)),
+		)
+
+		DescribeTable("should parse the headers of an exhausted rate limit",
+			func(headers map[string]string, expected time.Duration) {
+				header := http.Header{}
+				for k, v := range headers {
+					header.Set(k, v)
+				}
+				Expect(retryAfter(header, now)).To(Equal(expected))
+			},
+			Entry("reset in seconds", map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "30"}, 30*time.Second),
+			Entry("reset as unix timestamp", map[string]string{"RateLimit-Remaining": "0", "RateLimit-Reset": strconv.FormatInt(now.Add(time.Minute).Unix(), 10)}, time.Minute),
+			Entry("remaining requests", map[string]string{"X-RateLimit-Remaining": "5", "X-RateLimit-Reset": "30"}, time.Duration(0)),
+			Entry("Retry-After takes precedence", map[string]string{"Retry-After": "5", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "30"}, 5*time.Second),
+		)
+	})
+})
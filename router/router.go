@@ -33,6 +33,7 @@ import (
 	"github.com/rudderlabs/rudder-server/rruntime"
 	destinationdebugger "github.com/rudderlabs/rudder-server/services/debugger/destination"
 	"github.com/rudderlabs/rudder-server/services/diagnostics"
+	"github.com/rudderlabs/rudder-server/services/dlq"
 	"github.com/rudderlabs/rudder-server/services/metric"
 	"github.com/rudderlabs/rudder-server/services/rsources"
 	"github.com/rudderlabs/rudder-server/services/stats"
@@ -69,6 +70,7 @@ type HandleT struct {
 	responseQ             chan jobResponseT
 	jobsDB                jobsdb.MultiTenantJobsDB
 	errorDB               jobsdb.JobsDB
+	dlqDB                 jobsdb.JobsDB // dead-letter queue of aborted jobs, nil if disabled
 	netHandle             NetHandleI
 	MultitenantI          tenantStats
 	destName              string
@@ -92,7 +94,14 @@ type HandleT struct {
 	failuresMetric                         map[string]map[string]int
 	customDestinationManager               customDestinationManager.DestinationManager
 	throttler                              throttler.Throttler
+	circuitBreaker                         *circuitBreakerT
+	adaptiveLimiter                        *adaptiveLimiterT
+	pausedDestinations                     *pausedDestinationsT
+	httpBatcher                            *httpBatcherT
 	guaranteeUserEventOrder                bool
+	defaultOrdering                        orderingT
+	orderingKeyFallbacks                   sync.Map // destination id::ordering key, for the logged fallbacks to userId
+	enablePriorityLanes                    bool
 	netClientTimeout                       time.Duration
 	backendProxyTimeout                    time.Duration
 	jobdDBQueryRequestTimeout              time.Duration
@@ -112,6 +121,7 @@ type HandleT struct {
 	noOfWorkers                            int
 	allowAbortedUserJobsCountForProcessing int
 	throttledUserMap                       map[string]struct{} // used before calling findWorker. A temp storage to save <userid> whose job can be throttled.
+	throttledDestinationMap                map[string]struct{} // used before calling findWorker. A temp storage to save <destid> whose limit is reached in the current loop.
 	isBackendConfigInitialized             bool
 	backendConfig                          backendconfig.BackendConfig
 	backendConfigInitialized               chan bool
@@ -144,10 +154,10 @@ type HandleT struct {
 }
 
 type jobResponseT struct {
-	status *jobsdb.JobStatusT
-	worker *workerT
-	userID string
-	JobT   *jobsdb.JobT
+	status   *jobsdb.JobStatusT
+	worker   *workerT
+	orderKey string // see jobOrderKey
+	JobT     *jobsdb.JobT
 }
 
 // JobParametersT struct holds source id and destination id of a job
@@ -200,6 +210,7 @@ type workerT struct {
 	latestAssignedTime         time.Time
 	processingStartTime        time.Time
 	encounteredRouterTransform bool
+	httpBatches                map[string]*httpBatchT // batches of jobs waiting to be sent in a single request, by batch key
 }
 
 type destJobCountsT struct {
@@ -212,6 +223,7 @@ var (
 	failedEventsCacheSize                                         int
 	readSleep, minSleep, maxStatusUpdateWait, diagnosisTickerTime time.Duration
 	minRetryBackoff, maxRetryBackoff, jobsBatchTimeout            time.Duration
+	maxRetryAfter                                                 time.Duration
 	pkgLogger                                                     logger.LoggerI
 	Diagnostics                                                   diagnostics.DiagnosticsI
 	fixedLoopSleep                                                time.Duration
@@ -253,6 +265,7 @@ func loadConfig() {
 	config.RegisterDurationConfigVariable(60, &diagnosisTickerTime, false, time.Second, []string{"Diagnostics.routerTimePeriod", "Diagnostics.routerTimePeriodInS"}...)
 	config.RegisterDurationConfigVariable(10, &minRetryBackoff, true, time.Second, []string{"Router.minRetryBackoff", "Router.minRetryBackoffInS"}...)
 	config.RegisterDurationConfigVariable(300, &maxRetryBackoff, true, time.Second, []string{"Router.maxRetryBackoff", "Router.maxRetryBackoffInS"}...)
+	config.RegisterDurationConfigVariable(600, &maxRetryAfter, true, time.Second, "Router.maxRetryAfter")
 	config.RegisterDurationConfigVariable(0, &fixedLoopSleep, true, time.Millisecond, []string{"Router.fixedLoopSleep", "Router.fixedLoopSleepInMS"}...)
 	config.RegisterIntConfigVariable(10, &failedEventsCacheSize, false, 1, "Router.failedEventsCacheSize")
 	config.RegisterStringConfigVariable("", &toAbortDestinationIDs, true, "Router.toAbortDestinationIDs")
@@ -326,10 +339,17 @@ func (worker *workerT) batch(routerJobs []types.RouterJobT) []types.DestinationJ
 
 func (worker *workerT) workerProcess() {
 	timeout := time.After(jobsBatchTimeout)
+	var httpBatchTimeout <-chan time.Time
+	if worker.rt.httpBatcher != nil {
+		ticker := time.NewTicker(worker.rt.httpBatcher.timeout)
+		defer ticker.Stop()
+		httpBatchTimeout = ticker.C
+	}
 	for {
 		select {
 		case message, hasMore := <-worker.channel:
 			if !hasMore {
+				worker.flushHTTPBatches()
 				if len(worker.routerJobs) == 0 {
 					worker.rt.logger.Debugf("[%s Router] :: Worker channel closed, processed %d jobs", worker.rt.destName, len(worker.routerJobs))
 					return
@@ -362,13 +382,16 @@ func (worker *workerT) workerProcess() {
 			if worker.rt.guaranteeUserEventOrder {
 				// If there is a failed jobID from this user, we cannot pass future jobs
 				worker.failedJobIDMutex.RLock()
-				previousFailedJobID, isPrevFailedUser = worker.failedJobIDMap[userID]
+				if orderKey, _ := worker.rt.jobOrderKey(job); orderKey != "" {
+					previousFailedJobID, isPrevFailedUser = worker.failedJobIDMap[orderKey]
+				}
 				worker.failedJobIDMutex.RUnlock()
 
 				// mark job as waiting if prev job from same user has not succeeded yet
 				if isPrevFailedUser {
 					markedAsWaiting := worker.handleJobForPrevFailedUser(job, userID, previousFailedJobID)
 					if markedAsWaiting {
+						worker.rt.orderingBlocked(job)
 						worker.rt.logger.Debugf(`Decrementing in throttle map for destination:%s since job:%d is marked as waiting for user:%s`, parameters.DestinationID, job.JobID, userID)
 						worker.rt.throttler.Dec(parameters.DestinationID, userID, 1, worker.throttledAtTime, throttler.ALL_LEVELS)
 						continue
@@ -409,7 +432,8 @@ func (worker *workerT) workerProcess() {
 					Parameters:    routerutils.EmptyPayload,
 					WorkspaceId:   job.WorkspaceId,
 				}
-				worker.rt.responseQ <- jobResponseT{status: &status, worker: worker, userID: userID, JobT: job}
+				orderKey, _ := worker.rt.jobOrderKey(job)
+				worker.rt.responseQ <- jobResponseT{status: &status, worker: worker, orderKey: orderKey, JobT: job}
 				continue
 			}
 			if authType := routerutils.GetAuthType(destination); routerutils.IsNotEmptyString(authType) && authType == "OAuth" {
@@ -432,6 +456,15 @@ func (worker *workerT) workerProcess() {
 				}
 			}
 
+			if !worker.rt.enableBatching && parameters.TransformAt != "router" {
+				if request, body, ok := worker.rt.httpBatcher.request(job.EventPayload); ok {
+					// jobs are counted in the throttler once their batch is sent
+					worker.addToHTTPBatch(request, body, jobMetadata, destination)
+					continue
+				}
+				worker.flushHTTPBatchesOf(destination.ID)
+			}
+
 			worker.recordCountsByDestAndUser(destination.ID, userID)
 			worker.encounteredRouterTransform = false
 
@@ -468,6 +501,9 @@ func (worker *workerT) workerProcess() {
 				}
 				worker.processDestinationJobs()
 			}
+
+		case <-httpBatchTimeout:
+			worker.flushHTTPBatches()
 		}
 	}
 }
@@ -533,6 +569,8 @@ func (worker *workerT) processDestinationJobs() {
 
 	for _, destinationJob := range worker.destinationJobs {
 		var attemptedToSendTheJob bool
+		var retryAfter time.Duration
+		var itemResults []batchItemResultT // results of the jobs of an http batch, if returned by the destination
 		respBodyArr := make([]string, 0)
 		if destinationJob.StatusCode == 200 || destinationJob.StatusCode == 0 {
 			if worker.canSendJobToDestination(prevRespStatusCode, failedUserIDsMap, &destinationJob) {
@@ -621,11 +659,12 @@ func (worker *workerT) processDestinationJobs() {
 										})
 									}
 								} else {
-									sendCtx, cancel := context.WithTimeout(ctx, worker.rt.netClientTimeout)
+									sendCtx, cancel := context.WithTimeout(withDestination(ctx, destinationJob.Destination), worker.rt.netClientTimeout)
 									rdlTime := time.Now()
 									resp := worker.rt.netHandle.SendPost(sendCtx, val)
 									cancel()
 									respStatusCode, respBodyTemp, respContentType = resp.StatusCode, string(resp.ResponseBody), resp.ResponseContentType
+									retryAfter = resp.RetryAfter
 									// stat end
 									worker.routerDeliveryLatencyStat.SendTiming(time.Since(rdlTime))
 								}
@@ -674,7 +713,17 @@ func (worker *workerT) processDestinationJobs() {
 					respStatusCode = destinationResponseHandler.IsSuccessStatus(respStatusCode, respBody)
 				}
 
+				if isSuccessStatus(respStatusCode) && worker.isHTTPBatch(&destinationJob) {
+					itemResults = worker.rt.httpBatcher.itemResults(respBody, len(destinationJob.JobMetadataArray))
+				}
+
 				attemptedToSendTheJob = true
+				worker.rt.circuitBreaker.record(destinationID, respStatusCode)
+				worker.rt.adaptiveLimiter.observe(destinationID, respStatusCode, timeTaken, retryAfter)
+				if retryAfter > 0 {
+					// the destination asked to hold off through the Retry-After or rate limit headers of its response
+					worker.rt.pausedDestinations.pause(destinationID, retryAfter)
+				}
 
 				worker.deliveryTimeStat.End()
 				deliveryLatencyStat.End()
@@ -705,8 +754,14 @@ func (worker *workerT) processDestinationJobs() {
 
 		prevRespStatusCode = respStatusCode
 
-		if !isJobTerminated(respStatusCode) {
-			for _, metadata := range destinationJob.JobMetadataArray {
+		jobResult := func(i int) (int, string) {
+			if itemResults == nil || isSuccessStatus(itemResults[i].statusCode) {
+				return respStatusCode, respBody
+			}
+			return itemResults[i].statusCode, itemResults[i].body
+		}
+		for i, metadata := range destinationJob.JobMetadataArray {
+			if jobRespStatusCode, _ := jobResult(i); !isJobTerminated(jobRespStatusCode) {
 				failedUserIDsMap[metadata.UserID] = struct{}{}
 			}
 		}
@@ -715,17 +770,19 @@ func (worker *workerT) processDestinationJobs() {
 		// elements in routerJobResponses have pointer to the right job.
 		_destinationJob := destinationJob
 
-		for _, destinationJobMetadata := range _destinationJob.JobMetadataArray {
+		for i, destinationJobMetadata := range _destinationJob.JobMetadataArray {
 			_destinationJobMetadata := destinationJobMetadata
 			// assigning the destinationJobMetadata to a local variable (_destinationJobMetadata), so that
 			// elements in routerJobResponses have pointer to the right destinationJobMetadata.
 
+			jobRespStatusCode, jobRespBody := jobResult(i)
 			routerJobResponses = append(routerJobResponses, &JobResponse{
 				jobID:                  destinationJobMetadata.JobID,
 				destinationJob:         &_destinationJob,
 				destinationJobMetadata: &_destinationJobMetadata,
-				respStatusCode:         respStatusCode,
-				respBody:               respBody,
+				respStatusCode:         jobRespStatusCode,
+				respBody:               jobRespBody,
+				retryAfter:             retryAfter,
 				attemptedToSendTheJob:  attemptedToSendTheJob,
 			})
 		}
@@ -755,8 +812,9 @@ func (worker *workerT) processDestinationJobs() {
 
 		routerJobResponse.status = &status
 
-		if !isJobTerminated(respStatusCode) {
-			if prevFailedJobID, ok := userToJobIDMap[destinationJobMetadata.UserID]; ok {
+		orderKey, _ := worker.rt.jobOrderKey(destinationJobMetadata.JobT)
+		if !isJobTerminated(respStatusCode) && orderKey != "" {
+			if prevFailedJobID, ok := userToJobIDMap[orderKey]; ok {
 				// This means more than two jobs of the same user are in the batch & the batch job is failed
 				// Only one job is marked failed and the rest are marked waiting
 				// Job order logic requires that at any point of time, we should have only one failed job per user
@@ -767,10 +825,10 @@ func (worker *workerT) processDestinationJobs() {
 
 				status.JobState = jobsdb.Waiting.State
 				status.ErrorResponse = resp
-				worker.rt.responseQ <- jobResponseT{status: &status, worker: worker, userID: destinationJobMetadata.UserID, JobT: destinationJobMetadata.JobT}
+				worker.rt.responseQ <- jobResponseT{status: &status, worker: worker, orderKey: orderKey, JobT: destinationJobMetadata.JobT}
 				continue
 			}
-			userToJobIDMap[destinationJobMetadata.UserID] = destinationJobMetadata.JobID
+			userToJobIDMap[orderKey] = destinationJobMetadata.JobID
 		}
 
 		if attemptedToSendTheJob {
@@ -780,7 +838,7 @@ func (worker *workerT) processDestinationJobs() {
 		status.ErrorResponse = routerutils.EmptyPayload
 		status.ErrorCode = strconv.Itoa(respStatusCode)
 
-		worker.postStatusOnResponseQ(respStatusCode, routerJobResponse.respBody, destinationJob.Message, respContentType, destinationJobMetadata, &status)
+		worker.postStatusOnResponseQ(respStatusCode, routerJobResponse.respBody, routerJobResponse.retryAfter, destinationJob.Message, respContentType, destinationJobMetadata, &status)
 
 		worker.sendEventDeliveryStat(destinationJobMetadata, &status, &destinationJob.Destination)
 
@@ -871,6 +929,7 @@ type JobResponse struct {
 	destinationJobMetadata *types.JobMetadataT
 	respStatusCode         int
 	respBody               string
+	retryAfter             time.Duration
 	attemptedToSendTheJob  bool
 	status                 *jobsdb.JobStatusT
 }
@@ -968,7 +1027,7 @@ func (worker *workerT) updateAbortedMetrics(destinationID, statusCode string) {
 	eventsAbortedStat.Increment()
 }
 
-func (worker *workerT) postStatusOnResponseQ(respStatusCode int, respBody string, payload json.RawMessage,
+func (worker *workerT) postStatusOnResponseQ(respStatusCode int, respBody string, retryAfter time.Duration, payload json.RawMessage,
 	respContentType string, destinationJobMetadata *types.JobMetadataT, status *jobsdb.JobStatusT,
 ) {
 	// Enhancing status.ErrorResponse with firstAttemptedAt
@@ -988,7 +1047,8 @@ func (worker *workerT) postStatusOnResponseQ(respStatusCode int, respBody string
 		atomic.AddUint64(&worker.rt.successCount, 1)
 		status.JobState = jobsdb.Succeeded.State
 		worker.rt.logger.Debugf("[%v Router] :: sending success status to response", worker.rt.destName)
-		worker.rt.responseQ <- jobResponseT{status: status, worker: worker, userID: destinationJobMetadata.UserID, JobT: destinationJobMetadata.JobT}
+		orderKey, _ := worker.rt.jobOrderKey(destinationJobMetadata.JobT)
+		worker.rt.responseQ <- jobResponseT{status: status, worker: worker, orderKey: orderKey, JobT: destinationJobMetadata.JobT}
 
 		// Deleting jobID from retryForJobMap. jobID goes into retryForJobMap if it is failed with 5xx or 429.
 		// It's safe to delete from the map, even if jobID is not present.
@@ -1023,13 +1083,13 @@ func (worker *workerT) postStatusOnResponseQ(respStatusCode int, respBody string
 					worker.retryForJobMapMutex.Unlock()
 				} else {
 					worker.retryForJobMapMutex.Lock()
-					worker.retryForJobMap[destinationJobMetadata.JobID] = time.Now().Add(durationBeforeNextAttempt(status.AttemptNum))
+					worker.retryForJobMap[destinationJobMetadata.JobID] = time.Now().Add(nextAttemptAfter(status.AttemptNum, retryAfter))
 					worker.retryForJobMapMutex.Unlock()
 				}
 			}
 		} else if respStatusCode == 429 {
 			worker.retryForJobMapMutex.Lock()
-			worker.retryForJobMap[destinationJobMetadata.JobID] = time.Now().Add(durationBeforeNextAttempt(status.AttemptNum))
+			worker.retryForJobMap[destinationJobMetadata.JobID] = time.Now().Add(nextAttemptAfter(status.AttemptNum, retryAfter))
 			worker.retryForJobMapMutex.Unlock()
 		} else {
 			status.JobState = jobsdb.Aborted.State
@@ -1041,14 +1101,15 @@ func (worker *workerT) postStatusOnResponseQ(respStatusCode int, respBody string
 			destinationJobMetadata.JobT.Parameters = misc.UpdateJSONWithNewKeyVal(destinationJobMetadata.JobT.Parameters, "reason", status.ErrorResponse) // NOTE: Old key used was "error_response"
 		}
 
-		if worker.rt.guaranteeUserEventOrder {
+		orderKey, _ := worker.rt.jobOrderKey(destinationJobMetadata.JobT)
+		if worker.rt.guaranteeUserEventOrder && orderKey != "" {
 			if status.JobState == jobsdb.Failed.State {
 				//#JobOrder (see other #JobOrder comment)
 				worker.failedJobIDMutex.Lock()
-				_, isPrevFailedUser := worker.failedJobIDMap[destinationJobMetadata.UserID]
+				_, isPrevFailedUser := worker.failedJobIDMap[orderKey]
 				if !isPrevFailedUser && destinationJobMetadata.UserID != "" {
 					worker.rt.logger.Debugf("[%v Router] :: userId %v failed for the first time adding to map", worker.rt.destName, destinationJobMetadata.UserID)
-					worker.failedJobIDMap[destinationJobMetadata.UserID] = destinationJobMetadata.JobID
+					worker.failedJobIDMap[orderKey] = destinationJobMetadata.JobID
 				}
 				worker.failedJobIDMutex.Unlock()
 			} else if status.JobState == jobsdb.Aborted.State {
@@ -1058,15 +1119,15 @@ func (worker *workerT) postStatusOnResponseQ(respStatusCode int, respBody string
 				// This map is used to limit the pickup of aborted user's job.
 				worker.abortedUserMutex.Lock()
 				worker.rt.logger.Debugf("[%v Router] :: adding userID to abortedUserMap : %s", worker.rt.destName, destinationJobMetadata.UserID)
-				if _, ok := worker.abortedUserIDMap[destinationJobMetadata.UserID]; !ok {
+				if worker.abortedUserIDMap[orderKey] == nil {
 					// this will enable the concurrency limiter
-					worker.abortedUserIDMap[destinationJobMetadata.UserID] = map[int64]struct{}{}
+					worker.abortedUserIDMap[orderKey] = map[int64]struct{}{}
 				}
 				worker.abortedUserMutex.Unlock()
 			}
 		}
 		worker.rt.logger.Debugf("[%v Router] :: sending failed/aborted state as response", worker.rt.destName)
-		worker.rt.responseQ <- jobResponseT{status: status, worker: worker, userID: destinationJobMetadata.UserID, JobT: destinationJobMetadata.JobT}
+		worker.rt.responseQ <- jobResponseT{status: status, worker: worker, orderKey: orderKey, JobT: destinationJobMetadata.JobT}
 	}
 }
 
@@ -1146,7 +1207,8 @@ func (worker *workerT) handleJobForPrevFailedUser(job *jobsdb.JobT, userID strin
 			Parameters:    routerutils.EmptyPayload,
 			WorkspaceId:   job.WorkspaceId,
 		}
-		worker.rt.responseQ <- jobResponseT{status: &status, worker: worker, userID: userID, JobT: job}
+		orderKey, _ := worker.rt.jobOrderKey(job)
+		worker.rt.responseQ <- jobResponseT{status: &status, worker: worker, orderKey: orderKey, JobT: job}
 		return true
 	}
 	if previousFailedJobID != job.JobID {
@@ -1214,6 +1276,7 @@ func (rt *HandleT) initWorkers() {
 			routerProxyStat:           stats.NewTaggedStat("router_proxy_latency", stats.TimerType, stats.Tags{"destType": rt.destName}),
 			abortedUserIDMap:          make(map[string]map[int64]struct{}),
 			jobCountsByDestAndUser:    make(map[string]*destJobCountsT),
+			httpBatches:               make(map[string]*httpBatchT),
 		}
 		rt.workers[i] = worker
 
@@ -1264,6 +1327,12 @@ func (rt *HandleT) findWorker(job *jobsdb.JobT, throttledAtTime time.Time) (toSe
 		return nil
 	}
 
+	if _, ok := rt.throttledDestinationMap[parameters.DestinationID]; ok {
+		rt.throttledUserMap[userID] = struct{}{}
+		rt.logger.Debugf(`[%v Router] :: Skipping processing of job:%d of user:%s as destination throttled limits exceeded`, rt.destName, job.JobID, userID)
+		return nil
+	}
+
 	if rt.shouldThrottle(parameters.DestinationID, userID, throttledAtTime) {
 		rt.throttledUserMap[userID] = struct{}{}
 		rt.logger.Debugf(`[%v Router] :: Skipping processing of job:%d of user:%s as throttled limits exceeded`, rt.destName, job.JobID, userID)
@@ -1275,26 +1344,37 @@ func (rt *HandleT) findWorker(job *jobsdb.JobT, throttledAtTime time.Time) (toSe
 		return rt.workers[rand.Intn(rt.noOfWorkers)]
 	}
 
-	index := rt.getWorkerPartition(userID)
+	orderKey, partitionKey := rt.jobOrderKey(job)
+	if orderKey == "" {
+		// the job is not ordered, assigning worker randomly unless it has to backoff
+		worker := rt.workers[rand.Intn(rt.noOfWorkers)]
+		if worker.canBackoff(job) {
+			return nil
+		}
+		return worker
+	}
+
+	index := rt.getWorkerPartition(partitionKey)
 
 	worker := rt.workers[index]
 
 	//#JobOrder (see other #JobOrder comment)
 	worker.failedJobIDMutex.RLock()
 	defer worker.failedJobIDMutex.RUnlock()
-	blockJobID, found := worker.failedJobIDMap[userID]
+	blockJobID, found := worker.failedJobIDMap[orderKey]
 	if !found {
 		// not a failed user
 		// checking if he is an aborted user,
 		// if yes returning worker only for 1 job
 		worker.abortedUserMutex.Lock()
 		defer worker.abortedUserMutex.Unlock()
-		if runningJobIDs, ok := worker.abortedUserIDMap[userID]; ok {
+		if runningJobIDs, ok := worker.abortedUserIDMap[orderKey]; ok {
 			count := len(runningJobIDs)
 			_, hasJobID := runningJobIDs[job.JobID]
 
 			if !hasJobID && count >= rt.allowAbortedUserJobsCountForProcessing {
 				rt.logger.Debugf("[%v Router] :: allowed jobs count(%d) >= allowAbortedUserJobsCountForProcessing(%d) for userID %s. returning nil worker", rt.destName, count, rt.allowAbortedUserJobsCountForProcessing, userID)
+				rt.orderingBlocked(job)
 				return nil
 			}
 
@@ -1304,7 +1384,7 @@ func (rt *HandleT) findWorker(job *jobsdb.JobT, throttledAtTime time.Time) (toSe
 			// defer is called before defer Unlock
 			defer func() {
 				if toSendWorker != nil {
-					toSendWorker.abortedUserIDMap[userID][job.JobID] = struct{}{}
+					toSendWorker.abortedUserIDMap[orderKey][job.JobID] = struct{}{}
 				}
 			}()
 		}
@@ -1317,6 +1397,8 @@ func (rt *HandleT) findWorker(job *jobsdb.JobT, throttledAtTime time.Time) (toSe
 		}
 		if job.JobID == blockJobID {
 			toSendWorker = worker
+		} else {
+			rt.orderingBlocked(job)
 		}
 	}
 
@@ -1342,8 +1424,8 @@ func (worker *workerT) canBackoff(job *jobsdb.JobT) (shouldBackoff bool) {
 	return false
 }
 
-func (rt *HandleT) getWorkerPartition(userID string) int {
-	return misc.GetHash(userID) % rt.noOfWorkers
+func (rt *HandleT) getWorkerPartition(partitionKey string) int {
+	return misc.GetHash(partitionKey) % rt.noOfWorkers
 }
 
 func (rt *HandleT) shouldThrottle(destID, userID string, throttledAtTime time.Time) (canBeThrottled bool) {
@@ -1352,9 +1434,10 @@ func (rt *HandleT) shouldThrottle(destID, userID string, throttledAtTime time.Ti
 	}
 
 	// No need of locks here, because this is used only by a single goroutine (generatorLoop)
-	limitReached := rt.throttler.CheckLimitReached(destID, userID, throttledAtTime)
-	if !limitReached {
-		rt.throttler.Inc(destID, userID, throttledAtTime)
+	limitReached, atLevel := rt.throttler.CheckLimitReachedAndInc(destID, userID, throttledAtTime)
+	if limitReached && atLevel == throttler.DESTINATION_LEVEL {
+		// the destination's limit doesn't need to be checked again for the following jobs of this loop
+		rt.throttledDestinationMap[destID] = struct{}{}
 	}
 	return limitReached
 }
@@ -1375,7 +1458,7 @@ func (rt *HandleT) commitStatusList(responseList *[]jobResponseT) {
 	jobRunIDAbortedEventsMap := make(map[string][]*FailedEventRowT)
 	var completedJobsList []*jobsdb.JobT
 	var statusList []*jobsdb.JobStatusT
-	var routerAbortedJobs []*jobsdb.JobT
+	var routerAbortedJobs, dlqJobs []*jobsdb.JobT
 	for _, resp := range *responseList {
 
 		var parameters JobParametersT
@@ -1427,6 +1510,9 @@ func (rt *HandleT) commitStatusList(responseList *[]jobResponseT) {
 			sd.Count++
 			rt.MultitenantI.CalculateSuccessFailureCounts(workspaceID, rt.destName, false, true)
 			routerAbortedJobs = append(routerAbortedJobs, resp.JobT)
+			if rt.dlqDB != nil {
+				dlqJobs = append(dlqJobs, dlq.NewJob(resp.JobT, utilTypes.ROUTER, resp.status.ErrorCode, resp.status.ErrorResponse))
+			}
 			PrepareJobRunIdAbortedEventsMap(resp.JobT.Parameters, jobRunIDAbortedEventsMap)
 			completedJobsList = append(completedJobsList, resp.JobT)
 		}
@@ -1496,6 +1582,15 @@ func (rt *HandleT) commitStatusList(responseList *[]jobResponseT) {
 				panic(fmt.Errorf("storing jobs into ErrorDB: %w", err))
 			}
 		}
+		// Store the aborted jobs to the dead-letter queue
+		if dlqJobs != nil {
+			err := misc.RetryWith(context.Background(), rt.jobsDBCommandTimeout, rt.jobdDBMaxRetries, func(ctx context.Context) error {
+				return rt.dlqDB.Store(ctx, dlqJobs)
+			})
+			if err != nil {
+				panic(fmt.Errorf("storing jobs into dead-letter queue: %w", err))
+			}
+		}
 		// Update the status
 		err := misc.RetryWith(context.Background(), rt.jobsDBCommandTimeout, rt.jobdDBMaxRetries, func(ctx context.Context) error {
 			return rt.jobsDB.WithUpdateSafeTx(func(tx jobsdb.UpdateSafeTx) error {
@@ -1528,34 +1623,34 @@ func (rt *HandleT) commitStatusList(responseList *[]jobResponseT) {
 		//#JobOrder (see other #JobOrder comment)
 		for _, resp := range *responseList {
 			status := resp.status.JobState
-			userID := resp.userID
+			orderKey := resp.orderKey
 			worker := resp.worker
 			rt.eventOrderSyncMu.Lock()
 
 			if status == jobsdb.Succeeded.State || status == jobsdb.Aborted.State {
 				worker.failedJobIDMutex.RLock()
-				lastJobID, ok := worker.failedJobIDMap[userID]
+				lastJobID, ok := worker.failedJobIDMap[orderKey]
 				worker.failedJobIDMutex.RUnlock()
 				if ok && lastJobID == resp.status.JobID {
-					rt.logger.Debugf("[%v Router] :: clearing failedJobIDMap for userID: %v", rt.destName, userID)
+					rt.logger.Debugf("[%v Router] :: clearing failedJobIDMap for user: %v", rt.destName, orderKey)
 					_, ok := rt.toClearFailJobIDMap[worker.workerID]
 					if !ok {
 						rt.toClearFailJobIDMap[worker.workerID] = make([]string, 0)
 					}
-					rt.toClearFailJobIDMap[worker.workerID] = append(rt.toClearFailJobIDMap[worker.workerID], userID)
+					rt.toClearFailJobIDMap[worker.workerID] = append(rt.toClearFailJobIDMap[worker.workerID], orderKey)
 				}
 			}
 
 			// aborted jobIDs
 			worker.abortedUserMutex.RLock()
-			if runningJobs, ok := worker.abortedUserIDMap[userID]; ok {
+			if runningJobs, ok := worker.abortedUserIDMap[orderKey]; ok {
 				if status == jobsdb.Succeeded.State {
 					// clear the map in the next generatorLoop
 					_, ok := rt.toClearAbortedJobIDMap[worker.workerID]
 					if !ok {
 						rt.toClearAbortedJobIDMap[worker.workerID] = make([]string, 0)
 					}
-					rt.toClearAbortedJobIDMap[worker.workerID] = append(rt.toClearAbortedJobIDMap[worker.workerID], userID)
+					rt.toClearAbortedJobIDMap[worker.workerID] = append(rt.toClearAbortedJobIDMap[worker.workerID], orderKey)
 				} else { // any other state
 					if _, ok = runningJobs[resp.status.JobID]; ok {
 						// remove the jobID from the concurrency map during the next generatorLoop
@@ -1563,10 +1658,10 @@ func (rt *HandleT) commitStatusList(responseList *[]jobResponseT) {
 						if !ok {
 							rt.toRemoveAbortedJobIDMap[worker.workerID] = map[string]map[int64]struct{}{}
 						}
-						if _, ok := rt.toRemoveAbortedJobIDMap[worker.workerID][userID]; !ok {
-							rt.toRemoveAbortedJobIDMap[worker.workerID][userID] = make(map[int64]struct{})
+						if _, ok := rt.toRemoveAbortedJobIDMap[worker.workerID][orderKey]; !ok {
+							rt.toRemoveAbortedJobIDMap[worker.workerID][orderKey] = make(map[int64]struct{})
 						}
-						rt.toRemoveAbortedJobIDMap[worker.workerID][userID][resp.status.JobID] = struct{}{}
+						rt.toRemoveAbortedJobIDMap[worker.workerID][orderKey][resp.status.JobID] = struct{}{}
 					}
 				}
 			}
@@ -1617,6 +1712,7 @@ func (rt *HandleT) statusInsertLoop() {
 				jobStatus.status.JobState,
 				jobStatus.status.JobID,
 			)
+			rt.adaptiveLimiter.released(destinationID(jobStatus.JobT))
 			responseList = append(responseList, jobStatus)
 			rt.perfStats.End(1)
 		case <-timeout:
@@ -1760,6 +1856,17 @@ func (rt *HandleT) generatorLoop(ctx context.Context) {
 	}
 }
 
+// sortJobs sorts picked up jobs in job id order or, if priority lanes are enabled, jobs of a higher priority first
+// and jobs of the same priority in job id order
+func (rt *HandleT) sortJobs(jobs []*jobsdb.JobT) {
+	sort.Slice(jobs, func(i, j int) bool {
+		if rt.enablePriorityLanes && jobs[i].Priority != jobs[j].Priority {
+			return jobs[i].Priority > jobs[j].Priority
+		}
+		return jobs[i].JobID < jobs[j].JobID
+	})
+}
+
 func (rt *HandleT) readAndProcess() int {
 	if rt.guaranteeUserEventOrder {
 		//#JobOrder (See comment marked #JobOrder
@@ -1826,17 +1933,19 @@ func (rt *HandleT) readAndProcess() int {
 	}
 	rt.timeGained = 0
 	rt.logger.Debugf("[%v Router] :: pickupMap: %+v", rt.destName, pickupMap)
+	// the jobs of destinations outside of their delivery windows are held and the ones of destinations with an open circuit are parked, see below
+	excludedDestinations := append(rt.heldDestinationFilters(time.Now()), rt.circuitBreaker.openDestinationFilters()...)
 	combinedList, err := jobsdb.QueryJobsWithRetries(context.Background(), rt.jobdDBQueryRequestTimeout, rt.jobdDBMaxRetries, func(ctx context.Context) ([]*jobsdb.JobT, error) {
-		return rt.jobsDB.GetAllJobs(
-			ctx,
-			pickupMap,
-			jobsdb.GetQueryParamsT{
-				CustomValFilters: []string{rt.destName},
-				PayloadSizeLimit: rt.payloadLimit,
-				JobsLimit:        totalPickupCount,
-			},
-			rt.maxDSQuerySize,
-		)
+		params := jobsdb.GetQueryParamsT{
+			CustomValFilters:         []string{rt.destName},
+			ExcludedParameterFilters: excludedDestinations,
+			PayloadSizeLimit:         rt.payloadLimit,
+			JobsLimit:                totalPickupCount,
+		}
+		if rt.enablePriorityLanes {
+			return jobsdb.GetAllJobsByPriority(ctx, rt.jobsDB, pickupMap, params, rt.maxDSQuerySize)
+		}
+		return rt.jobsDB.GetAllJobs(ctx, pickupMap, params, rt.maxDSQuerySize)
 	})
 	if err != nil {
 		rt.logger.Errorf("[%v Router] :: Error getting jobs from DB: %v", rt.destName, err)
@@ -1849,9 +1958,7 @@ func (rt *HandleT) readAndProcess() int {
 		return 0
 	}
 
-	sort.Slice(combinedList, func(i, j int) bool {
-		return combinedList[i].JobID < combinedList[j].JobID
-	})
+	rt.sortJobs(combinedList)
 
 	if len(combinedList) > 0 {
 		rt.logger.Debugf("[%v Router] :: router is enabled", rt.destName)
@@ -1868,10 +1975,14 @@ func (rt *HandleT) readAndProcess() int {
 	var drainList []*jobsdb.JobStatusT
 	var drainJobList []*jobsdb.JobT
 	drainStatsbyDest := make(map[string]*routerutils.DrainStats)
+	parkedCountByDest := make(map[string]int)
+	heldCountByDest := make(map[string]int)
 
 	var toProcess []workerJobT
 
 	rt.throttledUserMap = make(map[string]struct{})
+	rt.throttledDestinationMap = make(map[string]struct{})
+	limitedDestinations := make(map[string]struct{}) // destinations which are paused or reached their adaptive limits, see #JobOrder
 	throttledAtTime := time.Now()
 	connectionDetailsMap := make(map[string]*utilTypes.ConnectionDetails)
 	statusDetailsMap := make(map[string]*utilTypes.StatusDetail)
@@ -1947,8 +2058,53 @@ func (rt *HandleT) readAndProcess() int {
 			// REPORTING - ROUTER - END
 			continue
 		}
+		if !rt.circuitBreaker.allow(destID) {
+			// jobs already parked are left as they are, so that they don't get a new status every time they are picked up
+			if !isParkedByCircuitBreaker(job) {
+				statusList = append(statusList, &jobsdb.JobStatusT{
+					JobID:         job.JobID,
+					AttemptNum:    job.LastJobStatus.AttemptNum,
+					JobState:      jobsdb.Waiting.State,
+					ExecTime:      time.Now(),
+					RetryTime:     time.Now(),
+					ErrorCode:     "",
+					ErrorResponse: routerutils.EnhanceJSON(routerutils.EmptyPayload, "reason", circuitOpenReason),
+					Parameters:    routerutils.EmptyPayload,
+					WorkspaceId:   job.WorkspaceId,
+				})
+				parkedCountByDest[destID]++
+			}
+			rt.timeGained += latenciesUsed[job.WorkspaceId]
+			continue
+		}
+		if !rt.isInDeliveryWindow(destID, throttledAtTime) {
+			// jobs already held are left as they are, so that they don't get a new status every time they are picked up
+			if !isHeldOutsideDeliveryWindow(job) {
+				statusList = append(statusList, &jobsdb.JobStatusT{
+					JobID:         job.JobID,
+					AttemptNum:    job.LastJobStatus.AttemptNum,
+					JobState:      jobsdb.Waiting.State,
+					ExecTime:      time.Now(),
+					RetryTime:     time.Now(),
+					ErrorCode:     "",
+					ErrorResponse: routerutils.EnhanceJSON(routerutils.EmptyPayload, "reason", outsideDeliveryWindowReason),
+					Parameters:    routerutils.EmptyPayload,
+					WorkspaceId:   job.WorkspaceId,
+				})
+				heldCountByDest[destID]++
+			}
+			rt.timeGained += latenciesUsed[job.WorkspaceId]
+			continue
+		}
+		if _, ok := limitedDestinations[destID]; ok || rt.pausedDestinations.isPaused(destID) || !rt.adaptiveLimiter.allow(destID) {
+			// once limited, none of the following jobs of the destination are picked up in this loop, keeping their order
+			limitedDestinations[destID] = struct{}{}
+			continue
+		}
 		w := rt.findWorker(job, throttledAtTime)
 		if w != nil {
+			rt.circuitBreaker.picked(destID)
+			rt.adaptiveLimiter.picked(destID)
 			status := jobsdb.JobStatusT{
 				JobID:         job.JobID,
 				AttemptNum:    job.LastJobStatus.AttemptNum,
@@ -1965,8 +2121,9 @@ func (rt *HandleT) readAndProcess() int {
 		}
 	}
 	rt.throttledUserMap = nil
+	rt.throttledDestinationMap = nil
 
-	// Mark the jobs as executing
+	// Mark the jobs as executing, or waiting if parked by the circuit breaker or held outside of the delivery window
 	err = misc.RetryWith(context.Background(), rt.jobsDBCommandTimeout, rt.jobdDBMaxRetries, func(ctx context.Context) error {
 		return rt.jobsDB.UpdateJobStatus(ctx, statusList, []string{rt.destName}, nil)
 	})
@@ -1974,6 +2131,18 @@ func (rt *HandleT) readAndProcess() int {
 		pkgLogger.Errorf("Error occurred while marking %s jobs statuses as executing. Panicking. Err: %v", rt.destName, err)
 		panic(err)
 	}
+	for destID, count := range parkedCountByDest {
+		stats.NewTaggedStat("router_circuit_breaker_parked_jobs", stats.CountType, stats.Tags{
+			"destType":      rt.destName,
+			"destinationId": destID,
+		}).Count(count)
+	}
+	for destID, count := range heldCountByDest {
+		stats.NewTaggedStat("router_delivery_window_held_jobs", stats.CountType, stats.Tags{
+			"destType":      rt.destName,
+			"destinationId": destID,
+		}).Count(count)
+	}
 
 	// Mark the jobs as aborted
 	if len(drainList) > 0 {
@@ -2138,6 +2307,8 @@ func (rt *HandleT) Setup(backendConfig backendconfig.BackendConfig, jobsDB jobsd
 		rt.saveDestinationResponse = value
 	}
 	rt.guaranteeUserEventOrder = getRouterConfigBool("guaranteeUserEventOrder", rt.destName, true)
+	rt.setupOrdering()
+	rt.enablePriorityLanes = getRouterConfigBool("enablePriorityLanes", rt.destName, false)
 	rt.noOfWorkers = getRouterConfigInt("noOfWorkers", destName, 64)
 	maxFailedCountKeys := []string{"Router." + rt.destName + "." + "maxFailedCountForJob", "Router." + "maxFailedCountForJob"}
 	retryTimeWindowKeys := []string{"Router." + rt.destName + "." + "retryTimeWindow", "Router." + rt.destName + "." + "retryTimeWindowInMins", "Router." + "retryTimeWindow", "Router." + "retryTimeWindowInMins"}
@@ -2188,6 +2359,10 @@ func (rt *HandleT) Setup(backendConfig backendconfig.BackendConfig, jobsDB jobsd
 	var t throttler.HandleT
 	t.SetUp(rt.destName)
 	rt.throttler = &t
+	rt.circuitBreaker = newCircuitBreaker(rt.destName)
+	rt.adaptiveLimiter = newAdaptiveLimiter(rt.destName)
+	rt.pausedDestinations = newPausedDestinations(rt.destName)
+	rt.httpBatcher = newHTTPBatcher(rt.destName)
 
 	rt.isBackendConfigInitialized = false
 	rt.backendConfigInitialized = make(chan bool)
@@ -2280,7 +2455,11 @@ func (rt *HandleT) backendConfigSubscriber() {
 					destination := &source.Destinations[i]
 					if destination.DestinationDefinition.Name == rt.destName {
 						if _, ok := rt.destinationsMap[destination.ID]; !ok {
-							rt.destinationsMap[destination.ID] = &routerutils.BatchDestinationT{Destination: *destination, Sources: []backendconfig.SourceT{}}
+							rt.destinationsMap[destination.ID] = &routerutils.BatchDestinationT{
+								Destination:     *destination,
+								Sources:         []backendconfig.SourceT{},
+								DeliveryWindows: routerutils.GetDeliveryWindows(*destination),
+							}
 						}
 						rt.destinationsMap[destination.ID].Sources = append(rt.destinationsMap[destination.ID].Sources, *source)
 
@@ -6,11 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"os"
 	"testing"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
 
+	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
 
 	"github.com/gofrs/uuid"
@@ -27,6 +29,7 @@ import (
 	mocksRouter "github.com/rudderlabs/rudder-server/mocks/router"
 	mocksTransformer "github.com/rudderlabs/rudder-server/mocks/router/transformer"
 	mocksMultitenant "github.com/rudderlabs/rudder-server/mocks/services/multitenant"
+	"github.com/rudderlabs/rudder-server/processor/integrations"
 	"github.com/rudderlabs/rudder-server/router/types"
 	routerUtils "github.com/rudderlabs/rudder-server/router/utils"
 	"github.com/rudderlabs/rudder-server/services/rsources"
@@ -279,6 +282,141 @@ var _ = Describe("Router", func() {
 			<-done
 		})
 
+		It("should park jobs of a destination whose circuit breaker is open", func() {
+			mockMultitenantHandle := mocksMultitenant.NewMockMultiTenantI(c.mockCtrl)
+			mockNetHandle := mocksRouter.NewMockNetHandleI(c.mockCtrl)
+			router := &HandleT{
+				Reporting:    &reportingNOOP{},
+				MultitenantI: mockMultitenantHandle,
+				netHandle:    mockNetHandle,
+			}
+			mockMultitenantHandle.EXPECT().UpdateWorkspaceLatencyMap(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			c.mockBackendConfig.EXPECT().AccessToken().AnyTimes()
+
+			router.Setup(c.mockBackendConfig, c.mockRouterJobsDB, c.mockProcErrorsDB, gaDestinationDefinition, transientsource.NewEmptyService(), rsources.NewNoOpService())
+			router.circuitBreaker = &circuitBreakerT{
+				destType:         customVal["GA"],
+				failureThreshold: 1,
+				halfOpenProbes:   1,
+				openTimeout:      time.Hour,
+				now:              time.Now,
+				circuits:         make(map[string]*circuitT),
+			}
+			router.circuitBreaker.record(gaDestinationID, 503)
+
+			parameters := fmt.Sprintf(`{"source_id": "1fMCVYZboDlYlauh4GFsEo2JU77", "destination_id": "%s", "message_id": "2f548e6d-60f6-44af-a1f4-62b3272445c3", "received_at": "2021-06-28T10:04:48.527+05:30", "transform_at": "processor"}`, gaDestinationID)
+			jobs := []*jobsdb.JobT{
+				{
+					UUID:         uuid.Must(uuid.NewV4()),
+					UserID:       "u1",
+					JobID:        2009,
+					CustomVal:    customVal["GA"],
+					EventPayload: []byte(`{}`),
+					LastJobStatus: jobsdb.JobStatusT{
+						AttemptNum:    1,
+						JobState:      jobsdb.Waiting.State,
+						ErrorResponse: []byte(`{"reason": "` + circuitOpenReason + `"}`),
+					},
+					Parameters:  []byte(parameters),
+					WorkspaceId: workspaceID,
+				},
+				{
+					UUID:          uuid.Must(uuid.NewV4()),
+					UserID:        "u1",
+					JobID:         2010,
+					CustomVal:     customVal["GA"],
+					EventPayload:  []byte(`{}`),
+					LastJobStatus: jobsdb.JobStatusT{AttemptNum: 0},
+					Parameters:    []byte(parameters),
+					WorkspaceId:   workspaceID,
+				},
+			}
+			workspaceCount := map[string]int{workspaceID: len(jobs)}
+
+			callGetRouterPickupJobs := mockMultitenantHandle.EXPECT().GetRouterPickupJobs(customVal["GA"], gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(workspaceCount, map[string]float64{}).Times(1)
+			callGetAllJobs := c.mockRouterJobsDB.EXPECT().GetAllJobs(gomock.Any(), workspaceCount, gomock.Any(), 10).Times(1).Return(jobs, nil).After(callGetRouterPickupJobs)
+			c.mockRouterJobsDB.EXPECT().UpdateJobStatus(gomock.Any(), gomock.Any(), []string{customVal["GA"]}, nil).Times(1).
+				Do(func(ctx context.Context, statuses []*jobsdb.JobStatusT, _, _ interface{}) {
+					Expect(statuses).To(HaveLen(1))
+					assertJobStatus(jobs[1], statuses[0], jobsdb.Waiting.State, "", "", 0)
+					Expect(statuses[0].ErrorResponse).To(MatchJSON(`{"reason": "` + circuitOpenReason + `"}`))
+				}).Return(nil).After(callGetAllJobs)
+
+			<-router.backendConfigInitialized
+			count := router.readAndProcess()
+			Expect(count).To(Equal(0))
+			Expect(router.circuitBreaker.status()).To(Equal(map[string]string{gaDestinationID: "open"}))
+		})
+
+		It("should hold jobs of a destination outside of its delivery windows", func() {
+			mockMultitenantHandle := mocksMultitenant.NewMockMultiTenantI(c.mockCtrl)
+			mockNetHandle := mocksRouter.NewMockNetHandleI(c.mockCtrl)
+			router := &HandleT{
+				Reporting:    &reportingNOOP{},
+				MultitenantI: mockMultitenantHandle,
+				netHandle:    mockNetHandle,
+			}
+			mockMultitenantHandle.EXPECT().UpdateWorkspaceLatencyMap(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			c.mockBackendConfig.EXPECT().AccessToken().AnyTimes()
+
+			router.Setup(c.mockBackendConfig, c.mockRouterJobsDB, c.mockProcErrorsDB, gaDestinationDefinition, transientsource.NewEmptyService(), rsources.NewNoOpService())
+
+			parameters := fmt.Sprintf(`{"source_id": "1fMCVYZboDlYlauh4GFsEo2JU77", "destination_id": "%s", "message_id": "2f548e6d-60f6-44af-a1f4-62b3272445c3", "received_at": "2021-06-28T10:04:48.527+05:30", "transform_at": "processor"}`, gaDestinationID)
+			jobs := []*jobsdb.JobT{
+				{
+					UUID:         uuid.Must(uuid.NewV4()),
+					UserID:       "u1",
+					JobID:        2009,
+					CustomVal:    customVal["GA"],
+					EventPayload: []byte(`{}`),
+					LastJobStatus: jobsdb.JobStatusT{
+						AttemptNum:    1,
+						JobState:      jobsdb.Waiting.State,
+						ErrorResponse: []byte(`{"reason": "` + outsideDeliveryWindowReason + `"}`),
+					},
+					Parameters:  []byte(parameters),
+					WorkspaceId: workspaceID,
+				},
+				{
+					UUID:          uuid.Must(uuid.NewV4()),
+					UserID:        "u1",
+					JobID:         2010,
+					CustomVal:     customVal["GA"],
+					EventPayload:  []byte(`{}`),
+					LastJobStatus: jobsdb.JobStatusT{AttemptNum: 0},
+					Parameters:    []byte(parameters),
+					WorkspaceId:   workspaceID,
+				},
+			}
+			workspaceCount := map[string]int{workspaceID: len(jobs)}
+
+			callGetRouterPickupJobs := mockMultitenantHandle.EXPECT().GetRouterPickupJobs(customVal["GA"], gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(workspaceCount, map[string]float64{}).Times(1)
+			callGetAllJobs := c.mockRouterJobsDB.EXPECT().GetAllJobs(gomock.Any(), workspaceCount, gomock.Any(), 10).Times(1).
+				Do(func(_ context.Context, _ map[string]int, params jobsdb.GetQueryParamsT, _ int) {
+					// the jobs of the destination are left out of the query, the ones returned were picked up while its window closed
+					Expect(params.ExcludedParameterFilters).To(Equal([]jobsdb.ParameterFilterT{{Name: "destination_id", Value: gaDestinationID}}))
+				}).Return(jobs, nil).After(callGetRouterPickupJobs)
+			c.mockRouterJobsDB.EXPECT().UpdateJobStatus(gomock.Any(), gomock.Any(), []string{customVal["GA"]}, nil).Times(1).
+				Do(func(ctx context.Context, statuses []*jobsdb.JobStatusT, _, _ interface{}) {
+					Expect(statuses).To(HaveLen(1))
+					assertJobStatus(jobs[1], statuses[0], jobsdb.Waiting.State, "", "", 0)
+					Expect(statuses[0].ErrorResponse).To(MatchJSON(`{"reason": "` + outsideDeliveryWindowReason + `"}`))
+				}).Return(nil).After(callGetAllJobs)
+
+			<-router.backendConfigInitialized
+			// a blackout of the whole day, but the last minute of it
+			router.configSubscriberLock.Lock()
+			now := time.Now().UTC()
+			minute := now.Hour()*60 + now.Minute()
+			router.destinationsMap[gaDestinationID].DeliveryWindows = routerUtils.DeliveryWindowsT{
+				Blackouts: []routerUtils.WindowT{{Start: minute, End: (minute + 1439) % 1440}},
+			}
+			router.configSubscriberLock.Unlock()
+
+			count := router.readAndProcess()
+			Expect(count).To(Equal(0))
+		})
+
 		It("should abort unprocessed jobs to ga destination because of bad payload", func() {
 			mockMultitenantHandle := mocksMultitenant.NewMockMultiTenantI(c.mockCtrl)
 
@@ -365,6 +503,77 @@ var _ = Describe("Router", func() {
 			<-done
 		})
 
+		It("should store aborted jobs into the dead-letter queue", func() {
+			mockMultitenantHandle := mocksMultitenant.NewMockMultiTenantI(c.mockCtrl)
+			mockDLQDB := mocksJobsDB.NewMockJobsDB(c.mockCtrl)
+
+			router := &HandleT{
+				Reporting:    &reportingNOOP{},
+				MultitenantI: mockMultitenantHandle,
+				dlqDB:        mockDLQDB,
+			}
+			mockMultitenantHandle.EXPECT().UpdateWorkspaceLatencyMap(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			c.mockBackendConfig.EXPECT().AccessToken().AnyTimes()
+
+			router.Setup(c.mockBackendConfig, c.mockRouterJobsDB, c.mockProcErrorsDB, gaDestinationDefinition, transientsource.NewEmptyService(), rsources.NewNoOpService())
+
+			mockNetHandle := mocksRouter.NewMockNetHandleI(c.mockCtrl)
+			router.netHandle = mockNetHandle
+
+			parameters := fmt.Sprintf(`{"source_id": "1fMCVYZboDlYlauh4GFsEo2JU77", "destination_id": "%s", "message_id": "2f548e6d-60f6-44af-a1f4-62b3272445c3", "received_at": "2021-06-28T10:04:48.527+05:30", "transform_at": "processor"}`, gaDestinationID)
+			unprocessedJobsList := []*jobsdb.JobT{
+				{
+					UUID:         uuid.Must(uuid.NewV4()),
+					UserID:       "u1",
+					JobID:        2010,
+					CreatedAt:    time.Date(2020, 0o4, 28, 13, 26, 0o0, 0o0, time.UTC),
+					ExpireAt:     time.Date(2020, 0o4, 28, 13, 26, 0o0, 0o0, time.UTC),
+					CustomVal:    customVal["GA"],
+					EventPayload: []byte(`{}`),
+					LastJobStatus: jobsdb.JobStatusT{
+						AttemptNum: 0,
+					},
+					Parameters:  []byte(parameters),
+					WorkspaceId: workspaceID,
+				},
+			}
+
+			workspaceCount := map[string]int{workspaceID: len(unprocessedJobsList)}
+			callGetRouterPickupJobs := mockMultitenantHandle.EXPECT().GetRouterPickupJobs(customVal["GA"], gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(workspaceCount, map[string]float64{}).Times(1)
+			callGetAllJobs := c.mockRouterJobsDB.EXPECT().GetAllJobs(gomock.Any(), workspaceCount, jobsdb.GetQueryParamsT{
+				CustomValFilters: []string{customVal["GA"]}, PayloadSizeLimit: router.payloadLimit, JobsLimit: workspaceCount[workspaceID],
+			}, 10).Times(1).Return(unprocessedJobsList, nil).After(callGetRouterPickupJobs)
+			c.mockRouterJobsDB.EXPECT().UpdateJobStatus(gomock.Any(), gomock.Any(), []string{customVal["GA"]}, nil).Times(1).After(callGetAllJobs)
+
+			mockNetHandle.EXPECT().SendPost(gomock.Any(), gomock.Any()).Times(1).Return(&routerUtils.SendPostResponse{StatusCode: 400, ResponseBody: []byte("")})
+			mockMultitenantHandle.EXPECT().CalculateSuccessFailureCounts(gomock.Any(), gomock.Any(), false, true).AnyTimes()
+
+			c.mockProcErrorsDB.EXPECT().Store(gomock.Any(), gomock.Any()).Times(1)
+			mockDLQDB.EXPECT().Store(gomock.Any(), gomock.Any()).Times(1).
+				Do(func(ctx context.Context, jobList []*jobsdb.JobT) {
+					Expect(jobList).To(HaveLen(1))
+					job := jobList[0]
+					Expect(job.UUID).NotTo(Equal(unprocessedJobsList[0].UUID))
+					Expect(job.CustomVal).To(Equal(unprocessedJobsList[0].CustomVal))
+					Expect(job.UserID).To(Equal(unprocessedJobsList[0].UserID))
+					Expect(job.EventPayload).To(Equal(unprocessedJobsList[0].EventPayload))
+					Expect(gjson.GetBytes(job.Parameters, "stage").String()).To(Equal("router"))
+					Expect(gjson.GetBytes(job.Parameters, "error_code").String()).To(Equal("400"))
+					Expect(gjson.GetBytes(job.Parameters, "aborted_job_id").Int()).To(Equal(unprocessedJobsList[0].JobID))
+				})
+			done := make(chan struct{})
+			c.mockRouterJobsDB.EXPECT().WithUpdateSafeTx(gomock.Any()).Times(1).Do(func(f func(tx jobsdb.UpdateSafeTx) error) {
+				_ = f(jobsdb.EmptyUpdateSafeTx())
+				close(done)
+			}).Return(nil)
+			c.mockRouterJobsDB.EXPECT().UpdateJobStatusInTx(gomock.Any(), gomock.Any(), gomock.Any(), []string{customVal["GA"]}, nil).Times(1)
+
+			<-router.backendConfigInitialized
+			count := router.readAndProcess()
+			Expect(count).To(Equal(1))
+			<-done
+		})
+
 		It("aborts events that are older than a configurable duration", func() {
 			routerUtils.JobRetention = time.Duration(24) * time.Hour
 			mockMultitenantHandle := mocksMultitenant.NewMockMultiTenantI(c.mockCtrl)
@@ -572,6 +781,97 @@ var _ = Describe("Router", func() {
 		})
 	})
 
+	Context("HTTP Batching", func() {
+		BeforeEach(func() {
+			maxStatusUpdateWait = 2 * time.Second
+			for key, value := range map[string]string{
+				"Router.GA.httpBatching.enabled":   "true",
+				"Router.GA.httpBatching.maxEvents": "3",
+				"Router.GA.httpBatching.timeout":   "1h",
+				"Router.GA.httpBatching.itemsPath": "results",
+				"Router.GA.noOfWorkers":            "1", // jobs are batched per worker
+			} {
+				Expect(os.Setenv(config.TransformKey(key), value)).To(Succeed())
+				DeferCleanup(os.Unsetenv, config.TransformKey(key))
+			}
+		})
+
+		It("sends the jobs of a destination in a single request and maps the per item results to the jobs", func() {
+			mockMultitenantHandle := mocksMultitenant.NewMockMultiTenantI(c.mockCtrl)
+			mockNetHandle := mocksRouter.NewMockNetHandleI(c.mockCtrl)
+			router := &HandleT{
+				Reporting:    &reportingNOOP{},
+				MultitenantI: mockMultitenantHandle,
+				netHandle:    mockNetHandle,
+			}
+			mockMultitenantHandle.EXPECT().UpdateWorkspaceLatencyMap(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			c.mockBackendConfig.EXPECT().AccessToken().AnyTimes()
+
+			router.Setup(c.mockBackendConfig, c.mockRouterJobsDB, c.mockProcErrorsDB, gaDestinationDefinition, transientsource.NewEmptyService(), rsources.NewNoOpService())
+			Expect(router.httpBatcher).NotTo(BeNil())
+
+			parameters := fmt.Sprintf(`{"source_id": "1fMCVYZboDlYlauh4GFsEo2JU77", "destination_id": "%s", "message_id": "2f548e6d-60f6-44af-a1f4-62b3272445c3", "received_at": "2021-06-28T10:04:48.527+05:30", "transform_at": "processor"}`, gaDestinationID)
+			var jobs []*jobsdb.JobT
+			for i, userID := range []string{"u1", "u2", "u3"} {
+				jobs = append(jobs, &jobsdb.JobT{
+					UUID:         uuid.Must(uuid.NewV4()),
+					UserID:       userID,
+					JobID:        int64(2010 + i),
+					CreatedAt:    time.Date(2020, 0o4, 28, 13, 26, 0o0, 0o0, time.UTC),
+					ExpireAt:     time.Date(2020, 0o4, 28, 13, 26, 0o0, 0o0, time.UTC),
+					CustomVal:    customVal["GA"],
+					EventPayload: []byte(fmt.Sprintf(`{"body": {"XML": {}, "FORM": {}, "JSON": {"event": "Demo Track", "userId": "%s"}}, "type": "REST", "files": {}, "method": "POST", "params": {}, "userId": "%s", "headers": {}, "version": "1", "endpoint": "https://webhook.example.com"}`, userID, userID)),
+					LastJobStatus: jobsdb.JobStatusT{
+						AttemptNum: 0,
+					},
+					Parameters:  []byte(parameters),
+					WorkspaceId: workspaceID,
+				})
+			}
+
+			workspaceCount := map[string]int{workspaceID: len(jobs)}
+			callGetRouterPickupJobs := mockMultitenantHandle.EXPECT().GetRouterPickupJobs(customVal["GA"], gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(workspaceCount, map[string]float64{}).Times(1)
+
+			payloadLimit := router.payloadLimit
+			callGetAllJobs := c.mockRouterJobsDB.EXPECT().GetAllJobs(gomock.Any(), workspaceCount,
+				jobsdb.GetQueryParamsT{CustomValFilters: []string{customVal["GA"]}, PayloadSizeLimit: payloadLimit, JobsLimit: workspaceCount[workspaceID]}, 10).Times(1).Return(jobs, nil).After(callGetRouterPickupJobs)
+
+			c.mockRouterJobsDB.EXPECT().UpdateJobStatus(gomock.Any(), gomock.Any(), []string{customVal["GA"]}, nil).Times(1).Return(nil).After(callGetAllJobs)
+
+			mockNetHandle.EXPECT().SendPost(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+				func(_ context.Context, request integrations.PostParametersT) *routerUtils.SendPostResponse {
+					Expect(request.URL).To(Equal("https://webhook.example.com"))
+					Expect(request.Body["JSON_ARRAY"]).To(HaveKeyWithValue("batch", MatchJSON(`[{"event": "Demo Track", "userId": "u1"}, {"event": "Demo Track", "userId": "u2"}, {"event": "Demo Track", "userId": "u3"}]`)))
+					return &routerUtils.SendPostResponse{StatusCode: 200, ResponseBody: []byte(`{"results": [{"status": 200}, {"status": 500, "error": "unavailable"}, {"status": 201}]}`)}
+				})
+			mockMultitenantHandle.EXPECT().CalculateSuccessFailureCounts(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+			done := make(chan struct{})
+
+			c.mockRouterJobsDB.EXPECT().WithUpdateSafeTx(gomock.Any()).Times(1).Do(func(f func(tx jobsdb.UpdateSafeTx) error) {
+				_ = f(jobsdb.EmptyUpdateSafeTx())
+				close(done)
+			}).Return(nil)
+			c.mockRouterJobsDB.EXPECT().UpdateJobStatusInTx(gomock.Any(), gomock.Any(), gomock.Any(), []string{customVal["GA"]}, nil).Times(1).
+				Do(func(ctx context.Context, _ interface{}, statuses []*jobsdb.JobStatusT, _, _ interface{}) {
+					Expect(statuses).To(HaveLen(3))
+					states := map[int64]string{}
+					for _, status := range statuses {
+						states[status.JobID] = status.JobState + ":" + status.ErrorCode
+					}
+					Expect(states).To(Equal(map[int64]string{
+						2010: jobsdb.Succeeded.State + ":200",
+						2011: jobsdb.Failed.State + ":500",
+						2012: jobsdb.Succeeded.State + ":200",
+					}))
+				})
+
+			<-router.backendConfigInitialized
+			count := router.readAndProcess()
+			Expect(count).To(Equal(3))
+			<-done
+		})
+	})
+
 	Context("Router Batching", func() {
 		BeforeEach(func() {
 			maxStatusUpdateWait = 2 * time.Second
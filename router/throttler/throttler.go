@@ -2,11 +2,13 @@ package throttler
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/rudderlabs/rudder-server/config"
-	"github.com/rudderlabs/rudder-server/router/throttler/ratelimiter"
+	"github.com/rudderlabs/rudder-server/services/kvstoremanager"
 	"github.com/rudderlabs/rudder-server/utils/logger"
+	"github.com/rudderlabs/rudder-server/utils/ratelimiter"
 )
 
 const (
@@ -17,8 +19,7 @@ const (
 
 // Throttler is an interface for throttling functions
 type Throttler interface {
-	CheckLimitReached(destID, userID string, currentTime time.Time) bool
-	Inc(destID, userID string, currentTime time.Time)
+	CheckLimitReachedAndInc(destID, userID string, currentTime time.Time) (limitReached bool, atLevel string)
 	Dec(destID, userID string, count int64, currentTime time.Time, atLevel string)
 	IsEnabled() bool
 	IsUserLevelEnabled() bool
@@ -46,7 +47,24 @@ type HandleT struct {
 	userLimiter     *Limiter
 }
 
-var pkgLogger logger.LoggerI
+var (
+	pkgLogger logger.LoggerI
+
+	sharedCounterStoreOnce sync.Once
+	sharedCounterStore     ratelimiter.CounterStore
+)
+
+// SharedCounterStore returns the store of the limiter counters shared by all the nodes of the cluster if the limits are
+// cluster-wide (Router.throttler.store is redis), or nil if each node applies the limits on its own
+func SharedCounterStore() ratelimiter.CounterStore {
+	if config.GetString("Router.throttler.store", "memory") != "redis" {
+		return nil
+	}
+	sharedCounterStoreOnce.Do(func() {
+		sharedCounterStore = kvstoremanager.New("REDIS", kvstoremanager.RedisConfig("Router.throttler.redis"))
+	})
+	return sharedCounterStore
+}
 
 func (throttler *HandleT) setLimits() {
 	destName := throttler.destinationName
@@ -78,6 +96,11 @@ func (throttler *HandleT) setLimits() {
 
 // SetUp eventLimiter
 func (throttler *HandleT) SetUp(destName string) {
+	throttler.SetUpWithStore(destName, SharedCounterStore())
+}
+
+// SetUpWithStore sets up the eventLimiter keeping its counters in store, or in memory if store is nil
+func (throttler *HandleT) SetUpWithStore(destName string, store ratelimiter.CounterStore) {
 	pkgLogger = logger.NewLogger().Child("router").Child("throttler")
 	throttler.destinationName = destName
 	throttler.destLimiter = &Limiter{}
@@ -87,56 +110,52 @@ func (throttler *HandleT) SetUp(destName string) {
 	throttler.setLimits()
 
 	if throttler.destLimiter.enabled {
-		dataStore := ratelimiter.NewMapLimitStore(2*throttler.destLimiter.timeWindow, 10*time.Second)
+		dataStore := newLimitStore(store, 2*throttler.destLimiter.timeWindow)
 		throttler.destLimiter.ratelimiter = ratelimiter.New(dataStore, int64(throttler.destLimiter.eventLimit), throttler.destLimiter.timeWindow)
 	}
 
 	if throttler.userLimiter.enabled {
-		dataStore := ratelimiter.NewMapLimitStore(2*throttler.userLimiter.timeWindow, 10*time.Second)
+		dataStore := newLimitStore(store, 2*throttler.userLimiter.timeWindow)
 		throttler.userLimiter.ratelimiter = ratelimiter.New(dataStore, int64(throttler.userLimiter.eventLimit), throttler.userLimiter.timeWindow)
 	}
 }
 
-// LimitReached returns true if number of events in the rolling window is less than the max events allowed, else false
-func (throttler *HandleT) CheckLimitReached(destID, userID string, currentTime time.Time) bool {
-	var destLevelLimitReached bool
+func newLimitStore(store ratelimiter.CounterStore, expirationTime time.Duration) ratelimiter.LimitStore {
+	if store == nil {
+		return ratelimiter.NewMapLimitStore(expirationTime, 10*time.Second)
+	}
+	return ratelimiter.NewSharedLimitStore(store, expirationTime)
+}
+
+// CheckLimitReachedAndInc returns true if the event would exceed the max events allowed in the rolling window,
+// along with the level whose limit is reached, else it counts the event at both levels and returns false.
+// Checking and counting is atomic per level, so that the nodes sharing the counters can't exceed the limits together.
+func (throttler *HandleT) CheckLimitReachedAndInc(destID, userID string, currentTime time.Time) (limitReached bool, atLevel string) {
 	if throttler.destLimiter.enabled {
 		destKey := throttler.getDestKey(destID)
-		limitStatus, err := throttler.destLimiter.ratelimiter.Check(destKey, currentTime)
+		limitStatus, err := throttler.destLimiter.ratelimiter.CheckAndInc(destKey, currentTime)
 		if err != nil {
 			// TODO: handle this
 			pkgLogger.Errorf(`[[ %s-router-throttler: Error checking limitStatus: %v]]`, throttler.destinationName, err)
-		} else {
-			destLevelLimitReached = limitStatus.IsLimited
+		} else if limitStatus.IsLimited {
+			return true, DESTINATION_LEVEL
 		}
 	}
 
-	var userLevelLimitReached bool
-	if !destLevelLimitReached && throttler.userLimiter.enabled {
+	if throttler.userLimiter.enabled {
 		userKey := throttler.getUserKey(destID, userID)
-		limitStatus, err := throttler.userLimiter.ratelimiter.Check(userKey, currentTime)
+		limitStatus, err := throttler.userLimiter.ratelimiter.CheckAndInc(userKey, currentTime)
 		if err != nil {
 			// TODO: handle this
 			pkgLogger.Errorf(`[[ %s-router-throttler: Error checking limitStatus: %v]]`, throttler.destinationName, err)
-		} else {
-			userLevelLimitReached = limitStatus.IsLimited
+		} else if limitStatus.IsLimited {
+			// the event isn't sent, so it doesn't count towards the destination limit either
+			throttler.Dec(destID, "", 1, currentTime, DESTINATION_LEVEL)
+			return true, USER_LEVEL
 		}
 	}
 
-	return destLevelLimitReached || userLevelLimitReached
-}
-
-// Inc increases the destLimiter and userLimiter counters.
-// If destID or userID passed is empty, we don't increment the counters.
-func (throttler *HandleT) Inc(destID, userID string, currentTime time.Time) {
-	if throttler.destLimiter.enabled && destID != "" {
-		destKey := throttler.getDestKey(destID)
-		_ = throttler.destLimiter.ratelimiter.Inc(destKey, currentTime)
-	}
-	if throttler.userLimiter.enabled && userID != "" {
-		userKey := throttler.getUserKey(destID, userID)
-		_ = throttler.userLimiter.ratelimiter.Inc(userKey, currentTime)
-	}
+	return false, ""
 }
 
 // Dec decrements the destLimiter and userLimiter counters by count passed
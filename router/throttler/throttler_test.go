@@ -0,0 +1,72 @@
+package throttler_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/router/throttler"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+	"github.com/rudderlabs/rudder-server/utils/ratelimiter"
+)
+
+func TestThrottlerSharedStore(t *testing.T) {
+	t.Setenv("RSERVER_ROUTER_THROTTLER_WEBHOOK_LIMIT", "10")
+	t.Setenv("RSERVER_ROUTER_THROTTLER_WEBHOOK_TIME_WINDOW", "60s")
+	t.Setenv("RSERVER_ROUTER_THROTTLER_WEBHOOK_USER_LEVEL_LIMIT", "3")
+	t.Setenv("RSERVER_ROUTER_THROTTLER_WEBHOOK_USER_LEVEL_TIME_WINDOW", "60s")
+	config.Load()
+	logger.Init()
+
+	store := ratelimiter.NewMemoryCounterStore()
+	var node1, node2 throttler.HandleT
+	node1.SetUpWithStore("WEBHOOK", store)
+	node2.SetUpWithStore("WEBHOOK", store)
+	require.True(t, node1.IsDestLevelEnabled())
+	require.True(t, node1.IsUserLevelEnabled())
+
+	now := time.Now()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := map[string]int{}
+	for _, node := range []*throttler.HandleT{&node1, &node2} {
+		for _, userID := range []string{"u1", "u2", "u3", "u4", "u5"} {
+			wg.Add(1)
+			go func(node *throttler.HandleT, userID string) {
+				defer wg.Done()
+				for i := 0; i < 5; i++ {
+					if limitReached, _ := node.CheckLimitReachedAndInc("d1", userID, now); !limitReached {
+						mu.Lock()
+						allowed[userID]++
+						mu.Unlock()
+					}
+				}
+			}(node, userID)
+		}
+	}
+	wg.Wait()
+	var total int
+	for userID, count := range allowed {
+		require.LessOrEqual(t, count, 3, "user %s exceeded its limit", userID)
+		total += count
+	}
+	require.Equal(t, 10, total, "the jobs of both nodes count towards the limit, without exceeding it")
+
+	limitReached, atLevel := node1.CheckLimitReachedAndInc("d1", "u6", now)
+	require.True(t, limitReached)
+	require.Equal(t, throttler.DESTINATION_LEVEL, atLevel)
+	limitReached, _ = node2.CheckLimitReachedAndInc("d2", "u1", now)
+	require.False(t, limitReached)
+
+	node2.Dec("d1", "u6", 1, now, throttler.DESTINATION_LEVEL)
+	limitReached, _ = node1.CheckLimitReachedAndInc("d1", "u6", now)
+	require.False(t, limitReached)
+
+	var local throttler.HandleT
+	local.SetUpWithStore("WEBHOOK", nil)
+	limitReached, _ = local.CheckLimitReachedAndInc("d1", "u7", now)
+	require.False(t, limitReached, "a node with a local store applies the limit on its own")
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"regexp"
+	"time"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/utils/timeutil"
+)
+
+const (
+	// DeliveryWindows is the destination setting with the windows of the day jobs are delivered in
+	DeliveryWindows = "deliveryWindows"
+	// BlackoutWindows is the destination setting with the windows of the day jobs are not delivered in
+	BlackoutWindows = "blackoutWindows"
+
+	windowStartTime = "startTime"
+	windowEndTime   = "endTime"
+)
+
+// WindowT is a window of every day, from start (inclusive) to end (exclusive) in minutes of the day in UTC.
+// A window starting after its end spans midnight, e.g. 22:00-02:00.
+type WindowT struct {
+	Start int
+	End   int
+}
+
+// Contains returns whether t is in the window
+func (w WindowT) Contains(t time.Time) bool {
+	t = t.UTC()
+	mins := t.Hour()*60 + t.Minute()
+	if w.Start <= w.End {
+		return w.Start <= mins && mins < w.End
+	}
+	return w.Start <= mins || mins < w.End
+}
+
+// DeliveryWindowsT are the windows of the day jobs of a destination are delivered in: within any of its windows,
+// if it has some, and outside all of its blackouts
+type DeliveryWindowsT struct {
+	Windows   []WindowT
+	Blackouts []WindowT
+}
+
+// timeOfDay matches the HH:MM times of the windows
+var timeOfDay = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// GetDeliveryWindows returns the delivery windows of a destination from its deliveryWindows & blackoutWindows settings,
+// lists of {"startTime": "HH:MM", "endTime": "HH:MM"} in UTC. Invalid windows are logged and ignored.
+func GetDeliveryWindows(destination backendconfig.DestinationT) DeliveryWindowsT {
+	return DeliveryWindowsT{
+		Windows:   parseWindows(destination.ID, DeliveryWindows, destination.Config[DeliveryWindows]),
+		Blackouts: parseWindows(destination.ID, BlackoutWindows, destination.Config[BlackoutWindows]),
+	}
+}
+
+func parseWindows(destID, setting string, value interface{}) []WindowT {
+	if value == nil {
+		return nil
+	}
+	values, ok := value.([]interface{})
+	if !ok {
+		pkgLogger.Errorf("Ignoring %s of destination %s: expected a list of windows, got %v", setting, destID, value)
+		return nil
+	}
+	var windows []WindowT
+	for _, value := range values {
+		window, ok := value.(map[string]interface{})
+		if !ok {
+			pkgLogger.Errorf("Ignoring window %v in %s of destination %s: expected a window with a startTime and an endTime", value, setting, destID)
+			continue
+		}
+		startTime, _ := window[windowStartTime].(string)
+		endTime, _ := window[windowEndTime].(string)
+		if !timeOfDay.MatchString(startTime) || !timeOfDay.MatchString(endTime) {
+			pkgLogger.Errorf("Ignoring window %v in %s of destination %s: startTime and endTime must be HH:MM times in UTC", value, setting, destID)
+			continue
+		}
+		if startTime == endTime {
+			pkgLogger.Errorf("Ignoring window %v in %s of destination %s: the window is empty", value, setting, destID)
+			continue
+		}
+		windows = append(windows, WindowT{Start: timeutil.MinsOfDay(startTime), End: timeutil.MinsOfDay(endTime)})
+	}
+	return windows
+}
+
+// IsOpen returns whether jobs can be delivered at t
+func (dw DeliveryWindowsT) IsOpen(t time.Time) bool {
+	for _, blackout := range dw.Blackouts {
+		if blackout.Contains(t) {
+			return false
+		}
+	}
+	if len(dw.Windows) == 0 {
+		return true
+	}
+	for _, window := range dw.Windows {
+		if window.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+)
+
+func TestDeliveryWindows(t *testing.T) {
+	at := func(hhmm string) time.Time {
+		t, err := time.Parse("15:04", hhmm)
+		if err != nil {
+			panic(err)
+		}
+		return time.Date(2022, 6, 1, t.Hour(), t.Minute(), 0, 0, time.UTC)
+	}
+	windows := func(startEnd ...string) []interface{} {
+		var windows []interface{}
+		for i := 0; i < len(startEnd); i += 2 {
+			windows = append(windows, map[string]interface{}{"startTime": startEnd[i], "endTime": startEnd[i+1]})
+		}
+		return windows
+	}
+
+	tests := []struct {
+		name      string
+		config    map[string]interface{}
+		open      []string
+		closed    []string
+		windows   int
+		blackouts int
+	}{
+		{
+			name: "without windows",
+			open: []string{"00:00", "12:00", "23:59"},
+		},
+		{
+			name:      "with a blackout",
+			config:    map[string]interface{}{BlackoutWindows: windows("01:00", "03:00")},
+			open:      []string{"00:59", "03:00", "12:00"},
+			closed:    []string{"01:00", "02:59"},
+			blackouts: 1,
+		},
+		{
+			name:      "with a blackout spanning midnight",
+			config:    map[string]interface{}{BlackoutWindows: windows("22:00", "02:00")},
+			open:      []string{"02:00", "21:59"},
+			closed:    []string{"22:00", "23:59", "00:00", "01:59"},
+			blackouts: 1,
+		},
+		{
+			name:    "with delivery windows",
+			config:  map[string]interface{}{DeliveryWindows: windows("09:00", "12:00", "14:00", "17:00")},
+			open:    []string{"09:00", "11:59", "14:00", "16:59"},
+			closed:  []string{"08:59", "12:00", "13:59", "17:00", "23:00"},
+			windows: 2,
+		},
+		{
+			name: "with delivery windows and blackouts",
+			config: map[string]interface{}{
+				DeliveryWindows: windows("09:00", "17:00"),
+				BlackoutWindows: windows("12:00", "13:00"),
+			},
+			open:      []string{"09:00", "11:59", "13:00"},
+			closed:    []string{"08:59", "12:00", "12:59", "17:00"},
+			windows:   1,
+			blackouts: 1,
+		},
+		{
+			name:   "with invalid windows",
+			config: map[string]interface{}{DeliveryWindows: append(windows("09:00", "", "10:00", "10:00", "9:00", "17:00", "09:00", "24:00", "09:60", "17:00"), "09:00-17:00")},
+			open:   []string{"00:00", "12:00"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dw := GetDeliveryWindows(backendconfig.DestinationT{Config: tt.config})
+			require.Len(t, dw.Windows, tt.windows)
+			require.Len(t, dw.Blackouts, tt.blackouts)
+			for _, hhmm := range tt.open {
+				require.True(t, dw.IsOpen(at(hhmm)), "open at %s", hhmm)
+			}
+			for _, hhmm := range tt.closed {
+				require.False(t, dw.IsOpen(at(hhmm)), "closed at %s", hhmm)
+			}
+		})
+	}
+}
@@ -2,29 +2,40 @@ package utils
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"strings"
 	"time"
 
 	"github.com/rudderlabs/rudder-server/config"
 	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
 	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/utils/logger"
 	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/utils/types"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
 )
 
 var (
 	JobRetention time.Duration
-	EmptyPayload = []byte(`{}`)
+	JobTTL       time.Duration
+	EmptyPayload                = []byte(`{}`)
+	pkgLogger    logger.LoggerI = logger.NOP{}
 )
 
 const (
 	DRAIN_ERROR_CODE int = 410
+
+	// UserIDOrderingKey orders the jobs of a destination per userId, the default
+	UserIDOrderingKey = "userId"
+	// NoneOrderingKey doesn't order the jobs of a destination
+	NoneOrderingKey = "none"
 )
 
 type BatchDestinationT struct {
-	Destination backendconfig.DestinationT
-	Sources     []backendconfig.SourceT
+	Destination     backendconfig.DestinationT
+	Sources         []backendconfig.SourceT
+	DeliveryWindows DeliveryWindowsT
 }
 
 type DrainStats struct {
@@ -37,14 +48,18 @@ type SendPostResponse struct {
 	StatusCode          int
 	ResponseContentType string
 	ResponseBody        []byte
+	RetryAfter          time.Duration // how long the destination asked to wait before sending more requests, 0 if it didn't
 }
 
 func Init() {
 	loadConfig()
+	pkgLogger = logger.NewLogger().Child("router").Child("utils")
 }
 
 func loadConfig() {
 	config.RegisterDurationConfigVariable(720, &JobRetention, true, time.Hour, "Router.jobRetention")
+	// default value is '0', which means router jobs never expire
+	config.RegisterDurationConfigVariable(0, &JobTTL, true, time.Second, []string{"Router.jobTTL", "Router.jobTTLInS"}...)
 }
 
 func getRetentionTimeForDestination(destID string) time.Duration {
@@ -56,6 +71,64 @@ func getRetentionTimeForDestination(destID string) time.Duration {
 	return JobRetention
 }
 
+func getJobTTLForDestination(destID string) time.Duration {
+	if config.IsSet("Router." + destID + ".jobTTL") {
+		return config.GetDuration("Router."+destID+".jobTTL", 0, time.Second)
+	}
+	return JobTTL
+}
+
+// JobExpiryTime returns the expiry time of a new router job for the destination, i.e. the earliest between the
+// destination's job ttl and the expiry time of the job the event originates from. A zero time means that the job never expires.
+func JobExpiryTime(destID string, createdAt, sourceJobExpiryTime time.Time) time.Time {
+	expiryTime := sourceJobExpiryTime
+	if ttl := getJobTTLForDestination(destID); ttl > 0 {
+		if destExpiryTime := createdAt.Add(ttl); expiryTime.IsZero() || destExpiryTime.Before(expiryTime) {
+			expiryTime = destExpiryTime
+		}
+	}
+	return expiryTime
+}
+
+// JobPriority returns the priority of router jobs for events of the source and event type, as configured by
+// Router.priority.sources.<sourceID> or, if not set, by Router.priority.eventTypes.<eventType>, i.e. high, normal or low.
+// Jobs get a normal priority if neither is set or the configured value is invalid.
+func JobPriority(sourceID, eventType string) int {
+	for _, key := range []string{"Router.priority.sources." + sourceID, "Router.priority.eventTypes." + eventType} {
+		if config.IsSet(key) {
+			priority, _ := jobsdb.ParsePriority(config.GetString(key, ""))
+			return priority
+		}
+	}
+	return jobsdb.NormalPriority
+}
+
+// OrderingKey returns the ordering key of the jobs of the destination, i.e. the orderingKey of its config or, if not set,
+// Router.<DEST_TYPE>.orderingKey: either userId, none or a json path over the event.
+func OrderingKey(destination *backendconfig.DestinationT) string {
+	if key, ok := destination.Config["orderingKey"].(string); ok && key != "" {
+		return key
+	}
+	destType := destination.DestinationDefinition.Name
+	if config.IsSet("Router." + destType + ".orderingKey") {
+		return config.GetString("Router."+destType+".orderingKey", UserIDOrderingKey)
+	}
+	return config.GetString("Router.orderingKey", UserIDOrderingKey)
+}
+
+// OrderingKeyValue returns the value of a json path ordering key for an event, before it gets transformed for the destination.
+// It is empty for the userId & none ordering keys, or if the event has no value for the path.
+func OrderingKeyValue(orderingKey string, event types.SingularEventT) string {
+	if orderingKey == UserIDOrderingKey || orderingKey == NoneOrderingKey {
+		return ""
+	}
+	rawEvent, err := json.Marshal(event)
+	if err != nil {
+		return ""
+	}
+	return gjson.GetBytes(rawEvent, orderingKey).String()
+}
+
 func ToBeDrained(job *jobsdb.JobT, destID, toAbortDestinationIDs string, destinationsMap map[string]*BatchDestinationT) (bool, string) {
 	// drain if job is older than a day
 	jobReceivedAt := gjson.GetBytes(job.Parameters, "received_at")
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/config"
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/utils/types"
+)
+
+func TestOrderingKey(t *testing.T) {
+	t.Setenv("RSERVER_ROUTER_WEBHOOK_ORDERING_KEY", "groupId")
+	config.Load()
+
+	destination := func(destType string, destConfig map[string]interface{}) *backendconfig.DestinationT {
+		return &backendconfig.DestinationT{Config: destConfig, DestinationDefinition: backendconfig.DestinationDefinitionT{Name: destType}}
+	}
+	require.Equal(t, UserIDOrderingKey, OrderingKey(destination("AM", nil)))
+	require.Equal(t, "groupId", OrderingKey(destination("WEBHOOK", nil)))
+	require.Equal(t, NoneOrderingKey, OrderingKey(destination("WEBHOOK", map[string]interface{}{"orderingKey": NoneOrderingKey})))
+
+	event := types.SingularEventT{"userId": "u1", "context": map[string]interface{}{"traits": map[string]interface{}{"accountId": "a1"}}}
+	require.Equal(t, "a1", OrderingKeyValue("context.traits.accountId", event))
+	require.Empty(t, OrderingKeyValue("groupId", event))
+	require.Empty(t, OrderingKeyValue(UserIDOrderingKey, event), "userId ordering uses the user id of the job")
+}
@@ -27,6 +27,7 @@ type DedupI interface {
 var (
 	dedupWindow  time.Duration
 	memOptimized bool
+	dedupMode    string
 	pkgLogger    logger.LoggerI
 )
 
@@ -39,6 +40,8 @@ func loadConfig() {
 	// Dedup time window in hours
 	config.RegisterDurationConfigVariable(3600, &dedupWindow, true, time.Second, []string{"Dedup.dedupWindow", "Dedup.dedupWindowInS"}...)
 	config.RegisterBoolConfigVariable(true, &memOptimized, false, "Dedup.memOptimized")
+	// Where processed messageIDs are kept: badger (local disk) or postgres (shared by all the nodes using the same jobsdb)
+	config.RegisterStringConfigVariable("badger", &dedupMode, false, "Dedup.mode")
 }
 
 type loggerForBadger struct {
@@ -58,48 +61,58 @@ func DefaultRudderPath() string {
 	return fmt.Sprintf(`%v%v`, tmpDirPath, badgerPathName)
 }
 
-type OptFn func(*DedupHandleT)
+// dedupOptions are the options common to all dedup stores
+type dedupOptions struct {
+	window  *time.Duration
+	clearDB bool
+}
+
+type OptFn func(*dedupOptions)
 
 func FromConfig() OptFn {
-	return func(dht *DedupHandleT) {
-		dht.window = &dedupWindow
+	return func(o *dedupOptions) {
+		o.window = &dedupWindow
 	}
 }
 
 func WithWindow(d time.Duration) OptFn {
-	return func(dht *DedupHandleT) {
-		dht.window = &d
+	return func(o *dedupOptions) {
+		o.window = &d
 	}
 }
 
 func WithClearDB() OptFn {
-	return func(dht *DedupHandleT) {
-		dht.clearDB = true
+	return func(o *dedupOptions) {
+		o.clearDB = true
+	}
+}
+
+func newOptions(fns ...OptFn) dedupOptions {
+	o := dedupOptions{window: &dedupWindow}
+	for _, fn := range fns {
+		fn(&o)
 	}
+	return o
 }
 
 type DedupHandleT struct {
+	dedupOptions
 	stats    stats.Stats
 	logger   loggerForBadger
 	badgerDB *badger.DB
-	window   *time.Duration
 	close    chan struct{}
 	gcDone   chan struct{}
 	path     string
-	clearDB  bool
 }
 
 func New(path string, fns ...OptFn) *DedupHandleT {
 	d := &DedupHandleT{
-		path:   path,
-		logger: loggerForBadger{logger.NewLogger().Child("dedup")},
-		stats:  stats.DefaultStats,
-		gcDone: make(chan struct{}),
-		close:  make(chan struct{}),
-		window: &dedupWindow,
-	}
-	for _, fn := range fns {
-		fn(d)
+		dedupOptions: newOptions(fns...),
+		path:         path,
+		logger:       loggerForBadger{logger.NewLogger().Child("dedup")},
+		stats:        stats.DefaultStats,
+		gcDone:       make(chan struct{}),
+		close:        make(chan struct{}),
 	}
 	d.openBadger()
 
@@ -197,6 +210,26 @@ func (d *DedupHandleT) MarkProcessed(messageIDs []string) error {
 }
 
 func (d *DedupHandleT) FindDuplicates(messageIDs []string, allMessageIDsSet map[string]struct{}) (duplicateIndexes []int) {
+	toRemoveMessageIndexesSet := findDuplicatesInBatch(messageIDs, allMessageIDsSet)
+
+	// Dedup with badgerDB
+	err := d.badgerDB.View(func(txn *badger.Txn) error {
+		for idx, messageID := range messageIDs {
+			_, err := txn.Get([]byte(messageID))
+			if err != badger.ErrKeyNotFound {
+				toRemoveMessageIndexesSet[idx] = struct{}{}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return sortedIndexes(toRemoveMessageIndexesSet)
+}
+
+// findDuplicatesInBatch returns the indexes of the messageIDs found earlier in messageIDs, or in allMessageIDsSet
+func findDuplicatesInBatch(messageIDs []string, allMessageIDsSet map[string]struct{}) map[int]struct{} {
 	toRemoveMessageIndexesSet := make(map[int]struct{})
 	// Dedup within events batch in a web request
 	messageIDSet := make(map[string]struct{})
@@ -222,26 +255,17 @@ func (d *DedupHandleT) FindDuplicates(messageIDs []string, allMessageIDsSet map[
 		}
 	}
 
-	// Dedup with badgerDB
-	err := d.badgerDB.View(func(txn *badger.Txn) error {
-		for idx, messageID := range messageIDs {
-			_, err := txn.Get([]byte(messageID))
-			if err != badger.ErrKeyNotFound {
-				toRemoveMessageIndexesSet[idx] = struct{}{}
-			}
-		}
-		return nil
-	})
-	if err != nil {
-		panic(err)
-	}
-	toRemoveMessageIndexes := make([]int, 0, len(toRemoveMessageIndexesSet))
-	for k := range toRemoveMessageIndexesSet {
-		toRemoveMessageIndexes = append(toRemoveMessageIndexes, k)
+	return toRemoveMessageIndexesSet
+}
+
+func sortedIndexes(indexesSet map[int]struct{}) []int {
+	indexes := make([]int, 0, len(indexesSet))
+	for k := range indexesSet {
+		indexes = append(indexes, k)
 	}
 
-	sort.Ints(toRemoveMessageIndexes)
-	return toRemoveMessageIndexes
+	sort.Ints(indexes)
+	return indexes
 }
 
 func (d *DedupHandleT) Close() {
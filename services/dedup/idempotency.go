@@ -0,0 +1,102 @@
+package dedup
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/rruntime"
+)
+
+// ErrIdempotencyKeyInUse is returned for idempotency keys of requests which are still being processed
+var ErrIdempotencyKeyInUse = errors.New("idempotency key is in use by a request being processed")
+
+// idempotencyKeyLease is the time an idempotency key is reserved for, while its request is being processed.
+// It is released after that, in case the node processing the request is gone.
+const idempotencyKeyLease = time.Minute
+
+/*
+IdempotencyStore keeps the responses of requests by their idempotency key in the dedup_idempotency_keys postgres table,
+so that replays of a request within the dedup window get the response of the original request, no matter which node
+of the cluster processed it.
+*/
+type IdempotencyStore struct {
+	dedupOptions
+	db          *sql.DB
+	close       chan struct{}
+	cleanupDone chan struct{}
+}
+
+// NewIdempotencyStore returns an IdempotencyStore using the database, creating its table if needed.
+// The store takes ownership of the database, which is closed by Close.
+func NewIdempotencyStore(db *sql.DB, fns ...OptFn) (*IdempotencyStore, error) {
+	s := &IdempotencyStore{
+		dedupOptions: newOptions(fns...),
+		db:           db,
+		close:        make(chan struct{}),
+		cleanupDone:  make(chan struct{}),
+	}
+	if err := setupExpiringTable(db, "dedup_idempotency_keys", `
+		key text not null primary key,
+		response text,
+		expires_at timestamp not null`, s.clearDB); err != nil {
+		return nil, fmt.Errorf("setting up dedup_idempotency_keys table: %w", err)
+	}
+	rruntime.Go(func() {
+		cleanupExpired(s.db, "dedup_idempotency_keys", s.close)
+		close(s.cleanupDone)
+	})
+	return s, nil
+}
+
+/*
+Begin reserves the idempotency key for a request about to be processed, which needs to be followed by either Complete or Release.
+
+If the key was already used by a request, the response of that request is returned along with found being true,
+unless the request is still being processed, in which case ErrIdempotencyKeyInUse is returned.
+*/
+func (s *IdempotencyStore) Begin(ctx context.Context, key string) (response string, found bool, err error) {
+	res, err := s.db.ExecContext(ctx, `insert into dedup_idempotency_keys (key, expires_at) values ($1, NOW() + $2 * interval '1 second')
+		on conflict (key) do update set response = null, expires_at = excluded.expires_at
+		where dedup_idempotency_keys.expires_at <= NOW()`, key, idempotencyKeyLease.Seconds())
+	if err != nil {
+		return "", false, err
+	}
+	if reserved, err := res.RowsAffected(); err != nil || reserved == 1 {
+		return "", false, err
+	}
+	var stored sql.NullString
+	err = s.db.QueryRowContext(ctx, `select response from dedup_idempotency_keys where key = $1`, key).Scan(&stored)
+	if errors.Is(err, sql.ErrNoRows) {
+		// expired & deleted in the meantime
+		return s.Begin(ctx, key)
+	}
+	if err != nil {
+		return "", false, err
+	}
+	if !stored.Valid {
+		return "", false, ErrIdempotencyKeyInUse
+	}
+	return stored.String, true, nil
+}
+
+// Complete stores the response of the request for the idempotency key, for dedupWindow
+func (s *IdempotencyStore) Complete(ctx context.Context, key, response string) error {
+	_, err := s.db.ExecContext(ctx, `update dedup_idempotency_keys set response = $2, expires_at = NOW() + $3 * interval '1 second' where key = $1`,
+		key, response, s.window.Seconds())
+	return err
+}
+
+// Release releases the idempotency key without storing a response, e.g. when the request failed for a reason worth retrying
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, `delete from dedup_idempotency_keys where key = $1 and response is null`, key)
+	return err
+}
+
+func (s *IdempotencyStore) Close() {
+	close(s.close)
+	<-s.cleanupDone
+	_ = s.db.Close()
+}
@@ -0,0 +1,138 @@
+package dedup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/rudderlabs/rudder-server/rruntime"
+)
+
+const postgresCleanupInterval = 5 * time.Minute
+
+// PostgresDedupHandleT is a DedupI keeping the messageIDs marked as processed in a postgres table, so that they are
+// shared by all the nodes using the same database and survive the loss of a node.
+type PostgresDedupHandleT struct {
+	dedupOptions
+	db          *sql.DB
+	close       chan struct{}
+	cleanupDone chan struct{}
+}
+
+// NewPostgres returns a DedupI using the dedup_message_ids table of the database, creating it if needed.
+// The handle takes ownership of the database, which is closed by Close.
+func NewPostgres(db *sql.DB, fns ...OptFn) (*PostgresDedupHandleT, error) {
+	d := &PostgresDedupHandleT{
+		dedupOptions: newOptions(fns...),
+		db:           db,
+		close:        make(chan struct{}),
+		cleanupDone:  make(chan struct{}),
+	}
+	if err := setupExpiringTable(db, "dedup_message_ids", `
+		message_id text not null primary key,
+		expires_at timestamp not null`, d.clearDB); err != nil {
+		return nil, fmt.Errorf("setting up dedup_message_ids table: %w", err)
+	}
+	rruntime.Go(func() {
+		cleanupExpired(d.db, "dedup_message_ids", d.close)
+		close(d.cleanupDone)
+	})
+	return d, nil
+}
+
+// MarkProcessed persists messageIDs in the database, with expiry time of dedupWindow
+func (d *PostgresDedupHandleT) MarkProcessed(messageIDs []string) error {
+	_, err := d.db.Exec(`insert into dedup_message_ids (message_id, expires_at)
+		select distinct unnest($1::text[]), NOW() + $2 * interval '1 second'
+		on conflict (message_id) do update set expires_at = excluded.expires_at`,
+		pq.Array(messageIDs), d.window.Seconds())
+	return err
+}
+
+func (d *PostgresDedupHandleT) FindDuplicates(messageIDs []string, allMessageIDsSet map[string]struct{}) (duplicateIndexes []int) {
+	toRemoveMessageIndexesSet := findDuplicatesInBatch(messageIDs, allMessageIDsSet)
+
+	rows, err := d.db.Query(`select message_id from dedup_message_ids where message_id = any($1) and expires_at > NOW()`, pq.Array(messageIDs))
+	if err != nil {
+		panic(err)
+	}
+	defer func() { _ = rows.Close() }()
+	processed := make(map[string]struct{})
+	for rows.Next() {
+		var messageID string
+		if err := rows.Scan(&messageID); err != nil {
+			panic(err)
+		}
+		processed[messageID] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		panic(err)
+	}
+	for idx, messageID := range messageIDs {
+		if _, ok := processed[messageID]; ok {
+			toRemoveMessageIndexesSet[idx] = struct{}{}
+		}
+	}
+	return sortedIndexes(toRemoveMessageIndexesSet)
+}
+
+func (d *PostgresDedupHandleT) PrintHistogram() {
+	var count int64
+	if err := d.db.QueryRow(`select count(*) from dedup_message_ids where expires_at > NOW()`).Scan(&count); err != nil {
+		pkgLogger.Errorf("Failed to count message ids: %v", err)
+		return
+	}
+	pkgLogger.Infof("Message ids within the dedup window: %d", count)
+}
+
+func (d *PostgresDedupHandleT) Close() {
+	close(d.close)
+	<-d.cleanupDone
+	_ = d.db.Close()
+}
+
+// setupExpiringTable creates a table with the provided columns, which need to include an expires_at timestamp
+func setupExpiringTable(db *sql.DB, table, columns string, clearDB bool) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(fmt.Sprintf(`create table if not exists %q (%s)`, table, columns)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if _, err = tx.Exec(fmt.Sprintf(`create index if not exists %q on %q (expires_at)`, table+"_expires_at_idx", table)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if clearDB {
+		if _, err = tx.Exec(fmt.Sprintf(`truncate table %q`, table)); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// cleanupExpired deletes the expired rows of the table periodically, until done gets closed
+func cleanupExpired(db *sql.DB, table string, done <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-done
+		cancel()
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(postgresCleanupInterval):
+		}
+		if _, err := db.ExecContext(ctx, fmt.Sprintf(`delete from %q where expires_at <= NOW()`, table)); err != nil && ctx.Err() == nil {
+			pkgLogger.Errorf("Failed to delete expired rows of %s: %v", table, err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package dedup_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/services/dedup"
+	"github.com/rudderlabs/rudder-server/testhelper/destination"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+)
+
+func setupPostgres(t *testing.T) *destination.PostgresResource {
+	config.Load()
+	logger.Init()
+
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+	postgresContainer, err := destination.SetupPostgres(pool, t)
+	require.NoError(t, err)
+	return postgresContainer
+}
+
+func openDB(t *testing.T, postgresContainer *destination.PostgresResource) *sql.DB {
+	db, err := sql.Open("postgres", postgresContainer.DBDsn)
+	require.NoError(t, err)
+	return db
+}
+
+func Test_Dedup_Postgres(t *testing.T) {
+	postgresContainer := setupPostgres(t)
+
+	d, err := dedup.NewPostgres(openDB(t, postgresContainer), dedup.WithClearDB(), dedup.WithWindow(time.Hour))
+	require.NoError(t, err)
+	defer d.Close()
+
+	t.Run("duplicate after marked as processed", func(t *testing.T) {
+		require.Equal(t, []int{}, d.FindDuplicates([]string{"a", "b", "c"}, nil))
+
+		require.NoError(t, d.MarkProcessed([]string{"a", "b", "c", "a"}))
+		require.Equal(t, []int{0, 2}, d.FindDuplicates([]string{"a", "d", "c"}, nil))
+		require.Equal(t, []int{1, 2}, d.FindDuplicates([]string{"x", "y", "a"}, map[string]struct{}{"y": {}}))
+	})
+
+	t.Run("duplicates are shared by handles of the same database", func(t *testing.T) {
+		other, err := dedup.NewPostgres(openDB(t, postgresContainer), dedup.WithWindow(time.Hour))
+		require.NoError(t, err)
+		defer other.Close()
+
+		require.NoError(t, other.MarkProcessed([]string{"e"}))
+		require.Equal(t, []int{0}, d.FindDuplicates([]string{"e"}, nil))
+	})
+
+	t.Run("no duplicate after the window", func(t *testing.T) {
+		short, err := dedup.NewPostgres(openDB(t, postgresContainer), dedup.WithWindow(time.Second))
+		require.NoError(t, err)
+		defer short.Close()
+
+		require.NoError(t, short.MarkProcessed([]string{"f"}))
+		require.Equal(t, []int{0}, short.FindDuplicates([]string{"f"}, nil))
+		require.Eventually(t, func() bool {
+			return len(short.FindDuplicates([]string{"f"}, nil)) == 0
+		}, 5*time.Second, 100*time.Millisecond)
+	})
+}
+
+func Test_IdempotencyStore(t *testing.T) {
+	postgresContainer := setupPostgres(t)
+	ctx := context.Background()
+
+	s, err := dedup.NewIdempotencyStore(openDB(t, postgresContainer), dedup.WithClearDB(), dedup.WithWindow(time.Hour))
+	require.NoError(t, err)
+	defer s.Close()
+
+	t.Run("replays get the stored response", func(t *testing.T) {
+		_, found, err := s.Begin(ctx, "key-1")
+		require.NoError(t, err)
+		require.False(t, found)
+
+		_, _, err = s.Begin(ctx, "key-1")
+		require.ErrorIs(t, err, dedup.ErrIdempotencyKeyInUse)
+
+		require.NoError(t, s.Complete(ctx, "key-1", "OK"))
+		response, found, err := s.Begin(ctx, "key-1")
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, "OK", response)
+	})
+
+	t.Run("released keys can be used again", func(t *testing.T) {
+		_, found, err := s.Begin(ctx, "key-2")
+		require.NoError(t, err)
+		require.False(t, found)
+		require.NoError(t, s.Release(ctx, "key-2"))
+
+		_, found, err = s.Begin(ctx, "key-2")
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+
+	t.Run("completed keys are not released", func(t *testing.T) {
+		require.NoError(t, s.Release(ctx, "key-1"))
+		_, found, err := s.Begin(ctx, "key-1")
+		require.NoError(t, err)
+		require.True(t, found)
+	})
+}
@@ -1,7 +1,10 @@
 package dedup
 
 import (
+	"database/sql"
 	"sync"
+
+	"github.com/rudderlabs/rudder-server/jobsdb"
 )
 
 var (
@@ -18,7 +21,18 @@ func GetInstance(clearDB *bool) DedupI {
 			opts = append(opts, WithClearDB())
 		}
 
-		dedupManager = New(DefaultRudderPath(), opts...)
+		switch dedupMode {
+		case "postgres":
+			db, err := sql.Open("postgres", jobsdb.GetConnectionString())
+			if err != nil {
+				panic(err)
+			}
+			if dedupManager, err = NewPostgres(db, opts...); err != nil {
+				panic(err)
+			}
+		default:
+			dedupManager = New(DefaultRudderPath(), opts...)
+		}
 	})
 
 	return dedupManager
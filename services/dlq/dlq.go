@@ -0,0 +1,386 @@
+package dlq
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/services/metric"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/utils/types"
+)
+
+//go:generate mockgen -destination=../../mocks/services/dlq/mock_dlq.go -package mock_dlq github.com/rudderlabs/rudder-server/services/dlq Service
+
+// ErrNotFound is returned for jobs which are not in the dead-letter queue, e.g. because they got re-driven already
+var ErrNotFound = errors.New("job not found in the dead-letter queue")
+
+// ErrInvalidRequest is returned for filters or payloads which cannot be used
+var ErrInvalidRequest = errors.New("invalid request")
+
+// parameters added to the parameters of the aborted jobs stored in the dead-letter queue
+const (
+	stageParam         = "stage"
+	reasonParam        = "reason"
+	errorCodeParam     = "error_code"
+	errorResponseParam = "error_response"
+	abortedJobIDParam  = "aborted_job_id"
+	editOfParam        = "edit_of"
+)
+
+var dlqParams = []string{stageParam, reasonParam, errorCodeParam, errorResponseParam, abortedJobIDParam, editOfParam}
+
+// IsEnabled returns whether jobs aborted by the routers are kept in the dead-letter queue
+func IsEnabled() bool {
+	return config.GetBool("DLQ.enabled", false)
+}
+
+// NewJob returns the job to be stored in the dead-letter queue for a job aborted at stage (router or batch router)
+func NewJob(job *jobsdb.JobT, stage, errorCode string, errorResponse json.RawMessage) *jobsdb.JobT {
+	parameters := job.Parameters
+	parameters, _ = sjson.SetBytes(parameters, stageParam, stage)
+	parameters, _ = sjson.SetBytes(parameters, errorCodeParam, errorCode)
+	parameters, _ = sjson.SetBytes(parameters, abortedJobIDParam, job.JobID)
+	if len(errorResponse) > 0 && json.Valid(errorResponse) {
+		parameters, _ = sjson.SetRawBytes(parameters, errorResponseParam, errorResponse)
+	}
+	return &jobsdb.JobT{
+		UUID:         uuid.Must(uuid.NewV4()),
+		UserID:       job.UserID,
+		Parameters:   parameters,
+		CustomVal:    job.CustomVal,
+		EventPayload: job.EventPayload,
+		EventCount:   job.EventCount,
+		WorkspaceId:  job.WorkspaceId,
+		Priority:     job.Priority,
+	}
+}
+
+// Event is a job of the dead-letter queue
+type Event struct {
+	JobID         int64           `json:"jobId"`
+	AbortedJobID  int64           `json:"abortedJobId"`
+	Stage         string          `json:"stage"`
+	DestType      string          `json:"destType"`
+	DestinationID string          `json:"destinationId"`
+	SourceID      string          `json:"sourceId"`
+	WorkspaceID   string          `json:"workspaceId"`
+	UserID        string          `json:"userId"`
+	ErrorCode     string          `json:"errorCode"`
+	ErrorResponse json.RawMessage `json:"errorResponse,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+func newEvent(job *jobsdb.JobT) Event {
+	event := Event{
+		JobID:         job.JobID,
+		AbortedJobID:  gjson.GetBytes(job.Parameters, abortedJobIDParam).Int(),
+		Stage:         gjson.GetBytes(job.Parameters, stageParam).String(),
+		DestType:      job.CustomVal,
+		DestinationID: gjson.GetBytes(job.Parameters, "destination_id").String(),
+		SourceID:      gjson.GetBytes(job.Parameters, "source_id").String(),
+		WorkspaceID:   job.WorkspaceId,
+		UserID:        job.UserID,
+		ErrorCode:     gjson.GetBytes(job.Parameters, errorCodeParam).String(),
+		Payload:       job.EventPayload,
+		CreatedAt:     job.CreatedAt,
+	}
+	if errorResponse := gjson.GetBytes(job.Parameters, errorResponseParam); errorResponse.Exists() {
+		event.ErrorResponse = json.RawMessage(errorResponse.Raw)
+	}
+	return event
+}
+
+// Filter of the events listed, empty fields match all events
+type Filter struct {
+	DestType      string
+	DestinationID string
+	ErrorCode     string
+	// Only events with a job id greater than AfterJobID are listed, for paginating through them
+	AfterJobID int64
+	Limit      int
+}
+
+// Service browses, edits & re-drives the events of the dead-letter queue
+type Service interface {
+	// List returns the events matching the filter, in job id order
+	List(ctx context.Context, filter Filter) ([]Event, error)
+	// Get returns the event of the job
+	Get(ctx context.Context, jobID int64) (Event, error)
+	// Edit replaces the payload of the event of the job, returning the edited event which supersedes it
+	Edit(ctx context.Context, jobID int64, payload json.RawMessage) (Event, error)
+	// Redrive stores the events of the jobs back into the jobsdb of the router they were aborted by
+	Redrive(ctx context.Context, jobIDs []int64) error
+}
+
+type reporter interface {
+	Report(metrics []*types.PUReportedMetric, txn *sql.Tx)
+}
+
+type service struct {
+	db        jobsdb.JobsDB
+	routerDBs map[string]jobsdb.JobsDB // stage -> jobsdb events get re-driven into
+	reporting reporter
+	redriveMu sync.Mutex // serializes the re-drives of a dead-letter queue without a postgres transaction to lock
+}
+
+// NewService returns the Service of the dead-letter queue db, re-driving events into the router & batch router jobsdbs
+func NewService(db, routerDB, batchRouterDB jobsdb.JobsDB, reporting reporter) Service {
+	return &service{
+		db: db,
+		routerDBs: map[string]jobsdb.JobsDB{
+			types.ROUTER:       routerDB,
+			types.BATCH_ROUTER: batchRouterDB,
+		},
+		reporting: reporting,
+	}
+}
+
+// filterValue matches the values filters can have, as they end up in the query
+var filterValue = regexp.MustCompile(`^[A-Za-z0-9_\-]*$`)
+
+func (s *service) List(ctx context.Context, filter Filter) ([]Event, error) {
+	for _, value := range []string{filter.DestType, filter.DestinationID, filter.ErrorCode} {
+		if !filterValue.MatchString(value) {
+			return nil, fmt.Errorf("%w: invalid filter value %q", ErrInvalidRequest, value)
+		}
+	}
+	params := jobsdb.GetQueryParamsT{
+		AfterJobID: filter.AfterJobID,
+		JobsLimit:  filter.Limit,
+	}
+	if filter.DestType != "" {
+		params.CustomValFilters = []string{filter.DestType}
+	}
+	if filter.DestinationID != "" {
+		params.ParameterFilters = append(params.ParameterFilters, jobsdb.ParameterFilterT{Name: "destination_id", Value: filter.DestinationID})
+	}
+	if filter.ErrorCode != "" {
+		params.ParameterFilters = append(params.ParameterFilters, jobsdb.ParameterFilterT{Name: errorCodeParam, Value: filter.ErrorCode})
+	}
+	res, err := s.db.GetUnprocessed(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]Event, 0, len(res.Jobs))
+	for _, job := range res.Jobs {
+		events = append(events, newEvent(job))
+	}
+	return events, nil
+}
+
+func (s *service) Get(ctx context.Context, jobID int64) (Event, error) {
+	job, err := s.getJob(ctx, jobID)
+	if err != nil {
+		return Event{}, err
+	}
+	return newEvent(job), nil
+}
+
+// getJob returns the job if it is still in the dead-letter queue, i.e. it has no status yet
+func (s *service) getJob(ctx context.Context, jobID int64) (*jobsdb.JobT, error) {
+	res, err := s.db.GetUnprocessed(ctx, jobsdb.GetQueryParamsT{AfterJobID: jobID - 1, JobsLimit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Jobs) == 0 || res.Jobs[0].JobID != jobID {
+		return nil, ErrNotFound
+	}
+	return res.Jobs[0], nil
+}
+
+/*
+Edit stores a copy of the job with the new payload and marks the job as aborted, since jobs are immutable.
+
+The copy is stored first, so that a failure in between leaves both events in the dead-letter queue rather than none.
+*/
+func (s *service) Edit(ctx context.Context, jobID int64, payload json.RawMessage) (Event, error) {
+	if !json.Valid(payload) {
+		return Event{}, fmt.Errorf("%w: payload is not valid json", ErrInvalidRequest)
+	}
+	job, err := s.getJob(ctx, jobID)
+	if err != nil {
+		return Event{}, err
+	}
+	edited := *job
+	edited.UUID = uuid.Must(uuid.NewV4())
+	edited.EventPayload = payload
+	edited.Parameters, _ = sjson.SetBytes(job.Parameters, editOfParam, strconv.FormatInt(jobID, 10))
+	if err := s.db.Store(ctx, []*jobsdb.JobT{&edited}); err != nil {
+		return Event{}, fmt.Errorf("storing edited job: %w", err)
+	}
+	status := newStatus(job, jobsdb.Aborted.State, fmt.Sprintf(`{"reason":"edited","edit_of":%d}`, jobID))
+	if err := s.db.UpdateJobStatus(ctx, []*jobsdb.JobStatusT{status}, nil, nil); err != nil {
+		return Event{}, fmt.Errorf("marking job %d as edited: %w", jobID, err)
+	}
+	res, err := s.db.GetUnprocessed(ctx, jobsdb.GetQueryParamsT{
+		AfterJobID:       jobID,
+		ParameterFilters: []jobsdb.ParameterFilterT{{Name: editOfParam, Value: strconv.FormatInt(jobID, 10)}},
+		JobsLimit:        1,
+	})
+	if err != nil {
+		return Event{}, err
+	}
+	if len(res.Jobs) == 0 {
+		return Event{}, ErrNotFound
+	}
+	return newEvent(res.Jobs[0]), nil
+}
+
+/*
+Redrive stores copies of the jobs into the jobsdb of their stage, without the parameters added by the dead-letter queue,
+and marks them as succeeded, reporting them. The copies are stored before the transaction marking the jobs commits,
+and re-drives are serialized, so that every job is re-driven once, unless that transaction fails to commit.
+*/
+func (s *service) Redrive(ctx context.Context, jobIDs []int64) error {
+	// re-drives are serialized, so that the jobs re-driven concurrently are not found in the dead-letter queue anymore
+	return s.db.WithTx(func(lockTx *sql.Tx) error {
+		if lockTx != nil {
+			if _, err := lockTx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, misc.DLQRedriveAdvisoryLock); err != nil {
+				return fmt.Errorf("locking the dead-letter queue for re-drive: %w", err)
+			}
+		} else {
+			s.redriveMu.Lock()
+			defer s.redriveMu.Unlock()
+		}
+		return s.redrive(ctx, jobIDs)
+	})
+}
+
+// redrive needs to be called holding the re-drive lock
+func (s *service) redrive(ctx context.Context, jobIDs []int64) error {
+	jobs := make([]*jobsdb.JobT, 0, len(jobIDs))
+	for _, jobID := range jobIDs {
+		job, err := s.getJob(ctx, jobID)
+		if err != nil {
+			return fmt.Errorf("job %d: %w", jobID, err)
+		}
+		if _, ok := s.routerDBs[gjson.GetBytes(job.Parameters, stageParam).String()]; !ok {
+			return fmt.Errorf("%w: job %d: cannot re-drive jobs of stage %q", ErrInvalidRequest, jobID, gjson.GetBytes(job.Parameters, stageParam).String())
+		}
+		jobs = append(jobs, job)
+	}
+
+	statuses := make([]*jobsdb.JobStatusT, 0, len(jobs))
+	for _, job := range jobs {
+		statuses = append(statuses, newStatus(job, jobsdb.Succeeded.State, `{"reason":"re-driven"}`))
+	}
+	// the copies are stored before the jobs marked as re-driven get committed, so that a failure to store them leaves them
+	// in the dead-letter queue
+	return s.db.WithUpdateSafeTx(func(tx jobsdb.UpdateSafeTx) error {
+		if err := s.db.UpdateJobStatusInTx(ctx, tx, statuses, nil, nil); err != nil {
+			return fmt.Errorf("marking jobs as re-driven: %w", err)
+		}
+		if err := s.store(ctx, jobs); err != nil {
+			return err
+		}
+		s.reporting.Report(redriveMetrics(jobs), tx.Tx())
+		return nil
+	})
+}
+
+// store stores the re-driven jobs into the jobsdb of their router
+func (s *service) store(ctx context.Context, jobs []*jobsdb.JobT) error {
+	redrivenJobs := make(map[string][]*jobsdb.JobT)
+	for _, job := range jobs {
+		stage := gjson.GetBytes(job.Parameters, stageParam).String()
+		redrivenJobs[stage] = append(redrivenJobs[stage], redrivenJob(job))
+	}
+	for stage, stageJobs := range redrivenJobs {
+		if err := s.routerDBs[stage].Store(ctx, stageJobs); err != nil {
+			return fmt.Errorf("storing re-driven jobs into %s: %w", stage, err)
+		}
+		pendingEvents := make(map[string]map[string]int) // workspace -> destType -> count
+		for _, job := range stageJobs {
+			if pendingEvents[job.WorkspaceId] == nil {
+				pendingEvents[job.WorkspaceId] = make(map[string]int)
+			}
+			pendingEvents[job.WorkspaceId][job.CustomVal]++
+		}
+		tablePrefix := map[string]string{types.ROUTER: "rt", types.BATCH_ROUTER: "batch_rt"}[stage]
+		for workspace, counts := range pendingEvents {
+			for destType, count := range counts {
+				metric.IncreasePendingEvents(tablePrefix, workspace, destType, float64(count))
+			}
+		}
+	}
+	return nil
+}
+
+// redrivenJob returns a copy of the job for re-driving it, without the parameters of the dead-letter queue
+func redrivenJob(job *jobsdb.JobT) *jobsdb.JobT {
+	parameters := job.Parameters
+	for _, param := range dlqParams {
+		parameters, _ = sjson.DeleteBytes(parameters, param)
+	}
+	return &jobsdb.JobT{
+		UUID:         uuid.Must(uuid.NewV4()),
+		UserID:       job.UserID,
+		Parameters:   parameters,
+		CustomVal:    job.CustomVal,
+		EventPayload: job.EventPayload,
+		EventCount:   job.EventCount,
+		WorkspaceId:  job.WorkspaceId,
+		Priority:     job.Priority,
+	}
+}
+
+// redriveMetrics returns the reporting metrics of the re-driven jobs, as succeeded from the dead-letter queue into their stage
+func redriveMetrics(jobs []*jobsdb.JobT) []*types.PUReportedMetric {
+	metrics := make(map[string]*types.PUReportedMetric)
+	var keys []string
+	for _, job := range jobs {
+		params := gjson.ParseBytes(job.Parameters)
+		stage := params.Get(stageParam).String()
+		eventName, eventType := params.Get("event_name").String(), params.Get("event_type").String()
+		key := fmt.Sprintf("%s:%s:%s:%s:%s:%s", params.Get("source_id"), params.Get("destination_id"), params.Get("source_batch_id"), stage, eventName, eventType)
+		m, ok := metrics[key]
+		if !ok {
+			m = &types.PUReportedMetric{
+				ConnectionDetails: *types.CreateConnectionDetail(
+					params.Get("source_id").String(), params.Get("destination_id").String(), params.Get("source_batch_id").String(),
+					params.Get("source_task_id").String(), params.Get("source_task_run_id").String(), params.Get("source_job_id").String(),
+					params.Get("source_job_run_id").String(), params.Get("source_definition_id").String(),
+					params.Get("destination_definition_id").String(), params.Get("source_category").String(),
+				),
+				PUDetails:    *types.CreatePUDetails(types.DLQ, stage, false, false),
+				StatusDetail: types.CreateStatusDetail(jobsdb.Succeeded.State, 0, 200, `{"reason":"re-driven"}`, job.EventPayload, eventName, eventType),
+			}
+			metrics[key] = m
+			keys = append(keys, key)
+		}
+		m.StatusDetail.Count++
+	}
+	reportMetrics := make([]*types.PUReportedMetric, 0, len(keys))
+	for _, key := range keys {
+		reportMetrics = append(reportMetrics, metrics[key])
+	}
+	return reportMetrics
+}
+
+func newStatus(job *jobsdb.JobT, state, errorResponse string) *jobsdb.JobStatusT {
+	return &jobsdb.JobStatusT{
+		JobID:         job.JobID,
+		AttemptNum:    1,
+		JobState:      state,
+		ExecTime:      time.Now(),
+		RetryTime:     time.Now(),
+		ErrorCode:     "",
+		ErrorResponse: []byte(errorResponse),
+		Parameters:    []byte(`{}`),
+		WorkspaceId:   job.WorkspaceId,
+	}
+}
@@ -0,0 +1,208 @@
+package dlq_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	mocksJobsDB "github.com/rudderlabs/rudder-server/mocks/jobsdb"
+	mock_types "github.com/rudderlabs/rudder-server/mocks/utils/types"
+	"github.com/rudderlabs/rudder-server/services/dlq"
+	"github.com/rudderlabs/rudder-server/utils/types"
+)
+
+func abortedJob() *jobsdb.JobT {
+	job := dlq.NewJob(&jobsdb.JobT{
+		JobID:        7,
+		UserID:       "u1",
+		CustomVal:    "WEBHOOK",
+		WorkspaceId:  "w1",
+		EventPayload: json.RawMessage(`{"event":"broken"}`),
+		Parameters:   json.RawMessage(`{"source_id":"s1","destination_id":"d1","stage":"router","event_name":"e","event_type":"track"}`),
+	}, types.ROUTER, "400", json.RawMessage(`{"error":"bad request"}`))
+	job.JobID = 11
+	return job
+}
+
+func TestNewJob(t *testing.T) {
+	job := abortedJob()
+	require.Equal(t, "u1", job.UserID)
+	require.Equal(t, "WEBHOOK", job.CustomVal)
+	require.Equal(t, "w1", job.WorkspaceId)
+	require.JSONEq(t, `{"event":"broken"}`, string(job.EventPayload))
+	require.JSONEq(t, `{
+		"source_id":"s1","destination_id":"d1","stage":"router","event_name":"e","event_type":"track",
+		"error_code":"400","aborted_job_id":7,"error_response":{"error":"bad request"}
+	}`, string(job.Parameters))
+}
+
+func TestList(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := mocksJobsDB.NewMockJobsDB(mockCtrl)
+	service := dlq.NewService(db, nil, nil, mock_types.NewMockReportingI(mockCtrl))
+	ctx := context.Background()
+
+	t.Run("filters", func(t *testing.T) {
+		db.EXPECT().GetUnprocessed(gomock.Any(), jobsdb.GetQueryParamsT{
+			CustomValFilters: []string{"WEBHOOK"},
+			ParameterFilters: []jobsdb.ParameterFilterT{{Name: "destination_id", Value: "d1"}, {Name: "error_code", Value: "400"}},
+			AfterJobID:       10,
+			JobsLimit:        5,
+		}).Return(jobsdb.JobsResult{Jobs: []*jobsdb.JobT{abortedJob()}}, nil).Times(1)
+
+		events, err := service.List(ctx, dlq.Filter{DestType: "WEBHOOK", DestinationID: "d1", ErrorCode: "400", AfterJobID: 10, Limit: 5})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, dlq.Event{
+			JobID:         11,
+			AbortedJobID:  7,
+			Stage:         types.ROUTER,
+			DestType:      "WEBHOOK",
+			DestinationID: "d1",
+			SourceID:      "s1",
+			WorkspaceID:   "w1",
+			UserID:        "u1",
+			ErrorCode:     "400",
+			ErrorResponse: json.RawMessage(`{"error":"bad request"}`),
+			Payload:       json.RawMessage(`{"event":"broken"}`),
+		}, events[0])
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		_, err := service.List(ctx, dlq.Filter{DestinationID: `d1'} OR true`})
+		require.ErrorIs(t, err, dlq.ErrInvalidRequest)
+	})
+}
+
+func TestEdit(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := mocksJobsDB.NewMockJobsDB(mockCtrl)
+	service := dlq.NewService(db, nil, nil, mock_types.NewMockReportingI(mockCtrl))
+	ctx := context.Background()
+	payload := json.RawMessage(`{"event":"fixed"}`)
+
+	t.Run("supersedes the job", func(t *testing.T) {
+		job := abortedJob()
+		db.EXPECT().GetUnprocessed(gomock.Any(), jobsdb.GetQueryParamsT{AfterJobID: 10, JobsLimit: 1}).
+			Return(jobsdb.JobsResult{Jobs: []*jobsdb.JobT{job}}, nil).Times(1)
+		var edited *jobsdb.JobT
+		storeCall := db.EXPECT().Store(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, jobs []*jobsdb.JobT) error {
+			require.Len(t, jobs, 1)
+			edited = jobs[0]
+			return nil
+		}).Times(1)
+		db.EXPECT().UpdateJobStatus(gomock.Any(), gomock.Any(), nil, nil).After(storeCall).DoAndReturn(func(_ context.Context, statuses []*jobsdb.JobStatusT, _ []string, _ []jobsdb.ParameterFilterT) error {
+			require.Len(t, statuses, 1)
+			require.Equal(t, int64(11), statuses[0].JobID)
+			require.Equal(t, jobsdb.Aborted.State, statuses[0].JobState)
+			return nil
+		}).Times(1)
+		db.EXPECT().GetUnprocessed(gomock.Any(), jobsdb.GetQueryParamsT{
+			AfterJobID:       11,
+			ParameterFilters: []jobsdb.ParameterFilterT{{Name: "edit_of", Value: "11"}},
+			JobsLimit:        1,
+		}).DoAndReturn(func(context.Context, jobsdb.GetQueryParamsT) (jobsdb.JobsResult, error) {
+			edited.JobID = 12
+			return jobsdb.JobsResult{Jobs: []*jobsdb.JobT{edited}}, nil
+		}).Times(1)
+
+		event, err := service.Edit(ctx, 11, payload)
+		require.NoError(t, err)
+		require.Equal(t, int64(12), event.JobID)
+		require.Equal(t, int64(7), event.AbortedJobID)
+		require.Equal(t, payload, event.Payload)
+		require.NotEqual(t, job.UUID, edited.UUID)
+	})
+
+	t.Run("job not in the queue", func(t *testing.T) {
+		db.EXPECT().GetUnprocessed(gomock.Any(), jobsdb.GetQueryParamsT{AfterJobID: 12, JobsLimit: 1}).
+			Return(jobsdb.JobsResult{}, nil).Times(1)
+		_, err := service.Edit(ctx, 13, payload)
+		require.ErrorIs(t, err, dlq.ErrNotFound)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		_, err := service.Edit(ctx, 11, json.RawMessage(`{`))
+		require.ErrorIs(t, err, dlq.ErrInvalidRequest)
+	})
+}
+
+func TestRedrive(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	db := mocksJobsDB.NewMockJobsDB(mockCtrl)
+	routerDB := mocksJobsDB.NewMockJobsDB(mockCtrl)
+	batchRouterDB := mocksJobsDB.NewMockJobsDB(mockCtrl)
+	reporter := mock_types.NewMockReportingI(mockCtrl)
+	service := dlq.NewService(db, routerDB, batchRouterDB, reporter)
+	ctx := context.Background()
+
+	t.Run("stores and marks the jobs in one transaction", func(t *testing.T) {
+		job := abortedJob()
+		db.EXPECT().WithTx(gomock.Any()).DoAndReturn(func(f func(tx *sql.Tx) error) error {
+			return f(nil)
+		}).Times(1)
+		db.EXPECT().GetUnprocessed(gomock.Any(), jobsdb.GetQueryParamsT{AfterJobID: 10, JobsLimit: 1}).
+			Return(jobsdb.JobsResult{Jobs: []*jobsdb.JobT{job}}, nil).Times(1)
+		db.EXPECT().WithUpdateSafeTx(gomock.Any()).DoAndReturn(func(f func(tx jobsdb.UpdateSafeTx) error) error {
+			return f(jobsdb.EmptyUpdateSafeTx())
+		}).Times(1)
+		updateCall := db.EXPECT().UpdateJobStatusInTx(gomock.Any(), gomock.Any(), gomock.Any(), nil, nil).DoAndReturn(func(_ context.Context, _ jobsdb.UpdateSafeTx, statuses []*jobsdb.JobStatusT, _ []string, _ []jobsdb.ParameterFilterT) error {
+			require.Len(t, statuses, 1)
+			require.Equal(t, int64(11), statuses[0].JobID)
+			require.Equal(t, jobsdb.Succeeded.State, statuses[0].JobState)
+			return nil
+		}).Times(1)
+		storeCall := routerDB.EXPECT().Store(gomock.Any(), gomock.Any()).After(updateCall).DoAndReturn(func(_ context.Context, jobs []*jobsdb.JobT) error {
+			require.Len(t, jobs, 1)
+			require.JSONEq(t, `{"source_id":"s1","destination_id":"d1","event_name":"e","event_type":"track"}`, string(jobs[0].Parameters))
+			require.Equal(t, job.EventPayload, jobs[0].EventPayload)
+			require.Equal(t, "WEBHOOK", jobs[0].CustomVal)
+			return nil
+		}).Times(1)
+		reporter.EXPECT().Report(gomock.Any(), gomock.Any()).After(storeCall).Do(func(metrics []*types.PUReportedMetric, _ interface{}) {
+			require.Len(t, metrics, 1)
+			require.Equal(t, types.DLQ, metrics[0].PUDetails.InPU)
+			require.Equal(t, types.ROUTER, metrics[0].PUDetails.PU)
+			require.Equal(t, "d1", metrics[0].ConnectionDetails.DestinationID)
+			require.Equal(t, jobsdb.Succeeded.State, metrics[0].StatusDetail.Status)
+			require.Equal(t, int64(1), metrics[0].StatusDetail.Count)
+			require.Equal(t, "e", metrics[0].StatusDetail.EventName)
+		}).Times(1)
+
+		require.NoError(t, service.Redrive(ctx, []int64{11}))
+	})
+
+	t.Run("job re-driven already", func(t *testing.T) {
+		db.EXPECT().WithTx(gomock.Any()).DoAndReturn(func(f func(tx *sql.Tx) error) error {
+			return f(nil)
+		}).Times(1)
+		db.EXPECT().GetUnprocessed(gomock.Any(), jobsdb.GetQueryParamsT{AfterJobID: 10, JobsLimit: 1}).
+			Return(jobsdb.JobsResult{}, nil).Times(1)
+
+		require.ErrorIs(t, service.Redrive(ctx, []int64{11}), dlq.ErrNotFound)
+	})
+
+	t.Run("failing to store leaves the jobs in the queue", func(t *testing.T) {
+		db.EXPECT().WithTx(gomock.Any()).DoAndReturn(func(f func(tx *sql.Tx) error) error {
+			return f(nil)
+		}).Times(1)
+		db.EXPECT().GetUnprocessed(gomock.Any(), jobsdb.GetQueryParamsT{AfterJobID: 10, JobsLimit: 1}).
+			Return(jobsdb.JobsResult{Jobs: []*jobsdb.JobT{abortedJob()}}, nil).Times(1)
+		db.EXPECT().WithUpdateSafeTx(gomock.Any()).DoAndReturn(func(f func(tx jobsdb.UpdateSafeTx) error) error {
+			return f(jobsdb.EmptyUpdateSafeTx())
+		}).Times(1)
+		db.EXPECT().UpdateJobStatusInTx(gomock.Any(), gomock.Any(), gomock.Any(), nil, nil).Return(nil).Times(1)
+		routerDB.EXPECT().Store(gomock.Any(), gomock.Any()).Return(errors.New("store failed")).Times(1)
+
+		require.Error(t, service.Redrive(ctx, []int64{11}))
+	})
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/rudderlabs/rudder-server/services/dlq"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+)
+
+// defaultLimit is the number of events listed when no limit is requested
+const defaultLimit = 100
+
+func NewHandler(service dlq.Service, logger logger.LoggerI) http.Handler {
+	h := &handler{
+		service: service,
+		logger:  logger,
+	}
+	srvMux := mux.NewRouter()
+	srvMux.HandleFunc("/v1/dlq/events", h.list).Methods("GET")
+	srvMux.HandleFunc("/v1/dlq/events/{job_id}", h.get).Methods("GET")
+	srvMux.HandleFunc("/v1/dlq/events/{job_id}", h.edit).Methods("PUT")
+	srvMux.HandleFunc("/v1/dlq/redrive", h.redrive).Methods("POST")
+	return srvMux
+}
+
+type handler struct {
+	logger  logger.LoggerI
+	service dlq.Service
+}
+
+type redriveRequest struct {
+	JobIDs []int64 `json:"jobIds"`
+}
+
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := dlq.Filter{
+		DestType:      query.Get("dest_type"),
+		DestinationID: query.Get("destination_id"),
+		ErrorCode:     query.Get("error_code"),
+		Limit:         defaultLimit,
+	}
+	var err error
+	if after := query.Get("after_job_id"); after != "" {
+		if filter.AfterJobID, err = strconv.ParseInt(after, 10, 64); err != nil {
+			http.Error(w, "after_job_id is not a number", http.StatusBadRequest)
+			return
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit <= 0 {
+			http.Error(w, "limit is not a positive number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	events, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode(err))
+		return
+	}
+	h.writeResponse(w, events)
+}
+
+func (h *handler) get(w http.ResponseWriter, r *http.Request) {
+	jobID, ok := getJobID(w, r)
+	if !ok {
+		return
+	}
+	event, err := h.service.Get(r.Context(), jobID)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode(err))
+		return
+	}
+	h.writeResponse(w, event)
+}
+
+func (h *handler) edit(w http.ResponseWriter, r *http.Request) {
+	jobID, ok := getJobID(w, r)
+	if !ok {
+		return
+	}
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "reading request body", http.StatusBadRequest)
+		return
+	}
+	event, err := h.service.Edit(r.Context(), jobID, payload)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode(err))
+		return
+	}
+	h.writeResponse(w, event)
+}
+
+func (h *handler) redrive(w http.ResponseWriter, r *http.Request) {
+	var req redriveRequest
+	if err := jsoniter.NewDecoder(r.Body).Decode(&req); err != nil || len(req.JobIDs) == 0 {
+		http.Error(w, "request body needs to have jobIds", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Redrive(r.Context(), req.JobIDs); err != nil {
+		http.Error(w, err.Error(), statusCode(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) writeResponse(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := marshalAndWriteResponse(w, response); err != nil {
+		h.logger.Errorf("error while marshalling and writing response body: %v", err)
+	}
+}
+
+func getJobID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	jobID, err := strconv.ParseInt(mux.Vars(r)["job_id"], 10, 64)
+	if err != nil {
+		http.Error(w, "job_id is not a number", http.StatusBadRequest)
+		return 0, false
+	}
+	return jobID, true
+}
+
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, dlq.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, dlq.ErrInvalidRequest):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func marshalAndWriteResponse(w http.ResponseWriter, response interface{}) (err error) {
+	body, err := jsoniter.Marshal(response)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
@@ -0,0 +1,173 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	mock_dlq "github.com/rudderlabs/rudder-server/mocks/services/dlq"
+	mock_logger "github.com/rudderlabs/rudder-server/mocks/utils/logger"
+	"github.com/rudderlabs/rudder-server/services/dlq"
+	dlq_http "github.com/rudderlabs/rudder-server/services/dlq/http"
+)
+
+func TestList(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	service := mock_dlq.NewMockService(mockCtrl)
+	handler := dlq_http.NewHandler(service, mock_logger.NewMockLoggerI(mockCtrl))
+
+	tests := []struct {
+		name                 string
+		endpoint             string
+		filter               *dlq.Filter
+		serviceReturnError   error
+		expectedResponseCode int
+	}{
+		{
+			name:                 "default limit",
+			endpoint:             "/v1/dlq/events",
+			filter:               &dlq.Filter{Limit: 100},
+			expectedResponseCode: 200,
+		},
+		{
+			name:                 "all filters",
+			endpoint:             "/v1/dlq/events?dest_type=WEBHOOK&destination_id=d1&error_code=500&after_job_id=10&limit=5",
+			filter:               &dlq.Filter{DestType: "WEBHOOK", DestinationID: "d1", ErrorCode: "500", AfterJobID: 10, Limit: 5},
+			expectedResponseCode: 200,
+		},
+		{
+			name:                 "invalid limit",
+			endpoint:             "/v1/dlq/events?limit=-1",
+			expectedResponseCode: 400,
+		},
+		{
+			name:                 "invalid filter",
+			endpoint:             "/v1/dlq/events?destination_id=d'1",
+			filter:               &dlq.Filter{DestinationID: "d'1", Limit: 100},
+			serviceReturnError:   fmt.Errorf("%w: invalid filter value", dlq.ErrInvalidRequest),
+			expectedResponseCode: 400,
+		},
+		{
+			name:                 "service returns error",
+			endpoint:             "/v1/dlq/events",
+			filter:               &dlq.Filter{Limit: 100},
+			serviceReturnError:   fmt.Errorf("something went wrong"),
+			expectedResponseCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := []dlq.Event{{JobID: 11, DestType: "WEBHOOK", Payload: json.RawMessage(`{"a":1}`)}}
+			if tt.filter != nil {
+				service.EXPECT().List(gomock.Any(), *tt.filter).Return(events, tt.serviceReturnError).Times(1)
+			}
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, httptest.NewRequest("GET", tt.endpoint, http.NoBody))
+
+			require.Equal(t, tt.expectedResponseCode, resp.Code)
+			if tt.expectedResponseCode == 200 {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				var got []dlq.Event
+				require.NoError(t, json.Unmarshal(body, &got))
+				require.Equal(t, events, got)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	service := mock_dlq.NewMockService(mockCtrl)
+	handler := dlq_http.NewHandler(service, mock_logger.NewMockLoggerI(mockCtrl))
+
+	service.EXPECT().Get(gomock.Any(), int64(11)).Return(dlq.Event{JobID: 11}, nil).Times(1)
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest("GET", "/v1/dlq/events/11", http.NoBody))
+	require.Equal(t, 200, resp.Code)
+	require.Contains(t, resp.Body.String(), `"jobId":11`)
+
+	service.EXPECT().Get(gomock.Any(), int64(12)).Return(dlq.Event{}, dlq.ErrNotFound).Times(1)
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest("GET", "/v1/dlq/events/12", http.NoBody))
+	require.Equal(t, 404, resp.Code)
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest("GET", "/v1/dlq/events/abc", http.NoBody))
+	require.Equal(t, 400, resp.Code)
+}
+
+func TestEdit(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	service := mock_dlq.NewMockService(mockCtrl)
+	handler := dlq_http.NewHandler(service, mock_logger.NewMockLoggerI(mockCtrl))
+
+	payload := `{"event":"fixed"}`
+	service.EXPECT().Edit(gomock.Any(), int64(11), json.RawMessage(payload)).Return(dlq.Event{JobID: 20, Payload: json.RawMessage(payload)}, nil).Times(1)
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest("PUT", "/v1/dlq/events/11", strings.NewReader(payload)))
+	require.Equal(t, 200, resp.Code)
+	require.JSONEq(t, `{"jobId":20,"abortedJobId":0,"stage":"","destType":"","destinationId":"","sourceId":"","workspaceId":"","userId":"","errorCode":"","payload":{"event":"fixed"},"createdAt":"0001-01-01T00:00:00Z"}`, resp.Body.String())
+
+	service.EXPECT().Edit(gomock.Any(), int64(11), gomock.Any()).Return(dlq.Event{}, fmt.Errorf("%w: payload is not valid json", dlq.ErrInvalidRequest)).Times(1)
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest("PUT", "/v1/dlq/events/11", strings.NewReader("{")))
+	require.Equal(t, 400, resp.Code)
+}
+
+func TestRedrive(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	service := mock_dlq.NewMockService(mockCtrl)
+	handler := dlq_http.NewHandler(service, mock_logger.NewMockLoggerI(mockCtrl))
+
+	tests := []struct {
+		name                 string
+		body                 string
+		jobIDs               []int64
+		serviceReturnError   error
+		expectedResponseCode int
+	}{
+		{
+			name:                 "re-drives jobs",
+			body:                 `{"jobIds":[1,2]}`,
+			jobIDs:               []int64{1, 2},
+			expectedResponseCode: 204,
+		},
+		{
+			name:                 "no jobs",
+			body:                 `{"jobIds":[]}`,
+			expectedResponseCode: 400,
+		},
+		{
+			name:                 "job not found",
+			body:                 `{"jobIds":[3]}`,
+			jobIDs:               []int64{3},
+			serviceReturnError:   fmt.Errorf("job 3: %w", dlq.ErrNotFound),
+			expectedResponseCode: 404,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.jobIDs != nil {
+				service.EXPECT().Redrive(gomock.Any(), tt.jobIDs).Return(tt.serviceReturnError).Times(1)
+			}
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, httptest.NewRequest("POST", "/v1/dlq/redrive", strings.NewReader(tt.body)))
+			require.Equal(t, tt.expectedResponseCode, resp.Code)
+		})
+	}
+}
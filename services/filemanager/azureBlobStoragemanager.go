@@ -79,6 +79,25 @@ func (manager *AzureBlobStorageManager) getContainerURL() (azblob.ContainerURL,
 
 // Upload passed in file to Azure Blob Storage
 func (manager *AzureBlobStorageManager) Upload(ctx context.Context, file *os.File, prefixes ...string) (UploadOutput, error) {
+	return manager.upload(ctx, file, prefixes, azblob.BlobAccessConditions{})
+}
+
+// UploadIfNotExists uploads the file to Azure Blob Storage with the If-None-Match access condition, so that it fails if
+// the blob exists
+func (manager *AzureBlobStorageManager) UploadIfNotExists(ctx context.Context, file *os.File, prefixes ...string) (UploadOutput, error) {
+	output, err := manager.upload(ctx, file, prefixes, azblob.BlobAccessConditions{
+		ModifiedAccessConditions: azblob.ModifiedAccessConditions{IfNoneMatch: azblob.ETagAny},
+	})
+	if serr, ok := err.(azblob.StorageError); ok {
+		switch serr.ServiceCode() {
+		case azblob.ServiceCodeBlobAlreadyExists, azblob.ServiceCodeConditionNotMet:
+			return UploadOutput{}, fmt.Errorf("uploading %s: %w", path.Base(file.Name()), ErrObjectExists)
+		}
+	}
+	return output, err
+}
+
+func (manager *AzureBlobStorageManager) upload(ctx context.Context, file *os.File, prefixes []string, accessConditions azblob.BlobAccessConditions) (UploadOutput, error) {
 	containerURL, err := manager.getContainerURL()
 	if err != nil {
 		return UploadOutput{}, err
@@ -98,8 +117,9 @@ func (manager *AzureBlobStorageManager) Upload(ctx context.Context, file *os.Fil
 	// Here's how to upload a blob.
 	blobURL := containerURL.NewBlockBlobURL(fileName)
 	_, err = azblob.UploadFileToBlockBlob(ctx, file, blobURL, azblob.UploadToBlockBlobOptions{
-		BlockSize:   4 * 1024 * 1024,
-		Parallelism: 16,
+		BlockSize:        4 * 1024 * 1024,
+		Parallelism:      16,
+		AccessConditions: accessConditions,
 	})
 	if err != nil {
 		return UploadOutput{}, err
@@ -19,6 +19,8 @@ var (
 	pkgLogger                 logger.LoggerI
 	DefaultFileManagerFactory FileManagerFactory
 	ErrKeyNotFound            = errors.New("NoSuchKey")
+	// ErrObjectExists is returned by the conditional uploads when an object already exists at the key of the file
+	ErrObjectExists = errors.New("object already exists")
 )
 
 type FileManagerFactoryT struct{}
@@ -49,6 +51,12 @@ type FileManager interface {
 	SetTimeout(timeout time.Duration)
 }
 
+// ConditionalUploader is implemented by the file managers which can upload a file only if no object exists at its key
+// yet, atomically in the object storage. It returns ErrObjectExists otherwise.
+type ConditionalUploader interface {
+	UploadIfNotExists(context.Context, *os.File, ...string) (UploadOutput, error)
+}
+
 // SettingsT sets configuration for FileManager
 type SettingsT struct {
 	Provider string
@@ -2,14 +2,17 @@ package filemanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path"
 	"strings"
 	"time"
 
 	"github.com/rudderlabs/rudder-server/utils/googleutils"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 
 	"cloud.google.com/go/storage"
@@ -24,6 +27,21 @@ func (manager *GCSManager) objectURL(objAttrs *storage.ObjectAttrs) string {
 }
 
 func (manager *GCSManager) Upload(ctx context.Context, file *os.File, prefixes ...string) (UploadOutput, error) {
+	return manager.upload(ctx, file, prefixes, storage.Conditions{})
+}
+
+// UploadIfNotExists uploads the file to GCS with the generation precondition of a missing object, so that it fails if
+// the key exists
+func (manager *GCSManager) UploadIfNotExists(ctx context.Context, file *os.File, prefixes ...string) (UploadOutput, error) {
+	output, err := manager.upload(ctx, file, prefixes, storage.Conditions{DoesNotExist: true})
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+		return UploadOutput{}, fmt.Errorf("uploading %s: %w", path.Base(file.Name()), ErrObjectExists)
+	}
+	return output, err
+}
+
+func (manager *GCSManager) upload(ctx context.Context, file *os.File, prefixes []string, conditions storage.Conditions) (UploadOutput, error) {
 	fileName := path.Join(manager.Config.Prefix, path.Join(prefixes...), path.Base(file.Name()))
 
 	client, err := manager.getClient(ctx)
@@ -35,6 +53,9 @@ func (manager *GCSManager) Upload(ctx context.Context, file *os.File, prefixes .
 	defer cancel()
 
 	obj := client.Bucket(manager.Config.Bucket).Object(fileName)
+	if conditions != (storage.Conditions{}) {
+		obj = obj.If(conditions)
+	}
 	w := obj.NewWriter(ctx)
 	if _, err := io.Copy(w, file); err != nil {
 		err = fmt.Errorf("copying file to GCS: %v", err)
@@ -48,6 +69,8 @@ func (manager *GCSManager) Upload(ctx context.Context, file *os.File, prefixes .
 	if err != nil {
 		return UploadOutput{}, fmt.Errorf("closing writer: %w", err)
 	}
+	// the preconditions of the upload don't hold once the object is written
+	obj = client.Bucket(manager.Config.Bucket).Object(fileName)
 
 	attrs, err := obj.Attrs(ctx)
 	if err != nil {
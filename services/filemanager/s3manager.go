@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
 	"path"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	awsS3Manager "github.com/aws/aws-sdk-go/service/s3/s3manager"
@@ -22,6 +24,22 @@ import (
 
 // Upload passed in file to s3
 func (manager *S3Manager) Upload(ctx context.Context, file *os.File, prefixes ...string) (UploadOutput, error) {
+	return manager.upload(ctx, file, prefixes)
+}
+
+// UploadIfNotExists uploads the file to s3 with the If-None-Match precondition, so that it fails if the key exists
+func (manager *S3Manager) UploadIfNotExists(ctx context.Context, file *os.File, prefixes ...string) (UploadOutput, error) {
+	output, err := manager.upload(ctx, file, prefixes, awsS3Manager.WithUploaderRequestOptions(func(r *request.Request) {
+		r.HTTPRequest.Header.Set("If-None-Match", "*")
+	}))
+	// a conflicting conditional upload in progress fails with 409 instead of 412
+	if requestErr, ok := err.(awserr.RequestFailure); ok && (requestErr.StatusCode() == http.StatusPreconditionFailed || requestErr.StatusCode() == http.StatusConflict) {
+		return UploadOutput{}, fmt.Errorf("uploading %s: %w", path.Base(file.Name()), ErrObjectExists)
+	}
+	return output, err
+}
+
+func (manager *S3Manager) upload(ctx context.Context, file *os.File, prefixes []string, options ...func(*awsS3Manager.Uploader)) (UploadOutput, error) {
 	fileName := path.Join(manager.Config.Prefix, path.Join(prefixes...), path.Base(file.Name()))
 
 	uploadInput := &awsS3Manager.UploadInput{
@@ -43,7 +61,7 @@ func (manager *S3Manager) Upload(ctx context.Context, file *os.File, prefixes ..
 	ctx, cancel := context.WithTimeout(ctx, manager.getTimeout())
 	defer cancel()
 
-	output, err := s3manager.UploadWithContext(ctx, uploadInput)
+	output, err := s3manager.UploadWithContext(ctx, uploadInput, options...)
 	if err != nil {
 		if awsError, ok := err.(awserr.Error); ok && awsError.Code() == "MissingRegion" {
 			err = fmt.Errorf(fmt.Sprintf(`Bucket '%s' not found.`, manager.Config.Bucket))
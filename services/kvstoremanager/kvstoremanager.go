@@ -2,8 +2,11 @@ package kvstoremanager
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/tidwall/gjson"
+
+	"github.com/rudderlabs/rudder-server/config"
 )
 
 type KVStoreManager interface {
@@ -14,6 +17,13 @@ type KVStoreManager interface {
 	DeleteKey(key string) (err error)
 	HMGet(key string, fields ...string) (result []interface{}, err error)
 	HGetAll(key string) (result map[string]string, err error)
+	// IncrBy increments the counter of key by value, resetting its expiration, and returns its new value
+	IncrBy(key string, value int64, expiration time.Duration) (result int64, err error)
+	// GetCounters returns the values of the counters of keys, 0 for the ones which don't exist
+	GetCounters(keys ...string) (result []int64, err error)
+	// IncrIfBelow atomically increments the counter of currKey by one, resetting its expiration, only if
+	// prevWeight*value(prevKey) + value(currKey) is below limit. It returns the values read and whether it was incremented
+	IncrIfBelow(prevKey, currKey string, prevWeight float64, limit int64, expiration time.Duration) (prevValue, currValue int64, incremented bool, err error)
 }
 
 type SettingsT struct {
@@ -39,6 +49,19 @@ func newManager(settings SettingsT) (m KVStoreManager) {
 	return m
 }
 
+// RedisConfig returns the config of a redis manager from the config keys under prefix, e.g. <prefix>.address
+func RedisConfig(prefix string) map[string]interface{} {
+	return map[string]interface{}{
+		"address":       config.GetString(prefix+".address", "localhost:6379"),
+		"password":      config.GetString(prefix+".password", ""),
+		"database":      config.GetString(prefix+".database", "0"),
+		"clusterMode":   config.GetBool(prefix+".clusterMode", false),
+		"secure":        config.GetBool(prefix+".secure", false),
+		"skipVerify":    config.GetBool(prefix+".skipVerify", false),
+		"caCertificate": config.GetString(prefix+".caCertificate", ""),
+	}
+}
+
 func EventToKeyValue(jsonData json.RawMessage) (string, map[string]interface{}) {
 	key := gjson.GetBytes(jsonData, "message.key").String()
 	result := gjson.GetBytes(jsonData, "message.fields").Map()
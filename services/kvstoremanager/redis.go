@@ -3,9 +3,11 @@ package kvstoremanager
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-redis/redis"
 	"github.com/rudderlabs/rudder-server/utils/types"
@@ -134,3 +136,70 @@ func (m *redisManagerT) HGetAll(key string) (result map[string]string, err error
 	}
 	return result, err
 }
+
+func (m *redisManagerT) cmdable() redis.Cmdable {
+	if m.clusterMode {
+		return m.clusterClient
+	}
+	return m.client
+}
+
+func (m *redisManagerT) IncrBy(key string, value int64, expiration time.Duration) (result int64, err error) {
+	var incr *redis.IntCmd
+	_, err = m.cmdable().TxPipelined(func(pipe redis.Pipeliner) error {
+		incr = pipe.IncrBy(key, value)
+		pipe.Expire(key, expiration)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (m *redisManagerT) GetCounters(keys ...string) (result []int64, err error) {
+	values, err := m.cmdable().MGet(keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	result = make([]int64, len(values))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			result[i], _ = strconv.ParseInt(s, 10, 64)
+		}
+	}
+	return result, nil
+}
+
+// incrIfBelowScript increments KEYS[2] and sets its expiration to ARGV[3] milliseconds if ARGV[1]*KEYS[1] + KEYS[2] < ARGV[2],
+// returning the values read and 1 if it was incremented, in a single step so that concurrent clients can't exceed the limit together
+var incrIfBelowScript = redis.NewScript(`
+local prev = tonumber(redis.call('GET', KEYS[1]) or '0')
+local curr = tonumber(redis.call('GET', KEYS[2]) or '0')
+if prev < 0 then prev = 0 end
+if curr < 0 then curr = 0 end
+if prev * tonumber(ARGV[1]) + curr >= tonumber(ARGV[2]) then
+	return {prev, curr, 0}
+end
+redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+return {prev, curr, 1}
+`)
+
+// IncrIfBelow needs prevKey and currKey to be in the same hash slot in cluster mode
+func (m *redisManagerT) IncrIfBelow(prevKey, currKey string, prevWeight float64, limit int64, expiration time.Duration) (prevValue, currValue int64, incremented bool, err error) {
+	result, err := incrIfBelowScript.Run(
+		m.cmdable(), []string{prevKey, currKey},
+		strconv.FormatFloat(prevWeight, 'f', -1, 64), limit, expiration.Milliseconds(),
+	).Result()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 3 {
+		return 0, 0, false, fmt.Errorf("unexpected result of incrementing %s: %v", currKey, result)
+	}
+	prevValue, _ = values[0].(int64)
+	currValue, _ = values[1].(int64)
+	return prevValue, currValue, values[2] == int64(1), nil
+}
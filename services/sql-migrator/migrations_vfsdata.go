@@ -1,6 +1,5 @@
 // Code generated by vfsgen; DO NOT EDIT.
 
-//go:build !dev
 // +build !dev
 
 package migrator
@@ -10,6 +9,7 @@ import (
 	"compress/gzip"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
 	pathpkg "path"
@@ -21,355 +21,400 @@ var MigrationAssets = func() http.FileSystem {
 	fs := vfsgen۰FS{
 		"/": &vfsgen۰DirInfo{
 			name:    "/",
-			modTime: time.Date(2022, 5, 11, 7, 57, 37, 776157319, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 		},
 		"/jobsdb": &vfsgen۰DirInfo{
 			name:    "jobsdb",
-			modTime: time.Date(2022, 7, 29, 8, 15, 27, 132901959, time.UTC),
+			modTime: time.Date(2026, 10, 16, 19, 28, 13, 870598651, time.UTC),
 		},
 		"/jobsdb/000001_create_tables.down.tmpl": &vfsgen۰CompressedFileInfo{
 			name:             "000001_create_tables.down.tmpl",
-			modTime:          time.Date(2022, 7, 29, 6, 20, 18, 724496169, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 269,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x84\x8e\x41\xca\x83\x30\x10\x46\xf7\x39\xc5\x87\xfc\x5b\xbd\x80\xab\xbf\x68\x41\x28\x54\xaa\x8b\x76\x15\x22\xa6\xa2\x48\x94\x24\x82\x32\xcc\xdd\x4b\x6d\xc5\xd2\x4d\x97\x33\xf3\xde\xf0\xc2\x10\x89\x1d\x46\x28\xb3\x40\xcf\xad\xf3\xad\x69\x50\x2b\xaf\x9c\xf6\x4e\x10\x59\x65\x1a\x8d\x28\x79\x6f\x98\x05\x00\x24\x97\x73\x8e\xf2\xff\x70\x4a\x11\x10\xfd\x45\xb9\xd5\xf7\x76\x66\x96\xdd\x50\x39\x49\x14\x31\x07\xf1\x4f\x52\x3a\xaf\xfc\xb4\xf3\x44\xda\xd4\xcc\x42\x6c\x51\x7e\x19\x35\x36\x50\xcb\xe7\x28\x5e\x0f\x6f\x79\xfa\x75\x88\x77\xad\x1b\x26\x6b\x54\x0f\xaf\xaa\x7e\x13\xd6\x82\xec\x88\xf4\x9a\x15\x65\x01\xa2\xcf\x92\x15\x8f\x85\x78\x04\x00\x00\xff\xff\x4b\xf0\x3c\x19\x0d\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x84\x8e\xbd\xaa\x83\x40\x10\x85\xfb\x79\x8a\x41\x6e\xab\x2f\x60\x75\x83\x06\x84\x40\x24\x5a\x24\xd5\xb2\xe2\x44\x14\x59\x65\x77\x05\x65\x98\x77\x0f\xf9\x11\x25\x4d\xca\x99\xf3\x9d\xc3\x17\x86\x98\xd8\x61\x44\x6d\x16\xa4\xb9\x75\xbe\x35\x0d\xd6\xda\x6b\x47\xde\x01\xb3\xd5\xa6\x21\x8c\x92\xcf\x47\x04\x10\x11\x93\xcb\x39\xc7\xf2\xff\x70\x4a\x31\x60\xfe\x8b\x72\x4b\xf7\x76\x16\x51\xdd\x50\x39\xc5\x1c\x89\x04\xf1\x4f\x52\x39\xaf\xfd\xb4\xf1\xcc\x64\x6a\x11\x80\x55\xca\x2f\x23\xe1\x0a\x92\x7a\x9e\xf0\x1e\xbc\xe5\xe9\x57\x10\x6f\xb5\x6e\x98\xac\xd1\x3d\x7a\x5d\xf5\x04\x3b\x83\xec\x88\xe9\x35\x2b\xca\x02\x99\xf7\x26\x2f\x3c\x06\x78\x0c\x00\x4b\xf0\x3c\x19\x0d\x01\x00\x00"),
 		},
 		"/jobsdb/000001_create_tables.up.tmpl": &vfsgen۰CompressedFileInfo{
 			name:             "000001_create_tables.up.tmpl",
-			modTime:          time.Date(2022, 7, 29, 6, 20, 18, 724894045, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 1362,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8c\x54\x51\x6f\xe2\x38\x10\x7e\xe7\x57\x8c\x56\x48\xb4\x12\x70\xd2\xdd\xdb\xf5\x29\x80\x6f\x37\x3d\x08\x08\xd2\xbd\xf6\x29\x9a\xd8\x03\xb8\x6b\xec\x9c\xed\xb4\x44\x51\xfe\xfb\xc9\x21\xe9\x5e\xd9\xae\xb4\x79\x9c\xf9\xbe\x6f\x66\xbe\xcc\x78\x32\x81\x58\x4b\x2f\x51\xc1\x0b\x59\x27\x8d\x06\xb3\x87\x7b\x93\xbb\xc5\x0c\x3c\xe6\x8a\xdc\x18\x72\x74\x24\xc0\x68\xf0\x74\x2a\x14\x7a\x02\x81\x1e\xa1\xb0\xe6\x45\x0a\x12\x90\x57\x40\xc8\x8f\x3d\x4d\x6a\xe7\x51\x73\x1a\x4c\x26\x30\x3f\x12\xff\x06\xcf\x26\x77\x22\xff\xcd\x91\x2f\x8b\xe9\xc1\xc0\xde\x58\x40\xa5\x00\x5f\x50\xaa\x50\xe4\xbd\xf2\x74\x10\xa8\xf7\xa6\xb4\x1a\x95\xbb\xb4\x31\x98\x6f\x59\x94\x32\x48\xa3\xd9\x92\x41\xfc\x17\x24\xeb\x14\xd8\x63\xbc\x4b\x77\x50\xd7\xd3\x8d\xa5\xbd\x3c\x37\x4d\xf6\x7c\x61\xc1\xcd\x00\x00\x40\x0a\x98\xc5\x9f\x77\x6c\x1b\x47\x4b\xd8\x6c\xe3\x55\xb4\x7d\x82\xbf\xd9\xd3\xb8\xcd\x9a\x82\x2c\xfa\x30\xf3\xd7\x68\x3b\xff\x12\x6d\x6f\xfe\xf8\xfd\xb6\x15\x4e\x1e\x96\xcb\x0b\x46\x18\x4d\x30\x5b\xaf\x97\x2c\x4a\xae\x58\x59\x81\x95\x32\x28\xe0\x7e\xb7\x4e\x66\x57\x3c\xe7\xd1\xfa\xcc\xcb\x13\x41\x1a\xaf\xd8\x2e\x8d\x56\x9b\x2b\x08\x69\x71\x05\xb8\xbd\xeb\x26\xcf\x03\xdf\x13\xf8\xaa\xa0\x31\x94\xee\x62\x72\xb0\xb1\xfb\x29\x83\xc5\x1a\x86\x43\x98\xb1\xcf\x71\xd2\x8a\xf5\xf6\x3c\x6d\x58\xc0\x65\x2d\x3f\x0b\xfc\x36\x1d\xbe\x68\x07\x2c\x79\x58\xdd\xbc\x05\xfa\x6f\xf4\x8a\xd2\x4b\x7d\x18\x8d\x7f\x4c\xd1\x99\x78\xf9\xb3\xa4\x2b\x39\x27\x12\x24\x3e\x4a\x76\xa2\x99\x25\x6f\xab\x8f\x00\x7b\x94\xea\x63\x2a\xe6\xc6\x7a\x12\xa3\xdb\xbb\x36\xc7\x1e\xe7\x6c\x93\xc6\xeb\xe4\x0d\xf9\xcf\x17\x96\x80\x28\x0b\x25\x79\x18\xd3\xe4\xcf\xc4\x3d\xa4\x21\xaa\x4b\xa5\xee\x06\x2c\x59\xc0\x70\x78\xb1\x73\x81\x1e\x1d\xf9\xce\x39\x40\x4b\xa0\x8d\x07\x6e\x09\x3d\x09\x10\xd2\x12\xf7\xaa\x0a\x0e\x9f\xe4\xa1\xdb\x08\xc7\xad\x2c\xbc\x1b\x83\x3f\x52\x4b\xe9\xe1\x2f\x12\xbb\x45\x1f\xb9\x2e\xb8\xd8\xc1\xbe\xd4\xbc\xe5\x85\xe5\x27\x14\xd3\x50\x38\x3d\x12\xfc\x5b\x92\xad\x2e\x7f\x50\xea\xef\xf8\x53\xe9\x3c\xa0\x7a\xc5\xaa\x17\x69\x0b\x89\xf7\xad\x96\x4e\xea\x43\x9b\x08\xb7\xe1\x3c\x38\x7e\xa4\x13\xf6\xa7\xda\x16\xf9\xda\x9d\x6d\x59\x88\x80\x69\x6f\x8b\xce\xd2\x05\xef\xaf\xf5\x38\x6a\xc8\x09\x04\xed\xa5\x26\x31\xee\xf5\xdf\x5d\xf5\x37\xaa\x60\xd4\x59\xe6\x46\xe0\x0d\x48\x1f\x16\x9e\x7e\x54\x95\x5a\x48\x4e\xae\x9b\x55\x3a\x90\x0e\x50\x03\x9d\xf1\x54\x28\x0a\x0f\x09\xbe\x61\xaf\x9d\xed\x8b\x7f\x8f\xf7\x6d\xb8\x3f\x07\x93\x49\x90\xac\x6b\x8b\xfa\x40\x30\xed\xbb\x69\x9a\x10\x6e\x57\x79\x99\xb2\x6d\xf7\x16\x7c\xaa\xeb\xe1\xff\xef\x3f\x77\x59\x5d\x4f\x9b\xe6\x13\x44\x8b\x05\xcc\xd7\xcb\x87\x55\x02\x9a\x5e\x33\x6e\x54\x79\xd2\x90\xb2\xc7\xf4\xee\x97\x84\xda\x23\x2a\x7f\x55\xae\xae\x49\x8b\xa6\xf9\x2f\x00\x00\xff\xff\x96\x37\xdb\xa7\x52\x05\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8c\x54\xc1\x6e\xe2\x48\x10\xbd\xf3\x15\x4f\x23\x24\x12\x09\x58\x69\xf7\xb6\x39\x19\xe8\x9d\x71\x16\x0c\x02\x67\x36\x39\xa1\xb2\xbb\x80\xce\x98\x6e\xaf\xbb\x9d\x80\x2c\xff\xfb\xaa\x8d\x9d\xd9\x30\x33\x52\xfa\x84\xaa\xde\x7b\x55\xf5\x5c\xc5\x68\x84\x50\x2b\xa7\x28\xc3\x0b\x17\x56\x19\x0d\xb3\xc3\xbd\x49\xec\x6c\x02\x47\x49\xc6\x76\x88\x84\x2c\x4b\x18\x0d\xc7\xc7\x3c\x23\xc7\x90\xe4\x08\x79\x61\x5e\x94\x64\x89\xe4\x0c\xa6\xf4\xd0\xd1\x94\xb6\x8e\x74\xca\xbd\xd1\x08\xd3\x03\xa7\xdf\xf0\x6c\x12\x2b\x93\xdf\x2c\xbb\x32\x1f\xef\x0d\x76\xa6\x00\x65\x19\xe8\x85\x54\xe6\x8b\xbc\x57\x1e\xf7\x3c\xf5\xde\x94\x85\xa6\xcc\x5e\xda\xe8\x4d\xd7\x22\x88\x05\xe2\x60\x32\x17\x08\xff\x42\xb4\x8c\x21\x1e\xc3\x4d\xbc\x41\x55\x8d\x57\x05\xef\xd4\xa9\xae\xb7\xcf\x17\x16\x6e\x7a\x00\xa0\x24\x26\xe1\xe7\x8d\x58\x87\xc1\x1c\xab\x75\xb8\x08\xd6\x4f\xf8\x5b\x3c\x0d\x9b\xac\xc9\xb9\x20\xe7\x67\xfe\x1a\xac\xa7\x5f\x82\xf5\xcd\x1f\xbf\xdf\x36\xc2\xd1\xc3\x7c\x7e\xc1\x48\xa3\x19\x93\xe5\x72\x2e\x82\xe8\x8a\xb5\xcd\xe9\x9c\x19\x92\xb8\xdf\x2c\xa3\xc9\x15\xcf\x3a\x2a\xdc\xd6\xa9\x23\x23\x0e\x17\x62\x13\x07\x8b\xd5\x15\x84\xb5\xbc\x02\xdc\xde\xb5\x93\x27\x9e\xef\x18\xee\x9c\xf3\x10\xa5\xbd\x98\xec\x6d\x6c\x3f\x4a\x6f\xb6\x44\xbf\x8f\x89\xf8\x1c\x46\x8d\x58\x67\xcf\xd3\x4a\x78\xdc\xb6\xe1\x6f\x3d\xbf\x49\xfb\x17\x6c\x20\xa2\x87\xc5\xcd\x5b\xa0\x7b\x83\x57\x52\x4e\xe9\xfd\x60\xf8\x63\x8a\x4f\x9c\x96\xbf\x4a\xda\x32\x4d\x99\x25\xcb\xc1\xf0\x97\xa2\xdb\x82\x5d\x71\xfe\x19\x60\x47\x2a\xfb\x39\x95\x12\x53\x38\x96\x83\xdb\xbb\x26\x27\x1e\xa7\x62\x15\x87\xcb\xe8\x0d\xf9\xcf\x17\x11\x41\x96\x79\xa6\x52\x3f\xa6\x49\x9e\x39\x75\x88\x7d\x54\x97\x59\x76\xd7\x13\xd1\x0c\xfd\xfe\xc5\xce\x19\x39\xb2\xec\x5a\xe7\x40\x05\x43\x1b\x87\xb4\x60\x72\x2c\x21\x55\xc1\xa9\xcb\xce\xde\xe1\xa3\xda\xb7\x1b\x61\xd3\x42\xe5\xce\x0e\xe1\x0e\xdc\x50\x3a\xf8\x8b\xa2\x76\xd1\x07\xb6\x0d\xce\x36\xd8\x95\x3a\x6d\x78\x7e\xf9\x99\xe4\xd8\x17\x8e\x0f\x8c\x7f\x4b\x2e\xce\x97\x2f\xa8\xf4\x77\xfc\xb1\xb4\x0e\x94\xbd\xd2\xb9\x13\x69\x0a\xc9\xf7\xad\x96\x56\xe9\x7d\x93\xf0\xb7\x61\x1d\x6c\x7a\xe0\x23\x75\xa7\xda\x14\xf9\x7a\xf9\x8d\x32\x97\x1e\xd3\xdc\x16\x9f\x94\xf5\xde\x5f\xeb\xa5\xa4\x91\x30\x24\xef\x94\x66\x39\xec\xf4\xdf\x5d\xf5\x37\x3e\x63\xd0\x5a\x66\x07\x70\x06\xca\xf9\x85\xe7\x1f\x55\x95\x96\x2a\x65\xdb\xce\xaa\x2c\x94\x05\x69\xf0\x89\x8e\x79\xc6\x30\x3b\xd0\x1b\xf6\xda\xd9\xae\xf8\xf7\x78\xd7\x86\xfd\xb3\x37\x1a\x79\xc9\xaa\x2a\x48\xef\x19\xe3\xae\x9b\xba\xf6\x61\xff\x82\x79\x2c\xd6\xed\x7f\xc1\xa7\xaa\xea\xff\xff\xfe\x13\xbb\xad\xaa\x71\x5d\x7f\x42\x30\x9b\x61\xba\x9c\x3f\x2c\x22\x68\x7e\xdd\xa6\x26\x2b\x8f\x1a\xb1\x78\x8c\xef\x3e\x24\xd4\x1c\x51\xf9\x51\xb9\xaa\x62\x2d\xeb\xfa\xbf\x01\x00\x96\x37\xdb\xa7\x52\x05\x00\x00"),
 		},
 		"/jobsdb/000002_alter_dataset_tables.down.tmpl": &vfsgen۰CompressedFileInfo{
 			name:             "000002_alter_dataset_tables.down.tmpl",
-			modTime:          time.Date(2022, 7, 29, 6, 20, 18, 725250166, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 812,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa4\x91\xd1\x8a\xea\x30\x10\x86\xef\x7d\x8a\x41\x0a\x9e\x03\x47\x5f\xa0\x57\xd5\xe6\x9c\x23\xd4\xb4\xb8\x29\xbb\x7b\x15\xd2\x66\x94\x48\x69\x25\x49\x59\x25\xe4\xdd\x97\xb6\xae\xe0\x52\x85\xdd\x9d\x8b\x42\x67\xfe\xf9\x26\xfc\xbf\x73\x5a\xd4\x7b\x84\x45\x2c\xac\x30\x68\x8d\xf7\x13\x00\x80\x28\x61\x64\x0b\x2c\x5a\x26\x04\xa6\xce\x05\x8b\x4c\xe3\x4e\x9d\xbc\xe7\x87\xa6\x30\xdc\xb9\x85\xf7\x53\x88\xb7\x69\x06\xab\x34\xc9\x37\x14\x5a\x83\x9a\x2b\x19\x7e\x61\x7d\x50\x5d\xf6\x4b\x8d\xc2\xa2\xe4\xc2\x82\x41\x0b\x31\xf9\x1b\xe5\x09\x03\x9a\x27\xc9\xb7\x99\x78\x3a\x2a\x8d\xe3\xc8\x9e\x39\x9f\x83\xc6\xe1\x32\x1c\x9a\x82\x1b\x2b\x2c\x72\x7b\x3e\x22\x74\x9f\x5e\x13\xa7\x10\x04\xb0\x24\xff\xd6\xb4\xff\xef\x6a\xb5\x25\x11\x23\xc0\x5e\x33\xf2\x69\xef\x2a\xe9\x9f\xfc\x04\x84\xe6\x9b\x5f\x37\xcd\x8f\x9a\xbd\x09\x65\x55\xbd\x9f\xfd\x19\x1f\xe3\x09\xcb\xf6\x91\xc0\xb4\x65\x89\x28\x51\xde\x13\x5c\x0e\x70\x8d\x56\x9f\xef\x89\x76\x42\x55\xf7\x11\xa2\x68\xb4\x45\x39\xfb\x1d\xde\xcc\xc9\xcb\x8a\x64\x6c\x9d\xd2\x9b\xee\xf3\x7f\x42\x41\xb6\xc7\x4a\x95\x9d\x1d\x4d\x71\xc0\xd2\x02\xeb\xba\x75\x5b\x55\x03\x82\xd0\x18\x82\xe0\xe2\xff\xc3\x4c\x7b\x57\xdb\xf1\x64\xaf\xa6\x8f\x65\x10\xfe\x9c\xad\xe4\x00\x5e\x53\x16\x4e\x9c\xc3\x5a\x7a\xff\x1e\x00\x00\xff\xff\xb4\xe5\x9b\xe1\x2c\x03\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa4\x91\xd1\x8a\xea\x30\x10\x86\xef\x7d\x8a\x41\x0a\x3d\x07\xd4\x17\xe8\x55\xb5\x39\x67\x85\x9a\x16\x37\x65\x77\xaf\x4a\xda\x8c\x12\x29\xad\x24\x29\xab\x84\xbc\xfb\xd2\x56\x17\x5c\xaa\xb0\xbb\x73\x11\xc8\xcc\x3f\xdf\xc0\xff\x5b\xab\x78\xbd\x47\x58\x44\xdc\x70\x8d\x46\x3b\x37\x01\x00\x08\x63\x46\xb6\xc0\xc2\x65\x4c\x60\x6a\xad\xb7\x48\x15\xee\xe4\xc9\xb9\xfc\xd0\x14\x3a\xb7\x76\xe1\xdc\x14\xa2\x6d\x92\xc2\x2a\x89\xb3\x0d\x85\x56\xa3\xca\xa5\x08\xbe\xb1\x3e\xa8\x2e\xfb\xa5\x42\x6e\x50\xe4\xdc\x80\x46\x03\x11\xf9\x17\x66\x31\x03\x9a\xc5\xf1\x8f\x99\x78\x3a\x4a\x85\xe3\xc8\x9e\x39\x9f\x83\xc2\xe1\x32\x1c\x9a\x22\xd7\x86\x1b\xcc\xcd\xf9\x88\xd0\x3d\xbd\x26\x4a\xc0\xf3\x60\x49\xfe\xaf\x69\xff\xef\x6a\xb5\x25\x21\x23\xc0\xde\x52\xf2\x65\xef\x53\xd2\x55\xf8\x0c\x84\x66\x9b\x3f\x37\xcd\x6b\xf9\xef\x5c\x1a\x59\xef\xfd\xd9\xf8\x18\x4f\x58\xb6\x8f\x04\xba\x2d\x4b\x44\x81\xc2\x9f\x3d\x3c\x90\x2b\x34\xea\x7c\x4f\xb4\xe3\xb2\xba\x8f\xe0\x45\xa3\x0c\x0a\xff\x6f\x70\x33\x27\xaf\x2b\x92\xb2\x75\x42\x6f\xba\x2f\x4f\x84\x82\x68\x8f\x95\x2c\x3b\x3b\x9a\xe2\x80\xa5\x01\xd6\x75\xeb\xb6\xaa\x06\x04\xa1\x11\x78\xde\xc5\xff\x87\x99\xf6\xae\xb6\xe3\xc9\x5e\xc7\x38\x96\x41\xf0\x7b\xb6\x14\x03\x78\x4d\x59\x30\xb1\x16\x6b\xe1\xdc\xc7\x00\xb4\xe5\x9b\xe1\x2c\x03\x00\x00"),
 		},
 		"/jobsdb/000002_alter_dataset_tables.up.tmpl": &vfsgen۰CompressedFileInfo{
 			name:             "000002_alter_dataset_tables.up.tmpl",
-			modTime:          time.Date(2022, 7, 29, 6, 20, 18, 725637916, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 728,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa4\x92\x51\x6b\xdb\x30\x14\x85\xdf\xfd\x2b\x0e\xc5\xd0\x0e\x9a\xc0\x60\xec\xc5\x4f\x4e\xac\x36\x06\x57\x0e\x8e\xb2\x66\x4f\x46\x8e\xef\x6a\x17\x63\x1b\x49\xee\x5a\x84\xfe\xfb\x90\xb3\xa5\x0c\x06\x19\xdb\x9b\xef\xf5\xb9\xdf\xbd\xe7\x20\x6b\x95\xec\x9f\x08\xcb\x44\x1a\xa9\xc9\x68\xe7\x02\x00\x88\x33\xc1\x0a\x88\x78\x95\x31\x5c\x59\x1b\x2e\xb7\x8a\xbe\xb5\xaf\xce\x95\xcf\x43\xa5\x4b\x6b\x97\xce\x5d\xfd\x54\xad\xf3\x6c\xff\xc0\x71\x54\x24\x0d\xd5\xa5\x34\xd0\x64\x90\xb0\xbb\x78\x9f\x09\xf0\xfc\xf1\xe6\x43\xf4\xaf\x50\x7a\x1d\x5b\x45\xff\xcf\x4c\x92\x5f\xc4\xf4\x0e\x3c\x17\x60\x87\x74\x27\x76\x98\x34\xa9\xb2\xad\x21\xd8\x41\xcc\x7d\xbe\xcf\xb2\xf3\x9e\xeb\xc5\xc7\xeb\x28\x98\xf7\x2c\x16\x58\x0f\xfd\x0b\x29\x83\xe7\xa1\x2a\xb5\x91\x86\x60\x06\xbc\x48\x75\x6c\xa4\xba\x45\xad\x86\x11\xe6\x6d\xa4\xf7\xff\xa5\x2f\x2f\x5f\x39\x8b\xa7\x3f\xfb\x7f\xdf\x25\xbe\x6e\x19\xbe\xc4\xc5\x7a\x13\x17\x37\x9f\x3f\xfd\x8d\xfd\x4b\x60\xef\xdb\x53\x57\xe9\x7d\xca\x45\x14\x9c\x9d\x3e\xb4\x4f\x8d\x81\x36\x6d\xd7\xa1\x22\x1f\x52\x8d\xea\x0d\x83\x69\x48\xf9\x49\x5d\x57\x68\x7b\x6d\x64\x7f\xa4\x5b\x74\x52\x1b\x0c\x3d\x61\x1a\x6b\xff\x02\xf0\xdd\xcf\x9d\xf2\x68\x68\xce\x64\x39\x93\x93\x1c\x61\x88\x15\xbb\x4f\xf9\x5c\xcf\xbd\x22\xdf\x9e\xae\xf8\x3d\xb6\xe8\xac\x60\x87\x35\xdb\x8a\x34\xe7\x78\xdc\x30\x8e\x5c\x6c\x58\xb1\x83\xf0\xdf\xfd\xd4\x75\x27\x21\xe3\x09\xc2\x30\x0a\xac\xa5\xbe\x76\x2e\xf8\x11\x00\x00\xff\xff\x27\xcd\x91\x07\xd8\x02\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa4\x92\x51\x6b\xdb\x30\x14\x85\xdf\xfd\x2b\x0e\xc5\xd0\x0e\x9a\xc0\x60\xec\xc5\x4f\x4e\xac\x36\x06\x57\x0e\x8e\xb2\x66\x4f\x46\x8e\xee\x1a\x17\x63\x1b\x49\xee\x5a\x84\xfe\xfb\xb0\xbd\xa5\x0c\x06\x19\xdb\x9b\xef\xf5\xb9\xdf\xbd\xe7\x20\xe7\xb4\x6c\x9f\x08\xcb\x44\x5a\x69\xc8\x1a\xef\x03\x00\x88\x33\xc1\x0a\x88\x78\x95\x31\x5c\x39\x17\x2e\xb7\x9a\xbe\xd5\xaf\xde\x97\xcf\x5d\x65\x4a\xe7\x96\xde\x5f\xfd\x54\xad\xf3\x6c\xff\xc0\x71\xd4\x24\x2d\xa9\x52\x5a\x18\xb2\x48\xd8\x5d\xbc\xcf\x04\x78\xfe\x78\xf3\x21\xfa\x57\x28\xbd\xf6\xb5\xa6\xff\x67\x26\xc9\x2f\x62\x7a\x07\x9e\x0b\xb0\x43\xba\x13\x3b\x0c\x86\x74\x59\x2b\x08\x76\x10\x53\x9f\xef\xb3\xec\xbc\xe7\x7a\xf1\xf1\x3a\x0a\xa6\x3d\x8b\x05\xd6\x5d\xfb\x42\xda\xe2\xb9\xab\x4a\x63\xa5\x25\xd8\x0e\x2f\x52\x1f\x4f\x52\xdf\x42\xe9\xae\x87\x7d\xeb\xe9\xfd\x7f\x39\x96\x97\xaf\x9c\xc4\xc3\x9f\xfd\xbf\xef\x12\x5f\xb7\x0c\x5f\xe2\x62\xbd\x89\x8b\x9b\xcf\x9f\xfe\xc6\xfe\x25\x70\xad\x66\xea\x2a\xbd\x4f\xb9\x88\x82\xb3\xd3\x87\xfa\xe9\x64\x61\x6c\xdd\x34\xa8\x68\x0c\x49\xa1\x7a\x43\x67\x4f\xa4\xc7\x49\xa3\x2a\xd4\xad\xb1\xb2\x3d\xd2\x2d\x1a\x69\x2c\xba\x96\x30\xf4\x6a\x7c\x01\xf8\x3e\xce\xcd\x79\x9c\x68\xca\x64\x39\x91\x93\x1c\x61\x88\x15\xbb\x4f\xf9\x54\x4f\xbd\x22\xdf\xce\x57\xfc\x1e\x5b\x74\x56\xb0\xc3\x9a\x6d\x45\x9a\x73\x3c\x6e\x18\x47\x2e\x36\xac\xd8\x41\x8c\xdf\xed\xd0\x34\xb3\x90\xf1\x04\x61\x18\x05\xce\x51\xab\xbc\x0f\x7e\x0c\x00\x27\xcd\x91\x07\xd8\x02\x00\x00"),
 		},
 		"/jobsdb/000003_alter_journal_table.down.tmpl": &vfsgen۰FileInfo{
 			name:    "000003_alter_journal_table.down.tmpl",
-			modTime: time.Date(2022, 5, 11, 7, 57, 37, 772751086, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x2d\x2d\x20\x44\x72\x6f\x70\x20\x6a\x6f\x75\x72\x6e\x61\x6c\x20\x74\x61\x62\x6c\x65\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x75\x72\x6e\x61\x6c\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x6f\x77\x6e\x65\x72\x3b"),
 		},
 		"/jobsdb/000003_alter_journal_table.up.tmpl": &vfsgen۰FileInfo{
 			name:    "000003_alter_journal_table.up.tmpl",
-			modTime: time.Date(2022, 5, 11, 7, 57, 37, 772818753, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x2d\x2d\x20\x41\x64\x64\x20\x6f\x77\x6e\x65\x72\x20\x63\x6f\x6c\x75\x6d\x6e\x20\x74\x6f\x20\x4a\x6f\x75\x72\x6e\x61\x6c\x20\x74\x61\x62\x6c\x65\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x75\x72\x6e\x61\x6c\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x6f\x77\x6e\x65\x72\x20\x54\x45\x58\x54\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x27\x27\x3b"),
 		},
 		"/jobsdb/000004_alter_status_table.down.tmpl": &vfsgen۰FileInfo{
 			name:    "000004_alter_status_table.down.tmpl",
-			modTime: time.Date(2022, 7, 29, 6, 20, 18, 726039834, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x2d\x2d\x20\x44\x72\x6f\x70\x20\x73\x74\x61\x74\x75\x73\x20\x74\x61\x62\x6c\x65\x0a\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x62\x5f\x73\x74\x61\x74\x75\x73\x5f\x7b\x7b\x2e\x7d\x7d\x22\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x70\x61\x72\x61\x6d\x65\x74\x65\x72\x73\x3b\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d"),
 		},
 		"/jobsdb/000004_alter_status_table.up.tmpl": &vfsgen۰CompressedFileInfo{
 			name:             "000004_alter_status_table.up.tmpl",
-			modTime:          time.Date(2022, 7, 29, 6, 20, 18, 726432000, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 179,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x44\xcd\xcd\x6a\x84\x30\x14\x47\xf1\x7d\x9e\xe2\x8f\x14\x5c\xe9\x03\xd8\x55\x6c\x22\x58\x52\x2d\x35\x42\x77\x12\x6b\x5a\x5a\xfc\x28\x49\x84\x81\xcb\x7d\xf7\x81\x99\xc5\x6c\xcf\xe2\xfc\x8a\x02\x72\x59\xf0\xef\x82\xdb\x7c\xf2\x01\x5f\xc7\x7a\x6e\x3b\xd2\x81\x98\x5c\x3a\x23\x92\x9b\x57\x2f\x88\x82\xdb\x7f\x3c\x4a\xe5\x92\x8b\x3e\x45\x66\x01\x00\xd2\x58\xfd\x01\x2b\x6b\xa3\x91\x11\x3d\x95\xef\xc1\x7f\xff\x5e\x98\xa7\xbf\x63\x9e\xee\x8b\x89\xa8\x64\xce\x20\x95\xc2\x4b\x6f\xc6\xb7\x0e\x6d\x83\xae\xb7\xd0\x9f\xed\x60\x87\x87\x1e\xf1\x3a\xf4\x5d\x0d\xa5\x1b\x39\x1a\x8b\x9c\x38\xaf\xaa\x5b\x7b\x16\x44\x7e\x5f\x98\xc5\x35\x00\x00\xff\xff\x34\xb0\x81\x70\xb3\x00\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x44\xcd\xcd\x6a\x84\x30\x14\x47\xf1\x7d\x9e\xe2\x8f\x14\x5c\xe9\x03\xd8\x55\x6c\x22\x58\x52\x2d\x35\x42\x77\x12\x6b\x5a\x5a\xfc\x28\x49\x84\x81\xcb\x7d\xf7\x81\x99\xc5\x6c\xcf\xe2\xfc\x8a\x02\x72\x59\xf0\xef\x82\xdb\x7c\xf2\x01\x5f\xc7\x7a\x6e\x3b\xd2\x81\x98\x5c\x3a\x23\x92\x9b\x57\x2f\x88\x82\xdb\x7f\x3c\x4a\xe5\x92\x8b\x3e\x45\x66\x01\x00\xd2\x58\xfd\x01\x2b\x6b\xa3\x91\x11\x3d\x95\xef\xc1\x7f\xff\x5e\x98\xa7\xbf\x63\x9e\xee\x8b\x89\xa8\x64\xce\x20\x95\xc2\x4b\x6f\xc6\xb7\x0e\x6d\x83\xae\xb7\xd0\x9f\xed\x60\x87\x87\x1e\xf1\x3a\xf4\x5d\x0d\xa5\x1b\x39\x1a\x8b\x9c\x38\xaf\xaa\x5b\x7b\x16\x44\x7e\x5f\x98\xc5\x75\x00\x34\xb0\x81\x70\xb3\x00\x00\x00"),
 		},
 		"/jobsdb/000005_alter_dataset_table.down.tmpl": &vfsgen۰FileInfo{
 			name:    "000005_alter_dataset_table.down.tmpl",
-			modTime: time.Date(2022, 7, 29, 6, 20, 18, 726654816, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x62\x73\x5f\x7b\x7b\x2e\x7d\x7d\x22\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x65\x76\x65\x6e\x74\x5f\x63\x6f\x75\x6e\x74\x3b\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a"),
 		},
 		"/jobsdb/000005_alter_dataset_table.up.tmpl": &vfsgen۰FileInfo{
 			name:    "000005_alter_dataset_table.up.tmpl",
-			modTime: time.Date(2022, 7, 29, 6, 20, 18, 726872380, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x62\x73\x5f\x7b\x7b\x2e\x7d\x7d\x22\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x65\x76\x65\x6e\x74\x5f\x63\x6f\x75\x6e\x74\x20\x49\x4e\x54\x45\x47\x45\x52\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x31\x3b\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a"),
 		},
 		"/jobsdb/000006_alter_dataset_table.down.tmpl": &vfsgen۰FileInfo{
 			name:    "000006_alter_dataset_table.down.tmpl",
-			modTime: time.Date(2022, 7, 29, 6, 20, 18, 727075360, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x62\x73\x5f\x7b\x7b\x2e\x7d\x7d\x22\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x77\x6f\x72\x6b\x73\x70\x61\x63\x65\x5f\x69\x64\x3b\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a"),
 		},
 		"/jobsdb/000006_alter_dataset_table.up.tmpl": &vfsgen۰FileInfo{
 			name:    "000006_alter_dataset_table.up.tmpl",
-			modTime: time.Date(2022, 7, 29, 6, 20, 18, 727286966, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x62\x73\x5f\x7b\x7b\x2e\x7d\x7d\x22\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x77\x6f\x72\x6b\x73\x70\x61\x63\x65\x5f\x69\x64\x20\x54\x45\x58\x54\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x27\x27\x3b\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d\x20\x0a"),
 		},
 		"/jobsdb/000007_add_index_rt_table.down.tmpl": &vfsgen۰FileInfo{
 			name:    "000007_add_index_rt_table.down.tmpl",
-			modTime: time.Date(2022, 7, 29, 6, 20, 18, 727799271, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x7b\x7b\x20\x69\x66\x20\x65\x71\x20\x24\x2e\x50\x72\x65\x66\x69\x78\x20\x22\x72\x74\x22\x20\x7d\x7d\x0a\x20\x20\x20\x20\x20\x20\x20\x20\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x22\x63\x75\x73\x74\x6f\x6d\x76\x61\x6c\x5f\x77\x6f\x72\x6b\x73\x70\x61\x63\x65\x5f\x7b\x7b\x2e\x7d\x7d\x22\x3b\x0a\x20\x20\x20\x20\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a"),
 		},
 		"/jobsdb/000007_add_index_rt_table.up.tmpl": &vfsgen۰CompressedFileInfo{
 			name:             "000007_add_index_rt_table.up.tmpl",
-			modTime:          time.Date(2022, 7, 29, 6, 20, 18, 728152558, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 189,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x44\x8e\xc1\x0a\x82\x40\x18\x84\xef\x3e\xc5\xb0\x74\x28\x08\x5f\xa0\x53\xe4\x06\x5e\x34\xd2\x83\xb7\x65\xd3\x35\x2c\xd3\xda\xdd\x2c\xf8\xf9\xdf\x3d\x22\x97\xe6\x32\x87\x8f\x6f\x18\x22\xab\x87\xb3\x41\x9c\x68\xaf\x9d\xf1\x8e\x39\x02\x00\x22\x74\x2d\xcc\x03\x8b\xf8\x60\x4d\xdb\xbd\x21\xac\x17\x98\xe9\x37\xbb\xa3\xdc\x96\x12\x69\x96\xc8\x0a\xe9\x1e\x59\x5e\x42\x56\x69\x51\x16\x10\xf5\xd3\xf9\xf1\x36\xe9\x5e\xbd\x46\x7b\x75\x77\x5d\x1b\x45\x14\x33\x0b\xe4\x19\x04\x51\x58\x65\x56\x97\xf1\xe4\x02\x5c\xfe\x44\x35\xe9\x7e\xfd\x37\xbb\x66\xb5\x99\x4f\x99\xa1\x61\x8e\x42\x7f\x02\x00\x00\xff\xff\x32\x5c\xea\x10\xbd\x00\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x44\x8e\xc1\x0a\x82\x40\x18\x84\xef\x3e\xc5\xb0\x74\x28\x08\x5f\xa0\x53\xe4\x06\x5e\x34\xd2\x83\xb7\x65\xd3\x35\x2c\xd3\xda\xdd\x2c\xf8\xf9\xdf\x3d\x22\x97\xe6\x32\x87\x8f\x6f\x18\x22\xab\x87\xb3\x41\x9c\x68\xaf\x9d\xf1\x8e\x39\x02\x00\x22\x74\x2d\xcc\x03\x8b\xf8\x60\x4d\xdb\xbd\x21\xac\x17\x98\xe9\x37\xbb\xa3\xdc\x96\x12\x69\x96\xc8\x0a\xe9\x1e\x59\x5e\x42\x56\x69\x51\x16\x10\xf5\xd3\xf9\xf1\x36\xe9\x5e\xbd\x46\x7b\x75\x77\x5d\x1b\x45\x14\x33\x0b\xe4\x19\x04\x51\x58\x65\x56\x97\xf1\xe4\x02\x5c\xfe\x44\x35\xe9\x7e\xfd\x37\xbb\x66\xb5\x99\x4f\x99\xa1\x61\x8e\x42\x7f\x06\x00\x32\x5c\xea\x10\xbd\x00\x00\x00"),
 		},
 		"/jobsdb/000008_alter_user_id.up.tmpl": &vfsgen۰CompressedFileInfo{
 			name:             "000008_alter_user_id.up.tmpl",
-			modTime:          time.Date(2022, 7, 29, 8, 15, 4, 777193557, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 277,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9c\xcc\xb1\x0a\xc2\x30\x14\x85\xe1\xbd\x4f\x71\x08\x42\x2c\x94\x80\xae\xb6\x05\xa1\x01\xc7\xa2\x15\xc7\x10\x69\x94\x88\xd4\x92\x44\x10\x6e\xef\xbb\x8b\x08\x6e\x2e\xdd\xce\x19\xbe\x9f\x28\xd8\xe1\xea\xa0\x1a\x9b\x6c\x74\x29\x32\x67\x00\x70\x6c\x9b\x6d\xa7\x21\x88\x16\xaa\x0d\xee\xe2\x5f\xcc\xe6\xf6\x38\x47\x43\xa4\x98\x05\x0e\xba\x83\x78\x46\x17\x8c\xef\x05\x2a\xc4\xf1\xee\x93\x19\x6d\x48\xcb\xbf\x48\xfd\x40\x21\xcb\xb2\xae\x65\xb1\xce\x31\x4d\xf8\x9e\xcf\x9a\x5d\x39\xed\xf4\x5e\xcf\xe4\xab\x1c\x15\xa4\xdc\x64\x44\x6e\xe8\x99\xb3\x77\x00\x00\x00\xff\xff\xb2\x71\x7c\x17\x15\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9c\xcc\xb1\x0a\xc2\x30\x14\x85\xe1\xbd\x4f\x71\x08\x42\x2c\x94\x80\xae\xb6\x05\xa1\x01\xc7\xa2\x15\xc7\x10\x69\x94\x88\xd4\x92\x44\x10\x6e\xef\xbb\x8b\x08\x6e\x2e\xdd\xce\x19\xbe\x9f\x28\xd8\xe1\xea\xa0\x1a\x9b\x6c\x74\x29\x32\x67\x00\x70\x6c\x9b\x6d\xa7\x21\x88\x16\xaa\x0d\xee\xe2\x5f\xcc\xe6\xf6\x38\x47\x43\xa4\x98\x05\x0e\xba\x83\x78\x46\x17\x8c\xef\x05\x2a\xc4\xf1\xee\x93\x19\x6d\x48\xcb\xbf\x48\xfd\x40\x21\xcb\xb2\xae\x65\xb1\xce\x31\x4d\xf8\x9e\xcf\x9a\x5d\x39\xed\xf4\x5e\xcf\xe4\xab\x1c\x15\xa4\xdc\x64\x44\x6e\xe8\x99\xb3\xf7\x00\xb2\x71\x7c\x17\x15\x01\x00\x00"),
+		},
+		"/jobsdb/000009_alter_dataset_table.down.tmpl": &vfsgen۰FileInfo{
+			name:    "000009_alter_dataset_table.down.tmpl",
+			modTime: time.Date(2026, 10, 16, 19, 27, 36, 502598651, time.UTC),
+			content: []byte("\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x62\x73\x5f\x7b\x7b\x2e\x7d\x7d\x22\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x65\x78\x70\x69\x72\x79\x5f\x74\x69\x6d\x65\x3b\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a"),
+		},
+		"/jobsdb/000009_alter_dataset_table.up.tmpl": &vfsgen۰FileInfo{
+			name:    "000009_alter_dataset_table.up.tmpl",
+			modTime: time.Date(2026, 10, 16, 19, 27, 36, 498598651, time.UTC),
+			content: []byte("\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x62\x73\x5f\x7b\x7b\x2e\x7d\x7d\x22\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x65\x78\x70\x69\x72\x79\x5f\x74\x69\x6d\x65\x20\x54\x49\x4d\x45\x53\x54\x41\x4d\x50\x3b\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a"),
+		},
+		"/jobsdb/000010_alter_dataset_table.down.tmpl": &vfsgen۰FileInfo{
+			name:    "000010_alter_dataset_table.down.tmpl",
+			modTime: time.Date(2026, 10, 16, 19, 27, 36, 506598651, time.UTC),
+			content: []byte("\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x62\x73\x5f\x7b\x7b\x2e\x7d\x7d\x22\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x70\x72\x69\x6f\x72\x69\x74\x79\x3b\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a"),
+		},
+		"/jobsdb/000010_alter_dataset_table.up.tmpl": &vfsgen۰FileInfo{
+			name:    "000010_alter_dataset_table.up.tmpl",
+			modTime: time.Date(2026, 10, 16, 19, 27, 36, 502598651, time.UTC),
+			content: []byte("\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x62\x73\x5f\x7b\x7b\x2e\x7d\x7d\x22\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x70\x72\x69\x6f\x72\x69\x74\x79\x20\x53\x4d\x41\x4c\x4c\x49\x4e\x54\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x30\x3b\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a"),
+		},
+		"/jobsdb/000011_alter_dataset_table.down.tmpl": &vfsgen۰FileInfo{
+			name:    "000011_alter_dataset_table.down.tmpl",
+			modTime: time.Date(2026, 10, 16, 19, 27, 36, 517937386, time.UTC),
+			content: []byte("\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x62\x73\x5f\x7b\x7b\x2e\x7d\x7d\x22\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x65\x6e\x63\x72\x79\x70\x74\x69\x6f\x6e\x5f\x6b\x65\x79\x5f\x69\x64\x3b\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a"),
+		},
+		"/jobsdb/000011_alter_dataset_table.up.tmpl": &vfsgen۰FileInfo{
+			name:    "000011_alter_dataset_table.up.tmpl",
+			modTime: time.Date(2026, 10, 16, 19, 27, 36, 510598651, time.UTC),
+			content: []byte("\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x62\x73\x5f\x7b\x7b\x2e\x7d\x7d\x22\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x65\x6e\x63\x72\x79\x70\x74\x69\x6f\x6e\x5f\x6b\x65\x79\x5f\x69\x64\x20\x54\x45\x58\x54\x3b\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a"),
+		},
+		"/jobsdb/000012_alter_dataset_table.up.tmpl": &vfsgen۰FileInfo{
+			name:    "000012_alter_dataset_table.up.tmpl",
+			modTime: time.Date(2026, 10, 16, 19, 27, 41, 876483206, time.UTC),
+			content: []byte("\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x62\x73\x5f\x7b\x7b\x2e\x7d\x7d\x22\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x65\x78\x70\x69\x72\x79\x5f\x74\x69\x6d\x65\x20\x54\x49\x4d\x45\x53\x54\x41\x4d\x50\x3b\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a"),
+		},
+		"/jobsdb/000013_alter_dataset_table.down.tmpl": &vfsgen۰FileInfo{
+			name:    "000013_alter_dataset_table.down.tmpl",
+			modTime: time.Date(2026, 10, 16, 19, 28, 13, 875622228, time.UTC),
+			content: []byte("\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x62\x73\x5f\x7b\x7b\x2e\x7d\x7d\x22\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x70\x6c\x61\x69\x6e\x74\x65\x78\x74\x5f\x73\x69\x7a\x65\x3b\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a"),
+		},
+		"/jobsdb/000013_alter_dataset_table.up.tmpl": &vfsgen۰FileInfo{
+			name:    "000013_alter_dataset_table.up.tmpl",
+			modTime: time.Date(2026, 10, 16, 19, 28, 13, 870598651, time.UTC),
+			content: []byte("\x7b\x7b\x72\x61\x6e\x67\x65\x20\x2e\x44\x61\x74\x61\x73\x65\x74\x73\x7d\x7d\x0a\x20\x20\x20\x20\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x22\x7b\x7b\x24\x2e\x50\x72\x65\x66\x69\x78\x7d\x7d\x5f\x6a\x6f\x62\x73\x5f\x7b\x7b\x2e\x7d\x7d\x22\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x70\x6c\x61\x69\x6e\x74\x65\x78\x74\x5f\x73\x69\x7a\x65\x20\x49\x4e\x54\x45\x47\x45\x52\x3b\x0a\x7b\x7b\x65\x6e\x64\x7d\x7d\x0a"),
 		},
 		"/node": &vfsgen۰DirInfo{
 			name:    "node",
-			modTime: time.Date(2022, 6, 6, 18, 19, 20, 993640377, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 		},
 		"/node/000001_create_event_schema.down.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000001_create_event_schema.down.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 773599134, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 279,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd2\xd5\xd5\xe5\xd2\xd5\xd5\x55\x70\x2d\x4b\xcd\x2b\x51\xf0\xcd\x4f\x49\xcd\x29\x06\x09\x70\x71\xb9\x04\xf9\x07\x28\x84\x38\x3a\xf9\xb8\x2a\x78\xba\x29\xb8\x46\x78\x06\x87\x04\x2b\xa4\x82\x94\xc5\xe7\x82\x95\x59\x43\xd5\x78\xfa\xb9\xb8\x46\x60\x57\x13\x5f\x5e\x94\x59\x92\x1a\x9f\x9d\x5a\x19\x9f\x99\x97\x92\x5a\x61\xcd\xc5\x05\xb3\x50\x21\x38\x39\x23\x35\x37\x51\x21\x2c\xb5\xa8\x38\x33\x3f\x0f\x9f\xa5\xc5\x60\x95\xf1\x65\x50\x95\xc4\xd8\x9b\x99\x02\xb3\x90\x18\xa5\x50\x0b\x32\x12\x8b\x33\xa0\xda\x00\x01\x00\x00\xff\xff\xcc\x7f\x3b\xaa\x17\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd2\xd5\xd5\xe5\xd2\xd5\xd5\x55\x70\x2d\x4b\xcd\x2b\x51\xf0\xcd\x4f\x49\xcd\x29\x06\x09\x70\x71\xb9\x04\xf9\x07\x28\x84\x38\x3a\xf9\xb8\x2a\x78\xba\x29\xb8\x46\x78\x06\x87\x04\x2b\xa4\x82\x94\xc5\xe7\x82\x95\x59\x43\xd5\x78\xfa\xb9\xb8\x46\x60\x57\x13\x5f\x5e\x94\x59\x92\x1a\x9f\x9d\x5a\x19\x9f\x99\x97\x92\x5a\x61\xcd\xc5\x05\xb3\x50\x21\x38\x39\x23\x35\x37\x51\x21\x2c\xb5\xa8\x38\x33\x3f\x0f\x9f\xa5\xc5\x60\x95\xf1\x65\x50\x95\xc4\xd8\x9b\x99\x02\xb3\x90\x18\xa5\x50\x0b\x32\x12\x8b\x33\xa0\xda\x00\x03\x00\xcc\x7f\x3b\xaa\x17\x01\x00\x00"),
 		},
 		"/node/000001_create_event_schema.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000001_create_event_schema.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 773663385, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 945,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x94\x91\x4d\x6f\xe2\x30\x10\x86\xcf\xc9\xaf\x98\x1b\x44\x22\x97\x5d\x69\x0f\x70\x32\x60\x76\xbd\xcd\x07\x4d\x1c\x0a\x27\x2b\xc5\x83\x70\x0b\xa1\x4a\x0c\x2d\xaa\xfa\xdf\x2b\x87\xcf\x40\x11\x70\xcd\x3c\xf3\x4e\xfc\xbc\xae\xeb\xda\xae\xeb\x02\x5d\x61\xa6\xc1\x5f\x48\x9c\x15\xe6\x83\x6d\x77\x22\x4a\x38\x05\x4e\xda\x1e\x05\xd6\x83\x20\xe4\x40\x87\x2c\xe6\x31\xa0\x81\xc5\xbc\x84\xa1\x6e\x5b\x96\x92\x10\xd3\x88\x11\x0f\xfa\x11\xf3\x49\x34\x82\x07\x3a\x6a\xd8\x96\xb5\x5c\x2a\x09\x03\x12\x75\xfe\x91\xa8\xfe\xfb\x8f\x53\xa6\x04\x89\xe7\x99\xe1\x7b\xae\x34\x8a\x57\x5c\x1f\x88\x5f\x55\x62\x73\x48\xaf\xdf\x10\x38\x1d\xf2\x1f\x66\xe5\x4f\x08\x25\x31\xd3\x6a\xa2\x30\xaf\x72\xd0\xa5\x3d\x92\x78\x1c\x6a\x35\xb3\x32\xce\x31\xd5\x28\x45\xaa\x81\x33\x9f\xc6\x9c\xf8\xfd\x73\x36\x08\x9f\xea\x8e\xd3\xda\x1b\x60\x41\x97\x0e\x2f\x1b\x10\xfb\x67\x08\x95\x49\xfc\x80\x30\x38\x11\xb4\x07\x4c\xe8\x4e\x38\xc4\xe3\x29\xce\x53\x18\x60\x5e\xa8\x45\x76\x5d\x7a\x51\xf2\x62\xb5\xe5\x77\xde\xdb\xec\xef\xbd\xea\xe1\xcc\xde\xc5\x8a\xb6\x47\xa7\x69\x31\xbd\x58\xd2\x86\x81\xff\x71\x18\xb4\x2b\x83\x39\xea\x54\xa6\xfa\x74\x74\x28\xe5\xf3\xab\xd6\x6c\xbe\x14\x8b\xec\xd9\xe0\x13\x95\x17\x5a\x14\x88\xd9\xd5\x76\x0c\x3e\x4b\x6f\xa5\xef\xe8\x52\xc9\x43\x89\x67\xc2\xab\xa0\xd3\xda\x85\x26\x01\x7b\x4c\x6e\xca\x3e\xd2\x79\xf3\x9d\x06\x1c\x6d\x39\xad\xef\x00\x00\x00\xff\xff\x0d\x70\xf8\x26\xb1\x03\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x94\x91\x4d\x6f\xe2\x30\x10\x86\xcf\xc9\xaf\x98\x1b\x44\x22\x97\x5d\x69\x0f\x70\x32\x60\x76\xbd\xcd\x07\x4d\x1c\x0a\x27\x2b\xc5\x83\x70\x0b\xa1\x4a\x0c\x2d\xaa\xfa\xdf\x2b\x87\xcf\x40\x11\x70\xcd\x3c\xf3\x4e\xfc\xbc\xae\xeb\xda\xae\xeb\x02\x5d\x61\xa6\xc1\x5f\x48\x9c\x15\xe6\x83\x6d\x77\x22\x4a\x38\x05\x4e\xda\x1e\x05\xd6\x83\x20\xe4\x40\x87\x2c\xe6\x31\xa0\x81\xc5\xbc\x84\xa1\x6e\x5b\x96\x92\x10\xd3\x88\x11\x0f\xfa\x11\xf3\x49\x34\x82\x07\x3a\x6a\xd8\x96\xb5\x5c\x2a\x09\x03\x12\x75\xfe\x91\xa8\xfe\xfb\x8f\x53\xa6\x04\x89\xe7\x99\xe1\x7b\xae\x34\x8a\x57\x5c\x1f\x88\x5f\x55\x62\x73\x48\xaf\xdf\x10\x38\x1d\xf2\x1f\x66\xe5\x4f\x08\x25\x31\xd3\x6a\xa2\x30\xaf\x72\xd0\xa5\x3d\x92\x78\x1c\x6a\x35\xb3\x32\xce\x31\xd5\x28\x45\xaa\x81\x33\x9f\xc6\x9c\xf8\xfd\x73\x36\x08\x9f\xea\x8e\xd3\xda\x1b\x60\x41\x97\x0e\x2f\x1b\x10\xfb\x67\x08\x95\x49\xfc\x80\x30\x38\x11\xb4\x07\x4c\xe8\x4e\x38\xc4\xe3\x29\xce\x53\x18\x60\x5e\xa8\x45\x76\x5d\x7a\x51\xf2\x62\xb5\xe5\x77\xde\xdb\xec\xef\xbd\xea\xe1\xcc\xde\xc5\x8a\xb6\x47\xa7\x69\x31\xbd\x58\xd2\x86\x81\xff\x71\x18\xb4\x2b\x83\x39\xea\x54\xa6\xfa\x74\x74\x28\xe5\xf3\xab\xd6\x6c\xbe\x14\x8b\xec\xd9\xe0\x13\x95\x17\x5a\x14\x88\xd9\xd5\x76\x0c\x3e\x4b\x6f\xa5\xef\xe8\x52\xc9\x43\x89\x67\xc2\xab\xa0\xd3\xda\x85\x26\x01\x7b\x4c\x6e\xca\x3e\xd2\x79\xf3\x9d\x06\x1c\x6d\x39\xad\xef\x01\x00\x0d\x70\xf8\x26\xb1\x03\x00\x00"),
 		},
 		"/node/000002_create_col_counters_event_schema.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000002_create_col_counters_event_schema.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 773733635, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 309,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8c\x90\x4f\x4b\x03\x31\x10\x47\xcf\xcd\xa7\xf8\xdd\xaa\x87\x80\xe7\xf6\x94\xed\xa6\x12\x89\x59\xec\x26\xe0\x6d\x89\xdd\x80\x91\x36\xd1\xfc\xe9\x45\xfc\xee\x92\x05\x3d\x89\xf4\x38\xcc\xf0\xde\x63\x28\xa5\x84\x52\x0a\x36\xcf\xd8\xc5\x53\x3d\x07\x94\x88\x5c\x62\x72\x38\xc6\x1a\x8a\x4b\x19\xb3\x2d\xb6\x5d\x11\xc2\xa4\xe6\x07\x68\xd6\x49\x8e\x7c\x7c\x75\x67\x3b\x5d\x5c\xca\x3e\x86\x0c\xb2\x5a\xb1\xbe\xc7\x6e\x90\xe6\x51\x41\xec\xa1\x06\x0d\xfe\x2c\x46\x3d\xe2\x3d\xf9\x8b\x2d\x6e\x6a\x24\x3c\x8c\x83\xea\x96\xad\x32\x52\xa2\xe7\x7b\x66\xa4\xc6\xfa\xf3\x6b\xbd\xd9\xbc\xe5\x18\x5e\xb6\xff\x89\x7e\x3d\x6a\xd4\x07\x26\x94\x46\x0d\xfe\xa3\xba\xa9\x56\x3f\xc3\x28\xf1\x64\x38\x6e\xda\x70\x7b\x1d\xe7\x8f\xde\x12\x8b\x3d\x4d\xcb\x03\xd0\x89\xfb\x26\xf9\xc9\xbc\xdb\x92\xef\x00\x00\x00\xff\xff\x22\x3a\x04\x17\x35\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8c\x90\x4f\x4b\x03\x31\x10\x47\xcf\xcd\xa7\xf8\xdd\xaa\x87\x80\xe7\xf6\x94\xed\xa6\x12\x89\x59\xec\x26\xe0\x6d\x89\xdd\x80\x91\x36\xd1\xfc\xe9\x45\xfc\xee\x92\x05\x3d\x89\xf4\x38\xcc\xf0\xde\x63\x28\xa5\x84\x52\x0a\x36\xcf\xd8\xc5\x53\x3d\x07\x94\x88\x5c\x62\x72\x38\xc6\x1a\x8a\x4b\x19\xb3\x2d\xb6\x5d\x11\xc2\xa4\xe6\x07\x68\xd6\x49\x8e\x7c\x7c\x75\x67\x3b\x5d\x5c\xca\x3e\x86\x0c\xb2\x5a\xb1\xbe\xc7\x6e\x90\xe6\x51\x41\xec\xa1\x06\x0d\xfe\x2c\x46\x3d\xe2\x3d\xf9\x8b\x2d\x6e\x6a\x24\x3c\x8c\x83\xea\x96\xad\x32\x52\xa2\xe7\x7b\x66\xa4\xc6\xfa\xf3\x6b\xbd\xd9\xbc\xe5\x18\x5e\xb6\xff\x89\x7e\x3d\x6a\xd4\x07\x26\x94\x46\x0d\xfe\xa3\xba\xa9\x56\x3f\xc3\x28\xf1\x64\x38\x6e\xda\x70\x7b\x1d\xe7\x8f\xde\x12\x8b\x3d\x4d\xcb\x03\xd0\x89\xfb\x26\xf9\xc9\xbc\xdb\x92\xef\x01\x00\x22\x3a\x04\x17\x35\x01\x00\x00"),
 		},
 		"/node/000002_del_col_counters_event_schema.down.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000002_del_col_counters_event_schema.down.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 773853552, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 230,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9c\x8f\x41\x0a\x83\x30\x10\x45\xf7\x9e\xe2\x5f\x20\x27\x70\x95\x5a\x0b\x82\xd5\xa2\x29\x74\x17\x82\x09\x34\xa0\x99\x36\x99\xf1\xfc\xc5\x9e\xa0\x74\xff\xff\x7b\x3c\xa5\x54\xa5\x94\x82\xf6\x1e\x0d\xad\xb2\x25\x30\xa1\x30\xe5\x80\x85\x24\x71\xc8\x05\xde\xb1\x3b\x56\x55\xa5\x7b\xd3\x4e\x30\xfa\xd4\xb7\x28\xcb\x33\x6c\xce\xee\x21\x97\x48\xa9\xe0\x3c\x8d\x37\x34\x63\x7f\xbf\x0e\xe8\x2e\x68\x1f\xdd\x6c\x66\xbc\x72\xdc\x1d\x07\x7b\x30\xea\x5f\xfe\xc3\x6c\x26\xdd\x0d\x06\x92\xe2\x5b\x82\x15\x89\xbe\xfe\x43\xcc\xc4\x6e\xb5\xdf\x86\xfa\x13\x00\x00\xff\xff\x73\xa7\x01\x8c\xe6\x00\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9c\x8f\x41\x0a\x83\x30\x10\x45\xf7\x9e\xe2\x5f\x20\x27\x70\x95\x5a\x0b\x82\xd5\xa2\x29\x74\x17\x82\x09\x34\xa0\x99\x36\x99\xf1\xfc\xc5\x9e\xa0\x74\xff\xff\x7b\x3c\xa5\x54\xa5\x94\x82\xf6\x1e\x0d\xad\xb2\x25\x30\xa1\x30\xe5\x80\x85\x24\x71\xc8\x05\xde\xb1\x3b\x56\x55\xa5\x7b\xd3\x4e\x30\xfa\xd4\xb7\x28\xcb\x33\x6c\xce\xee\x21\x97\x48\xa9\xe0\x3c\x8d\x37\x34\x63\x7f\xbf\x0e\xe8\x2e\x68\x1f\xdd\x6c\x66\xbc\x72\xdc\x1d\x07\x7b\x30\xea\x5f\xfe\xc3\x6c\x26\xdd\x0d\x06\x92\xe2\x5b\x82\x15\x89\xbe\xfe\x43\xcc\xc4\x6e\xb5\xdf\x86\xfa\x33\x00\x73\xa7\x01\x8c\xe6\x00\x00\x00"),
 		},
 		"/node/000003_add_event_model_columns.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000003_add_event_model_columns.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 773937386, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 553,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xb4\xd0\xc1\x4a\xc3\x40\x10\x80\xe1\x73\xf3\x14\x73\xab\x1e\x16\x3c\xb7\xa7\x8d\x49\x25\xb2\xd9\x88\xd9\xa0\xb7\x30\x66\x07\x5d\xc9\xce\x4a\x76\xda\x8b\xf8\xee\x62\x41\x41\x7a\x2b\xf4\x3a\x03\xdf\x3f\x8c\x52\xaa\x50\x4a\x81\xf6\x3e\xf0\x2b\x4c\x69\xde\x47\xce\x20\x09\x22\x06\x16\x0c\x0c\x11\xb3\xd0\x02\x79\x7a\xa3\x88\x80\xec\x61\x4a\x7b\x16\x5a\x32\x04\x06\x3a\x10\xcb\x18\x93\xa7\x39\xff\x48\x45\xa1\x8d\xab\x1f\xc1\xe9\xd2\xd4\xff\xb7\xab\x95\xae\x2a\xb8\xed\xcc\xd0\x5a\x68\x76\x60\x3b\x07\xf5\x73\xd3\xbb\xfe\x17\xbf\xef\x3b\x5b\x1e\xe7\x76\x30\x06\xaa\x7a\xa7\x07\xe3\x60\xfd\xf9\xb5\xde\x6c\xde\x73\xe2\x97\xed\x59\x7c\x24\x41\x8f\x72\xb9\xc0\xc7\x12\x0e\x28\x34\x5e\x34\x22\x49\x70\x1e\x8f\xcf\x87\xb2\xb9\x6b\xac\xfb\xb3\x6f\xce\x13\x67\xcc\x32\x66\x22\x06\xd7\xb4\x75\xef\x74\xfb\x70\x7a\xb7\xed\x9e\xae\xae\xb7\xdf\x01\x00\x00\xff\xff\x31\x24\x19\x95\x29\x02\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xb4\xd0\xc1\x4a\xc3\x40\x10\x80\xe1\x73\xf3\x14\x73\xab\x1e\x16\x3c\xb7\xa7\x8d\x49\x25\xb2\xd9\x88\xd9\xa0\xb7\x30\x66\x07\x5d\xc9\xce\x4a\x76\xda\x8b\xf8\xee\x62\x41\x41\x7a\x2b\xf4\x3a\x03\xdf\x3f\x8c\x52\xaa\x50\x4a\x81\xf6\x3e\xf0\x2b\x4c\x69\xde\x47\xce\x20\x09\x22\x06\x16\x0c\x0c\x11\xb3\xd0\x02\x79\x7a\xa3\x88\x80\xec\x61\x4a\x7b\x16\x5a\x32\x04\x06\x3a\x10\xcb\x18\x93\xa7\x39\xff\x48\x45\xa1\x8d\xab\x1f\xc1\xe9\xd2\xd4\xff\xb7\xab\x95\xae\x2a\xb8\xed\xcc\xd0\x5a\x68\x76\x60\x3b\x07\xf5\x73\xd3\xbb\xfe\x17\xbf\xef\x3b\x5b\x1e\xe7\x76\x30\x06\xaa\x7a\xa7\x07\xe3\x60\xfd\xf9\xb5\xde\x6c\xde\x73\xe2\x97\xed\x59\x7c\x24\x41\x8f\x72\xb9\xc0\xc7\x12\x0e\x28\x34\x5e\x34\x22\x49\x70\x1e\x8f\xcf\x87\xb2\xb9\x6b\xac\xfb\xb3\x6f\xce\x13\x67\xcc\x32\x66\x22\x06\xd7\xb4\x75\xef\x74\xfb\x70\x7a\xb7\xed\x9e\xae\xae\xb7\xdf\x03\x00\x31\x24\x19\x95\x29\x02\x00\x00"),
 		},
 		"/node/000003_remove_event_model_columns.down.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000003_remove_event_model_columns.down.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 774010554, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 390,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9c\xcf\x41\x4a\xc6\x30\x10\xc5\xf1\x7d\x4e\xf1\x2e\x90\x13\x7c\xab\xaa\x15\x0a\xd5\x4a\x5b\xc1\x5d\x18\x9a\xc1\x06\x92\x89\x64\xa6\x3d\xbf\x28\x6e\x5c\xf6\x5b\x3f\x7e\x7f\x78\xde\x7b\xe7\xbd\xc7\xcc\xa5\x9e\x49\x3e\xb1\xd5\x7c\x14\x51\xd8\x4e\x06\x6a\x8c\x43\x39\xc2\x2a\x0a\x25\x31\x4a\x82\x42\x6a\xdc\xa0\xdb\xce\x85\x40\x12\xb1\xd5\x43\x8c\x9b\x22\x09\xf8\x64\xb1\x50\x6a\xe4\xac\x3f\x65\xe7\xba\x71\xed\x67\xac\xdd\xc3\xd8\xff\x5b\xf1\x34\x4f\x6f\x78\x9c\xc6\xf7\x97\x57\x0c\xcf\xe8\x3f\x86\x65\x5d\xfe\xba\xb7\xab\xac\xb0\x51\x24\xbb\x0e\xbf\x5a\x3a\xc9\x38\xdc\x85\xad\x1a\xe5\xf0\xfb\xff\xb2\xcd\xa4\x16\x94\x59\x6e\xee\x3b\x00\x00\xff\xff\x23\x26\xf4\xfe\x86\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9c\xcf\x41\x4a\xc6\x30\x10\xc5\xf1\x7d\x4e\xf1\x2e\x90\x13\x7c\xab\xaa\x15\x0a\xd5\x4a\x5b\xc1\x5d\x18\x9a\xc1\x06\x92\x89\x64\xa6\x3d\xbf\x28\x6e\x5c\xf6\x5b\x3f\x7e\x7f\x78\xde\x7b\xe7\xbd\xc7\xcc\xa5\x9e\x49\x3e\xb1\xd5\x7c\x14\x51\xd8\x4e\x06\x6a\x8c\x43\x39\xc2\x2a\x0a\x25\x31\x4a\x82\x42\x6a\xdc\xa0\xdb\xce\x85\x40\x12\xb1\xd5\x43\x8c\x9b\x22\x09\xf8\x64\xb1\x50\x6a\xe4\xac\x3f\x65\xe7\xba\x71\xed\x67\xac\xdd\xc3\xd8\xff\x5b\xf1\x34\x4f\x6f\x78\x9c\xc6\xf7\x97\x57\x0c\xcf\xe8\x3f\x86\x65\x5d\xfe\xba\xb7\xab\xac\xb0\x51\x24\xbb\x0e\xbf\x5a\x3a\xc9\x38\xdc\x85\xad\x1a\xe5\xf0\xfb\xff\xb2\xcd\xa4\x16\x94\x59\x6e\xee\x7b\x00\x23\x26\xf4\xfe\x86\x01\x00\x00"),
 		},
 		"/node/000004_create_ops.down.sql": &vfsgen۰FileInfo{
 			name:    "000004_create_ops.down.sql",
-			modTime: time.Date(2022, 5, 11, 7, 57, 37, 774102596, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x2d\x2d\x2d\x0a\x2d\x2d\x2d\x20\x4f\x70\x65\x72\x61\x74\x69\x6f\x6e\x73\x0a\x2d\x2d\x2d\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6f\x70\x65\x72\x61\x74\x69\x6f\x6e\x73\x3b"),
 		},
 		"/node/000004_create_ops.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000004_create_ops.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 774199305, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 375,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x84\x8d\xb1\x4e\xf3\x30\x14\x46\x67\xfb\x29\xbe\xad\x89\x54\x0f\xff\x3f\x21\x31\x39\xc5\x55\x0d\x8e\x5d\x39\x0e\xa5\x2c\x91\xd5\x78\x88\x04\x71\x94\xb8\x03\x3c\x3d\x4a\x84\x22\xba\xc0\xdd\xee\x3d\x47\xe7\x32\xc6\x28\x63\x0c\x66\x08\xa3\x4f\x5d\xec\xa7\x79\xa5\x74\x67\x05\x77\x02\x8e\x17\x4a\x40\xee\xa1\x8d\x83\x78\x91\x95\xab\x10\x57\x15\x19\x25\xa4\x6b\x51\x09\x2b\xb9\xc2\xd1\xca\x92\xdb\x33\x9e\xc4\x79\x4b\x09\x59\x3d\x3c\x73\xbb\x3b\x70\x9b\xfd\xfb\x7f\x97\x2f\x25\x5d\x2b\xb5\xa5\xf8\x9e\xc1\x7f\xbc\x45\xdf\xe2\xb1\x32\xba\xf8\xc1\x09\x69\x63\x1f\x50\x18\xa3\x04\xd7\x37\x20\x0e\xcd\x94\x7c\xba\x4e\x37\xed\x99\x5c\xc6\xe0\x53\x68\x1b\x9f\xe0\x64\x29\x2a\xc7\xcb\x23\x4e\xd2\x1d\x4c\xed\x96\x0b\x5e\x8d\x16\x6b\x0c\x0f\x62\xcf\x6b\xe5\x90\x69\x73\xca\x72\xf8\x84\xd4\xbd\x07\x7c\xce\x9f\x37\xd7\x74\xd9\x2c\xd5\x29\xf9\x31\x35\x0b\xf9\xa5\x3a\x9b\xa1\x6f\xff\xf4\xf2\xfb\xaf\x00\x00\x00\xff\xff\xdd\x5b\x28\x06\x77\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x84\x8d\xb1\x4e\xf3\x30\x14\x46\x67\xfb\x29\xbe\xad\x89\x54\x0f\xff\x3f\x21\x31\x39\xc5\x55\x0d\x8e\x5d\x39\x0e\xa5\x2c\x91\xd5\x78\x88\x04\x71\x94\xb8\x03\x3c\x3d\x4a\x84\x22\xba\xc0\xdd\xee\x3d\x47\xe7\x32\xc6\x28\x63\x0c\x66\x08\xa3\x4f\x5d\xec\xa7\x79\xa5\x74\x67\x05\x77\x02\x8e\x17\x4a\x40\xee\xa1\x8d\x83\x78\x91\x95\xab\x10\x57\x15\x19\x25\xa4\x6b\x51\x09\x2b\xb9\xc2\xd1\xca\x92\xdb\x33\x9e\xc4\x79\x4b\x09\x59\x3d\x3c\x73\xbb\x3b\x70\x9b\xfd\xfb\x7f\x97\x2f\x25\x5d\x2b\xb5\xa5\xf8\x9e\xc1\x7f\xbc\x45\xdf\xe2\xb1\x32\xba\xf8\xc1\x09\x69\x63\x1f\x50\x18\xa3\x04\xd7\x37\x20\x0e\xcd\x94\x7c\xba\x4e\x37\xed\x99\x5c\xc6\xe0\x53\x68\x1b\x9f\xe0\x64\x29\x2a\xc7\xcb\x23\x4e\xd2\x1d\x4c\xed\x96\x0b\x5e\x8d\x16\x6b\x0c\x0f\x62\xcf\x6b\xe5\x90\x69\x73\xca\x72\xf8\x84\xd4\xbd\x07\x7c\xce\x9f\x37\xd7\x74\xd9\x2c\xd5\x29\xf9\x31\x35\x0b\xf9\xa5\x3a\x9b\xa1\x6f\xff\xf4\xf2\xfb\xaf\x01\x00\xdd\x5b\x28\x06\x77\x01\x00\x00"),
 		},
 		"/node/000005_alter_event_schemas_autovacuum.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000005_alter_event_schemas_autovacuum.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 774308014, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 333,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd2\xd5\xd5\xe5\xd2\xd5\xd5\x55\x70\x2d\x4b\xcd\x2b\x51\xf0\xcd\x4f\x49\xcd\x29\x06\x09\x70\x71\x39\xfa\x84\xb8\x06\x29\x84\x38\x3a\xf9\xb8\x2a\xa4\x82\x64\xe3\x73\xc1\xb2\x0a\xc1\xae\x21\x0a\x1a\x89\xa5\x25\xf9\x65\x89\xc9\xa5\xa5\xb9\xf1\x50\xaa\x38\x39\x31\x27\x35\x3e\x2d\x31\xb9\x24\xbf\x48\xc1\x56\xc1\x40\xcf\xc0\x50\xd3\x9a\x64\x53\x92\xf3\x8b\x4b\xe2\x53\x52\x73\x12\x2b\x41\x66\x68\x5a\x73\x71\xc1\x5c\xa8\x10\x9c\x9c\x91\x9a\x9b\xa8\x10\x96\x5a\x54\x9c\x99\x9f\x87\xc5\x95\xc5\x60\x05\xf1\x65\x50\x05\xe4\x3b\x94\x48\x83\x30\xdc\x0a\x08\x00\x00\xff\xff\x58\x51\xf4\x80\x4d\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd2\xd5\xd5\xe5\xd2\xd5\xd5\x55\x70\x2d\x4b\xcd\x2b\x51\xf0\xcd\x4f\x49\xcd\x29\x06\x09\x70\x71\x39\xfa\x84\xb8\x06\x29\x84\x38\x3a\xf9\xb8\x2a\xa4\x82\x64\xe3\x73\xc1\xb2\x0a\xc1\xae\x21\x0a\x1a\x89\xa5\x25\xf9\x65\x89\xc9\xa5\xa5\xb9\xf1\x50\xaa\x38\x39\x31\x27\x35\x3e\x2d\x31\xb9\x24\xbf\x48\xc1\x56\xc1\x40\xcf\xc0\x50\xd3\x9a\x64\x53\x92\xf3\x8b\x4b\xe2\x53\x52\x73\x12\x2b\x41\x66\x68\x5a\x73\x71\xc1\x5c\xa8\x10\x9c\x9c\x91\x9a\x9b\xa8\x10\x96\x5a\x54\x9c\x99\x9f\x87\xc5\x95\xc5\x60\x05\xf1\x65\x50\x05\xe4\x3b\x94\x48\x83\x30\xdc\x0a\x18\x00\x58\x51\xf4\x80\x4d\x01\x00\x00"),
 		},
 		"/node/000006_add_archived_to_event_schemas_tables.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000006_add_archived_to_event_schemas_tables.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 774603475, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 261,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa4\xce\x41\xaa\x83\x30\x18\x04\xe0\xb5\x39\xc5\x5c\x20\x27\x78\xab\xf8\x8c\x20\xfc\xcf\xc0\x33\x42\x77\x9a\x9a\xbf\x28\xc4\x04\x8c\xf5\xfc\xa5\x85\x2e\xec\xb6\xeb\x61\xbe\x19\x29\xa5\x90\x52\x42\x79\x8f\x29\x85\xfb\x1a\x31\xba\x6d\x9a\x97\x83\xfd\x88\x3d\x81\x0f\x8e\xfb\xb0\x26\xcf\x21\xc3\x45\x8f\x3c\xcd\xbc\xba\xe1\xe0\x2d\x2f\x29\x66\xec\xee\x1a\x38\x3f\x11\x21\x14\x59\xfd\x0f\xab\x4a\xd2\xa7\xa2\x28\x0a\x55\x55\xf8\x35\xd4\xff\xb5\x68\x6a\xb4\xc6\x42\x5f\x9a\xce\x76\x78\xaf\xa1\x34\x86\x5e\x41\xdb\x13\xa1\xd2\xb5\xea\xc9\xe2\xe6\x42\xe6\x9f\x93\xfc\xf1\xe0\x7b\xfc\x11\x00\x00\xff\xff\x37\xf4\x3d\x62\x05\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa4\xce\x41\xaa\x83\x30\x18\x04\xe0\xb5\x39\xc5\x5c\x20\x27\x78\xab\xf8\x8c\x20\xfc\xcf\xc0\x33\x42\x77\x9a\x9a\xbf\x28\xc4\x04\x8c\xf5\xfc\xa5\x85\x2e\xec\xb6\xeb\x61\xbe\x19\x29\xa5\x90\x52\x42\x79\x8f\x29\x85\xfb\x1a\x31\xba\x6d\x9a\x97\x83\xfd\x88\x3d\x81\x0f\x8e\xfb\xb0\x26\xcf\x21\xc3\x45\x8f\x3c\xcd\xbc\xba\xe1\xe0\x2d\x2f\x29\x66\xec\xee\x1a\x38\x3f\x11\x21\x14\x59\xfd\x0f\xab\x4a\xd2\xa7\xa2\x28\x0a\x55\x55\xf8\x35\xd4\xff\xb5\x68\x6a\xb4\xc6\x42\x5f\x9a\xce\x76\x78\xaf\xa1\x34\x86\x5e\x41\xdb\x13\xa1\xd2\xb5\xea\xc9\xe2\xe6\x42\xe6\x9f\x93\xfc\xf1\xe0\x7b\xfc\x31\x00\x37\xf4\x3d\x62\x05\x01\x00\x00"),
 		},
 		"/node/000006_remove_archived_from_event_schemas_tables.down.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000006_remove_archived_from_event_schemas_tables.down.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 774687892, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 201,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x84\xcd\x4d\xaa\xc2\x30\x14\x47\xf1\x79\x57\xf1\xdf\xc0\x5d\xc1\x1b\xf5\x69\x85\x42\xb4\x92\x46\x70\xd6\xc6\xe4\x6a\x03\xf9\x80\xa4\x66\xfd\xe2\xc0\x41\x9d\xb8\x80\xdf\x39\x44\xd4\x10\x11\x24\x87\x54\x5d\x7c\xc0\x24\xff\x0c\x98\x75\x36\x8b\xab\x6c\x67\xdc\x73\x0a\xe0\xca\x71\x9d\x42\xb2\xec\x0b\x74\xb4\x28\x66\xe1\xa0\xa7\xca\xb9\xb8\x14\x0b\x56\x7d\xf3\x5c\xde\xa9\xa6\x69\x85\xea\x24\x54\xfb\x2f\xba\x2d\xdc\xcb\xe1\x8c\xdd\x20\x2e\xc7\x13\xfa\x03\xba\x6b\x3f\xaa\x11\x9f\xd5\xdf\x06\x7e\x0f\x7e\xd9\x57\x00\x00\x00\xff\xff\x94\xfc\xf5\xe6\xc9\x00\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x84\xcd\x4d\xaa\xc2\x30\x14\x47\xf1\x79\x57\xf1\xdf\xc0\x5d\xc1\x1b\xf5\x69\x85\x42\xb4\x92\x46\x70\xd6\xc6\xe4\x6a\x03\xf9\x80\xa4\x66\xfd\xe2\xc0\x41\x9d\xb8\x80\xdf\x39\x44\xd4\x10\x11\x24\x87\x54\x5d\x7c\xc0\x24\xff\x0c\x98\x75\x36\x8b\xab\x6c\x67\xdc\x73\x0a\xe0\xca\x71\x9d\x42\xb2\xec\x0b\x74\xb4\x28\x66\xe1\xa0\xa7\xca\xb9\xb8\x14\x0b\x56\x7d\xf3\x5c\xde\xa9\xa6\x69\x85\xea\x24\x54\xfb\x2f\xba\x2d\xdc\xcb\xe1\x8c\xdd\x20\x2e\xc7\x13\xfa\x03\xba\x6b\x3f\xaa\x11\x9f\xd5\xdf\x06\x7e\x0f\x7e\xd9\xd7\x00\x94\xfc\xf5\xe6\xc9\x00\x00\x00"),
 		},
 		"/node/000007_remove_ops.down.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000007_remove_ops.down.sql",
-			modTime:          time.Date(2022, 6, 6, 18, 19, 20, 993597293, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 375,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x84\x8d\xb1\x4e\xf3\x30\x14\x46\x67\xfb\x29\xbe\xad\x89\x54\x0f\xff\x3f\x21\x31\x39\xc5\x55\x0d\x8e\x5d\x39\x0e\xa5\x2c\x91\xd5\x78\x88\x04\x71\x94\xb8\x03\x3c\x3d\x4a\x84\x22\xba\xc0\xdd\xee\x3d\x47\xe7\x32\xc6\x28\x63\x0c\x66\x08\xa3\x4f\x5d\xec\xa7\x79\xa5\x74\x67\x05\x77\x02\x8e\x17\x4a\x40\xee\xa1\x8d\x83\x78\x91\x95\xab\x10\x57\x15\x19\x25\xa4\x6b\x51\x09\x2b\xb9\xc2\xd1\xca\x92\xdb\x33\x9e\xc4\x79\x4b\x09\x59\x3d\x3c\x73\xbb\x3b\x70\x9b\xfd\xfb\x7f\x97\x2f\x25\x5d\x2b\xb5\xa5\xf8\x9e\xc1\x7f\xbc\x45\xdf\xe2\xb1\x32\xba\xf8\xc1\x09\x69\x63\x1f\x50\x18\xa3\x04\xd7\x37\x20\x0e\xcd\x94\x7c\xba\x4e\x37\xed\x99\x5c\xc6\xe0\x53\x68\x1b\x9f\xe0\x64\x29\x2a\xc7\xcb\x23\x4e\xd2\x1d\x4c\xed\x96\x0b\x5e\x8d\x16\x6b\x0c\x0f\x62\xcf\x6b\xe5\x90\x69\x73\xca\x72\xf8\x84\xd4\xbd\x07\x7c\xce\x9f\x37\xd7\x74\xd9\x2c\xd5\x29\xf9\x31\x35\x0b\xf9\xa5\x3a\x9b\xa1\x6f\xff\xf4\xf2\xfb\xaf\x00\x00\x00\xff\xff\xdd\x5b\x28\x06\x77\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x84\x8d\xb1\x4e\xf3\x30\x14\x46\x67\xfb\x29\xbe\xad\x89\x54\x0f\xff\x3f\x21\x31\x39\xc5\x55\x0d\x8e\x5d\x39\x0e\xa5\x2c\x91\xd5\x78\x88\x04\x71\x94\xb8\x03\x3c\x3d\x4a\x84\x22\xba\xc0\xdd\xee\x3d\x47\xe7\x32\xc6\x28\x63\x0c\x66\x08\xa3\x4f\x5d\xec\xa7\x79\xa5\x74\x67\x05\x77\x02\x8e\x17\x4a\x40\xee\xa1\x8d\x83\x78\x91\x95\xab\x10\x57\x15\x19\x25\xa4\x6b\x51\x09\x2b\xb9\xc2\xd1\xca\x92\xdb\x33\x9e\xc4\x79\x4b\x09\x59\x3d\x3c\x73\xbb\x3b\x70\x9b\xfd\xfb\x7f\x97\x2f\x25\x5d\x2b\xb5\xa5\xf8\x9e\xc1\x7f\xbc\x45\xdf\xe2\xb1\x32\xba\xf8\xc1\x09\x69\x63\x1f\x50\x18\xa3\x04\xd7\x37\x20\x0e\xcd\x94\x7c\xba\x4e\x37\xed\x99\x5c\xc6\xe0\x53\x68\x1b\x9f\xe0\x64\x29\x2a\xc7\xcb\x23\x4e\xd2\x1d\x4c\xed\x96\x0b\x5e\x8d\x16\x6b\x0c\x0f\x62\xcf\x6b\xe5\x90\x69\x73\xca\x72\xf8\x84\xd4\xbd\x07\x7c\xce\x9f\x37\xd7\x74\xd9\x2c\xd5\x29\xf9\x31\x35\x0b\xf9\xa5\x3a\x9b\xa1\x6f\xff\xf4\xf2\xfb\xaf\x01\x00\xdd\x5b\x28\x06\x77\x01\x00\x00"),
 		},
 		"/node/000007_remove_ops.up.sql": &vfsgen۰FileInfo{
 			name:    "000007_remove_ops.up.sql",
-			modTime: time.Date(2022, 6, 6, 18, 19, 20, 993672669, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x2d\x2d\x2d\x0a\x2d\x2d\x2d\x20\x4f\x70\x65\x72\x61\x74\x69\x6f\x6e\x73\x0a\x2d\x2d\x2d\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6f\x70\x65\x72\x61\x74\x69\x6f\x6e\x73\x3b\x0a"),
 		},
 		"/pg_notifier_queue": &vfsgen۰DirInfo{
 			name:    "pg_notifier_queue",
-			modTime: time.Date(2022, 6, 6, 18, 19, 20, 993784461, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 		},
 		"/pg_notifier_queue/0000001_pg_notifier_queue_init.down.sql": &vfsgen۰CompressedFileInfo{
 			name:             "0000001_pg_notifier_queue_init.down.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 774964227, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 137,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x48\x8f\xcf\xcb\x2f\xc9\x4c\xcb\x4c\x2d\x8a\x2f\x2c\x4d\x2d\x4d\xb5\xe6\xe2\x82\x28\x8c\x0c\xc0\xa5\xae\xb8\x34\xa9\x38\xb9\x28\x33\x09\xc4\x2c\x49\x2c\x29\x2d\x26\x46\x0f\x58\x61\x7c\x49\x65\x41\xaa\x35\x20\x00\x00\xff\xff\xd9\x51\xd7\xeb\x89\x00\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x48\x8f\xcf\xcb\x2f\xc9\x4c\xcb\x4c\x2d\x8a\x2f\x2c\x4d\x2d\x4d\xb5\xe6\xe2\x82\x28\x8c\x0c\xc0\xa5\xae\xb8\x34\xa9\x38\xb9\x28\x33\x09\xc4\x2c\x49\x2c\x29\x2d\x26\x46\x0f\x58\x61\x7c\x49\x65\x41\xaa\x35\x60\x00\xd9\x51\xd7\xeb\x89\x00\x00\x00"),
 		},
 		"/pg_notifier_queue/0000001_pg_notifier_queue_init.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "0000001_pg_notifier_queue_init.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 775089478, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 1220,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9c\x54\xc1\x72\xda\x30\x10\x3d\x5b\x5f\xb1\x07\x66\x80\x19\x3c\xd3\x43\xa7\x17\x4e\x06\x94\xc4\xad\x91\x19\x5b\x34\x70\xf2\xc8\xf6\x26\x55\xeb\x60\x55\x5e\x4d\xc2\xdf\x77\xc0\xc5\x9e\xd0\x96\x94\xdc\xbc\x7e\x6f\xdf\x4a\x6f\x57\xeb\xfb\x3e\xf3\x7d\x1f\x62\x83\x56\x91\xae\x77\xcd\x21\x64\x6c\x11\xc3\x60\x00\x33\x7e\x1b\x0a\x06\x00\x30\x4f\x78\x20\x39\xc8\xed\x8a\x83\x79\xcc\x76\x35\xe9\x07\x8d\x36\x6b\x48\x91\x6b\x32\xda\x1b\x64\x5e\x90\x02\x17\xeb\xe5\x88\x79\xde\xf0\x59\x69\xd2\xbb\xc7\xe1\xe4\x10\xe0\x0b\x16\xae\x0f\x1b\x57\x14\x88\x25\x96\x6d\xf8\xa0\x74\x75\xfa\x56\x79\x6d\x09\xcb\x21\xf3\x3c\x6f\x3c\x65\x1e\xdf\xcc\xf9\x4a\x86\xb1\x60\x9e\x77\x7f\xc7\x05\x94\xce\x54\xba\x50\x84\x59\x9d\x7f\xc7\x82\x40\x1e\xfe\xee\x5c\x55\x4d\x19\x17\x0b\x18\x0c\xa6\x8c\x9d\x4e\x1b\xcc\x22\x0e\xe1\x0d\x88\x58\x02\xdf\x84\xa9\x4c\x5f\x1d\xfe\xa7\x43\x87\x30\x3a\x5e\x50\x97\x30\x0b\x6f\x53\x9e\x84\x41\x04\xab\x24\x5c\x06\xc9\x16\xbe\xf0\xed\xe4\x88\xe6\x8a\x8a\x6f\x99\x2e\xe1\x6b\x90\xcc\xef\x82\x64\xf4\xe9\xe3\xf8\xa8\x2a\xd6\x51\xd4\x52\x5a\x27\xfe\x65\xce\x19\x99\x6a\xa3\x8b\x0b\x62\x46\xed\xab\x5a\x95\xf0\x39\x8d\xc5\xec\x0c\x2b\x2c\x2a\xc2\x32\x53\x04\x32\x5c\xf2\x54\x06\xcb\x55\x47\x81\x05\xbf\x09\xd6\x91\x04\x11\xdf\x8f\xc6\x6d\x82\x33\xe5\x75\x09\x95\x6a\x28\x3b\xf4\x2c\x23\xfd\x84\x7d\x52\x8b\x2a\x22\x7c\x32\x04\xe9\x32\x88\xa2\x50\xc8\x4e\xe1\x43\x8b\xa3\xb5\xb5\x05\xc9\x37\xb2\x8d\x9f\x6b\xfb\x03\xed\x99\x79\x3d\xd4\x18\x55\xe0\x9f\x90\xb1\xba\xb6\x9a\xf6\xa0\x77\x34\xee\x5b\x1a\x8a\x05\xdf\xbc\xd5\xd2\x93\xf1\xba\x7c\x81\x58\xfc\xad\xe5\x2d\xe1\x6a\xdd\xd3\x18\x5c\x50\x3e\x51\xae\xd6\xee\xbc\xc8\x8e\xc3\x71\xa1\x44\xc7\x9c\xb4\x73\xf4\x5e\x7b\xfa\x8a\x6f\x19\x35\xe9\x3b\x75\x28\xf6\xdf\xcb\xc1\xe5\x4d\x61\x75\xde\x3d\x85\xd7\x2b\x22\x77\xcd\xfe\xf7\x06\xb0\x88\x43\x78\xef\x7b\xff\x15\x00\x00\xff\xff\x11\x3c\xce\x03\xc4\x04\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9c\x54\xc1\x6e\xda\x40\x10\x3d\x7b\xbf\x62\x0e\x48\x80\x84\xa5\x1e\xaa\x5e\x38\x19\xd8\x24\x6e\xcd\x1a\xd9\x4b\x03\x27\x6b\x6d\x4f\xd2\x6d\x1d\xbc\x5d\xcf\x2a\xe1\xef\x2b\x70\xb1\x15\xda\x10\x91\x1b\xc3\x7b\xf3\x76\xfd\xde\xec\xf8\xbe\xcf\x7c\xdf\x87\xd8\xa0\x55\xa4\xeb\x5d\x73\x28\x19\x5b\xc4\x30\x18\xc0\x8c\xdf\x86\x82\x01\x00\xcc\x13\x1e\x48\x0e\x72\xbb\xe2\x60\x1e\xb3\x5d\x4d\xfa\x41\xa3\xcd\x1a\x52\xe4\x9a\x8c\xf6\x06\x99\x17\xa4\xc0\xc5\x7a\x39\x62\x9e\x37\x7c\x56\x9a\xf4\xee\x71\x38\x39\x14\xf8\x82\x85\xeb\xcb\xc6\x15\x05\x62\x89\x65\x5b\x3e\x28\x5d\x9d\x7e\xab\xbc\xb6\x84\xe5\x90\x79\x9e\x37\x9e\x32\x8f\x6f\xe6\x7c\x25\xc3\x58\x30\xcf\xbb\xbf\xe3\x02\x4a\x67\x2a\x5d\x28\xc2\xac\xce\x7f\x62\x41\x20\x0f\xff\xee\x5c\x55\x4d\x19\x17\x0b\x18\x0c\xa6\x8c\x9d\x6e\x1b\xcc\x22\x0e\xe1\x0d\x88\x58\x02\xdf\x84\xa9\x4c\x5f\x5d\xfe\xb7\x43\x87\x30\x3a\x7e\xa0\x2e\x61\x16\xde\xa6\x3c\x09\x83\x08\x56\x49\xb8\x0c\x92\x2d\x7c\xe3\xdb\xc9\x11\xcd\x15\x15\x3f\x32\x5d\xc2\xf7\x20\x99\xdf\x05\xc9\xe8\xcb\xe7\xf1\x51\x55\xac\xa3\xa8\xa5\xb4\x4e\xbc\x65\xce\x19\x99\x6a\xa3\x8b\x0b\x62\x46\xed\xab\x5a\x95\xf0\x35\x8d\xc5\xec\x0c\x2b\x2c\x2a\xc2\x32\x53\x04\x32\x5c\xf2\x54\x06\xcb\x55\x47\x81\x05\xbf\x09\xd6\x91\x04\x11\xdf\x8f\xc6\x6d\x83\x33\xe5\x75\x0d\x95\x6a\x28\x3b\x64\x96\x91\x7e\xc2\xbe\xa9\x45\x15\x11\x3e\x19\x82\x74\x19\x44\x51\x28\x64\xa7\xf0\xa9\xc5\xd1\xda\xda\x82\xe4\x1b\xd9\xd6\xcf\xb5\xfd\x85\xf6\xcc\xbc\x1e\x6a\x8c\x2a\xf0\x5f\xc8\x58\x5d\x5b\x4d\x7b\xd0\x3b\x1a\xf7\x91\x86\x62\xc1\x37\xef\x45\x7a\x32\x5e\x97\x2f\x10\x8b\xff\x45\xde\x12\xae\xd6\x3d\x8d\xc1\x05\xe5\x13\xe5\x6a\xed\xce\x8b\xec\x38\x1c\x17\x8e\xe8\x98\x93\x76\x8e\x3e\x6a\x4f\x7f\xe2\x7b\x46\x4d\xfa\xa4\xc6\xd3\x2b\x96\x83\xcb\x9b\xc2\xea\xbc\x7b\x0a\xaf\x57\x44\xee\x9a\xfd\xdf\x0d\x60\x11\x87\xf0\xd1\xf7\xfe\x67\x00\x11\x3c\xce\x03\xc4\x04\x00\x00"),
 		},
 		"/pg_notifier_queue/0000002_pg_notifier_queue_null_topic.down.sql": &vfsgen۰CompressedFileInfo{
 			name:             "0000002_pg_notifier_queue_null_topic.down.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 775197604, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 302,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x94\xce\xd1\xca\x82\x30\x18\xc6\xf1\x73\xaf\xe2\x39\xfc\x3e\x70\x57\xe0\x91\xd9\x1b\x08\x6b\x03\x9d\xe0\xd9\x10\x5b\x31\x02\xb7\xdc\xa4\x2e\x3f\x54\x90\xa0\x3a\xe8\x70\xec\xf7\xf2\x7f\x18\x63\x09\x63\x0c\xd2\x9b\xb1\x8b\xd6\x0d\x61\x7e\x26\x49\x51\x51\xae\x08\xa5\xd8\x53\x8b\xf2\x00\x21\x15\xa8\x2d\x6b\x55\xc3\x5f\xf4\xe0\xa2\x3d\x5b\x33\xea\xdb\x64\x26\xa3\xef\x6e\xbc\x06\xdf\xf5\x46\x47\xe7\x6d\xaf\xed\xe9\x01\x29\xde\x21\xfe\x36\x99\x62\xa1\xff\xd9\x8f\xa9\x10\xbb\x38\x85\x97\xe2\xf7\xd6\x4a\x53\x6c\x76\x8e\xe5\x5c\x51\x05\x95\xef\x38\x7d\xb8\x59\x7f\x0b\xc9\x9b\xa3\x58\x07\xa2\x26\xb5\x2c\x12\x0d\xe7\xd9\x33\x00\x00\xff\xff\x91\x78\x54\x38\x2e\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x94\xce\xd1\xca\x82\x30\x18\xc6\xf1\x73\xaf\xe2\x39\xfc\x3e\x70\x57\xe0\x91\xd9\x1b\x08\x6b\x03\x9d\xe0\xd9\x10\x5b\x31\x02\xb7\xdc\xa4\x2e\x3f\x54\x90\xa0\x3a\xe8\x70\xec\xf7\xf2\x7f\x18\x63\x09\x63\x0c\xd2\x9b\xb1\x8b\xd6\x0d\x61\x7e\x26\x49\x51\x51\xae\x08\xa5\xd8\x53\x8b\xf2\x00\x21\x15\xa8\x2d\x6b\x55\xc3\x5f\xf4\xe0\xa2\x3d\x5b\x33\xea\xdb\x64\x26\xa3\xef\x6e\xbc\x06\xdf\xf5\x46\x47\xe7\x6d\xaf\xed\xe9\x01\x29\xde\x21\xfe\x36\x99\x62\xa1\xff\xd9\x8f\xa9\x10\xbb\x38\x85\x97\xe2\xf7\xd6\x4a\x53\x6c\x76\x8e\xe5\x5c\x51\x05\x95\xef\x38\x7d\xb8\x59\x7f\x0b\xc9\x9b\xa3\x58\x07\xa2\x26\xb5\x2c\x12\x0d\xe7\xd9\x73\x00\x91\x78\x54\x38\x2e\x01\x00\x00"),
 		},
 		"/pg_notifier_queue/0000002_pg_notifier_queue_null_topic.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "0000002_pg_notifier_queue_null_topic.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 775263479, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 210,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8c\xcd\x41\x0a\xc2\x30\x10\x85\xe1\x7d\x4e\x31\x17\x98\x13\x74\x55\x6d\x84\x40\x4c\xa4\x4d\xa1\xbb\x10\x6a\x94\x20\x34\x31\x99\xa0\xc7\x17\xdb\x8d\xe0\xa6\xcb\x61\xf8\xde\x8f\x88\x0c\x11\x41\x27\x9f\x1d\x85\xb8\x94\xef\xc9\x58\xd7\xeb\x0b\x08\xd5\xf1\x09\xc4\x09\xf8\x24\x06\x33\x40\xba\xdb\x25\x52\xb8\x05\x9f\xed\xb3\xfa\xea\xed\x2b\xe6\x47\x49\x6e\xf6\x96\x62\x0a\xb3\x0d\xd7\x77\xb3\x1b\x17\x72\x54\xcb\xcf\xc6\xa6\x5b\x69\x78\x0f\xa6\x3d\x48\xfe\x8f\x60\xfb\x1e\xb5\x1c\xcf\x0a\xd6\x28\xac\x39\xa5\x0d\xa8\x51\xca\xe6\x13\x00\x00\xff\xff\xf4\x66\xf0\xcc\xd2\x00\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8c\xcd\x41\x0a\xc2\x30\x10\x85\xe1\x7d\x4e\x31\x17\x98\x13\x74\x55\x6d\x84\x40\x4c\xa4\x4d\xa1\xbb\x10\x6a\x94\x20\x34\x31\x99\xa0\xc7\x17\xdb\x8d\xe0\xa6\xcb\x61\xf8\xde\x8f\x88\x0c\x11\x41\x27\x9f\x1d\x85\xb8\x94\xef\xc9\x58\xd7\xeb\x0b\x08\xd5\xf1\x09\xc4\x09\xf8\x24\x06\x33\x40\xba\xdb\x25\x52\xb8\x05\x9f\xed\xb3\xfa\xea\xed\x2b\xe6\x47\x49\x6e\xf6\x96\x62\x0a\xb3\x0d\xd7\x77\xb3\x1b\x17\x72\x54\xcb\xcf\xc6\xa6\x5b\x69\x78\x0f\xa6\x3d\x48\xfe\x8f\x60\xfb\x1e\xb5\x1c\xcf\x0a\xd6\x28\xac\x39\xa5\x0d\xa8\x51\xca\xe6\x33\x00\xf4\x66\xf0\xcc\xd2\x00\x00\x00"),
 		},
 		"/pg_notifier_queue/0000003_pg_notifier_queue_add_priority.down.sql": &vfsgen۰FileInfo{
 			name:    "0000003_pg_notifier_queue_add_priority.down.sql",
-			modTime: time.Date(2022, 6, 6, 18, 19, 20, 993748669, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x2d\x2d\x2d\x0a\x2d\x2d\x2d\x20\x4f\x70\x65\x72\x61\x74\x69\x6f\x6e\x73\x0a\x2d\x2d\x2d\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x70\x67\x5f\x6e\x6f\x74\x69\x66\x69\x65\x72\x5f\x71\x75\x65\x75\x65\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x70\x72\x69\x6f\x72\x69\x74\x79\x3b\x0a"),
 		},
 		"/pg_notifier_queue/0000003_pg_notifier_queue_add_priority.up.sql": &vfsgen۰FileInfo{
 			name:    "0000003_pg_notifier_queue_add_priority.up.sql",
-			modTime: time.Date(2022, 6, 6, 18, 19, 20, 993820753, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x2d\x2d\x2d\x0a\x2d\x2d\x2d\x20\x4f\x70\x65\x72\x61\x74\x69\x6f\x6e\x73\x0a\x2d\x2d\x2d\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x70\x67\x5f\x6e\x6f\x74\x69\x66\x69\x65\x72\x5f\x71\x75\x65\x75\x65\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x70\x72\x69\x6f\x72\x69\x74\x79\x20\x69\x6e\x74\x3b\x0a"),
 		},
 		"/reports": &vfsgen۰DirInfo{
 			name:    "reports",
-			modTime: time.Date(2022, 5, 11, 7, 57, 37, 776095235, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 		},
 		"/reports/000001_create_reports.down.sql": &vfsgen۰FileInfo{
 			name:    "000001_create_reports.down.sql",
-			modTime: time.Date(2022, 5, 11, 7, 57, 37, 775667316, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x2d\x2d\x2d\x0a\x2d\x2d\x2d\x20\x52\x65\x70\x6f\x72\x74\x73\x0a\x2d\x2d\x2d\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x72\x74\x73\x3b"),
 		},
 		"/reports/000001_create_reports.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000001_create_reports.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 775741774, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 651,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8c\x91\xcf\x4e\xf3\x30\x10\xc4\xcf\xf1\x53\xec\xb1\x95\xbe\xdc\x3e\x71\xe1\xe4\x54\x06\x0c\x21\x41\x8e\x41\xed\x29\x72\x93\x95\x30\x6d\xed\xc8\xde\xc0\xeb\x23\xb7\x20\xa0\xff\xe0\x66\xcf\xfc\x66\xac\xf5\xe6\x79\xce\xf2\x3c\x07\x85\x83\x0f\x14\xd3\x99\xb1\x99\x12\x5c\x0b\xd0\xbc\x28\x05\xc8\x2b\xa8\x6a\x0d\x62\x2e\x1b\xdd\x40\xd8\x71\x30\x61\x59\x66\x7b\x28\xe4\x75\x23\x94\xe4\x25\x3c\x28\x79\xcf\xd5\x02\xee\xc4\xe2\x1f\xcb\xb2\x37\x1f\x56\x71\x30\x1d\xb6\xb6\x87\x27\xae\x66\x37\x5c\x4d\x2e\xfe\x4f\xb7\x5d\xd5\x63\x59\x26\xc8\x99\x0d\x6e\xa1\x93\x84\x75\x91\x8c\x3b\xdf\x12\xfd\x18\x0e\x88\x64\xf4\x18\xc9\x3a\x43\xd6\xbb\x3f\xe4\x97\x86\xba\xe7\x23\x2d\x1f\x36\x99\xb8\xfa\xc5\x0d\xa3\x3b\x4d\xbc\xf8\xe5\x79\xf3\x78\xda\xba\x76\x18\xf7\xc5\x43\x65\xb7\x16\xec\x5b\x43\x69\x27\xb2\xd2\x3f\x47\x24\x43\x63\x6c\x3b\xdf\x23\xc8\x4a\x6f\x25\xb3\x19\xd6\xd8\x06\x8c\x83\x77\x11\x41\x8b\xf9\x77\x1d\x5f\xd1\x11\xdc\x36\x75\x55\x7c\x15\x9c\xfc\xc2\xce\x8f\xee\xf3\xe1\x74\x27\x0c\x1b\xeb\xcc\xba\x4d\x39\x84\xa2\xae\x4b\xc1\xab\xdd\x3c\x96\xec\xbe\xc1\xb2\x6c\x7a\xc9\xde\x03\x00\x00\xff\xff\xd1\xe5\x67\xe5\x8b\x02\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8c\x91\xcf\x4e\xf3\x30\x10\xc4\xcf\xf1\x53\xec\xb1\x95\xbe\xdc\x3e\x71\xe1\xe4\x54\x06\x0c\x21\x41\x8e\x41\xed\x29\x72\x93\x95\x30\x6d\xed\xc8\xde\xc0\xeb\x23\xb7\x20\xa0\xff\xe0\x66\xcf\xfc\x66\xac\xf5\xe6\x79\xce\xf2\x3c\x07\x85\x83\x0f\x14\xd3\x99\xb1\x99\x12\x5c\x0b\xd0\xbc\x28\x05\xc8\x2b\xa8\x6a\x0d\x62\x2e\x1b\xdd\x40\xd8\x71\x30\x61\x59\x66\x7b\x28\xe4\x75\x23\x94\xe4\x25\x3c\x28\x79\xcf\xd5\x02\xee\xc4\xe2\x1f\xcb\xb2\x37\x1f\x56\x71\x30\x1d\xb6\xb6\x87\x27\xae\x66\x37\x5c\x4d\x2e\xfe\x4f\xb7\x5d\xd5\x63\x59\x26\xc8\x99\x0d\x6e\xa1\x93\x84\x75\x91\x8c\x3b\xdf\x12\xfd\x18\x0e\x88\x64\xf4\x18\xc9\x3a\x43\xd6\xbb\x3f\xe4\x97\x86\xba\xe7\x23\x2d\x1f\x36\x99\xb8\xfa\xc5\x0d\xa3\x3b\x4d\xbc\xf8\xe5\x79\xf3\x78\xda\xba\x76\x18\xf7\xc5\x43\x65\xb7\x16\xec\x5b\x43\x69\x27\xb2\xd2\x3f\x47\x24\x43\x63\x6c\x3b\xdf\x23\xc8\x4a\x6f\x25\xb3\x19\xd6\xd8\x06\x8c\x83\x77\x11\x41\x8b\xf9\x77\x1d\x5f\xd1\x11\xdc\x36\x75\x55\x7c\x15\x9c\xfc\xc2\xce\x8f\xee\xf3\xe1\x74\x27\x0c\x1b\xeb\xcc\xba\x4d\x39\x84\xa2\xae\x4b\xc1\xab\xdd\x3c\x96\xec\xbe\xc1\xb2\x6c\x7a\xc9\xde\x07\x00\xd1\xe5\x67\xe5\x8b\x02\x00\x00"),
 		},
 		"/reports/000002_alter_reports.down.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000002_alter_reports.down.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 776060027, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 169,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4a\x2d\xc8\x2f\x2a\x29\xe6\x72\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\x28\xce\x2f\x2d\x4a\x4e\x8d\x4f\x4e\x2c\x49\x4d\xcf\x2f\xaa\xd4\xc1\x26\x99\x92\x9a\x96\x99\x97\x59\x92\x99\x9f\x17\x9f\x99\x82\xaa\x22\x25\xb5\xb8\x24\x33\x2f\x11\x2c\x87\x47\x59\x6a\x59\x6a\x5e\x49\x7c\x5e\x62\x6e\x2a\x36\xf1\x92\xca\x82\x54\x6b\x40\x00\x00\x00\xff\xff\xce\x5c\x0a\x81\xa9\x00\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4a\x2d\xc8\x2f\x2a\x29\xe6\x72\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\x28\xce\x2f\x2d\x4a\x4e\x8d\x4f\x4e\x2c\x49\x4d\xcf\x2f\xaa\xd4\xc1\x26\x99\x92\x9a\x96\x99\x97\x59\x92\x99\x9f\x17\x9f\x99\x82\xaa\x22\x25\xb5\xb8\x24\x33\x2f\x11\x2c\x87\x47\x59\x6a\x59\x6a\x5e\x49\x7c\x5e\x62\x6e\x2a\x36\xf1\x92\xca\x82\x54\x6b\xc0\x00\xce\x5c\x0a\x81\xa9\x00\x00\x00"),
 		},
 		"/reports/000002_alter_reports.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000002_alter_reports.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 776125402, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 315,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9c\xcc\x31\x0a\xc2\x30\x14\x06\xe0\xbd\xa7\xf8\xb7\x2e\xde\xc0\x29\xda\x14\x0a\xb1\x05\xfb\x0a\xdd\x42\x68\x9e\x92\xc1\xa4\x24\x4f\xa1\xb7\x17\x5c\x1d\xa4\x1e\xe0\xfb\x94\x21\x7d\x05\xa9\x93\xd1\xc8\xbc\xa6\x2c\xa5\x52\x4d\x83\xf3\x60\xa6\x4b\x8f\xae\x45\x3f\x10\xf4\xdc\x8d\x34\xa2\xa4\x67\x5e\xd8\x2e\x4e\xf8\x9e\xf2\x06\xd2\x33\xa1\xd1\xad\x9a\x0c\xa1\xae\x0f\x3f\xa5\xe7\x5b\x88\x41\x42\x8a\x36\xf8\x1d\xdc\x73\x91\x10\xdd\x07\xfe\x7b\xf0\x8b\xa3\xd8\xe8\x1e\xbc\x1b\xc9\xb6\x7e\xa1\x63\xf5\x0e\x00\x00\xff\xff\x16\xf8\xd2\x7c\x3b\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9c\xcc\x31\x0a\xc2\x30\x14\x06\xe0\xbd\xa7\xf8\xb7\x2e\xde\xc0\x29\xda\x14\x0a\xb1\x05\xfb\x0a\xdd\x42\x68\x9e\x92\xc1\xa4\x24\x4f\xa1\xb7\x17\x5c\x1d\xa4\x1e\xe0\xfb\x94\x21\x7d\x05\xa9\x93\xd1\xc8\xbc\xa6\x2c\xa5\x52\x4d\x83\xf3\x60\xa6\x4b\x8f\xae\x45\x3f\x10\xf4\xdc\x8d\x34\xa2\xa4\x67\x5e\xd8\x2e\x4e\xf8\x9e\xf2\x06\xd2\x33\xa1\xd1\xad\x9a\x0c\xa1\xae\x0f\x3f\xa5\xe7\x5b\x88\x41\x42\x8a\x36\xf8\x1d\xdc\x73\x91\x10\xdd\x07\xfe\x7b\xf0\x8b\xa3\xd8\xe8\x1e\xbc\x1b\xc9\xb6\x7e\xa1\x63\xf5\x1e\x00\x16\xf8\xd2\x7c\x3b\x01\x00\x00"),
 		},
 		"/warehouse": &vfsgen۰DirInfo{
 			name:    "warehouse",
-			modTime: time.Date(2022, 5, 11, 7, 57, 37, 777390828, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 		},
 		"/warehouse/000001_create_tables.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000001_create_tables.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 776228570, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 4017,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd4\x57\x4f\x73\x9b\x3e\x10\x3d\xff\xf8\x14\x7b\xf0\x21\x9e\x89\x6f\xbf\xe9\x85\x13\xb6\xd5\x84\x16\x83\x07\x94\xd6\x39\x69\x54\x50\x12\xcd\x10\xf0\x80\xdc\xa6\xdf\xbe\x03\x22\x20\x30\xc8\x4a\xe2\x64\xda\x9b\xc7\xbb\xfb\xd8\x3f\x6f\xf5\x24\x6b\xb1\xb0\x16\x0b\xf8\xf5\x40\x4a\x41\xef\x79\x76\x4f\xee\x78\xca\xca\xea\x6f\x6b\x15\x22\x07\x23\xc0\xce\xd2\x43\xe0\x7e\x06\x3f\xc0\x80\x76\x6e\x84\xa3\x23\x7f\xb8\xb0\x00\x00\x78\x02\x4b\xf7\x2a\x42\xa1\xeb\x78\xb0\x0d\xdd\x8d\x13\xde\xc2\x57\x74\x7b\x59\x5b\xd3\x3c\xa6\x82\xe7\x19\x60\xb4\xc3\x35\x9a\x7f\xe3\x79\xd2\x56\xe6\x87\x22\x66\x84\x27\xf0\xcd\x09\x57\xd7\x4e\x78\xf1\xe9\xff\xf9\xc0\x27\x61\xa5\xe0\x59\x0d\xa1\x77\x2c\xe3\x07\xf6\x48\xe1\x4b\x14\xf8\xcb\x81\x89\x15\x45\x5e\xd4\x09\x34\xae\x82\x8a\x43\xa9\x62\xc9\xff\xef\x78\x51\x0a\xc2\x7e\xb2\x4c\x10\x2a\x00\xbb\x1b\x14\x61\x67\xb3\x6d\x2a\xa1\x1a\xa3\xc8\x05\x4d\xa5\xb5\xac\xda\xe1\xfa\xcd\xb7\xe2\x82\x51\xc1\x92\x5e\xc8\x20\xbd\xc3\x3e\x99\x76\x99\xdb\x96\xe5\x78\x18\x85\xcd\x48\x8e\x86\x56\x21\x38\xeb\x35\xac\x02\xef\x66\xe3\x0f\x46\xa6\xaf\x68\x32\x4c\x5b\xea\x64\xd4\x48\x0f\xec\x96\x50\xae\xbf\x46\xbb\x13\x84\x22\x3c\x21\x3c\x4b\xd8\x13\x04\xfe\x08\xdb\x5a\xba\x5c\x0e\x58\x71\xaa\x47\x20\x8d\x4d\xd2\xcd\xf4\xf1\xed\x16\xa9\x14\xb0\x2d\x6b\x1d\xc0\x6c\x06\x4b\x74\xe5\xfa\x75\xa5\xeb\x30\xd8\x4a\x3f\x05\xb1\x0a\x67\x44\xfc\xde\x33\xbb\x76\x42\xbb\x15\xda\x62\x37\xf0\xe1\xfb\x35\xf2\x21\xc0\xd7\x28\x8c\x00\x57\xbf\xb3\x43\x9a\xda\x16\xf2\xd7\x30\x9b\xd9\x56\xb7\x75\x69\x4e\x13\xe3\x95\xeb\x9c\x8d\xf6\x4d\x2d\x9c\x48\xd7\x96\x8b\x1f\xb6\x8b\xaa\x63\xd5\x29\x8d\xab\xa0\x3f\x52\x46\x32\xfa\xc8\xc6\xb2\x7a\xdd\x5a\x8d\xf0\x41\xe9\x62\x8f\x0c\xea\xe7\xab\x41\x57\x39\xe8\xa3\xcf\xc9\xfe\x0e\x97\x34\xfd\xef\xb7\x98\x74\xe9\xf5\x16\x43\xe5\xc4\xe4\x56\x5c\x2a\xc5\xcd\x55\xfa\x1d\xf6\x55\xbc\x09\xf7\x1a\x4f\x33\xe2\x19\x10\xa8\x4a\xa5\xdc\xd3\x98\x7d\x30\xc9\x4a\x41\x0b\x41\x74\xab\xc1\xb2\x44\x6b\x97\x08\x6d\xdf\xc7\xc2\x27\x8d\xc7\x72\xf3\x52\xe9\xaa\x2d\x6f\xd6\x28\x69\x7c\x62\xf1\x98\x7e\xf1\x47\x9e\xdd\x97\xea\x97\xde\x41\xb9\x9e\x99\xf7\xf7\x68\x96\xae\x27\xd3\xab\xae\x36\xeb\xe4\x92\x37\x45\x13\x49\x83\xde\x1e\xb7\xfb\x25\x6d\x73\x73\xac\xd1\xd3\x62\x1c\xfa\x05\xaa\xf9\x1c\x74\x0e\xbd\x94\x58\xe7\x90\x4b\x79\x8c\x19\x9e\x5a\xff\x0d\x03\x8c\x0e\xaf\x2e\xdf\x9e\x9f\x56\xaa\x0c\x97\x7b\x78\xf9\xec\x18\x27\x78\x85\xa4\xb9\x47\x76\x41\xef\xb0\x8b\xfd\x26\xbd\x50\xd6\xf4\x58\x2a\x7b\x9a\xf2\x15\x6d\xd5\x33\xbc\x07\x45\xd4\xb9\x4c\x89\xe1\x60\xda\x6a\xc8\x50\x02\xcd\xd3\x7e\x25\xe9\x55\xc4\x73\x50\x5f\x2a\x83\xd1\xcb\x4c\x7a\xbe\x81\xed\xff\x80\x8c\x9b\x3f\xf1\x4e\xde\x0f\xeb\xb1\xb5\x1c\x3c\xea\xa3\xee\x84\x3d\xfd\xa8\x69\x30\x06\xc1\x6d\xdb\xfa\x8f\x9c\xe7\xc1\x0d\x6f\x6f\xad\xfb\xdc\xfe\x13\x00\x00\xff\xff\x89\x94\x7c\x27\xb1\x0f\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd4\x57\x41\x73\x9b\x3c\x10\x3d\x7f\xfc\x8a\x3d\xf8\x10\xcf\xc4\xb7\x6f\x7a\xe1\x84\x6d\x35\xa1\xb5\xc1\x83\x95\xd6\x39\x69\x54\xa3\x24\x9a\xc1\xe0\x01\xb9\x4d\xff\x7d\x07\x44\x40\xc8\x20\x2b\x8e\x93\x69\x6f\x1e\xef\xdb\x65\xb5\xfb\x9e\x1e\x38\x93\x89\x33\x99\xc0\xaf\x27\x52\x08\xfa\xc8\xd3\x47\xf2\xc0\x13\x56\x94\x7f\x3b\xb3\x08\x79\x18\x01\xf6\xa6\x0b\x04\xfe\x67\x08\x42\x0c\x68\xe3\xaf\xf1\xfa\x08\x0f\x57\x0e\x00\x00\x8f\x61\xea\xdf\xac\x51\xe4\x7b\x0b\x58\x45\xfe\xd2\x8b\xee\xe1\x2b\xba\xbf\xae\xa2\x49\xb6\xa5\x82\x67\x29\x60\xb4\xc1\x55\xb5\xe0\x6e\xb1\x90\xb1\x22\x3b\xe4\x5b\x46\x78\x0c\xdf\xbc\x68\x76\xeb\x45\x57\x9f\xfe\x1f\x6b\x98\x98\x15\x82\xa7\x55\x09\x33\xb0\xd8\x3e\xb1\x1d\x85\x2f\xeb\x30\x98\x6a\x21\x96\xe7\x59\x5e\x35\x50\x43\x05\x15\x87\x42\xad\x25\xff\x7f\xe0\x79\x21\x08\xfb\xc9\x52\x41\xa8\x00\xec\x2f\xd1\x1a\x7b\xcb\x55\x7d\x12\x6a\x08\x8a\x4c\xd0\x44\x46\x8b\x72\x1c\x7e\x50\x3f\x6b\x9b\x33\x2a\x58\xdc\x49\xd1\xda\x3b\xec\xe3\x61\xc8\xd8\x75\x1c\x6f\x81\x51\x54\xaf\xe4\x68\x69\x00\x00\xde\x7c\x0e\xb3\x70\x71\xb7\x0c\xb4\x95\x99\x4f\x34\x98\x96\xd0\x73\xb2\x7a\x66\xe0\x36\x84\xf2\x83\x39\xda\x9c\x20\x14\xe1\x31\xe1\x69\xcc\x9e\x21\x0c\x7a\xd8\xd6\xd0\xe5\x5a\x63\xc5\xa9\x19\x81\x0c\xd6\x4d\xd7\xdb\xc7\xf7\x2b\xa4\x52\xc0\x75\x9c\x79\x08\xa3\x11\x4c\xd1\x8d\x1f\x54\x27\x9d\x47\xe1\x4a\xe2\x94\x8a\x65\x3a\x23\xe2\xf7\x9e\xb9\x15\x08\x6d\x66\x68\x85\xfd\x30\x80\xef\xb7\x28\x80\x10\xdf\xa2\x68\x0d\xb8\xfc\x9d\x1e\x92\xc4\x75\x50\x30\x87\xd1\xc8\x75\x5a\xd5\x25\x19\x8d\xad\x25\xd7\x82\xad\xf4\xa6\x1e\x9c\xf0\xb8\xc3\xc5\x0f\xd3\xa2\x0a\x2c\x27\x65\x80\x0a\xfa\x23\x61\x24\xa5\x3b\xd6\xd7\xd5\x79\xb2\xea\xe1\x83\x32\xc5\x0e\x19\xd4\xc7\x97\x8b\x2e\x7b\x30\x67\x5f\x92\xfd\x6d\x5d\x52\xcf\xbf\x3b\x62\xd2\xb6\xd7\x11\x86\xca\x89\x41\x55\x5c\x2b\x87\x1b\xab\xf4\x3b\xec\xcb\x7c\x1b\xee\xd5\x48\x3b\xe2\x59\x10\xa8\x6c\xa5\xd8\xd3\x2d\xfb\x60\x92\x15\x82\xe6\x82\x98\xa4\xc1\xd2\xd8\x18\x97\x15\x9a\xb9\xf7\xa5\x0f\x06\x8f\xed\xe6\xb5\xd6\x55\x45\xde\xec\x51\x32\xf8\xcc\xb6\x3d\x31\xc1\x77\x3c\x7d\x2c\xd4\x27\xbd\x83\x73\xbd\x30\xef\xef\xf1\x2c\xd3\x4c\x86\xa5\xae\x0e\xeb\xa4\xc8\xeb\x43\x13\x49\x83\x8e\x8e\x1b\x7d\xc9\xd8\xd8\xbe\x56\xef\x6d\xd1\x5f\xfa\x15\xae\xf9\x92\x74\x09\xbf\x94\xb5\x2e\x61\x97\xf2\x1a\xb3\xbc\xb5\xfe\xd3\x13\xac\x2e\xaf\xb6\xdf\x0e\xce\x68\x55\x96\xe2\xd6\x5f\x3e\x5b\xc6\x09\xbe\x63\x3a\xe7\x3a\x2e\xd2\x26\xbd\x83\x16\xbb\x43\x7a\xa5\xad\x99\x6b\xa9\xec\xa9\x8f\xaf\x78\xab\x99\xe1\x9d\x52\x44\xdd\xcb\x90\x19\x6a\xdb\x56\x53\x74\x0b\xb4\x6f\xfb\x4c\xd2\xab\x15\x2f\x41\x7d\xe9\x0c\x56\x5f\x66\x12\xf9\x06\xb6\xff\x03\x36\x6e\xff\x89\x77\xf2\xfd\xb0\x5a\x5b\xc3\xc1\xa3\x39\x9a\x6e\xd8\xd3\x1f\x35\x75\x0d\x2d\xb9\x19\x5b\xf7\x23\xe7\x65\x71\xfa\xdb\x5b\x03\x1f\xbb\x7f\x06\x00\x89\x94\x7c\x27\xb1\x0f\x00\x00"),
 		},
 		"/warehouse/000002_alter_wh_table_uploads.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000002_alter_wh_table_uploads.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 776287278, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 201,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x84\xcc\x31\x0a\xc2\x30\x18\xc5\xf1\x3d\xa7\x78\xa3\x0e\x39\x41\xa7\xb4\x8d\x25\x10\x53\x31\x5f\xa1\x4e\x21\xd6\x80\x42\x4c\x84\xa6\x7a\x7d\x51\x5c\xc4\xc1\xf5\xbd\x1f\x7f\xc6\x39\xe3\x1c\x8f\xb3\x2b\xfe\x18\x83\x5b\x6e\x31\xfb\xd3\xfc\x9a\x99\xd0\x24\xf7\x20\x51\x6b\xf9\x03\x20\xda\x16\x4d\xaf\x87\xad\x81\xda\xc0\xf4\x04\x39\x2a\x4b\x16\x31\x4f\xbe\x5c\x72\x02\xc9\x91\xaa\x7f\x95\xf7\x39\xe5\xb8\x5c\x13\x4a\x2e\x3e\xba\x70\x0f\xa9\xcc\xa0\xc3\x4e\xa2\x56\x9d\x32\x84\xc1\x2a\xd3\xa1\x11\x96\x56\x5f\x46\xd8\x8f\x58\x57\xec\x19\x00\x00\xff\xff\x10\xd9\xe2\xb0\xc9\x00\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x84\xcc\x31\x0a\xc2\x30\x18\xc5\xf1\x3d\xa7\x78\xa3\x0e\x39\x41\xa7\xb4\x8d\x25\x10\x53\x31\x5f\xa1\x4e\x21\xd6\x80\x42\x4c\x84\xa6\x7a\x7d\x51\x5c\xc4\xc1\xf5\xbd\x1f\x7f\xc6\x39\xe3\x1c\x8f\xb3\x2b\xfe\x18\x83\x5b\x6e\x31\xfb\xd3\xfc\x9a\x99\xd0\x24\xf7\x20\x51\x6b\xf9\x03\x20\xda\x16\x4d\xaf\x87\xad\x81\xda\xc0\xf4\x04\x39\x2a\x4b\x16\x31\x4f\xbe\x5c\x72\x02\xc9\x91\xaa\x7f\x95\xf7\x39\xe5\xb8\x5c\x13\x4a\x2e\x3e\xba\x70\x0f\xa9\xcc\xa0\xc3\x4e\xa2\x56\x9d\x32\x84\xc1\x2a\xd3\xa1\x11\x96\x56\x5f\x46\xd8\x8f\x58\x57\xec\x39\x00\x10\xd9\xe2\xb0\xc9\x00\x00\x00"),
 		},
 		"/warehouse/000003_wh_uploads_add_metadata_column.up.sql": &vfsgen۰FileInfo{
 			name:    "000003_wh_uploads_add_metadata_column.up.sql",
-			modTime: time.Date(2022, 5, 11, 7, 57, 37, 776347029, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x0a\x2d\x2d\x0a\x2d\x2d\x20\x77\x68\x5f\x75\x70\x6c\x6f\x61\x64\x73\x0a\x2d\x2d\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x77\x68\x5f\x75\x70\x6c\x6f\x61\x64\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x6d\x65\x74\x61\x64\x61\x74\x61\x20\x4a\x53\x4f\x4e\x42\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x27\x7b\x7d\x27\x3b\x0a"),
 		},
 		"/warehouse/000004_add_upd_col_to_wh_schemas_and_uniq_constraint.sql.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000004_add_upd_col_to_wh_schemas_and_uniq_constraint.sql.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 776413738, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 460,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7c\x90\x41\x6b\xdc\x40\x0c\x85\xef\xfe\x15\x0f\x93\x43\x02\x75\xfb\x03\x72\x72\x62\xb7\x18\xb6\x76\x9b\xf5\x42\x6e\x46\xcc\x28\x9d\x01\x5b\xe3\xce\x68\x70\xf3\xef\xcb\x24\x81\xee\xa9\x3a\x49\x7a\xe2\xbd\x0f\x35\x4d\xd5\x34\x38\xdc\x92\x8c\xe3\x8d\x52\xd5\x34\x55\xd5\x9e\xe6\xfe\x09\x73\xfb\x70\xea\xaf\x24\xb4\x5d\x87\xc7\xe9\x74\xf9\x3e\x62\xf8\x8a\x71\x9a\xd1\x3f\x0f\xe7\xf9\x8c\xbc\x5b\x52\xb6\x0b\x29\xd4\x6f\x9c\x94\xb6\xfd\xbe\x2a\xc6\x64\x6d\x02\xc1\x04\x49\x1a\xc9\x8b\x42\xc3\xb5\xe5\xe1\xbc\x71\xa0\x75\x0d\x47\x42\x4e\x45\xb5\x01\x79\x4f\x1c\xf5\xf6\xdd\xf6\x8b\x97\x32\xdd\x21\x08\x24\x08\xf6\xe8\x37\x8a\xaf\x30\x61\xcd\x9b\x14\x60\x98\xc8\xa4\x5c\x82\x34\x92\x24\x32\xea\x83\x7c\x86\x7f\x81\x3a\xbe\x0e\xa7\x35\x32\xd9\x57\xf0\x1f\x9f\x34\x15\x55\x60\x03\x27\x48\x50\xe7\xe5\x57\xd5\x4d\xb8\xb9\xa9\x00\xe0\xa1\xff\x36\x8c\x6f\x5d\xa9\xff\x7e\x64\x3c\xcf\x4f\xed\x30\xce\xc8\xe2\x7f\x67\x5e\x0e\xb7\x78\xcb\xa2\xfe\xc5\x73\xc4\x65\x1c\x7e\x5e\xfa\xdb\x3a\x85\x1c\x0d\x2f\xde\xd6\x9f\x6a\xcb\x49\xbd\x50\xc1\x7c\x5f\x08\x6d\x9c\x76\x32\x5c\xdf\xdd\xbf\x85\xf6\xcf\x8f\xfd\x8f\x79\x98\xfe\x21\x1c\x05\x36\xa8\xe3\xf8\x01\x2e\x79\x5d\x3f\x8e\xc7\xae\x60\xff\x0d\x00\x00\xff\xff\x88\x0e\xff\xc3\xcc\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7c\x90\x41\x6b\xdc\x40\x0c\x85\xef\xfe\x15\x0f\x93\x43\x02\x75\xfb\x03\x72\x72\x62\xb7\x18\xb6\x76\x9b\xf5\x42\x6e\x46\xcc\x28\x9d\x01\x5b\xe3\xce\x68\x70\xf3\xef\xcb\x24\x81\xee\xa9\x3a\x49\x7a\xe2\xbd\x0f\x35\x4d\xd5\x34\x38\xdc\x92\x8c\xe3\x8d\x52\xd5\x34\x55\xd5\x9e\xe6\xfe\x09\x73\xfb\x70\xea\xaf\x24\xb4\x5d\x87\xc7\xe9\x74\xf9\x3e\x62\xf8\x8a\x71\x9a\xd1\x3f\x0f\xe7\xf9\x8c\xbc\x5b\x52\xb6\x0b\x29\xd4\x6f\x9c\x94\xb6\xfd\xbe\x2a\xc6\x64\x6d\x02\xc1\x04\x49\x1a\xc9\x8b\x42\xc3\xb5\xe5\xe1\xbc\x71\xa0\x75\x0d\x47\x42\x4e\x45\xb5\x01\x79\x4f\x1c\xf5\xf6\xdd\xf6\x8b\x97\x32\xdd\x21\x08\x24\x08\xf6\xe8\x37\x8a\xaf\x30\x61\xcd\x9b\x14\x60\x98\xc8\xa4\x5c\x82\x34\x92\x24\x32\xea\x83\x7c\x86\x7f\x81\x3a\xbe\x0e\xa7\x35\x32\xd9\x57\xf0\x1f\x9f\x34\x15\x55\x60\x03\x27\x48\x50\xe7\xe5\x57\xd5\x4d\xb8\xb9\xa9\x00\xe0\xa1\xff\x36\x8c\x6f\x5d\xa9\xff\x7e\x64\x3c\xcf\x4f\xed\x30\xce\xc8\xe2\x7f\x67\x5e\x0e\xb7\x78\xcb\xa2\xfe\xc5\x73\xc4\x65\x1c\x7e\x5e\xfa\xdb\x3a\x85\x1c\x0d\x2f\xde\xd6\x9f\x6a\xcb\x49\xbd\x50\xc1\x7c\x5f\x08\x6d\x9c\x76\x32\x5c\xdf\xdd\xbf\x85\xf6\xcf\x8f\xfd\x8f\x79\x98\xfe\x21\x1c\x05\x36\xa8\xe3\xf8\x01\x2e\x79\x5d\x3f\x8e\xc7\xae\x60\xff\x1d\x00\x88\x0e\xff\xc3\xcc\x01\x00\x00"),
 		},
 		"/warehouse/000005_create_table_schema_versions.sql.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000005_create_table_schema_versions.sql.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 776477988, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 1709,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xac\x94\xc1\x72\x9b\x3c\x14\x85\xf7\x7a\x8a\x3b\x8c\x17\xf6\x0c\xe4\xdf\xfc\xd3\x8d\x57\x32\x96\x09\x2d\x11\x54\x88\xd4\x59\x79\x54\xa3\x18\xcd\x38\x22\x23\xe1\x24\x7e\xfb\x8e\xc0\x06\xa7\x75\xda\x3a\xa9\x37\x06\x74\xf5\x9d\xa3\x7b\x0f\x04\x01\x0a\x02\x78\xae\x56\x76\x5d\xc9\x07\xb1\x7a\x92\xc6\xaa\x5a\x5b\x14\x04\x08\x85\x8c\x60\x4e\x80\xe3\x59\x42\x20\x5e\x00\x4d\x39\x90\x65\x9c\xf3\xfc\xcc\x0e\x18\x23\xf8\xeb\x9f\x2a\x61\x16\x47\x39\x61\x31\x4e\x20\x63\xf1\x0d\x66\x77\xf0\x85\xdc\xf9\x17\x30\x06\x0b\xaf\x68\xce\x24\x2d\x92\xe4\x12\x94\xad\x77\x66\x2d\x1d\xe7\x16\xb3\xf0\x1a\xb3\xf1\xa7\xff\x27\xef\x22\x69\xf1\x20\xed\xa3\x58\xcb\x0f\x93\x4a\x69\x1b\xa5\x45\xa3\x6a\xfd\x2f\x8c\x9d\xe2\x9a\xfd\xe3\xc7\xfd\x75\xbd\x87\xcf\x79\x4a\x67\xef\x02\x48\x63\x6a\x03\x9c\x2c\xf9\x25\xbb\xd6\x95\xd0\x1b\x59\xae\x44\x03\x3c\xbe\x21\x39\xc7\x37\x59\x2f\x3f\x99\xf6\xa1\x8d\xe9\x9c\x2c\xff\x18\xda\xd5\x69\x88\x56\x4a\x97\xf2\x05\x52\x7a\x36\xdd\xa7\x95\x4e\x27\x08\x60\x6d\xa4\x68\x24\x08\x68\x8c\xda\x6c\xa4\x81\xfb\x9d\x5e\xbb\x0e\x83\xd2\x56\x9a\xc6\x82\xd2\x4d\x7d\x8e\x76\x5f\x1b\x90\x4f\xd2\xec\x0f\x95\xff\xed\x1e\x4b\x87\xaa\xf5\x50\x6d\xa1\x11\xdf\xb7\xf2\x78\xa0\x94\x01\x23\x59\x82\x43\x02\x8b\x82\x86\x3c\x76\x3e\x85\x91\x55\xbd\xb3\xf2\x28\x50\x29\xdb\xd4\x66\x3f\x9e\xb4\x1d\x65\x84\x17\x8c\xe6\xc0\x59\x1c\x45\x84\xb5\xcf\x12\x4c\xa3\x02\x47\x04\xb2\x24\x8b\xf2\xaf\x09\xc2\x39\x1a\x8d\xd0\x8c\x44\x31\x6d\x0b\x62\x9a\x13\xc6\x21\xa6\x3c\x3d\xe3\x7c\xec\x9d\xf6\xc1\xf3\xbd\xfe\xd5\xf1\x7c\xf0\xfa\xf4\x7b\xbe\xf7\x3a\xbf\x6e\xf5\xe7\x08\xba\xdd\x2d\xc9\xf3\xbd\x36\x0c\x9e\xef\x0d\xe3\xf5\xba\x33\xdc\xe2\xa4\x20\xf9\x58\xcb\xe7\x2b\x55\xfa\xe0\xfe\x7b\xc9\xee\xb6\x17\xed\x6e\x5f\xeb\xfe\xfa\xcc\x29\x1f\x38\xad\x78\x77\xdd\xea\x77\x97\xdd\x28\x9c\x85\xc9\xf4\xa4\x8d\x6e\x6d\x8a\x08\x9d\xc3\x14\x8d\x46\x6f\x04\xe0\xb9\x92\x1a\x9a\x4a\x1a\x09\xca\x82\x38\x4c\x17\x6a\x03\xe7\x06\x3c\x20\x6c\xcb\x10\xda\x8a\x36\x3f\x57\xa0\xee\x1d\xa6\xe7\x8a\xad\x91\xa2\xdc\x83\x7c\x51\xb6\xb1\x6e\x49\x43\x59\x4b\x0b\xba\x6e\x2a\xa5\x37\x68\x9e\xc2\x68\xd4\xba\x1d\x26\xd9\xbe\x2e\x9d\xc3\x23\xe7\xcd\xc4\xac\x8e\x15\x78\xc1\x09\x3b\x86\x20\x65\x50\x64\xf3\x36\x7d\x14\x86\xc9\x5b\x0f\x16\x29\x03\x82\xc3\x6b\x60\xe9\x37\x20\x4b\x12\x16\x9c\x40\xc6\xd2\x90\xcc\x0b\x46\x7e\x13\xcc\xae\xa5\x64\x19\x92\xcc\x45\x18\x0d\x5f\x73\xa9\xa1\x76\xad\x3b\x1c\x4f\xef\xb6\xdb\x43\x31\x9d\xbb\xc3\x21\xf4\x23\x00\x00\xff\xff\xfd\x83\x8c\x84\xad\x06\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xac\x94\xd1\x73\xa2\x3a\x18\xc5\xdf\xf3\x57\x9c\x61\x7c\xd0\x19\xe9\x7d\xb9\x73\x5f\x7c\x42\x8c\x96\x7b\x29\x70\x43\xe8\xda\x27\x27\x2b\xa9\x64\xc6\x86\x4e\x82\x6d\xfd\xef\x77\x02\x2a\x76\xd7\xee\x6e\xdb\xe5\x45\x20\x5f\x7e\xe7\xe4\xfb\x8e\xf8\x3e\xf1\x7d\x3c\x57\x2b\xbb\xae\xe4\x83\x58\x3d\x49\x63\x55\xad\x2d\xf1\x7d\x42\x42\x46\x03\x4e\xc1\x83\x69\x4c\x11\xcd\x91\xa4\x1c\x74\x19\xe5\x3c\xbf\xb0\x03\x43\x82\xdf\xbe\x54\x89\x69\xb4\xc8\x29\x8b\x82\x18\x19\x8b\x6e\x02\x76\x87\xff\xe8\xdd\xf8\x1d\x8c\xde\xc2\x2b\x9a\x33\x99\x14\x71\xfc\x1e\x94\xad\x77\x66\x2d\x1d\xe7\x36\x60\xe1\x75\xc0\x86\xff\xfc\x3d\xfa\x10\x49\x8b\x07\x69\x1f\xc5\x5a\x7e\x9a\x54\x4a\xdb\x28\x2d\x1a\x55\xeb\x3f\x61\xec\x1c\xd7\xec\x1f\x3f\xef\xaf\xeb\x3d\xfe\xcd\xd3\x64\xfa\x21\x80\x34\xa6\x36\xe0\x74\xc9\xdf\xb3\x6b\x5d\x09\xbd\x91\xe5\x4a\x34\xe0\xd1\x0d\xcd\x79\x70\x93\x9d\xe4\x47\x93\x53\x68\xa3\x64\x46\x97\xbf\x0c\xed\xea\x3c\x44\x2b\xa5\x4b\xf9\x82\x34\xb9\x98\xee\xf3\x4a\xa7\xe3\xfb\x58\x1b\x29\x1a\x09\x81\xc6\xa8\xcd\x46\x1a\xdc\xef\xf4\xda\x75\x18\x4a\x5b\x69\x1a\x0b\xa5\x9b\xfa\x12\xed\xbe\x36\x90\x4f\xd2\xec\x0f\x95\x7f\xed\x1e\x4b\x87\xaa\x75\x5f\x6d\xd1\x88\xaf\x5b\x79\x3c\x50\xca\xc0\x68\x16\x07\x21\xc5\xbc\x48\x42\x1e\x39\x9f\xc2\xc8\xaa\xde\x59\x79\x14\xa8\x94\x6d\x6a\xb3\x1f\x8e\x08\x00\x30\xca\x0b\x96\xe4\xe0\x2c\x5a\x2c\x28\x6b\xdf\xc5\x41\xb2\x28\x82\x05\x45\x16\x67\x8b\xfc\xff\x98\x04\x39\x19\x0c\xc8\x94\x2e\xa2\xa4\x2d\x88\x92\x9c\x32\x8e\x28\xe1\xe9\x05\xe7\x43\xef\xbc\x0f\xde\xd8\x3b\xfd\x75\xbc\x31\xbc\x53\xfa\xbd\xb1\xf7\x3a\xbf\x6e\xf5\xfb\x08\xba\xdd\x2d\xc9\x1b\x7b\x6d\x18\xbc\xb1\xd7\x8f\xd7\xeb\xce\x70\x1b\xc4\x05\xcd\x87\x5a\x3e\x5f\xa9\x72\x0c\xf7\x7b\x92\xec\x1e\x4f\xa2\xdd\xe3\x6b\xdd\x1f\xdf\x39\xe5\x03\xa7\x15\xef\xee\x5b\xfd\xee\xb6\x1b\x85\xb3\x30\x9a\x9c\xb5\xd1\xad\x4d\x08\x4d\x66\x98\x90\xc1\xe0\x8d\x00\x3c\x57\x52\xa3\xa9\xa4\x91\x50\x16\xe2\x30\x5d\xd4\x06\x97\x06\xdc\x23\x6c\xcb\x10\xda\x8a\x36\x3f\x57\x50\xf7\x0e\x73\xe2\x8a\xad\x91\xa2\xdc\x43\xbe\x28\xdb\x58\xb7\xa4\x51\xd6\xd2\x42\xd7\x4d\xa5\xf4\x86\xcc\x52\x0c\x06\x04\x00\xfa\x49\xba\xeb\xe0\xf0\xc8\x79\x33\x31\xab\x63\x45\x30\xe7\x94\x1d\x43\x90\x32\x14\xd9\xac\x4d\x5f\x82\x7e\xf2\xd6\xc3\x3c\x65\xa0\x41\x78\x0d\x96\x7e\x01\x5d\xd2\xb0\xe0\x14\x19\x4b\x43\x3a\x2b\x18\xfd\x49\x30\xbb\x96\xd2\x65\x48\x33\x17\x61\xd2\x7f\xcd\xa5\x46\xed\x5a\x77\x38\x9e\xde\x6d\xb7\x87\xe2\x64\xe6\x0e\x47\xc8\xb7\x01\x00\xfd\x83\x8c\x84\xad\x06\x00\x00"),
 		},
 		"/warehouse/000006_add_wh_table_uploads_unique_constraint.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000006_add_wh_table_uploads_unique_constraint.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 776678990, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 958,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x84\x93\x5f\x6f\x9b\x3c\x14\xc6\xaf\xf1\xa7\x78\x2e\x22\x05\xa4\xb4\xd2\x7b\x8d\xfa\x4a\xb4\xf1\xb4\x48\x69\xd8\x08\x5d\xb5\x2b\xcb\x85\x03\xb1\x06\x76\x6a\xec\x25\x1f\x7f\x02\x9a\x3f\x65\xdd\xc6\x15\x3e\xcf\x39\xcf\xcf\xc7\x3e\x5e\xf2\x35\xcf\x39\x3e\x65\xe9\x23\x0e\x3b\xe1\xe4\x4b\x43\xc2\xef\x1b\x23\xcb\x8e\x05\xcf\x9f\x79\xc6\xa1\x4a\x28\x8d\x90\x05\xc1\x96\xaf\xf9\x43\x0e\x55\xb2\x20\x08\x86\x9a\x3e\x7a\x8a\x0f\xbf\x41\x96\x3e\x8b\xcd\xd3\xe3\x3d\xcf\xc2\x08\xe9\x37\x9e\x21\xfc\x92\x64\xf9\x2a\x5f\xa5\x1b\xdc\x7f\xef\x29\xa3\xbf\x50\xe5\x02\x23\x50\xcb\x96\x90\x66\x4b\x9e\xf5\x19\xaa\xc4\x92\x6f\x1f\x22\x24\x5b\x58\x73\x10\xda\xb7\x2f\x64\x17\xa3\xbd\xbb\x1d\xe9\x03\x7e\x0c\x4d\xf7\x0d\xc7\x7a\x21\x42\x6d\x8d\xdf\x53\x39\xed\x2a\x18\xfb\x1a\x8a\x3f\x4c\xb9\xbd\x50\xf1\x3f\xfe\x63\x41\x14\x33\x56\x58\x92\x8e\x60\x2c\x2c\xed\x1b\x59\x10\x2a\xaf\x0b\xa7\x8c\xc6\x28\x89\xc2\xe8\xce\x59\xa9\xb4\x13\xaa\x12\xda\x38\x41\x47\xd5\xb9\x0e\x21\x03\x00\x37\xb6\xe9\xe8\xe8\x16\x28\xde\x2d\x2e\x85\xdd\x6b\x33\x04\x59\xc4\x2c\x39\x6f\x75\x87\x9f\x46\x95\x48\xb6\x6c\x36\x63\x2f\x54\x2b\x3d\x98\xdd\xdc\x60\x6d\xcc\x0f\x54\xc6\xc2\x78\x7b\x65\x31\xc8\xaa\x82\x36\x0e\x27\x7e\x47\x0d\x15\xee\x9a\xd3\xd3\x87\xcc\xc9\x57\x59\xd3\x42\xe9\xca\xd8\x56\xf6\xbd\x89\xae\xd8\x51\x2b\x6f\xaf\x4a\x0b\xd3\xf8\x56\x0b\xdf\xc9\xfa\x43\x8b\xc3\x8e\x2c\x5d\x5f\xec\xdd\xa9\x75\x48\x5d\x4e\x37\x81\xbb\xb7\xb3\x88\xe0\x76\xa4\xcf\x86\x74\xa4\xc2\x3b\x9a\x9c\x4d\x3c\xe8\xa4\x4b\xa8\x2a\x66\xa4\xcb\x98\xcd\x66\x68\xa4\xae\xbd\xac\x09\xf3\x7d\xb3\xaf\xbb\xd7\x66\x1e\x33\xf6\x36\xab\xff\xb8\x9b\xf0\x0c\x9c\x4f\xc7\x68\xbe\xb8\x68\x5e\xab\x57\x4f\xef\x74\x71\x1e\xe4\xeb\xc4\x64\x9d\xf3\x0c\x79\x72\xbf\xe6\xbf\xbd\x27\x24\xcb\x25\x1e\xd2\xcd\x36\xcf\x92\xd5\x26\xc7\x5f\x4d\xf1\xb4\x59\x7d\x7d\xe2\x08\xff\xf4\x5e\xa2\x78\x1e\xfd\x0a\x00\x00\xff\xff\x92\x3a\x8e\x8b\xbe\x03\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x84\x93\x5f\x6f\x9b\x3c\x14\xc6\xaf\xf1\xa7\x78\x2e\x22\x05\xa4\xb4\xd2\x7b\x8d\xfa\x4a\xb4\xf1\xb4\x48\x69\xd8\x08\x5d\xb5\x2b\xcb\x85\x03\xb1\x06\x76\x6a\xec\x25\x1f\x7f\x02\x9a\x3f\x65\xdd\xc6\x15\x3e\xcf\x39\xcf\xcf\xc7\x3e\x5e\xf2\x35\xcf\x39\x3e\x65\xe9\x23\x0e\x3b\xe1\xe4\x4b\x43\xc2\xef\x1b\x23\xcb\x8e\x05\xcf\x9f\x79\xc6\xa1\x4a\x28\x8d\x90\x05\xc1\x96\xaf\xf9\x43\x0e\x55\xb2\x20\x08\x86\x9a\x3e\x7a\x8a\x0f\xbf\x41\x96\x3e\x8b\xcd\xd3\xe3\x3d\xcf\xc2\x08\xe9\x37\x9e\x21\xfc\x92\x64\xf9\x2a\x5f\xa5\x1b\xdc\x7f\xef\x29\xa3\xbf\x50\xe5\x02\x23\x50\xcb\x96\x90\x66\x4b\x9e\xf5\x19\xaa\xc4\x92\x6f\x1f\x22\x24\x5b\x58\x73\x10\xda\xb7\x2f\x64\x17\xa3\xbd\xbb\x1d\xe9\x03\x7e\x0c\x4d\xf7\x0d\xc7\x7a\x21\x42\x6d\x8d\xdf\x53\x39\xed\x2a\x18\xfb\x1a\x8a\x3f\x4c\xb9\xbd\x50\xf1\x3f\xfe\x63\x41\x14\x33\x56\x58\x92\x8e\x60\x2c\x2c\xed\x1b\x59\x10\x2a\xaf\x0b\xa7\x8c\xc6\x28\x89\xc2\xe8\xce\x59\xa9\xb4\x13\xaa\x12\xda\x38\x41\x47\xd5\xb9\x0e\x21\x03\x00\x37\xb6\xe9\xe8\xe8\x16\x28\xde\x2d\x2e\x85\xdd\x6b\x33\x04\x59\xc4\x2c\x39\x6f\x75\x87\x9f\x46\x95\x48\xb6\x6c\x36\x63\x2f\x54\x2b\x3d\x98\xdd\xdc\x60\x6d\xcc\x0f\x54\xc6\xc2\x78\x7b\x65\x31\xc8\xaa\x82\x36\x0e\x27\x7e\x47\x0d\x15\xee\x9a\xd3\xd3\x87\xcc\xc9\x57\x59\xd3\x42\xe9\xca\xd8\x56\xf6\xbd\x89\xae\xd8\x51\x2b\x6f\xaf\x4a\x0b\xd3\xf8\x56\x0b\xdf\xc9\xfa\x43\x8b\xc3\x8e\x2c\x5d\x5f\xec\xdd\xa9\x75\x48\x5d\x4e\x37\x81\xbb\xb7\xb3\x88\xe0\x76\xa4\xcf\x86\x74\xa4\xc2\x3b\x9a\x9c\x4d\x3c\xe8\xa4\x4b\xa8\x2a\x66\xa4\xcb\x98\xcd\x66\x68\xa4\xae\xbd\xac\x09\xf3\x7d\xb3\xaf\xbb\xd7\x66\x1e\x33\xf6\x36\xab\xff\xb8\x9b\xf0\x0c\x9c\x4f\xc7\x68\xbe\xb8\x68\x5e\xab\x57\x4f\xef\x74\x71\x1e\xe4\xeb\xc4\x64\x9d\xf3\x0c\x79\x72\xbf\xe6\xbf\xbd\x27\x24\xcb\x25\x1e\xd2\xcd\x36\xcf\x92\xd5\x26\xc7\x5f\x4d\xf1\xb4\x59\x7d\x7d\xe2\x08\xff\xf4\x5e\xa2\x78\x1e\xfd\x1a\x00\x92\x3a\x8e\x8b\xbe\x03\x00\x00"),
 		},
 		"/warehouse/000007_add_wh_uploads_indexes.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000007_add_wh_uploads_indexes.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 776769574, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 451,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9c\x90\xc1\xaa\xc2\x30\x10\x45\xf7\xfd\x8a\xbb\xeb\x2b\x24\x5f\xf0\x56\xa2\x11\xba\x69\xc1\x76\xd1\x5d\x18\x9a\x80\x01\x4d\x0b\x99\xa2\x42\x3f\x5e\x8a\x58\xab\x18\x14\x57\xc3\xc0\xdc\x39\x67\x46\x4a\x9c\xf6\x7a\xe8\x0f\x1d\x99\x00\x29\x93\x64\xbd\x53\xab\x5a\x21\x2f\x36\xaa\x41\xbe\x45\x51\xd6\x50\x4d\x5e\xd5\xd5\x62\x52\x3b\xa3\x8d\x0d\xec\x3c\xb1\xeb\xfc\xd4\x7a\x3a\xda\xd0\x53\x6b\x75\x60\xe2\x21\x68\xe7\x8d\x3d\xa3\x2c\x96\x80\x3f\x67\x04\x9e\x83\x02\x73\x52\xe0\x16\xcd\xfe\xbf\xd5\x88\x3a\xbc\x85\x47\xc1\xbf\x01\xf9\xd2\xcf\xc7\x46\x45\xda\xce\xb7\xc4\xd6\x13\x5b\xf3\xd9\x6a\x5a\x79\xff\x82\x78\x15\xc6\x38\x22\xd5\xe9\x54\x1e\xe6\x59\x72\x0d\x00\x00\xff\xff\x8e\x18\xb9\x4e\xc3\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9c\x90\xc1\xaa\xc2\x30\x10\x45\xf7\xfd\x8a\xbb\xeb\x2b\x24\x5f\xf0\x56\xa2\x11\xba\x69\xc1\x76\xd1\x5d\x18\x9a\x80\x01\x4d\x0b\x99\xa2\x42\x3f\x5e\x8a\x58\xab\x18\x14\x57\xc3\xc0\xdc\x39\x67\x46\x4a\x9c\xf6\x7a\xe8\x0f\x1d\x99\x00\x29\x93\x64\xbd\x53\xab\x5a\x21\x2f\x36\xaa\x41\xbe\x45\x51\xd6\x50\x4d\x5e\xd5\xd5\x62\x52\x3b\xa3\x8d\x0d\xec\x3c\xb1\xeb\xfc\xd4\x7a\x3a\xda\xd0\x53\x6b\x75\x60\xe2\x21\x68\xe7\x8d\x3d\xa3\x2c\x96\x80\x3f\x67\x04\x9e\x83\x02\x73\x52\xe0\x16\xcd\xfe\xbf\xd5\x88\x3a\xbc\x85\x47\xc1\xbf\x01\xf9\xd2\xcf\xc7\x46\x45\xda\xce\xb7\xc4\xd6\x13\x5b\xf3\xd9\x6a\x5a\x79\xff\x82\x78\x15\xc6\x38\x22\xd5\xe9\x54\x1e\xe6\x59\x72\x1d\x00\x8e\x18\xb9\x4e\xc3\x01\x00\x00"),
 		},
 		"/warehouse/000008_add_wh_tables_indices.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000008_add_wh_tables_indices.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 776858408, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 563,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x94\x90\xc1\xaa\x83\x30\x10\x45\xf7\x7e\xc5\x2c\x15\x92\x2f\x70\xf5\x78\x4d\xc1\x8d\x42\x75\xe1\x6e\x48\x9b\x54\x07\x6c\x2c\x4d\xc4\x7e\x7e\x51\x4b\x35\x42\xc5\xee\x1c\x39\xb9\xf7\x70\x39\x87\xbe\xc6\xa6\x95\x0a\xaf\xd4\x68\x0b\x9c\x07\xc1\xff\x49\xfc\x15\x02\x92\xf4\x20\x4a\x48\x8e\x90\x66\x05\x88\x32\xc9\x8b\xdc\x87\xd1\x3a\x59\x91\xa9\xc6\x0b\x49\x21\x19\xa5\x9f\x90\xa5\xab\xcc\x70\xc5\x45\xf1\x0f\x1d\xa4\xd0\xb6\xdd\xe3\x32\x16\x28\x6d\x1d\x19\xe9\xa8\x35\xc3\xe9\xe4\xb9\xd1\x68\xe4\x4d\x7f\xad\x26\xc5\xe0\xf3\x9e\x81\x1f\xc0\x60\x4e\x18\xa4\xa6\x35\xa6\x7f\xdd\x7d\x88\xd9\x33\x88\xc7\x63\x5f\xbf\x3f\x47\x73\x27\x5d\x67\x3d\x39\x3f\x3d\x5c\xe2\x0c\x26\x7e\x56\x59\x0e\xb7\x47\xc5\xe3\x37\xa7\x5b\x2a\xf9\x2d\xdb\x93\x45\xf1\x2b\x00\x00\xff\xff\x42\xa7\x96\x5e\x33\x02\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x94\x90\xc1\xaa\x83\x30\x10\x45\xf7\x7e\xc5\x2c\x15\x92\x2f\x70\xf5\x78\x4d\xc1\x8d\x42\x75\xe1\x6e\x48\x9b\x54\x07\x6c\x2c\x4d\xc4\x7e\x7e\x51\x4b\x35\x42\xc5\xee\x1c\x39\xb9\xf7\x70\x39\x87\xbe\xc6\xa6\x95\x0a\xaf\xd4\x68\x0b\x9c\x07\xc1\xff\x49\xfc\x15\x02\x92\xf4\x20\x4a\x48\x8e\x90\x66\x05\x88\x32\xc9\x8b\xdc\x87\xd1\x3a\x59\x91\xa9\xc6\x0b\x49\x21\x19\xa5\x9f\x90\xa5\xab\xcc\x70\xc5\x45\xf1\x0f\x1d\xa4\xd0\xb6\xdd\xe3\x32\x16\x28\x6d\x1d\x19\xe9\xa8\x35\xc3\xe9\xe4\xb9\xd1\x68\xe4\x4d\x7f\xad\x26\xc5\xe0\xf3\x9e\x81\x1f\xc0\x60\x4e\x18\xa4\xa6\x35\xa6\x7f\xdd\x7d\x88\xd9\x33\x88\xc7\x63\x5f\xbf\x3f\x47\x73\x27\x5d\x67\x3d\x39\x3f\x3d\x5c\xe2\x0c\x26\x7e\x56\x59\x0e\xb7\x47\xc5\xe3\x37\xa7\x5b\x2a\xf9\x2d\xdb\x93\x45\xf1\x6b\x00\x42\xa7\x96\x5e\x33\x02\x00\x00"),
 		},
 		"/warehouse/000009_add_wh_load_file_index_on_table_staging_file.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000009_add_wh_load_file_index_on_table_staging_file.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 776929908, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 143,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd2\xd5\x55\x28\xcf\x88\xcf\xc9\x4f\x4c\x89\x4f\xcb\xcc\x49\x2d\x56\xd0\xd5\xe5\xe2\x72\x0e\x72\x75\x0c\x71\x55\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\xf0\xf3\x0f\x51\x70\x8d\xf0\x0c\x0e\x09\x46\x55\x1c\x5f\x5c\x92\x98\x9e\x99\x97\x0e\xe6\xc5\x67\xa6\xc4\x97\x24\x26\xe5\xa4\xc6\xe7\x25\xe6\xa6\xc6\x67\xe6\xa5\xa4\x56\x28\xf8\xfb\xa1\x19\xaf\x81\xa6\x45\x47\x01\xa1\x47\xd3\x9a\x0b\x10\x00\x00\xff\xff\x88\xa2\x4a\x76\x8f\x00\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd2\xd5\x55\x28\xcf\x88\xcf\xc9\x4f\x4c\x89\x4f\xcb\xcc\x49\x2d\x56\xd0\xd5\xe5\xe2\x72\x0e\x72\x75\x0c\x71\x55\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\xf0\xf3\x0f\x51\x70\x8d\xf0\x0c\x0e\x09\x46\x55\x1c\x5f\x5c\x92\x98\x9e\x99\x97\x0e\xe6\xc5\x67\xa6\xc4\x97\x24\x26\xe5\xa4\xc6\xe7\x25\xe6\xa6\xc6\x67\xe6\xa5\xa4\x56\x28\xf8\xfb\xa1\x19\xaf\x81\xa6\x45\x47\x01\xa1\x47\xd3\x9a\x0b\x30\x00\x88\xa2\x4a\x76\x8f\x00\x00\x00"),
 		},
 		"/warehouse/000010_add_metadata_to_wh_staging_files.up.sql": &vfsgen۰FileInfo{
 			name:    "000010_add_metadata_to_wh_staging_files.up.sql",
-			modTime: time.Date(2022, 5, 11, 7, 57, 37, 777146076, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x0a\x2d\x2d\x0a\x2d\x2d\x20\x77\x68\x5f\x73\x74\x61\x67\x69\x6e\x67\x5f\x46\x69\x6c\x65\x73\x0a\x2d\x2d\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x77\x68\x5f\x73\x74\x61\x67\x69\x6e\x67\x5f\x66\x69\x6c\x65\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x6d\x65\x74\x61\x64\x61\x74\x61\x20\x4a\x53\x4f\x4e\x42\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x27\x7b\x7d\x27\x3b\x0a"),
 		},
 		"/warehouse/000011_add_wh_loadfiles_metadata_column.up.sql": &vfsgen۰FileInfo{
 			name:    "000011_add_wh_loadfiles_metadata_column.up.sql",
-			modTime: time.Date(2022, 5, 11, 7, 57, 37, 777222535, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x2d\x2d\x20\x77\x68\x5f\x6c\x6f\x61\x64\x5f\x66\x69\x6c\x65\x73\x20\x2d\x2d\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x77\x68\x5f\x6c\x6f\x61\x64\x5f\x66\x69\x6c\x65\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x6d\x65\x74\x61\x64\x61\x74\x61\x20\x4a\x53\x4f\x4e\x42\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x27\x7b\x7d\x27\x3b\x0a"),
 		},
 		"/warehouse/000012_add_mergedSchema_to_wh_uploads.up.sql": &vfsgen۰FileInfo{
 			name:    "000012_add_mergedSchema_to_wh_uploads.up.sql",
-			modTime: time.Date(2022, 5, 11, 7, 57, 37, 777292744, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x0a\x2d\x2d\x0a\x2d\x2d\x20\x77\x68\x5f\x75\x70\x6c\x6f\x61\x64\x73\x0a\x2d\x2d\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x77\x68\x5f\x75\x70\x6c\x6f\x61\x64\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x6d\x65\x72\x67\x65\x64\x73\x63\x68\x65\x6d\x61\x20\x4a\x53\x4f\x4e\x42\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x27\x7b\x7d\x27\x3b\x0a"),
 		},
 		"/warehouse/000013_add_in_progress_to_wh_uploads.up.sql": &vfsgen۰FileInfo{
 			name:    "000013_add_in_progress_to_wh_uploads.up.sql",
-			modTime: time.Date(2022, 5, 11, 7, 57, 37, 777355744, time.UTC),
+			modTime: time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			content: []byte("\x0a\x2d\x2d\x0a\x2d\x2d\x20\x77\x68\x5f\x75\x70\x6c\x6f\x61\x64\x73\x0a\x2d\x2d\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x77\x68\x5f\x75\x70\x6c\x6f\x61\x64\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x69\x6e\x5f\x70\x72\x6f\x67\x72\x65\x73\x73\x20\x42\x4f\x4f\x4c\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x66\x61\x6c\x73\x65\x3b\x0a"),
 		},
 		"/warehouse/000014_add_and_drop_wh_uploads_index_for_in_progress.up.sql": &vfsgen۰CompressedFileInfo{
 			name:             "000014_add_and_drop_wh_uploads_index_for_in_progress.up.sql",
-			modTime:          time.Date(2022, 5, 11, 7, 57, 37, 777422537, time.UTC),
+			modTime:          time.Date(2022, 9, 5, 11, 32, 8, 0, time.UTC),
 			uncompressedSize: 335,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x94\x8f\x41\xca\x83\x30\x10\x46\xf7\x39\xc5\xec\xfc\x05\x73\x02\x57\x3f\x35\x05\x37\x5a\xd4\x85\xbb\x21\x98\xa1\x0d\xb4\x49\x30\x23\x6d\xc1\xc3\x17\x29\xb4\xda\x55\xbb\x1a\x18\x1e\xef\xe3\x09\x29\x85\x94\x70\x3d\xe1\x14\xce\x5e\x9b\xb8\x3c\xc4\xae\x51\xff\x9d\x82\xb2\x2a\x54\x0f\xe5\x1e\xaa\xba\x03\xd5\x97\x6d\xd7\xae\x48\x34\x14\xd9\x3a\xcd\xd6\x3b\xe4\x7b\x20\xb4\x0e\xc3\xe8\x8f\x23\xc5\x88\x91\x35\x4f\x5b\xc6\x1a\x74\xfa\x42\x31\xe8\x81\x70\xf0\x6e\xd0\x4c\x4e\x33\x19\xb4\xce\xd0\x0d\xea\x6a\x65\x87\xbf\x4f\x7d\x06\x2b\x7f\x06\xcf\x81\x6c\xcb\x59\x03\xf3\x0c\x09\x26\xcb\x79\x8d\xa5\x69\x2e\x44\xd1\xd4\x87\x77\xd1\x17\x35\xbf\x17\xe4\xe2\x11\x00\x00\xff\xff\x14\xb0\xfa\x98\x4f\x01\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x94\x8f\x41\xca\x83\x30\x10\x46\xf7\x39\xc5\xec\xfc\x05\x73\x02\x57\x3f\x35\x05\x37\x5a\xd4\x85\xbb\x21\x98\xa1\x0d\xb4\x49\x30\x23\x6d\xc1\xc3\x17\x29\xb4\xda\x55\xbb\x1a\x18\x1e\xef\xe3\x09\x29\x85\x94\x70\x3d\xe1\x14\xce\x5e\x9b\xb8\x3c\xc4\xae\x51\xff\x9d\x82\xb2\x2a\x54\x0f\xe5\x1e\xaa\xba\x03\xd5\x97\x6d\xd7\xae\x48\x34\x14\xd9\x3a\xcd\xd6\x3b\xe4\x7b\x20\xb4\x0e\xc3\xe8\x8f\x23\xc5\x88\x91\x35\x4f\x5b\xc6\x1a\x74\xfa\x42\x31\xe8\x81\x70\xf0\x6e\xd0\x4c\x4e\x33\x19\xb4\xce\xd0\x0d\xea\x6a\x65\x87\xbf\x4f\x7d\x06\x2b\x7f\x06\xcf\x81\x6c\xcb\x59\x03\xf3\x0c\x09\x26\xcb\x79\x8d\xa5\x69\x2e\x44\xd1\xd4\x87\x77\xd1\x17\x35\xbf\x17\xe4\xe2\x31\x00\x14\xb0\xfa\x98\x4f\x01\x00\x00"),
 		},
 	}
 	fs["/"].(*vfsgen۰DirInfo).entries = []os.FileInfo{
@@ -395,6 +440,15 @@ var MigrationAssets = func() http.FileSystem {
 		fs["/jobsdb/000007_add_index_rt_table.down.tmpl"].(os.FileInfo),
 		fs["/jobsdb/000007_add_index_rt_table.up.tmpl"].(os.FileInfo),
 		fs["/jobsdb/000008_alter_user_id.up.tmpl"].(os.FileInfo),
+		fs["/jobsdb/000009_alter_dataset_table.down.tmpl"].(os.FileInfo),
+		fs["/jobsdb/000009_alter_dataset_table.up.tmpl"].(os.FileInfo),
+		fs["/jobsdb/000010_alter_dataset_table.down.tmpl"].(os.FileInfo),
+		fs["/jobsdb/000010_alter_dataset_table.up.tmpl"].(os.FileInfo),
+		fs["/jobsdb/000011_alter_dataset_table.down.tmpl"].(os.FileInfo),
+		fs["/jobsdb/000011_alter_dataset_table.up.tmpl"].(os.FileInfo),
+		fs["/jobsdb/000012_alter_dataset_table.up.tmpl"].(os.FileInfo),
+		fs["/jobsdb/000013_alter_dataset_table.down.tmpl"].(os.FileInfo),
+		fs["/jobsdb/000013_alter_dataset_table.up.tmpl"].(os.FileInfo),
 	}
 	fs["/node"].(*vfsgen۰DirInfo).entries = []os.FileInfo{
 		fs["/node/000001_create_event_schema.down.sql"].(os.FileInfo),
@@ -523,7 +577,7 @@ func (f *vfsgen۰CompressedFile) Read(p []byte) (n int, err error) {
 	}
 	if f.grPos < f.seekPos {
 		// Fast-forward.
-		_, err = io.CopyN(io.Discard, f.gr, f.seekPos-f.grPos)
+		_, err = io.CopyN(ioutil.Discard, f.gr, f.seekPos-f.grPos)
 		if err != nil {
 			return 0, err
 		}
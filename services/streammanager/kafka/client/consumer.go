@@ -81,7 +81,34 @@ func (c *Consumer) Receive(ctx context.Context) (Message, error) {
 	if err != nil {
 		return Message{}, err
 	}
+	return fromKafkaMessage(msg), nil
+}
+
+// Fetch reads and returns the next message from the consumer, without committing its offset (see Commit).
+// The method blocks until a message becomes available, or an error occurs.
+func (c *Consumer) Fetch(ctx context.Context) (Message, error) {
+	msg, err := c.reader.FetchMessage(ctx)
+	if err != nil {
+		return Message{}, err
+	}
+	return fromKafkaMessage(msg), nil
+}
+
+// Commit commits the offsets of the messages, which is only possible for consumers that are part of a group.
+// Only the highest offset of each partition needs to be committed, but messages can be committed in any order.
+func (c *Consumer) Commit(ctx context.Context, msgs ...Message) error {
+	kafkaMsgs := make([]kafka.Message, len(msgs))
+	for i := range msgs {
+		kafkaMsgs[i] = kafka.Message{
+			Topic:     msgs[i].Topic,
+			Partition: int(msgs[i].Partition),
+			Offset:    msgs[i].Offset,
+		}
+	}
+	return c.reader.CommitMessages(ctx, kafkaMsgs...)
+}
 
+func fromKafkaMessage(msg kafka.Message) Message {
 	var headers []MessageHeader
 	if l := len(msg.Headers); l > 0 {
 		headers = make([]MessageHeader, l)
@@ -101,5 +128,5 @@ func (c *Consumer) Receive(ctx context.Context) (Message, error) {
 		Offset:    msg.Offset,
 		Headers:   headers,
 		Timestamp: msg.Time,
-	}, nil
+	}
 }
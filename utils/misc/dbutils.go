@@ -14,6 +14,7 @@ type AdvisoryLock int
 
 const (
 	JobsDBAddDsAdvisoryLock AdvisoryLock = 11
+	DLQRedriveAdvisoryLock  AdvisoryLock = 12
 )
 
 var (
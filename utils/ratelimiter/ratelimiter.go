@@ -12,6 +12,9 @@ type LimitStore interface {
 	Dec(key string, count int64, window time.Time) error
 	// Get gets value of previous window counter and current window counter for key
 	Get(key string, previousWindow, currentWindow time.Time) (prevValue, currValue int64, err error)
+	// IncIfBelow atomically increments current window limit counter for key, only if the rate given by the counters,
+	// prevWeight*prevValue + currValue, is below limit. It returns the counter values read and whether it was incremented
+	IncIfBelow(key string, previousWindow, currentWindow time.Time, prevWeight float64, limit int64) (prevValue, currValue int64, incremented bool, err error)
 }
 
 // RateLimiter is a simple rate-limiter for any resources inspired by Cloudflare's approach: https://blog.cloudflare.com/counting-things-a-lot-of-different-things/
@@ -83,6 +86,30 @@ func (r *RateLimiter) Check(key string, currentTime time.Time) (limitStatus *Lim
 	return limitStatus, nil
 }
 
+// CheckAndInc checks status of rate-limiting for a key and increments its counter if it isn't limited, atomically, so that
+// concurrent callers sharing the data store can't exceed the limit together. It returns error when limiter data could not be read or updated
+func (r *RateLimiter) CheckAndInc(key string, currentTime time.Time) (limitStatus *LimitStatus, err error) {
+	if currentTime.IsZero() {
+		currentTime = time.Now()
+	}
+	currentWindow := currentTime.UTC().Truncate(r.windowSize)
+	previousWindow := currentWindow.Add(-r.windowSize)
+	timeFromCurrWindow := currentTime.UTC().Sub(currentWindow)
+	prevWeight := (float64(r.windowSize) - float64(timeFromCurrWindow)) / float64(r.windowSize)
+	prevValue, currentValue, incremented, err := r.dataStore.IncIfBelow(key, previousWindow, currentWindow, prevWeight, r.requestsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	limitStatus = &LimitStatus{CurrentRate: prevWeight*float64(prevValue) + float64(currentValue)}
+	if !incremented {
+		limitStatus.IsLimited = true
+		limitDuration := r.calcLimitDuration(prevValue, currentValue, timeFromCurrWindow)
+		limitStatus.LimitDuration = &limitDuration
+	}
+	return limitStatus, nil
+}
+
 func (r *RateLimiter) calcLimitDuration(prevValue, currValue int64, timeFromCurrWindow time.Duration) time.Duration {
 	// we should find x parameter in equation: x*prevValue+currentValue = r.requestsLimit
 	// then (1.0-x)*windowSize is duration from current window start when limit can be removed
@@ -0,0 +1,148 @@
+package ratelimiter_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/utils/ratelimiter"
+)
+
+// limitStores are the local store of a single node and the store shared by the nodes of a cluster
+func limitStores() map[string]func() ratelimiter.LimitStore {
+	return map[string]func() ratelimiter.LimitStore{
+		"local": func() ratelimiter.LimitStore {
+			return ratelimiter.NewMapLimitStore(time.Minute, time.Minute)
+		},
+		"shared": func() ratelimiter.LimitStore {
+			return ratelimiter.NewSharedLimitStore(ratelimiter.NewMemoryCounterStore(), time.Minute)
+		},
+	}
+}
+
+func TestCheckAndInc(t *testing.T) {
+	windowStart := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	for name, newStore := range limitStores() {
+		t.Run(name+" store counts concurrent calls up to the limit", func(t *testing.T) {
+			dataStore := newStore()
+			limiter := ratelimiter.New(dataStore, 10, time.Minute)
+
+			var allowed int64
+			var wg sync.WaitGroup
+			for i := 0; i < 100; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					limitStatus, err := limiter.CheckAndInc("key", windowStart)
+					if !assert.NoError(t, err) {
+						return
+					}
+					if !limitStatus.IsLimited {
+						atomic.AddInt64(&allowed, 1)
+						return
+					}
+					assert.NotNil(t, limitStatus.LimitDuration)
+				}()
+			}
+			wg.Wait()
+			require.EqualValues(t, 10, allowed)
+
+			_, currValue, err := dataStore.Get("key", windowStart.Add(-time.Minute), windowStart)
+			require.NoError(t, err)
+			require.EqualValues(t, 10, currValue, "limited calls are not counted")
+
+			limitStatus, err := limiter.CheckAndInc("other-key", windowStart)
+			require.NoError(t, err)
+			require.False(t, limitStatus.IsLimited)
+		})
+
+		t.Run(name+" store weighs the previous window", func(t *testing.T) {
+			limiter := ratelimiter.New(newStore(), 10, time.Minute)
+			for i := 0; i < 10; i++ {
+				limitStatus, err := limiter.CheckAndInc("key", windowStart)
+				require.NoError(t, err)
+				require.False(t, limitStatus.IsLimited)
+			}
+
+			// half way through the next window, half of the previous window counts
+			halfWay := windowStart.Add(90 * time.Second)
+			for i := 0; i < 5; i++ {
+				limitStatus, err := limiter.CheckAndInc("key", halfWay)
+				require.NoError(t, err)
+				require.False(t, limitStatus.IsLimited)
+			}
+			limitStatus, err := limiter.CheckAndInc("key", halfWay)
+			require.NoError(t, err)
+			require.True(t, limitStatus.IsLimited)
+			require.Equal(t, float64(10), limitStatus.CurrentRate)
+		})
+
+		t.Run(name+" store releases decremented calls", func(t *testing.T) {
+			limiter := ratelimiter.New(newStore(), 2, time.Minute)
+			for i := 0; i < 2; i++ {
+				limitStatus, err := limiter.CheckAndInc("key", windowStart)
+				require.NoError(t, err)
+				require.False(t, limitStatus.IsLimited)
+			}
+			limitStatus, err := limiter.CheckAndInc("key", windowStart)
+			require.NoError(t, err)
+			require.True(t, limitStatus.IsLimited)
+
+			require.NoError(t, limiter.Dec("key", 5, windowStart))
+			limitStatus, err = limiter.Check("key", windowStart)
+			require.NoError(t, err)
+			require.False(t, limitStatus.IsLimited)
+			require.Equal(t, float64(0), limitStatus.CurrentRate, "counters don't drop below zero")
+		})
+	}
+}
+
+func TestSharedLimitStore(t *testing.T) {
+	windowStart := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	counterStore := ratelimiter.NewMemoryCounterStore()
+	node1 := ratelimiter.New(ratelimiter.NewSharedLimitStore(counterStore, time.Minute), 3, time.Minute)
+	node2 := ratelimiter.New(ratelimiter.NewSharedLimitStore(counterStore, time.Minute), 3, time.Minute)
+
+	var allowed int
+	for i := 0; i < 3; i++ {
+		for _, node := range []*ratelimiter.RateLimiter{node1, node2} {
+			limitStatus, err := node.CheckAndInc("key", windowStart)
+			require.NoError(t, err)
+			if !limitStatus.IsLimited {
+				allowed++
+			}
+		}
+	}
+	require.Equal(t, 3, allowed, "the nodes share the limit")
+}
+
+func TestMemoryCounterStoreExpiration(t *testing.T) {
+	store := ratelimiter.NewMemoryCounterStore()
+	value, err := store.IncrBy("key", 2, 20*time.Millisecond)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, value)
+
+	_, _, incremented, err := store.IncrIfBelow("previous-key", "key", 1, 3, 20*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, incremented)
+	values, err := store.GetCounters("key", "missing-key")
+	require.NoError(t, err)
+	require.Equal(t, []int64{3, 0}, values)
+
+	require.Eventually(t, func() bool {
+		values, err := store.GetCounters("key")
+		return err == nil && values[0] == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestMapLimitStoreFlush(t *testing.T) {
+	store := ratelimiter.NewMapLimitStore(10*time.Millisecond, 10*time.Millisecond)
+	require.NoError(t, store.Inc("key", time.Now()))
+	require.Equal(t, 1, store.Size())
+	require.Eventually(t, func() bool { return store.Size() == 0 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,143 @@
+package ratelimiter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CounterStore is a store of counters shared by all the nodes of the cluster, e.g. a kvstoremanager.KVStoreManager
+type CounterStore interface {
+	// IncrBy increments the counter of key by value, resetting its expiration, and returns its new value
+	IncrBy(key string, value int64, expiration time.Duration) (int64, error)
+	// GetCounters returns the values of the counters of keys, 0 for the ones which don't exist
+	GetCounters(keys ...string) ([]int64, error)
+	// IncrIfBelow atomically increments the counter of currKey by one, resetting its expiration, only if
+	// prevWeight*value(prevKey) + value(currKey) is below limit. It returns the values read and whether it was incremented
+	IncrIfBelow(prevKey, currKey string, prevWeight float64, limit int64, expiration time.Duration) (prevValue, currValue int64, incremented bool, err error)
+}
+
+// SharedLimitStore represents internal limiter data database where data are stored in a CounterStore, so that the limits
+// are shared by all the nodes of the cluster instead of applying to each node
+type SharedLimitStore struct {
+	store          CounterStore
+	expirationTime time.Duration
+}
+
+// NewSharedLimitStore creates new data store for internal limiter data kept in store. Each counter expires after expirationTime from its last update
+func NewSharedLimitStore(store CounterStore, expirationTime time.Duration) *SharedLimitStore {
+	return &SharedLimitStore{
+		store:          store,
+		expirationTime: expirationTime,
+	}
+}
+
+// Inc increments current window limit counter for key
+func (s *SharedLimitStore) Inc(key string, window time.Time) error {
+	_, err := s.store.IncrBy(sharedKey(key, window), 1, s.expirationTime)
+	return err
+}
+
+// Dec decrements current window limit counter for key
+func (s *SharedLimitStore) Dec(key string, count int64, window time.Time) error {
+	_, err := s.store.IncrBy(sharedKey(key, window), -count, s.expirationTime)
+	return err
+}
+
+// Get gets value of previous window counter and current window counter for key
+func (s *SharedLimitStore) Get(key string, previousWindow, currentWindow time.Time) (prevValue, currValue int64, err error) {
+	values, err := s.store.GetCounters(sharedKey(key, previousWindow), sharedKey(key, currentWindow))
+	if err != nil {
+		return 0, 0, err
+	}
+	// counters decremented concurrently by several nodes may drop below zero
+	return nonNegative(values[0]), nonNegative(values[1]), nil
+}
+
+// IncIfBelow atomically increments current window limit counter for key if prevWeight*prevValue + currValue is below limit
+func (s *SharedLimitStore) IncIfBelow(key string, previousWindow, currentWindow time.Time, prevWeight float64, limit int64) (prevValue, currValue int64, incremented bool, err error) {
+	prevValue, currValue, incremented, err = s.store.IncrIfBelow(sharedKey(key, previousWindow), sharedKey(key, currentWindow), prevWeight, limit, s.expirationTime)
+	return nonNegative(prevValue), nonNegative(currValue), incremented, err
+}
+
+// sharedKey keeps the counters of all the windows of a key in the same slot of a redis cluster
+func sharedKey(key string, window time.Time) string {
+	return fmt.Sprintf("rudder-limiter:{%s}_%s", key, window.Format(time.RFC3339))
+}
+
+func nonNegative(value int64) int64 {
+	if value < 0 {
+		return 0
+	}
+	return value
+}
+
+// MemoryCounterStore is an in-memory CounterStore, standing in for the store shared by all the nodes of the cluster in tests
+// and single node setups. Expired counters are removed when they are read or incremented.
+type MemoryCounterStore struct {
+	mu       sync.Mutex
+	counters map[string]memoryCounter
+	now      func() time.Time
+}
+
+type memoryCounter struct {
+	value     int64
+	expiresAt time.Time
+}
+
+// NewMemoryCounterStore creates a new in-memory counter store
+func NewMemoryCounterStore() *MemoryCounterStore {
+	return &MemoryCounterStore{
+		counters: make(map[string]memoryCounter),
+		now:      time.Now,
+	}
+}
+
+// IncrBy increments the counter of key by value, resetting its expiration, and returns its new value
+func (m *MemoryCounterStore) IncrBy(key string, value int64, expiration time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counter := m.get(key)
+	counter.value += value
+	counter.expiresAt = m.now().Add(expiration)
+	m.counters[key] = counter
+	return counter.value, nil
+}
+
+// GetCounters returns the values of the counters of keys, 0 for the ones which don't exist
+func (m *MemoryCounterStore) GetCounters(keys ...string) ([]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	values := make([]int64, len(keys))
+	for i, key := range keys {
+		values[i] = m.get(key).value
+	}
+	return values, nil
+}
+
+// IncrIfBelow atomically increments the counter of currKey by one, resetting its expiration, only if
+// prevWeight*value(prevKey) + value(currKey) is below limit
+func (m *MemoryCounterStore) IncrIfBelow(prevKey, currKey string, prevWeight float64, limit int64, expiration time.Duration) (prevValue, currValue int64, incremented bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	prevValue = nonNegative(m.get(prevKey).value)
+	counter := m.get(currKey)
+	currValue = nonNegative(counter.value)
+	if prevWeight*float64(prevValue)+float64(currValue) >= float64(limit) {
+		return prevValue, currValue, false, nil
+	}
+	counter.value++
+	counter.expiresAt = m.now().Add(expiration)
+	m.counters[currKey] = counter
+	return prevValue, currValue, true, nil
+}
+
+// get needs to be called with m.mu held
+func (m *MemoryCounterStore) get(key string) memoryCounter {
+	counter, ok := m.counters[key]
+	if ok && !m.now().Before(counter.expiresAt) {
+		delete(m.counters, key)
+		return memoryCounter{}
+	}
+	return counter
+}
@@ -75,6 +75,23 @@ func (m *MapLimitStore) Get(key string, previousWindow, currentWindow time.Time)
 	return prevValue, currValue, nil
 }
 
+// IncIfBelow atomically increments current window limit counter for key if prevWeight*prevValue + currValue is below limit
+func (m *MapLimitStore) IncIfBelow(key string, previousWindow, currentWindow time.Time, prevWeight float64, limit int64) (prevValue, currValue int64, incremented bool, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	prevValue = m.data[mapKey(key, previousWindow)].val
+	data := m.data[mapKey(key, currentWindow)]
+	currValue = data.val
+	if prevWeight*float64(prevValue)+float64(currValue) >= float64(limit) {
+		return prevValue, currValue, false, nil
+	}
+	data.val++
+	data.lastUpdate = time.Now().UTC()
+	m.data[mapKey(key, currentWindow)] = data
+	return prevValue, currValue, true, nil
+}
+
 // Size returns current length of data map
 func (m *MapLimitStore) Size() int {
 	m.mutex.RLock()
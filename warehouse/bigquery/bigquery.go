@@ -62,6 +62,11 @@ var dataTypesMap = map[string]bigquery.FieldType{
 	"datetime": bigquery.TimestampFieldType,
 }
 
+// maps the datatypes stored in rudder which columns can be altered to, to their names in bigquery standard sql
+var sqlDataTypesMap = map[string]string{
+	"float": "FLOAT64",
+}
+
 // maps datatype in bigquery to datatype stored in rudder
 var dataTypesMapToRudder = map[bigquery.FieldType]string{
 	"BOOLEAN":   "boolean",
@@ -272,6 +277,12 @@ func (bq *HandleT) loadTable(tableName string, forceLoad, getLoadFileLocFromTabl
 	gcsRef.SourceFormat = bigquery.JSON
 	gcsRef.MaxBadRecords = 0
 	gcsRef.IgnoreUnknownValues = false
+	// the load files of the identity tables are written by the identity resolution in json, whatever the load file type of the upload
+	useAvro := bq.Uploader.GetLoadFileType() == warehouseutils.LOAD_FILE_TYPE_AVRO && !getLoadFileLocFromTableUploads
+	if useAvro {
+		gcsRef.SourceFormat = bigquery.Avro
+	}
+	upsertConfig, upsert := warehouseutils.GetUpsertConfig(bq.Warehouse, tableName)
 
 	loadTableByAppend := func() (err error) {
 		stagingLoadTable.partitionDate = time.Now().Format("2006-01-02")
@@ -284,6 +295,7 @@ func (bq *HandleT) loadTable(tableName string, forceLoad, getLoadFileLocFromTabl
 		}
 
 		loader := bq.Db.Dataset(bq.Namespace).Table(outputTable).LoaderFrom(gcsRef)
+		loader.UseAvroLogicalTypes = useAvro
 
 		job, err := loader.Run(bq.BQContext)
 		if err != nil {
@@ -320,6 +332,7 @@ func (bq *HandleT) loadTable(tableName string, forceLoad, getLoadFileLocFromTabl
 		}
 
 		loader := bq.Db.Dataset(bq.Namespace).Table(stagingTableName).LoaderFrom(gcsRef)
+		loader.UseAvroLogicalTypes = useAvro
 		job, err := loader.Run(bq.BQContext)
 		if err != nil {
 			pkgLogger.Errorf("BQ: Error initiating staging table load job: %v\n", err)
@@ -349,6 +362,16 @@ func (bq *HandleT) loadTable(tableName string, forceLoad, getLoadFileLocFromTabl
 			partitionKey = column
 		}
 
+		// in upsert mode, the rows of the table are only updated by rows with a later value of the order by column
+		orderBy := "RECEIVED_AT DESC"
+		matchedCondition := ""
+		if upsert {
+			primaryKey = strings.Join(upsertConfig.PrimaryKeys, ",")
+			partitionKey = primaryKey
+			orderBy = fmt.Sprintf("CASE WHEN `%[1]s` IS NULL THEN 1 ELSE 0 END, `%[1]s` DESC", upsertConfig.OrderByColumn)
+			matchedCondition = fmt.Sprintf("AND (original.`%[1]s` IS NULL OR staging.`%[1]s` >= original.`%[1]s`)", upsertConfig.OrderByColumn)
+		}
+
 		tableColMap := bq.Uploader.GetTableSchemaInWarehouse(tableName)
 		var tableColNames []string
 		for colName := range tableColMap {
@@ -374,14 +397,14 @@ func (bq *HandleT) loadTable(tableName string, forceLoad, getLoadFileLocFromTabl
 		sqlStatement := fmt.Sprintf(`MERGE INTO %[1]s AS original
 										USING (
 											SELECT * FROM (
-												SELECT *, row_number() OVER (PARTITION BY %[7]s ORDER BY RECEIVED_AT DESC) AS _rudder_staging_row_number FROM %[2]s
+												SELECT *, row_number() OVER (PARTITION BY %[7]s ORDER BY %[8]s) AS _rudder_staging_row_number FROM %[2]s
 											) AS q WHERE _rudder_staging_row_number = 1
 										) AS staging
 										ON (%[3]s)
-										WHEN MATCHED THEN
+										WHEN MATCHED %[9]s THEN
 										UPDATE SET %[6]s
 										WHEN NOT MATCHED THEN
-										INSERT (%[4]s) VALUES (%[5]s)`, bqTable(tableName), bqTable(stagingTableName), primaryJoinClause, columnNames, stagingColumnNames, columnsWithValues, partitionKey)
+										INSERT (%[4]s) VALUES (%[5]s)`, bqTable(tableName), bqTable(stagingTableName), primaryJoinClause, columnNames, stagingColumnNames, columnsWithValues, partitionKey, orderBy, matchedCondition)
 		pkgLogger.Infof("BQ: Dedup records for table:%s using staging table: %s\n", tableName, sqlStatement)
 
 		q := bq.Db.Query(sqlStatement)
@@ -402,7 +425,7 @@ func (bq *HandleT) loadTable(tableName string, forceLoad, getLoadFileLocFromTabl
 		return
 	}
 
-	if !dedupEnabled() {
+	if !dedupEnabled() && !upsert {
 		err = loadTableByAppend()
 		return
 	}
@@ -808,8 +831,23 @@ func (bq *HandleT) AddColumn(tableName, columnName, columnType string) (err erro
 	return err
 }
 
+// AlterColumn changes the type of a column to a wider one. Strings have no length in bigquery, so they are not altered to text.
 func (bq *HandleT) AlterColumn(tableName, columnName, columnType string) (err error) {
-	return
+	dataType, ok := sqlDataTypesMap[columnType]
+	if !ok {
+		return
+	}
+	sqlStatement := fmt.Sprintf("ALTER TABLE `%s`.`%s` ALTER COLUMN `%s` SET DATA TYPE %s", bq.Namespace, tableName, columnName, dataType)
+	pkgLogger.Infof("BQ: Altering column in bigquery for BQ:%s : %v", bq.Warehouse.Destination.ID, sqlStatement)
+	job, err := bq.Db.Query(sqlStatement).Run(bq.BQContext)
+	if err != nil {
+		return err
+	}
+	status, err := job.Wait(bq.BQContext)
+	if err != nil {
+		return err
+	}
+	return status.Err()
 }
 
 // FetchSchema queries bigquery and returns the schema assoiciated with provided namespace
@@ -528,6 +528,14 @@ func (ch *HandleT) loadTable(tableName string, tableSchemaInUpload warehouseutil
 	pkgLogger.Infof("%s LoadTable Started", ch.GetLogIdentifier(tableName))
 	defer pkgLogger.Infof("%s LoadTable Completed", ch.GetLogIdentifier(tableName))
 
+	// tables in upsert mode are only deduplicated by clickhouse if they were created for it
+	if upsertConfig, ok := warehouseutils.GetUpsertConfig(ch.Warehouse, tableName); ok {
+		err = ch.checkUpsertTable(tableName, upsertConfig)
+		if err != nil {
+			return
+		}
+	}
+
 	// Clickhouse stats
 	chStats := ch.newClickHouseStat(tableName)
 
@@ -790,8 +798,58 @@ func getSortKeyTuple(sortKeyFields []string) string {
 	return tuple
 }
 
+// upsertVersionTypePrefixes are the prefixes of the clickhouse types of the columns ReplacingMergeTree accepts as version
+var upsertVersionTypePrefixes = []string{"UInt", "Date"}
+
+// validateUpsertOrderByColumn returns an error if the order by column of a table in upsert mode can not be the version of
+// its ReplacingMergeTree, which should be a UInt, Date or DateTime column
+func validateUpsertOrderByColumn(tableName string, upsertConfig warehouseutils.UpsertConfigT, columns map[string]string) error {
+	columnType := rudderDataTypesMapToClickHouse[columns[upsertConfig.OrderByColumn]]
+	for _, prefix := range upsertVersionTypePrefixes {
+		if strings.HasPrefix(columnType, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("order by column %s of table %s in upsert mode is of type %s, clickhouse only versions rows by UInt, Date or DateTime columns", upsertConfig.OrderByColumn, tableName, columns[upsertConfig.OrderByColumn])
+}
+
+// checkUpsertEngine returns an error if a table in upsert mode, with the given engine, sorting key and full engine of
+// system.tables, is not a ReplacingMergeTree sorted by the primary keys and versioned by the order by column of the table
+func checkUpsertEngine(tableName string, upsertConfig warehouseutils.UpsertConfigT, engine, sortingKey, engineFull string) error {
+	var version string
+	if engineArgs := strings.TrimPrefix(engineFull, engine+"("); engineArgs != engineFull && strings.Contains(engineArgs, ")") {
+		engineArgs := strings.Split(engineArgs[:strings.Index(engineArgs, ")")], ",")
+		version = strings.Trim(strings.TrimSpace(engineArgs[len(engineArgs)-1]), "`\"'")
+	}
+	expectedSortingKey := strings.Join(upsertConfig.PrimaryKeys, ", ")
+	if !strings.HasSuffix(engine, "ReplacingMergeTree") || strings.ReplaceAll(sortingKey, "`", "") != expectedSortingKey || version != upsertConfig.OrderByColumn {
+		return fmt.Errorf("table %s in upsert mode has engine %s, it should be a ReplacingMergeTree sorted by (%s) and versioned by %s: recreate the table or remove its upsert mode", tableName, engineFull, expectedSortingKey, upsertConfig.OrderByColumn)
+	}
+	return nil
+}
+
+// checkUpsertTable returns an error if the order by column or the engine of an existing table in upsert mode do not
+// deduplicate its rows
+func (ch *HandleT) checkUpsertTable(tableName string, upsertConfig warehouseutils.UpsertConfigT) error {
+	err := validateUpsertOrderByColumn(tableName, upsertConfig, ch.Uploader.GetTableSchemaInWarehouse(tableName))
+	if err != nil {
+		return err
+	}
+	var engine, sortingKey, engineFull string
+	sqlStatement := "SELECT engine, sorting_key, engine_full FROM system.tables WHERE database = ? AND name = ?"
+	err = ch.Db.QueryRow(sqlStatement, ch.Namespace, tableName).Scan(&engine, &sortingKey, &engineFull)
+	if err != nil {
+		return fmt.Errorf("%s Error fetching the engine of table in upsert mode: %w", ch.GetLogIdentifier(tableName), err)
+	}
+	return checkUpsertEngine(tableName, upsertConfig, engine, sortingKey, engineFull)
+}
+
 // createTable creates table with engine ReplacingMergeTree(), this is used for dedupe event data and replace it will latest data if duplicate data found. This logic is handled by clickhouse
 // The engine differs from MergeTree in that it removes duplicate entries with the same sorting key value.
+// Tables in upsert mode are sorted by their primary keys and versioned by their order by column, so that the latest row
+// with a primary key is kept. They are not partitioned, as the duplicates are only removed within a partition.
+// Duplicates are removed by background merges, so readers of these tables should query them with FINAL, e.g.
+// SELECT * FROM "namespace"."orders" FINAL, to only read the latest rows.
 func (ch *HandleT) CreateTable(tableName string, columns map[string]string) (err error) {
 	sortKeyFields := []string{"received_at", "id"}
 	if tableName == warehouseutils.DiscardsTable {
@@ -804,14 +862,28 @@ func (ch *HandleT) CreateTable(tableName string, columns map[string]string) (err
 	if tableName == warehouseutils.UsersTable {
 		return ch.createUsersTable(tableName, columns)
 	}
+	notNullableColumns := sortKeyFields
+	var versionColumn string
+	upsertConfig, upsert := warehouseutils.GetUpsertConfig(ch.Warehouse, tableName)
+	if upsert {
+		if err = validateUpsertOrderByColumn(tableName, upsertConfig, columns); err != nil {
+			return
+		}
+		sortKeyFields = upsertConfig.PrimaryKeys
+		versionColumn = fmt.Sprintf(`%q`, upsertConfig.OrderByColumn)
+		notNullableColumns = append(append([]string{}, sortKeyFields...), upsertConfig.OrderByColumn)
+	}
 	clusterClause := ""
 	engine := "ReplacingMergeTree"
-	engineOptions := ""
+	engineOptions := versionColumn
 	cluster := warehouseutils.GetConfigValue(Cluster, ch.Warehouse)
 	if len(strings.TrimSpace(cluster)) > 0 {
 		clusterClause = fmt.Sprintf(`ON CLUSTER %q`, cluster)
 		engine = fmt.Sprintf(`%s%s`, "Replicated", engine)
 		engineOptions = `'/clickhouse/{cluster}/tables/{database}/{table}', '{replica}'`
+		if versionColumn != "" {
+			engineOptions = fmt.Sprintf(`%s, %s`, engineOptions, versionColumn)
+		}
 	}
 	var orderByClause string
 	if len(sortKeyFields) > 0 {
@@ -819,11 +891,11 @@ func (ch *HandleT) CreateTable(tableName string, columns map[string]string) (err
 	}
 
 	var partitionByClause string
-	if _, ok := columns[partitionField]; ok {
+	if _, ok := columns[partitionField]; ok && !upsert {
 		partitionByClause = fmt.Sprintf(`PARTITION BY toDate(%s)`, partitionField)
 	}
 
-	sqlStatement = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %q.%q %s ( %v ) ENGINE = %s(%s) %s %s`, ch.Namespace, tableName, clusterClause, ColumnsWithDataTypes(tableName, columns, sortKeyFields), engine, engineOptions, orderByClause, partitionByClause)
+	sqlStatement = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %q.%q %s ( %v ) ENGINE = %s(%s) %s %s`, ch.Namespace, tableName, clusterClause, ColumnsWithDataTypes(tableName, columns, notNullableColumns), engine, engineOptions, orderByClause, partitionByClause)
 
 	pkgLogger.Infof("CH: Creating table in clickhouse for ch:%s : %v", ch.Warehouse.Destination.ID, sqlStatement)
 	_, err = ch.Db.Exec(sqlStatement)
@@ -1001,6 +1073,10 @@ func (ch *HandleT) IsEmpty(warehouse warehouseutils.WarehouseT) (empty bool, err
 
 func (ch *HandleT) GetTotalCountInTable(tableName string) (total int64, err error) {
 	sqlStatement := fmt.Sprintf(`SELECT count(*) FROM "%[1]s"."%[2]s"`, ch.Namespace, tableName)
+	// tables in upsert mode are counted without the duplicates not merged yet
+	if _, ok := warehouseutils.GetUpsertConfig(ch.Warehouse, tableName); ok {
+		sqlStatement += " FINAL"
+	}
 	err = ch.Db.QueryRow(sqlStatement).Scan(&total)
 	if err != nil {
 		pkgLogger.Errorf(`CH: Error getting total count in table %s:%s`, ch.Namespace, tableName)
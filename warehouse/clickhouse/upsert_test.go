@@ -0,0 +1,87 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestValidateUpsertOrderByColumn(t *testing.T) {
+	upsertConfig := warehouseutils.UpsertConfigT{PrimaryKeys: []string{"order_id"}, OrderByColumn: "updated_at"}
+	require.NoError(t, validateUpsertOrderByColumn("orders", upsertConfig, map[string]string{"order_id": "string", "updated_at": "datetime"}))
+	require.NoError(t, validateUpsertOrderByColumn("orders", upsertConfig, map[string]string{"order_id": "string", "updated_at": "boolean"}))
+	require.EqualError(t,
+		validateUpsertOrderByColumn("orders", upsertConfig, map[string]string{"order_id": "string", "updated_at": "int"}),
+		"order by column updated_at of table orders in upsert mode is of type int, clickhouse only versions rows by UInt, Date or DateTime columns",
+	)
+	require.Error(t, validateUpsertOrderByColumn("orders", upsertConfig, map[string]string{"order_id": "string", "updated_at": "array(datetime)"}))
+}
+
+func TestCheckUpsertEngine(t *testing.T) {
+	upsertConfig := warehouseutils.UpsertConfigT{PrimaryKeys: []string{"order_id", "user_id"}, OrderByColumn: "updated_at"}
+
+	tests := []struct {
+		name       string
+		engine     string
+		sortingKey string
+		engineFull string
+		valid      bool
+	}{
+		{
+			name:       "created in upsert mode",
+			engine:     "ReplacingMergeTree",
+			sortingKey: "order_id, user_id",
+			engineFull: "ReplacingMergeTree(updated_at) ORDER BY (order_id, user_id) SETTINGS index_granularity = 8192",
+			valid:      true,
+		},
+		{
+			name:       "created in upsert mode on a cluster",
+			engine:     "ReplicatedReplacingMergeTree",
+			sortingKey: "order_id, user_id",
+			engineFull: "ReplicatedReplacingMergeTree('/clickhouse/{cluster}/tables/{database}/{table}', '{replica}', updated_at) ORDER BY (order_id, user_id) SETTINGS index_granularity = 8192",
+			valid:      true,
+		},
+		{
+			name:       "created before the upsert mode",
+			engine:     "ReplacingMergeTree",
+			sortingKey: "received_at, id",
+			engineFull: "ReplacingMergeTree PARTITION BY toDate(received_at) ORDER BY (received_at, id) SETTINGS index_granularity = 8192",
+		},
+		{
+			name:       "sorted by other primary keys",
+			engine:     "ReplacingMergeTree",
+			sortingKey: "order_id",
+			engineFull: "ReplacingMergeTree(updated_at) ORDER BY order_id SETTINGS index_granularity = 8192",
+		},
+		{
+			name:       "versioned by another column",
+			engine:     "ReplacingMergeTree",
+			sortingKey: "order_id, user_id",
+			engineFull: "ReplacingMergeTree(received_at) ORDER BY (order_id, user_id) SETTINGS index_granularity = 8192",
+		},
+		{
+			name:       "not versioned",
+			engine:     "ReplacingMergeTree",
+			sortingKey: "order_id, user_id",
+			engineFull: "ReplacingMergeTree ORDER BY (order_id, user_id) SETTINGS index_granularity = 8192",
+		},
+		{
+			name:       "not a ReplacingMergeTree",
+			engine:     "MergeTree",
+			sortingKey: "order_id, user_id",
+			engineFull: "MergeTree ORDER BY (order_id, user_id) SETTINGS index_granularity = 8192",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkUpsertEngine("orders", upsertConfig, tt.engine, tt.sortingKey, tt.engineFull)
+			if tt.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, "table orders in upsert mode has engine "+tt.engineFull+", it should be a ReplacingMergeTree sorted by (order_id, user_id) and versioned by updated_at: recreate the table or remove its upsert mode")
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package datalake
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -20,7 +21,10 @@ func Init() {
 	pkgLogger = logger.NewLogger().Child("warehouse").Child("datalake")
 }
 
+// HandleT loads the uploads of the datalakes, within the context of the upload from Setup until Cleanup
 type HandleT struct {
+	ctx              context.Context
+	cancel           context.CancelFunc
 	SchemaRepository schemarepository.SchemaRepository
 	Warehouse        warehouseutils.WarehouseT
 	Uploader         warehouseutils.UploaderI
@@ -29,8 +33,9 @@ type HandleT struct {
 func (wh *HandleT) Setup(warehouse warehouseutils.WarehouseT, uploader warehouseutils.UploaderI) (err error) {
 	wh.Warehouse = warehouse
 	wh.Uploader = uploader
+	wh.ctx, wh.cancel = context.WithCancel(context.Background())
 
-	wh.SchemaRepository, err = schemarepository.NewSchemaRepository(wh.Warehouse, wh.Uploader)
+	wh.SchemaRepository, err = schemarepository.NewSchemaRepository(wh.ctx, wh.Warehouse, wh.Uploader)
 
 	return err
 }
@@ -64,11 +69,21 @@ func (wh *HandleT) AlterColumn(tableName, columnName, columnType string) (err er
 }
 
 func (wh *HandleT) LoadTable(tableName string) error {
+	if tableLoader, ok := wh.SchemaRepository.(schemarepository.TableLoader); ok {
+		return tableLoader.LoadTable(tableName)
+	}
 	pkgLogger.Infof("Skipping load for table %s : %s is a datalake destination", tableName, wh.Warehouse.Destination.ID)
 	return nil
 }
 
 func (wh *HandleT) LoadUserTables() map[string]error {
+	if tableLoader, ok := wh.SchemaRepository.(schemarepository.TableLoader); ok {
+		errorMap := map[string]error{warehouseutils.IdentifiesTable: tableLoader.LoadTable(warehouseutils.IdentifiesTable)}
+		if len(wh.Uploader.GetTableSchemaInUpload(warehouseutils.UsersTable)) > 0 {
+			errorMap[warehouseutils.UsersTable] = tableLoader.LoadTable(warehouseutils.UsersTable)
+		}
+		return errorMap
+	}
 	pkgLogger.Infof("Skipping load for user tables : %s is a datalake destination", wh.Warehouse.Destination.ID)
 	// return map with nil error entries for identifies and users(if any) tables
 	// this is so that they are marked as succeeded
@@ -90,6 +105,9 @@ func (wh *HandleT) LoadIdentityMappingsTable() error {
 }
 
 func (wh *HandleT) Cleanup() {
+	if wh.cancel != nil {
+		wh.cancel()
+	}
 }
 
 func (wh *HandleT) IsEmpty(warehouse warehouseutils.WarehouseT) (bool, error) {
@@ -0,0 +1,308 @@
+package iceberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+const (
+	catalogFileName     = "iceberg-catalog.json"
+	versionHintFileName = "version-hint.text"
+	// loadFilesSummaryKey is the key of the snapshot summary property with the hash of the load files appended in
+	// the snapshot, so that the load files of an upload are appended only once when the upload is retried
+	loadFilesSummaryKey = "rudder.load-files-hash"
+)
+
+// ErrConcurrentCommit is returned when the table was committed by someone else since its metadata was read
+var ErrConcurrentCommit = errors.New("iceberg table was updated concurrently")
+
+/*
+Catalog is a file based iceberg catalog of the datalake tables of a namespace, in the layout of the hadoop catalog:
+the metadata of the version N of a table is at <table location>/metadata/vN.metadata.json, and the version-hint.text
+file next to it holds the current version of the table. Creating the metadata file of the version N+1 is the commit of
+a new version: it is written with a conditional write of the object storage (If-None-Match on S3 and Azure, a
+generation precondition on GCS), so that only one of concurrent commits over the version N succeeds, and every other
+file of the new version is written before it, so readers see either all the changes of a commit or none of them. The
+version hint is only a hint: a version committed by a writer which failed before updating it is still found.
+
+Every table is committed on its own: the tables of an upload are not committed atomically together, so readers can
+see the new snapshot of a table of an upload before the snapshots of its other tables, and a failed upload can leave
+some of its tables committed. Retrying the upload commits the rest, appending the load files of a table only once.
+
+The tables of the namespace are listed in the iceberg-catalog.json file of the namespace, so that the schema of the
+namespace can be fetched without listing the data files of the tables.
+*/
+type Catalog struct {
+	store     ObjectStore
+	namespace string
+	now       func() time.Time
+}
+
+type catalogFileT struct {
+	Tables []string `json:"tables"`
+}
+
+func NewCatalog(store ObjectStore, namespace string) *Catalog {
+	return &Catalog{store: store, namespace: namespace, now: time.Now}
+}
+
+func (c *Catalog) tablePath(tableName string) string {
+	return warehouseutils.GetTablePathInObjectStorage(c.namespace, tableName)
+}
+
+func (c *Catalog) metadataPath(tableName, fileName string) string {
+	return path.Join(c.tablePath(tableName), "metadata", fileName)
+}
+
+// catalogPath returns the path of the catalog file, in the folder of the tables of the namespace
+func (c *Catalog) catalogPath() string {
+	return c.tablePath(catalogFileName)
+}
+
+// Tables returns the names of the tables of the namespace
+func (c *Catalog) Tables(ctx context.Context) ([]string, error) {
+	data, err := c.store.Read(ctx, c.catalogPath())
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var catalog catalogFileT
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("parsing iceberg catalog of namespace %s: %w", c.namespace, err)
+	}
+	return catalog.Tables, nil
+}
+
+// Table returns the current metadata of a table and its version, or ErrNotFound if the table doesn't exist
+func (c *Catalog) Table(ctx context.Context, tableName string) (*TableMetadata, int, error) {
+	version, err := c.currentVersion(ctx, tableName)
+	if err != nil {
+		return nil, 0, err
+	}
+	data, err := c.store.Read(ctx, c.metadataPath(tableName, metadataFileName(version)))
+	if err != nil {
+		return nil, 0, err
+	}
+	var metadata TableMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, 0, fmt.Errorf("parsing metadata of iceberg table %s: %w", tableName, err)
+	}
+	return &metadata, version, nil
+}
+
+// currentVersion returns the version of the version hint of a table, or the later version committed by a writer which
+// failed before updating the version hint
+func (c *Catalog) currentVersion(ctx context.Context, tableName string) (int, error) {
+	var version int
+	data, err := c.store.Read(ctx, c.metadataPath(tableName, versionHintFileName))
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return 0, err
+	}
+	if err == nil {
+		if version, err = strconv.Atoi(strings.TrimSpace(string(data))); err != nil {
+			return 0, fmt.Errorf("parsing version hint of iceberg table %s: %w", tableName, err)
+		}
+	}
+	for {
+		_, err := c.store.Read(ctx, c.metadataPath(tableName, metadataFileName(version+1)))
+		if errors.Is(err, ErrNotFound) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		version++
+	}
+	if version == 0 {
+		return 0, fmt.Errorf("iceberg table %s: %w", tableName, ErrNotFound)
+	}
+	return version, nil
+}
+
+// CreateTable creates an unpartitioned table with the columns of columnMap, if it doesn't exist yet
+func (c *Catalog) CreateTable(ctx context.Context, tableName string, columnMap map[string]string) error {
+	if _, _, err := c.Table(ctx, tableName); err == nil {
+		return nil
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	metadata, err := NewTableMetadata(c.store.URI(c.tablePath(tableName)), columnMap, c.now())
+	if err != nil {
+		return err
+	}
+	if err := c.commit(ctx, tableName, 0, metadata); err != nil {
+		return err
+	}
+	return c.addToCatalog(ctx, tableName)
+}
+
+func (c *Catalog) addToCatalog(ctx context.Context, tableName string) error {
+	tables, err := c.Tables(ctx)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if table == tableName {
+			return nil
+		}
+	}
+	tables = append(tables, tableName)
+	sort.Strings(tables)
+	data, err := json.Marshal(catalogFileT{Tables: tables})
+	if err != nil {
+		return err
+	}
+	return c.store.Write(ctx, c.catalogPath(), data)
+}
+
+// EvolveSchema commits a new version of the schema of a table with the changes of diff
+func (c *Catalog) EvolveSchema(ctx context.Context, tableName string, diff warehouseutils.TableSchemaDiffT) error {
+	metadata, version, err := c.Table(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	changed, err := metadata.EvolveSchema(diff, c.now())
+	if err != nil || !changed {
+		return err
+	}
+	return c.commit(ctx, tableName, version, metadata)
+}
+
+// AppendFiles commits a snapshot of a table appending the data files to it. Appending again the same data files, as
+// when an upload is retried after its commit, is a no-op.
+func (c *Catalog) AppendFiles(ctx context.Context, tableName string, dataFiles []DataFile) error {
+	if len(dataFiles) == 0 {
+		return nil
+	}
+	metadata, version, err := c.Table(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	hash := dataFilesHash(dataFiles)
+	for _, snapshot := range metadata.Snapshots {
+		if snapshot.Summary[loadFilesSummaryKey] == hash {
+			return nil
+		}
+	}
+
+	snapshotID := rand.Int63()
+	var parentSnapshotID *int64
+	var previousManifestList []byte
+	if parent := metadata.CurrentSnapshot(); parent != nil {
+		parentSnapshotID = &parent.SnapshotID
+		if previousManifestList, err = c.store.Read(ctx, c.pathOf(tableName, parent.ManifestList)); err != nil {
+			return fmt.Errorf("reading manifest list of snapshot %d: %w", parent.SnapshotID, err)
+		}
+	}
+
+	commitUUID := uuid.Must(uuid.NewV4()).String()
+	manifest, err := writeManifest(metadata.Schema, snapshotID, dataFiles)
+	if err != nil {
+		return err
+	}
+	manifestPath := c.metadataPath(tableName, fmt.Sprintf("%s-m0.avro", commitUUID))
+	if err := c.store.Write(ctx, manifestPath, manifest); err != nil {
+		return err
+	}
+
+	var rowsCount int64
+	for _, dataFile := range dataFiles {
+		rowsCount += dataFile.RecordCount
+	}
+	manifestList, err := writeManifestList(snapshotID, parentSnapshotID, previousManifestList, manifestFile{
+		path:           c.store.URI(manifestPath),
+		length:         int64(len(manifest)),
+		snapshotID:     snapshotID,
+		dataFilesCount: len(dataFiles),
+		rowsCount:      rowsCount,
+	})
+	if err != nil {
+		return err
+	}
+	manifestListPath := c.metadataPath(tableName, fmt.Sprintf("snap-%d-1-%s.avro", snapshotID, commitUUID))
+	if err := c.store.Write(ctx, manifestListPath, manifestList); err != nil {
+		return err
+	}
+
+	metadata.addSnapshot(Snapshot{
+		SnapshotID:       snapshotID,
+		ParentSnapshotID: parentSnapshotID,
+		TimestampMs:      c.now().UnixMilli(),
+		ManifestList:     c.store.URI(manifestListPath),
+		Summary: map[string]string{
+			"operation":         "append",
+			"added-data-files":  strconv.Itoa(len(dataFiles)),
+			"added-records":     strconv.FormatInt(rowsCount, 10),
+			loadFilesSummaryKey: hash,
+		},
+		SchemaID: metadata.CurrentSchemaID,
+	})
+	return c.commit(ctx, tableName, version, metadata)
+}
+
+// pathOf returns the path in the object storage of a file of a table from its location
+func (c *Catalog) pathOf(tableName, location string) string {
+	return path.Join(c.tablePath(tableName), strings.TrimPrefix(location, c.store.URI(c.tablePath(tableName))))
+}
+
+// commit creates the metadata file of the version following version of the table, and then points the version hint of
+// the table to it, unless the table has been committed by someone else since version was read
+func (c *Catalog) commit(ctx context.Context, tableName string, version int, metadata *TableMetadata) error {
+	currentVersion, err := c.currentVersion(ctx, tableName)
+	if errors.Is(err, ErrNotFound) {
+		currentVersion, err = 0, nil
+	}
+	if err != nil {
+		return err
+	}
+	if currentVersion != version {
+		return fmt.Errorf("committing version %d of table %s over version %d: %w", version+1, tableName, currentVersion, ErrConcurrentCommit)
+	}
+
+	if version > 0 {
+		metadata.MetadataLog = append(metadata.MetadataLog, MetadataLogEntry{
+			TimestampMs:  metadata.LastUpdatedMs,
+			MetadataFile: c.store.URI(c.metadataPath(tableName, metadataFileName(version))),
+		})
+	}
+	metadata.LastUpdatedMs = c.now().UnixMilli()
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	err = c.store.Create(ctx, c.metadataPath(tableName, metadataFileName(version+1)), data)
+	if errors.Is(err, ErrExists) {
+		return fmt.Errorf("committing version %d of table %s: %w", version+1, tableName, ErrConcurrentCommit)
+	}
+	if err != nil {
+		return err
+	}
+	return c.store.Write(ctx, c.metadataPath(tableName, versionHintFileName), []byte(strconv.Itoa(version+1)))
+}
+
+func metadataFileName(version int) string {
+	return fmt.Sprintf("v%d.metadata.json", version)
+}
+
+func dataFilesHash(dataFiles []DataFile) string {
+	paths := make([]string, len(dataFiles))
+	for i := range dataFiles {
+		paths[i] = dataFiles[i].Path
+	}
+	sort.Strings(paths)
+	return misc.GetMD5Hash(strings.Join(paths, ","))
+}
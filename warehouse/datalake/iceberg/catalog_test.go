@@ -0,0 +1,240 @@
+package iceberg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linkedin/goavro"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+type memoryStore struct {
+	files map[string][]byte
+}
+
+func (s *memoryStore) Read(_ context.Context, filePath string) ([]byte, error) {
+	data, ok := s.files[filePath]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", filePath, ErrNotFound)
+	}
+	return data, nil
+}
+
+func (s *memoryStore) Write(_ context.Context, filePath string, data []byte) error {
+	s.files[filePath] = data
+	return nil
+}
+
+func (s *memoryStore) Create(ctx context.Context, filePath string, data []byte) error {
+	if _, ok := s.files[filePath]; ok {
+		return fmt.Errorf("%s: %w", filePath, ErrExists)
+	}
+	return s.Write(ctx, filePath, data)
+}
+
+func (*memoryStore) URI(filePath string) string {
+	return "s3://bucket/" + filePath
+}
+
+func readManifestList(data []byte) []map[string]interface{} {
+	reader, err := goavro.NewOCFReader(bytes.NewReader(data))
+	Expect(err).To(BeNil())
+	var manifests []map[string]interface{}
+	for reader.Scan() {
+		manifest, err := reader.Read()
+		Expect(err).To(BeNil())
+		manifests = append(manifests, manifest.(map[string]interface{}))
+	}
+	Expect(reader.Err()).To(BeNil())
+	return manifests
+}
+
+var _ = Describe("Catalog", func() {
+	ctx := context.Background()
+	var (
+		store   *memoryStore
+		catalog *Catalog
+	)
+
+	BeforeEach(func() {
+		store = &memoryStore{files: map[string][]byte{}}
+		catalog = NewCatalog(store, "namespace")
+		catalog.now = func() time.Time { return time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+		Expect(catalog.CreateTable(ctx, "tracks", map[string]string{
+			"id":          "string",
+			"received_at": "datetime",
+			"count":       "int",
+		})).To(BeNil())
+	})
+
+	It("creates tables with their columns", func() {
+		Expect(catalog.Tables(ctx)).To(Equal([]string{"tracks"}))
+		Expect(store.files).To(HaveKey(catalog.metadataPath("tracks", "v1.metadata.json")))
+		Expect(string(store.files[catalog.metadataPath("tracks", versionHintFileName)])).To(Equal("1"))
+
+		metadata, version, err := catalog.Table(ctx, "tracks")
+		Expect(err).To(BeNil())
+		Expect(version).To(Equal(1))
+		Expect(metadata.Location).To(Equal("s3://bucket/rudder-datalake/namespace/tracks"))
+		Expect(metadata.Schema.Fields).To(Equal([]Field{
+			{ID: 1, Name: "count", Type: "long"},
+			{ID: 2, Name: "id", Type: "string"},
+			{ID: 3, Name: "received_at", Type: "timestamptz"},
+		}))
+		Expect(metadata.Columns()).To(Equal(map[string]string{
+			"id":          "string",
+			"received_at": "datetime",
+			"count":       "int",
+		}))
+		Expect(metadata.Properties[nameMappingProperty]).To(Equal(`[{"field-id":1,"names":["count"]},{"field-id":2,"names":["id"]},{"field-id":3,"names":["received_at"]}]`))
+
+		Expect(catalog.CreateTable(ctx, "tracks", map[string]string{"id": "string"})).To(BeNil())
+		_, version, err = catalog.Table(ctx, "tracks")
+		Expect(err).To(BeNil())
+		Expect(version).To(Equal(1))
+	})
+
+	It("returns ErrNotFound for missing tables", func() {
+		_, _, err := catalog.Table(ctx, "pages")
+		Expect(err).To(MatchError(ErrNotFound))
+	})
+
+	It("adds columns in a new version of the schema", func() {
+		Expect(catalog.EvolveSchema(ctx, "tracks", warehouseutils.TableSchemaDiffT{
+			Exists:    true,
+			ColumnMap: map[string]string{"price": "float"},
+		})).To(BeNil())
+
+		metadata, version, err := catalog.Table(ctx, "tracks")
+		Expect(err).To(BeNil())
+		Expect(version).To(Equal(2))
+		Expect(metadata.Schemas).To(HaveLen(2))
+		Expect(metadata.CurrentSchemaID).To(Equal(1))
+		Expect(metadata.LastColumnID).To(Equal(4))
+		Expect(metadata.Schema.Fields[3]).To(Equal(Field{ID: 4, Name: "price", Type: "double"}))
+		Expect(metadata.MetadataLog).To(Equal([]MetadataLogEntry{
+			{TimestampMs: metadata.LastUpdatedMs, MetadataFile: "s3://bucket/rudder-datalake/namespace/tracks/metadata/v1.metadata.json"},
+		}))
+	})
+
+	It("keeps the schema when columns are altered to text", func() {
+		Expect(catalog.EvolveSchema(ctx, "tracks", warehouseutils.TableSchemaDiffT{
+			Exists:                         true,
+			StringColumnsToBeAlteredToText: []string{"id"},
+		})).To(BeNil())
+
+		_, version, err := catalog.Table(ctx, "tracks")
+		Expect(err).To(BeNil())
+		Expect(version).To(Equal(1))
+	})
+
+	It("rejects changes of the type of columns", func() {
+		err := catalog.EvolveSchema(ctx, "tracks", warehouseutils.TableSchemaDiffT{
+			Exists:    true,
+			ColumnMap: map[string]string{"count": "boolean"},
+		})
+		Expect(err).To(MatchError(ContainSubstring("changing the type of column count from long to boolean")))
+	})
+
+	It("appends data files in snapshots", func() {
+		Expect(catalog.AppendFiles(ctx, "tracks", []DataFile{
+			{Path: "s3://bucket/rudder-datalake/namespace/tracks/1.parquet", Format: ParquetFileFormat, RecordCount: 10, SizeInBytes: 1000},
+			{Path: "s3://bucket/rudder-datalake/namespace/tracks/2.parquet", Format: ParquetFileFormat, RecordCount: 5, SizeInBytes: 500},
+		})).To(BeNil())
+
+		metadata, version, err := catalog.Table(ctx, "tracks")
+		Expect(err).To(BeNil())
+		Expect(version).To(Equal(2))
+		snapshot := metadata.CurrentSnapshot()
+		Expect(snapshot).NotTo(BeNil())
+		Expect(snapshot.ParentSnapshotID).To(BeNil())
+		Expect(snapshot.Summary).To(HaveKeyWithValue("added-records", "15"))
+
+		manifests := readManifestList(store.files[catalog.pathOf("tracks", snapshot.ManifestList)])
+		Expect(manifests).To(HaveLen(1))
+		manifestPath := manifests[0]["manifest_path"].(string)
+		Expect(manifests[0]["added_rows_count"]).To(Equal(map[string]interface{}{"long": int64(15)}))
+
+		manifest := store.files[catalog.pathOf("tracks", manifestPath)]
+		Expect(manifest).NotTo(BeEmpty())
+		Expect(manifests[0]["manifest_length"]).To(Equal(int64(len(manifest))))
+		Expect(bytes.HasPrefix(manifest, []byte("Obj\x01"))).To(BeTrue())
+		Expect(strings.Count(string(manifest), "rudder-datalake/namespace/tracks/")).To(Equal(2))
+
+		By("appending other data files")
+		Expect(catalog.AppendFiles(ctx, "tracks", []DataFile{
+			{Path: "s3://bucket/rudder-datalake/namespace/tracks/3.parquet", Format: ParquetFileFormat, RecordCount: 1, SizeInBytes: 100},
+		})).To(BeNil())
+		metadata, version, err = catalog.Table(ctx, "tracks")
+		Expect(err).To(BeNil())
+		Expect(version).To(Equal(3))
+		Expect(metadata.Snapshots).To(HaveLen(2))
+		Expect(*metadata.CurrentSnapshot().ParentSnapshotID).To(Equal(snapshot.SnapshotID))
+		Expect(readManifestList(store.files[catalog.pathOf("tracks", metadata.CurrentSnapshot().ManifestList)])).To(HaveLen(2))
+
+		By("appending the same data files again")
+		Expect(catalog.AppendFiles(ctx, "tracks", []DataFile{
+			{Path: "s3://bucket/rudder-datalake/namespace/tracks/2.parquet", Format: ParquetFileFormat, RecordCount: 5, SizeInBytes: 500},
+			{Path: "s3://bucket/rudder-datalake/namespace/tracks/1.parquet", Format: ParquetFileFormat, RecordCount: 10, SizeInBytes: 1000},
+		})).To(BeNil())
+		_, version, err = catalog.Table(ctx, "tracks")
+		Expect(err).To(BeNil())
+		Expect(version).To(Equal(3))
+	})
+
+	It("rejects commits over a newer version of the table", func() {
+		metadata, version, err := catalog.Table(ctx, "tracks")
+		Expect(err).To(BeNil())
+		Expect(catalog.EvolveSchema(ctx, "tracks", warehouseutils.TableSchemaDiffT{
+			Exists:    true,
+			ColumnMap: map[string]string{"price": "float"},
+		})).To(BeNil())
+
+		err = catalog.commit(ctx, "tracks", version, metadata)
+		Expect(err).To(MatchError(ErrConcurrentCommit))
+		Expect(string(store.files[catalog.metadataPath("tracks", versionHintFileName)])).To(Equal("2"))
+	})
+
+	It("rejects commits of a version created since the version hint was read", func() {
+		metadata, version, err := catalog.Table(ctx, "tracks")
+		Expect(err).To(BeNil())
+		store.files[catalog.metadataPath("tracks", "v2.metadata.json")] = []byte("{}")
+
+		err = catalog.commit(ctx, "tracks", version, metadata)
+		Expect(err).To(MatchError(ErrConcurrentCommit))
+		Expect(string(store.files[catalog.metadataPath("tracks", "v2.metadata.json")])).To(Equal("{}"))
+	})
+
+	It("finds the versions committed without updating the version hint", func() {
+		Expect(catalog.EvolveSchema(ctx, "tracks", warehouseutils.TableSchemaDiffT{
+			Exists:    true,
+			ColumnMap: map[string]string{"price": "float"},
+		})).To(BeNil())
+		store.files[catalog.metadataPath("tracks", versionHintFileName)] = []byte("1")
+
+		metadata, version, err := catalog.Table(ctx, "tracks")
+		Expect(err).To(BeNil())
+		Expect(version).To(Equal(2))
+		Expect(metadata.Columns()).To(HaveKey("price"))
+
+		delete(store.files, catalog.metadataPath("tracks", versionHintFileName))
+		_, version, err = catalog.Table(ctx, "tracks")
+		Expect(err).To(BeNil())
+		Expect(version).To(Equal(2))
+	})
+})
+
+var _ = DescribeTable("DataFilePath", func(provider, location, expected string) {
+	Expect(DataFilePath(provider, location)).To(Equal(expected))
+},
+	Entry("S3", "S3", "https://bucket.s3.amazonaws.com/rudder-datalake/namespace/tracks/1.parquet", "s3://bucket/rudder-datalake/namespace/tracks/1.parquet"),
+	Entry("GCS", "GCS", "https://storage.googleapis.com/bucket/rudder-datalake/namespace/tracks/1.parquet", "gs://bucket/rudder-datalake/namespace/tracks/1.parquet"),
+	Entry("AZURE_BLOB", "AZURE_BLOB", "https://account.blob.core.windows.net/container/rudder-datalake/namespace/tracks/1.parquet", "wasbs://container@account.blob.core.windows.net/rudder-datalake/namespace/tracks/1.parquet"),
+)
@@ -0,0 +1,223 @@
+package iceberg
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/linkedin/goavro"
+)
+
+const (
+	manifestEntryStatusAdded = 1
+	// defaultBlockSize is the block_size_in_bytes of the data files, required by format version 1 and ignored by readers
+	defaultBlockSize = 64 * 1024 * 1024
+)
+
+// manifestEntrySchema is the avro schema of the entries of a manifest file of an unpartitioned table (format version 1)
+const manifestEntrySchema = `{
+	"type": "record",
+	"name": "manifest_entry",
+	"fields": [
+		{"name": "status", "type": "int", "field-id": 0},
+		{"name": "snapshot_id", "type": "long", "field-id": 1},
+		{"name": "data_file", "field-id": 2, "type": {
+			"type": "record",
+			"name": "r2",
+			"fields": [
+				{"name": "file_path", "type": "string", "field-id": 100},
+				{"name": "file_format", "type": "string", "field-id": 101},
+				{"name": "partition", "type": {"type": "record", "name": "r102", "fields": []}, "field-id": 102},
+				{"name": "record_count", "type": "long", "field-id": 103},
+				{"name": "file_size_in_bytes", "type": "long", "field-id": 104},
+				{"name": "block_size_in_bytes", "type": "long", "field-id": 105}
+			]
+		}}
+	]
+}`
+
+// manifestEntryEncodingSchema is manifestEntrySchema without the partition of the data files: goavro rejects records
+// without fields, but the partition of an unpartitioned table is an empty record, which is encoded with no bytes at all,
+// so the entries encoded with this schema are valid entries of manifestEntrySchema
+const manifestEntryEncodingSchema = `{
+	"type": "record",
+	"name": "manifest_entry",
+	"fields": [
+		{"name": "status", "type": "int"},
+		{"name": "snapshot_id", "type": "long"},
+		{"name": "data_file", "type": {
+			"type": "record",
+			"name": "r2",
+			"fields": [
+				{"name": "file_path", "type": "string"},
+				{"name": "file_format", "type": "string"},
+				{"name": "record_count", "type": "long"},
+				{"name": "file_size_in_bytes", "type": "long"},
+				{"name": "block_size_in_bytes", "type": "long"}
+			]
+		}}
+	]
+}`
+
+// manifestFileSchema is the avro schema of the manifest files listed in a manifest list (format version 1)
+const manifestFileSchema = `{
+	"type": "record",
+	"name": "manifest_file",
+	"fields": [
+		{"name": "manifest_path", "type": "string", "field-id": 500},
+		{"name": "manifest_length", "type": "long", "field-id": 501},
+		{"name": "partition_spec_id", "type": "int", "field-id": 502},
+		{"name": "added_snapshot_id", "type": ["null", "long"], "default": null, "field-id": 503},
+		{"name": "added_data_files_count", "type": ["null", "int"], "default": null, "field-id": 504},
+		{"name": "existing_data_files_count", "type": ["null", "int"], "default": null, "field-id": 505},
+		{"name": "deleted_data_files_count", "type": ["null", "int"], "default": null, "field-id": 506},
+		{"name": "added_rows_count", "type": ["null", "long"], "default": null, "field-id": 512},
+		{"name": "existing_rows_count", "type": ["null", "long"], "default": null, "field-id": 513},
+		{"name": "deleted_rows_count", "type": ["null", "long"], "default": null, "field-id": 514}
+	]
+}`
+
+var (
+	manifestEntryCodec = mustCodec(manifestEntryEncodingSchema)
+	metadataCodec      = mustCodec(`{"type": "map", "values": "bytes"}`)
+	longCodec          = mustCodec(`"long"`)
+)
+
+func mustCodec(schema string) *goavro.Codec {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		panic(err)
+	}
+	return codec
+}
+
+// the formats of the data files
+const (
+	ParquetFileFormat = "PARQUET"
+	OrcFileFormat     = "ORC"
+)
+
+// DataFile is a data file appended to a table, in one of the formats of the data files
+type DataFile struct {
+	Path        string
+	Format      string
+	RecordCount int64
+	SizeInBytes int64
+}
+
+// writeManifest returns a manifest file (an avro object container file) adding the data files in the snapshot
+func writeManifest(schema Schema, snapshotID int64, dataFiles []DataFile) ([]byte, error) {
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var block []byte
+	for _, dataFile := range dataFiles {
+		block, err = manifestEntryCodec.BinaryFromNative(block, map[string]interface{}{
+			"status":      manifestEntryStatusAdded,
+			"snapshot_id": snapshotID,
+			"data_file": map[string]interface{}{
+				"file_path":           dataFile.Path,
+				"file_format":         dataFile.Format,
+				"record_count":        dataFile.RecordCount,
+				"file_size_in_bytes":  dataFile.SizeInBytes,
+				"block_size_in_bytes": int64(defaultBlockSize),
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("encoding manifest entry of %s: %w", dataFile.Path, err)
+		}
+	}
+
+	// header of the object container file, see https://avro.apache.org/docs/1.11.1/specification/#object-container-files
+	buf := bytes.NewBufferString("Obj\x01")
+	header, err := metadataCodec.BinaryFromNative(nil, map[string]interface{}{
+		"avro.schema":       []byte(manifestEntrySchema),
+		"avro.codec":        []byte("null"),
+		"schema":            schemaJSON,
+		"schema-id":         []byte(strconv.Itoa(schema.SchemaID)),
+		"partition-spec":    []byte("[]"),
+		"partition-spec-id": []byte("0"),
+		"format-version":    []byte(strconv.Itoa(formatVersion)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(header)
+	sync := make([]byte, 16)
+	if _, err := rand.Read(sync); err != nil {
+		return nil, err
+	}
+	buf.Write(sync)
+
+	if len(dataFiles) > 0 {
+		blockHeader, _ := longCodec.BinaryFromNative(nil, int64(len(dataFiles)))
+		blockHeader, _ = longCodec.BinaryFromNative(blockHeader, int64(len(block)))
+		buf.Write(blockHeader)
+		buf.Write(block)
+		buf.Write(sync)
+	}
+	return buf.Bytes(), nil
+}
+
+// manifestFile is the entry of a manifest file in a manifest list
+type manifestFile struct {
+	path           string
+	length         int64
+	snapshotID     int64
+	dataFilesCount int
+	rowsCount      int64
+}
+
+// writeManifestList returns a manifest list listing the manifests of previousManifestList followed by manifest
+func writeManifestList(snapshotID int64, parentSnapshotID *int64, previousManifestList []byte, manifest manifestFile) ([]byte, error) {
+	metadata := map[string][]byte{
+		"snapshot-id":    []byte(strconv.FormatInt(snapshotID, 10)),
+		"format-version": []byte(strconv.Itoa(formatVersion)),
+	}
+	if parentSnapshotID != nil {
+		metadata["parent-snapshot-id"] = []byte(strconv.FormatInt(*parentSnapshotID, 10))
+	}
+
+	var manifests []interface{}
+	if len(previousManifestList) > 0 {
+		reader, err := goavro.NewOCFReader(bytes.NewReader(previousManifestList))
+		if err != nil {
+			return nil, fmt.Errorf("reading previous manifest list: %w", err)
+		}
+		for reader.Scan() {
+			previous, err := reader.Read()
+			if err != nil {
+				return nil, fmt.Errorf("reading previous manifest list: %w", err)
+			}
+			manifests = append(manifests, previous)
+		}
+		if err := reader.Err(); err != nil {
+			return nil, fmt.Errorf("reading previous manifest list: %w", err)
+		}
+	}
+	manifests = append(manifests, map[string]interface{}{
+		"manifest_path":             manifest.path,
+		"manifest_length":           manifest.length,
+		"partition_spec_id":         0,
+		"added_snapshot_id":         goavro.Union("long", manifest.snapshotID),
+		"added_data_files_count":    goavro.Union("int", int32(manifest.dataFilesCount)),
+		"existing_data_files_count": goavro.Union("int", int32(0)),
+		"deleted_data_files_count":  goavro.Union("int", int32(0)),
+		"added_rows_count":          goavro.Union("long", manifest.rowsCount),
+		"existing_rows_count":       goavro.Union("long", int64(0)),
+		"deleted_rows_count":        goavro.Union("long", int64(0)),
+	})
+
+	var buf bytes.Buffer
+	writer, err := goavro.NewOCFWriter(goavro.OCFConfig{W: &buf, Schema: manifestFileSchema, MetaData: metadata})
+	if err != nil {
+		return nil, err
+	}
+	if err := writer.Append(manifests); err != nil {
+		return nil, fmt.Errorf("writing manifest list: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,254 @@
+package iceberg
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+const formatVersion = 1
+
+// lastPartitionID is the last partition field id of a v1 table without partition fields
+const lastPartitionID = 999
+
+var dataTypesMap = map[string]string{
+	"boolean":  "boolean",
+	"int":      "long",
+	"bigint":   "long",
+	"float":    "double",
+	"string":   "string",
+	"text":     "string",
+	"json":     "string",
+	"datetime": "timestamptz",
+}
+
+var dataTypesMapToRudder = map[string]string{
+	"boolean":     "boolean",
+	"int":         "int",
+	"long":        "int",
+	"float":       "float",
+	"double":      "float",
+	"string":      "string",
+	"timestamptz": "datetime",
+	"timestamp":   "datetime",
+}
+
+const nameMappingProperty = "schema.name-mapping.default"
+
+type nameMappingT struct {
+	FieldID int      `json:"field-id"`
+	Names   []string `json:"names"`
+}
+
+// Field is a column of an iceberg schema
+type Field struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	Type     string `json:"type"`
+}
+
+// Schema is a version of the schema of an iceberg table
+type Schema struct {
+	Type     string  `json:"type"`
+	SchemaID int     `json:"schema-id"`
+	Fields   []Field `json:"fields"`
+}
+
+type PartitionSpec struct {
+	SpecID int           `json:"spec-id"`
+	Fields []interface{} `json:"fields"`
+}
+
+type SortOrder struct {
+	OrderID int           `json:"order-id"`
+	Fields  []interface{} `json:"fields"`
+}
+
+type Snapshot struct {
+	SnapshotID       int64             `json:"snapshot-id"`
+	ParentSnapshotID *int64            `json:"parent-snapshot-id,omitempty"`
+	TimestampMs      int64             `json:"timestamp-ms"`
+	ManifestList     string            `json:"manifest-list"`
+	Summary          map[string]string `json:"summary"`
+	SchemaID         int               `json:"schema-id"`
+}
+
+type SnapshotLogEntry struct {
+	TimestampMs int64 `json:"timestamp-ms"`
+	SnapshotID  int64 `json:"snapshot-id"`
+}
+
+type MetadataLogEntry struct {
+	TimestampMs  int64  `json:"timestamp-ms"`
+	MetadataFile string `json:"metadata-file"`
+}
+
+// TableMetadata is the metadata file of an iceberg table (format version 1), see https://iceberg.apache.org/spec/#table-metadata
+type TableMetadata struct {
+	FormatVersion      int                `json:"format-version"`
+	TableUUID          string             `json:"table-uuid"`
+	Location           string             `json:"location"`
+	LastUpdatedMs      int64              `json:"last-updated-ms"`
+	LastColumnID       int                `json:"last-column-id"`
+	Schema             Schema             `json:"schema"`
+	Schemas            []Schema           `json:"schemas"`
+	CurrentSchemaID    int                `json:"current-schema-id"`
+	PartitionSpec      []interface{}      `json:"partition-spec"`
+	PartitionSpecs     []PartitionSpec    `json:"partition-specs"`
+	DefaultSpecID      int                `json:"default-spec-id"`
+	LastPartitionID    int                `json:"last-partition-id"`
+	Properties         map[string]string  `json:"properties"`
+	CurrentSnapshotID  int64              `json:"current-snapshot-id"`
+	Snapshots          []Snapshot         `json:"snapshots"`
+	SnapshotLog        []SnapshotLogEntry `json:"snapshot-log"`
+	MetadataLog        []MetadataLogEntry `json:"metadata-log"`
+	SortOrders         []SortOrder        `json:"sort-orders"`
+	DefaultSortOrderID int                `json:"default-sort-order-id"`
+}
+
+// NewTableMetadata returns the metadata of a new unpartitioned table at location with the columns of columnMap
+func NewTableMetadata(location string, columnMap map[string]string, now time.Time) (*TableMetadata, error) {
+	metadata := &TableMetadata{
+		FormatVersion:     formatVersion,
+		TableUUID:         uuid.Must(uuid.NewV4()).String(),
+		Location:          location,
+		LastUpdatedMs:     now.UnixMilli(),
+		Schemas:           []Schema{},
+		PartitionSpec:     []interface{}{},
+		PartitionSpecs:    []PartitionSpec{{SpecID: 0, Fields: []interface{}{}}},
+		LastPartitionID:   lastPartitionID,
+		Properties:        map[string]string{},
+		CurrentSnapshotID: -1,
+		Snapshots:         []Snapshot{},
+		SnapshotLog:       []SnapshotLogEntry{},
+		MetadataLog:       []MetadataLogEntry{},
+		SortOrders:        []SortOrder{{OrderID: 0, Fields: []interface{}{}}},
+	}
+	schema := Schema{Type: "struct", SchemaID: 0, Fields: []Field{}}
+	for _, columnName := range sortedColumnNames(columnMap) {
+		fieldType, err := fieldType(columnMap[columnName])
+		if err != nil {
+			return nil, err
+		}
+		metadata.LastColumnID++
+		schema.Fields = append(schema.Fields, Field{ID: metadata.LastColumnID, Name: columnName, Type: fieldType})
+	}
+	metadata.setCurrentSchema(schema)
+	return metadata, nil
+}
+
+func (metadata *TableMetadata) setCurrentSchema(schema Schema) {
+	metadata.Schemas = append(metadata.Schemas, schema)
+	metadata.Schema = schema
+	metadata.CurrentSchemaID = schema.SchemaID
+
+	// the parquet and orc load files have no field ids, so readers map their columns to the fields of the schema by name
+	nameMapping := make([]nameMappingT, len(schema.Fields))
+	for i, field := range schema.Fields {
+		nameMapping[i] = nameMappingT{FieldID: field.ID, Names: []string{field.Name}}
+	}
+	nameMappingJSON, _ := json.Marshal(nameMapping)
+	if metadata.Properties == nil {
+		metadata.Properties = map[string]string{}
+	}
+	metadata.Properties[nameMappingProperty] = string(nameMappingJSON)
+}
+
+// Columns returns the rudder data types of the columns of the current schema of the table
+func (metadata *TableMetadata) Columns() map[string]string {
+	columns := make(map[string]string, len(metadata.Schema.Fields))
+	for _, field := range metadata.Schema.Fields {
+		if dataType, ok := dataTypesMapToRudder[field.Type]; ok {
+			columns[field.Name] = dataType
+		}
+	}
+	return columns
+}
+
+/*
+EvolveSchema adds a new version of the schema of the table with the changes of diff, and returns whether the schema changed.
+
+New columns get new field ids, so that the data files written before the change keep being read with the schema they
+were written with. Columns altered from string to text are both iceberg strings, so they don't change the schema.
+Any other change of the type of a column can't be read back from the existing data files, and is rejected.
+*/
+func (metadata *TableMetadata) EvolveSchema(diff warehouseutils.TableSchemaDiffT, now time.Time) (bool, error) {
+	current := make(map[string]Field, len(metadata.Schema.Fields))
+	for _, field := range metadata.Schema.Fields {
+		current[field.Name] = field
+	}
+
+	schema := Schema{Type: "struct", Fields: append([]Field{}, metadata.Schema.Fields...)}
+	lastColumnID := metadata.LastColumnID
+	for _, columnName := range sortedColumnNames(diff.ColumnMap) {
+		fieldType, err := fieldType(diff.ColumnMap[columnName])
+		if err != nil {
+			return false, err
+		}
+		if field, ok := current[columnName]; ok {
+			if field.Type != fieldType {
+				return false, fmt.Errorf("changing the type of column %s from %s to %s is not supported", columnName, field.Type, fieldType)
+			}
+			continue
+		}
+		lastColumnID++
+		schema.Fields = append(schema.Fields, Field{ID: lastColumnID, Name: columnName, Type: fieldType})
+	}
+	for _, columnName := range diff.StringColumnsToBeAlteredToText {
+		if _, ok := current[columnName]; !ok {
+			return false, fmt.Errorf("column %s to be altered does not exist", columnName)
+		}
+	}
+	if lastColumnID == metadata.LastColumnID {
+		return false, nil
+	}
+
+	for _, s := range metadata.Schemas {
+		if s.SchemaID >= schema.SchemaID {
+			schema.SchemaID = s.SchemaID + 1
+		}
+	}
+	metadata.LastColumnID = lastColumnID
+	metadata.LastUpdatedMs = now.UnixMilli()
+	metadata.setCurrentSchema(schema)
+	return true, nil
+}
+
+// CurrentSnapshot returns the current snapshot of the table, or nil if the table has no snapshot yet
+func (metadata *TableMetadata) CurrentSnapshot() *Snapshot {
+	for i := range metadata.Snapshots {
+		if metadata.Snapshots[i].SnapshotID == metadata.CurrentSnapshotID {
+			return &metadata.Snapshots[i]
+		}
+	}
+	return nil
+}
+
+func (metadata *TableMetadata) addSnapshot(snapshot Snapshot) {
+	metadata.Snapshots = append(metadata.Snapshots, snapshot)
+	metadata.SnapshotLog = append(metadata.SnapshotLog, SnapshotLogEntry{TimestampMs: snapshot.TimestampMs, SnapshotID: snapshot.SnapshotID})
+	metadata.CurrentSnapshotID = snapshot.SnapshotID
+	metadata.LastUpdatedMs = snapshot.TimestampMs
+}
+
+func fieldType(dataType string) (string, error) {
+	fieldType, ok := dataTypesMap[dataType]
+	if !ok {
+		return "", fmt.Errorf("unsupported data type %s for iceberg", dataType)
+	}
+	return fieldType, nil
+}
+
+func sortedColumnNames(columnMap map[string]string) []string {
+	columnNames := make([]string, 0, len(columnMap))
+	for columnName := range columnMap {
+		columnNames = append(columnNames, columnName)
+	}
+	sort.Strings(columnNames)
+	return columnNames
+}
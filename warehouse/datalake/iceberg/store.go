@@ -0,0 +1,163 @@
+package iceberg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/rudderlabs/rudder-server/services/filemanager"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+var (
+	// ErrNotFound is returned when reading a file which doesn't exist
+	ErrNotFound = errors.New("iceberg file not found")
+	// ErrExists is returned when creating a file which already exists
+	ErrExists = errors.New("iceberg file already exists")
+)
+
+// ObjectStore stores the metadata files of the iceberg tables. Paths are relative to the configured prefix of the storage.
+type ObjectStore interface {
+	Read(ctx context.Context, filePath string) ([]byte, error)
+	Write(ctx context.Context, filePath string, data []byte) error
+	// Create writes a file only if it doesn't exist yet, or returns ErrExists
+	Create(ctx context.Context, filePath string, data []byte) error
+	// URI returns the location of a file, as written in the iceberg metadata
+	URI(filePath string) string
+}
+
+type fileManagerStore struct {
+	settings *filemanager.SettingsT
+	fm       filemanager.FileManager
+	provider string
+	config   map[string]interface{}
+}
+
+// NewObjectStore returns an object store on the object storage of provider configured with config
+func NewObjectStore(provider string, config map[string]interface{}) (ObjectStore, error) {
+	settings := &filemanager.SettingsT{
+		Provider: provider,
+		Config:   config,
+	}
+	fm, err := filemanager.DefaultFileManagerFactory.New(settings)
+	if err != nil {
+		return nil, err
+	}
+	return &fileManagerStore{settings: settings, fm: fm, provider: provider, config: config}, nil
+}
+
+func (s *fileManagerStore) key(filePath string) string {
+	return path.Join(s.fm.GetConfiguredPrefix(), filePath)
+}
+
+func (s *fileManagerStore) Read(ctx context.Context, filePath string) ([]byte, error) {
+	key := s.key(filePath)
+	// downloading a missing object fails with an error specific to each storage, so the object is looked up first,
+	// with a new file manager as listing with a file manager continues its previous listing
+	lister, err := filemanager.DefaultFileManagerFactory.New(s.settings)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := lister.ListFilesWithPrefix(ctx, "", key, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 || objects[0].Key != key {
+		return nil, fmt.Errorf("%s: %w", key, ErrNotFound)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "iceberg")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+	file, err := os.Create(filepath.Join(tmpDir, path.Base(key)))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if err := s.fm.Download(ctx, file, key); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(file.Name())
+}
+
+func (s *fileManagerStore) Write(ctx context.Context, filePath string, data []byte) error {
+	return s.upload(ctx, filePath, data, s.fm.Upload)
+}
+
+// Create uploads the file with the conditional upload of the object storage, so that only one of concurrent writers
+// of a file succeeds. The object storages without conditional uploads (MINIO, DIGITAL_OCEAN_SPACES) only check that
+// the file doesn't exist before writing it.
+func (s *fileManagerStore) Create(ctx context.Context, filePath string, data []byte) error {
+	conditionalUploader, ok := s.fm.(filemanager.ConditionalUploader)
+	if !ok {
+		if _, err := s.Read(ctx, filePath); err == nil {
+			return fmt.Errorf("%s: %w", s.key(filePath), ErrExists)
+		} else if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		return s.Write(ctx, filePath, data)
+	}
+	err := s.upload(ctx, filePath, data, conditionalUploader.UploadIfNotExists)
+	if errors.Is(err, filemanager.ErrObjectExists) {
+		return fmt.Errorf("%s: %w", s.key(filePath), ErrExists)
+	}
+	return err
+}
+
+func (*fileManagerStore) upload(ctx context.Context, filePath string, data []byte, upload func(context.Context, *os.File, ...string) (filemanager.UploadOutput, error)) error {
+	tmpDir, err := os.MkdirTemp("", "iceberg")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	fileName := filepath.Join(tmpDir, path.Base(filePath))
+	if err := os.WriteFile(fileName, data, 0o644); err != nil {
+		return err
+	}
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = upload(ctx, file, path.Dir(filePath))
+	return err
+}
+
+func (s *fileManagerStore) URI(filePath string) string {
+	key := s.key(filePath)
+	switch s.provider {
+	case "GCS":
+		return fmt.Sprintf("gs://%s/%s", s.config["bucketName"], key)
+	case "AZURE_BLOB":
+		return fmt.Sprintf("wasbs://%s@%s.blob.core.windows.net/%s", s.config["containerName"], s.config["accountName"], key)
+	default:
+		return fmt.Sprintf("s3://%s/%s", s.config["bucketName"], key)
+	}
+}
+
+// DataFilePath returns the location of a load file in the iceberg metadata from its location in the object storage
+func DataFilePath(provider, location string) string {
+	switch provider {
+	case "S3":
+		s3Location, _ := warehouseutils.GetS3Location(location)
+		return s3Location
+	case "GCS":
+		return warehouseutils.GetGCSLocation(location, warehouseutils.GCSLocationOptionsT{TLDFormat: "gs"})
+	case "AZURE_BLOB":
+		blobURL, err := url.Parse(location)
+		if err != nil {
+			return location
+		}
+		accountName := strings.TrimSuffix(blobURL.Host, ".blob.core.windows.net")
+		containerName, blobName, _ := strings.Cut(strings.TrimPrefix(blobURL.Path, "/"), "/")
+		return fmt.Sprintf("wasbs://%s@%s.blob.core.windows.net/%s", containerName, accountName, blobName)
+	}
+	return location
+}
@@ -25,6 +25,13 @@ var (
 	glueSerdeSerializationLib = "org.apache.hadoop.hive.ql.io.parquet.serde.ParquetHiveSerDe"
 	glueParquetInputFormat    = "org.apache.hadoop.hive.ql.io.parquet.MapredParquetInputFormat"
 	glueParquetOutputFormat   = "org.apache.hadoop.hive.ql.io.parquet.MapredParquetOutputFormat"
+	glueOrcSerdeName          = "OrcSerde"
+	glueOrcSerializationLib   = "org.apache.hadoop.hive.ql.io.orc.OrcSerde"
+	glueOrcInputFormat        = "org.apache.hadoop.hive.ql.io.orc.OrcInputFormat"
+	glueOrcOutputFormat       = "org.apache.hadoop.hive.ql.io.orc.OrcOutputFormat"
+	// the columns of the orc load files are in the order of their names, unlike the columns of the glue tables, so
+	// they are read by name
+	glueOrcSerdeParameters = map[string]*string{"orc.column.index.access": aws.String("false")}
 )
 
 type GlueSchemaRepository struct {
@@ -33,14 +40,16 @@ type GlueSchemaRepository struct {
 	s3prefix   string
 	Warehouse  warehouseutils.WarehouseT
 	Namespace  string
+	uploader   warehouseutils.UploaderI
 }
 
-func NewGlueSchemaRepository(wh warehouseutils.WarehouseT) (*GlueSchemaRepository, error) {
+func NewGlueSchemaRepository(wh warehouseutils.WarehouseT, uploader warehouseutils.UploaderI) (*GlueSchemaRepository, error) {
 	gl := GlueSchemaRepository{
 		s3bucket:  warehouseutils.GetConfigValue(AWSBucketNameConfig, wh),
 		s3prefix:  warehouseutils.GetConfigValue(AWSS3Prefix, wh),
 		Warehouse: wh,
 		Namespace: wh.Namespace,
+		uploader:  uploader,
 	}
 
 	glueClient, err := getGlueClient(wh)
@@ -137,6 +146,11 @@ func (gl *GlueSchemaRepository) CreateTable(tableName string, columnMap map[stri
 }
 
 func (gl *GlueSchemaRepository) AddColumn(tableName, columnName, columnType string) (err error) {
+	// the storage descriptor of the table is replaced, along with its serde
+	if err = gl.checkLoadFileType(tableName); err != nil {
+		return err
+	}
+
 	updateTableInput := glue.UpdateTableInput{
 		DatabaseName: aws.String(gl.Namespace),
 		TableInput: &glue.TableInput{
@@ -171,6 +185,49 @@ func (gl *GlueSchemaRepository) AlterColumn(tableName, columnName, columnType st
 	return gl.AddColumn(tableName, columnName, columnType)
 }
 
+// LoadTable doesn't load anything, the load files are already in the location of the table, but fails the uploads
+// whose load files aren't of the format of the table
+func (gl *GlueSchemaRepository) LoadTable(tableName string) error {
+	return gl.checkLoadFileType(tableName)
+}
+
+// checkLoadFileType returns an error if the table exists with the serde of a load file type other than the one of
+// the upload. The serde applies to all the files in the location of the table, so the load file type of the tables
+// of a destination can't be changed.
+func (gl *GlueSchemaRepository) checkLoadFileType(tableName string) error {
+	output, err := gl.glueClient.GetTable(&glue.GetTableInput{
+		DatabaseName: aws.String(gl.Namespace),
+		Name:         aws.String(tableName),
+	})
+	if err != nil {
+		if _, ok := err.(*glue.EntityNotFoundException); ok {
+			return nil
+		}
+		return err
+	}
+	return checkTableLoadFileType(output.Table, gl.uploader.GetLoadFileType())
+}
+
+func checkTableLoadFileType(table *glue.TableData, loadFileType string) error {
+	if table == nil || table.StorageDescriptor == nil || table.StorageDescriptor.SerdeInfo == nil || table.StorageDescriptor.SerdeInfo.SerializationLibrary == nil {
+		return nil
+	}
+	var tableLoadFileType string
+	switch *table.StorageDescriptor.SerdeInfo.SerializationLibrary {
+	case glueSerdeSerializationLib:
+		tableLoadFileType = warehouseutils.LOAD_FILE_TYPE_PARQUET
+	case glueOrcSerializationLib:
+		tableLoadFileType = warehouseutils.LOAD_FILE_TYPE_ORC
+	default:
+		// serde not set by us
+		return nil
+	}
+	if tableLoadFileType != loadFileType {
+		return fmt.Errorf("table %s has %s files, which can't be mixed with the %s load files of the upload: create the tables in another namespace to change the load file type", aws.StringValue(table.Name), tableLoadFileType, loadFileType)
+	}
+	return nil
+}
+
 func getGlueClient(wh warehouseutils.WarehouseT) (*glue.Glue, error) {
 	var accessKey, accessKeyID string
 
@@ -212,6 +269,16 @@ func (gl *GlueSchemaRepository) getStorageDescriptor(tableName string, columnMap
 		OutputFormat: aws.String(glueParquetOutputFormat),
 	}
 
+	if gl.uploader.GetLoadFileType() == warehouseutils.LOAD_FILE_TYPE_ORC {
+		storageDescriptor.SerdeInfo = &glue.SerDeInfo{
+			Name:                 aws.String(glueOrcSerdeName),
+			SerializationLibrary: aws.String(glueOrcSerializationLib),
+			Parameters:           glueOrcSerdeParameters,
+		}
+		storageDescriptor.InputFormat = aws.String(glueOrcInputFormat)
+		storageDescriptor.OutputFormat = aws.String(glueOrcOutputFormat)
+	}
+
 	// add columns to storage descriptor
 	for colName, colType := range columnMap {
 		storageDescriptor.Columns = append(storageDescriptor.Columns, &glue.Column{
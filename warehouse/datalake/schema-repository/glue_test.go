@@ -0,0 +1,36 @@
+package schemarepository
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+var _ = Describe("Glue", func() {
+	table := func(serializationLibrary string) *glue.TableData {
+		return &glue.TableData{
+			Name:              aws.String("tracks"),
+			StorageDescriptor: &glue.StorageDescriptor{SerdeInfo: &glue.SerDeInfo{SerializationLibrary: aws.String(serializationLibrary)}},
+		}
+	}
+
+	DescribeTable("load file type of existing tables",
+		func(table *glue.TableData, loadFileType, expectedErr string) {
+			err := checkTableLoadFileType(table, loadFileType)
+			if expectedErr == "" {
+				Expect(err).To(BeNil())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+			}
+		},
+		Entry("parquet table with parquet load files", table(glueSerdeSerializationLib), warehouseutils.LOAD_FILE_TYPE_PARQUET, ""),
+		Entry("orc table with orc load files", table(glueOrcSerializationLib), warehouseutils.LOAD_FILE_TYPE_ORC, ""),
+		Entry("parquet table with orc load files", table(glueSerdeSerializationLib), warehouseutils.LOAD_FILE_TYPE_ORC, "table tracks has parquet files"),
+		Entry("orc table with parquet load files", table(glueOrcSerializationLib), warehouseutils.LOAD_FILE_TYPE_PARQUET, "table tracks has orc files"),
+		Entry("table with another serde", table("org.apache.hadoop.hive.serde2.lazy.LazySimpleSerDe"), warehouseutils.LOAD_FILE_TYPE_ORC, ""),
+		Entry("table without storage descriptor", &glue.TableData{Name: aws.String("tracks")}, warehouseutils.LOAD_FILE_TYPE_ORC, ""),
+	)
+})
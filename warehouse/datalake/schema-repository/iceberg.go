@@ -0,0 +1,118 @@
+package schemarepository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/warehouse/datalake/iceberg"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+var UseIcebergConfig = "useIceberg"
+
+// TableLoader is implemented by the schema repositories which need the load files of the uploads to be committed or
+// checked
+type TableLoader interface {
+	LoadTable(tableName string) error
+}
+
+// IcebergSchemaRepository keeps the schema of the datalake tables in iceberg table metadata, next to the data files
+// in the object storage, and commits the load files of every upload of a table as a snapshot of the table. The
+// metadata is read and written within the context of the upload.
+type IcebergSchemaRepository struct {
+	ctx           context.Context
+	catalog       *iceberg.Catalog
+	objectStorage string
+	warehouse     warehouseutils.WarehouseT
+	uploader      warehouseutils.UploaderI
+}
+
+func NewIcebergSchemaRepository(ctx context.Context, wh warehouseutils.WarehouseT, uploader warehouseutils.UploaderI) (*IcebergSchemaRepository, error) {
+	objectStorage := warehouseutils.ObjectStorageType(wh.Destination.DestinationDefinition.Name, wh.Destination.Config, uploader.UseRudderStorage())
+	store, err := iceberg.NewObjectStore(objectStorage, misc.GetObjectStorageConfig(misc.ObjectStorageOptsT{
+		Provider:         objectStorage,
+		Config:           wh.Destination.Config,
+		UseRudderStorage: uploader.UseRudderStorage(),
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return &IcebergSchemaRepository{
+		ctx:           ctx,
+		catalog:       iceberg.NewCatalog(store, wh.Namespace),
+		objectStorage: objectStorage,
+		warehouse:     wh,
+		uploader:      uploader,
+	}, nil
+}
+
+func (ic *IcebergSchemaRepository) FetchSchema(_ warehouseutils.WarehouseT) (warehouseutils.SchemaT, error) {
+	schema := warehouseutils.SchemaT{}
+	tables, err := ic.catalog.Tables(ic.ctx)
+	if err != nil {
+		return schema, err
+	}
+	for _, tableName := range tables {
+		metadata, _, err := ic.catalog.Table(ic.ctx, tableName)
+		if errors.Is(err, iceberg.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return schema, err
+		}
+		schema[tableName] = metadata.Columns()
+	}
+	return schema, nil
+}
+
+func (*IcebergSchemaRepository) CreateSchema() (err error) {
+	return nil
+}
+
+func (ic *IcebergSchemaRepository) CreateTable(tableName string, columnMap map[string]string) (err error) {
+	return ic.catalog.CreateTable(ic.ctx, tableName, columnMap)
+}
+
+func (ic *IcebergSchemaRepository) AddColumn(tableName, columnName, columnType string) (err error) {
+	return ic.catalog.EvolveSchema(ic.ctx, tableName, warehouseutils.TableSchemaDiffT{
+		Exists:    true,
+		ColumnMap: map[string]string{columnName: columnType},
+	})
+}
+
+func (ic *IcebergSchemaRepository) AlterColumn(tableName, columnName, _ string) (err error) {
+	return ic.catalog.EvolveSchema(ic.ctx, tableName, warehouseutils.TableSchemaDiffT{
+		Exists:                         true,
+		StringColumnsToBeAlteredToText: []string{columnName},
+	})
+}
+
+// LoadTable commits the load files of the table in the upload as a snapshot of the table. The snapshot is committed
+// on its own, not atomically with the snapshots of the other tables of the upload.
+func (ic *IcebergSchemaRepository) LoadTable(tableName string) error {
+	fileFormat := iceberg.ParquetFileFormat
+	if ic.uploader.GetLoadFileType() == warehouseutils.LOAD_FILE_TYPE_ORC {
+		fileFormat = iceberg.OrcFileFormat
+	}
+	loadFiles := ic.uploader.GetLoadFilesMetadata(warehouseutils.GetLoadFilesOptionsT{Table: tableName})
+	dataFiles := make([]iceberg.DataFile, 0, len(loadFiles))
+	for _, loadFile := range loadFiles {
+		var metadata struct {
+			ContentLength int64 `json:"content_length"`
+			TotalRows     int64 `json:"total_rows"`
+		}
+		if err := json.Unmarshal(loadFile.Metadata, &metadata); err != nil {
+			return fmt.Errorf("parsing metadata of load file %s: %w", loadFile.Location, err)
+		}
+		dataFiles = append(dataFiles, iceberg.DataFile{
+			Path:        iceberg.DataFilePath(ic.objectStorage, loadFile.Location),
+			Format:      fileFormat,
+			RecordCount: metadata.TotalRows,
+			SizeInBytes: metadata.ContentLength,
+		})
+	}
+	return ic.catalog.AppendFiles(ic.ctx, tableName, dataFiles)
+}
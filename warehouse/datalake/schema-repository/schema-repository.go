@@ -1,6 +1,7 @@
 package schemarepository
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/rudderlabs/rudder-server/utils/logger"
@@ -44,9 +45,12 @@ type SchemaRepository interface {
 	AlterColumn(tableName, columnName, columnType string) (err error)
 }
 
-func NewSchemaRepository(wh warehouseutils.WarehouseT, uploader warehouseutils.UploaderI) (SchemaRepository, error) {
+func NewSchemaRepository(ctx context.Context, wh warehouseutils.WarehouseT, uploader warehouseutils.UploaderI) (SchemaRepository, error) {
+	if warehouseutils.GetConfigValueBoolString(UseIcebergConfig, wh) == "true" {
+		return NewIcebergSchemaRepository(ctx, wh, uploader)
+	}
 	if warehouseutils.GetConfigValueBoolString(UseGlueConfig, wh) == "true" && misc.HasAWSRegionInConfig(wh.Destination.Config) {
-		return NewGlueSchemaRepository(wh)
+		return NewGlueSchemaRepository(wh, uploader)
 	}
 	return NewLocalSchemaRepository(wh, uploader)
 }
@@ -0,0 +1,13 @@
+package schemarepository
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSchemaRepository(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Schema Repository Suite")
+}
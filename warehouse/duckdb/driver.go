@@ -0,0 +1,6 @@
+//go:build duckdb
+
+package duckdb
+
+// the duckdb driver needs cgo, so it is linked in only when building with the duckdb build tag (make build DUCKDB_ENABLED=TRUE)
+import _ "github.com/marcboeker/go-duckdb"
@@ -0,0 +1,785 @@
+package duckdb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/services/filemanager"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/warehouse/client"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+var (
+	pkgLogger                     logger.LoggerI
+	driverName                    string
+	skipComputingUserLatestTraits bool
+)
+
+const (
+	stagingTablePrefix = "rudder_staging_"
+	databasePath       = "databasePath"
+)
+
+var rudderDataTypesMapToDuckDB = map[string]string{
+	"int":      "BIGINT",
+	"float":    "DOUBLE",
+	"string":   "VARCHAR",
+	"datetime": "TIMESTAMPTZ",
+	"boolean":  "BOOLEAN",
+	"json":     "JSON",
+}
+
+var duckDBDataTypesMapToRudder = map[string]string{
+	"BIGINT":                   "int",
+	"INTEGER":                  "int",
+	"SMALLINT":                 "int",
+	"TINYINT":                  "int",
+	"HUGEINT":                  "int",
+	"DOUBLE":                   "float",
+	"FLOAT":                    "float",
+	"REAL":                     "float",
+	"DECIMAL":                  "float",
+	"VARCHAR":                  "string",
+	"TEXT":                     "string",
+	"TIMESTAMP WITH TIME ZONE": "datetime",
+	"TIMESTAMPTZ":              "datetime",
+	"TIMESTAMP":                "datetime",
+	"BOOLEAN":                  "boolean",
+	"JSON":                     "json",
+}
+
+var primaryKeyMap = map[string]string{
+	warehouseutils.UsersTable:      "id",
+	warehouseutils.IdentifiesTable: "id",
+	warehouseutils.DiscardsTable:   "row_id",
+}
+
+var partitionKeyMap = map[string]string{
+	warehouseutils.UsersTable:      "id",
+	warehouseutils.IdentifiesTable: "id",
+	warehouseutils.DiscardsTable:   "row_id, column_name, table_name",
+}
+
+/*
+HandleT loads the uploads of a warehouse into a DuckDB database file on the local disk, so that whole upload cycles
+can be run without a cloud warehouse. The load files (csv or parquet) are downloaded from the object storage of the
+destination and copied into the tables of the schema of the namespace.
+
+DuckDB is used through database/sql: the driver registered with the Warehouse.duckdb.driverName name (duckdb by default)
+is linked in by building with the duckdb build tag, e.g. make build DUCKDB_ENABLED=TRUE.
+
+The load files are downloaded and loaded within the context of the upload, from Setup until Cleanup.
+*/
+type HandleT struct {
+	ctx            context.Context
+	cancel         context.CancelFunc
+	Db             *sql.DB
+	Namespace      string
+	ObjectStorage  string
+	Warehouse      warehouseutils.WarehouseT
+	Uploader       warehouseutils.UploaderI
+	ConnectTimeout time.Duration
+}
+
+// inMemoryPath is the database path of an in-memory database
+const inMemoryPath = ":memory:"
+
+type CredentialsT struct {
+	Path string
+}
+
+func Connect(cred CredentialsT) (*sql.DB, error) {
+	if cred.Path == "" {
+		return nil, fmt.Errorf("duckdb connection error : database path is not set")
+	}
+	if !misc.ContainsString(sql.Drivers(), driverName) {
+		return nil, fmt.Errorf("duckdb connection error : driver %q is not registered, build with the duckdb build tag", driverName)
+	}
+	// go-duckdb parses the dsn as a url and opens an in-memory database for an empty one
+	dsn := cred.Path
+	if dsn == inMemoryPath {
+		dsn = ""
+	}
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("duckdb connection error : (%v)", err)
+	}
+	return db, nil
+}
+
+func Init() {
+	loadConfig()
+	pkgLogger = logger.NewLogger().Child("warehouse").Child("duckdb")
+}
+
+func loadConfig() {
+	config.RegisterStringConfigVariable("duckdb", &driverName, false, "Warehouse.duckdb.driverName")
+	config.RegisterBoolConfigVariable(false, &skipComputingUserLatestTraits, true, "Warehouse.duckdb.skipComputingUserLatestTraits")
+}
+
+func (dd *HandleT) getConnectionCredentials() CredentialsT {
+	return CredentialsT{
+		Path: warehouseutils.GetConfigValue(databasePath, dd.Warehouse),
+	}
+}
+
+func columnsWithDataTypes(columns map[string]string, prefix string) string {
+	var arr []string
+	for name, dataType := range columns {
+		arr = append(arr, fmt.Sprintf(`"%s%s" %s`, prefix, name, rudderDataTypesMapToDuckDB[dataType]))
+	}
+	sort.Strings(arr)
+	return strings.Join(arr, ",")
+}
+
+// copyStatement returns the statement copying the rows of a load file into the columns of a table
+func copyStatement(namespace, tableName string, columns []string, fileName, loadFileType string) string {
+	quotedColumnNames := warehouseutils.DoubleQuoteAndJoinByComma(columns)
+	if loadFileType == warehouseutils.LOAD_FILE_TYPE_PARQUET {
+		// parquet load files have the names of the columns, so columns are matched by name rather than by position
+		return fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[3]s) SELECT %[3]s FROM read_parquet('%[4]s')`, namespace, tableName, quotedColumnNames, escapeLiteral(fileName))
+	}
+	return fmt.Sprintf(`COPY "%[1]s"."%[2]s" (%[3]s) FROM '%[4]s' (FORMAT CSV, HEADER false, COMPRESSION gzip)`, namespace, tableName, quotedColumnNames, escapeLiteral(fileName))
+}
+
+func escapeLiteral(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
+
+func (*HandleT) IsEmpty(_ warehouseutils.WarehouseT) (empty bool, err error) {
+	return
+}
+
+func (dd *HandleT) downloader() (filemanager.FileManager, error) {
+	storageProvider := warehouseutils.ObjectStorageType(dd.Warehouse.Destination.DestinationDefinition.Name, dd.Warehouse.Destination.Config, dd.Uploader.UseRudderStorage())
+	return filemanager.DefaultFileManagerFactory.New(&filemanager.SettingsT{
+		Provider: storageProvider,
+		Config: misc.GetObjectStorageConfig(misc.ObjectStorageOptsT{
+			Provider:         storageProvider,
+			Config:           dd.Warehouse.Destination.Config,
+			UseRudderStorage: dd.Uploader.UseRudderStorage(),
+		}),
+	})
+}
+
+// downloadLoadFile downloads the load file at location in a tmp directory and returns its path
+func (dd *HandleT) downloadLoadFile(downloader filemanager.FileManager, location string) (string, error) {
+	objectName, err := warehouseutils.GetObjectName(location, dd.Warehouse.Destination.Config, dd.ObjectStorage)
+	if err != nil {
+		return "", fmt.Errorf("converting object location %s to object key: %w", location, err)
+	}
+	tmpDirPath, err := misc.CreateTMPDIR()
+	if err != nil {
+		return "", fmt.Errorf("creating tmp directory: %w", err)
+	}
+	objectPath := tmpDirPath + fmt.Sprintf(`/%s/`, misc.RudderWarehouseLoadUploadsTmp) + fmt.Sprintf(`%s_%s_%d/`, dd.Warehouse.Destination.DestinationDefinition.Name, dd.Warehouse.Destination.ID, time.Now().Unix()) + objectName
+	if err = os.MkdirAll(filepath.Dir(objectPath), os.ModePerm); err != nil {
+		return "", fmt.Errorf("making tmp directory: %w", err)
+	}
+	objectFile, err := os.Create(objectPath)
+	if err != nil {
+		return "", fmt.Errorf("creating file in tmp directory: %w", err)
+	}
+	err = downloader.Download(dd.ctx, objectFile, objectName)
+	if closeErr := objectFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		misc.RemoveFilePaths(objectPath)
+		return "", fmt.Errorf("downloading load file %s: %w", location, err)
+	}
+	return objectPath, nil
+}
+
+func (dd *HandleT) DownloadLoadFiles(tableName string) ([]string, error) {
+	objects := dd.Uploader.GetLoadFilesMetadata(warehouseutils.GetLoadFilesOptionsT{Table: tableName})
+	downloader, err := dd.downloader()
+	if err != nil {
+		pkgLogger.Errorf("DD: Error in setting up a downloader for destionationID : %s Error : %v", dd.Warehouse.Destination.ID, err)
+		return nil, err
+	}
+	var fileNames []string
+	for _, object := range objects {
+		fileName, err := dd.downloadLoadFile(downloader, object.Location)
+		if err != nil {
+			pkgLogger.Errorf("DD: Error in downloading load file for table:%s: %v", tableName, err)
+			misc.RemoveFilePaths(fileNames...)
+			return nil, err
+		}
+		fileNames = append(fileNames, fileName)
+	}
+	return fileNames, nil
+}
+
+func (dd *HandleT) stagingTableName(tableName string) string {
+	return misc.TruncateStr(fmt.Sprintf(`%s%s_%s`, stagingTablePrefix, tableName, strings.ReplaceAll(uuid.Must(uuid.NewV4()).String(), "-", "")), 63)
+}
+
+// copyLoadFiles creates a staging table like tableName and copies the load files of tableName into it
+func (dd *HandleT) copyLoadFiles(txn *sql.Tx, tableName string, columns []string) (stagingTableName string, err error) {
+	fileNames, err := dd.DownloadLoadFiles(tableName)
+	defer misc.RemoveFilePaths(fileNames...)
+	if err != nil {
+		return
+	}
+
+	stagingTableName = dd.stagingTableName(tableName)
+	sqlStatement := fmt.Sprintf(`CREATE TABLE "%[1]s"."%[2]s" AS SELECT * FROM "%[1]s"."%[3]s" LIMIT 0`, dd.Namespace, stagingTableName, tableName)
+	pkgLogger.Debugf("DD: Creating staging table for table:%s at %s\n", tableName, sqlStatement)
+	if _, err = txn.ExecContext(dd.ctx, sqlStatement); err != nil {
+		pkgLogger.Errorf("DD: Error creating staging table for table:%s: %v\n", tableName, err)
+		return
+	}
+
+	loadFileType := dd.Uploader.GetLoadFileType()
+	for _, fileName := range fileNames {
+		sqlStatement = copyStatement(dd.Namespace, stagingTableName, columns, fileName, loadFileType)
+		pkgLogger.Debugf("DD: Copying load file into staging table:%s at %s\n", stagingTableName, sqlStatement)
+		if _, err = txn.ExecContext(dd.ctx, sqlStatement); err != nil {
+			pkgLogger.Errorf("DD: Error copying load file %s into staging table:%s: %v", fileName, stagingTableName, err)
+			return
+		}
+	}
+	return
+}
+
+func (dd *HandleT) loadTable(tableName string, tableSchemaInUpload warehouseutils.TableSchemaT, skipTempTableDelete bool) (stagingTableName string, err error) {
+	pkgLogger.Infof("DD: Starting load for table:%s", tableName)
+	sortedColumnKeys := warehouseutils.SortColumnKeysFromColumnMap(tableSchemaInUpload)
+
+	txn, err := dd.Db.BeginTx(dd.ctx, nil)
+	if err != nil {
+		pkgLogger.Errorf("DD: Error while beginning a transaction in db for loading in table:%s: %v", tableName, err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := txn.Rollback(); rollbackErr != nil {
+				pkgLogger.Errorf("DD: Error in rolling back transaction : %v", rollbackErr)
+			}
+		}
+	}()
+
+	stagingTableName, err = dd.copyLoadFiles(txn, tableName, sortedColumnKeys)
+	if err != nil {
+		return
+	}
+
+	// deduplication process
+	primaryKey := "id"
+	if column, ok := primaryKeyMap[tableName]; ok {
+		primaryKey = column
+	}
+	partitionKey := "id"
+	if column, ok := partitionKeyMap[tableName]; ok {
+		partitionKey = column
+	}
+	var additionalJoinClause string
+	if tableName == warehouseutils.DiscardsTable {
+		additionalJoinClause = fmt.Sprintf(`AND _source."%[3]s" = "%[1]s"."%[2]s"."%[3]s" AND _source."%[4]s" = "%[1]s"."%[2]s"."%[4]s"`, dd.Namespace, tableName, "table_name", "column_name")
+	}
+	sqlStatement := fmt.Sprintf(`DELETE FROM "%[1]s"."%[2]s" USING "%[1]s"."%[3]s" AS _source WHERE (_source."%[4]s" = "%[1]s"."%[2]s"."%[4]s" %[5]s)`, dd.Namespace, tableName, stagingTableName, primaryKey, additionalJoinClause)
+	pkgLogger.Infof("DD: Deduplicate records for table:%s using staging table: %s\n", tableName, sqlStatement)
+	if _, err = txn.ExecContext(dd.ctx, sqlStatement); err != nil {
+		pkgLogger.Errorf("DD: Error deleting from original table for dedup: %v\n", err)
+		return
+	}
+
+	quotedColumnNames := warehouseutils.DoubleQuoteAndJoinByComma(sortedColumnKeys)
+	sqlStatement = fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[3]s)
+									SELECT %[3]s FROM (
+										SELECT *, row_number() OVER (PARTITION BY %[5]s ORDER BY received_at DESC) AS _rudder_staging_row_number FROM "%[1]s"."%[4]s"
+									) AS _ WHERE _rudder_staging_row_number = 1`, dd.Namespace, tableName, quotedColumnNames, stagingTableName, partitionKey)
+	pkgLogger.Infof("DD: Inserting records for table:%s using staging table: %s\n", tableName, sqlStatement)
+	if _, err = txn.ExecContext(dd.ctx, sqlStatement); err != nil {
+		pkgLogger.Errorf("DD: Error inserting into original table: %v\n", err)
+		return
+	}
+
+	if !skipTempTableDelete {
+		sqlStatement = fmt.Sprintf(`DROP TABLE "%[1]s"."%[2]s"`, dd.Namespace, stagingTableName)
+		if _, err = txn.ExecContext(dd.ctx, sqlStatement); err != nil {
+			pkgLogger.Errorf("DD: Error dropping staging table %s: %v\n", stagingTableName, err)
+			return
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		pkgLogger.Errorf("DD: Error while committing transaction for loading table:%s: %v", tableName, err)
+		return
+	}
+	pkgLogger.Infof("DD: Complete load for table:%s", tableName)
+	return
+}
+
+// usersStagingTableStatement returns the statement creating a staging table with the latest non null value of every
+// trait of the users of the identifies in the identifies staging table, merged with the current traits of the users table
+func usersStagingTableStatement(namespace, stagingTableName, identifiesStagingTable string, userColNames []string) string {
+	var traits []string
+	for _, colName := range userColNames {
+		if colName == "received_at" {
+			traits = append(traits, `max("received_at") AS "received_at"`)
+			continue
+		}
+		traits = append(traits, fmt.Sprintf(`arg_max("%[1]s", "received_at") FILTER (WHERE "%[1]s" IS NOT NULL) AS "%[1]s"`, colName))
+	}
+	quotedColumnNames := warehouseutils.DoubleQuoteAndJoinByComma(userColNames)
+	return fmt.Sprintf(`CREATE TABLE "%[1]s"."%[2]s" AS
+							SELECT id, %[5]s FROM (
+								SELECT id, %[6]s FROM "%[1]s"."%[3]s" WHERE id IN (SELECT user_id FROM "%[1]s"."%[4]s" WHERE user_id IS NOT NULL)
+								UNION ALL
+								SELECT user_id AS id, %[6]s FROM "%[1]s"."%[4]s" WHERE user_id IS NOT NULL
+							) AS _ GROUP BY id`,
+		namespace, stagingTableName, warehouseutils.UsersTable, identifiesStagingTable, strings.Join(traits, ", "), quotedColumnNames)
+}
+
+func (dd *HandleT) loadUserTables() (errorMap map[string]error) {
+	errorMap = map[string]error{warehouseutils.IdentifiesTable: nil}
+	pkgLogger.Infof("DD: Starting load for identifies and users tables\n")
+	identifyStagingTable, err := dd.loadTable(warehouseutils.IdentifiesTable, dd.Uploader.GetTableSchemaInUpload(warehouseutils.IdentifiesTable), true)
+	defer dd.dropStagingTable(identifyStagingTable)
+	if err != nil {
+		errorMap[warehouseutils.IdentifiesTable] = err
+		return
+	}
+
+	if len(dd.Uploader.GetTableSchemaInUpload(warehouseutils.UsersTable)) == 0 {
+		return
+	}
+	errorMap[warehouseutils.UsersTable] = nil
+
+	userColMap := dd.Uploader.GetTableSchemaInWarehouse(warehouseutils.UsersTable)
+	if _, ok := userColMap["received_at"]; skipComputingUserLatestTraits || !ok {
+		_, err := dd.loadTable(warehouseutils.UsersTable, dd.Uploader.GetTableSchemaInUpload(warehouseutils.UsersTable), false)
+		if err != nil {
+			errorMap[warehouseutils.UsersTable] = err
+		}
+		return
+	}
+
+	var userColNames []string
+	for colName := range userColMap {
+		if colName == "id" {
+			continue
+		}
+		userColNames = append(userColNames, colName)
+	}
+	sort.Strings(userColNames)
+
+	stagingTableName := dd.stagingTableName(warehouseutils.UsersTable)
+	defer dd.dropStagingTable(stagingTableName)
+	sqlStatement := usersStagingTableStatement(dd.Namespace, stagingTableName, identifyStagingTable, userColNames)
+	pkgLogger.Infof("DD: Creating staging table for users: %s\n", sqlStatement)
+	if _, err = dd.Db.ExecContext(dd.ctx, sqlStatement); err != nil {
+		errorMap[warehouseutils.UsersTable] = err
+		return
+	}
+
+	txn, err := dd.Db.BeginTx(dd.ctx, nil)
+	if err != nil {
+		errorMap[warehouseutils.UsersTable] = err
+		return
+	}
+	sqlStatement = fmt.Sprintf(`DELETE FROM "%[1]s"."%[2]s" USING "%[1]s"."%[3]s" AS _source WHERE (_source.id = "%[1]s"."%[2]s".id)`, dd.Namespace, warehouseutils.UsersTable, stagingTableName)
+	pkgLogger.Infof("DD: Dedup records for table:%s using staging table: %s\n", warehouseutils.UsersTable, sqlStatement)
+	if _, err = txn.ExecContext(dd.ctx, sqlStatement); err != nil {
+		pkgLogger.Errorf("DD: Error deleting from original table for dedup: %v\n", err)
+		_ = txn.Rollback()
+		errorMap[warehouseutils.UsersTable] = err
+		return
+	}
+	quotedColumnNames := warehouseutils.DoubleQuoteAndJoinByComma(append([]string{"id"}, userColNames...))
+	sqlStatement = fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[4]s) SELECT %[4]s FROM "%[1]s"."%[3]s"`, dd.Namespace, warehouseutils.UsersTable, stagingTableName, quotedColumnNames)
+	pkgLogger.Infof("DD: Inserting records for table:%s using staging table: %s\n", warehouseutils.UsersTable, sqlStatement)
+	if _, err = txn.ExecContext(dd.ctx, sqlStatement); err != nil {
+		pkgLogger.Errorf("DD: Error inserting into users table from staging table: %v\n", err)
+		_ = txn.Rollback()
+		errorMap[warehouseutils.UsersTable] = err
+		return
+	}
+	if err = txn.Commit(); err != nil {
+		pkgLogger.Errorf("DD: Error in transaction commit for users table: %v\n", err)
+		errorMap[warehouseutils.UsersTable] = err
+	}
+	return
+}
+
+func (dd *HandleT) CreateSchema() (err error) {
+	sqlStatement := fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %q`, dd.Namespace)
+	pkgLogger.Infof("DD: Creating schema name in duckdb for DD:%s : %v", dd.Warehouse.Destination.ID, sqlStatement)
+	_, err = dd.Db.Exec(sqlStatement)
+	return
+}
+
+func (dd *HandleT) dropStagingTable(stagingTableName string) {
+	if stagingTableName == "" {
+		return
+	}
+	pkgLogger.Infof("DD: dropping table %+v\n", stagingTableName)
+	_, err := dd.Db.Exec(fmt.Sprintf(`DROP TABLE IF EXISTS "%[1]s"."%[2]s"`, dd.Namespace, stagingTableName))
+	if err != nil {
+		pkgLogger.Errorf("DD:  Error dropping staging table %s in duckdb: %v", stagingTableName, err)
+	}
+}
+
+func (dd *HandleT) CreateTable(tableName string, columnMap map[string]string) (err error) {
+	sqlStatement := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS "%[1]s"."%[2]s" ( %v )`, dd.Namespace, tableName, columnsWithDataTypes(columnMap, ""))
+	pkgLogger.Infof("DD: Creating table in duckdb for DD:%s : %v", dd.Warehouse.Destination.ID, sqlStatement)
+	_, err = dd.Db.Exec(sqlStatement)
+	return
+}
+
+func (dd *HandleT) DropTable(tableName string) (err error) {
+	sqlStatement := fmt.Sprintf(`DROP TABLE "%[1]s"."%[2]s"`, dd.Namespace, tableName)
+	pkgLogger.Infof("DD: Dropping table in duckdb for DD:%s : %v", dd.Warehouse.Destination.ID, sqlStatement)
+	_, err = dd.Db.Exec(sqlStatement)
+	return
+}
+
+func (dd *HandleT) AddColumn(tableName, columnName, columnType string) (err error) {
+	sqlStatement := fmt.Sprintf(`ALTER TABLE "%s"."%s" ADD COLUMN IF NOT EXISTS %q %s`, dd.Namespace, tableName, columnName, rudderDataTypesMapToDuckDB[columnType])
+	pkgLogger.Infof("DD: Adding column in duckdb for DD:%s : %v", dd.Warehouse.Destination.ID, sqlStatement)
+	_, err = dd.Db.Exec(sqlStatement)
+	return
+}
+
+// AlterColumn changes the type of a column to a wider one. Strings are already of unlimited length in duckdb.
+func (dd *HandleT) AlterColumn(tableName, columnName, columnType string) (err error) {
+	dataType, ok := rudderDataTypesMapToDuckDB[columnType]
+	if !ok {
+		return
+	}
+	sqlStatement := fmt.Sprintf(`ALTER TABLE "%s"."%s" ALTER COLUMN %q TYPE %s`, dd.Namespace, tableName, columnName, dataType)
+	pkgLogger.Infof("DD: Altering column in duckdb for DD:%s : %v", dd.Warehouse.Destination.ID, sqlStatement)
+	_, err = dd.Db.Exec(sqlStatement)
+	return
+}
+
+func (dd *HandleT) TestConnection(warehouse warehouseutils.WarehouseT) (err error) {
+	dd.Warehouse = warehouse
+	dd.Db, err = Connect(dd.getConnectionCredentials())
+	if err != nil {
+		return
+	}
+	defer dd.Db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), dd.ConnectTimeout)
+	defer cancel()
+
+	err = dd.Db.PingContext(ctx)
+	if err == context.DeadlineExceeded {
+		return fmt.Errorf("connection testing timed out after %d sec", dd.ConnectTimeout/time.Second)
+	}
+	return err
+}
+
+func (dd *HandleT) Setup(warehouse warehouseutils.WarehouseT, uploader warehouseutils.UploaderI) (err error) {
+	dd.Warehouse = warehouse
+	dd.Namespace = warehouse.Namespace
+	dd.Uploader = uploader
+	dd.ObjectStorage = warehouseutils.ObjectStorageType(warehouseutils.DUCKDB, warehouse.Destination.Config, dd.Uploader.UseRudderStorage())
+	dd.ctx, dd.cancel = context.WithCancel(context.Background())
+
+	dd.Db, err = Connect(dd.getConnectionCredentials())
+	return err
+}
+
+func (dd *HandleT) CrashRecover(warehouse warehouseutils.WarehouseT) (err error) {
+	dd.Warehouse = warehouse
+	dd.Namespace = warehouse.Namespace
+	dd.Db, err = Connect(dd.getConnectionCredentials())
+	if err != nil {
+		return err
+	}
+	defer dd.Db.Close()
+	dd.dropDanglingStagingTables()
+	return
+}
+
+func (dd *HandleT) dropDanglingStagingTables() {
+	sqlStatement := fmt.Sprintf(`SELECT table_name FROM information_schema.tables WHERE table_schema = '%s' AND table_name LIKE '%s%%'`, escapeLiteral(dd.Namespace), stagingTablePrefix)
+	rows, err := dd.Db.Query(sqlStatement)
+	if err != nil {
+		pkgLogger.Errorf("WH: DD: Error dropping dangling staging tables in duckdb: %v\nQuery: %s\n", err, sqlStatement)
+		return
+	}
+	defer rows.Close()
+
+	var stagingTableNames []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			pkgLogger.Errorf("WH: DD: Error scanning dangling staging tables in duckdb: %v\n", err)
+			return
+		}
+		stagingTableNames = append(stagingTableNames, tableName)
+	}
+	pkgLogger.Infof("WH: DD: Dropping dangling staging tables: %+v  %+v\n", len(stagingTableNames), stagingTableNames)
+	for _, stagingTableName := range stagingTableNames {
+		dd.dropStagingTable(stagingTableName)
+	}
+}
+
+// FetchSchema queries duckdb and returns the schema associated with provided namespace
+func (dd *HandleT) FetchSchema(warehouse warehouseutils.WarehouseT) (schema warehouseutils.SchemaT, err error) {
+	dd.Warehouse = warehouse
+	dd.Namespace = warehouse.Namespace
+	dbHandle, err := Connect(dd.getConnectionCredentials())
+	if err != nil {
+		return
+	}
+	defer dbHandle.Close()
+
+	schema = make(warehouseutils.SchemaT)
+	sqlStatement := fmt.Sprintf(`SELECT t.table_name, c.column_name, c.data_type FROM information_schema.tables t LEFT JOIN information_schema.columns c ON (t.table_name = c.table_name AND t.table_schema = c.table_schema) WHERE t.table_schema = '%s' AND t.table_name NOT LIKE '%s%%'`, escapeLiteral(dd.Namespace), stagingTablePrefix)
+	rows, err := dbHandle.Query(sqlStatement)
+	if err != nil {
+		pkgLogger.Errorf("DD: Error in fetching schema from duckdb destination:%v, query: %v", dd.Warehouse.Destination.ID, sqlStatement)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tName, cName, cType sql.NullString
+		if err = rows.Scan(&tName, &cName, &cType); err != nil {
+			pkgLogger.Errorf("DD: Error in processing fetched schema from duckdb destination:%v", dd.Warehouse.Destination.ID)
+			return
+		}
+		if _, ok := schema[tName.String]; !ok {
+			schema[tName.String] = make(map[string]string)
+		}
+		if cName.Valid && cType.Valid {
+			if datatype, ok := duckDBDataTypesMapToRudder[strings.ToUpper(cType.String)]; ok {
+				schema[tName.String][cName.String] = datatype
+			} else {
+				warehouseutils.WHCounterStat(warehouseutils.RUDDER_MISSING_DATATYPE, &dd.Warehouse, warehouseutils.Tag{Name: "datatype", Value: cType.String}).Count(1)
+			}
+		}
+	}
+	err = rows.Err()
+	return
+}
+
+func (dd *HandleT) LoadUserTables() map[string]error {
+	return dd.loadUserTables()
+}
+
+func (dd *HandleT) LoadTable(tableName string) error {
+	_, err := dd.loadTable(tableName, dd.Uploader.GetTableSchemaInUpload(tableName), false)
+	return err
+}
+
+func (dd *HandleT) Cleanup() {
+	if dd.cancel != nil {
+		dd.cancel()
+	}
+	if dd.Db != nil {
+		dd.dropDanglingStagingTables()
+		dd.Db.Close()
+	}
+}
+
+// loadIdentityTable copies the single load file of an identity table into a staging table with the given columns
+func (dd *HandleT) loadIdentityTable(txn *sql.Tx, tableName string, columns []string) (stagingTableName string, err error) {
+	loadFile, err := dd.Uploader.GetSingleLoadFile(tableName)
+	if err != nil {
+		return
+	}
+	downloader, err := dd.downloader()
+	if err != nil {
+		return
+	}
+	fileName, err := dd.downloadLoadFile(downloader, loadFile.Location)
+	if err != nil {
+		return
+	}
+	defer misc.RemoveFilePaths(fileName)
+
+	stagingTableName = dd.stagingTableName(tableName)
+	sqlStatement := fmt.Sprintf(`CREATE TABLE "%[1]s"."%[2]s" AS SELECT * FROM "%[1]s"."%[3]s" LIMIT 0`, dd.Namespace, stagingTableName, tableName)
+	if _, err = txn.ExecContext(dd.ctx, sqlStatement); err != nil {
+		return
+	}
+	// identity load files are always gzipped csv files
+	_, err = txn.ExecContext(dd.ctx, copyStatement(dd.Namespace, stagingTableName, columns, fileName, warehouseutils.LOAD_FILE_TYPE_CSV))
+	return
+}
+
+func (dd *HandleT) LoadIdentityMergeRulesTable() (err error) {
+	tableName := warehouseutils.IdentityMergeRulesTable
+	pkgLogger.Infof("DD: Starting load for table:%s\n", tableName)
+	columns := []string{"merge_property_1_type", "merge_property_1_value", "merge_property_2_type", "merge_property_2_value"}
+
+	txn, err := dd.Db.BeginTx(dd.ctx, nil)
+	if err != nil {
+		return
+	}
+	stagingTableName, err := dd.loadIdentityTable(txn, tableName, columns)
+	if err != nil {
+		pkgLogger.Errorf("DD: Error loading table:%s: %v\n", tableName, err)
+		_ = txn.Rollback()
+		return
+	}
+	quotedColumnNames := warehouseutils.DoubleQuoteAndJoinByComma(columns)
+	sqlStatement := fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[4]s) SELECT %[4]s FROM "%[1]s"."%[3]s"`, dd.Namespace, tableName, stagingTableName, quotedColumnNames)
+	if _, err = txn.ExecContext(dd.ctx, sqlStatement); err == nil {
+		_, err = txn.ExecContext(dd.ctx, fmt.Sprintf(`DROP TABLE "%[1]s"."%[2]s"`, dd.Namespace, stagingTableName))
+	}
+	if err != nil {
+		pkgLogger.Errorf("DD: Error loading table:%s: %v\n", tableName, err)
+		_ = txn.Rollback()
+		return
+	}
+	return txn.Commit()
+}
+
+func (dd *HandleT) LoadIdentityMappingsTable() (err error) {
+	tableName := warehouseutils.IdentityMappingsTable
+	pkgLogger.Infof("DD: Starting load for table:%s\n", tableName)
+	columns := []string{"merge_property_type", "merge_property_value", "rudder_id", "updated_at"}
+
+	txn, err := dd.Db.BeginTx(dd.ctx, nil)
+	if err != nil {
+		return
+	}
+	stagingTableName, err := dd.loadIdentityTable(txn, tableName, columns)
+	if err != nil {
+		pkgLogger.Errorf("DD: Error loading table:%s: %v\n", tableName, err)
+		_ = txn.Rollback()
+		return
+	}
+	quotedColumnNames := warehouseutils.DoubleQuoteAndJoinByComma(columns)
+	sqlStatements := []string{
+		fmt.Sprintf(`DELETE FROM "%[1]s"."%[2]s" USING "%[1]s"."%[3]s" AS _source WHERE (_source.merge_property_type = "%[1]s"."%[2]s".merge_property_type AND _source.merge_property_value = "%[1]s"."%[2]s".merge_property_value)`, dd.Namespace, tableName, stagingTableName),
+		fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[4]s)
+						SELECT %[4]s FROM (
+							SELECT *, row_number() OVER (PARTITION BY merge_property_type, merge_property_value ORDER BY updated_at DESC) AS _rudder_staging_row_number FROM "%[1]s"."%[3]s"
+						) AS _ WHERE _rudder_staging_row_number = 1`, dd.Namespace, tableName, stagingTableName, quotedColumnNames),
+		fmt.Sprintf(`DROP TABLE "%[1]s"."%[2]s"`, dd.Namespace, stagingTableName),
+	}
+	for _, sqlStatement := range sqlStatements {
+		pkgLogger.Infof("DD: Dedup records for table:%s using staging table: %s\n", tableName, sqlStatement)
+		if _, err = txn.ExecContext(dd.ctx, sqlStatement); err != nil {
+			pkgLogger.Errorf("DD: Error loading table:%s: %v\n", tableName, err)
+			_ = txn.Rollback()
+			return
+		}
+	}
+	return txn.Commit()
+}
+
+// DownloadIdentityRules gets distinct combinations of anonymous_id, user_id from tables in warehouse
+func (dd *HandleT) DownloadIdentityRules(gzWriter *misc.GZipWriter) (err error) {
+	schema, err := dd.FetchSchema(dd.Warehouse)
+	if err != nil {
+		return
+	}
+	for _, tableName := range []string{"tracks", "pages", "screens", "identifies", "aliases"} {
+		columns, ok := schema[tableName]
+		if !ok {
+			continue
+		}
+		_, hasAnonymousID := columns["anonymous_id"]
+		_, hasUserID := columns["user_id"]
+		var toSelectFields string
+		switch {
+		case hasAnonymousID && hasUserID:
+			toSelectFields = `anonymous_id, user_id`
+		case hasAnonymousID:
+			toSelectFields = `anonymous_id, NULL AS user_id`
+		case hasUserID:
+			toSelectFields = `NULL AS anonymous_id, user_id`
+		default:
+			pkgLogger.Infof("DD: anonymous_id, user_id columns not present in table: %s", tableName)
+			continue
+		}
+
+		sqlStatement := fmt.Sprintf(`SELECT DISTINCT %s FROM "%s"."%s"`, toSelectFields, dd.Namespace, tableName)
+		pkgLogger.Infof("DD: Downloading distinct combinations of anonymous_id, user_id: %s", sqlStatement)
+		if err = dd.downloadIdentityRules(gzWriter, sqlStatement); err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+func (dd *HandleT) downloadIdentityRules(gzWriter *misc.GZipWriter, sqlStatement string) error {
+	rows, err := dd.Db.Query(sqlStatement)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var anonymousID, userID sql.NullString
+		if err := rows.Scan(&anonymousID, &userID); err != nil {
+			return err
+		}
+		if !anonymousID.Valid && !userID.Valid {
+			continue
+		}
+		var buff bytes.Buffer
+		csvWriter := csv.NewWriter(&buff)
+		// avoid setting null merge_property_1 to avoid not null constraint
+		if anonymousID.Valid {
+			_ = csvWriter.Write([]string{"anonymous_id", anonymousID.String, "user_id", userID.String})
+		} else {
+			_ = csvWriter.Write([]string{"user_id", userID.String, "anonymous_id", anonymousID.String})
+		}
+		csvWriter.Flush()
+		if err := gzWriter.WriteGZ(buff.String()); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (dd *HandleT) GetTotalCountInTable(tableName string) (total int64, err error) {
+	sqlStatement := fmt.Sprintf(`SELECT count(*) FROM "%[1]s"."%[2]s"`, dd.Namespace, tableName)
+	err = dd.Db.QueryRow(sqlStatement).Scan(&total)
+	if err != nil {
+		pkgLogger.Errorf(`DD: Error getting total count in table %s:%s`, dd.Namespace, tableName)
+	}
+	return
+}
+
+func (dd *HandleT) Connect(warehouse warehouseutils.WarehouseT) (client.Client, error) {
+	dd.Warehouse = warehouse
+	dd.Namespace = warehouse.Namespace
+	dd.ObjectStorage = warehouseutils.ObjectStorageType(
+		warehouseutils.DUCKDB,
+		warehouse.Destination.Config,
+		misc.IsConfiguredToUseRudderObjectStorage(dd.Warehouse.Destination.Config),
+	)
+	dbHandle, err := Connect(dd.getConnectionCredentials())
+	if err != nil {
+		return client.Client{}, err
+	}
+
+	return client.Client{Type: client.SQLClient, SQL: dbHandle}, err
+}
+
+func (dd *HandleT) LoadTestTable(_, tableName string, payloadMap map[string]interface{}, _ string) (err error) {
+	sqlStatement := fmt.Sprintf(`INSERT INTO %q.%q (%v) VALUES (%s)`,
+		dd.Namespace,
+		tableName,
+		fmt.Sprintf(`%q, %q`, "id", "val"),
+		fmt.Sprintf(`'%d', '%s'`, payloadMap["id"], payloadMap["val"]),
+	)
+	_, err = dd.Db.Exec(sqlStatement)
+	return
+}
+
+func (dd *HandleT) SetConnectionTimeout(timeout time.Duration) {
+	dd.ConnectTimeout = timeout
+}
@@ -0,0 +1,220 @@
+//go:build duckdb
+
+package duckdb
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rudderlabs/rudder-server/config"
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/services/filemanager"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// localFileManager downloads the load files from the local disk, the locations of the load files being their paths
+type localFileManager struct {
+	filemanager.FileManager
+}
+
+func (*localFileManager) New(*filemanager.SettingsT) (filemanager.FileManager, error) {
+	return &localFileManager{}, nil
+}
+
+func (*localFileManager) GetObjectNameFromLocation(location string) (string, error) {
+	return location, nil
+}
+
+func (*localFileManager) Download(_ context.Context, file *os.File, key string) error {
+	data, err := os.ReadFile(key)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	return err
+}
+
+// testUploader is the upload of the load files of its tables, with the given schemas in the upload & in the warehouse
+type testUploader struct {
+	warehouseutils.UploaderI
+	schemaInUpload    warehouseutils.SchemaT
+	schemaInWarehouse warehouseutils.SchemaT
+	loadFiles         map[string][]string
+}
+
+func (u *testUploader) GetTableSchemaInUpload(tableName string) warehouseutils.TableSchemaT {
+	return u.schemaInUpload[tableName]
+}
+
+func (u *testUploader) GetTableSchemaInWarehouse(tableName string) warehouseutils.TableSchemaT {
+	return u.schemaInWarehouse[tableName]
+}
+
+func (u *testUploader) GetLoadFilesMetadata(options warehouseutils.GetLoadFilesOptionsT) []warehouseutils.LoadFileT {
+	var loadFiles []warehouseutils.LoadFileT
+	for _, location := range u.loadFiles[options.Table] {
+		loadFiles = append(loadFiles, warehouseutils.LoadFileT{Location: location})
+	}
+	return loadFiles
+}
+
+func (*testUploader) UseRudderStorage() bool  { return false }
+func (*testUploader) GetLoadFileType() string { return warehouseutils.LOAD_FILE_TYPE_CSV }
+
+var _ = Describe("DuckDB load", func() {
+	const namespace = "rudder_test"
+
+	var (
+		dd       *HandleT
+		uploader *testUploader
+	)
+
+	// writeLoadFile writes a gzipped csv load file with the values of the sorted columns of each row
+	writeLoadFile := func(rows ...[]string) string {
+		path := filepath.Join(GinkgoT().TempDir(), "load.csv.gz")
+		file, err := os.Create(path)
+		Expect(err).NotTo(HaveOccurred())
+		gzWriter := gzip.NewWriter(file)
+		Expect(csv.NewWriter(gzWriter).WriteAll(rows)).To(Succeed())
+		Expect(gzWriter.Close()).To(Succeed())
+		Expect(file.Close()).To(Succeed())
+		return path
+	}
+
+	createTables := func() {
+		for tableName, columns := range uploader.schemaInUpload {
+			Expect(dd.CreateTable(tableName, columns)).To(Succeed())
+		}
+	}
+
+	queryStrings := func(query string) [][]string {
+		rows, err := dd.Db.Query(query)
+		Expect(err).NotTo(HaveOccurred())
+		defer rows.Close()
+		columns, err := rows.Columns()
+		Expect(err).NotTo(HaveOccurred())
+		var result [][]string
+		for rows.Next() {
+			values := make([]*string, len(columns))
+			dest := make([]interface{}, len(columns))
+			for i := range values {
+				dest[i] = &values[i]
+			}
+			Expect(rows.Scan(dest...)).To(Succeed())
+			row := make([]string, len(columns))
+			for i, value := range values {
+				if value != nil {
+					row[i] = *value
+				}
+			}
+			result = append(result, row)
+		}
+		Expect(rows.Err()).NotTo(HaveOccurred())
+		return result
+	}
+
+	BeforeEach(func() {
+		config.Load()
+		logger.Init()
+		misc.Init()
+		Init()
+
+		fileManagerFactory := filemanager.DefaultFileManagerFactory
+		filemanager.DefaultFileManagerFactory = &localFileManager{}
+		DeferCleanup(func() { filemanager.DefaultFileManagerFactory = fileManagerFactory })
+
+		uploader = &testUploader{loadFiles: map[string][]string{}}
+		dd = &HandleT{}
+		Expect(dd.Setup(warehouseutils.WarehouseT{
+			Namespace: namespace,
+			Destination: backendconfig.DestinationT{
+				ID:                    "duckdb-destination",
+				Config:                map[string]interface{}{databasePath: ":memory:"},
+				DestinationDefinition: backendconfig.DestinationDefinitionT{Name: warehouseutils.DUCKDB},
+			},
+		}, uploader)).To(Succeed())
+		DeferCleanup(dd.Cleanup)
+		Expect(dd.CreateSchema()).To(Succeed())
+	})
+
+	It("loads tables, keeping the latest of the events with the same id", func() {
+		uploader.schemaInUpload = warehouseutils.SchemaT{
+			"tracks": {"id": "string", "event": "string", "received_at": "datetime", "revenue": "float"},
+		}
+		createTables()
+
+		// columns: event, id, received_at, revenue
+		uploader.loadFiles["tracks"] = []string{
+			writeLoadFile(
+				[]string{"signed_up", "t1", "2022-06-01T10:00:00.000Z", "1.5"},
+				[]string{"purchased", "t2", "2022-06-01T10:01:00.000Z", ""},
+			),
+			writeLoadFile(
+				[]string{"signed_up_retried", "t1", "2022-06-01T10:02:00.000Z", "2.5"},
+			),
+		}
+		Expect(dd.LoadTable("tracks")).To(Succeed())
+		Expect(queryStrings(`SELECT id, event, revenue FROM "rudder_test"."tracks" ORDER BY id`)).To(Equal([][]string{
+			{"t1", "signed_up_retried", "2.5"},
+			{"t2", "purchased", ""},
+		}))
+
+		// the events of a later upload replace the ones already loaded
+		uploader.loadFiles["tracks"] = []string{
+			writeLoadFile([]string{"purchased_again", "t2", "2022-06-02T10:00:00.000Z", "3"}),
+		}
+		Expect(dd.LoadTable("tracks")).To(Succeed())
+		Expect(queryStrings(`SELECT id, event, revenue FROM "rudder_test"."tracks" ORDER BY id`)).To(Equal([][]string{
+			{"t1", "signed_up_retried", "2.5"},
+			{"t2", "purchased_again", "3"},
+		}))
+		Expect(dd.GetTotalCountInTable("tracks")).To(BeEquivalentTo(2))
+
+		Expect(queryStrings(`SELECT table_name FROM information_schema.tables WHERE table_name LIKE 'rudder_staging_%'`)).To(BeEmpty())
+	})
+
+	It("loads the latest traits of users from their identifies", func() {
+		uploader.schemaInUpload = warehouseutils.SchemaT{
+			warehouseutils.IdentifiesTable: {"id": "string", "user_id": "string", "email": "string", "name": "string", "received_at": "datetime"},
+			warehouseutils.UsersTable:      {"id": "string", "email": "string", "name": "string", "received_at": "datetime"},
+		}
+		uploader.schemaInWarehouse = uploader.schemaInUpload
+		createTables()
+		_, err := dd.Db.Exec(`INSERT INTO "rudder_test"."users" (id, email, name, received_at) VALUES ('u1', 'old@example.com', 'Old Name', '2022-05-01 00:00:00+00')`)
+		Expect(err).NotTo(HaveOccurred())
+
+		// columns: email, id, name, received_at, user_id
+		uploader.loadFiles[warehouseutils.IdentifiesTable] = []string{writeLoadFile(
+			[]string{"new@example.com", "i1", "", "2022-06-01T10:00:00.000Z", "u1"},
+			[]string{"first@example.com", "i2", "First", "2022-06-01T10:00:00.000Z", "u2"},
+			[]string{"", "i3", "Second", "2022-06-01T11:00:00.000Z", "u2"},
+		)}
+		for tableName, err := range dd.LoadUserTables() {
+			Expect(err).NotTo(HaveOccurred(), tableName)
+		}
+
+		Expect(dd.GetTotalCountInTable(warehouseutils.IdentifiesTable)).To(BeEquivalentTo(3))
+		Expect(queryStrings(`SELECT id, email, name FROM "rudder_test"."users" ORDER BY id`)).To(Equal([][]string{
+			{"u1", "new@example.com", "Old Name"},
+			{"u2", "first@example.com", "Second"},
+		}))
+	})
+
+	It("stops loading once the upload is cleaned up", func() {
+		uploader.schemaInUpload = warehouseutils.SchemaT{"tracks": {"id": "string", "received_at": "datetime"}}
+		createTables()
+		uploader.loadFiles["tracks"] = []string{writeLoadFile([]string{"t1", time.Now().UTC().Format(time.RFC3339)})}
+
+		dd.cancel()
+		Expect(dd.LoadTable("tracks")).To(MatchError(context.Canceled))
+	})
+})
@@ -0,0 +1,17 @@
+package duckdb
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DuckDB", func() {
+	It("fails to connect without a registered driver", func() {
+		DeferCleanup(func(name string) { driverName = name }, driverName)
+		driverName = "unregistered-duckdb"
+		_, err := Connect(CredentialsT{Path: "/tmp/rudder.duckdb"})
+		Expect(err).To(MatchError(ContainSubstring(`driver "unregistered-duckdb" is not registered`)))
+		_, err = Connect(CredentialsT{})
+		Expect(err).To(MatchError(ContainSubstring("database path is not set")))
+	})
+})
@@ -325,6 +325,7 @@ func (wh *HandleT) populateHistoricIdentities(warehouse warehouseutils.Warehouse
 			warehouse:    job.warehouse,
 			stagingFiles: job.stagingFiles,
 			dbHandle:     job.dbHandle,
+			schemaPolicy: getSchemaPolicy(job.warehouse),
 		}
 		job.schemaHandle = &schemaHandle
 
@@ -159,7 +159,7 @@ func (idr *HandleT) applyRule(txn *sql.Tx, ruleID int64, gzWriter *misc.GZipWrit
 	}
 	columnNames := []string{"merge_property_type", "merge_property_value", "rudder_id", "updated_at"}
 	for _, row := range rows {
-		eventLoader := warehouseutils.GetNewEventLoader(idr.Warehouse.Type, idr.Uploader.GetLoadFileType(), gzWriter)
+		eventLoader := warehouseutils.GetNewEventLoader(idr.Warehouse.Type, idr.identityLoadFileType(), gzWriter)
 		// TODO : support add row for parquet loader
 		eventLoader.AddRow(columnNames, row)
 		data, _ := eventLoader.WriteToString()
@@ -169,7 +169,7 @@ func (idr *HandleT) applyRule(txn *sql.Tx, ruleID int64, gzWriter *misc.GZipWrit
 	return len(rows), err
 }
 
-func (idr *HandleT) addRules(txn *sql.Tx, loadFileNames []string, gzWriter *misc.GZipWriter) (ids []int64, err error) {
+func (idr *HandleT) addRules(txn *sql.Tx, loadFileNames []string, loadFileType string, gzWriter *misc.GZipWriter) (ids []int64, err error) {
 	// add rules from load files into temp table
 	// use original table to delete redundant ones from temp table
 	// insert from temp table into original table
@@ -204,15 +204,20 @@ func (idr *HandleT) addRules(txn *sql.Tx, loadFileNames []string, gzWriter *misc
 		}
 		defer gzipFile.Close()
 
-		var gzipReader *gzip.Reader
-		gzipReader, err = gzip.NewReader(gzipFile)
-		if err != nil {
-			pkgLogger.Errorf(`IDR: Error reading downloaded load file at %s: %v`, loadFileName, err)
-			return
+		var loadFileReader io.Reader = gzipFile
+		// avro load files are compressed in blocks, and not gzipped
+		if loadFileType != warehouseutils.LOAD_FILE_TYPE_AVRO {
+			var gzipReader *gzip.Reader
+			gzipReader, err = gzip.NewReader(gzipFile)
+			if err != nil {
+				pkgLogger.Errorf(`IDR: Error reading downloaded load file at %s: %v`, loadFileName, err)
+				return
+			}
+			defer gzipReader.Close()
+			loadFileReader = gzipReader
 		}
-		defer gzipReader.Close()
 
-		eventReader := warehouseutils.NewEventReader(gzipReader, idr.Warehouse.Type)
+		eventReader := warehouseutils.NewEventReader(loadFileReader, idr.Warehouse.Type, loadFileType)
 		columnNames := []string{"merge_property_1_type", "merge_property_1_value", "merge_property_2_type", "merge_property_2_value"}
 		for {
 			var record []string
@@ -323,7 +328,7 @@ func (idr *HandleT) writeTableToFile(tableName string, txn *sql.Tx, gzWriter *mi
 		columnNames := []string{"merge_property_1_type", "merge_property_1_value", "merge_property_2_type", "merge_property_2_value"}
 		for rows.Next() {
 			var rowData []string
-			eventLoader := warehouseutils.GetNewEventLoader(idr.Warehouse.Type, idr.Uploader.GetLoadFileType(), gzWriter)
+			eventLoader := warehouseutils.GetNewEventLoader(idr.Warehouse.Type, idr.identityLoadFileType(), gzWriter)
 			var prop1Val, prop2Val, prop1Type, prop2Type sql.NullString
 			err = rows.Scan(&prop1Type, &prop1Val, &prop2Type, &prop2Val)
 			if err != nil {
@@ -346,6 +351,12 @@ func (idr *HandleT) writeTableToFile(tableName string, txn *sql.Tx, gzWriter *mi
 	return
 }
 
+// identityLoadFileType returns the type of the files of the identity tables written here, which are always loaded with
+// the default load file type of the warehouse, whatever the load file type of the upload
+func (idr *HandleT) identityLoadFileType() string {
+	return warehouseutils.GetLoadFileType(idr.Warehouse.Type)
+}
+
 func (idr *HandleT) downloadLoadFiles(tableName string) ([]string, error) {
 	objects := idr.Uploader.GetLoadFilesMetadata(warehouseutils.GetLoadFilesOptionsT{Table: tableName})
 	var fileNames []string
@@ -450,7 +461,7 @@ func (idr *HandleT) createTempGzFile(dirName string) (gzWriter misc.GZipWriter,
 	return
 }
 
-func (idr *HandleT) processMergeRules(fileNames []string) (err error) {
+func (idr *HandleT) processMergeRules(fileNames []string, loadFileType string) (err error) {
 	txn, err := idr.DbHandle.Begin()
 	if err != nil {
 		panic(err)
@@ -460,7 +471,7 @@ func (idr *HandleT) processMergeRules(fileNames []string) (err error) {
 	mergeRulesFileGzWriter, mergeRulesFilePath := idr.createTempGzFile(fmt.Sprintf(`/%s/`, misc.RudderIdentityMergeRulesTmp))
 	defer misc.RemoveFilePaths(mergeRulesFilePath)
 
-	ruleIDs, err := idr.addRules(txn, fileNames, &mergeRulesFileGzWriter)
+	ruleIDs, err := idr.addRules(txn, fileNames, loadFileType, &mergeRulesFileGzWriter)
 	if err != nil {
 		pkgLogger.Errorf(`IDR: Error adding rules to %s: %v`, idr.mergeRulesTable(), err)
 		return
@@ -524,7 +535,7 @@ func (idr *HandleT) Resolve() (err error) {
 		return
 	}
 
-	return idr.processMergeRules(loadFileNames)
+	return idr.processMergeRules(loadFileNames, idr.Uploader.GetLoadFileType())
 }
 
 func (idr *HandleT) ResolveHistoricIdentities() (err error) {
@@ -539,5 +550,5 @@ func (idr *HandleT) ResolveHistoricIdentities() (err error) {
 	}
 	loadFileNames = append(loadFileNames, path)
 
-	return idr.processMergeRules(loadFileNames)
+	return idr.processMergeRules(loadFileNames, idr.identityLoadFileType())
 }
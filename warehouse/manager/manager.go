@@ -10,6 +10,7 @@ import (
 	"github.com/rudderlabs/rudder-server/warehouse/client"
 	"github.com/rudderlabs/rudder-server/warehouse/datalake"
 	"github.com/rudderlabs/rudder-server/warehouse/deltalake"
+	"github.com/rudderlabs/rudder-server/warehouse/duckdb"
 	"github.com/rudderlabs/rudder-server/warehouse/mssql"
 	"github.com/rudderlabs/rudder-server/warehouse/postgres"
 	"github.com/rudderlabs/rudder-server/warehouse/redshift"
@@ -80,6 +81,9 @@ func New(destType string) (ManagerI, error) {
 	case warehouseutils.DELTALAKE:
 		var dl deltalake.HandleT
 		return &dl, nil
+	case warehouseutils.DUCKDB:
+		var dd duckdb.HandleT
+		return &dd, nil
 	}
 	return nil, fmt.Errorf("Provider of type %s is not configured for WarehouseManager", destType)
 }
@@ -114,6 +118,9 @@ func NewWarehouseOperations(destType string) (WarehouseOperations, error) {
 	case warehouseutils.DELTALAKE:
 		var dl deltalake.HandleT
 		return &dl, nil
+	case warehouseutils.DUCKDB:
+		var dd duckdb.HandleT
+		return &dd, nil
 	}
 	return nil, fmt.Errorf("Provider of type %s is not configured for WarehouseManager", destType)
 }
@@ -436,6 +436,12 @@ func (ms *HandleT) loadTable(tableName string, tableSchemaInUpload warehouseutil
 		additionalJoinClause = fmt.Sprintf(`AND _source.%[3]s = "%[1]s"."%[2]s"."%[3]s" AND _source.%[4]s = "%[1]s"."%[2]s"."%[4]s"`, ms.Namespace, tableName, "table_name", "column_name")
 	}
 	sqlStatement = fmt.Sprintf(`DELETE FROM "%[1]s"."%[2]s" FROM "%[1]s"."%[3]s" as  _source where (_source.%[4]s = "%[1]s"."%[2]s"."%[4]s" %[5]s)`, ms.Namespace, tableName, stagingTableName, primaryKey, additionalJoinClause)
+	// in upsert mode, only the rows not newer than the rows in the staging table are replaced
+	upsertConfig, upsert := warehouseutils.GetUpsertConfig(ms.Warehouse, tableName)
+	if upsert {
+		target := fmt.Sprintf(`"%s"."%s"`, ms.Namespace, tableName)
+		sqlStatement = fmt.Sprintf(`DELETE FROM %[1]s FROM "%[2]s"."%[3]s" as _source where (%[4]s AND %[5]s)`, target, ms.Namespace, stagingTableName, upsertConfig.JoinCondition("_source", target), upsertConfig.NotNewerCondition("_source", target))
+	}
 	pkgLogger.Infof("MS: Deduplicate records for table:%s using staging table: %s\n", tableName, sqlStatement)
 	_, err = txn.Exec(sqlStatement)
 	if err != nil {
@@ -450,6 +456,13 @@ func (ms *HandleT) loadTable(tableName string, tableSchemaInUpload warehouseutil
 										SELECT *, row_number() OVER (PARTITION BY %[5]s ORDER BY received_at DESC) AS _rudder_staging_row_number FROM "%[1]s"."%[4]s" 
 									) AS _ where _rudder_staging_row_number = 1
 									`, ms.Namespace, tableName, quotedColumnNames, stagingTableName, partitionKey)
+	if upsert {
+		sqlStatement = fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[3]s)
+									SELECT %[3]s FROM (
+										SELECT *, row_number() OVER (PARTITION BY %[5]s ORDER BY %[6]s) AS _rudder_staging_row_number FROM "%[1]s"."%[4]s"
+									) AS _ where _rudder_staging_row_number = 1 AND NOT EXISTS (SELECT 1 FROM "%[1]s"."%[2]s" AS _target WHERE %[7]s)
+									`, ms.Namespace, tableName, quotedColumnNames, stagingTableName, upsertConfig.PartitionBy(), upsertConfig.LatestFirst(), upsertConfig.JoinCondition("_", "_target"))
+	}
 	pkgLogger.Infof("MS: Inserting records for table:%s using staging table: %s\n", tableName, sqlStatement)
 	_, err = txn.Exec(sqlStatement)
 
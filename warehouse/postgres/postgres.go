@@ -386,6 +386,12 @@ func (pg *HandleT) loadTable(tableName string, tableSchemaInUpload warehouseutil
 		additionalJoinClause = fmt.Sprintf(`AND _source.%[3]s = "%[1]s"."%[2]s"."%[3]s" AND _source.%[4]s = "%[1]s"."%[2]s"."%[4]s"`, pg.Namespace, tableName, "table_name", "column_name")
 	}
 	sqlStatement = fmt.Sprintf(`DELETE FROM "%[1]s"."%[2]s" USING "%[1]s"."%[3]s" as  _source where (_source.%[4]s = "%[1]s"."%[2]s"."%[4]s" %[5]s)`, pg.Namespace, tableName, stagingTableName, primaryKey, additionalJoinClause)
+	// in upsert mode, only the rows not newer than the rows in the staging table are replaced
+	upsertConfig, upsert := warehouseutils.GetUpsertConfig(pg.Warehouse, tableName)
+	if upsert {
+		target := fmt.Sprintf(`"%s"."%s"`, pg.Namespace, tableName)
+		sqlStatement = fmt.Sprintf(`DELETE FROM %[1]s USING "%[2]s"."%[3]s" as _source where (%[4]s AND %[5]s)`, target, pg.Namespace, stagingTableName, upsertConfig.JoinCondition("_source", target), upsertConfig.NotNewerCondition("_source", target))
+	}
 	pkgLogger.Infof("PG: Deduplicate records for table:%s using staging table: %s\n", tableName, sqlStatement)
 	_, err = handleExec(&QueryParams{txn: txn, query: sqlStatement, enableWithQueryPlan: enableSQLStatementExecutionPlan})
 	if err != nil {
@@ -401,6 +407,13 @@ func (pg *HandleT) loadTable(tableName string, tableSchemaInUpload warehouseutil
 										SELECT *, row_number() OVER (PARTITION BY %[5]s ORDER BY received_at DESC) AS _rudder_staging_row_number FROM "%[1]s"."%[4]s" 
 									) AS _ where _rudder_staging_row_number = 1
 									`, pg.Namespace, tableName, quotedColumnNames, stagingTableName, partitionKey)
+	if upsert {
+		sqlStatement = fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[3]s)
+									SELECT %[3]s FROM (
+										SELECT *, row_number() OVER (PARTITION BY %[5]s ORDER BY %[6]s) AS _rudder_staging_row_number FROM "%[1]s"."%[4]s"
+									) AS _ where _rudder_staging_row_number = 1 AND NOT EXISTS (SELECT 1 FROM "%[1]s"."%[2]s" AS _target WHERE %[7]s)
+									`, pg.Namespace, tableName, quotedColumnNames, stagingTableName, upsertConfig.PartitionBy(), upsertConfig.LatestFirst(), upsertConfig.JoinCondition("_", "_target"))
+	}
 	pkgLogger.Infof("PG: Inserting records for table:%s using staging table: %s\n", tableName, sqlStatement)
 	_, err = handleExec(&QueryParams{txn: txn, query: sqlStatement, enableWithQueryPlan: enableSQLStatementExecutionPlan})
 
@@ -635,7 +648,15 @@ func (pg *HandleT) AddColumn(tableName, columnName, columnType string) (err erro
 	return err
 }
 
+// AlterColumn changes the type of a column to a wider one. Strings are already text columns in postgres.
 func (pg *HandleT) AlterColumn(tableName, columnName, columnType string) (err error) {
+	dataType, ok := rudderDataTypesMapToPostgres[columnType]
+	if !ok {
+		return
+	}
+	sqlStatement := fmt.Sprintf(`ALTER TABLE %s.%s ALTER COLUMN %q TYPE %s`, pg.Namespace, tableName, columnName, dataType)
+	pkgLogger.Infof("PG: Altering column in postgres for PG:%s : %v", pg.Warehouse.Destination.ID, sqlStatement)
+	_, err = pg.Db.Exec(sqlStatement)
 	return
 }
 
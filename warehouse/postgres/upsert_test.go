@@ -0,0 +1,161 @@
+package postgres_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/config"
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/services/filemanager"
+	"github.com/rudderlabs/rudder-server/testhelper/destination"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+	"github.com/rudderlabs/rudder-server/warehouse/postgres"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// localFileManager downloads the load files from the local disk, the locations of the load files being their paths
+type localFileManager struct {
+	filemanager.FileManager
+}
+
+func (*localFileManager) New(*filemanager.SettingsT) (filemanager.FileManager, error) {
+	return &localFileManager{}, nil
+}
+
+func (*localFileManager) GetObjectNameFromLocation(location string) (string, error) {
+	return location, nil
+}
+
+func (*localFileManager) Download(_ context.Context, file *os.File, key string) error {
+	data, err := os.ReadFile(key)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	return err
+}
+
+// testUploader is the upload of the load files of its tables, with the given schema
+type testUploader struct {
+	warehouseutils.UploaderI
+	schema    warehouseutils.SchemaT
+	loadFiles map[string][]string
+}
+
+func (u *testUploader) GetTableSchemaInUpload(tableName string) warehouseutils.TableSchemaT {
+	return u.schema[tableName]
+}
+
+func (u *testUploader) GetTableSchemaInWarehouse(tableName string) warehouseutils.TableSchemaT {
+	return u.schema[tableName]
+}
+
+func (u *testUploader) GetLoadFilesMetadata(options warehouseutils.GetLoadFilesOptionsT) []warehouseutils.LoadFileT {
+	var loadFiles []warehouseutils.LoadFileT
+	for _, location := range u.loadFiles[options.Table] {
+		loadFiles = append(loadFiles, warehouseutils.LoadFileT{Location: location})
+	}
+	return loadFiles
+}
+
+func (*testUploader) UseRudderStorage() bool { return false }
+
+// writeLoadFile writes a gzipped csv load file with the values of the sorted columns of each row
+func writeLoadFile(t *testing.T, rows ...[]string) string {
+	path := filepath.Join(t.TempDir(), "load.csv.gz")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	gzWriter := gzip.NewWriter(file)
+	require.NoError(t, csv.NewWriter(gzWriter).WriteAll(rows))
+	require.NoError(t, gzWriter.Close())
+	require.NoError(t, file.Close())
+	return path
+}
+
+func TestUpsertLoad(t *testing.T) {
+	config.Load()
+	logger.Init()
+	postgres.Init()
+	warehouseutils.Init()
+
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+	postgresContainer, err := destination.SetupPostgres(pool, t)
+	require.NoError(t, err)
+
+	fileManagerFactory := filemanager.DefaultFileManagerFactory
+	filemanager.DefaultFileManagerFactory = &localFileManager{}
+	t.Cleanup(func() { filemanager.DefaultFileManagerFactory = fileManagerFactory })
+
+	const namespace = "rudder_test"
+	uploader := &testUploader{
+		schema: warehouseutils.SchemaT{
+			"orders": {"id": "string", "order_id": "string", "status": "string", "updated_at": "datetime"},
+		},
+		loadFiles: map[string][]string{},
+	}
+	pg := &postgres.HandleT{}
+	require.NoError(t, pg.Setup(warehouseutils.WarehouseT{
+		Type:      warehouseutils.POSTGRES,
+		Namespace: namespace,
+		Destination: backendconfig.DestinationT{
+			ID: "postgres-destination",
+			Config: map[string]interface{}{
+				"host":     postgresContainer.Host,
+				"port":     postgresContainer.Port,
+				"database": postgresContainer.Database,
+				"user":     postgresContainer.User,
+				"password": postgresContainer.Password,
+				"sslMode":  "disable",
+				warehouseutils.UpsertTablesConfig: []interface{}{
+					map[string]interface{}{"tableName": "orders", "primaryKeys": []interface{}{"order_id"}, "orderByColumn": "updated_at"},
+				},
+			},
+			DestinationDefinition: backendconfig.DestinationDefinitionT{Name: warehouseutils.POSTGRES},
+		},
+	}, uploader))
+	t.Cleanup(pg.Cleanup)
+	require.NoError(t, pg.CreateSchema())
+	require.NoError(t, pg.CreateTable("orders", uploader.schema["orders"]))
+
+	_, err = pg.Db.Exec(`INSERT INTO "rudder_test"."orders" (id, order_id, status, updated_at) VALUES
+		('e0', 'o1', 'shipped', '2022-06-03 00:00:00+00'),
+		('e0', 'o2', 'created', '2022-06-01 00:00:00+00')`)
+	require.NoError(t, err)
+
+	// columns: id, order_id, status, updated_at
+	uploader.loadFiles["orders"] = []string{writeLoadFile(t,
+		// older than the existing row, which is kept
+		[]string{"e1", "o1", "cancelled", "2022-06-02T00:00:00.000Z"},
+		// newer than the existing row, which is replaced
+		[]string{"e2", "o2", "paid", "2022-06-02T00:00:00.000Z"},
+		// the latest of the rows of the upload is loaded
+		[]string{"e3", "o3", "created", "2022-06-01T00:00:00.000Z"},
+		[]string{"e4", "o3", "paid", "2022-06-02T00:00:00.000Z"},
+		[]string{"e5", "o3", "stale", "2022-05-31T00:00:00.000Z"},
+	)}
+	require.NoError(t, pg.LoadTable("orders"))
+
+	rows, err := pg.Db.Query(`SELECT id, order_id, status FROM "rudder_test"."orders" ORDER BY order_id`)
+	require.NoError(t, err)
+	defer rows.Close()
+	var orders [][]string
+	for rows.Next() {
+		var id, orderID, status string
+		require.NoError(t, rows.Scan(&id, &orderID, &status))
+		orders = append(orders, []string{id, orderID, status})
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, [][]string{
+		{"e0", "o1", "shipped"},
+		{"e2", "o2", "paid"},
+		{"e4", "o3", "paid"},
+	}, orders)
+}
@@ -360,6 +360,12 @@ func (rs *HandleT) loadTable(tableName string, tableSchemaInUpload, tableSchemaA
 	}
 
 	sqlStatement = fmt.Sprintf(`DELETE FROM %[1]s."%[2]s" using %[1]s."%[3]s" _source where (_source.%[4]s = %[1]s.%[2]s.%[4]s %[5]s)`, rs.Namespace, tableName, stagingTableName, primaryKey, additionalJoinClause)
+	// in upsert mode, only the rows not newer than the rows in the staging table are replaced
+	upsertConfig, upsert := warehouseutils.GetUpsertConfig(rs.Warehouse, tableName)
+	if upsert {
+		target := fmt.Sprintf(`%s."%s"`, rs.Namespace, tableName)
+		sqlStatement = fmt.Sprintf(`DELETE FROM %[1]s using %[2]s."%[3]s" _source where (%[4]s AND %[5]s)`, target, rs.Namespace, stagingTableName, upsertConfig.JoinCondition("_source", target), upsertConfig.NotNewerCondition("_source", target))
+	}
 	pkgLogger.Infof("RS: Dedup records for table:%s using staging table: %s\n", tableName, sqlStatement)
 	_, err = tx.Exec(sqlStatement)
 	if err != nil {
@@ -371,6 +377,9 @@ func (rs *HandleT) loadTable(tableName string, tableSchemaInUpload, tableSchemaA
 	quotedColumnNames := warehouseutils.DoubleQuoteAndJoinByComma(strkeys)
 
 	sqlStatement = fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[3]s) SELECT %[3]s FROM ( SELECT *, row_number() OVER (PARTITION BY %[5]s ORDER BY received_at ASC) AS _rudder_staging_row_number FROM "%[1]s"."%[4]s" ) AS _ where _rudder_staging_row_number = 1`, rs.Namespace, tableName, quotedColumnNames, stagingTableName, partitionKey)
+	if upsert {
+		sqlStatement = fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[3]s) SELECT %[3]s FROM ( SELECT *, row_number() OVER (PARTITION BY %[5]s ORDER BY %[6]s) AS _rudder_staging_row_number FROM "%[1]s"."%[4]s" ) AS _ where _rudder_staging_row_number = 1 AND NOT EXISTS (SELECT 1 FROM "%[1]s"."%[2]s" AS _target WHERE %[7]s)`, rs.Namespace, tableName, quotedColumnNames, stagingTableName, upsertConfig.PartitionBy(), upsertConfig.LatestFirst(), upsertConfig.JoinCondition("_", "_target"))
+	}
 	pkgLogger.Infof("RS: Inserting records for table:%s using staging table: %s\n", tableName, sqlStatement)
 	_, err = tx.Exec(sqlStatement)
 
@@ -19,6 +19,7 @@ type SchemaHandleT struct {
 	localSchema       warehouseutils.SchemaT
 	schemaInWarehouse warehouseutils.SchemaT
 	uploadSchema      warehouseutils.SchemaT
+	schemaPolicy      schemaPolicyT
 }
 
 func HandleSchemaChange(existingDataType, columnType string, columnVal interface{}) (newColumnVal interface{}, ok bool) {
@@ -130,7 +131,9 @@ func (sHandle *SchemaHandleT) fetchSchemaFromWarehouse(whManager manager.Manager
 	return schemaInWarehouse, nil
 }
 
-func mergeSchema(currentSchema warehouseutils.SchemaT, schemaList []warehouseutils.SchemaT, currentMergedSchema warehouseutils.SchemaT, warehouseType string) warehouseutils.SchemaT {
+// mergeSchema merges the schemas of the staging files in currentMergedSchema. Columns keep their type in the warehouse,
+// unless widenColumnTypes is set and the staging files have a wider type for them.
+func mergeSchema(currentSchema warehouseutils.SchemaT, schemaList []warehouseutils.SchemaT, currentMergedSchema warehouseutils.SchemaT, warehouseType string, widenColumnTypes bool) warehouseutils.SchemaT {
 	if len(currentMergedSchema) == 0 {
 		currentMergedSchema = warehouseutils.SchemaT{}
 	}
@@ -144,6 +147,15 @@ func mergeSchema(currentSchema warehouseutils.SchemaT, schemaList []warehouseuti
 			currentMergedSchema[tableName][columnName] = columnType
 			return true
 		}
+		if widenColumnTypes {
+			if isWiderColumnType(columnTypeInDB, columnType) {
+				columnTypeInDB = columnType
+			}
+			// keep the type of the column if an earlier staging file already widened it
+			if mergedColumnType, ok := currentMergedSchema[tableName][columnName]; ok && isWiderColumnType(columnTypeInDB, mergedColumnType) {
+				return true
+			}
+		}
 		currentMergedSchema[tableName][columnName] = columnTypeInDB
 		return true
 	}
@@ -187,7 +199,9 @@ func mergeSchema(currentSchema warehouseutils.SchemaT, schemaList []warehouseuti
 					}
 				}
 				// check if we already set the columnType in currentMergedSchema
-				if _, ok := currentMergedSchema[tableName][columnName]; !ok {
+				if mergedColumnType, ok := currentMergedSchema[tableName][columnName]; !ok {
+					currentMergedSchema[tableName][columnName] = columnType
+				} else if widenColumnTypes && isWiderColumnType(mergedColumnType, columnType) {
 					currentMergedSchema[tableName][columnName] = columnType
 				}
 			}
@@ -274,7 +288,7 @@ func (sh *SchemaHandleT) consolidateStagingFilesSchemaUsingWarehouseSchema() war
 		}
 		rows.Close()
 
-		consolidatedSchema = mergeSchema(schemaInLocalDB, schemas, consolidatedSchema, sh.warehouse.Type)
+		consolidatedSchema = mergeSchema(schemaInLocalDB, schemas, consolidatedSchema, sh.warehouse.Type, sh.schemaPolicy.widenColumnTypes)
 
 		count += stagingFilesSchemaPaginationSize
 		if count >= len(sh.stagingFiles) {
@@ -327,10 +341,12 @@ func hasSchemaChanged(localSchema, schemaInWarehouse warehouseutils.SchemaT) boo
 	return false
 }
 
-func getTableSchemaDiff(tableName string, currentSchema, uploadSchema warehouseutils.SchemaT) (diff warehouseutils.TableSchemaDiffT) {
+// getTableSchemaDiff returns the changes of the schema of the table in the upload. Columns are altered to a wider type only if widenColumnTypes is set.
+func getTableSchemaDiff(tableName string, currentSchema, uploadSchema warehouseutils.SchemaT, widenColumnTypes bool) (diff warehouseutils.TableSchemaDiffT) {
 	diff = warehouseutils.TableSchemaDiffT{
-		ColumnMap:     make(map[string]string),
-		UpdatedSchema: make(map[string]string),
+		ColumnMap:              make(map[string]string),
+		UpdatedSchema:          make(map[string]string),
+		ColumnTypesToBeWidened: make(map[string]string),
 	}
 
 	var currentTableSchema map[string]string
@@ -360,6 +376,10 @@ func getTableSchemaDiff(tableName string, currentSchema, uploadSchema warehouseu
 			diff.StringColumnsToBeAlteredToText = append(diff.StringColumnsToBeAlteredToText, columnName)
 			diff.UpdatedSchema[columnName] = columnType
 			diff.Exists = true
+		} else if widenColumnTypes && isWiderColumnType(currentTableSchema[columnName], columnType) {
+			diff.ColumnTypesToBeWidened[columnName] = columnType
+			diff.UpdatedSchema[columnName] = columnType
+			diff.Exists = true
 		}
 	}
 	return diff
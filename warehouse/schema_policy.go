@@ -0,0 +1,213 @@
+package warehouse
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// warehousesSupportingTypeWidening are the warehouses whose AlterColumn changes the type of a column to a wider one
+var warehousesSupportingTypeWidening = []string{warehouseutils.POSTGRES, warehouseutils.BQ, warehouseutils.DUCKDB}
+
+// schemaPolicyProtectedColumns are loaded whatever the column lists and limits of the policy, as the loads rely on them
+var schemaPolicyProtectedColumns = []string{"id", "received_at", "uuid_ts", "loaded_at", "user_id", "anonymous_id"}
+
+/*
+schemaPolicyT is how the schema of the tables of a destination evolves with the schema of the events, set in the config
+of the destination:
+
+	widenColumnTypes: columns whose events have a wider type than the column in the warehouse (int to bigint to
+	float) are altered to that type, instead of sending the values which can't be converted to the discards table
+	includeColumns: only the columns matching one of these patterns are loaded
+	excludeColumns: the columns matching one of these patterns are not loaded
+	maxColumnsPerTable: columns are not added to tables which already have that many columns
+	schemaChangesDryRun: the schema changes are only reported in the metadata of the upload, and the events are
+	loaded in the tables and columns already in the warehouse
+
+Patterns are either column names, matched in all the tables, or <table name>.<column name>, and may use the wildcards of
+path.Match. They are matched case insensitively.
+*/
+type schemaPolicyT struct {
+	widenColumnTypes   bool
+	includeColumns     []string
+	excludeColumns     []string
+	maxColumnsPerTable int
+	dryRun             bool
+}
+
+type columnTypeChangeT struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// tableSchemaChangesT are the changes of the schema of a table in an upload, and the columns left out of the upload by the policy
+type tableSchemaChangesT struct {
+	TableToBeCreated bool                         `json:"table_to_be_created,omitempty"`
+	AddedColumns     map[string]string            `json:"added_columns,omitempty"`
+	WidenedColumns   map[string]columnTypeChangeT `json:"widened_columns,omitempty"`
+	ExcludedColumns  []string                     `json:"excluded_columns,omitempty"`
+	ColumnsOverLimit []string                     `json:"columns_over_limit,omitempty"`
+}
+
+type schemaChangesT struct {
+	DryRun bool                           `json:"dry_run"`
+	Tables map[string]tableSchemaChangesT `json:"tables"`
+}
+
+func getSchemaPolicy(warehouse warehouseutils.WarehouseT) schemaPolicyT {
+	policy := schemaPolicyT{
+		widenColumnTypes: warehouseutils.GetConfigValueBoolString("widenColumnTypes", warehouse) == "true" && misc.ContainsString(warehousesSupportingTypeWidening, warehouse.Type),
+		includeColumns:   getConfigList("includeColumns", warehouse),
+		excludeColumns:   getConfigList("excludeColumns", warehouse),
+		dryRun:           warehouseutils.GetConfigValueBoolString("schemaChangesDryRun", warehouse) == "true",
+	}
+	switch maxColumns := warehouse.Destination.Config["maxColumnsPerTable"].(type) {
+	case float64:
+		policy.maxColumnsPerTable = int(maxColumns)
+	case string:
+		policy.maxColumnsPerTable, _ = strconv.Atoi(maxColumns)
+	}
+	return policy
+}
+
+// getConfigList returns the values of a list in the config of the destination, set either as an array or as a comma separated string
+func getConfigList(key string, warehouse warehouseutils.WarehouseT) []string {
+	var values []string
+	switch list := warehouse.Destination.Config[key].(type) {
+	case []interface{}:
+		for _, value := range list {
+			if s, ok := value.(string); ok && strings.TrimSpace(s) != "" {
+				values = append(values, strings.TrimSpace(s))
+			}
+		}
+	case string:
+		for _, s := range strings.Split(list, ",") {
+			if strings.TrimSpace(s) != "" {
+				values = append(values, strings.TrimSpace(s))
+			}
+		}
+	}
+	return values
+}
+
+// columnTypeWidenings are the wider types which the values of the columns of a type can be converted to without loss
+var columnTypeWidenings = map[string][]string{
+	"int":    {"bigint", "float"},
+	"bigint": {"float"},
+	"string": {"text"},
+}
+
+func isWiderColumnType(columnType, widerColumnType string) bool {
+	return misc.ContainsString(columnTypeWidenings[columnType], widerColumnType)
+}
+
+func matchesColumnPattern(patterns []string, tableName, columnName string) bool {
+	for _, pattern := range patterns {
+		name := columnName
+		if strings.Contains(pattern, ".") {
+			name = tableName + "." + columnName
+		}
+		if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name)); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func isSchemaPolicyProtectedColumn(columnName string) bool {
+	return misc.ContainsString(schemaPolicyProtectedColumns, strings.ToLower(columnName))
+}
+
+func isRudderTable(whType, tableName string) bool {
+	for _, rudderTable := range []string{warehouseutils.DiscardsTable, warehouseutils.IdentityMergeRulesTable, warehouseutils.IdentityMappingsTable} {
+		if tableName == warehouseutils.ToProviderCase(whType, rudderTable) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+apply returns the schema of the upload allowed by the policy, and the schema changes it brings to the schema in the
+warehouse. The tables of rudder, like the discards table, are left as they are.
+*/
+func (policy schemaPolicyT) apply(uploadSchema, schemaInWarehouse warehouseutils.SchemaT, whType string) (warehouseutils.SchemaT, schemaChangesT) {
+	schema := warehouseutils.SchemaT{}
+	changes := schemaChangesT{DryRun: policy.dryRun, Tables: map[string]tableSchemaChangesT{}}
+	for tableName, columnMap := range uploadSchema {
+		if isRudderTable(whType, tableName) {
+			schema[tableName] = columnMap
+			continue
+		}
+
+		tableSchemaInWarehouse, tableExists := schemaInWarehouse[tableName]
+		tableChanges := tableSchemaChangesT{
+			TableToBeCreated: !tableExists,
+			AddedColumns:     map[string]string{},
+			WidenedColumns:   map[string]columnTypeChangeT{},
+		}
+		tableSchema := map[string]string{}
+		var newColumns []string
+		for columnName, columnType := range columnMap {
+			if !isSchemaPolicyProtectedColumn(columnName) {
+				excluded := matchesColumnPattern(policy.excludeColumns, tableName, columnName)
+				if len(policy.includeColumns) > 0 && !matchesColumnPattern(policy.includeColumns, tableName, columnName) {
+					excluded = true
+				}
+				if excluded {
+					tableChanges.ExcludedColumns = append(tableChanges.ExcludedColumns, columnName)
+					continue
+				}
+			}
+
+			columnTypeInWarehouse, columnExists := tableSchemaInWarehouse[columnName]
+			switch {
+			case !columnExists:
+				newColumns = append(newColumns, columnName)
+			case isWiderColumnType(columnTypeInWarehouse, columnType):
+				tableChanges.WidenedColumns[columnName] = columnTypeChangeT{From: columnTypeInWarehouse, To: columnType}
+				if policy.dryRun {
+					columnType = columnTypeInWarehouse
+				}
+				tableSchema[columnName] = columnType
+			default:
+				tableSchema[columnName] = columnType
+			}
+		}
+
+		// protected columns are added first, and then the other columns by name up to the limit of columns of the table
+		sort.SliceStable(newColumns, func(i, j int) bool {
+			iProtected, jProtected := isSchemaPolicyProtectedColumn(newColumns[i]), isSchemaPolicyProtectedColumn(newColumns[j])
+			if iProtected != jProtected {
+				return iProtected
+			}
+			return newColumns[i] < newColumns[j]
+		})
+		columnsCount := len(tableSchemaInWarehouse)
+		for _, columnName := range newColumns {
+			if policy.maxColumnsPerTable > 0 && columnsCount >= policy.maxColumnsPerTable && !isSchemaPolicyProtectedColumn(columnName) {
+				tableChanges.ColumnsOverLimit = append(tableChanges.ColumnsOverLimit, columnName)
+				continue
+			}
+			columnsCount++
+			tableChanges.AddedColumns[columnName] = columnMap[columnName]
+			if !policy.dryRun {
+				tableSchema[columnName] = columnMap[columnName]
+			}
+		}
+		sort.Strings(tableChanges.ExcludedColumns)
+
+		if len(tableChanges.AddedColumns) > 0 || len(tableChanges.WidenedColumns) > 0 || len(tableChanges.ExcludedColumns) > 0 || len(tableChanges.ColumnsOverLimit) > 0 {
+			changes.Tables[tableName] = tableChanges
+		}
+		// tables are not created in dry runs, so their events are not loaded
+		if len(tableSchema) > 0 && (tableExists || !policy.dryRun) {
+			schema[tableName] = tableSchema
+		}
+	}
+	return schema, changes
+}
@@ -0,0 +1,177 @@
+package warehouse
+
+import (
+	"testing"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSchemaPolicy(t *testing.T) {
+	warehouse := func(destType string, config map[string]interface{}) warehouseutils.WarehouseT {
+		return warehouseutils.WarehouseT{Type: destType, Destination: backendconfig.DestinationT{Config: config}}
+	}
+
+	policy := getSchemaPolicy(warehouse(warehouseutils.POSTGRES, map[string]interface{}{
+		"widenColumnTypes":    true,
+		"includeColumns":      []interface{}{"tracks.*", " event "},
+		"excludeColumns":      "context_ip, context_user_agent",
+		"maxColumnsPerTable":  float64(100),
+		"schemaChangesDryRun": true,
+	}))
+	require.Equal(t, schemaPolicyT{
+		widenColumnTypes:   true,
+		includeColumns:     []string{"tracks.*", "event"},
+		excludeColumns:     []string{"context_ip", "context_user_agent"},
+		maxColumnsPerTable: 100,
+		dryRun:             true,
+	}, policy)
+
+	policy = getSchemaPolicy(warehouse(warehouseutils.SNOWFLAKE, map[string]interface{}{
+		"widenColumnTypes":   true,
+		"maxColumnsPerTable": "50",
+	}))
+	require.False(t, policy.widenColumnTypes, "snowflake columns can't be widened")
+	require.Equal(t, 50, policy.maxColumnsPerTable)
+
+	require.Equal(t, schemaPolicyT{}, getSchemaPolicy(warehouse(warehouseutils.BQ, map[string]interface{}{})))
+}
+
+func TestSchemaPolicyApply(t *testing.T) {
+	schemaInWarehouse := warehouseutils.SchemaT{
+		"tracks": {"id": "string", "event": "string", "count": "int", "name": "string"},
+	}
+	uploadSchema := warehouseutils.SchemaT{
+		"tracks":          {"id": "string", "event": "string", "count": "float", "name": "text", "context_ip": "string", "context_locale": "string"},
+		"pages":           {"id": "string", "url": "string"},
+		"rudder_discards": {"column_name": "string", "column_value": "string"},
+	}
+
+	testCases := []struct {
+		name           string
+		policy         schemaPolicyT
+		expectedSchema warehouseutils.SchemaT
+		expectedTables map[string]tableSchemaChangesT
+	}{
+		{
+			name:           "default policy",
+			policy:         schemaPolicyT{},
+			expectedSchema: uploadSchema,
+			expectedTables: map[string]tableSchemaChangesT{
+				"tracks": {
+					AddedColumns:   map[string]string{"context_ip": "string", "context_locale": "string"},
+					WidenedColumns: map[string]columnTypeChangeT{"count": {From: "int", To: "float"}, "name": {From: "string", To: "text"}},
+				},
+				"pages": {
+					TableToBeCreated: true,
+					AddedColumns:     map[string]string{"id": "string", "url": "string"},
+					WidenedColumns:   map[string]columnTypeChangeT{},
+				},
+			},
+		},
+		{
+			name:   "column lists",
+			policy: schemaPolicyT{includeColumns: []string{"tracks.*", "url"}, excludeColumns: []string{"CONTEXT_IP", "tracks.name"}},
+			expectedSchema: warehouseutils.SchemaT{
+				"tracks":          {"id": "string", "event": "string", "count": "float", "context_locale": "string"},
+				"pages":           {"id": "string", "url": "string"},
+				"rudder_discards": {"column_name": "string", "column_value": "string"},
+			},
+			expectedTables: map[string]tableSchemaChangesT{
+				"tracks": {
+					AddedColumns:    map[string]string{"context_locale": "string"},
+					WidenedColumns:  map[string]columnTypeChangeT{"count": {From: "int", To: "float"}},
+					ExcludedColumns: []string{"context_ip", "name"},
+				},
+				"pages": {
+					TableToBeCreated: true,
+					AddedColumns:     map[string]string{"id": "string", "url": "string"},
+					WidenedColumns:   map[string]columnTypeChangeT{},
+				},
+			},
+		},
+		{
+			name:   "max columns per table",
+			policy: schemaPolicyT{maxColumnsPerTable: 5},
+			expectedSchema: warehouseutils.SchemaT{
+				"tracks":          {"id": "string", "event": "string", "count": "float", "name": "text", "context_ip": "string"},
+				"pages":           {"id": "string", "url": "string"},
+				"rudder_discards": {"column_name": "string", "column_value": "string"},
+			},
+			expectedTables: map[string]tableSchemaChangesT{
+				"tracks": {
+					AddedColumns:     map[string]string{"context_ip": "string"},
+					WidenedColumns:   map[string]columnTypeChangeT{"count": {From: "int", To: "float"}, "name": {From: "string", To: "text"}},
+					ColumnsOverLimit: []string{"context_locale"},
+				},
+				"pages": {
+					TableToBeCreated: true,
+					AddedColumns:     map[string]string{"id": "string", "url": "string"},
+					WidenedColumns:   map[string]columnTypeChangeT{},
+				},
+			},
+		},
+		{
+			name:   "dry run",
+			policy: schemaPolicyT{dryRun: true},
+			expectedSchema: warehouseutils.SchemaT{
+				"tracks":          {"id": "string", "event": "string", "count": "int", "name": "string"},
+				"rudder_discards": {"column_name": "string", "column_value": "string"},
+			},
+			expectedTables: map[string]tableSchemaChangesT{
+				"tracks": {
+					AddedColumns:   map[string]string{"context_ip": "string", "context_locale": "string"},
+					WidenedColumns: map[string]columnTypeChangeT{"count": {From: "int", To: "float"}, "name": {From: "string", To: "text"}},
+				},
+				"pages": {
+					TableToBeCreated: true,
+					AddedColumns:     map[string]string{"id": "string", "url": "string"},
+					WidenedColumns:   map[string]columnTypeChangeT{},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schema, changes := tc.policy.apply(uploadSchema, schemaInWarehouse, warehouseutils.POSTGRES)
+			require.Equal(t, tc.expectedSchema, schema)
+			require.Equal(t, tc.policy.dryRun, changes.DryRun)
+			require.Equal(t, tc.expectedTables, changes.Tables)
+		})
+	}
+}
+
+func TestMergeSchemaWideningColumnTypes(t *testing.T) {
+	currentSchema := warehouseutils.SchemaT{"tracks": {"count": "int", "name": "string", "active": "boolean"}}
+	schemaList := []warehouseutils.SchemaT{
+		{"tracks": {"count": "float", "name": "string", "active": "string", "price": "int"}},
+		{"tracks": {"count": "int", "name": "text", "price": "float"}},
+	}
+
+	require.Equal(t,
+		warehouseutils.SchemaT{"tracks": {"count": "int", "name": "text", "active": "boolean", "price": "int"}},
+		mergeSchema(currentSchema, schemaList, warehouseutils.SchemaT{}, warehouseutils.POSTGRES, false),
+	)
+	require.Equal(t,
+		warehouseutils.SchemaT{"tracks": {"count": "float", "name": "text", "active": "boolean", "price": "float"}},
+		mergeSchema(currentSchema, schemaList, warehouseutils.SchemaT{}, warehouseutils.POSTGRES, true),
+	)
+}
+
+func TestGetTableSchemaDiffWideningColumnTypes(t *testing.T) {
+	currentSchema := warehouseutils.SchemaT{"tracks": {"count": "int", "name": "string", "price": "bigint"}}
+	uploadSchema := warehouseutils.SchemaT{"tracks": {"count": "float", "name": "text", "price": "float", "url": "string"}}
+
+	diff := getTableSchemaDiff("tracks", currentSchema, uploadSchema, false)
+	require.Equal(t, map[string]string{"url": "string"}, diff.ColumnMap)
+	require.Equal(t, []string{"name"}, diff.StringColumnsToBeAlteredToText)
+	require.Empty(t, diff.ColumnTypesToBeWidened)
+	require.Equal(t, map[string]string{"count": "int", "name": "text", "price": "bigint", "url": "string"}, diff.UpdatedSchema)
+
+	diff = getTableSchemaDiff("tracks", currentSchema, uploadSchema, true)
+	require.True(t, diff.Exists)
+	require.Equal(t, map[string]string{"count": "float", "price": "float"}, diff.ColumnTypesToBeWidened)
+	require.Equal(t, map[string]string{"count": "float", "name": "text", "price": "float", "url": "string"}, diff.UpdatedSchema)
+}
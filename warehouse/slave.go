@@ -185,7 +185,7 @@ func (job *PayloadT) getDiscardsTable() string {
 func (jobRun *JobRunT) getLoadFilePath(tableName string) string {
 	job := jobRun.job
 	randomness := uuid.Must(uuid.NewV4()).String()
-	return strings.TrimSuffix(jobRun.stagingFilePath, "json.gz") + tableName + fmt.Sprintf(`.%s`, randomness) + fmt.Sprintf(`.%s`, warehouseutils.GetLoadFileFormat(job.DestinationType))
+	return strings.TrimSuffix(jobRun.stagingFilePath, "json.gz") + tableName + fmt.Sprintf(`.%s`, randomness) + fmt.Sprintf(`.%s`, warehouseutils.GetLoadFileFormatForType(job.DestinationType, job.LoadFileType))
 }
 
 func (job *PayloadT) getColumnName(columnName string) string {
@@ -323,9 +323,14 @@ func (jobRun *JobRunT) GetWriter(tableName string) (warehouseutils.LoadFileWrite
 	if !ok {
 		var err error
 		outputFilePath := jobRun.getLoadFilePath(tableName)
-		if jobRun.job.LoadFileType == warehouseutils.LOAD_FILE_TYPE_PARQUET {
+		switch jobRun.job.LoadFileType {
+		case warehouseutils.LOAD_FILE_TYPE_PARQUET:
 			writer, err = warehouseutils.CreateParquetWriter(jobRun.job.UploadSchema[tableName], outputFilePath, jobRun.job.DestinationType)
-		} else {
+		case warehouseutils.LOAD_FILE_TYPE_AVRO:
+			writer, err = warehouseutils.CreateAvroWriter(jobRun.job.UploadSchema[tableName], outputFilePath, jobRun.job.DestinationType)
+		case warehouseutils.LOAD_FILE_TYPE_ORC:
+			writer, err = warehouseutils.CreateOrcWriter(jobRun.job.UploadSchema[tableName], outputFilePath, jobRun.job.DestinationType)
+		default:
 			writer, err = misc.CreateGZ(outputFilePath)
 		}
 		if err != nil {
@@ -451,6 +456,11 @@ func processStagingFile(job PayloadT, workerIndex int) (loadFileUploadOutputs []
 		tableName := batchRouterEvent.Metadata.Table
 		columnData := batchRouterEvent.Data
 
+		// skip the events of the tables left out of the upload by the schema policy of the destination
+		if _, ok := job.UploadSchema[tableName]; !ok {
+			continue
+		}
+
 		// Create separate load file for each table
 		writer, err := jobRun.GetWriter(tableName)
 		if err != nil {
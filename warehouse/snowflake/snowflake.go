@@ -274,6 +274,11 @@ func (sf *HandleT) loadTable(tableName string, tableSchemaInUpload warehouseutil
 	// https://docs.snowflake.com/en/sql-reference/sql/copy-into-table.html#copy-options-copyoptions
 	sqlStatement = fmt.Sprintf(`COPY INTO %v(%v) FROM '%v' %s PATTERN = '.*\.csv\.gz'
 		FILE_FORMAT = ( TYPE = csv FIELD_OPTIONALLY_ENCLOSED_BY = '"' ESCAPE_UNENCLOSED_FIELD = NONE ) TRUNCATECOLUMNS = TRUE`, fmt.Sprintf(`%s."%s"`, schemaIdentifier, stagingTableName), sortedColumnNames, loadFolder, sf.authString())
+	if sf.Uploader.GetLoadFileType() == warehouseutils.LOAD_FILE_TYPE_AVRO {
+		// the columns of avro load files are matched by name, and the columns missing in the load files are left null
+		sqlStatement = fmt.Sprintf(`COPY INTO %v FROM '%v' %s PATTERN = '.*\.avro'
+		FILE_FORMAT = ( TYPE = avro ) MATCH_BY_COLUMN_NAME = CASE_INSENSITIVE TRUNCATECOLUMNS = TRUE`, fmt.Sprintf(`%s."%s"`, schemaIdentifier, stagingTableName), loadFolder, sf.authString())
+	}
 
 	sanitisedSQLStmt, regexErr := misc.ReplaceMultiRegex(sqlStatement, map[string]string{
 		"AWS_KEY_ID='[^']*'":     "AWS_KEY_ID='***'",
@@ -175,6 +175,7 @@ func setMaxParallelLoads() {
 		warehouseutils.SNOWFLAKE:  config.GetInt("Warehouse.snowflake.maxParallelLoads", 3),
 		warehouseutils.CLICKHOUSE: config.GetInt("Warehouse.clickhouse.maxParallelLoads", 3),
 		warehouseutils.DELTALAKE:  config.GetInt("Warehouse.deltalake.maxParallelLoads", 3),
+		warehouseutils.DUCKDB:     config.GetInt("Warehouse.duckdb.maxParallelLoads", 1),
 	}
 	columnCountThresholds = map[string]int{
 		warehouseutils.AZURE_SYNAPSE: config.GetInt("Warehouse.azure_synapse.columnCountThreshold", 800),
@@ -218,9 +219,17 @@ func (job *UploadJobT) trackLongRunningUpload() chan struct{} {
 }
 
 func (job *UploadJobT) generateUploadSchema(schemaHandle *SchemaHandleT) error {
-	schemaHandle.uploadSchema = schemaHandle.consolidateStagingFilesSchemaUsingWarehouseSchema()
-	if job.upload.LoadFileType == warehouseutils.LOAD_FILE_TYPE_PARQUET {
-		// set merged schema if the loadFileType is parquet
+	uploadSchema := schemaHandle.consolidateStagingFilesSchemaUsingWarehouseSchema()
+	var schemaChanges schemaChangesT
+	schemaHandle.uploadSchema, schemaChanges = schemaHandle.schemaPolicy.apply(uploadSchema, schemaHandle.schemaInWarehouse, job.warehouse.Type)
+	if len(schemaChanges.Tables) > 0 {
+		err := job.setSchemaChanges(schemaChanges)
+		if err != nil {
+			return err
+		}
+	}
+	if job.upload.LoadFileType == warehouseutils.LOAD_FILE_TYPE_PARQUET || job.upload.LoadFileType == warehouseutils.LOAD_FILE_TYPE_ORC {
+		// set merged schema if the loadFileType is parquet or orc, so that the load files have all the columns of the tables
 		mergedSchema := mergeUploadAndLocalSchemas(schemaHandle.uploadSchema, schemaHandle.localSchema)
 		err := job.setMergedSchema(mergedSchema)
 		if err != nil {
@@ -254,6 +263,7 @@ func (job *UploadJobT) syncRemoteSchema() (schemaChanged bool, err error) {
 		warehouse:    job.warehouse,
 		stagingFiles: job.stagingFiles,
 		dbHandle:     job.dbHandle,
+		schemaPolicy: getSchemaPolicy(job.warehouse),
 	}
 	job.schemaHandle = &schemaHandle
 	schemaHandle.localSchema = schemaHandle.getLocalSchema()
@@ -831,6 +841,19 @@ func (job *UploadJobT) updateTableSchema(tName string, tableSchemaDiff warehouse
 		}
 	}
 
+	if err != nil {
+		return err
+	}
+
+	for columnName, columnType := range tableSchemaDiff.ColumnTypesToBeWidened {
+		err = job.whManager.AlterColumn(tName, columnName, columnType)
+		if err != nil {
+			pkgLogger.Errorf("Widening column %s in table: %s.%s to %s failed. Error: %v", columnName, job.warehouse.Namespace, tName, columnType, err)
+			break
+		}
+		job.counterStat("columns_widened").Increment()
+	}
+
 	return err
 }
 
@@ -914,7 +937,7 @@ func (job *UploadJobT) loadAllTablesExcept(skipLoadForTables []string, loadFiles
 }
 
 func (job *UploadJobT) updateSchema(tName string) (alteredSchema bool, err error) {
-	tableSchemaDiff := getTableSchemaDiff(tName, job.schemaHandle.schemaInWarehouse, job.upload.UploadSchema)
+	tableSchemaDiff := getTableSchemaDiff(tName, job.schemaHandle.schemaInWarehouse, job.upload.UploadSchema, job.schemaHandle.schemaPolicy.widenColumnTypes)
 	if tableSchemaDiff.Exists {
 		err = job.updateTableSchema(tName, tableSchemaDiff)
 		if err != nil {
@@ -953,6 +976,15 @@ func (job *UploadJobT) loadTable(tName string) (alteredSchema bool, err error) {
 		return
 	}
 
+	// the keys of tables in upsert mode should be columns of the table, as they are loaded by merging on them
+	if upsertConfig, ok := warehouseutils.GetUpsertConfig(job.warehouse, tName); ok {
+		err = upsertConfig.Validate(tName, job.GetTableSchemaInWarehouse(tName))
+		if err != nil {
+			tableUpload.setError(TableUploadExportingFailed, err)
+			return
+		}
+	}
+
 	pkgLogger.Infof(`[WH]: Starting load for table %s in namespace %s of destination %s:%s`, tName, job.warehouse.Namespace, job.warehouse.Type, job.warehouse.Destination.ID)
 	tableUpload.setStatus(TableUploadExecuting)
 
@@ -1098,7 +1130,7 @@ func (job *UploadJobT) loadIdentityTables(populateHistoricIdentities bool) (load
 		errorMap[tableName] = nil
 		tableUpload := NewTableUpload(job.upload.ID, tableName)
 
-		tableSchemaDiff := getTableSchemaDiff(tableName, job.schemaHandle.schemaInWarehouse, job.upload.UploadSchema)
+		tableSchemaDiff := getTableSchemaDiff(tableName, job.schemaHandle.schemaInWarehouse, job.upload.UploadSchema, job.schemaHandle.schemaPolicy.widenColumnTypes)
 		if tableSchemaDiff.Exists {
 			err := job.updateTableSchema(tableName, tableSchemaDiff)
 			if err != nil {
@@ -1263,6 +1295,36 @@ func (job *UploadJobT) setUploadSchema(consolidatedSchema warehouseutils.SchemaT
 	return job.setUploadColumns(UploadColumnsOpts{Fields: []UploadColumnT{{Column: UploadSchemaField, Value: marshalledSchema}}})
 }
 
+// setSchemaChanges reports the schema changes of the upload in its metadata
+func (job *UploadJobT) setSchemaChanges(schemaChanges schemaChangesT) error {
+	marshalledChanges, err := json.Marshal(schemaChanges)
+	if err != nil {
+		return err
+	}
+	if schemaChanges.DryRun {
+		pkgLogger.Infof("[WH]: Dry run of schema changes for upload %d in namespace %s of destination %s:%s: %s", job.upload.ID, job.warehouse.Namespace, job.warehouse.Type, job.warehouse.Destination.ID, marshalledChanges)
+	} else {
+		pkgLogger.Debugf("[WH]: Schema changes for upload %d in namespace %s of destination %s:%s: %s", job.upload.ID, job.warehouse.Namespace, job.warehouse.Type, job.warehouse.Destination.ID, marshalledChanges)
+	}
+
+	var metadata map[string]interface{}
+	unmarshallErr := json.Unmarshal(job.upload.Metadata, &metadata)
+	if unmarshallErr != nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata["schema_changes"] = schemaChanges
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	err = job.setUploadColumns(UploadColumnsOpts{Fields: []UploadColumnT{{Column: "metadata", Value: metadataJSON}}})
+	if err != nil {
+		return err
+	}
+	job.upload.Metadata = metadataJSON
+	return nil
+}
+
 func (job *UploadJobT) setMergedSchema(mergedSchema warehouseutils.SchemaT) error {
 	marshalledSchema, err := json.Marshal(mergedSchema)
 	if err != nil {
@@ -1793,7 +1855,7 @@ func (job *UploadJobT) createLoadFiles(generateAll bool) (startLoadFileID, endLo
 		}
 
 		schema := &job.upload.UploadSchema
-		if job.upload.LoadFileType == warehouseutils.LOAD_FILE_TYPE_PARQUET {
+		if job.upload.LoadFileType == warehouseutils.LOAD_FILE_TYPE_PARQUET || job.upload.LoadFileType == warehouseutils.LOAD_FILE_TYPE_ORC {
 			schema = &job.upload.MergedSchema
 		}
 
@@ -1912,7 +1974,7 @@ func (job *UploadJobT) bulkInsertLoadFileRecords(loadFiles []loadFileUploadOutpu
 	defer stmt.Close()
 
 	for _, loadFile := range loadFiles {
-		metadata := fmt.Sprintf(`{"content_length": %d, "destination_revision_id": %q, "use_rudder_storage": %t}`, loadFile.ContentLength, loadFile.DestinationRevisionID, loadFile.UseRudderStorage)
+		metadata := fmt.Sprintf(`{"content_length": %d, "total_rows": %d, "destination_revision_id": %q, "use_rudder_storage": %t}`, loadFile.ContentLength, loadFile.TotalRows, loadFile.DestinationRevisionID, loadFile.UseRudderStorage)
 		_, err = stmt.Exec(loadFile.StagingFileID, loadFile.Location, job.upload.SourceID, job.upload.DestinationID, job.upload.DestinationType, loadFile.TableName, loadFile.TotalRows, timeutil.Now(), metadata)
 		if err != nil {
 			pkgLogger.Errorf(`[WH]: Error copying row in pq.CopyIn for loadFules: %v Error: %v`, loadFile, err)
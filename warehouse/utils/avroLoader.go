@@ -0,0 +1,91 @@
+package warehouseutils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+// AvroLoader is used for generating avro load files. Unlike parquet load files, the values of the columns are written by
+// name, as the load time columns of some warehouses are not added in the order of the columns.
+type AvroLoader struct {
+	destType   string
+	columnData map[string]interface{}
+	fileWriter LoadFileWriterI
+}
+
+func NewAvroLoader(destType string, w LoadFileWriterI) *AvroLoader {
+	loader := &AvroLoader{destType: destType, fileWriter: w}
+	loader.columnData = make(map[string]interface{})
+	return loader
+}
+
+func (loader *AvroLoader) IsLoadTimeColumn(columnName string) bool {
+	return columnName == ToProviderCase(loader.destType, UUID_TS_COLUMN) || (loader.destType == BQ && columnName == LOADED_AT_COLUMN)
+}
+
+func (loader *AvroLoader) GetLoadTimeFomat(columnName string) string {
+	return misc.RFC3339Milli
+}
+
+func (loader *AvroLoader) AddColumn(columnName, colType string, val interface{}) {
+	var err error
+	if val != nil {
+		val, err = GetAvroValue(val, colType, loader.destType)
+		if err != nil {
+			// make val nil to avoid writing zero values to the avro file
+			pkgLogger.Debugf("[AvroLoader]: Error converting value of column %s to avro: %v", columnName, err)
+			val = nil
+		}
+	}
+	loader.columnData[ToProviderCase(loader.destType, columnName)] = val
+}
+
+func (loader *AvroLoader) AddRow(columnNames, row []string) {
+	for i, columnName := range columnNames {
+		loader.columnData[ToProviderCase(loader.destType, columnName)] = row[i]
+	}
+}
+
+func (loader *AvroLoader) AddEmptyColumn(columnName string) {
+	loader.AddColumn(columnName, "", nil)
+}
+
+func (loader *AvroLoader) WriteToString() (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (loader *AvroLoader) Write() error {
+	avroWriter, ok := loader.fileWriter.(*AvroWriter)
+	if !ok {
+		return errors.New("avro load files can only be written with an avro writer")
+	}
+	return avroWriter.WriteRecord(loader.columnData)
+}
+
+// GetAvroValue converts the value of a column of type colType to the type of the column in the avro load files of destType
+func GetAvroValue(val interface{}, colType, destType string) (retVal interface{}, err error) {
+	switch colType {
+	case "bigint", "int":
+		retVal, err = getInt64(val)
+		return
+	case "boolean":
+		retVal, err = getBool(val)
+		return
+	case "float":
+		retVal, err = getFloat64(val)
+		return
+	case "datetime":
+		if _, ok := rudderDataTypeToAvroDataType[destType][colType].(string); ok {
+			retVal, err = getString(val)
+			return
+		}
+		retVal, err = getUnixTimestamp(val)
+		return
+	case "string", "text", "json":
+		retVal, err = getString(val)
+		return
+	}
+	return nil, fmt.Errorf("unsupported type for avro: %s", colType)
+}
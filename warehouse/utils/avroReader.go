@@ -0,0 +1,59 @@
+package warehouseutils
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/linkedin/goavro"
+)
+
+type AvroReader struct {
+	r         io.Reader
+	destType  string
+	ocfReader *goavro.OCFReader
+}
+
+// Read returns the values of the columns of the next record of the avro load file. Null values are returned as empty strings.
+func (avro *AvroReader) Read(columnNames []string) (record []string, err error) {
+	if avro.ocfReader == nil {
+		avro.ocfReader, err = goavro.NewOCFReader(avro.r)
+		if err != nil {
+			return
+		}
+	}
+	if !avro.ocfReader.Scan() {
+		err = avro.ocfReader.Err()
+		if err != nil {
+			return
+		}
+		return []string{}, io.EOF
+	}
+
+	datum, err := avro.ocfReader.Read()
+	if err != nil {
+		return
+	}
+	columnData, ok := datum.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("avro load file record is not a record: %v", datum)
+	}
+	for _, columnName := range columnNames {
+		var value string
+		switch val := columnData[ToProviderCase(avro.destType, columnName)].(type) {
+		case nil:
+		case map[string]interface{}:
+			// values of nullable columns are unions with null
+			for _, unionVal := range val {
+				value = fmt.Sprintf("%v", unionVal)
+			}
+		default:
+			value = fmt.Sprintf("%v", val)
+		}
+		record = append(record, value)
+	}
+	return
+}
+
+func NewAvroReader(r io.Reader, destType string) *AvroReader {
+	return &AvroReader{r: r, destType: destType}
+}
@@ -0,0 +1,172 @@
+package warehouseutils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/linkedin/goavro"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+var (
+	avroLong            = "long"
+	avroDouble          = "double"
+	avroBoolean         = "boolean"
+	avroString          = "string"
+	avroTimestampMicros = map[string]interface{}{"type": "long", "logicalType": "timestamp-micros"}
+)
+
+// rudderDataTypeToAvroDataType maps the rudder data types to the avro types of the columns of the avro load files of
+// the warehouses which support them. Snowflake doesn't convert avro logical types, so its timestamps are written as strings.
+var rudderDataTypeToAvroDataType = map[string]map[string]interface{}{
+	BQ: {
+		"int":      avroLong,
+		"boolean":  avroBoolean,
+		"float":    avroDouble,
+		"string":   avroString,
+		"datetime": avroTimestampMicros,
+	},
+	SNOWFLAKE: {
+		"int":      avroLong,
+		"bigint":   avroLong,
+		"boolean":  avroBoolean,
+		"float":    avroDouble,
+		"string":   avroString,
+		"json":     avroString,
+		"datetime": avroString,
+	},
+}
+
+type avroField struct {
+	name string
+	// typeName is the name of the type of the field in its union with null
+	typeName string
+}
+
+// AvroWriter writes the rows of a load file in an avro object container file, deflating blocks of avroRecordsPerBlock records
+type AvroWriter struct {
+	writer     *goavro.OCFWriter
+	fileWriter misc.BufferedWriter
+	fields     []avroField
+	records    []interface{}
+}
+
+func CreateAvroWriter(schema TableSchemaT, outputFilePath, destType string) (*AvroWriter, error) {
+	avroSchema, fields, err := getAvroSchema(schema, destType)
+	if err != nil {
+		return nil, err
+	}
+	bufWriter, err := misc.CreateBufferedWriter(outputFilePath)
+	if err != nil {
+		return nil, err
+	}
+	w, err := goavro.NewOCFWriter(goavro.OCFConfig{
+		W:               bufWriter,
+		Schema:          avroSchema,
+		CompressionName: goavro.CompressionDeflateLabel,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &AvroWriter{
+		writer:     w,
+		fileWriter: bufWriter,
+		fields:     fields,
+	}, nil
+}
+
+// WriteRow writes a row with the values of the columns sorted by name
+func (a *AvroWriter) WriteRow(row []interface{}) error {
+	if len(row) != len(a.fields) {
+		return fmt.Errorf("row has %d values for %d columns", len(row), len(a.fields))
+	}
+	record := make(map[string]interface{}, len(a.fields))
+	for i, field := range a.fields {
+		record[field.name] = row[i]
+	}
+	return a.WriteRecord(record)
+}
+
+// WriteRecord writes a row with the values of its columns by name. Columns without a value are written as null.
+func (a *AvroWriter) WriteRecord(values map[string]interface{}) error {
+	record := make(map[string]interface{}, len(a.fields))
+	for _, field := range a.fields {
+		if val, ok := values[field.name]; ok && val != nil {
+			record[field.name] = goavro.Union(field.typeName, val)
+			continue
+		}
+		record[field.name] = nil
+	}
+	a.records = append(a.records, record)
+	if len(a.records) >= avroRecordsPerBlock {
+		return a.flush()
+	}
+	return nil
+}
+
+func (a *AvroWriter) flush() error {
+	if len(a.records) == 0 {
+		return nil
+	}
+	err := a.writer.Append(a.records)
+	a.records = nil
+	return err
+}
+
+func (a *AvroWriter) Close() error {
+	err := a.flush()
+	if err != nil {
+		return err
+	}
+	// close the bufWriter
+	return a.fileWriter.Close()
+}
+
+func (a *AvroWriter) WriteGZ(s string) error {
+	return errors.New("not implemented")
+}
+
+func (a *AvroWriter) Write(b []byte) (int, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (a *AvroWriter) GetLoadFile() *os.File {
+	return a.fileWriter.GetFile()
+}
+
+func getAvroSchema(schema TableSchemaT, destType string) (string, []avroField, error) {
+	whTypeMap, ok := rudderDataTypeToAvroDataType[destType]
+	if !ok {
+		return "", nil, errors.New("unsupported warehouse for avro load files")
+	}
+	var fields []avroField
+	schemaFields := []interface{}{}
+	for _, col := range getSortedTableColumns(schema) {
+		avroType, ok := whTypeMap[schema[col]]
+		if !ok {
+			return "", nil, fmt.Errorf("unsupported data type %s of column %s for avro load files", schema[col], col)
+		}
+		typeName, ok := avroType.(string)
+		if !ok {
+			typeName = avroType.(map[string]interface{})["type"].(string)
+		}
+		name := ToProviderCase(destType, col)
+		fields = append(fields, avroField{name: name, typeName: typeName})
+		schemaFields = append(schemaFields, map[string]interface{}{
+			"name":    name,
+			"type":    []interface{}{"null", avroType},
+			"default": nil,
+		})
+	}
+	avroSchema, err := json.Marshal(map[string]interface{}{
+		"type":   "record",
+		"name":   "load_file",
+		"fields": schemaFields,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return string(avroSchema), fields, nil
+}
@@ -0,0 +1,115 @@
+package warehouseutils_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/linkedin/goavro"
+	"github.com/stretchr/testify/require"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	. "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestAvroLoadFiles(t *testing.T) {
+	receivedAt := "2022-01-20T13:39:21.033Z"
+	uuidTS := time.Date(2022, 1, 20, 13, 40, 0, 0, time.UTC)
+
+	testCases := []struct {
+		destType           string
+		expectedReceivedAt interface{}
+		expectedRecord     []string
+	}{
+		{
+			destType:           BQ,
+			expectedReceivedAt: map[string]interface{}{"long": int64(1642685961033000)},
+			expectedRecord:     []string{"id", "10", "", "true", "1642685961033000"},
+		},
+		{
+			destType:           SNOWFLAKE,
+			expectedReceivedAt: map[string]interface{}{"string": receivedAt},
+			expectedRecord:     []string{"id", "10", "", "true", receivedAt},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.destType, func(t *testing.T) {
+			schema := TableSchemaT{
+				ToProviderCase(tc.destType, "id"):          "string",
+				ToProviderCase(tc.destType, "count"):       "int",
+				ToProviderCase(tc.destType, "price"):       "float",
+				ToProviderCase(tc.destType, "active"):      "boolean",
+				ToProviderCase(tc.destType, "received_at"): "datetime",
+				ToProviderCase(tc.destType, "uuid_ts"):     "datetime",
+			}
+			outputFilePath := filepath.Join(t.TempDir(), "tracks.avro")
+			writer, err := CreateAvroWriter(schema, outputFilePath, tc.destType)
+			require.NoError(t, err)
+
+			for i := 0; i < 3; i++ {
+				loader := GetNewEventLoader(tc.destType, LOAD_FILE_TYPE_AVRO, writer)
+				require.True(t, loader.IsLoadTimeColumn(ToProviderCase(tc.destType, "uuid_ts")))
+				loader.AddColumn("active", "boolean", true)
+				loader.AddColumn("count", "int", 10)
+				loader.AddColumn("id", "string", "id")
+				loader.AddEmptyColumn("price")
+				loader.AddColumn("received_at", "datetime", receivedAt)
+				loader.AddColumn("uuid_ts", "datetime", uuidTS.Format(loader.GetLoadTimeFomat("uuid_ts")))
+				require.NoError(t, loader.Write())
+			}
+			require.NoError(t, writer.Close())
+
+			file, err := os.Open(outputFilePath)
+			require.NoError(t, err)
+			defer file.Close()
+			ocfReader, err := goavro.NewOCFReader(file)
+			require.NoError(t, err)
+			require.True(t, ocfReader.Scan())
+			datum, err := ocfReader.Read()
+			require.NoError(t, err)
+			record := datum.(map[string]interface{})
+			require.Equal(t, tc.expectedReceivedAt, record[ToProviderCase(tc.destType, "received_at")])
+			require.Nil(t, record[ToProviderCase(tc.destType, "price")])
+
+			_, err = file.Seek(0, io.SeekStart)
+			require.NoError(t, err)
+			reader := NewEventReader(file, tc.destType, LOAD_FILE_TYPE_AVRO)
+			columnNames := []string{"id", "count", "price", "active", "received_at"}
+			for i := 0; i < 3; i++ {
+				record, err := reader.Read(columnNames)
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedRecord, record)
+			}
+			_, err = reader.Read(columnNames)
+			require.Equal(t, io.EOF, err)
+		})
+	}
+}
+
+func TestCreateAvroWriterWithUnsupportedWarehouse(t *testing.T) {
+	_, err := CreateAvroWriter(TableSchemaT{"id": "string"}, filepath.Join(t.TempDir(), "tracks.avro"), POSTGRES)
+	require.EqualError(t, err, "unsupported warehouse for avro load files")
+}
+
+func TestGetConfiguredLoadFileType(t *testing.T) {
+	warehouse := func(whType, loadFileType string) WarehouseT {
+		return WarehouseT{
+			Type: whType,
+			Destination: backendconfig.DestinationT{
+				Config: map[string]interface{}{"loadFileType": loadFileType},
+			},
+		}
+	}
+	require.Equal(t, LOAD_FILE_TYPE_AVRO, GetConfiguredLoadFileType(warehouse(BQ, "avro")))
+	require.Equal(t, LOAD_FILE_TYPE_AVRO, GetConfiguredLoadFileType(warehouse(SNOWFLAKE, "avro")))
+	require.Equal(t, LOAD_FILE_TYPE_JSON, GetConfiguredLoadFileType(warehouse(BQ, "")))
+	require.Equal(t, LOAD_FILE_TYPE_CSV, GetConfiguredLoadFileType(warehouse(POSTGRES, "avro")))
+	require.Equal(t, "avro", GetLoadFileFormatForType(SNOWFLAKE, LOAD_FILE_TYPE_AVRO))
+	require.Equal(t, "csv.gz", GetLoadFileFormatForType(SNOWFLAKE, LOAD_FILE_TYPE_CSV))
+	require.Equal(t, LOAD_FILE_TYPE_ORC, GetConfiguredLoadFileType(warehouse(S3_DATALAKE, "orc")))
+	require.Equal(t, LOAD_FILE_TYPE_PARQUET, GetConfiguredLoadFileType(warehouse(S3_DATALAKE, "avro")))
+	require.Equal(t, LOAD_FILE_TYPE_CSV, GetConfiguredLoadFileType(warehouse(SNOWFLAKE, "orc")))
+	require.Equal(t, "orc", GetLoadFileFormatForType(GCS_DATALAKE, LOAD_FILE_TYPE_ORC))
+}
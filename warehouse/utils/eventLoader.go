@@ -18,6 +18,10 @@ func GetNewEventLoader(destinationType, loadFileType string, w LoadFileWriterI)
 		return NewJSONLoader(destinationType, w)
 	case LOAD_FILE_TYPE_PARQUET:
 		return NewParquetLoader(destinationType, w)
+	case LOAD_FILE_TYPE_AVRO:
+		return NewAvroLoader(destinationType, w)
+	case LOAD_FILE_TYPE_ORC:
+		return NewOrcLoader(destinationType, w)
 	default:
 		return NewCSVLoader(destinationType, w)
 	}
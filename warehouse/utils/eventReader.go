@@ -6,7 +6,13 @@ type eventReader interface {
 	Read(columnNames []string) (record []string, err error)
 }
 
-func NewEventReader(r io.Reader, provider string) eventReader {
+func NewEventReader(r io.Reader, provider, loadFileType string) eventReader {
+	switch loadFileType {
+	case LOAD_FILE_TYPE_AVRO:
+		return NewAvroReader(r, provider)
+	case LOAD_FILE_TYPE_ORC:
+		return NewOrcReader(r, provider)
+	}
 	if provider == BQ {
 		return NewJSONReader(r)
 	}
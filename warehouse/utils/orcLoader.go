@@ -0,0 +1,84 @@
+package warehouseutils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// OrcLoader is used for generating orc load files. Like parquet load files, the values of the columns are written in
+// the order of the columns.
+type OrcLoader struct {
+	destType   string
+	values     []interface{}
+	fileWriter LoadFileWriterI
+}
+
+func NewOrcLoader(destType string, w LoadFileWriterI) *OrcLoader {
+	return &OrcLoader{destType: destType, fileWriter: w}
+}
+
+func (loader *OrcLoader) IsLoadTimeColumn(columnName string) bool {
+	return columnName == ToProviderCase(loader.destType, UUID_TS_COLUMN)
+}
+
+func (loader *OrcLoader) GetLoadTimeFomat(columnName string) string {
+	return time.RFC3339
+}
+
+func (loader *OrcLoader) AddColumn(columnName, colType string, val interface{}) {
+	var err error
+	if val != nil {
+		val, err = GetOrcValue(val, colType)
+		if err != nil {
+			// make val nil to avoid writing zero values to the orc file
+			pkgLogger.Debugf("[OrcLoader]: Error converting value of column %s to orc: %v", columnName, err)
+			val = nil
+		}
+	}
+	loader.values = append(loader.values, val)
+}
+
+func (loader *OrcLoader) AddRow(columnNames, row []string) {
+	for _, value := range row {
+		loader.values = append(loader.values, value)
+	}
+}
+
+func (loader *OrcLoader) AddEmptyColumn(columnName string) {
+	loader.AddColumn(columnName, "", nil)
+}
+
+func (loader *OrcLoader) WriteToString() (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (loader *OrcLoader) Write() error {
+	return loader.fileWriter.WriteRow(loader.values)
+}
+
+// GetOrcValue converts the value of a column of type colType to the type of the column in the orc load files
+func GetOrcValue(val interface{}, colType string) (retVal interface{}, err error) {
+	switch colType {
+	case "bigint", "int":
+		retVal, err = getInt64(val)
+		return
+	case "boolean":
+		retVal, err = getBool(val)
+		return
+	case "float":
+		retVal, err = getFloat64(val)
+		return
+	case "datetime":
+		tsString, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("%v is not a valid timestamp string", val)
+		}
+		retVal, err = time.Parse(time.RFC3339, tsString)
+		return
+	case "string", "text":
+		retVal, err = getString(val)
+		return
+	}
+	return nil, fmt.Errorf("unsupported type for orc: %s", colType)
+}
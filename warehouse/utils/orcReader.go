@@ -0,0 +1,488 @@
+package warehouseutils
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+// OrcReader reads the rows of orc load files with the columns written by OrcWriter. As the metadata of orc files is at
+// their end, the whole file is read in memory.
+type OrcReader struct {
+	r           io.Reader
+	destType    string
+	data        []byte
+	compression uint64
+	columns     map[string]int
+	kinds       []uint64
+	stripes     []orcFieldsT
+	rows        [][]string
+	row         int
+}
+
+// Read returns the values of the columns of the next row of the orc load file. Null values are returned as empty strings.
+func (orc *OrcReader) Read(columnNames []string) (record []string, err error) {
+	if orc.data == nil {
+		if err = orc.readFooter(); err != nil {
+			return
+		}
+	}
+	for orc.row >= len(orc.rows) {
+		if len(orc.stripes) == 0 {
+			return []string{}, io.EOF
+		}
+		if orc.rows, err = orc.readStripe(orc.stripes[0]); err != nil {
+			return
+		}
+		orc.stripes = orc.stripes[1:]
+		orc.row = 0
+	}
+	row := orc.rows[orc.row]
+	orc.row++
+	for _, columnName := range columnNames {
+		var value string
+		if i, ok := orc.columns[ToProviderCase(orc.destType, columnName)]; ok {
+			value = row[i]
+		}
+		record = append(record, value)
+	}
+	return
+}
+
+func (orc *OrcReader) readFooter() error {
+	data, err := io.ReadAll(orc.r)
+	if err != nil {
+		return err
+	}
+	if len(data) <= len(orcMagic) || !bytes.HasPrefix(data, []byte(orcMagic)) {
+		return errors.New("orc load file doesn't start with the orc magic")
+	}
+	postScriptEnd := len(data) - 1
+	postScriptStart := postScriptEnd - int(data[postScriptEnd])
+	if postScriptStart < len(orcMagic) {
+		return errors.New("orc load file is truncated")
+	}
+	postScript, err := orcFields(data[postScriptStart:postScriptEnd])
+	if err != nil {
+		return fmt.Errorf("parsing orc postscript: %w", err)
+	}
+	orc.compression = postScript.uint(2)
+	if orc.compression != orcCompressionNone && orc.compression != orcCompressionZlib {
+		return fmt.Errorf("unsupported orc compression kind %d", orc.compression)
+	}
+	footerStart := postScriptStart - int(postScript.uint(1))
+	if footerStart < len(orcMagic) {
+		return errors.New("orc load file is truncated")
+	}
+	footerData, err := orc.decompress(data[footerStart:postScriptStart])
+	if err != nil {
+		return err
+	}
+	footer, err := orcFields(footerData)
+	if err != nil {
+		return fmt.Errorf("parsing orc footer: %w", err)
+	}
+
+	var types []orcFieldsT
+	for _, typeData := range footer.messages(4) {
+		orcType, err := orcFields(typeData)
+		if err != nil {
+			return fmt.Errorf("parsing orc type: %w", err)
+		}
+		types = append(types, orcType)
+	}
+	if len(types) == 0 || types[0].uint(1) != orcStruct {
+		return errors.New("orc load file rows are not structs")
+	}
+	fieldNames := types[0].messages(3)
+	subtypes := types[0].uints(2)
+	if len(fieldNames) != len(subtypes) {
+		return errors.New("orc load file has struct fields without names")
+	}
+	orc.columns = make(map[string]int, len(subtypes))
+	orc.kinds = make([]uint64, len(types))
+	for i, subtype := range subtypes {
+		if subtype == 0 || subtype >= uint64(len(types)) {
+			return fmt.Errorf("orc load file has an invalid type %d", subtype)
+		}
+		orc.columns[string(fieldNames[i])] = int(subtype)
+		orc.kinds[subtype] = types[subtype].uint(1)
+	}
+	for _, stripeData := range footer.messages(3) {
+		stripe, err := orcFields(stripeData)
+		if err != nil {
+			return fmt.Errorf("parsing orc stripe information: %w", err)
+		}
+		orc.stripes = append(orc.stripes, stripe)
+	}
+	orc.data = data
+	return nil
+}
+
+// readStripe returns the rows of a stripe, with the values of the columns of every type, by column id
+func (orc *OrcReader) readStripe(stripe orcFieldsT) ([][]string, error) {
+	offset, indexLength, dataLength, footerLength := stripe.uint(1), stripe.uint(2), stripe.uint(3), stripe.uint(4)
+	rowsCount := int(stripe.uint(5))
+	footerStart := offset + indexLength + dataLength
+	if footerStart+footerLength > uint64(len(orc.data)) {
+		return nil, errors.New("orc load file is truncated")
+	}
+	footerData, err := orc.decompress(orc.data[footerStart : footerStart+footerLength])
+	if err != nil {
+		return nil, err
+	}
+	footer, err := orcFields(footerData)
+	if err != nil {
+		return nil, fmt.Errorf("parsing orc stripe footer: %w", err)
+	}
+	for _, encodingData := range footer.messages(2) {
+		encoding, err := orcFields(encodingData)
+		if err != nil {
+			return nil, fmt.Errorf("parsing orc column encoding: %w", err)
+		}
+		if encoding.uint(1) != orcEncodingDirect {
+			return nil, fmt.Errorf("unsupported orc column encoding kind %d", encoding.uint(1))
+		}
+	}
+
+	// streams of every column by kind
+	streams := make([]map[uint64][]byte, len(orc.kinds))
+	position := offset
+	for _, streamData := range footer.messages(1) {
+		stream, err := orcFields(streamData)
+		if err != nil {
+			return nil, fmt.Errorf("parsing orc stream: %w", err)
+		}
+		kind, column, length := stream.uint(1), stream.uint(2), stream.uint(3)
+		if position+length > footerStart {
+			return nil, errors.New("orc load file is truncated")
+		}
+		if column < uint64(len(streams)) {
+			if streams[column] == nil {
+				streams[column] = map[uint64][]byte{}
+			}
+			if streams[column][kind], err = orc.decompress(orc.data[position : position+length]); err != nil {
+				return nil, err
+			}
+		}
+		position += length
+	}
+
+	rows := make([][]string, rowsCount)
+	for i := range rows {
+		rows[i] = make([]string, len(orc.kinds))
+	}
+	for column := 1; column < len(orc.kinds); column++ {
+		values, err := orcColumnValues(orc.kinds[column], streams[column], rowsCount)
+		if err != nil {
+			return nil, fmt.Errorf("reading orc column %d: %w", column, err)
+		}
+		for i, value := range values {
+			rows[i][column] = value
+		}
+	}
+	return rows, nil
+}
+
+// orcColumnValues returns the values of the rows of a column from its streams
+func orcColumnValues(kind uint64, streams map[uint64][]byte, rowsCount int) ([]string, error) {
+	present := make([]bool, rowsCount)
+	valuesCount := rowsCount
+	if presentStream, ok := streams[orcStreamPresent]; ok {
+		var err error
+		if present, err = orcDecodeBooleans(presentStream, rowsCount); err != nil {
+			return nil, err
+		}
+		valuesCount = 0
+		for _, isPresent := range present {
+			if isPresent {
+				valuesCount++
+			}
+		}
+	} else {
+		for i := range present {
+			present[i] = true
+		}
+	}
+
+	values := make([]string, valuesCount)
+	switch kind {
+	case orcLong:
+		integers, err := orcDecodeIntegers(streams[orcStreamData], valuesCount, true)
+		if err != nil {
+			return nil, err
+		}
+		for i, integer := range integers {
+			values[i] = strconv.FormatInt(integer, 10)
+		}
+	case orcBoolean:
+		booleans, err := orcDecodeBooleans(streams[orcStreamData], valuesCount)
+		if err != nil {
+			return nil, err
+		}
+		for i, boolean := range booleans {
+			values[i] = strconv.FormatBool(boolean)
+		}
+	case orcDouble:
+		doubles := streams[orcStreamData]
+		if len(doubles) < 8*valuesCount {
+			return nil, errors.New("orc double stream ended early")
+		}
+		for i := range values {
+			values[i] = strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(doubles[8*i:])), 'f', -1, 64)
+		}
+	case orcString:
+		lengths, err := orcDecodeIntegers(streams[orcStreamLength], valuesCount, false)
+		if err != nil {
+			return nil, err
+		}
+		stringsData := streams[orcStreamData]
+		for i, length := range lengths {
+			if length < 0 || int(length) > len(stringsData) {
+				return nil, errors.New("orc string stream ended early")
+			}
+			values[i], stringsData = string(stringsData[:length]), stringsData[length:]
+		}
+	case orcTimestamp:
+		seconds, err := orcDecodeIntegers(streams[orcStreamData], valuesCount, true)
+		if err != nil {
+			return nil, err
+		}
+		nanos, err := orcDecodeIntegers(streams[orcStreamSecondary], valuesCount, false)
+		if err != nil {
+			return nil, err
+		}
+		for i := range values {
+			values[i] = time.Unix(seconds[i]+orcTimestampBase, orcParseNanos(nanos[i])).UTC().Format(misc.RFC3339Milli)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported orc type kind %d", kind)
+	}
+
+	rowValues := make([]string, rowsCount)
+	for i := range rowValues {
+		if present[i] {
+			rowValues[i], values = values[0], values[1:]
+		}
+	}
+	return rowValues, nil
+}
+
+// decompress returns the data of a stream, a footer or a stripe footer, from its compressed chunks
+func (orc *OrcReader) decompress(data []byte) ([]byte, error) {
+	if orc.compression == orcCompressionNone {
+		return data, nil
+	}
+	var decompressed bytes.Buffer
+	for len(data) > 0 {
+		if len(data) < 3 {
+			return nil, errors.New("orc compressed chunk header is truncated")
+		}
+		header := int(data[0]) | int(data[1])<<8 | int(data[2])<<16
+		length, isOriginal := header>>1, header&1 == 1
+		data = data[3:]
+		if length > len(data) {
+			return nil, errors.New("orc compressed chunk is truncated")
+		}
+		if isOriginal {
+			decompressed.Write(data[:length])
+		} else {
+			r := flate.NewReader(bytes.NewReader(data[:length]))
+			_, err := io.Copy(&decompressed, r)
+			r.Close()
+			if err != nil {
+				return nil, fmt.Errorf("inflating orc compressed chunk: %w", err)
+			}
+		}
+		data = data[length:]
+	}
+	return decompressed.Bytes(), nil
+}
+
+// orcDecodeIntegers decodes count integers encoded with the version 1 of the run length encoding of integers
+func orcDecodeIntegers(data []byte, count int, signed bool) ([]int64, error) {
+	values := make([]int64, 0, count)
+	readValue := func() (int64, error) {
+		value, n := protowire.ConsumeVarint(data)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		data = data[n:]
+		if signed {
+			return protowire.DecodeZigZag(value), nil
+		}
+		return int64(value), nil
+	}
+	for len(values) < count {
+		if len(data) == 0 {
+			return nil, errors.New("orc integer stream ended early")
+		}
+		header := int8(data[0])
+		data = data[1:]
+		if header < 0 {
+			for i := 0; i < -int(header); i++ {
+				value, err := readValue()
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, value)
+			}
+			continue
+		}
+		if len(data) == 0 {
+			return nil, errors.New("orc integer stream ended early")
+		}
+		delta := int64(int8(data[0]))
+		data = data[1:]
+		base, err := readValue()
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < int(header)+3; i++ {
+			values = append(values, base+int64(i)*delta)
+		}
+	}
+	return values[:count], nil
+}
+
+// orcDecodeBytes decodes count bytes encoded with the run length encoding of bytes
+func orcDecodeBytes(data []byte, count int) ([]byte, error) {
+	values := make([]byte, 0, count)
+	for len(values) < count {
+		if len(data) < 2 {
+			return nil, errors.New("orc byte stream ended early")
+		}
+		header := int8(data[0])
+		data = data[1:]
+		if header >= 0 {
+			for i := 0; i < int(header)+3; i++ {
+				values = append(values, data[0])
+			}
+			data = data[1:]
+			continue
+		}
+		n := -int(header)
+		if n > len(data) {
+			return nil, errors.New("orc byte stream ended early")
+		}
+		values = append(values, data[:n]...)
+		data = data[n:]
+	}
+	return values[:count], nil
+}
+
+// orcDecodeBooleans decodes count booleans from the bits of bytes encoded with the run length encoding of bytes
+func orcDecodeBooleans(data []byte, count int) ([]bool, error) {
+	bits, err := orcDecodeBytes(data, (count+7)/8)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]bool, count)
+	for i := range values {
+		values[i] = bits[i/8]&(0x80>>(i%8)) != 0
+	}
+	return values, nil
+}
+
+// orcParseNanos decodes the nanoseconds of a timestamp encoded by orcFormatNanos
+func orcParseNanos(serialized int64) int64 {
+	nanos := serialized >> 3
+	if zeros := serialized & 7; zeros != 0 {
+		for i := int64(0); i <= zeros; i++ {
+			nanos *= 10
+		}
+	}
+	return nanos
+}
+
+// orcFieldsT are the values of the fields of a protobuf message: varints as uint64, length delimited fields as []byte
+type orcFieldsT map[protowire.Number][]interface{}
+
+func orcFields(message []byte) (orcFieldsT, error) {
+	fields := orcFieldsT{}
+	for len(message) > 0 {
+		number, wireType, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		message = message[n:]
+		switch wireType {
+		case protowire.VarintType:
+			var value uint64
+			value, n = protowire.ConsumeVarint(message)
+			if n >= 0 {
+				fields[number] = append(fields[number], value)
+			}
+		case protowire.BytesType:
+			var value []byte
+			value, n = protowire.ConsumeBytes(message)
+			if n >= 0 {
+				fields[number] = append(fields[number], value)
+			}
+		default:
+			n = protowire.ConsumeFieldValue(number, wireType, message)
+		}
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		message = message[n:]
+	}
+	return fields, nil
+}
+
+// uint returns the last value of a varint field, or 0 if the field is not set
+func (fields orcFieldsT) uint(number protowire.Number) uint64 {
+	var value uint64
+	for _, field := range fields[number] {
+		if v, ok := field.(uint64); ok {
+			value = v
+		}
+	}
+	return value
+}
+
+// uints returns the values of a repeated varint field, packed or not
+func (fields orcFieldsT) uints(number protowire.Number) []uint64 {
+	var values []uint64
+	for _, field := range fields[number] {
+		switch v := field.(type) {
+		case uint64:
+			values = append(values, v)
+		case []byte:
+			for len(v) > 0 {
+				value, n := protowire.ConsumeVarint(v)
+				if n < 0 {
+					break
+				}
+				values = append(values, value)
+				v = v[n:]
+			}
+		}
+	}
+	return values
+}
+
+// messages returns the values of a repeated length delimited field
+func (fields orcFieldsT) messages(number protowire.Number) [][]byte {
+	var values [][]byte
+	for _, field := range fields[number] {
+		if v, ok := field.([]byte); ok {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func NewOrcReader(r io.Reader, destType string) *OrcReader {
+	return &OrcReader{r: r, destType: destType}
+}
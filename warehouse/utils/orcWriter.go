@@ -0,0 +1,443 @@
+package warehouseutils
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+// the kinds of the types, streams, encodings and compressions of orc files, see https://orc.apache.org/specification/ORCv1/
+const (
+	orcBoolean   = 0
+	orcLong      = 4
+	orcDouble    = 6
+	orcString    = 7
+	orcTimestamp = 9
+	orcStruct    = 12
+
+	orcStreamPresent   = 0
+	orcStreamData      = 1
+	orcStreamLength    = 2
+	orcStreamSecondary = 5
+
+	orcEncodingDirect = 0
+
+	orcCompressionNone = 0
+	orcCompressionZlib = 1
+
+	orcMagic                = "ORC"
+	orcCompressionBlockSize = 256 * 1024
+	// orcWriterVersion is the version of the writer fixing HIVE-8732, the first one of the 0.12 file format
+	orcWriterVersion = 1
+	orcTimezone      = "UTC"
+	// orcLiteralsLength is the maximum number of values of a literal run of the run length encodings
+	orcLiteralsLength = 128
+)
+
+// orcTimestampBase is the base of the seconds of the orc timestamps, 2015-01-01 00:00:00 in the timezone of the writer
+var orcTimestampBase = time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+var orcFileVersion = []uint64{0, 12}
+
+var rudderDataTypeToOrcDataType = map[string]map[string]uint64{
+	S3_DATALAKE: {
+		"bigint":   orcLong,
+		"int":      orcLong,
+		"boolean":  orcBoolean,
+		"float":    orcDouble,
+		"string":   orcString,
+		"text":     orcString,
+		"datetime": orcTimestamp,
+	},
+	GCS_DATALAKE: {
+		"int":      orcLong,
+		"boolean":  orcBoolean,
+		"float":    orcDouble,
+		"string":   orcString,
+		"datetime": orcTimestamp,
+	},
+	AZURE_DATALAKE: {
+		"int":      orcLong,
+		"boolean":  orcBoolean,
+		"float":    orcDouble,
+		"string":   orcString,
+		"datetime": orcTimestamp,
+	},
+}
+
+type orcColumn struct {
+	name string
+	kind uint64
+}
+
+// OrcWriter writes the rows of a load file in an orc file, in stripes of orcRowsPerStripe rows. The columns are written
+// with the DIRECT encodings of the 0.12 file format and their streams are compressed with zlib. Timestamps are written
+// in UTC.
+type OrcWriter struct {
+	fileWriter misc.BufferedWriter
+	columns    []orcColumn
+	rows       [][]interface{}
+	// offset is the number of bytes written in the file
+	offset  uint64
+	stripes []orcMessage
+	// valuesCount and hasNull are the statistics of the columns, the first one being the struct of the rows
+	valuesCount []uint64
+	hasNull     []bool
+}
+
+func CreateOrcWriter(schema TableSchemaT, outputFilePath, destType string) (*OrcWriter, error) {
+	whTypeMap, ok := rudderDataTypeToOrcDataType[destType]
+	if !ok {
+		return nil, errors.New("unsupported warehouse for orc load files")
+	}
+	var columns []orcColumn
+	for _, col := range getSortedTableColumns(schema) {
+		kind, ok := whTypeMap[schema[col]]
+		if !ok {
+			return nil, fmt.Errorf("unsupported data type %s of column %s for orc load files", schema[col], col)
+		}
+		columns = append(columns, orcColumn{name: ToProviderCase(destType, col), kind: kind})
+	}
+	bufWriter, err := misc.CreateBufferedWriter(outputFilePath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bufWriter.Write([]byte(orcMagic)); err != nil {
+		return nil, err
+	}
+	return &OrcWriter{
+		fileWriter:  bufWriter,
+		columns:     columns,
+		offset:      uint64(len(orcMagic)),
+		valuesCount: make([]uint64, len(columns)+1),
+		hasNull:     make([]bool, len(columns)+1),
+	}, nil
+}
+
+// WriteRow writes a row with the values of the columns sorted by name: int64, bool, float64, string or time.Time
+// values depending on the types of the columns, or nil
+func (o *OrcWriter) WriteRow(row []interface{}) error {
+	if len(row) != len(o.columns) {
+		return fmt.Errorf("row has %d values for %d columns", len(row), len(o.columns))
+	}
+	for i, column := range o.columns {
+		var ok bool
+		switch row[i].(type) {
+		case nil:
+			ok = true
+		case int64:
+			ok = column.kind == orcLong
+		case bool:
+			ok = column.kind == orcBoolean
+		case float64:
+			ok = column.kind == orcDouble
+		case string:
+			ok = column.kind == orcString
+		case time.Time:
+			ok = column.kind == orcTimestamp
+		}
+		if !ok {
+			return fmt.Errorf("invalid value %v of type %T for column %s", row[i], row[i], column.name)
+		}
+	}
+	o.rows = append(o.rows, row)
+	if len(o.rows) >= orcRowsPerStripe {
+		return o.flush()
+	}
+	return nil
+}
+
+// flush writes the buffered rows in a stripe
+func (o *OrcWriter) flush() error {
+	if len(o.rows) == 0 {
+		return nil
+	}
+	var data bytes.Buffer
+	var footer orcMessage
+	addStream := func(kind, column uint64, stream []byte) error {
+		compressed, err := orcCompress(stream)
+		if err != nil {
+			return err
+		}
+		data.Write(compressed)
+		footer = footer.message(1, orcMessage{}.uint(1, kind).uint(2, column).uint(3, uint64(len(compressed))))
+		return nil
+	}
+
+	o.valuesCount[0] += uint64(len(o.rows))
+	for i, column := range o.columns {
+		columnID := uint64(i + 1)
+		present := make([]bool, len(o.rows))
+		var values []interface{}
+		for j, row := range o.rows {
+			if row[i] != nil {
+				present[j] = true
+				values = append(values, row[i])
+			}
+		}
+		o.valuesCount[columnID] += uint64(len(values))
+		if len(values) < len(o.rows) {
+			o.hasNull[columnID] = true
+			if err := addStream(orcStreamPresent, columnID, orcBooleans(present)); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		switch column.kind {
+		case orcLong:
+			integers := make([]int64, len(values))
+			for j, value := range values {
+				integers[j] = value.(int64)
+			}
+			err = addStream(orcStreamData, columnID, orcIntegers(integers, true))
+		case orcBoolean:
+			booleans := make([]bool, len(values))
+			for j, value := range values {
+				booleans[j] = value.(bool)
+			}
+			err = addStream(orcStreamData, columnID, orcBooleans(booleans))
+		case orcDouble:
+			doubles := make([]byte, 8*len(values))
+			for j, value := range values {
+				binary.LittleEndian.PutUint64(doubles[8*j:], math.Float64bits(value.(float64)))
+			}
+			err = addStream(orcStreamData, columnID, doubles)
+		case orcString:
+			var stringsData []byte
+			lengths := make([]int64, len(values))
+			for j, value := range values {
+				stringsData = append(stringsData, value.(string)...)
+				lengths[j] = int64(len(value.(string)))
+			}
+			if err = addStream(orcStreamData, columnID, stringsData); err == nil {
+				err = addStream(orcStreamLength, columnID, orcIntegers(lengths, false))
+			}
+		case orcTimestamp:
+			seconds := make([]int64, len(values))
+			nanos := make([]int64, len(values))
+			for j, value := range values {
+				seconds[j] = value.(time.Time).Unix() - orcTimestampBase
+				nanos[j] = orcFormatNanos(value.(time.Time).Nanosecond())
+			}
+			if err = addStream(orcStreamData, columnID, orcIntegers(seconds, true)); err == nil {
+				err = addStream(orcStreamSecondary, columnID, orcIntegers(nanos, false))
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for i := 0; i <= len(o.columns); i++ {
+		footer = footer.message(2, orcMessage{}.uint(1, orcEncodingDirect))
+	}
+	footer = footer.bytes(3, []byte(orcTimezone))
+	compressedFooter, err := orcCompress(footer)
+	if err != nil {
+		return err
+	}
+
+	o.stripes = append(o.stripes, orcMessage{}.
+		uint(1, o.offset).
+		uint(2, 0).
+		uint(3, uint64(data.Len())).
+		uint(4, uint64(len(compressedFooter))).
+		uint(5, uint64(len(o.rows))))
+	o.rows = nil
+	if _, err := o.fileWriter.Write(data.Bytes()); err != nil {
+		return err
+	}
+	if _, err := o.fileWriter.Write(compressedFooter); err != nil {
+		return err
+	}
+	o.offset += uint64(data.Len() + len(compressedFooter))
+	return nil
+}
+
+func (o *OrcWriter) Close() error {
+	err := o.flush()
+	if err != nil {
+		return err
+	}
+
+	footer := orcMessage{}.uint(1, uint64(len(orcMagic))).uint(2, o.offset)
+	for _, stripe := range o.stripes {
+		footer = footer.message(3, stripe)
+	}
+	structType := orcMessage{}.uint(1, orcStruct)
+	subtypes := make([]uint64, len(o.columns))
+	for i, column := range o.columns {
+		subtypes[i] = uint64(i + 1)
+		structType = structType.bytes(3, []byte(column.name))
+	}
+	footer = footer.message(4, structType.packed(2, subtypes))
+	for _, column := range o.columns {
+		footer = footer.message(4, orcMessage{}.uint(1, column.kind))
+	}
+	footer = footer.uint(6, o.valuesCount[0])
+	for i := range o.valuesCount {
+		footer = footer.message(7, orcMessage{}.uint(1, o.valuesCount[i]).boolean(10, o.hasNull[i]))
+	}
+	footer = footer.uint(8, 0)
+	compressedFooter, err := orcCompress(footer)
+	if err != nil {
+		return err
+	}
+
+	postScript := orcMessage{}.
+		uint(1, uint64(len(compressedFooter))).
+		uint(2, orcCompressionZlib).
+		uint(3, orcCompressionBlockSize).
+		packed(4, orcFileVersion).
+		uint(5, 0).
+		uint(6, orcWriterVersion).
+		bytes(8000, []byte(orcMagic))
+	tail := append(append(compressedFooter, postScript...), byte(len(postScript)))
+	if _, err := o.fileWriter.Write(tail); err != nil {
+		return err
+	}
+	// close the bufWriter
+	return o.fileWriter.Close()
+}
+
+func (o *OrcWriter) WriteGZ(s string) error {
+	return errors.New("not implemented")
+}
+
+func (o *OrcWriter) Write(b []byte) (int, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (o *OrcWriter) GetLoadFile() *os.File {
+	return o.fileWriter.GetFile()
+}
+
+// orcMessage is an encoded protobuf message of the metadata of an orc file
+type orcMessage []byte
+
+func (m orcMessage) uint(field protowire.Number, value uint64) orcMessage {
+	return protowire.AppendVarint(protowire.AppendTag(m, field, protowire.VarintType), value)
+}
+
+func (m orcMessage) boolean(field protowire.Number, value bool) orcMessage {
+	return m.uint(field, protowire.EncodeBool(value))
+}
+
+func (m orcMessage) bytes(field protowire.Number, value []byte) orcMessage {
+	return protowire.AppendBytes(protowire.AppendTag(m, field, protowire.BytesType), value)
+}
+
+func (m orcMessage) message(field protowire.Number, value orcMessage) orcMessage {
+	return m.bytes(field, value)
+}
+
+func (m orcMessage) packed(field protowire.Number, values []uint64) orcMessage {
+	var packed []byte
+	for _, value := range values {
+		packed = protowire.AppendVarint(packed, value)
+	}
+	return m.bytes(field, packed)
+}
+
+// orcCompress compresses a stream in chunks of orcCompressionBlockSize bytes, each one preceded by a 3 bytes header
+// with its length and whether it is kept uncompressed, when deflating it doesn't make it smaller
+func orcCompress(data []byte) ([]byte, error) {
+	var compressed bytes.Buffer
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > orcCompressionBlockSize {
+			chunk = chunk[:orcCompressionBlockSize]
+		}
+		data = data[len(chunk):]
+
+		var deflated bytes.Buffer
+		w, err := flate.NewWriter(&deflated, flate.DefaultCompression)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(chunk); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		header, body := deflated.Len()<<1, deflated.Bytes()
+		if deflated.Len() >= len(chunk) {
+			header, body = len(chunk)<<1|1, chunk
+		}
+		compressed.Write([]byte{byte(header), byte(header >> 8), byte(header >> 16)})
+		compressed.Write(body)
+	}
+	return compressed.Bytes(), nil
+}
+
+// orcIntegers encodes integers with the version 1 of the run length encoding of integers, in literal runs
+func orcIntegers(values []int64, signed bool) []byte {
+	var encoded []byte
+	for len(values) > 0 {
+		n := len(values)
+		if n > orcLiteralsLength {
+			n = orcLiteralsLength
+		}
+		encoded = append(encoded, byte(-n))
+		for _, value := range values[:n] {
+			if signed {
+				encoded = protowire.AppendVarint(encoded, protowire.EncodeZigZag(value))
+			} else {
+				encoded = protowire.AppendVarint(encoded, uint64(value))
+			}
+		}
+		values = values[n:]
+	}
+	return encoded
+}
+
+// orcBytes encodes bytes with the run length encoding of bytes, in literal runs
+func orcBytes(values []byte) []byte {
+	var encoded []byte
+	for len(values) > 0 {
+		n := len(values)
+		if n > orcLiteralsLength {
+			n = orcLiteralsLength
+		}
+		encoded = append(append(encoded, byte(-n)), values[:n]...)
+		values = values[n:]
+	}
+	return encoded
+}
+
+// orcBooleans encodes booleans as the bits of bytes, from the most significant one, encoded with the run length
+// encoding of bytes
+func orcBooleans(values []bool) []byte {
+	bits := make([]byte, (len(values)+7)/8)
+	for i, value := range values {
+		if value {
+			bits[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return orcBytes(bits)
+}
+
+// orcFormatNanos encodes the nanoseconds of a timestamp without their trailing zeros, their count minus one being
+// in the 3 least significant bits, when there are more than one
+func orcFormatNanos(nanos int) int64 {
+	if nanos == 0 || nanos%100 != 0 {
+		return int64(nanos) << 3
+	}
+	nanos /= 100
+	trailingZeros := 1
+	for nanos%10 == 0 && trailingZeros < 7 {
+		nanos /= 10
+		trailingZeros++
+	}
+	return int64(nanos)<<3 | int64(trailingZeros)
+}
@@ -0,0 +1,260 @@
+package warehouseutils_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/rudderlabs/rudder-server/config"
+	. "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestOrcLoadFiles(t *testing.T) {
+	t.Setenv("RSERVER_WAREHOUSE_ORC_ROWS_PER_STRIPE", "2")
+	config.Load()
+	Init()
+
+	schema := TableSchemaT{
+		"id":          "string",
+		"count":       "int",
+		"price":       "float",
+		"active":      "boolean",
+		"received_at": "datetime",
+		"uuid_ts":     "datetime",
+	}
+	outputFilePath := filepath.Join(t.TempDir(), "tracks.orc")
+	writer, err := CreateOrcWriter(schema, outputFilePath, S3_DATALAKE)
+	require.NoError(t, err)
+
+	longText := strings.Repeat("rudder", 100000)
+	uuidTS := time.Date(2022, 1, 20, 13, 40, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		loader := GetNewEventLoader(S3_DATALAKE, LOAD_FILE_TYPE_ORC, writer)
+		require.True(t, loader.IsLoadTimeColumn("uuid_ts"))
+		// columns sorted by name
+		loader.AddColumn("active", "boolean", i%2 == 0)
+		loader.AddColumn("count", "int", i-2)
+		if i == 3 {
+			loader.AddColumn("id", "string", longText)
+		} else {
+			loader.AddColumn("id", "string", "id")
+		}
+		if i == 1 {
+			loader.AddColumn("price", "float", 10.5)
+		} else {
+			loader.AddEmptyColumn("price")
+		}
+		loader.AddColumn("received_at", "datetime", "2022-01-20T13:39:21.033Z")
+		loader.AddColumn("uuid_ts", "datetime", uuidTS.Format(loader.GetLoadTimeFomat("uuid_ts")))
+		require.NoError(t, loader.Write())
+	}
+	require.NoError(t, writer.Close())
+
+	data, err := os.ReadFile(outputFilePath)
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(data, []byte("ORC")))
+	require.Less(t, len(data), len(longText), "streams are compressed")
+
+	reader := NewEventReader(bytes.NewReader(data), S3_DATALAKE, LOAD_FILE_TYPE_ORC)
+	columnNames := []string{"id", "count", "price", "active", "received_at", "uuid_ts", "missing"}
+	expectedRecords := [][]string{
+		{"id", "-2", "", "true", "2022-01-20T13:39:21.033Z", "2022-01-20T13:40:00.000Z", ""},
+		{"id", "-1", "10.5", "false", "2022-01-20T13:39:21.033Z", "2022-01-20T13:40:00.000Z", ""},
+		{"id", "0", "", "true", "2022-01-20T13:39:21.033Z", "2022-01-20T13:40:00.000Z", ""},
+		{longText, "1", "", "false", "2022-01-20T13:39:21.033Z", "2022-01-20T13:40:00.000Z", ""},
+		{"id", "2", "", "true", "2022-01-20T13:39:21.033Z", "2022-01-20T13:40:00.000Z", ""},
+	}
+	for _, expectedRecord := range expectedRecords {
+		record, err := reader.Read(columnNames)
+		require.NoError(t, err)
+		require.Equal(t, expectedRecord, record)
+	}
+	_, err = reader.Read(columnNames)
+	require.Equal(t, io.EOF, err)
+}
+
+func TestOrcWriterRejectsInvalidValues(t *testing.T) {
+	writer, err := CreateOrcWriter(TableSchemaT{"id": "string", "count": "int"}, filepath.Join(t.TempDir(), "tracks.orc"), S3_DATALAKE)
+	require.NoError(t, err)
+	defer writer.Close()
+	require.EqualError(t, writer.WriteRow([]interface{}{"10", "id"}), "invalid value 10 of type string for column count")
+	require.EqualError(t, writer.WriteRow([]interface{}{int64(10)}), "row has 1 values for 2 columns")
+}
+
+func TestCreateOrcWriterWithUnsupportedWarehouse(t *testing.T) {
+	_, err := CreateOrcWriter(TableSchemaT{"id": "string"}, filepath.Join(t.TempDir(), "tracks.orc"), BQ)
+	require.EqualError(t, err, "unsupported warehouse for orc load files")
+}
+
+// TestOrcFileLayout decodes an orc file with the layout of the ORCv1 specification, https://orc.apache.org/specification/ORCv1/,
+// independently of the orc reader, and checks its streams against the encodings of the values derived from the
+// specification
+func TestOrcFileLayout(t *testing.T) {
+	schema := TableSchemaT{"count": "int", "id": "string", "received_at": "datetime"}
+	outputFilePath := filepath.Join(t.TempDir(), "tracks.orc")
+	writer, err := CreateOrcWriter(schema, outputFilePath, S3_DATALAKE)
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteRow([]interface{}{int64(-2), "ab", time.Date(2015, 1, 1, 0, 0, 1, 1000, time.UTC)}))
+	require.NoError(t, writer.WriteRow([]interface{}{nil, "c", time.Date(2014, 12, 31, 23, 59, 59, 0, time.UTC)}))
+	require.NoError(t, writer.Close())
+	data, err := os.ReadFile(outputFilePath)
+	require.NoError(t, err)
+	require.Equal(t, "ORC", string(data[:3]))
+
+	// postscript: footerLength = 1, compression = 2, version = 4, magic = 8000
+	psLength := int(data[len(data)-1])
+	postScript := decodeOrcMessage(t, data[len(data)-1-psLength:len(data)-1])
+	require.Equal(t, uint64(1), postScript.uint(2), "zlib compression")
+	require.Equal(t, []uint64{0, 12}, postScript.packed(4))
+	require.Equal(t, "ORC", string(postScript.bytes(8000)[0]))
+	footerEnd := len(data) - 1 - psLength
+	footerStart := footerEnd - int(postScript.uint(1))
+
+	// footer: headerLength = 1, contentLength = 2, stripes = 3, types = 4, numberOfRows = 6, statistics = 7
+	footer := decodeOrcMessage(t, decompressOrcStream(t, data[footerStart:footerEnd]))
+	require.Equal(t, uint64(3), footer.uint(1))
+	require.Equal(t, uint64(footerStart), footer.uint(2))
+	require.Equal(t, uint64(2), footer.uint(6))
+	var types []orcTestMessage
+	for _, typ := range footer.bytes(4) {
+		types = append(types, decodeOrcMessage(t, typ))
+	}
+	require.Len(t, types, 4)
+	require.Equal(t, uint64(12), types[0].uint(1), "struct")
+	require.Equal(t, []uint64{1, 2, 3}, types[0].packed(2))
+	require.Equal(t, [][]byte{[]byte("count"), []byte("id"), []byte("received_at")}, types[0].bytes(3))
+	require.Equal(t, uint64(4), types[1].uint(1), "long")
+	require.Equal(t, uint64(7), types[2].uint(1), "string")
+	require.Equal(t, uint64(9), types[3].uint(1), "timestamp")
+	countStatistics := decodeOrcMessage(t, footer.bytes(7)[1])
+	require.Equal(t, uint64(1), countStatistics.uint(1))
+	require.Equal(t, uint64(1), countStatistics.uint(10), "count has nulls")
+
+	// stripe information: offset = 1, indexLength = 2, dataLength = 3, footerLength = 4, numberOfRows = 5
+	require.Len(t, footer.bytes(3), 1)
+	stripe := decodeOrcMessage(t, footer.bytes(3)[0])
+	require.Equal(t, uint64(3), stripe.uint(1))
+	require.Equal(t, uint64(0), stripe.uint(2))
+	require.Equal(t, uint64(2), stripe.uint(5))
+	dataStart := int(stripe.uint(1))
+	dataEnd := dataStart + int(stripe.uint(3))
+	require.Equal(t, footerStart, dataEnd+int(stripe.uint(4)))
+
+	// stripe footer: streams = 1 with kind = 1, column = 2 and length = 3, columns = 2, writerTimezone = 3
+	stripeFooter := decodeOrcMessage(t, decompressOrcStream(t, data[dataEnd:dataEnd+int(stripe.uint(4))]))
+	require.Equal(t, "UTC", string(stripeFooter.bytes(3)[0]))
+	require.Len(t, stripeFooter.bytes(2), 4)
+	for _, encoding := range stripeFooter.bytes(2) {
+		require.Equal(t, uint64(0), decodeOrcMessage(t, encoding).uint(1), "direct encoding")
+	}
+	type stream struct {
+		kind, column uint64
+		data         []byte
+	}
+	var streams []stream
+	offset := dataStart
+	for _, s := range stripeFooter.bytes(1) {
+		message := decodeOrcMessage(t, s)
+		length := int(message.uint(3))
+		streams = append(streams, stream{kind: message.uint(1), column: message.uint(2), data: decompressOrcStream(t, data[offset:offset+length])})
+		offset += length
+	}
+	require.Equal(t, dataEnd, offset)
+	require.Equal(t, []stream{
+		// PRESENT of count: bits 10000000 as a byte run length encoded literal
+		{kind: 0, column: 1, data: []byte{0xff, 0x80}},
+		// DATA of count: -2 zigzag encoded as a signed integer run length encoded literal
+		{kind: 1, column: 1, data: []byte{0xff, 0x03}},
+		// DATA and LENGTH of id
+		{kind: 1, column: 2, data: []byte("abc")},
+		{kind: 2, column: 2, data: []byte{0xfe, 0x02, 0x01}},
+		// DATA of received_at: seconds since 2015-01-01, 1 and -1 zigzag encoded
+		{kind: 1, column: 3, data: []byte{0xfe, 0x02, 0x01}},
+		// SECONDARY of received_at: 1000 nanoseconds are 0x0a, as in the example of the specification
+		{kind: 5, column: 3, data: []byte{0xfe, 0x0a, 0x00}},
+	}, streams)
+}
+
+// orcTestMessage has the values of the fields of a protobuf message of the metadata of an orc file, by field number
+type orcTestMessage map[protowire.Number][]interface{}
+
+func decodeOrcMessage(t *testing.T, b []byte) orcTestMessage {
+	t.Helper()
+	message := orcTestMessage{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		switch typ {
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			require.GreaterOrEqual(t, n, 0)
+			message[num] = append(message[num], v)
+			b = b[n:]
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			require.GreaterOrEqual(t, n, 0)
+			message[num] = append(message[num], v)
+			b = b[n:]
+		default:
+			t.Fatalf("unexpected wire type %d of field %d", typ, num)
+		}
+	}
+	return message
+}
+
+func (m orcTestMessage) uint(num protowire.Number) uint64 {
+	if len(m[num]) == 0 {
+		return 0
+	}
+	return m[num][0].(uint64)
+}
+
+func (m orcTestMessage) bytes(num protowire.Number) [][]byte {
+	var values [][]byte
+	for _, v := range m[num] {
+		values = append(values, v.([]byte))
+	}
+	return values
+}
+
+func (m orcTestMessage) packed(num protowire.Number) []uint64 {
+	var values []uint64
+	for _, b := range m.bytes(num) {
+		for len(b) > 0 {
+			v, n := protowire.ConsumeVarint(b)
+			values = append(values, v)
+			b = b[n:]
+		}
+	}
+	return values
+}
+
+// decompressOrcStream decompresses the chunks of a zlib compressed stream, each one preceded by a 3 bytes little endian
+// header with its length shifted by one, and whether it is kept uncompressed in the least significant bit
+func decompressOrcStream(t *testing.T, b []byte) []byte {
+	t.Helper()
+	var decompressed []byte
+	for len(b) > 0 {
+		require.GreaterOrEqual(t, len(b), 3)
+		header := int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+		chunk := b[3 : 3+header>>1]
+		if header&1 == 1 {
+			decompressed = append(decompressed, chunk...)
+		} else {
+			inflated, err := io.ReadAll(flate.NewReader(bytes.NewReader(chunk)))
+			require.NoError(t, err)
+			decompressed = append(decompressed, inflated...)
+		}
+		b = b[3+header>>1:]
+	}
+	return decompressed
+}
@@ -51,6 +51,13 @@ var rudderDataTypeToParquetDataType = map[string]map[string]string{
 		"string":   PARQUET_STRING,
 		"datetime": PARQUET_TIMESTAMP_MICROS,
 	},
+	DUCKDB: {
+		"int":      PARQUET_INT_64,
+		"boolean":  PARQUET_BOOLEAN,
+		"float":    PARQUET_DOUBLE,
+		"string":   PARQUET_STRING,
+		"datetime": PARQUET_TIMESTAMP_MICROS,
+	},
 }
 
 type ParquetWriter struct {
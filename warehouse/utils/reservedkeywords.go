@@ -2239,4 +2239,81 @@ var ReservedKeywords = map[string]map[string]bool{
 		"ZONE":                             true,
 	},
 	"CLICKHOUSE": {},
+	"DUCKDB": {
+		"ALL":          true,
+		"ANALYSE":      true,
+		"ANALYZE":      true,
+		"AND":          true,
+		"ANY":          true,
+		"ARRAY":        true,
+		"AS":           true,
+		"ASC":          true,
+		"ASYMMETRIC":   true,
+		"BOTH":         true,
+		"CASE":         true,
+		"CAST":         true,
+		"CHECK":        true,
+		"COLLATE":      true,
+		"COLUMN":       true,
+		"CONSTRAINT":   true,
+		"CREATE":       true,
+		"DEFAULT":      true,
+		"DEFERRABLE":   true,
+		"DESC":         true,
+		"DESCRIBE":     true,
+		"DISTINCT":     true,
+		"DO":           true,
+		"ELSE":         true,
+		"END":          true,
+		"EXCEPT":       true,
+		"FALSE":        true,
+		"FETCH":        true,
+		"FOR":          true,
+		"FOREIGN":      true,
+		"FROM":         true,
+		"GRANT":        true,
+		"GROUP":        true,
+		"HAVING":       true,
+		"IN":           true,
+		"INITIALLY":    true,
+		"INTERSECT":    true,
+		"INTO":         true,
+		"LATERAL":      true,
+		"LEADING":      true,
+		"LIMIT":        true,
+		"NOT":          true,
+		"NULL":         true,
+		"OFFSET":       true,
+		"ON":           true,
+		"ONLY":         true,
+		"OR":           true,
+		"ORDER":        true,
+		"PIVOT":        true,
+		"PIVOT_LONGER": true,
+		"PIVOT_WIDER":  true,
+		"PLACING":      true,
+		"PRIMARY":      true,
+		"QUALIFY":      true,
+		"REFERENCES":   true,
+		"RETURNING":    true,
+		"SELECT":       true,
+		"SHOW":         true,
+		"SOME":         true,
+		"SUMMARIZE":    true,
+		"SYMMETRIC":    true,
+		"TABLE":        true,
+		"THEN":         true,
+		"TO":           true,
+		"TRAILING":     true,
+		"TRUE":         true,
+		"UNION":        true,
+		"UNIQUE":       true,
+		"UNPIVOT":      true,
+		"USING":        true,
+		"VARIADIC":     true,
+		"WHEN":         true,
+		"WHERE":        true,
+		"WINDOW":       true,
+		"WITH":         true,
+	},
 }
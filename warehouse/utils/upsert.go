@@ -0,0 +1,112 @@
+package warehouseutils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+// UpsertTablesConfig is the key in the config of a destination of the tables loaded in upsert mode
+const UpsertTablesConfig = "upsertTables"
+
+// UpsertEnabledWarehouses are the warehouses whose tables can be loaded in upsert mode
+var UpsertEnabledWarehouses = []string{POSTGRES, RS, MSSQL, AZURE_SYNAPSE, CLICKHOUSE, BQ}
+
+/*
+UpsertConfigT is the upsert mode of a table, set in the config of the destination as
+
+	"upsertTables": [{"tableName": "orders", "primaryKeys": ["order_id"], "orderByColumn": "updated_at"}]
+
+The rows loaded in the table replace its rows with the same primary key, unless they have a later value of the order by
+column, which is received_at by default. Among the rows of an upload with the same primary key, the latest wins too.
+In clickhouse, the rows are replaced by the background merges of the table, so it should be read with FINAL.
+*/
+type UpsertConfigT struct {
+	PrimaryKeys   []string
+	OrderByColumn string
+}
+
+// GetUpsertConfig returns the upsert mode of a table, if it is loaded in upsert mode. The identifies and users tables,
+// which are loaded together, and the tables of rudder keep their own dedup keys.
+func GetUpsertConfig(warehouse WarehouseT, tableName string) (UpsertConfigT, bool) {
+	if !misc.ContainsString(UpsertEnabledWarehouses, warehouse.Type) {
+		return UpsertConfigT{}, false
+	}
+	for _, table := range []string{IdentifiesTable, UsersTable, DiscardsTable, IdentityMergeRulesTable, IdentityMappingsTable} {
+		if strings.EqualFold(tableName, table) {
+			return UpsertConfigT{}, false
+		}
+	}
+
+	tables, _ := warehouse.Destination.Config[UpsertTablesConfig].([]interface{})
+	for _, t := range tables {
+		table, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _ := table["tableName"].(string); !strings.EqualFold(strings.TrimSpace(name), tableName) {
+			continue
+		}
+
+		var upsertConfig UpsertConfigT
+		switch primaryKeys := table["primaryKeys"].(type) {
+		case []interface{}:
+			for _, primaryKey := range primaryKeys {
+				if s, ok := primaryKey.(string); ok && strings.TrimSpace(s) != "" {
+					upsertConfig.PrimaryKeys = append(upsertConfig.PrimaryKeys, ToProviderCase(warehouse.Type, strings.TrimSpace(s)))
+				}
+			}
+		case string:
+			for _, s := range strings.Split(primaryKeys, ",") {
+				if strings.TrimSpace(s) != "" {
+					upsertConfig.PrimaryKeys = append(upsertConfig.PrimaryKeys, ToProviderCase(warehouse.Type, strings.TrimSpace(s)))
+				}
+			}
+		}
+		if len(upsertConfig.PrimaryKeys) == 0 {
+			return UpsertConfigT{}, false
+		}
+
+		upsertConfig.OrderByColumn = ToProviderCase(warehouse.Type, "received_at")
+		if orderByColumn, _ := table["orderByColumn"].(string); strings.TrimSpace(orderByColumn) != "" {
+			upsertConfig.OrderByColumn = ToProviderCase(warehouse.Type, strings.TrimSpace(orderByColumn))
+		}
+		return upsertConfig, true
+	}
+	return UpsertConfigT{}, false
+}
+
+// Validate returns an error if the primary keys or the order by column are not columns of the table
+func (upsertConfig UpsertConfigT) Validate(tableName string, tableSchema TableSchemaT) error {
+	for _, columnName := range append(append([]string{}, upsertConfig.PrimaryKeys...), upsertConfig.OrderByColumn) {
+		if _, ok := tableSchema[columnName]; !ok {
+			return fmt.Errorf("column %s of the upsert mode of table %s does not exist in the table", columnName, tableName)
+		}
+	}
+	return nil
+}
+
+// PartitionBy returns the double quoted primary keys, separated by commas
+func (upsertConfig UpsertConfigT) PartitionBy() string {
+	return DoubleQuoteAndJoinByComma(upsertConfig.PrimaryKeys)
+}
+
+// LatestFirst returns the order of the rows by the order by column, latest first and rows without a value last
+func (upsertConfig UpsertConfigT) LatestFirst() string {
+	return fmt.Sprintf(`CASE WHEN %[1]q IS NULL THEN 1 ELSE 0 END, %[1]q DESC`, upsertConfig.OrderByColumn)
+}
+
+// JoinCondition returns the condition of the rows of source and target with the same primary key
+func (upsertConfig UpsertConfigT) JoinCondition(source, target string) string {
+	conditions := make([]string, len(upsertConfig.PrimaryKeys))
+	for i, primaryKey := range upsertConfig.PrimaryKeys {
+		conditions[i] = fmt.Sprintf(`%[1]s.%[3]q = %[2]s.%[3]q`, source, target, primaryKey)
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// NotNewerCondition returns the condition of the rows of target which are not newer than the rows of source
+func (upsertConfig UpsertConfigT) NotNewerCondition(source, target string) string {
+	return fmt.Sprintf(`(%[2]s.%[3]q IS NULL OR %[1]s.%[3]q >= %[2]s.%[3]q)`, source, target, upsertConfig.OrderByColumn)
+}
@@ -0,0 +1,72 @@
+package warehouseutils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	. "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestGetUpsertConfig(t *testing.T) {
+	upsertTables := []interface{}{
+		map[string]interface{}{"tableName": "orders", "primaryKeys": []interface{}{"order_id", " store_id "}, "orderByColumn": "updated_at"},
+		map[string]interface{}{"tableName": "carts", "primaryKeys": "cart_id"},
+		map[string]interface{}{"tableName": "products"},
+		map[string]interface{}{"tableName": "identifies", "primaryKeys": "user_id"},
+	}
+	warehouse := func(destType string) WarehouseT {
+		return WarehouseT{
+			Type:        destType,
+			Destination: backendconfig.DestinationT{Config: map[string]interface{}{UpsertTablesConfig: upsertTables}},
+		}
+	}
+
+	testCases := []struct {
+		destType       string
+		tableName      string
+		expectedConfig UpsertConfigT
+		expectedOK     bool
+	}{
+		{
+			destType:       POSTGRES,
+			tableName:      "orders",
+			expectedConfig: UpsertConfigT{PrimaryKeys: []string{"order_id", "store_id"}, OrderByColumn: "updated_at"},
+			expectedOK:     true,
+		},
+		{
+			destType:       BQ,
+			tableName:      "carts",
+			expectedConfig: UpsertConfigT{PrimaryKeys: []string{"cart_id"}, OrderByColumn: "received_at"},
+			expectedOK:     true,
+		},
+		{destType: POSTGRES, tableName: "products"},
+		{destType: POSTGRES, tableName: "tracks"},
+		{destType: POSTGRES, tableName: "identifies"},
+		{destType: SNOWFLAKE, tableName: "ORDERS"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.destType+"/"+tc.tableName, func(t *testing.T) {
+			upsertConfig, ok := GetUpsertConfig(warehouse(tc.destType), tc.tableName)
+			require.Equal(t, tc.expectedOK, ok)
+			require.Equal(t, tc.expectedConfig, upsertConfig)
+		})
+	}
+}
+
+func TestUpsertConfigQueries(t *testing.T) {
+	upsertConfig := UpsertConfigT{PrimaryKeys: []string{"order_id", "store_id"}, OrderByColumn: "updated_at"}
+
+	require.NoError(t, upsertConfig.Validate("orders", TableSchemaT{"order_id": "string", "store_id": "int", "updated_at": "datetime"}))
+	require.EqualError(t,
+		upsertConfig.Validate("orders", TableSchemaT{"order_id": "string", "store_id": "int"}),
+		"column updated_at of the upsert mode of table orders does not exist in the table",
+	)
+
+	require.Equal(t, `"order_id","store_id"`, upsertConfig.PartitionBy())
+	require.Equal(t, `CASE WHEN "updated_at" IS NULL THEN 1 ELSE 0 END, "updated_at" DESC`, upsertConfig.LatestFirst())
+	require.Equal(t, `_source."order_id" = "ns"."orders"."order_id" AND _source."store_id" = "ns"."orders"."store_id"`, upsertConfig.JoinCondition("_source", `"ns"."orders"`))
+	require.Equal(t, `("ns"."orders"."updated_at" IS NULL OR _source."updated_at" >= "ns"."orders"."updated_at")`, upsertConfig.NotNewerCondition("_source", `"ns"."orders"`))
+}
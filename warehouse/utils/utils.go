@@ -45,6 +45,7 @@ const (
 	S3_DATALAKE    = "S3_DATALAKE"
 	GCS_DATALAKE   = "GCS_DATALAKE"
 	AZURE_DATALAKE = "AZURE_DATALAKE"
+	DUCKDB         = "DUCKDB"
 )
 
 const (
@@ -115,6 +116,7 @@ var WHDestNameMap = map[string]string{
 	GCS_DATALAKE:   "gcs_datalake",
 	AZURE_DATALAKE: "azure_datalake",
 	AZURE_SYNAPSE:  "azure_synapse",
+	DUCKDB:         "duckdb",
 }
 
 var ObjectStorageMap = map[string]string{
@@ -144,15 +146,20 @@ const (
 	LOAD_FILE_TYPE_CSV     = "csv"
 	LOAD_FILE_TYPE_JSON    = "json"
 	LOAD_FILE_TYPE_PARQUET = "parquet"
+	LOAD_FILE_TYPE_AVRO    = "avro"
+	LOAD_FILE_TYPE_ORC     = "orc"
 	TestConnectionTimeout  = 15 * time.Second
 )
 
 var (
 	pkgLogger              logger.LoggerI
 	useParquetLoadFilesRS  bool
+	useParquetLoadFilesDD  bool
 	TimeWindowDestinations []string
 	WarehouseDestinations  []string
 	parquetParallelWriters int64
+	avroRecordsPerBlock    int
+	orcRowsPerStripe       int
 )
 
 var (
@@ -166,14 +173,17 @@ func Init() {
 }
 
 func loadConfig() {
-	IdentityEnabledWarehouses = []string{SNOWFLAKE, BQ}
+	IdentityEnabledWarehouses = []string{SNOWFLAKE, BQ, DUCKDB}
 	TimeWindowDestinations = []string{S3_DATALAKE, GCS_DATALAKE, AZURE_DATALAKE}
-	WarehouseDestinations = []string{RS, BQ, SNOWFLAKE, POSTGRES, CLICKHOUSE, MSSQL, AZURE_SYNAPSE, S3_DATALAKE, GCS_DATALAKE, AZURE_DATALAKE, DELTALAKE}
+	WarehouseDestinations = []string{RS, BQ, SNOWFLAKE, POSTGRES, CLICKHOUSE, MSSQL, AZURE_SYNAPSE, S3_DATALAKE, GCS_DATALAKE, AZURE_DATALAKE, DELTALAKE, DUCKDB}
 	config.RegisterBoolConfigVariable(false, &enableIDResolution, false, "Warehouse.enableIDResolution")
 	config.RegisterInt64ConfigVariable(3600, &AWSCredsExpiryInS, true, 1, "Warehouse.awsCredsExpiryInS")
 	config.RegisterIntConfigVariable(10240, &maxStagingFileReadBufferCapacityInK, false, 1, "Warehouse.maxStagingFileReadBufferCapacityInK")
 	config.RegisterBoolConfigVariable(false, &useParquetLoadFilesRS, true, "Warehouse.useParquetLoadFilesRS")
+	config.RegisterBoolConfigVariable(false, &useParquetLoadFilesDD, true, "Warehouse.duckdb.useParquetLoadFiles")
 	config.RegisterInt64ConfigVariable(8, &parquetParallelWriters, true, 1, "Warehouse.parquetParallelWriters")
+	config.RegisterIntConfigVariable(1000, &avroRecordsPerBlock, true, 1, "Warehouse.avroRecordsPerBlock")
+	config.RegisterIntConfigVariable(100000, &orcRowsPerStripe, true, 1, "Warehouse.orcRowsPerStripe")
 }
 
 type WarehouseT struct {
@@ -265,6 +275,8 @@ type TableSchemaDiffT struct {
 	ColumnMap                      map[string]string
 	UpdatedSchema                  map[string]string
 	StringColumnsToBeAlteredToText []string
+	// ColumnTypesToBeWidened are the columns to be altered to a wider type, with that type
+	ColumnTypesToBeWidened map[string]string
 }
 
 type QueryResult struct {
@@ -863,11 +875,43 @@ func GetLoadFileType(wh string) string {
 		return LOAD_FILE_TYPE_PARQUET
 	case DELTALAKE:
 		return LOAD_FILE_TYPE_CSV
+	case DUCKDB:
+		if useParquetLoadFilesDD {
+			return LOAD_FILE_TYPE_PARQUET
+		}
+		return LOAD_FILE_TYPE_CSV
 	default:
 		return LOAD_FILE_TYPE_CSV
 	}
 }
 
+// GetConfiguredLoadFileType returns the load file type set with loadFileType in the config of the destination of the
+// warehouse, if the warehouse supports it, or else the default load file type of the warehouse
+func GetConfiguredLoadFileType(warehouse WarehouseT) string {
+	switch GetConfigValue("loadFileType", warehouse) {
+	case LOAD_FILE_TYPE_AVRO:
+		if _, ok := rudderDataTypeToAvroDataType[warehouse.Type]; ok {
+			return LOAD_FILE_TYPE_AVRO
+		}
+	case LOAD_FILE_TYPE_ORC:
+		if _, ok := rudderDataTypeToOrcDataType[warehouse.Type]; ok {
+			return LOAD_FILE_TYPE_ORC
+		}
+	}
+	return GetLoadFileType(warehouse.Type)
+}
+
+// GetLoadFileFormatForType returns the extension of the load files of the load file type for the warehouse
+func GetLoadFileFormatForType(whType, loadFileType string) string {
+	switch loadFileType {
+	case LOAD_FILE_TYPE_AVRO:
+		return "avro"
+	case LOAD_FILE_TYPE_ORC:
+		return "orc"
+	}
+	return GetLoadFileFormat(whType)
+}
+
 func GetLoadFileFormat(whType string) string {
 	switch whType {
 	case BQ:
@@ -881,6 +925,11 @@ func GetLoadFileFormat(whType string) string {
 		return "csv.gz"
 	case DELTALAKE:
 		return "csv.gz"
+	case DUCKDB:
+		if useParquetLoadFilesDD {
+			return "parquet"
+		}
+		return "csv.gz"
 	default:
 		return "csv.gz"
 	}